@@ -20,13 +20,15 @@ var (
 	ErrCache              = errors.New("cache error")
 	ErrValidation         = errors.New("validation error")
 	ErrRateLimit          = errors.New("rate limit exceeded")
+	ErrQuotaExceeded      = errors.New("quota exceeded")
 )
 
 // AppError 应用错误
 type AppError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Err     error  `json:"-"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+	Err     error       `json:"-"`
 }
 
 func (e *AppError) Error() string {
@@ -66,6 +68,12 @@ func WrapWithCode(err error, appErr *AppError) *AppError {
 	}
 }
 
+// WithDetails 附加结构化错误详情
+func (e *AppError) WithDetails(details interface{}) *AppError {
+	e.Details = details
+	return e
+}
+
 // Is 检查错误是否匹配
 func Is(err, target error) bool {
 	return errors.Is(err, target)
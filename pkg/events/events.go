@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/redis"
+)
+
+// 事件来源服务
+const (
+	SourceTraining   = "training"
+	SourceInference  = "inference"
+	SourceData       = "data"
+	SourceExperiment = "experiment"
+)
+
+const (
+	// DefaultStream 默认事件流
+	DefaultStream = "aitip:events"
+	// DefaultMaxLen 默认事件流最大长度
+	DefaultMaxLen = 100000
+)
+
+// Event 资源生命周期事件
+type Event struct {
+	ID         string                 `json:"id"`   // 事件流消息 ID，发布时由 Redis 分配
+	Type       string                 `json:"type"` // <source>.<resource>.<action>，如 training.job.completed
+	Source     string                 `json:"source"`
+	ProjectID  uuid.UUID              `json:"project_id"`
+	ResourceID uuid.UUID              `json:"resource_id"`
+	Status     string                 `json:"status,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Timestamp  time.Time              `json:"timestamp"`
+}
+
+// Publisher 事件发布接口
+type Publisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+// Handler 事件处理函数，返回错误时消息不会被确认
+type Handler func(ctx context.Context, event *Event) error
+
+// Bus 基于 Redis Streams 的事件总线
+//
+// 各服务将事件追加到同一个流中，消费者通过消费组读取：同一消费组内的每条事件只投递给一个消费者，
+// 处理失败（未确认）的事件在该消费者下次启动时重新投递。
+type Bus struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewBus 创建事件总线，stream 为空时使用 DefaultStream，maxLen <= 0 时使用 DefaultMaxLen
+func NewBus(client *redis.Client, stream string, maxLen int64) *Bus {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLen
+	}
+
+	return &Bus{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// Publish 发布事件，成功后 event.ID 为分配的消息 ID
+func (b *Bus) Publish(ctx context.Context, event *Event) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	id, err := b.client.XAddMaxLen(ctx, b.stream, b.maxLen, map[string]interface{}{
+		"type":  event.Type,
+		"event": payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	event.ID = id
+	return nil
+}
+
+// Consume 以消费组 group 中 consumer 的身份消费事件，直到 ctx 结束
+//
+// 消费组不存在时从当前位置创建，因此只会收到创建之后发布的事件。启动时先重新处理该消费者
+// 此前已读取但未确认的事件。
+func (b *Bus) Consume(ctx context.Context, group, consumer string, handler Handler) error {
+	if err := b.client.XGroupCreateMkStream(ctx, b.stream, group, "$"); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group: %w", err)
+	}
+
+	// "0" 之后的 ID 读取未确认的事件，读完后切换为 ">" 读取新事件
+	cursor := "0"
+	for ctx.Err() == nil {
+		streams, err := b.client.XReadGroup(ctx, group, consumer, []string{b.stream, cursor}, 100, 5*time.Second)
+		if err != nil {
+			if errors.Is(err, goredis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			logger.Warn("Failed to read events", zap.String("group", group), zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var count int
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				count++
+				b.handle(ctx, group, msg, handler)
+				if cursor != ">" {
+					cursor = msg.ID
+				}
+			}
+		}
+		if cursor != ">" && count == 0 {
+			cursor = ">"
+		}
+	}
+
+	return nil
+}
+
+// handle 处理单条消息，无法解析的消息直接确认丢弃
+func (b *Bus) handle(ctx context.Context, group string, msg goredis.XMessage, handler Handler) {
+	event, err := parseEvent(msg)
+	if err != nil {
+		logger.Warn("Dropping malformed event", zap.String("id", msg.ID), zap.Error(err))
+	} else if err := handler(ctx, event); err != nil {
+		logger.Warn("Failed to handle event",
+			zap.String("id", msg.ID),
+			zap.String("type", event.Type),
+			zap.String("group", group),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if err := b.client.XAck(ctx, b.stream, group, msg.ID); err != nil {
+		logger.Warn("Failed to ack event", zap.String("id", msg.ID), zap.Error(err))
+	}
+}
+
+// parseEvent 解析流消息中的事件
+func parseEvent(msg goredis.XMessage) (*Event, error) {
+	payload, ok := msg.Values["event"].(string)
+	if !ok {
+		return nil, errors.New("missing event payload")
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	event.ID = msg.ID
+	return &event, nil
+}
+
+// MatchType 检查事件类型是否匹配模式，模式支持 "*" 以及 "training.job.*" 形式的前缀通配
+func MatchType(pattern, eventType string) bool {
+	if pattern == "*" || pattern == eventType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(eventType, prefix)
+	}
+	return false
+}
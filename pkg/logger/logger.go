@@ -10,10 +10,10 @@ import (
 )
 
 var (
-	// Log is the global logger instance
-	Log *zap.Logger
+	// Log is the global logger instance, a no-op logger until Init is called
+	Log = zap.NewNop()
 	// SugaredLog is the sugared logger for convenient logging
-	SugaredLog *zap.SugaredLogger
+	SugaredLog = Log.Sugar()
 )
 
 // Config logger configuration
@@ -153,3 +153,70 @@ func (l *LogEntry) BeforeCreate(tx *gorm.DB) error {
 func (LogEntry) TableName() string {
 	return "log_entries"
 }
+
+// Sweep 超参数搜索模型
+type Sweep struct {
+	ID           uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ExperimentID uuid.UUID      `json:"experiment_id" gorm:"type:uuid;not null;index:idx_sweep_exp"`
+	UserID       uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	Name         string         `json:"name" gorm:"not null;size:255"`
+	Algorithm    string         `json:"algorithm" gorm:"not null;size:20"`        // grid, random, bayesian
+	Config       JSON           `json:"config" gorm:"type:jsonb;serializer:json"` // 搜索空间、优化目标、早停策略和任务模板
+	MaxTrials    int            `json:"max_trials" gorm:"not null"`
+	Parallelism  int            `json:"parallelism" gorm:"not null;default:1"`
+	Status       string         `json:"status" gorm:"default:'running';size:50;index:idx_sweep_status"`
+	Message      string         `json:"message" gorm:"size:1000"`
+	BestTrialID  *uuid.UUID     `json:"best_trial_id" gorm:"type:uuid"`
+	BestValue    *float64       `json:"best_value"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	CompletedAt  *time.Time     `json:"completed_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Experiment *Experiment  `json:"experiment,omitempty" gorm:"foreignKey:ExperimentID"`
+	Trials     []SweepTrial `json:"trials,omitempty" gorm:"foreignKey:SweepID"`
+}
+
+// BeforeCreate 创建前钩子
+func (s *Sweep) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName 表名
+func (Sweep) TableName() string {
+	return "sweeps"
+}
+
+// SweepTrial 超参数搜索试验模型，每个试验对应一个运行记录和一个训练任务
+type SweepTrial struct {
+	ID              uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SweepID         uuid.UUID  `json:"sweep_id" gorm:"type:uuid;not null;uniqueIndex:idx_trial_sweep_number"`
+	Number          int        `json:"number" gorm:"not null;uniqueIndex:idx_trial_sweep_number"`
+	RunID           uuid.UUID  `json:"run_id" gorm:"type:uuid;not null;index"`
+	TrainingJobID   *uuid.UUID `json:"training_job_id" gorm:"type:uuid"`
+	Hyperparameters JSON       `json:"hyperparameters" gorm:"type:jsonb;serializer:json"`
+	Status          string     `json:"status" gorm:"default:'pending';size:50;index:idx_trial_status"`
+	Message         string     `json:"message" gorm:"size:1000"`
+	ObjectiveValue  *float64   `json:"objective_value"`
+	LastStep        int64      `json:"last_step" gorm:"default:0"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+}
+
+// BeforeCreate 创建前钩子
+func (t *SweepTrial) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName 表名
+func (SweepTrial) TableName() string {
+	return "sweep_trials"
+}
@@ -16,6 +16,8 @@ func AutoMigrate(db *gorm.DB) error {
 		&Metric{},
 		&Artifact{},
 		&LogEntry{},
+		&Sweep{},
+		&SweepTrial{},
 
 		// 训练相关
 		&TrainingJob{},
@@ -42,6 +44,8 @@ func GetAllModels() []interface{} {
 		&Metric{},
 		&Artifact{},
 		&LogEntry{},
+		&Sweep{},
+		&SweepTrial{},
 		&TrainingJob{},
 		&Checkpoint{},
 		&Model{},
@@ -74,7 +74,9 @@ type Checkpoint struct {
 	TrainingJobID  uuid.UUID      `json:"training_job_id" gorm:"type:uuid;not null;index"`
 	Step           int64          `json:"step" gorm:"not null"`
 	Epoch          *int64         `json:"epoch"`
-	StoragePath    string         `json:"storage_path" gorm:"not null;size:500"`
+	Name           string         `json:"name" gorm:"size:255"`
+	StoragePath    string         `json:"storage_path" gorm:"not null;size:500"` // 对象存储路径，目录以 / 结尾
+	SourcePath     string         `json:"source_path" gorm:"size:500;index"`      // 发现检查点时的宿主机路径
 	Metrics        JSON           `json:"metrics" gorm:"type:jsonb;default:'{}'"`
 	Size           int64          `json:"size" gorm:"default:0"`
 	IsBest         bool           `json:"is_best" gorm:"default:false"`
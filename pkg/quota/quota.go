@@ -0,0 +1,384 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+)
+
+// Scope 配额范围
+type Scope string
+
+const (
+	ScopeProject      Scope = "project"
+	ScopeOrganization Scope = "organization"
+)
+
+// Resource 受配额限制的资源
+type Resource string
+
+const (
+	ResourceConcurrentGPUs   Resource = "concurrent_gpus"
+	ResourceConcurrentJobs   Resource = "concurrent_jobs"
+	ResourceGPUHoursPerMonth Resource = "gpu_hours_per_month"
+	ResourceMemoryGBPerJob   Resource = "memory_gb_per_job"
+	ResourceStorageBytes     Resource = "storage_bytes"
+)
+
+// Limits 配额限制，0 表示不限制
+type Limits struct {
+	MaxConcurrentGPUs   int     `json:"max_concurrent_gpus"`
+	MaxConcurrentJobs   int     `json:"max_concurrent_jobs"`
+	MaxGPUHoursPerMonth float64 `json:"max_gpu_hours_per_month"`
+	MaxMemoryGBPerJob   int     `json:"max_memory_gb_per_job"`
+	MaxStorageBytes     int64   `json:"max_storage_bytes"`
+}
+
+// Usage 资源使用量
+type Usage struct {
+	ConcurrentGPUs    int     `json:"concurrent_gpus"`
+	ConcurrentJobs    int     `json:"concurrent_jobs"`
+	GPUHoursThisMonth float64 `json:"gpu_hours_this_month"`
+	GPUHoursReserved  float64 `json:"gpu_hours_reserved"` // 未结束的任务在超时前最多还会使用的 GPU 时长
+	StorageBytes      int64   `json:"storage_bytes"`
+}
+
+// ScopeReport 单个范围的配额与使用情况
+type ScopeReport struct {
+	Scope  Scope     `json:"scope"`
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Plan   string    `json:"plan,omitempty"`
+	Limits Limits    `json:"limits"`
+	Usage  Usage     `json:"usage"`
+}
+
+// Report 项目及其所属组织的配额报告
+type Report struct {
+	Project      *ScopeReport `json:"project"`
+	Organization *ScopeReport `json:"organization,omitempty"`
+	PeriodStart  time.Time    `json:"period_start"`
+}
+
+// Request 待申请的资源
+type Request struct {
+	GPUs         int     // 新增占用的 GPU 数
+	GPUHours     float64 // 新任务最多使用的 GPU 时长（GPU 数 × 超时时长），没有时长限制时为 0
+	Jobs         int     // 新增的并发任务数
+	MemoryGB     int     // 单个任务申请的内存
+	StorageBytes int64   // 新增的存储字节数
+}
+
+// Violation 配额超限详情
+type Violation struct {
+	Scope     Scope     `json:"scope"`
+	ScopeID   uuid.UUID `json:"scope_id"`
+	Resource  Resource  `json:"resource"`
+	Limit     float64   `json:"limit"`
+	Used      float64   `json:"used"`
+	Requested float64   `json:"requested"`
+}
+
+// PlanLimits 各套餐的默认配额，项目/组织的 quota 字段可覆盖其中的任意项
+var PlanLimits = map[string]Limits{
+	"free": {
+		MaxConcurrentGPUs:   1,
+		MaxConcurrentJobs:   2,
+		MaxGPUHoursPerMonth: 20,
+		MaxMemoryGBPerJob:   16,
+		MaxStorageBytes:     10 << 30,
+	},
+	"pro": {
+		MaxConcurrentGPUs:   8,
+		MaxConcurrentJobs:   10,
+		MaxGPUHoursPerMonth: 500,
+		MaxMemoryGBPerJob:   128,
+		MaxStorageBytes:     1 << 40,
+	},
+	"enterprise": {},
+}
+
+// Checker 配额检查器接口
+type Checker interface {
+	// Check 检查项目（及其所属组织）是否有足够配额满足请求，超限或项目不存在时返回 *errors.AppError
+	Check(ctx context.Context, projectID uuid.UUID, req Request) error
+	// Reserve 在一个事务中检查配额并调用 create 写入占用配额的记录；项目和组织行被锁定到事务结束，
+	// 同一项目或组织的并发申请依次检查，不会一起超出配额
+	Reserve(ctx context.Context, projectID uuid.UUID, req Request, create func(tx *gorm.DB) error) error
+	// GetReport 获取项目及所属组织的配额和使用情况
+	GetReport(ctx context.Context, projectID uuid.UUID) (*Report, error)
+}
+
+// checker 基于共享数据库的配额检查器
+type checker struct {
+	db *gorm.DB
+}
+
+// NewChecker 创建配额检查器
+func NewChecker(db *gorm.DB) Checker {
+	return &checker{db: db}
+}
+
+// activeJobStatuses 占用并发配额的训练任务状态
+var activeJobStatuses = []string{"pending", "queued", "running", "stopping", "pausing"}
+
+// activeServiceStatuses 占用 GPU 配额的推理服务状态
+var activeServiceStatuses = []string{"pending", "deploying", "running"}
+
+// Check 检查配额
+func (c *checker) Check(ctx context.Context, projectID uuid.UUID, req Request) error {
+	report, err := c.report(c.db.WithContext(ctx), projectID, false)
+	if err != nil {
+		return err
+	}
+	return report.check(req)
+}
+
+// Reserve 加锁检查配额并在同一事务中创建记录
+func (c *checker) Reserve(ctx context.Context, projectID uuid.UUID, req Request, create func(tx *gorm.DB) error) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		report, err := c.report(tx, projectID, true)
+		if err != nil {
+			return err
+		}
+		if err := report.check(req); err != nil {
+			return err
+		}
+		return create(tx)
+	})
+}
+
+// check 检查项目和组织的配额，超限时返回 403
+func (r *Report) check(req Request) error {
+	for _, scope := range []*ScopeReport{r.Project, r.Organization} {
+		if scope == nil {
+			continue
+		}
+		if v := scope.check(req); v != nil {
+			message := fmt.Sprintf("%s quota exceeded for %s: limit %v, used %v, requested %v",
+				v.Scope, v.Resource, v.Limit, v.Used, v.Requested)
+			return apperrors.Wrap(apperrors.ErrQuotaExceeded, http.StatusForbidden, message).WithDetails(v)
+		}
+	}
+	return nil
+}
+
+// check 检查单个范围是否超限
+func (s *ScopeReport) check(req Request) *Violation {
+	violation := func(resource Resource, limit, used, requested float64) *Violation {
+		return &Violation{
+			Scope:     s.Scope,
+			ScopeID:   s.ID,
+			Resource:  resource,
+			Limit:     limit,
+			Used:      used,
+			Requested: requested,
+		}
+	}
+
+	l, u := s.Limits, s.Usage
+
+	if l.MaxMemoryGBPerJob > 0 && req.MemoryGB > l.MaxMemoryGBPerJob {
+		return violation(ResourceMemoryGBPerJob, float64(l.MaxMemoryGBPerJob), 0, float64(req.MemoryGB))
+	}
+	if l.MaxConcurrentJobs > 0 && req.Jobs > 0 && u.ConcurrentJobs+req.Jobs > l.MaxConcurrentJobs {
+		return violation(ResourceConcurrentJobs, float64(l.MaxConcurrentJobs), float64(u.ConcurrentJobs), float64(req.Jobs))
+	}
+	if l.MaxConcurrentGPUs > 0 && req.GPUs > 0 && u.ConcurrentGPUs+req.GPUs > l.MaxConcurrentGPUs {
+		return violation(ResourceConcurrentGPUs, float64(l.MaxConcurrentGPUs), float64(u.ConcurrentGPUs), float64(req.GPUs))
+	}
+	// 已用时长加上未结束任务和本次申请最多会用的时长，不能超过本月配额
+	if l.MaxGPUHoursPerMonth > 0 && req.GPUs > 0 {
+		used := u.GPUHoursThisMonth + u.GPUHoursReserved
+		if used >= l.MaxGPUHoursPerMonth || used+req.GPUHours > l.MaxGPUHoursPerMonth {
+			return violation(ResourceGPUHoursPerMonth, l.MaxGPUHoursPerMonth, used, req.GPUHours)
+		}
+	}
+	if l.MaxStorageBytes > 0 && req.StorageBytes > 0 && u.StorageBytes+req.StorageBytes > l.MaxStorageBytes {
+		return violation(ResourceStorageBytes, float64(l.MaxStorageBytes), float64(u.StorageBytes), float64(req.StorageBytes))
+	}
+
+	return nil
+}
+
+// GetReport 获取配额报告
+func (c *checker) GetReport(ctx context.Context, projectID uuid.UUID) (*Report, error) {
+	return c.report(c.db.WithContext(ctx), projectID, false)
+}
+
+// report 统计项目及所属组织的配额和使用情况，lock 为 true 时锁定项目和组织行直到事务结束
+func (c *checker) report(db *gorm.DB, projectID uuid.UUID, lock bool) (*Report, error) {
+	periodStart := monthStart(time.Now())
+	rows := db
+	if lock {
+		rows = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+
+	var project models.Project
+	if err := rows.First(&project, "id = ?", projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("project not found: %s", projectID))
+		}
+		return nil, err
+	}
+
+	projectLimits, err := mergeLimits(Limits{}, project.Config["quota"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid project quota: %w", err)
+	}
+
+	projectUsage, err := c.usage(db, "project_id = ?", projectID, periodStart)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Project: &ScopeReport{
+			Scope:  ScopeProject,
+			ID:     project.ID,
+			Name:   project.Name,
+			Limits: projectLimits,
+			Usage:  *projectUsage,
+		},
+		PeriodStart: periodStart,
+	}
+
+	if project.OrgID == nil {
+		return report, nil
+	}
+
+	var org models.Organization
+	if err := rows.First(&org, "id = ?", *project.OrgID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return report, nil
+		}
+		return nil, err
+	}
+
+	orgLimits, err := mergeLimits(PlanLimits[org.Plan], map[string]interface{}(org.Quota))
+	if err != nil {
+		return nil, fmt.Errorf("invalid organization quota: %w", err)
+	}
+
+	orgUsage, err := c.usage(db, "project_id IN (SELECT id FROM projects WHERE org_id = ? AND deleted_at IS NULL)", org.ID, periodStart)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Organization = &ScopeReport{
+		Scope:  ScopeOrganization,
+		ID:     org.ID,
+		Name:   org.Name,
+		Plan:   org.Plan,
+		Limits: orgLimits,
+		Usage:  *orgUsage,
+	}
+
+	return report, nil
+}
+
+// usage 统计满足条件的项目的资源使用量
+func (c *checker) usage(db *gorm.DB, projectFilter string, arg interface{}, periodStart time.Time) (*Usage, error) {
+	usage := &Usage{}
+
+	if db.Migrator().HasTable("training_jobs") {
+		var jobs struct {
+			Jobs int
+			GPUs int
+		}
+		if err := db.Table("training_jobs").
+			Select("COUNT(*) AS jobs, COALESCE(SUM(gpu_count), 0) AS gpus").
+			Where(projectFilter, arg).
+			Where("status IN ?", activeJobStatuses).
+			Scan(&jobs).Error; err != nil {
+			return nil, fmt.Errorf("failed to count training usage: %w", err)
+		}
+		usage.ConcurrentJobs = jobs.Jobs
+		usage.ConcurrentGPUs = jobs.GPUs
+
+		// 本月 GPU 时长：只计算落在本月内的运行时间
+		if err := db.Table("training_jobs").
+			Select("COALESCE(SUM(gpu_count * EXTRACT(EPOCH FROM (COALESCE(completed_at, NOW()) - GREATEST(started_at, ?))) / 3600), 0)", periodStart).
+			Where(projectFilter, arg).
+			Where("gpu_count > 0 AND started_at IS NOT NULL").
+			Where("COALESCE(completed_at, NOW()) > ?", periodStart).
+			Scan(&usage.GPUHoursThisMonth).Error; err != nil {
+			return nil, fmt.Errorf("failed to sum gpu hours: %w", err)
+		}
+
+		// 未结束的任务按超时时长预留剩余的 GPU 时长，运行中的任务扣除当前尝试已运行的时间
+		if err := db.Table("training_jobs").
+			Select(`COALESCE(SUM(gpu_count * GREATEST(timeout_hours - run_seconds / 3600.0 - CASE
+				WHEN status IN ('running', 'pausing') AND started_at IS NOT NULL THEN EXTRACT(EPOCH FROM (NOW() - started_at)) / 3600
+				ELSE 0 END, 0)), 0)`).
+			Where(projectFilter, arg).
+			Where("gpu_count > 0").
+			Where("status IN ?", activeJobStatuses).
+			Scan(&usage.GPUHoursReserved).Error; err != nil {
+			return nil, fmt.Errorf("failed to sum reserved gpu hours: %w", err)
+		}
+	}
+
+	if db.Migrator().HasTable("inference_services") {
+		// 多副本服务按最大副本数占用 GPU，没有 max_replicas 列的旧表按单副本计算
+		gpuExpr := "COALESCE(SUM(gpu_count), 0)"
+		if db.Migrator().HasColumn("inference_services", "max_replicas") {
+			gpuExpr = "COALESCE(SUM(gpu_count * GREATEST(max_replicas, 1)), 0)"
+		}
+		var gpus int
+		if err := db.Table("inference_services").
+			Select(gpuExpr).
+			Where(projectFilter, arg).
+			Where("status IN ?", activeServiceStatuses).
+			Scan(&gpus).Error; err != nil {
+			return nil, fmt.Errorf("failed to count inference usage: %w", err)
+		}
+		usage.ConcurrentGPUs += gpus
+	}
+
+	if db.Migrator().HasTable("datasets") {
+		if err := db.Table("datasets").
+			Select("COALESCE(SUM(size_bytes), 0)").
+			Where(projectFilter, arg).
+			Where("deleted_at IS NULL").
+			Scan(&usage.StorageBytes).Error; err != nil {
+			return nil, fmt.Errorf("failed to sum storage usage: %w", err)
+		}
+	}
+
+	return usage, nil
+}
+
+// mergeLimits 用 JSON 配置覆盖基础配额
+func mergeLimits(base Limits, override interface{}) (Limits, error) {
+	if override == nil {
+		return base, nil
+	}
+
+	data, err := json.Marshal(override)
+	if err != nil {
+		return base, err
+	}
+
+	limits := base
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return base, err
+	}
+	return limits, nil
+}
+
+// monthStart 返回当月第一天零点（UTC）
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,83 @@
+package quota
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+)
+
+func TestScopeReportCheck(t *testing.T) {
+	limits := Limits{
+		MaxConcurrentGPUs:   8,
+		MaxConcurrentJobs:   4,
+		MaxGPUHoursPerMonth: 100,
+		MaxMemoryGBPerJob:   64,
+		MaxStorageBytes:     1 << 30,
+	}
+
+	tests := []struct {
+		name   string
+		limits Limits
+		usage  Usage
+		req    Request
+		want   Resource
+	}{
+		{"within all limits", limits, Usage{ConcurrentGPUs: 2, ConcurrentJobs: 1, GPUHoursThisMonth: 10}, Request{GPUs: 2, GPUHours: 48, Jobs: 1, MemoryGB: 32}, ""},
+		{"unlimited", Limits{}, Usage{ConcurrentGPUs: 100, GPUHoursThisMonth: 1e6}, Request{GPUs: 64, GPUHours: 1e4, Jobs: 1, MemoryGB: 1024}, ""},
+		{"memory per job", limits, Usage{}, Request{MemoryGB: 65}, ResourceMemoryGBPerJob},
+		{"concurrent jobs", limits, Usage{ConcurrentJobs: 4}, Request{Jobs: 1}, ResourceConcurrentJobs},
+		{"concurrent gpus", limits, Usage{ConcurrentGPUs: 7}, Request{GPUs: 2}, ResourceConcurrentGPUs},
+		{"gpus exactly at limit", limits, Usage{ConcurrentGPUs: 6}, Request{GPUs: 2}, ""},
+		{"gpu hours used up", limits, Usage{GPUHoursThisMonth: 100}, Request{GPUs: 1}, ResourceGPUHoursPerMonth},
+		{"gpu hours projected over limit", limits, Usage{GPUHoursThisMonth: 60}, Request{GPUs: 2, GPUHours: 48}, ResourceGPUHoursPerMonth},
+		{"gpu hours projected to limit", limits, Usage{GPUHoursThisMonth: 52}, Request{GPUs: 2, GPUHours: 48}, ""},
+		{"gpu hours reserved by running jobs", limits, Usage{GPUHoursThisMonth: 20, GPUHoursReserved: 60}, Request{GPUs: 1, GPUHours: 24}, ResourceGPUHoursPerMonth},
+		{"reserved hours use up the quota", limits, Usage{GPUHoursReserved: 100}, Request{GPUs: 1}, ResourceGPUHoursPerMonth},
+		{"gpu hours ignored without gpus", limits, Usage{GPUHoursThisMonth: 200}, Request{Jobs: 1}, ""},
+		{"storage", limits, Usage{StorageBytes: 1 << 29}, Request{StorageBytes: 1<<29 + 1}, ResourceStorageBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := &ScopeReport{Scope: ScopeProject, ID: uuid.New(), Limits: tt.limits, Usage: tt.usage}
+			v := scope.check(tt.req)
+			var got Resource
+			if v != nil {
+				got = v.Resource
+			}
+			if got != tt.want {
+				t.Errorf("check() resource = %q, want %q (%+v)", got, tt.want, v)
+			}
+		})
+	}
+}
+
+func TestReportCheckOrganization(t *testing.T) {
+	report := &Report{
+		Project: &ScopeReport{Scope: ScopeProject, ID: uuid.New(), Limits: Limits{MaxConcurrentGPUs: 8}, Usage: Usage{ConcurrentGPUs: 2}},
+		Organization: &ScopeReport{
+			Scope:  ScopeOrganization,
+			ID:     uuid.New(),
+			Limits: Limits{MaxGPUHoursPerMonth: 500},
+			Usage:  Usage{GPUHoursThisMonth: 400, GPUHoursReserved: 90},
+		},
+	}
+
+	if err := report.check(Request{GPUs: 1, GPUHours: 10}); err != nil {
+		t.Fatalf("check() error = %v, want nil", err)
+	}
+
+	err := report.check(Request{GPUs: 1, GPUHours: 11})
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != http.StatusForbidden {
+		t.Fatalf("check() error = %v, want 403", err)
+	}
+	v, ok := appErr.Details.(*Violation)
+	if !ok || v.Scope != ScopeOrganization || v.Resource != ResourceGPUHoursPerMonth || v.Used != 490 || v.Requested != 11 {
+		t.Errorf("details = %+v, want organization gpu hours violation", appErr.Details)
+	}
+}
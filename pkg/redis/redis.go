@@ -244,6 +244,16 @@ func (c *Client) XAdd(ctx context.Context, stream string, values map[string]inte
 	}).Result()
 }
 
+// XAddMaxLen 添加流消息，并将流近似裁剪到 maxLen 条
+func (c *Client) XAddMaxLen(ctx context.Context, stream string, maxLen int64, values map[string]interface{}) (string, error) {
+	return c.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Result()
+}
+
 // XRange 流范围查询
 func (c *Client) XRange(ctx context.Context, stream, start, stop string) ([]redis.XMessage, error) {
 	return c.client.XRange(ctx, stream, start, stop).Result()
@@ -263,6 +273,11 @@ func (c *Client) XGroupCreate(ctx context.Context, stream, group, start string)
 	return c.client.XGroupCreate(ctx, stream, group, start).Err()
 }
 
+// XGroupCreateMkStream 创建消费组，流不存在时一并创建
+func (c *Client) XGroupCreateMkStream(ctx context.Context, stream, group, start string) error {
+	return c.client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+}
+
 // XReadGroup 消费组读取
 func (c *Client) XReadGroup(ctx context.Context, group, consumer string, streams []string, count int64, block time.Duration) ([]redis.XStream, error) {
 	return c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
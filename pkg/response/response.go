@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
 )
 
 // Response is the standard API response type
@@ -15,8 +17,9 @@ type Response struct {
 }
 
 type ErrorInfo struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 type MetaInfo struct {
@@ -65,6 +68,22 @@ func ErrorWithCode(c *gin.Context, status int, code, message string) {
 	})
 }
 
+// AppError returns an error response built from an AppError, including its details
+func AppError(c *gin.Context, err *apperrors.AppError) {
+	status := err.Code
+	if status < 400 || status > 599 {
+		status = http.StatusInternalServerError
+	}
+	c.JSON(status, Response{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    http.StatusText(status),
+			Message: err.Message,
+			Details: err.Details,
+		},
+	})
+}
+
 // Created returns a 201 created response
 func Created(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusCreated, Response{
@@ -0,0 +1,140 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// KeyProvider 主密钥提供者（KMS 风格）：只负责加解密数据密钥，密钥值本身由每个密钥独立的数据密钥加密
+type KeyProvider interface {
+	// KeyID 当前用于加密数据密钥的主密钥 ID
+	KeyID() string
+	// WrapKey 用当前主密钥加密数据密钥
+	WrapKey(dataKey []byte) ([]byte, error)
+	// UnwrapKey 用 keyID 对应的主密钥解密数据密钥
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// localKeyProvider 使用配置中的 AES-256 主密钥
+type localKeyProvider struct {
+	keyID string
+	aead  cipher.AEAD
+}
+
+// NewLocalKeyProvider 创建本地主密钥提供者，masterKey 必须为 32 字节
+func NewLocalKeyProvider(keyID string, masterKey []byte) (KeyProvider, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("master key id is required")
+	}
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	return &localKeyProvider{keyID: keyID, aead: aead}, nil
+}
+
+// KeyID 主密钥 ID
+func (p *localKeyProvider) KeyID() string {
+	return p.keyID
+}
+
+// WrapKey 加密数据密钥
+func (p *localKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	return seal(p.aead, dataKey, []byte(p.keyID))
+}
+
+// UnwrapKey 解密数据密钥
+func (p *localKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != p.keyID {
+		return nil, fmt.Errorf("unknown master key %q", keyID)
+	}
+	return open(p.aead, wrapped, []byte(keyID))
+}
+
+// ParseMasterKey 解析 base64 或 hex 编码的 32 字节主密钥
+func ParseMasterKey(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	for _, decode := range []func(string) ([]byte, error){
+		base64.StdEncoding.DecodeString,
+		base64.RawURLEncoding.DecodeString,
+		hex.DecodeString,
+	} {
+		if key, err := decode(value); err == nil && len(key) == 32 {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("master key must be 32 bytes encoded as base64 or hex")
+}
+
+// encrypt 用新生成的数据密钥加密值，aad 绑定密钥所属的项目和名称，防止密文被挪用到其他密钥
+func encrypt(provider KeyProvider, plaintext, aad []byte) (ciphertext, wrappedKey []byte, keyID string, err error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if ciphertext, err = seal(aead, plaintext, aad); err != nil {
+		return nil, nil, "", err
+	}
+	if wrappedKey, err = provider.WrapKey(dataKey); err != nil {
+		return nil, nil, "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	return ciphertext, wrappedKey, provider.KeyID(), nil
+}
+
+// decrypt 解密 encrypt 的结果
+func decrypt(provider KeyProvider, ciphertext, wrappedKey []byte, keyID string, aad []byte) ([]byte, error) {
+	dataKey, err := provider.UnwrapKey(keyID, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(aead, ciphertext, aad)
+}
+
+// newAEAD 创建 AES-256-GCM
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 加密，输出为 nonce || 密文
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open 解密 seal 的输出
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,359 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+)
+
+// CreateRequest 创建密钥请求
+type CreateRequest struct {
+	ProjectID   string `json:"project_id" binding:"required,uuid"`
+	Name        string `json:"name" binding:"required,max=128"`
+	Value       string `json:"value" binding:"required,max=65536"`
+	Description string `json:"description" binding:"max=1000"`
+}
+
+// UpdateRequest 更新密钥请求，未设置的字段保持不变
+type UpdateRequest struct {
+	Value       *string `json:"value" binding:"omitempty,min=1,max=65536"`
+	Description *string `json:"description" binding:"omitempty,max=1000"`
+}
+
+// AccessLogQuery 审计记录查询条件
+type AccessLogQuery struct {
+	ProjectID  string `form:"project_id" binding:"required,uuid"`
+	SecretName string `form:"secret_name"`
+	Action     string `form:"action" binding:"omitempty,oneof=create update delete inject read"`
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	PageSize   int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// Access 读取密钥值的上下文，写入审计记录
+type Access struct {
+	Action     string     // ActionInject 或 ActionRead，为空时为 ActionInject
+	ActorID    *uuid.UUID // 触发访问的用户
+	Resource   string     // 使用密钥的资源类型，例如 training_job、inference_service
+	ResourceID string
+	Detail     string
+}
+
+// Manager 密钥管理接口
+type Manager interface {
+	// Create 创建密钥
+	Create(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*Secret, error)
+	// List 列出项目的密钥（不含值）
+	List(ctx context.Context, projectID uuid.UUID) ([]*Secret, error)
+	// Get 获取密钥（不含值）
+	Get(ctx context.Context, id uuid.UUID) (*Secret, error)
+	// Update 更新密钥值或描述，更新值时版本号加一
+	Update(ctx context.Context, userID, id uuid.UUID, req *UpdateRequest) (*Secret, error)
+	// Delete 删除密钥，审计记录保留
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	// Check 校验引用格式以及引用的密钥在项目中存在，不读取值
+	Check(ctx context.Context, projectID uuid.UUID, refs []Ref) error
+	// Resolve 解密引用的密钥并为每个密钥写入审计记录
+	Resolve(ctx context.Context, projectID uuid.UUID, refs []Ref, access Access) ([]Resolved, error)
+	// ListAccessLogs 分页列出审计记录，按时间倒序
+	ListAccessLogs(ctx context.Context, query *AccessLogQuery) ([]*AccessLog, int64, error)
+	// AutoMigrate 自动迁移数据库表
+	AutoMigrate() error
+}
+
+// manager Manager 实现
+type manager struct {
+	db       *gorm.DB
+	provider KeyProvider
+}
+
+// NewManager 创建密钥管理器
+func NewManager(db *gorm.DB, provider KeyProvider) Manager {
+	return &manager{db: db, provider: provider}
+}
+
+// Create 创建密钥
+func (m *manager) Create(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*Secret, error) {
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+	}
+	if err := ValidateName(req.Name); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+	}
+
+	secret := &Secret{
+		ID:          uuid.New(),
+		ProjectID:   projectID,
+		Name:        req.Name,
+		Description: req.Description,
+		Version:     1,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+	if err := m.seal(secret, req.Value); err != nil {
+		return nil, err
+	}
+
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&Secret{}).Where("project_id = ? AND name = ?", projectID, req.Name).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check secret name: %w", err)
+		}
+		if count > 0 {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("secret already exists: %s", req.Name))
+		}
+		if err := tx.Create(secret).Error; err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("secret already exists: %s", req.Name))
+			}
+			return fmt.Errorf("failed to create secret: %w", err)
+		}
+		return audit(tx, secret, ActionCreate, Access{ActorID: &userID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// List 列出项目的密钥
+func (m *manager) List(ctx context.Context, projectID uuid.UUID) ([]*Secret, error) {
+	var secrets []*Secret
+	if err := m.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("name ASC").
+		Find(&secrets).Error; err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+// Get 获取密钥
+func (m *manager) Get(ctx context.Context, id uuid.UUID) (*Secret, error) {
+	var secret Secret
+	if err := m.db.WithContext(ctx).First(&secret, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("secret not found: %s", id))
+		}
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	return &secret, nil
+}
+
+// Update 更新密钥
+func (m *manager) Update(ctx context.Context, userID, id uuid.UUID, req *UpdateRequest) (*Secret, error) {
+	secret, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	if req.Value != nil {
+		if err := m.seal(secret, *req.Value); err != nil {
+			return nil, err
+		}
+		secret.Version++
+		changed = append(changed, "value")
+	}
+	if req.Description != nil {
+		secret.Description = *req.Description
+		changed = append(changed, "description")
+	}
+	if len(changed) == 0 {
+		return secret, nil
+	}
+
+	secret.UpdatedBy = userID
+	secret.UpdatedAt = time.Now()
+	err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(secret).Error; err != nil {
+			return fmt.Errorf("failed to update secret: %w", err)
+		}
+		return audit(tx, secret, ActionUpdate, Access{ActorID: &userID, Detail: strings.Join(changed, ",")})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Delete 删除密钥
+func (m *manager) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	secret, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&Secret{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to delete secret: %w", err)
+		}
+		return audit(tx, secret, ActionDelete, Access{ActorID: &userID})
+	})
+}
+
+// Check 校验引用
+func (m *manager) Check(ctx context.Context, projectID uuid.UUID, refs []Ref) error {
+	if err := ValidateRefs(refs); err != nil {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	found, err := m.find(ctx, projectID, refs)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if _, ok := found[ref.Name]; !ok {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("secret not found in project: %s", ref.Name))
+		}
+	}
+	return nil
+}
+
+// Resolve 解密引用的密钥
+func (m *manager) Resolve(ctx context.Context, projectID uuid.UUID, refs []Ref, access Access) ([]Resolved, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	if access.Action == "" {
+		access.Action = ActionInject
+	}
+
+	found, err := m.find(ctx, projectID, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]Resolved, 0, len(refs))
+	accessed := make(map[string]bool, len(refs))
+	logs := make([]*AccessLog, 0, len(refs))
+	for _, ref := range refs {
+		secret, ok := found[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("secret %s not found in project %s", ref.Name, projectID)
+		}
+		value, err := decrypt(m.provider, secret.Ciphertext, secret.WrappedKey, secret.KeyID, additionalData(secret))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt secret %s: %w", ref.Name, err)
+		}
+		resolved = append(resolved, Resolved{Ref: ref, Value: string(value)})
+
+		// 同一个密钥被多次引用时只记录一次
+		if !accessed[ref.Name] {
+			accessed[ref.Name] = true
+			logs = append(logs, newAccessLog(secret, access.Action, access))
+		}
+	}
+
+	// 审计记录写入失败时不返回密钥值
+	if err := m.db.WithContext(ctx).Create(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to record secret access: %w", err)
+	}
+	return resolved, nil
+}
+
+// ListAccessLogs 分页列出审计记录
+func (m *manager) ListAccessLogs(ctx context.Context, query *AccessLogQuery) ([]*AccessLog, int64, error) {
+	var logs []*AccessLog
+	var total int64
+
+	q := m.db.WithContext(ctx).Model(&AccessLog{}).Where("project_id = ?", query.ProjectID)
+	if query.SecretName != "" {
+		q = q.Where("secret_name = ?", query.SecretName)
+	}
+	if query.Action != "" {
+		q = q.Where("action = ?", query.Action)
+	}
+
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count secret access logs: %w", err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := q.Order("created_at DESC").
+		Offset(offset).
+		Limit(query.PageSize).
+		Find(&logs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list secret access logs: %w", err)
+	}
+	return logs, total, nil
+}
+
+// AutoMigrate 自动迁移数据库表
+func (m *manager) AutoMigrate() error {
+	return m.db.AutoMigrate(&Secret{}, &AccessLog{})
+}
+
+// seal 加密密钥值
+func (m *manager) seal(secret *Secret, value string) error {
+	ciphertext, wrappedKey, keyID, err := encrypt(m.provider, []byte(value), additionalData(secret))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+	secret.Ciphertext = ciphertext
+	secret.WrappedKey = wrappedKey
+	secret.KeyID = keyID
+	return nil
+}
+
+// find 按名称查找项目中被引用的密钥
+func (m *manager) find(ctx context.Context, projectID uuid.UUID, refs []Ref) (map[string]*Secret, error) {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+
+	var secrets []*Secret
+	if err := m.db.WithContext(ctx).
+		Where("project_id = ? AND name IN ?", projectID, names).
+		Find(&secrets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get secrets: %w", err)
+	}
+
+	found := make(map[string]*Secret, len(secrets))
+	for _, secret := range secrets {
+		found[secret.Name] = secret
+	}
+	return found, nil
+}
+
+// additionalData 加密时绑定的附加数据
+func additionalData(secret *Secret) []byte {
+	return []byte(secret.ProjectID.String() + "/" + secret.Name)
+}
+
+// audit 在事务中写入审计记录
+func audit(tx *gorm.DB, secret *Secret, action string, access Access) error {
+	if err := tx.Create(newAccessLog(secret, action, access)).Error; err != nil {
+		return fmt.Errorf("failed to record secret access: %w", err)
+	}
+	return nil
+}
+
+// newAccessLog 构建审计记录
+func newAccessLog(secret *Secret, action string, access Access) *AccessLog {
+	return &AccessLog{
+		ID:         uuid.New(),
+		ProjectID:  secret.ProjectID,
+		SecretID:   secret.ID,
+		SecretName: secret.Name,
+		Version:    secret.Version,
+		Action:     action,
+		ActorID:    access.ActorID,
+		Resource:   access.Resource,
+		ResourceID: access.ResourceID,
+		Detail:     access.Detail,
+		CreatedAt:  time.Now(),
+	}
+}
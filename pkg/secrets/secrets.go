@@ -0,0 +1,246 @@
+package secrets
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 审计记录的操作类型
+const (
+	ActionCreate = "create" // 创建密钥
+	ActionUpdate = "update" // 更新密钥值或描述
+	ActionDelete = "delete" // 删除密钥
+	ActionInject = "inject" // 启动容器时注入
+	ActionRead   = "read"   // 服务内部读取，例如重启后恢复日志脱敏
+)
+
+// redactedValue 日志中替换密钥值的文本
+const redactedValue = "[REDACTED]"
+
+// minRedactLength 参与脱敏的最短值，过短的值会误伤大量正常日志
+const minRedactLength = 4
+
+var (
+	// namePattern 密钥名，可直接作为环境变量名时无需在引用中指定 env
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	// envPattern 环境变量名
+	envPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Secret 项目级密钥，值经信封加密后保存，API 不返回明文
+type Secret struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID   uuid.UUID `json:"project_id" gorm:"type:uuid;not null;uniqueIndex:idx_secret_project_name"`
+	Name        string    `json:"name" gorm:"not null;size:128;uniqueIndex:idx_secret_project_name"`
+	Description string    `json:"description"`
+	Ciphertext  []byte    `json:"-" gorm:"not null"` // nonce || AES-GCM 密文
+	WrappedKey  []byte    `json:"-" gorm:"not null"` // 由主密钥加密的数据密钥
+	KeyID       string    `json:"key_id" gorm:"not null;size:64"`
+	Version     int       `json:"version" gorm:"not null;default:1"` // 每次更新值时递增
+	CreatedBy   uuid.UUID `json:"created_by" gorm:"type:uuid"`
+	UpdatedBy   uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (Secret) TableName() string {
+	return "secrets"
+}
+
+// AccessLog 密钥访问审计记录，删除密钥后保留
+type AccessLog struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID  uuid.UUID  `json:"project_id" gorm:"type:uuid;not null;index"`
+	SecretID   uuid.UUID  `json:"secret_id" gorm:"type:uuid;not null;index"`
+	SecretName string     `json:"secret_name" gorm:"not null;size:128"`
+	Version    int        `json:"version"`
+	Action     string     `json:"action" gorm:"not null;size:32;index"`
+	ActorID    *uuid.UUID `json:"actor_id,omitempty" gorm:"type:uuid"` // 操作的用户，服务内部访问时为空
+	Resource   string     `json:"resource,omitempty" gorm:"size:64"`   // 使用密钥的资源类型，例如 training_job
+	ResourceID string     `json:"resource_id,omitempty" gorm:"size:64"`
+	Detail     string     `json:"detail,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"index"`
+}
+
+// TableName 表名
+func (AccessLog) TableName() string {
+	return "secret_access_logs"
+}
+
+// Ref 任务或推理服务对密钥的引用：设置 path 时以文件形式挂载，否则注入为环境变量 env（默认与密钥同名）
+type Ref struct {
+	Name string `json:"name" binding:"required"`
+	Env  string `json:"env,omitempty"`
+	Path string `json:"path,omitempty"` // 容器内的绝对路径
+}
+
+// EnvName 注入的环境变量名，以文件形式挂载时为空
+func (r Ref) EnvName() string {
+	if r.Path != "" {
+		return r.Env
+	}
+	if r.Env != "" {
+		return r.Env
+	}
+	return r.Name
+}
+
+// Validate 校验引用
+func (r Ref) Validate() error {
+	if !namePattern.MatchString(r.Name) {
+		return fmt.Errorf("invalid secret name %q", r.Name)
+	}
+	if env := r.EnvName(); env != "" && !envPattern.MatchString(env) {
+		if r.Env == "" {
+			return fmt.Errorf("secret %q is not a valid environment variable name, set env or path", r.Name)
+		}
+		return fmt.Errorf("invalid env %q for secret %q", r.Env, r.Name)
+	}
+	if r.Path != "" {
+		if !path.IsAbs(r.Path) || path.Clean(r.Path) != r.Path || r.Path == "/" {
+			return fmt.Errorf("path for secret %q must be a clean absolute file path", r.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateRefs 校验一组引用，同一个环境变量或路径只能由一个引用设置
+func ValidateRefs(refs []Ref) error {
+	envs := make(map[string]bool, len(refs))
+	paths := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if err := ref.Validate(); err != nil {
+			return err
+		}
+		if env := ref.EnvName(); env != "" {
+			if envs[env] {
+				return fmt.Errorf("environment variable %s is set by more than one secret", env)
+			}
+			envs[env] = true
+		}
+		if ref.Path != "" {
+			if paths[ref.Path] {
+				return fmt.Errorf("path %s is set by more than one secret", ref.Path)
+			}
+			paths[ref.Path] = true
+		}
+	}
+	return nil
+}
+
+// ValidateName 校验密钥名
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '_', '.' and '-' (max 128)", name)
+	}
+	return nil
+}
+
+// Resolved 解密后的密钥引用，只在启动容器时使用，不能持久化
+type Resolved struct {
+	Ref
+	Value string
+}
+
+// Env 以 KEY=VALUE 形式返回需要注入为环境变量的密钥
+func Env(resolved []Resolved) []string {
+	var env []string
+	for _, secret := range resolved {
+		if name := secret.EnvName(); name != "" {
+			env = append(env, name+"="+secret.Value)
+		}
+	}
+	return env
+}
+
+// Files 返回需要以文件形式挂载的密钥，键为容器内路径
+func Files(resolved []Resolved) map[string]string {
+	files := make(map[string]string)
+	for _, secret := range resolved {
+		if secret.Path != "" {
+			files[secret.Path] = secret.Value
+		}
+	}
+	return files
+}
+
+// Redactor 将日志中出现的密钥值替换为 [REDACTED]
+type Redactor struct {
+	replacer *strings.Replacer
+}
+
+// NewRedactor 创建脱敏器，多行的值按行脱敏（日志按行收集）
+func NewRedactor(resolved []Resolved) *Redactor {
+	var values []string
+	seen := make(map[string]bool)
+	for _, secret := range resolved {
+		for _, value := range append([]string{secret.Value}, strings.Split(secret.Value, "\n")...) {
+			value = strings.TrimSpace(value)
+			if len(value) < minRedactLength || seen[value] {
+				continue
+			}
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	// 较长的值优先匹配，避免包含其他密钥的值只被替换一部分
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	pairs := make([]string, 0, 2*len(values))
+	for _, value := range values {
+		pairs = append(pairs, value, redactedValue)
+	}
+	return &Redactor{replacer: strings.NewReplacer(pairs...)}
+}
+
+// Redact 脱敏一行日志，nil 脱敏器原样返回
+func (r *Redactor) Redact(line string) string {
+	if r == nil {
+		return line
+	}
+	return r.replacer.Replace(line)
+}
+
+// Archive 将以文件形式挂载的密钥打包为 tar，解压到容器根目录即得到对应路径的只读文件
+func Archive(files map[string]string) (*bytes.Buffer, error) {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	now := time.Now()
+	for _, p := range paths {
+		content := files[p]
+		header := &tar.Header{
+			Name:    strings.TrimPrefix(p, "/"),
+			Mode:    0444,
+			Size:    int64(len(content)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write secret file %s: %w", p, err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return nil, fmt.Errorf("failed to write secret file %s: %w", p, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to archive secret files: %w", err)
+	}
+	return &buf, nil
+}
@@ -0,0 +1,379 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+)
+
+// maxDrainBody 读取并丢弃的响应体长度上限，读完响应体后连接可以复用
+const maxDrainBody = 64 * 1024
+
+// Config 投递配置
+type Config struct {
+	Group          string        // 事件总线消费组
+	Consumer       string        // 消费者名称，多实例部署时应各不相同
+	MaxAttempts    int           // 最多投递次数，用尽后进入死信
+	InitialBackoff time.Duration // 首次重试的等待时间，之后每次翻倍
+	MaxBackoff     time.Duration // 重试等待时间上限
+	Timeout        time.Duration // 单次请求超时
+	PollInterval   time.Duration // 检查到期重试的间隔
+	Workers        int           // 并发投递数
+}
+
+// DefaultConfig 默认投递配置
+func DefaultConfig() Config {
+	consumer, _ := os.Hostname()
+	return Config{
+		Group:          "webhooks",
+		Consumer:       consumer,
+		MaxAttempts:    8,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Hour,
+		Timeout:        10 * time.Second,
+		PollInterval:   15 * time.Second,
+		Workers:        4,
+	}
+}
+
+// Dispatcher 将事件总线上的事件投递到订阅的 Webhook
+//
+// 收到事件后为每个匹配的 Webhook 写入一条投递记录再确认消息，投递循环发送到期的记录；
+// 失败时按指数退避重试，次数用尽后标记为死信。投递记录保存在数据库中，服务重启后继续重试。
+type Dispatcher struct {
+	db     *gorm.DB
+	bus    *events.Bus
+	client *http.Client
+	config Config
+
+	notifyCh chan struct{}
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewDispatcher 创建投递器，未设置的配置项使用默认值
+func NewDispatcher(db *gorm.DB, bus *events.Bus, config Config) *Dispatcher {
+	defaults := DefaultConfig()
+	if config.Group == "" {
+		config.Group = defaults.Group
+	}
+	if config.Consumer == "" {
+		config.Consumer = defaults.Consumer
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaults.InitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaults.MaxBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+
+	return &Dispatcher{
+		db:       db,
+		bus:      bus,
+		client:   newHTTPClient(config.Timeout),
+		config:   config,
+		notifyCh: make(chan struct{}, 1),
+	}
+}
+
+// AutoMigrate 自动迁移表结构
+func (d *Dispatcher) AutoMigrate() error {
+	return d.db.AutoMigrate(&Webhook{}, &Delivery{})
+}
+
+// Start 开始消费事件和投递
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.wg.Add(2)
+	go func() {
+		defer d.wg.Done()
+		if err := d.bus.Consume(ctx, d.config.Group, d.config.Consumer, d.enqueue); err != nil {
+			logger.Error("Webhook event consumer stopped", zap.Error(err))
+		}
+	}()
+	go d.loop(ctx)
+
+	logger.Info("Webhook dispatcher started",
+		zap.String("group", d.config.Group),
+		zap.String("consumer", d.config.Consumer),
+		zap.Int("max_attempts", d.config.MaxAttempts),
+	)
+}
+
+// Stop 停止投递，等待进行中的请求结束
+func (d *Dispatcher) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+}
+
+// Notify 唤醒投递循环
+func (d *Dispatcher) Notify() {
+	select {
+	case d.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue 为订阅了事件的 Webhook 写入投递记录
+func (d *Dispatcher) enqueue(ctx context.Context, event *events.Event) error {
+	var hooks []*Webhook
+	if err := d.db.WithContext(ctx).
+		Where("project_id = ? AND active = ?", event.ProjectID, true).
+		Find(&hooks).Error; err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	now := time.Now()
+	var deliveries []*Delivery
+	for _, hook := range hooks {
+		if !subscribed(hook, event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, &Delivery{
+			WebhookID:     hook.ID,
+			ProjectID:     hook.ProjectID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       string(payload),
+			Status:        DeliveryPending,
+			NextAttemptAt: &now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	// 事件被重新投递时不重复写入
+	if err := d.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+
+	d.Notify()
+	return nil
+}
+
+// subscribed 检查 Webhook 是否订阅了事件类型
+func subscribed(hook *Webhook, eventType string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+	for _, pattern := range hook.Events {
+		if events.MatchType(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// loop 投递循环
+func (d *Dispatcher) loop(ctx context.Context) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.notifyCh:
+		}
+	}
+}
+
+// deliverDue 发送全部到期的投递记录
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		var due []*Delivery
+		if err := d.db.WithContext(ctx).
+			Where("status IN ? AND next_attempt_at <= ?", []DeliveryStatus{DeliveryPending, DeliveryRetrying}, time.Now()).
+			Order("next_attempt_at").
+			Limit(d.config.Workers * 10).
+			Find(&due).Error; err != nil {
+			logger.Error("Failed to list due webhook deliveries", zap.Error(err))
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+
+		sem := make(chan struct{}, d.config.Workers)
+		var wg sync.WaitGroup
+		var claimed int
+		for _, delivery := range due {
+			if !d.claim(ctx, delivery) {
+				continue
+			}
+			claimed++
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(delivery *Delivery) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				d.attempt(ctx, delivery)
+			}(delivery)
+		}
+		wg.Wait()
+
+		if claimed == 0 {
+			return
+		}
+	}
+}
+
+// claim 将投递记录的下次投递时间推迟到请求超时之后，多实例部署时只有一个实例能取得记录
+func (d *Dispatcher) claim(ctx context.Context, delivery *Delivery) bool {
+	lease := time.Now().Add(2 * d.config.Timeout)
+	result := d.db.WithContext(ctx).
+		Model(&Delivery{}).
+		Where("id = ? AND next_attempt_at = ?", delivery.ID, delivery.NextAttemptAt).
+		Update("next_attempt_at", lease)
+	if result.Error != nil {
+		logger.Warn("Failed to claim webhook delivery", zap.String("delivery_id", delivery.ID.String()), zap.Error(result.Error))
+		return false
+	}
+	return result.RowsAffected > 0
+}
+
+// attempt 发送一次并记录结果
+func (d *Dispatcher) attempt(ctx context.Context, delivery *Delivery) {
+	var hook Webhook
+	if err := d.db.WithContext(ctx).First(&hook, "id = ?", delivery.WebhookID).Error; err != nil {
+		logger.Warn("Webhook of delivery not found", zap.String("delivery_id", delivery.ID.String()), zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"attempts":   delivery.Attempts + 1,
+		"updated_at": now,
+	}
+
+	var status int
+	var err error
+	if hook.Active {
+		status, err = d.send(ctx, &hook, delivery)
+	} else {
+		err = fmt.Errorf("webhook is disabled")
+		updates["attempts"] = d.config.MaxAttempts
+	}
+	updates["response_status"] = status
+
+	switch {
+	case err == nil:
+		updates["status"] = DeliverySucceeded
+		updates["error"] = ""
+		updates["next_attempt_at"] = nil
+		updates["delivered_at"] = now
+	case updates["attempts"].(int) >= d.config.MaxAttempts:
+		updates["status"] = DeliveryDeadLetter
+		updates["error"] = err.Error()
+		updates["next_attempt_at"] = nil
+		logger.Warn("Webhook delivery moved to dead letter",
+			zap.String("delivery_id", delivery.ID.String()),
+			zap.String("webhook_id", hook.ID.String()),
+			zap.String("event_type", delivery.EventType),
+			zap.Error(err),
+		)
+	default:
+		updates["status"] = DeliveryRetrying
+		updates["error"] = err.Error()
+		updates["next_attempt_at"] = now.Add(d.backoff(delivery.Attempts + 1))
+	}
+
+	// 请求可能在 ctx 取消时被中断，结果仍需记录
+	saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.db.WithContext(saveCtx).Model(&Delivery{}).Where("id = ?", delivery.ID).Updates(updates).Error; err != nil {
+		logger.Error("Failed to record webhook delivery", zap.String("delivery_id", delivery.ID.String()), zap.Error(err))
+	}
+}
+
+// send 发送签名后的请求，非 2xx 响应（包括重定向）视为失败
+func (d *Dispatcher) send(ctx context.Context, hook *Webhook, delivery *Delivery) (int, error) {
+	if err := ValidateURL(hook.URL, true); err != nil {
+		return 0, err
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AITIP-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, "sha256="+Sign(hook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrAddressNotAllowed) {
+			return 0, ErrAddressNotAllowed
+		}
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff 第 attempts 次失败后的等待时间
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.config.InitialBackoff
+	for i := 1; i < attempts && wait < d.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.config.MaxBackoff {
+		wait = d.config.MaxBackoff
+	}
+	return wait
+}
@@ -0,0 +1,62 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		allowHTTP bool
+		wantErr   bool
+	}{
+		{url: "https://hooks.example.com/aitip", wantErr: false},
+		{url: "http://hooks.example.com/aitip", wantErr: true},
+		{url: "http://hooks.example.com/aitip", allowHTTP: true, wantErr: false},
+		{url: "https://8.8.8.8/hook", wantErr: false},
+		{url: "https://localhost/hook", wantErr: true},
+		{url: "https://api.localhost./hook", wantErr: true},
+		{url: "https://127.0.0.1:8080/hook", wantErr: true},
+		{url: "https://10.1.2.3/hook", wantErr: true},
+		{url: "https://172.16.0.1/hook", wantErr: true},
+		{url: "https://192.168.1.1/hook", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data", allowHTTP: true, wantErr: true},
+		{url: "https://[::1]/hook", wantErr: true},
+		{url: "https://[fd00:ec2::254]/hook", wantErr: true},
+		{url: "https://0.0.0.0/hook", wantErr: true},
+		{url: "not a url", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateURL(tt.url, tt.allowHTTP)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateURL(%q, %v) error = %v, wantErr %v", tt.url, tt.allowHTTP, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := newHTTPClient(time.Second).Post(server.URL, "application/json", nil)
+	if !errors.Is(err, ErrAddressNotAllowed) {
+		t.Fatalf("Post() error = %v, want ErrAddressNotAllowed", err)
+	}
+}
+
+func TestHTTPClientDoesNotFollowRedirects(t *testing.T) {
+	client := newHTTPClient(time.Second)
+	req := httptest.NewRequest(http.MethodPost, "https://hooks.example.com/redirected", nil)
+	if err := client.CheckRedirect(req, []*http.Request{req}); !errors.Is(err, http.ErrUseLastResponse) {
+		t.Fatalf("CheckRedirect() = %v, want http.ErrUseLastResponse", err)
+	}
+}
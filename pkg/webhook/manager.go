@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+)
+
+// CreateRequest 注册 Webhook 请求
+type CreateRequest struct {
+	ProjectID   string   `json:"project_id" binding:"required,uuid"`
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events"`                            // 为空时订阅全部事件
+	Secret      string   `json:"secret" binding:"omitempty,min=16"` // 为空时自动生成
+	Description string   `json:"description"`
+}
+
+// UpdateRequest 更新 Webhook 请求，未设置的字段保持不变
+type UpdateRequest struct {
+	URL          *string  `json:"url"`
+	Events       []string `json:"events"`
+	Description  *string  `json:"description"`
+	Active       *bool    `json:"active"`
+	RotateSecret bool     `json:"rotate_secret"` // 重新生成签名密钥
+}
+
+// Created 注册或轮换密钥后的 Webhook，签名密钥只在此时返回
+type Created struct {
+	*Webhook
+	Secret string `json:"secret"`
+}
+
+// DeliveryQuery 投递记录查询条件
+type DeliveryQuery struct {
+	ProjectID string         `form:"project_id" binding:"omitempty,uuid"`
+	Status    DeliveryStatus `form:"status" binding:"omitempty,oneof=pending retrying succeeded dead_letter"`
+	EventType string         `form:"event_type"`
+	Page      int            `form:"page,default=1" binding:"min=1"`
+	PageSize  int            `form:"page_size,default=20" binding:"min=1,max=100"`
+	WebhookID *uuid.UUID     `form:"-"`
+}
+
+// Manager Webhook 管理接口
+type Manager interface {
+	// Create 注册 Webhook
+	Create(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*Created, error)
+	// List 列出项目的 Webhook
+	List(ctx context.Context, projectID uuid.UUID) ([]*Webhook, error)
+	// Get 获取 Webhook
+	Get(ctx context.Context, id uuid.UUID) (*Webhook, error)
+	// Update 更新 Webhook，轮换密钥时返回的 Created.Secret 非空
+	Update(ctx context.Context, id uuid.UUID, req *UpdateRequest) (*Created, error)
+	// Delete 删除 Webhook 及其投递记录
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDeliveries 分页列出投递记录，按创建时间倒序
+	ListDeliveries(ctx context.Context, query *DeliveryQuery) ([]*Delivery, int64, error)
+	// Redeliver 重新投递，重置重试次数
+	Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*Delivery, error)
+}
+
+// manager Manager 实现
+type manager struct {
+	db        *gorm.DB
+	allowHTTP bool
+	notify    func()
+}
+
+// NewManager 创建 Webhook 管理器，notify 在有新的待投递记录时调用（通常为 Dispatcher.Notify）
+func NewManager(db *gorm.DB, allowHTTP bool, notify func()) Manager {
+	return &manager{
+		db:        db,
+		allowHTTP: allowHTTP,
+		notify:    notify,
+	}
+}
+
+// Create 注册 Webhook
+func (m *manager) Create(ctx context.Context, userID uuid.UUID, req *CreateRequest) (*Created, error) {
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+	}
+	if err := ValidateURL(req.URL, m.allowHTTP); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = GenerateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	hook := &Webhook{
+		ID:          uuid.New(),
+		ProjectID:   projectID,
+		UserID:      userID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		Description: req.Description,
+		Active:      true,
+	}
+	if err := m.db.WithContext(ctx).Create(hook).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return &Created{Webhook: hook, Secret: secret}, nil
+}
+
+// List 列出项目的 Webhook
+func (m *manager) List(ctx context.Context, projectID uuid.UUID) ([]*Webhook, error) {
+	var hooks []*Webhook
+	if err := m.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("created_at DESC").
+		Find(&hooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return hooks, nil
+}
+
+// Get 获取 Webhook
+func (m *manager) Get(ctx context.Context, id uuid.UUID) (*Webhook, error) {
+	var hook Webhook
+	if err := m.db.WithContext(ctx).First(&hook, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("webhook not found: %s", id))
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &hook, nil
+}
+
+// Update 更新 Webhook
+func (m *manager) Update(ctx context.Context, id uuid.UUID, req *UpdateRequest) (*Created, error) {
+	hook, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Created{Webhook: hook}
+	if req.URL != nil {
+		if err := ValidateURL(*req.URL, m.allowHTTP); err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+		}
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		hook.Events = req.Events
+	}
+	if req.Description != nil {
+		hook.Description = *req.Description
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+	if req.RotateSecret {
+		if hook.Secret, err = GenerateSecret(); err != nil {
+			return nil, err
+		}
+		result.Secret = hook.Secret
+	}
+
+	hook.UpdatedAt = time.Now()
+	if err := m.db.WithContext(ctx).Save(hook).Error; err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return result, nil
+}
+
+// Delete 删除 Webhook 及其投递记录
+func (m *manager) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := m.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&Delivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		if err := tx.Delete(&Webhook{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListDeliveries 分页列出投递记录
+func (m *manager) ListDeliveries(ctx context.Context, query *DeliveryQuery) ([]*Delivery, int64, error) {
+	var deliveries []*Delivery
+	var total int64
+
+	q := m.db.WithContext(ctx).Model(&Delivery{})
+	if query.WebhookID != nil {
+		q = q.Where("webhook_id = ?", *query.WebhookID)
+	}
+	if query.ProjectID != "" {
+		if projectID, err := uuid.Parse(query.ProjectID); err == nil {
+			q = q.Where("project_id = ?", projectID)
+		}
+	}
+	if query.Status != "" {
+		q = q.Where("status = ?", query.Status)
+	}
+	if query.EventType != "" {
+		q = q.Where("event_type = ?", query.EventType)
+	}
+
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	if err := q.Order("created_at DESC").
+		Offset(offset).
+		Limit(query.PageSize).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
+
+// Redeliver 重新投递
+func (m *manager) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*Delivery, error) {
+	var delivery Delivery
+	if err := m.db.WithContext(ctx).First(&delivery, "id = ? AND webhook_id = ?", deliveryID, webhookID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("delivery not found: %s", deliveryID))
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if delivery.Status == DeliveryPending || delivery.Status == DeliveryRetrying {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, "delivery is already scheduled")
+	}
+
+	now := time.Now()
+	delivery.Status = DeliveryPending
+	delivery.Attempts = 0
+	delivery.Error = ""
+	delivery.NextAttemptAt = &now
+	delivery.UpdatedAt = now
+	if err := m.db.WithContext(ctx).Save(&delivery).Error; err != nil {
+		return nil, fmt.Errorf("failed to schedule redelivery: %w", err)
+	}
+
+	if m.notify != nil {
+		m.notify()
+	}
+	return &delivery, nil
+}
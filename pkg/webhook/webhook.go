@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 请求头
+const (
+	HeaderEvent     = "X-AITIP-Event"
+	HeaderDelivery  = "X-AITIP-Delivery"
+	HeaderTimestamp = "X-AITIP-Timestamp"
+	HeaderSignature = "X-AITIP-Signature"
+)
+
+// DeliveryStatus 投递状态
+type DeliveryStatus string
+
+const (
+	DeliveryPending    DeliveryStatus = "pending"
+	DeliveryRetrying   DeliveryStatus = "retrying"
+	DeliverySucceeded  DeliveryStatus = "succeeded"
+	DeliveryDeadLetter DeliveryStatus = "dead_letter" // 重试次数用尽，可手动重新投递
+)
+
+// Webhook 项目的事件回调地址
+type Webhook struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID   uuid.UUID `json:"project_id" gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid"`
+	URL         string    `json:"url" gorm:"not null"`
+	Secret      string    `json:"-" gorm:"not null"`
+	Events      []string  `json:"events" gorm:"serializer:json"` // 订阅的事件类型，支持 "training.job.*" 通配，为空时订阅全部
+	Description string    `json:"description"`
+	Active      bool      `json:"active" gorm:"not null;default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Delivery 单个事件到单个 Webhook 的投递记录
+type Delivery struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	WebhookID      uuid.UUID      `json:"webhook_id" gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	ProjectID      uuid.UUID      `json:"project_id" gorm:"type:uuid;not null;index"`
+	EventID        string         `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType      string         `json:"event_type" gorm:"not null"`
+	Payload        string         `json:"payload" gorm:"type:text"`
+	Status         DeliveryStatus `json:"status" gorm:"not null;index"`
+	Attempts       int            `json:"attempts"`
+	ResponseStatus int            `json:"response_status,omitempty"` // 不保存响应体，避免通过投递记录读取回调地址返回的内容
+	Error          string         `json:"error,omitempty"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty" gorm:"index"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// TableName 表名
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// Sign 计算签名：hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+//
+// 接收方应使用 X-AITIP-Timestamp 和原始请求体重新计算并与 X-AITIP-Signature 中 "sha256=" 之后的部分
+// 做常量时间比较，并拒绝时间戳过旧的请求以防重放。
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret 生成签名密钥
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// ValidateURL 校验回调地址，allowHTTP 为 false 时只接受 HTTPS
+//
+// 指向 localhost 或内网 IP 的地址直接拒绝；域名的解析结果在每次投递连接时检查。
+func ValidateURL(raw string, allowHTTP bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("invalid url: %s", raw)
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && allowHTTP:
+	default:
+		return fmt.Errorf("url must use https: %s", raw)
+	}
+	return checkHost(u.Hostname())
+}
@@ -10,6 +10,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/quota"
+	pkgredis "github.com/plucky-groove3/ai-train-infer-platform/pkg/redis"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/data/internal/config"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/data/internal/handler"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/data/internal/minio"
@@ -73,7 +76,16 @@ func main() {
 	datasetRepo := repository.NewDatasetRepository(db)
 
 	// 初始化服务
-	datasetService := service.NewDatasetService(datasetRepo, minioClient, redisClient, cfg)
+	datasetService := service.NewDatasetService(datasetRepo, minioClient, redisClient, cfg, quota.NewChecker(db))
+
+	// 初始化事件总线，数据集生命周期事件发布到各服务共用的事件流
+	eventRedis, err := pkgredis.New(cfg.Redis.ToRedisConfig())
+	if err != nil {
+		log.Warn("Failed to connect to event bus, dataset events are disabled", zap.Error(err))
+	} else {
+		defer eventRedis.Close()
+		datasetService.SetEventPublisher(events.NewBus(eventRedis, os.Getenv("EVENT_STREAM"), 0))
+	}
 
 	// 初始化处理器
 	uploadConfig := handler.UploadConfig{
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/data/internal/domain"
@@ -260,6 +262,11 @@ func (h *DatasetHandler) Upload(c *gin.Context) {
 	// 上传文件
 	if err := h.service.UploadFile(c.Request.Context(), datasetResp.ID.String(), header.Filename, file, header.Size); err != nil {
 		logger.Error("failed to upload file", zap.Error(err))
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			response.AppError(c, appErr)
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -285,6 +292,11 @@ func (h *DatasetHandler) InitUpload(c *gin.Context) {
 	resp, err := h.service.InitUpload(c.Request.Context(), &req)
 	if err != nil {
 		logger.Error("failed to init upload", zap.Error(err))
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			response.AppError(c, appErr)
+			return
+		}
 		response.ErrorWithCode(c, http.StatusBadRequest, "INIT_FAILED", err.Error())
 		return
 	}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,7 +13,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/quota"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/data/internal/minio"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/data/internal/config"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/data/internal/domain"
@@ -34,6 +38,7 @@ type DatasetService interface {
 	CompleteUpload(ctx context.Context, uploadID string, checksum string) (*domain.DatasetResponse, error)
 	GetUploadProgress(ctx context.Context, uploadID string) (*domain.UploadProgress, error)
 	Download(ctx context.Context, id string) (*domain.DownloadResponse, error)
+	SetEventPublisher(publisher events.Publisher)
 }
 
 // datasetService 数据集服务实现
@@ -42,15 +47,18 @@ type datasetService struct {
 	minioClient   *minio.Client
 	redisClient   *redis.Client
 	config        *config.Config
+	quota         quota.Checker
+	events        events.Publisher
 }
 
 // NewDatasetService 创建数据集服务
-func NewDatasetService(repo repository.DatasetRepository, minioClient *minio.Client, redisClient *redis.Client, cfg *config.Config) DatasetService {
+func NewDatasetService(repo repository.DatasetRepository, minioClient *minio.Client, redisClient *redis.Client, cfg *config.Config, quotaChecker quota.Checker) DatasetService {
 	return &datasetService{
 		repo:        repo,
 		minioClient: minioClient,
 		redisClient: redisClient,
 		config:      cfg,
+		quota:       quotaChecker,
 	}
 }
 
@@ -87,6 +95,7 @@ func (s *datasetService) Create(ctx context.Context, req *domain.DatasetCreateRe
 	if err := s.repo.Create(ctx, dataset); err != nil {
 		return nil, fmt.Errorf("failed to create dataset: %w", err)
 	}
+	s.publish(dataset, "created", "")
 
 	return s.toResponse(dataset), nil
 }
@@ -198,6 +207,7 @@ func (s *datasetService) Delete(ctx context.Context, id string) error {
 	if err := s.repo.Delete(ctx, datasetID); err != nil {
 		return fmt.Errorf("failed to delete dataset: %w", err)
 	}
+	s.publish(dataset, "deleted", "")
 
 	return nil
 }
@@ -215,6 +225,11 @@ func (s *datasetService) InitUpload(ctx context.Context, req *domain.UploadInitR
 
 	projectID, _ := uuid.Parse(req.ProjectID)
 
+	// 检查存储配额
+	if err := s.quota.Check(ctx, projectID, quota.Request{StorageBytes: req.Size}); err != nil {
+		return nil, err
+	}
+
 	// 检查同名数据集
 	exists, err := s.repo.ExistsByProjectAndName(ctx, projectID, req.Name)
 	if err != nil {
@@ -308,6 +323,13 @@ func (s *datasetService) UploadFile(ctx context.Context, datasetID string, filen
 		return fmt.Errorf("file size exceeds maximum limit")
 	}
 
+	// 检查存储配额（只计算相对原有大小的增量）
+	if delta := size - dataset.SizeBytes; delta > 0 {
+		if err := s.quota.Check(ctx, dataset.ProjectID, quota.Request{StorageBytes: delta}); err != nil {
+			return err
+		}
+	}
+
 	// 构建存储路径
 	bucketName := minio.GetProjectBucketName(dataset.ProjectID.String())
 	objectName := minio.GetDatasetObjectName(dataset.ProjectID.String(), datasetID, filename)
@@ -319,9 +341,13 @@ func (s *datasetService) UploadFile(ctx context.Context, datasetID string, filen
 		PartSize:    uint64(s.config.Upload.ChunkSize),
 	}
 
-	result, err := s.minioClient.Upload(ctx, bucketName, objectName, reader, size, opts)
+	// 上传的同时计算校验和，训练任务以此区分数据集内容的版本
+	hash := sha256.New()
+	result, err := s.minioClient.Upload(ctx, bucketName, objectName, io.TeeReader(reader, hash), size, opts)
 	if err != nil {
 		s.repo.UpdateStatus(ctx, id, domain.DatasetStatusFailed)
+		dataset.Status = domain.DatasetStatusFailed
+		s.publish(dataset, "failed", err.Error())
 		return fmt.Errorf("failed to upload file: %w", err)
 	}
 
@@ -331,6 +357,7 @@ func (s *datasetService) UploadFile(ctx context.Context, datasetID string, filen
 	dataset.Format = format
 	dataset.Status = domain.DatasetStatusReady
 	dataset.OriginalName = filename
+	dataset.Checksum = "sha256:" + hex.EncodeToString(hash.Sum(nil))
 
 	if err := s.repo.Update(ctx, dataset); err != nil {
 		return fmt.Errorf("failed to update dataset: %w", err)
@@ -340,6 +367,7 @@ func (s *datasetService) UploadFile(ctx context.Context, datasetID string, filen
 		zap.String("dataset_id", datasetID),
 		zap.Int64("size", result.Size),
 	)
+	s.publish(dataset, "ready", "")
 
 	return nil
 }
@@ -442,6 +470,7 @@ func (s *datasetService) CompleteUpload(ctx context.Context, uploadID string, ch
 	// 删除进度记录
 	progressKey := s.config.Redis.GetUploadProgressKey(uploadID)
 	s.redisClient.Del(ctx, progressKey)
+	s.publish(dataset, "ready", "")
 
 	return s.toResponse(dataset), nil
 }
@@ -510,8 +539,39 @@ func (s *datasetService) Download(ctx context.Context, id string) (*domain.Downl
 	}, nil
 }
 
+// SetEventPublisher 设置事件发布器，未设置时不发布数据集事件
+func (s *datasetService) SetEventPublisher(publisher events.Publisher) {
+	s.events = publisher
+}
+
 // 辅助方法
 
+// publish 以 data.dataset.<action> 类型发布数据集事件，失败时只记录日志
+func (s *datasetService) publish(dataset *domain.Dataset, action, message string) {
+	if s.events == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.events.Publish(ctx, &events.Event{
+		Type:       "data.dataset." + action,
+		Source:     events.SourceData,
+		ProjectID:  dataset.ProjectID,
+		ResourceID: dataset.ID,
+		Status:     string(dataset.Status),
+		Message:    message,
+		Data: map[string]interface{}{
+			"name":       dataset.Name,
+			"size_bytes": dataset.SizeBytes,
+		},
+	})
+	if err != nil {
+		logger.Warn("failed to publish dataset event", zap.Error(err), zap.String("dataset_id", dataset.ID.String()))
+	}
+}
+
 func (s *datasetService) toResponse(dataset *domain.Dataset) *domain.DatasetResponse {
 	return &domain.DatasetResponse{
 		ID:           dataset.ID,
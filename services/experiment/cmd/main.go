@@ -4,14 +4,18 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/database"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	pkgredis "github.com/plucky-groove3/ai-train-infer-platform/pkg/redis"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/handler"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/repository"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/service"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/sweep"
 	"go.uber.org/zap"
 )
 
@@ -21,6 +25,10 @@ func main() {
 	port := getEnv("PORT", "8085")
 	mlflowEnabled := getEnvBool("MLFLOW_ENABLED", false)
 	mlflowURI := getEnv("MLFLOW_TRACKING_URI", "http://localhost:5000")
+	trainingURL := getEnv("TRAINING_SERVICE_URL", "http://localhost:8081")
+	sweepInterval := getEnvDuration("SWEEP_POLL_INTERVAL", 15*time.Second)
+	redisURL := getEnv("REDIS_URL", "redis://localhost:6379")
+	eventStream := getEnv("EVENT_STREAM", "aitip:events")
 
 	// Initialize logger
 	if err := logger.InitDevelopment(); err != nil {
@@ -38,12 +46,29 @@ func main() {
 	runRepo := repository.NewRunRepository(db)
 	metricRepo := repository.NewMetricRepository(db)
 	artifactRepo := repository.NewArtifactRepository(db)
+	sweepRepo := repository.NewSweepRepository(db)
+	if err := sweepRepo.AutoMigrate(); err != nil {
+		logger.Log.Fatal("Failed to migrate sweep tables", zap.Error(err))
+	}
+
+	// Start sweep controller
+	sweepController := sweep.NewController(sweepRepo, runRepo, expRepo, metricRepo, sweep.NewTrainingClient(trainingURL), sweepInterval)
+	redisClient, err := pkgredis.NewFromURL(redisURL)
+	if err != nil {
+		logger.Log.Warn("Failed to connect to Redis, sweep events are disabled", zap.Error(err))
+	} else {
+		defer redisClient.Close()
+		sweepController.SetEventPublisher(events.NewBus(redisClient, eventStream, 0))
+	}
+	sweepController.Start()
+	defer sweepController.Stop()
 
 	// Initialize services
 	expService := service.NewExperimentService(expRepo, runRepo, metricRepo, artifactRepo)
 	runService := service.NewRunService(runRepo, expRepo, metricRepo)
 	metricService := service.NewMetricService(metricRepo, runRepo)
 	vizService := service.NewVisualizationService(expRepo, runRepo, metricRepo)
+	sweepService := service.NewSweepService(sweepRepo, expRepo, sweepController)
 	_ = service.NewMLflowService(mlflowEnabled, mlflowURI)
 
 	// Initialize handlers
@@ -51,6 +76,7 @@ func main() {
 	runHandler := handler.NewRunHandler(runService, metricService)
 	metricHandler := handler.NewMetricHandler(metricService)
 	vizHandler := handler.NewVisualizationHandler(vizService)
+	sweepHandler := handler.NewSweepHandler(sweepService)
 
 	// Setup router
 	router := gin.New()
@@ -78,6 +104,8 @@ func main() {
 			experiments.DELETE("/:id", expHandler.DeleteExperiment)
 			experiments.GET("/:id/runs", expHandler.GetExperimentRuns)
 			experiments.GET("/:id/report", vizHandler.GetExperimentReport)
+			experiments.POST("/:id/sweeps", sweepHandler.CreateSweep)
+			experiments.GET("/:id/sweeps", sweepHandler.ListSweeps)
 		}
 
 		// Experiment comparison
@@ -97,6 +125,15 @@ func main() {
 			runs.GET("/:id/accuracy-trend", vizHandler.GetAccuracyTrend)
 		}
 
+		// Sweep routes
+		sweeps := apiV1.Group("/sweeps")
+		{
+			sweeps.GET("/:id", sweepHandler.GetSweep)
+			sweeps.GET("/:id/trials", sweepHandler.ListTrials)
+			sweeps.GET("/:id/best", sweepHandler.GetBestTrial)
+			sweeps.POST("/:id/stop", sweepHandler.StopSweep)
+		}
+
 		// Metric routes
 		metrics := apiV1.Group("/metrics")
 		{
@@ -126,6 +163,15 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
+
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
@@ -342,7 +342,7 @@ type MetricResponse struct {
 type RecordMetricRequest struct {
 	RunID     uuid.UUID              `json:"run_id" binding:"required"`
 	Key       string                 `json:"key" binding:"required"`
-	Value     float64                `json:"value" binding:"required"`
+	Value     float64                `json:"value"`
 	Step      *int64                 `json:"step"`
 	Timestamp *time.Time             `json:"timestamp"`
 	Metadata  map[string]interface{} `json:"metadata"`
@@ -420,11 +420,12 @@ type ReportSummary struct {
 	BestMetrics     map[string]float64 `json:"best_metrics"`
 }
 
-// HyperparameterSearchSpace 超参数搜索空间
+// HyperparameterSearchSpace 超参数搜索空间，任意参数通过 Parameters 描述
 type HyperparameterSearchSpace struct {
-	LearningRate *FloatRange   `json:"learning_rate,omitempty"`
-	BatchSize    []int         `json:"batch_size,omitempty"`
-	Epochs       *IntRange     `json:"epochs,omitempty"`
+	LearningRate *FloatRange               `json:"learning_rate,omitempty"`
+	BatchSize    []int                     `json:"batch_size,omitempty"`
+	Epochs       *IntRange                 `json:"epochs,omitempty"`
+	Parameters   map[string]*ParameterSpec `json:"parameters,omitempty"`
 }
 
 // FloatRange 浮点范围
@@ -0,0 +1,453 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+)
+
+// SweepAlgorithm 超参数搜索算法
+type SweepAlgorithm string
+
+const (
+	SweepAlgorithmGrid     SweepAlgorithm = "grid"
+	SweepAlgorithmRandom   SweepAlgorithm = "random"
+	SweepAlgorithmBayesian SweepAlgorithm = "bayesian" // TPE
+)
+
+// SweepStatus 超参数搜索状态
+type SweepStatus string
+
+const (
+	SweepStatusRunning   SweepStatus = "running"
+	SweepStatusCompleted SweepStatus = "completed"
+	SweepStatusFailed    SweepStatus = "failed"
+	SweepStatusStopped   SweepStatus = "stopped"
+)
+
+// TrialStatus 试验状态
+type TrialStatus string
+
+const (
+	TrialStatusPending   TrialStatus = "pending" // 训练任务已提交，尚未开始运行
+	TrialStatusRunning   TrialStatus = "running"
+	TrialStatusCompleted TrialStatus = "completed"
+	TrialStatusFailed    TrialStatus = "failed"
+	TrialStatusPruned    TrialStatus = "pruned" // 被早停策略提前终止
+	TrialStatusStopped   TrialStatus = "stopped"
+)
+
+// IsActive 试验是否仍在占用并行度
+func (s TrialStatus) IsActive() bool {
+	return s == TrialStatusPending || s == TrialStatusRunning
+}
+
+// ParameterType 超参数类型
+type ParameterType string
+
+const (
+	ParameterTypeFloat       ParameterType = "float"
+	ParameterTypeInt         ParameterType = "int"
+	ParameterTypeCategorical ParameterType = "categorical"
+)
+
+// DefaultGridPoints 未指定步长的浮点参数在网格搜索中的取值个数
+const DefaultGridPoints = 5
+
+// ParameterSpec 单个超参数的搜索范围
+type ParameterSpec struct {
+	Type   ParameterType `json:"type"`
+	Min    float64       `json:"min,omitempty"`
+	Max    float64       `json:"max,omitempty"`
+	Step   float64       `json:"step,omitempty"`   // 网格搜索步长，int 默认为 1
+	Log    bool          `json:"log,omitempty"`    // 在对数尺度上采样，要求 min > 0
+	Values []interface{} `json:"values,omitempty"` // categorical 的候选值
+}
+
+// Validate 校验参数范围
+func (p *ParameterSpec) Validate() error {
+	switch p.Type {
+	case ParameterTypeFloat, ParameterTypeInt:
+		if p.Max < p.Min {
+			return fmt.Errorf("max must not be less than min")
+		}
+		if p.Log && p.Min <= 0 {
+			return fmt.Errorf("log scale requires min > 0")
+		}
+		if p.Step < 0 {
+			return fmt.Errorf("step must not be negative")
+		}
+		if p.Type == ParameterTypeInt && (p.Min != math.Trunc(p.Min) || p.Max != math.Trunc(p.Max)) {
+			return fmt.Errorf("int parameter requires integer bounds")
+		}
+	case ParameterTypeCategorical:
+		if len(p.Values) == 0 {
+			return fmt.Errorf("categorical parameter requires values")
+		}
+	default:
+		return fmt.Errorf("unknown parameter type %q", p.Type)
+	}
+	return nil
+}
+
+// GridValues 参数在网格搜索中的全部取值
+func (p *ParameterSpec) GridValues() []interface{} {
+	switch p.Type {
+	case ParameterTypeCategorical:
+		return p.Values
+	case ParameterTypeInt:
+		step := int(p.Step)
+		if step < 1 {
+			step = 1
+		}
+		var values []interface{}
+		for v := int(p.Min); v <= int(p.Max); v += step {
+			values = append(values, v)
+		}
+		return values
+	default:
+		if p.Min == p.Max {
+			return []interface{}{p.Min}
+		}
+		if p.Step > 0 {
+			var values []interface{}
+			// 加上半个步长的容差，避免浮点累积误差漏掉上界
+			for i := 0; p.Min+float64(i)*p.Step <= p.Max+p.Step/2; i++ {
+				values = append(values, p.Min+float64(i)*p.Step)
+			}
+			return values
+		}
+		values := make([]interface{}, DefaultGridPoints)
+		for i := range values {
+			frac := float64(i) / float64(DefaultGridPoints-1)
+			if p.Log {
+				values[i] = math.Exp(math.Log(p.Min) + frac*(math.Log(p.Max)-math.Log(p.Min)))
+			} else {
+				values[i] = p.Min + frac*(p.Max-p.Min)
+			}
+		}
+		return values
+	}
+}
+
+// Specs 将搜索空间规范化为参数名到范围的映射，兼容 learning_rate、batch_size、epochs 字段
+func (s *HyperparameterSearchSpace) Specs() map[string]*ParameterSpec {
+	specs := make(map[string]*ParameterSpec, len(s.Parameters)+3)
+	if s.LearningRate != nil {
+		specs["learning_rate"] = &ParameterSpec{
+			Type: ParameterTypeFloat,
+			Min:  s.LearningRate.Min,
+			Max:  s.LearningRate.Max,
+			Step: s.LearningRate.Step,
+		}
+	}
+	if len(s.BatchSize) > 0 {
+		values := make([]interface{}, len(s.BatchSize))
+		for i, v := range s.BatchSize {
+			values[i] = v
+		}
+		specs["batch_size"] = &ParameterSpec{Type: ParameterTypeCategorical, Values: values}
+	}
+	if s.Epochs != nil {
+		specs["epochs"] = &ParameterSpec{
+			Type: ParameterTypeInt,
+			Min:  float64(s.Epochs.Min),
+			Max:  float64(s.Epochs.Max),
+			Step: float64(s.Epochs.Step),
+		}
+	}
+	for name, spec := range s.Parameters {
+		specs[name] = spec
+	}
+	return specs
+}
+
+// ObjectiveGoal 优化方向
+type ObjectiveGoal string
+
+const (
+	GoalMinimize ObjectiveGoal = "minimize"
+	GoalMaximize ObjectiveGoal = "maximize"
+)
+
+// SweepObjective 优化目标，取试验运行记录中某个指标的最优值
+type SweepObjective struct {
+	Metric string        `json:"metric" binding:"required"`
+	Goal   ObjectiveGoal `json:"goal" binding:"required,oneof=minimize maximize"`
+}
+
+// Better a 是否优于 b
+func (o SweepObjective) Better(a, b float64) bool {
+	if o.Goal == GoalMaximize {
+		return a > b
+	}
+	return a < b
+}
+
+// EarlyStoppingPolicy 早停策略
+type EarlyStoppingPolicy string
+
+const (
+	EarlyStoppingNone   EarlyStoppingPolicy = "none"
+	EarlyStoppingMedian EarlyStoppingPolicy = "median"
+	EarlyStoppingASHA   EarlyStoppingPolicy = "asha"
+)
+
+// EarlyStoppingConfig 早停配置
+type EarlyStoppingConfig struct {
+	Policy EarlyStoppingPolicy `json:"policy" binding:"omitempty,oneof=none median asha"`
+
+	// median：试验达到 GraceSteps 后，若其截至当前 step 的最优值差于其他试验同一 step 最优值的中位数则终止，
+	// 至少需要 MinTrials 个可比较的试验
+	GraceSteps int64 `json:"grace_steps,omitempty" binding:"omitempty,min=0"`
+	MinTrials  int   `json:"min_trials,omitempty" binding:"omitempty,min=1"`
+
+	// asha：在 MinSteps * ReductionFactor^k（不超过 MaxSteps）的各级检查点上，只保留前 1/ReductionFactor 的试验
+	MinSteps        int64   `json:"min_steps,omitempty" binding:"omitempty,min=1"`
+	MaxSteps        int64   `json:"max_steps,omitempty" binding:"omitempty,min=1"`
+	ReductionFactor float64 `json:"reduction_factor,omitempty" binding:"omitempty,gt=1"`
+}
+
+// ApplyDefaults 补全默认值
+func (c *EarlyStoppingConfig) ApplyDefaults() {
+	if c.Policy == "" {
+		c.Policy = EarlyStoppingNone
+	}
+	if c.MinTrials == 0 {
+		c.MinTrials = 3
+	}
+	if c.MinSteps == 0 {
+		c.MinSteps = 1
+	}
+	if c.ReductionFactor == 0 {
+		c.ReductionFactor = 3
+	}
+}
+
+// TrialJobTemplate 试验训练任务模板，字段与训练服务的创建任务请求一致
+type TrialJobTemplate struct {
+	ProjectID       uuid.UUID              `json:"project_id" binding:"required"`
+	ModelName       string                 `json:"model_name" binding:"required,max=255"`
+	DatasetPath     string                 `json:"dataset_path" binding:"required,max=500"`
+	OutputPath      string                 `json:"output_path" binding:"required,max=450"` // 每个试验追加 /trial-N 子目录
+	Framework       string                 `json:"framework" binding:"required,oneof=pytorch tensorflow other"`
+	Image           string                 `json:"image" binding:"required,max=500"`
+	Command         []string               `json:"command,omitempty"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters,omitempty"` // 固定超参数，被采样值覆盖
+	Environment     map[string]string      `json:"environment,omitempty"`
+	GPUCount        int                    `json:"gpu_count" binding:"min=0,max=8"`
+	GPUType         string                 `json:"gpu_type,omitempty" binding:"max=50"`
+	CPUCount        int                    `json:"cpu_count" binding:"required,min=1,max=64"`
+	MemoryGB        int                    `json:"memory_gb" binding:"required,min=1,max=256"`
+	TimeoutHours    int                    `json:"timeout_hours" binding:"required,min=1,max=168"`
+	Priority        int                    `json:"priority" binding:"min=0,max=100"`
+}
+
+// SweepConfig 超参数搜索配置
+type SweepConfig struct {
+	SearchSpace   HyperparameterSearchSpace `json:"search_space"`
+	Objective     SweepObjective            `json:"objective"`
+	EarlyStopping EarlyStoppingConfig       `json:"early_stopping"`
+	JobTemplate   TrialJobTemplate          `json:"job_template"`
+	Seed          int64                     `json:"seed"`
+}
+
+// Sweep 超参数搜索领域模型
+type Sweep struct {
+	ID           uuid.UUID      `json:"id"`
+	ExperimentID uuid.UUID      `json:"experiment_id"`
+	UserID       uuid.UUID      `json:"user_id"`
+	Name         string         `json:"name"`
+	Algorithm    SweepAlgorithm `json:"algorithm"`
+	Config       SweepConfig    `json:"config"`
+	MaxTrials    int            `json:"max_trials"`
+	Parallelism  int            `json:"parallelism"`
+	Status       SweepStatus    `json:"status"`
+	Message      string         `json:"message"`
+	BestTrialID  *uuid.UUID     `json:"best_trial_id"`
+	BestValue    *float64       `json:"best_value"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	CompletedAt  *time.Time     `json:"completed_at"`
+}
+
+// ToModel 转换为数据库模型
+func (s *Sweep) ToModel() *models.Sweep {
+	return &models.Sweep{
+		ID:           s.ID,
+		ExperimentID: s.ExperimentID,
+		UserID:       s.UserID,
+		Name:         s.Name,
+		Algorithm:    string(s.Algorithm),
+		Config:       toJSON(s.Config),
+		MaxTrials:    s.MaxTrials,
+		Parallelism:  s.Parallelism,
+		Status:       string(s.Status),
+		Message:      s.Message,
+		BestTrialID:  s.BestTrialID,
+		BestValue:    s.BestValue,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		CompletedAt:  s.CompletedAt,
+	}
+}
+
+// FromModel 从数据库模型转换
+func (s *Sweep) FromModel(m *models.Sweep) {
+	s.ID = m.ID
+	s.ExperimentID = m.ExperimentID
+	s.UserID = m.UserID
+	s.Name = m.Name
+	s.Algorithm = SweepAlgorithm(m.Algorithm)
+	fromJSON(m.Config, &s.Config)
+	s.MaxTrials = m.MaxTrials
+	s.Parallelism = m.Parallelism
+	s.Status = SweepStatus(m.Status)
+	s.Message = m.Message
+	s.BestTrialID = m.BestTrialID
+	s.BestValue = m.BestValue
+	s.CreatedAt = m.CreatedAt
+	s.UpdatedAt = m.UpdatedAt
+	s.CompletedAt = m.CompletedAt
+}
+
+// Trial 超参数搜索试验领域模型
+type Trial struct {
+	ID              uuid.UUID              `json:"id"`
+	SweepID         uuid.UUID              `json:"sweep_id"`
+	Number          int                    `json:"number"`
+	RunID           uuid.UUID              `json:"run_id"`
+	TrainingJobID   *uuid.UUID             `json:"training_job_id"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	Status          TrialStatus            `json:"status"`
+	Message         string                 `json:"message,omitempty"`
+	ObjectiveValue  *float64               `json:"objective_value"` // 截至 LastStep 的目标指标最优值
+	LastStep        int64                  `json:"last_step"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	EndedAt         *time.Time             `json:"ended_at"`
+}
+
+// ToModel 转换为数据库模型
+func (t *Trial) ToModel() *models.SweepTrial {
+	return &models.SweepTrial{
+		ID:              t.ID,
+		SweepID:         t.SweepID,
+		Number:          t.Number,
+		RunID:           t.RunID,
+		TrainingJobID:   t.TrainingJobID,
+		Hyperparameters: models.JSON(t.Hyperparameters),
+		Status:          string(t.Status),
+		Message:         t.Message,
+		ObjectiveValue:  t.ObjectiveValue,
+		LastStep:        t.LastStep,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+		EndedAt:         t.EndedAt,
+	}
+}
+
+// FromModel 从数据库模型转换
+func (t *Trial) FromModel(m *models.SweepTrial) {
+	t.ID = m.ID
+	t.SweepID = m.SweepID
+	t.Number = m.Number
+	t.RunID = m.RunID
+	t.TrainingJobID = m.TrainingJobID
+	t.Hyperparameters = m.Hyperparameters
+	t.Status = TrialStatus(m.Status)
+	t.Message = m.Message
+	t.ObjectiveValue = m.ObjectiveValue
+	t.LastStep = m.LastStep
+	t.CreatedAt = m.CreatedAt
+	t.UpdatedAt = m.UpdatedAt
+	t.EndedAt = m.EndedAt
+}
+
+// CreateSweepRequest 创建超参数搜索请求
+type CreateSweepRequest struct {
+	Name          string                    `json:"name" binding:"required,max=255"`
+	Algorithm     SweepAlgorithm            `json:"algorithm" binding:"required,oneof=grid random bayesian"`
+	SearchSpace   HyperparameterSearchSpace `json:"search_space"`
+	Objective     SweepObjective            `json:"objective"`
+	EarlyStopping *EarlyStoppingConfig      `json:"early_stopping"`
+	JobTemplate   TrialJobTemplate          `json:"job_template"`
+	MaxTrials     int                       `json:"max_trials" binding:"omitempty,min=1,max=1000"` // 网格搜索默认为网格大小
+	Parallelism   int                       `json:"parallelism" binding:"omitempty,min=1,max=64"`  // 默认 1
+	Seed          *int64                    `json:"seed"`
+}
+
+// SweepResponse 超参数搜索响应
+type SweepResponse struct {
+	ID            uuid.UUID           `json:"id"`
+	ExperimentID  uuid.UUID           `json:"experiment_id"`
+	Name          string              `json:"name"`
+	Algorithm     SweepAlgorithm      `json:"algorithm"`
+	Objective     SweepObjective      `json:"objective"`
+	EarlyStopping EarlyStoppingConfig `json:"early_stopping"`
+	MaxTrials     int                 `json:"max_trials"`
+	Parallelism   int                 `json:"parallelism"`
+	Status        SweepStatus         `json:"status"`
+	Message       string              `json:"message,omitempty"`
+	TrialCounts   map[TrialStatus]int `json:"trial_counts"`
+	BestTrial     *Trial              `json:"best_trial,omitempty"`
+	BestValue     *float64            `json:"best_value,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	CompletedAt   *time.Time          `json:"completed_at,omitempty"`
+}
+
+// ToResponse 转换为响应，trials 为该搜索的全部试验
+func (s *Sweep) ToResponse(trials []*Trial) *SweepResponse {
+	resp := &SweepResponse{
+		ID:            s.ID,
+		ExperimentID:  s.ExperimentID,
+		Name:          s.Name,
+		Algorithm:     s.Algorithm,
+		Objective:     s.Config.Objective,
+		EarlyStopping: s.Config.EarlyStopping,
+		MaxTrials:     s.MaxTrials,
+		Parallelism:   s.Parallelism,
+		Status:        s.Status,
+		Message:       s.Message,
+		TrialCounts:   make(map[TrialStatus]int),
+		BestValue:     s.BestValue,
+		CreatedAt:     s.CreatedAt,
+		UpdatedAt:     s.UpdatedAt,
+		CompletedAt:   s.CompletedAt,
+	}
+	for _, t := range trials {
+		resp.TrialCounts[t.Status]++
+		if s.BestTrialID != nil && t.ID == *s.BestTrialID {
+			resp.BestTrial = t
+		}
+	}
+	return resp
+}
+
+// toJSON 通过 JSON 编码将结构体转换为 models.JSON
+func toJSON(v interface{}) models.JSON {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return models.JSON{}
+	}
+	m := models.JSON{}
+	_ = json.Unmarshal(data, &m)
+	return m
+}
+
+// fromJSON 将 models.JSON 解码到结构体
+func fromJSON(m models.JSON, v interface{}) {
+	if m == nil {
+		return
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, v)
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/service"
+	"go.uber.org/zap"
+)
+
+// SweepHandler 超参数搜索处理器
+type SweepHandler struct {
+	sweepService service.SweepService
+}
+
+// NewSweepHandler 创建超参数搜索处理器
+func NewSweepHandler(sweepService service.SweepService) *SweepHandler {
+	return &SweepHandler{sweepService: sweepService}
+}
+
+// CreateSweep 创建超参数搜索
+// POST /api/v1/experiments/:id/sweeps
+func (h *SweepHandler) CreateSweep(c *gin.Context) {
+	experimentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid experiment id")
+		return
+	}
+
+	var req domain.CreateSweepRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Log.Warn("Invalid create sweep request", zap.Error(err))
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	uid, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	sweep, err := h.sweepService.CreateSweep(c.Request.Context(), uid, experimentID, &req)
+	if err != nil {
+		status := service.MapServiceError(err)
+		response.Error(c, status, err.Error())
+		return
+	}
+
+	response.Created(c, sweep)
+}
+
+// ListSweeps 列出实验的超参数搜索
+// GET /api/v1/experiments/:id/sweeps
+func (h *SweepHandler) ListSweeps(c *gin.Context) {
+	experimentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid experiment id")
+		return
+	}
+
+	sweeps, err := h.sweepService.ListSweeps(c.Request.Context(), experimentID)
+	if err != nil {
+		status := service.MapServiceError(err)
+		response.Error(c, status, err.Error())
+		return
+	}
+
+	response.Success(c, sweeps)
+}
+
+// GetSweep 获取超参数搜索状态及最优试验
+// GET /api/v1/sweeps/:id
+func (h *SweepHandler) GetSweep(c *gin.Context) {
+	id, ok := parseSweepID(c)
+	if !ok {
+		return
+	}
+
+	sweep, err := h.sweepService.GetSweep(c.Request.Context(), id)
+	if err != nil {
+		status := service.MapServiceError(err)
+		response.Error(c, status, err.Error())
+		return
+	}
+
+	response.Success(c, sweep)
+}
+
+// ListTrials 列出超参数搜索的全部试验
+// GET /api/v1/sweeps/:id/trials
+func (h *SweepHandler) ListTrials(c *gin.Context) {
+	id, ok := parseSweepID(c)
+	if !ok {
+		return
+	}
+
+	trials, err := h.sweepService.ListTrials(c.Request.Context(), id)
+	if err != nil {
+		status := service.MapServiceError(err)
+		response.Error(c, status, err.Error())
+		return
+	}
+
+	response.Success(c, trials)
+}
+
+// GetBestTrial 获取目标值最优的试验
+// GET /api/v1/sweeps/:id/best
+func (h *SweepHandler) GetBestTrial(c *gin.Context) {
+	id, ok := parseSweepID(c)
+	if !ok {
+		return
+	}
+
+	trial, err := h.sweepService.GetBestTrial(c.Request.Context(), id)
+	if err != nil {
+		status := service.MapServiceError(err)
+		response.Error(c, status, err.Error())
+		return
+	}
+
+	response.Success(c, trial)
+}
+
+// StopSweep 停止超参数搜索及其进行中的试验
+// POST /api/v1/sweeps/:id/stop
+func (h *SweepHandler) StopSweep(c *gin.Context) {
+	id, ok := parseSweepID(c)
+	if !ok {
+		return
+	}
+
+	sweep, err := h.sweepService.StopSweep(c.Request.Context(), id)
+	if err != nil {
+		status := service.MapServiceError(err)
+		response.Error(c, status, err.Error())
+		return
+	}
+
+	response.Success(c, sweep)
+}
+
+// parseSweepID 解析路径中的搜索 ID
+func parseSweepID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid sweep id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// currentUserID 获取当前用户 ID，优先使用上下文，其次使用网关转发的 X-User-ID 头
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID := c.GetHeader("X-User-ID")
+	if v, exists := c.Get("user_id"); exists {
+		userID, _ = v.(string)
+	}
+	if userID == "" {
+		response.Error(c, http.StatusUnauthorized, "user not authenticated")
+		return uuid.Nil, false
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "invalid user id")
+		return uuid.Nil, false
+	}
+	return uid, true
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/domain"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrSweepNotFound = errors.New("sweep not found")
+
+// SweepRepository 超参数搜索仓库接口
+type SweepRepository interface {
+	AutoMigrate() error
+	Create(ctx context.Context, sweep *domain.Sweep) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Sweep, error)
+	ListByExperiment(ctx context.Context, experimentID uuid.UUID) ([]*domain.Sweep, error)
+	ListByStatus(ctx context.Context, status domain.SweepStatus) ([]*domain.Sweep, error)
+	Update(ctx context.Context, sweep *domain.Sweep) error
+
+	CreateTrial(ctx context.Context, trial *domain.Trial) error
+	UpdateTrial(ctx context.Context, trial *domain.Trial) error
+	ListTrials(ctx context.Context, sweepID uuid.UUID) ([]*domain.Trial, error)
+}
+
+// sweepRepository 超参数搜索仓库实现
+type sweepRepository struct {
+	db *gorm.DB
+}
+
+// NewSweepRepository 创建超参数搜索仓库
+func NewSweepRepository(db *gorm.DB) SweepRepository {
+	return &sweepRepository{db: db}
+}
+
+// AutoMigrate 迁移超参数搜索相关的表
+func (r *sweepRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&models.Sweep{}, &models.SweepTrial{})
+}
+
+func (r *sweepRepository) Create(ctx context.Context, sweep *domain.Sweep) error {
+	model := sweep.ToModel()
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		logger.Log.Error("Failed to create sweep", zap.Error(err))
+		return err
+	}
+
+	sweep.ID = model.ID
+	sweep.CreatedAt = model.CreatedAt
+	sweep.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *sweepRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Sweep, error) {
+	var model models.Sweep
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSweepNotFound
+		}
+		logger.Log.Error("Failed to get sweep", zap.String("id", id.String()), zap.Error(err))
+		return nil, err
+	}
+
+	sweep := &domain.Sweep{}
+	sweep.FromModel(&model)
+	return sweep, nil
+}
+
+func (r *sweepRepository) ListByExperiment(ctx context.Context, experimentID uuid.UUID) ([]*domain.Sweep, error) {
+	var models []models.Sweep
+	if err := r.db.WithContext(ctx).Where("experiment_id = ?", experimentID).
+		Order("created_at DESC").Find(&models).Error; err != nil {
+		logger.Log.Error("Failed to list sweeps", zap.String("experiment_id", experimentID.String()), zap.Error(err))
+		return nil, err
+	}
+
+	return sweepsFromModels(models), nil
+}
+
+func (r *sweepRepository) ListByStatus(ctx context.Context, status domain.SweepStatus) ([]*domain.Sweep, error) {
+	var models []models.Sweep
+	if err := r.db.WithContext(ctx).Where("status = ?", string(status)).
+		Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return sweepsFromModels(models), nil
+}
+
+func (r *sweepRepository) Update(ctx context.Context, sweep *domain.Sweep) error {
+	result := r.db.WithContext(ctx).Model(&models.Sweep{}).Where("id = ?", sweep.ID).Updates(map[string]interface{}{
+		"status":        string(sweep.Status),
+		"message":       sweep.Message,
+		"best_trial_id": sweep.BestTrialID,
+		"best_value":    sweep.BestValue,
+		"completed_at":  sweep.CompletedAt,
+		"updated_at":    time.Now(),
+	})
+	if result.Error != nil {
+		logger.Log.Error("Failed to update sweep", zap.String("id", sweep.ID.String()), zap.Error(result.Error))
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSweepNotFound
+	}
+
+	return nil
+}
+
+func (r *sweepRepository) CreateTrial(ctx context.Context, trial *domain.Trial) error {
+	model := trial.ToModel()
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		logger.Log.Error("Failed to create sweep trial", zap.String("sweep_id", trial.SweepID.String()), zap.Error(err))
+		return err
+	}
+
+	trial.ID = model.ID
+	trial.CreatedAt = model.CreatedAt
+	trial.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *sweepRepository) UpdateTrial(ctx context.Context, trial *domain.Trial) error {
+	return r.db.WithContext(ctx).Model(&models.SweepTrial{}).Where("id = ?", trial.ID).Updates(map[string]interface{}{
+		"training_job_id": trial.TrainingJobID,
+		"status":          string(trial.Status),
+		"message":         trial.Message,
+		"objective_value": trial.ObjectiveValue,
+		"last_step":       trial.LastStep,
+		"ended_at":        trial.EndedAt,
+		"updated_at":      time.Now(),
+	}).Error
+}
+
+func (r *sweepRepository) ListTrials(ctx context.Context, sweepID uuid.UUID) ([]*domain.Trial, error) {
+	var models []models.SweepTrial
+	if err := r.db.WithContext(ctx).Where("sweep_id = ?", sweepID).
+		Order("number ASC").Find(&models).Error; err != nil {
+		logger.Log.Error("Failed to list sweep trials", zap.String("sweep_id", sweepID.String()), zap.Error(err))
+		return nil, err
+	}
+
+	trials := make([]*domain.Trial, len(models))
+	for i := range models {
+		trial := &domain.Trial{}
+		trial.FromModel(&models[i])
+		trials[i] = trial
+	}
+
+	return trials, nil
+}
+
+// sweepsFromModels 批量转换数据库模型
+func sweepsFromModels(models []models.Sweep) []*domain.Sweep {
+	sweeps := make([]*domain.Sweep, len(models))
+	for i := range models {
+		sweep := &domain.Sweep{}
+		sweep.FromModel(&models[i])
+		sweeps[i] = sweep
+	}
+	return sweeps
+}
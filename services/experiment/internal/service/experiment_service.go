@@ -758,6 +758,8 @@ func MapServiceError(err error) int {
 		return http.StatusNotFound
 	case errors.Is(err, ErrMetricNotFound):
 		return http.StatusNotFound
+	case errors.Is(err, ErrSweepNotFound), errors.Is(err, ErrNoBestTrial):
+		return http.StatusNotFound
 	case errors.Is(err, ErrInvalidInput):
 		return http.StatusBadRequest
 	case errors.Is(err, ErrUnauthorized):
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/repository"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/sweep"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSweepNotFound = errors.New("sweep not found")
+	ErrNoBestTrial   = errors.New("sweep has no completed trial with an objective value yet")
+)
+
+const (
+	// maxGridSize 网格搜索允许的最大组合数
+	maxGridSize = 10000
+	// defaultSweepTrials 随机和贝叶斯搜索的默认试验数
+	defaultSweepTrials = 20
+)
+
+// SweepService 超参数搜索服务接口
+type SweepService interface {
+	CreateSweep(ctx context.Context, userID, experimentID uuid.UUID, req *domain.CreateSweepRequest) (*domain.SweepResponse, error)
+	GetSweep(ctx context.Context, id uuid.UUID) (*domain.SweepResponse, error)
+	ListSweeps(ctx context.Context, experimentID uuid.UUID) ([]*domain.SweepResponse, error)
+	ListTrials(ctx context.Context, id uuid.UUID) ([]*domain.Trial, error)
+	GetBestTrial(ctx context.Context, id uuid.UUID) (*domain.Trial, error)
+	StopSweep(ctx context.Context, id uuid.UUID) (*domain.SweepResponse, error)
+}
+
+// sweepService 超参数搜索服务实现
+type sweepService struct {
+	sweepRepo  repository.SweepRepository
+	expRepo    repository.ExperimentRepository
+	controller *sweep.Controller
+}
+
+// NewSweepService 创建超参数搜索服务
+func NewSweepService(
+	sweepRepo repository.SweepRepository,
+	expRepo repository.ExperimentRepository,
+	controller *sweep.Controller,
+) SweepService {
+	return &sweepService{
+		sweepRepo:  sweepRepo,
+		expRepo:    expRepo,
+		controller: controller,
+	}
+}
+
+func (s *sweepService) CreateSweep(ctx context.Context, userID, experimentID uuid.UUID, req *domain.CreateSweepRequest) (*domain.SweepResponse, error) {
+	logger.Log.Info("Creating sweep",
+		zap.String("experiment_id", experimentID.String()),
+		zap.String("name", req.Name),
+		zap.String("algorithm", string(req.Algorithm)),
+	)
+
+	if _, err := s.expRepo.GetByID(ctx, experimentID); err != nil {
+		if errors.Is(err, repository.ErrExperimentNotFound) {
+			return nil, ErrExperimentNotFound
+		}
+		return nil, err
+	}
+
+	specs := req.SearchSpace.Specs()
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%w: search space has no parameters", ErrInvalidInput)
+	}
+	for name, spec := range specs {
+		if spec == nil {
+			return nil, fmt.Errorf("%w: parameter %s has no spec", ErrInvalidInput, name)
+		}
+		if err := spec.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: parameter %s: %v", ErrInvalidInput, name, err)
+		}
+	}
+
+	maxTrials := req.MaxTrials
+	if req.Algorithm == domain.SweepAlgorithmGrid {
+		size := sweep.GridSize(specs)
+		if size > maxGridSize {
+			return nil, fmt.Errorf("%w: grid has %d combinations, at most %d allowed", ErrInvalidInput, size, maxGridSize)
+		}
+		if maxTrials == 0 || maxTrials > size {
+			maxTrials = size
+		}
+	} else if maxTrials == 0 {
+		maxTrials = defaultSweepTrials
+	}
+
+	parallelism := req.Parallelism
+	if parallelism == 0 {
+		parallelism = 1
+	}
+	if parallelism > maxTrials {
+		parallelism = maxTrials
+	}
+
+	earlyStopping := domain.EarlyStoppingConfig{}
+	if req.EarlyStopping != nil {
+		earlyStopping = *req.EarlyStopping
+	}
+	earlyStopping.ApplyDefaults()
+	if earlyStopping.MaxSteps > 0 && earlyStopping.MaxSteps < earlyStopping.MinSteps {
+		return nil, fmt.Errorf("%w: early_stopping.max_steps must not be less than min_steps", ErrInvalidInput)
+	}
+
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	now := time.Now()
+	sw := &domain.Sweep{
+		ID:           uuid.New(),
+		ExperimentID: experimentID,
+		UserID:       userID,
+		Name:         req.Name,
+		Algorithm:    req.Algorithm,
+		Config: domain.SweepConfig{
+			SearchSpace:   req.SearchSpace,
+			Objective:     req.Objective,
+			EarlyStopping: earlyStopping,
+			JobTemplate:   req.JobTemplate,
+			Seed:          seed,
+		},
+		MaxTrials:   maxTrials,
+		Parallelism: parallelism,
+		Status:      domain.SweepStatusRunning,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.sweepRepo.Create(ctx, sw); err != nil {
+		logger.Log.Error("Failed to create sweep", zap.Error(err))
+		return nil, err
+	}
+
+	// 立即启动第一批试验，不等待下一个控制周期
+	s.controller.Notify()
+
+	logger.Log.Info("Sweep created", zap.String("id", sw.ID.String()), zap.Int("max_trials", maxTrials))
+	return sw.ToResponse(nil), nil
+}
+
+func (s *sweepService) GetSweep(ctx context.Context, id uuid.UUID) (*domain.SweepResponse, error) {
+	sw, err := s.getSweep(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	trials, err := s.sweepRepo.ListTrials(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sw.ToResponse(trials), nil
+}
+
+func (s *sweepService) ListSweeps(ctx context.Context, experimentID uuid.UUID) ([]*domain.SweepResponse, error) {
+	sweeps, err := s.sweepRepo.ListByExperiment(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*domain.SweepResponse, len(sweeps))
+	for i, sw := range sweeps {
+		trials, err := s.sweepRepo.ListTrials(ctx, sw.ID)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = sw.ToResponse(trials)
+	}
+
+	return responses, nil
+}
+
+func (s *sweepService) ListTrials(ctx context.Context, id uuid.UUID) ([]*domain.Trial, error) {
+	if _, err := s.getSweep(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.sweepRepo.ListTrials(ctx, id)
+}
+
+func (s *sweepService) GetBestTrial(ctx context.Context, id uuid.UUID) (*domain.Trial, error) {
+	resp, err := s.GetSweep(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.BestTrial == nil {
+		return nil, ErrNoBestTrial
+	}
+
+	return resp.BestTrial, nil
+}
+
+func (s *sweepService) StopSweep(ctx context.Context, id uuid.UUID) (*domain.SweepResponse, error) {
+	logger.Log.Info("Stopping sweep", zap.String("id", id.String()))
+
+	sw, err := s.getSweep(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if sw.Status != domain.SweepStatusRunning {
+		return nil, fmt.Errorf("%w: sweep is already %s", ErrInvalidInput, sw.Status)
+	}
+
+	if err := s.controller.StopSweep(ctx, sw); err != nil {
+		logger.Log.Error("Failed to stop sweep", zap.String("id", id.String()), zap.Error(err))
+		return nil, err
+	}
+
+	return s.GetSweep(ctx, id)
+}
+
+// getSweep 获取搜索并转换仓库错误
+func (s *sweepService) getSweep(ctx context.Context, id uuid.UUID) (*domain.Sweep, error) {
+	sw, err := s.sweepRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSweepNotFound) {
+			return nil, ErrSweepNotFound
+		}
+		return nil, err
+	}
+	return sw, nil
+}
@@ -0,0 +1,528 @@
+package sweep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Controller 超参数搜索控制器
+//
+// 周期性推进所有运行中的搜索：同步试验对应训练任务的状态、读取目标指标、
+// 按早停策略终止表现差的试验、更新最优试验，并在并行度允许时启动新的试验。
+// 所有状态都保存在数据库中，服务重启后会继续推进未完成的搜索。
+type Controller struct {
+	sweepRepo  repository.SweepRepository
+	runRepo    repository.RunRepository
+	expRepo    repository.ExperimentRepository
+	metricRepo repository.MetricRepository
+	training   TrainingClient
+	interval   time.Duration
+	events     events.Publisher
+
+	// mu 串行化控制循环与外部的停止操作
+	mu sync.Mutex
+	// finished 按搜索缓存已结束试验的学习曲线，结束后指标不再变化
+	finished  map[uuid.UUID]map[uuid.UUID]*curve
+	ashaRungs map[uuid.UUID]map[uuid.UUID]int64
+
+	notify chan struct{}
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewController 创建超参数搜索控制器
+func NewController(
+	sweepRepo repository.SweepRepository,
+	runRepo repository.RunRepository,
+	expRepo repository.ExperimentRepository,
+	metricRepo repository.MetricRepository,
+	training TrainingClient,
+	interval time.Duration,
+) *Controller {
+	return &Controller{
+		sweepRepo:  sweepRepo,
+		runRepo:    runRepo,
+		expRepo:    expRepo,
+		metricRepo: metricRepo,
+		training:   training,
+		interval:   interval,
+		finished:   make(map[uuid.UUID]map[uuid.UUID]*curve),
+		ashaRungs:  make(map[uuid.UUID]map[uuid.UUID]int64),
+		notify:     make(chan struct{}, 1),
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start 启动控制循环
+func (c *Controller) Start() {
+	c.wg.Add(1)
+	go c.loop()
+	logger.Log.Info("Sweep controller started", zap.Duration("interval", c.interval))
+}
+
+// Stop 停止控制循环，已启动的训练任务不受影响
+func (c *Controller) Stop() {
+	close(c.stopCh)
+	c.wg.Wait()
+}
+
+// Notify 立即触发一轮推进（例如新建搜索后）
+func (c *Controller) Notify() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Controller) loop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.tick()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.tick()
+		case <-c.notify:
+			c.tick()
+		}
+	}
+}
+
+// tick 推进所有运行中的搜索
+func (c *Controller) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.interval*4)
+	defer cancel()
+
+	sweeps, err := c.sweepRepo.ListByStatus(ctx, domain.SweepStatusRunning)
+	if err != nil {
+		logger.Log.Error("Failed to list running sweeps", zap.Error(err))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, sweep := range sweeps {
+		if err := c.advance(ctx, sweep); err != nil {
+			logger.Log.Error("Failed to advance sweep", zap.String("sweep_id", sweep.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// advance 推进单个搜索
+func (c *Controller) advance(ctx context.Context, sweep *domain.Sweep) error {
+	// 停止操作可能在本轮列出搜索之后发生
+	current, err := c.sweepRepo.GetByID(ctx, sweep.ID)
+	if err != nil {
+		return err
+	}
+	if current.Status != domain.SweepStatusRunning {
+		return nil
+	}
+	sweep = current
+
+	trials, err := c.sweepRepo.ListTrials(ctx, sweep.ID)
+	if err != nil {
+		return err
+	}
+
+	curves := make(map[uuid.UUID]*curve, len(trials))
+	for _, trial := range trials {
+		curves[trial.ID] = c.curveOf(ctx, sweep, trial)
+		if trial.Status.IsActive() {
+			c.refreshTrial(ctx, sweep, trial, curves[trial.ID])
+		}
+	}
+
+	c.applyEarlyStopping(ctx, sweep, trials, curves)
+	c.updateBest(sweep, trials)
+
+	active := 0
+	for _, trial := range trials {
+		if trial.Status.IsActive() {
+			active++
+		}
+	}
+
+	exhausted := false
+	sampler := NewSampler(sweep)
+	for active < sweep.Parallelism && len(trials) < sweep.MaxTrials {
+		params, ok := sampler.Next(len(trials), trials)
+		if !ok {
+			exhausted = true
+			break
+		}
+
+		trial, err := c.launchTrial(ctx, sweep, len(trials), params)
+		if trial != nil {
+			trials = append(trials, trial)
+		}
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.IsClientError() {
+				// 任务模板被训练服务拒绝，重试也不会成功
+				c.finish(sweep, domain.SweepStatusFailed, fmt.Sprintf("failed to launch trial %d: %v", len(trials)-1, err))
+				return c.sweepRepo.Update(ctx, sweep)
+			}
+			// 训练服务暂不可用等情况，下一轮重试
+			logger.Log.Warn("Failed to launch sweep trial", zap.String("sweep_id", sweep.ID.String()), zap.Error(err))
+			break
+		}
+		active++
+	}
+
+	if active == 0 && (exhausted || len(trials) >= sweep.MaxTrials) {
+		c.finish(sweep, domain.SweepStatusCompleted, fmt.Sprintf("%d trials finished", len(trials)))
+		logger.Log.Info("Sweep completed", zap.String("sweep_id", sweep.ID.String()), zap.Int("trials", len(trials)))
+	}
+
+	return c.sweepRepo.Update(ctx, sweep)
+}
+
+// curveOf 读取试验目标指标的学习曲线，已结束的试验使用缓存
+func (c *Controller) curveOf(ctx context.Context, sweep *domain.Sweep, trial *domain.Trial) *curve {
+	cache, ok := c.finished[sweep.ID]
+	if !ok {
+		cache = make(map[uuid.UUID]*curve)
+		c.finished[sweep.ID] = cache
+	}
+	if cached, ok := cache[trial.ID]; ok {
+		return cached
+	}
+
+	metrics, err := c.metricRepo.GetByRunIDAndKey(ctx, trial.RunID, sweep.Config.Objective.Metric)
+	if err != nil {
+		return &curve{}
+	}
+	result := newCurve(metrics, sweep.Config.Objective)
+	if !trial.Status.IsActive() {
+		cache[trial.ID] = result
+	}
+	return result
+}
+
+// refreshTrial 同步试验的训练任务状态和目标值
+func (c *Controller) refreshTrial(ctx context.Context, sweep *domain.Sweep, trial *domain.Trial, cv *curve) {
+	if !cv.empty() {
+		value := cv.final()
+		trial.ObjectiveValue = &value
+		trial.LastStep = cv.lastStep()
+	}
+
+	if trial.TrainingJobID == nil {
+		return
+	}
+
+	job, err := c.training.GetJob(ctx, sweep.UserID, *trial.TrainingJobID)
+	if err != nil {
+		logger.Log.Warn("Failed to get trial job status",
+			zap.String("sweep_id", sweep.ID.String()),
+			zap.Int("trial", trial.Number),
+			zap.Error(err),
+		)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.IsClientError() {
+			// 任务已被删除或不再可见，重试也不会成功，试验按失败结束以释放并行名额
+			c.endTrial(ctx, sweep, trial, domain.TrialStatusFailed, fmt.Sprintf("failed to get training job: %v", err))
+		}
+		// 仍然保存最新的目标值
+		if err := c.sweepRepo.UpdateTrial(ctx, trial); err != nil {
+			logger.Log.Error("Failed to update sweep trial", zap.String("trial_id", trial.ID.String()), zap.Error(err))
+		}
+		return
+	}
+
+	switch job.Status {
+	case JobStatusRunning, JobStatusStopping:
+		if trial.Status == domain.TrialStatusPending {
+			trial.Status = domain.TrialStatusRunning
+			c.updateRunStatus(ctx, trial.RunID, "running")
+		}
+	case JobStatusCompleted:
+		c.endTrial(ctx, sweep, trial, domain.TrialStatusCompleted, "")
+	case JobStatusFailed:
+		c.endTrial(ctx, sweep, trial, domain.TrialStatusFailed, job.StatusMessage)
+	case JobStatusCancelled:
+		c.endTrial(ctx, sweep, trial, domain.TrialStatusStopped, job.StatusMessage)
+	}
+
+	if err := c.sweepRepo.UpdateTrial(ctx, trial); err != nil {
+		logger.Log.Error("Failed to update sweep trial", zap.String("trial_id", trial.ID.String()), zap.Error(err))
+	}
+}
+
+// applyEarlyStopping 按早停策略终止表现差的运行中试验
+func (c *Controller) applyEarlyStopping(ctx context.Context, sweep *domain.Sweep, trials []*domain.Trial, curves map[uuid.UUID]*curve) {
+	if sweep.Config.EarlyStopping.Policy == "" || sweep.Config.EarlyStopping.Policy == domain.EarlyStoppingNone {
+		return
+	}
+
+	rungs, ok := c.ashaRungs[sweep.ID]
+	if !ok {
+		rungs = make(map[uuid.UUID]int64)
+		c.ashaRungs[sweep.ID] = rungs
+	}
+	s := newStopper(sweep.Config.EarlyStopping, sweep.Config.Objective, rungs)
+
+	for _, trial := range trials {
+		if trial.Status != domain.TrialStatusRunning {
+			continue
+		}
+		reason, stop := s.shouldStop(trial, curves)
+		if !stop {
+			continue
+		}
+
+		logger.Log.Info("Pruning sweep trial",
+			zap.String("sweep_id", sweep.ID.String()),
+			zap.Int("trial", trial.Number),
+			zap.String("reason", reason),
+		)
+		c.stopTrialJob(ctx, sweep, trial)
+		c.endTrial(ctx, sweep, trial, domain.TrialStatusPruned, reason)
+		if err := c.sweepRepo.UpdateTrial(ctx, trial); err != nil {
+			logger.Log.Error("Failed to update sweep trial", zap.String("trial_id", trial.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// updateBest 在完成的试验中选出目标值最优者
+func (c *Controller) updateBest(sweep *domain.Sweep, trials []*domain.Trial) {
+	objective := sweep.Config.Objective
+	for _, trial := range trials {
+		if trial.Status != domain.TrialStatusCompleted || trial.ObjectiveValue == nil {
+			continue
+		}
+		if sweep.BestValue == nil || objective.Better(*trial.ObjectiveValue, *sweep.BestValue) {
+			value := *trial.ObjectiveValue
+			id := trial.ID
+			sweep.BestValue = &value
+			sweep.BestTrialID = &id
+		}
+	}
+}
+
+// launchTrial 创建试验的运行记录并提交训练任务
+//
+// 试验记录先于训练任务创建，提交失败时试验标记为 failed 并返回错误。
+func (c *Controller) launchTrial(ctx context.Context, sweep *domain.Sweep, number int, sampled map[string]interface{}) (*domain.Trial, error) {
+	tmpl := sweep.Config.JobTemplate
+
+	hyperparameters := make(map[string]interface{}, len(tmpl.Hyperparameters)+len(sampled))
+	for key, value := range tmpl.Hyperparameters {
+		hyperparameters[key] = value
+	}
+	for key, value := range sampled {
+		hyperparameters[key] = value
+	}
+
+	environment := make(map[string]string, len(tmpl.Environment)+2)
+	for key, value := range tmpl.Environment {
+		environment[key] = value
+	}
+	environment["AITIP_SWEEP_ID"] = sweep.ID.String()
+	environment["AITIP_TRIAL_NUMBER"] = fmt.Sprintf("%d", number)
+
+	now := time.Now()
+	run := &domain.Run{
+		ID:           uuid.New(),
+		ExperimentID: sweep.ExperimentID,
+		RunType:      "training",
+		Status:       "pending",
+		Config: domain.RunConfig{
+			Hyperparameters: hyperparameters,
+			Environment:     environment,
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := c.runRepo.Create(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create trial run: %w", err)
+	}
+	if err := c.expRepo.UpdateRunsCount(ctx, sweep.ExperimentID); err != nil {
+		logger.Log.Warn("Failed to update runs count", zap.Error(err))
+	}
+
+	trial := &domain.Trial{
+		ID:              uuid.New(),
+		SweepID:         sweep.ID,
+		Number:          number,
+		RunID:           run.ID,
+		Hyperparameters: sampled,
+		Status:          domain.TrialStatusPending,
+	}
+	if err := c.sweepRepo.CreateTrial(ctx, trial); err != nil {
+		return nil, fmt.Errorf("failed to create trial: %w", err)
+	}
+
+	job, err := c.training.CreateJob(ctx, sweep.UserID, &CreateJobRequest{
+		Name:            fmt.Sprintf("%s-trial-%d", sweep.Name, number),
+		Description:     fmt.Sprintf("Trial %d of sweep %s", number, sweep.ID),
+		ProjectID:       tmpl.ProjectID.String(),
+		ExperimentID:    sweep.ExperimentID.String(),
+		RunID:           run.ID.String(),
+		ModelName:       tmpl.ModelName,
+		DatasetPath:     tmpl.DatasetPath,
+		OutputPath:      path.Join(tmpl.OutputPath, fmt.Sprintf("trial-%d", number)),
+		Framework:       tmpl.Framework,
+		Image:           tmpl.Image,
+		Command:         tmpl.Command,
+		Hyperparameters: hyperparameters,
+		Environment:     environment,
+		GPUCount:        tmpl.GPUCount,
+		GPUType:         tmpl.GPUType,
+		CPUCount:        tmpl.CPUCount,
+		MemoryGB:        tmpl.MemoryGB,
+		TimeoutHours:    tmpl.TimeoutHours,
+		Priority:        tmpl.Priority,
+	})
+	if err != nil {
+		c.endTrial(ctx, sweep, trial, domain.TrialStatusFailed, err.Error())
+		_ = c.sweepRepo.UpdateTrial(ctx, trial)
+		return trial, err
+	}
+
+	trial.TrainingJobID = &job.ID
+	if err := c.sweepRepo.UpdateTrial(ctx, trial); err != nil {
+		logger.Log.Error("Failed to record trial job", zap.String("trial_id", trial.ID.String()), zap.Error(err))
+	}
+
+	logger.Log.Info("Sweep trial launched",
+		zap.String("sweep_id", sweep.ID.String()),
+		zap.Int("trial", number),
+		zap.String("job_id", job.ID.String()),
+		zap.Any("hyperparameters", sampled),
+	)
+	return trial, nil
+}
+
+// StopSweep 停止搜索及其所有进行中的试验
+func (c *Controller) StopSweep(ctx context.Context, sweep *domain.Sweep) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	trials, err := c.sweepRepo.ListTrials(ctx, sweep.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, trial := range trials {
+		if !trial.Status.IsActive() {
+			continue
+		}
+		c.stopTrialJob(ctx, sweep, trial)
+		c.endTrial(ctx, sweep, trial, domain.TrialStatusStopped, "sweep stopped")
+		if err := c.sweepRepo.UpdateTrial(ctx, trial); err != nil {
+			logger.Log.Error("Failed to update sweep trial", zap.String("trial_id", trial.ID.String()), zap.Error(err))
+		}
+	}
+
+	c.updateBest(sweep, trials)
+	c.finish(sweep, domain.SweepStatusStopped, "stopped by user")
+	return c.sweepRepo.Update(ctx, sweep)
+}
+
+// stopTrialJob 停止试验的训练任务，失败时只记录日志
+func (c *Controller) stopTrialJob(ctx context.Context, sweep *domain.Sweep, trial *domain.Trial) {
+	if trial.TrainingJobID == nil {
+		return
+	}
+	if err := c.training.StopJob(ctx, sweep.UserID, *trial.TrainingJobID); err != nil {
+		logger.Log.Warn("Failed to stop trial job",
+			zap.String("sweep_id", sweep.ID.String()),
+			zap.Int("trial", trial.Number),
+			zap.Error(err),
+		)
+	}
+}
+
+// endTrial 将试验置为结束状态并同步运行记录
+func (c *Controller) endTrial(ctx context.Context, sweep *domain.Sweep, trial *domain.Trial, status domain.TrialStatus, message string) {
+	now := time.Now()
+	trial.Status = status
+	trial.Message = message
+	trial.EndedAt = &now
+
+	runStatus := string(status)
+	if status == domain.TrialStatusPruned {
+		runStatus = "stopped"
+	}
+	c.updateRunStatus(ctx, trial.RunID, runStatus)
+
+	if trial.ObjectiveValue != nil {
+		summary := map[string]float64{sweep.Config.Objective.Metric: *trial.ObjectiveValue}
+		if err := c.runRepo.UpdateMetricsSummary(ctx, trial.RunID, summary); err != nil {
+			logger.Log.Warn("Failed to update trial run summary", zap.String("run_id", trial.RunID.String()), zap.Error(err))
+		}
+	}
+}
+
+// updateRunStatus 更新试验运行记录状态，失败时只记录日志
+func (c *Controller) updateRunStatus(ctx context.Context, runID uuid.UUID, status string) {
+	if err := c.runRepo.UpdateStatus(ctx, runID, status); err != nil {
+		logger.Log.Warn("Failed to update trial run status", zap.String("run_id", runID.String()), zap.Error(err))
+	}
+}
+
+// finish 将搜索置为结束状态
+func (c *Controller) finish(sweep *domain.Sweep, status domain.SweepStatus, message string) {
+	now := time.Now()
+	sweep.Status = status
+	sweep.Message = message
+	sweep.CompletedAt = &now
+	delete(c.finished, sweep.ID)
+	delete(c.ashaRungs, sweep.ID)
+	c.publish(sweep)
+}
+
+// SetEventPublisher 设置事件发布器，搜索结束时发布 experiment.sweep.<status> 事件
+func (c *Controller) SetEventPublisher(publisher events.Publisher) {
+	c.events = publisher
+}
+
+// publish 发布搜索结束事件，失败时只记录日志
+func (c *Controller) publish(sweep *domain.Sweep) {
+	if c.events == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	data := map[string]interface{}{
+		"experiment_id": sweep.ExperimentID,
+		"name":          sweep.Name,
+	}
+	if sweep.BestTrialID != nil {
+		data["best_trial_id"] = *sweep.BestTrialID
+		data["best_value"] = sweep.BestValue
+	}
+
+	err := c.events.Publish(ctx, &events.Event{
+		Type:       "experiment.sweep." + string(sweep.Status),
+		Source:     events.SourceExperiment,
+		ProjectID:  sweep.Config.JobTemplate.ProjectID,
+		ResourceID: sweep.ID,
+		Status:     string(sweep.Status),
+		Message:    sweep.Message,
+		Data:       data,
+	})
+	if err != nil {
+		logger.Log.Warn("Failed to publish sweep event", zap.String("sweep_id", sweep.ID.String()), zap.Error(err))
+	}
+}
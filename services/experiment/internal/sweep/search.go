@@ -0,0 +1,375 @@
+package sweep
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/domain"
+)
+
+const (
+	// tpeStartupTrials TPE 在积累到该数量的观测之前退化为随机搜索
+	tpeStartupTrials = 10
+	// tpeGamma 观测中被视为"好"的比例
+	tpeGamma = 0.25
+	// tpeCandidates 每个参数从好分布中抽取的候选数
+	tpeCandidates = 24
+)
+
+// Sampler 超参数采样器
+type Sampler interface {
+	// Next 生成编号为 number 的试验的超参数，history 为已有试验；搜索空间耗尽时返回 false
+	Next(number int, history []*domain.Trial) (map[string]interface{}, bool)
+}
+
+// NewSampler 根据搜索算法创建采样器
+func NewSampler(sweep *domain.Sweep) Sampler {
+	specs := sweep.Config.SearchSpace.Specs()
+	base := baseSampler{
+		names: sortedNames(specs),
+		specs: specs,
+		seed:  sweep.Config.Seed,
+	}
+
+	switch sweep.Algorithm {
+	case domain.SweepAlgorithmGrid:
+		return newGridSampler(base)
+	case domain.SweepAlgorithmBayesian:
+		return &tpeSampler{baseSampler: base, objective: sweep.Config.Objective}
+	default:
+		return &randomSampler{baseSampler: base}
+	}
+}
+
+// GridSize 网格搜索的组合总数
+func GridSize(specs map[string]*domain.ParameterSpec) int {
+	size := 1
+	for _, spec := range specs {
+		size *= len(spec.GridValues())
+		if size == 0 {
+			return 0
+		}
+	}
+	return size
+}
+
+// baseSampler 采样器公共部分，参数按名称排序以保证同一种子下结果可复现
+type baseSampler struct {
+	names []string
+	specs map[string]*domain.ParameterSpec
+	seed  int64
+}
+
+// rng 每个试验使用独立的随机源，重启后重新生成的结果与之前一致
+func (b *baseSampler) rng(number int) *rand.Rand {
+	return rand.New(rand.NewSource(b.seed + int64(number)))
+}
+
+// gridSampler 网格搜索：按编号枚举所有参数组合，最后一个参数变化最快
+type gridSampler struct {
+	baseSampler
+	values [][]interface{}
+	size   int
+}
+
+func newGridSampler(base baseSampler) *gridSampler {
+	g := &gridSampler{baseSampler: base, size: 1}
+	for _, name := range base.names {
+		values := base.specs[name].GridValues()
+		g.values = append(g.values, values)
+		g.size *= len(values)
+	}
+	return g
+}
+
+func (g *gridSampler) Next(number int, _ []*domain.Trial) (map[string]interface{}, bool) {
+	if number >= g.size {
+		return nil, false
+	}
+
+	params := make(map[string]interface{}, len(g.names))
+	index := number
+	for i := len(g.names) - 1; i >= 0; i-- {
+		values := g.values[i]
+		params[g.names[i]] = values[index%len(values)]
+		index /= len(values)
+	}
+	return params, true
+}
+
+// randomSampler 随机搜索：各参数独立均匀采样（log 参数在对数尺度上均匀）
+type randomSampler struct {
+	baseSampler
+}
+
+func (r *randomSampler) Next(number int, _ []*domain.Trial) (map[string]interface{}, bool) {
+	rng := r.rng(number)
+	params := make(map[string]interface{}, len(r.names))
+	for _, name := range r.names {
+		params[name] = sampleUniform(rng, r.specs[name])
+	}
+	return params, true
+}
+
+// tpeSampler 贝叶斯搜索（Tree-structured Parzen Estimator）
+//
+// 将已有观测按目标值分为好、坏两组，对每个参数分别用 Parzen 窗估计 l(x) 和 g(x)，
+// 从 l(x) 中抽取候选并选择 l(x)/g(x) 最大者。观测不足时使用随机采样。
+type tpeSampler struct {
+	baseSampler
+	objective domain.SweepObjective
+}
+
+func (t *tpeSampler) Next(number int, history []*domain.Trial) (map[string]interface{}, bool) {
+	rng := t.rng(number)
+
+	observed := make([]*domain.Trial, 0, len(history))
+	for _, trial := range history {
+		// 被早停的试验使用其终止时的目标值，作为坏样本同样有信息量
+		if trial.ObjectiveValue != nil && (trial.Status == domain.TrialStatusCompleted || trial.Status == domain.TrialStatusPruned) {
+			observed = append(observed, trial)
+		}
+	}
+
+	params := make(map[string]interface{}, len(t.names))
+	if len(observed) < tpeStartupTrials {
+		for _, name := range t.names {
+			params[name] = sampleUniform(rng, t.specs[name])
+		}
+		return params, true
+	}
+
+	sort.SliceStable(observed, func(i, j int) bool {
+		return t.objective.Better(*observed[i].ObjectiveValue, *observed[j].ObjectiveValue)
+	})
+	nGood := int(math.Ceil(tpeGamma * float64(len(observed))))
+	good, bad := observed[:nGood], observed[nGood:]
+
+	for _, name := range t.names {
+		spec := t.specs[name]
+		if spec.Type == domain.ParameterTypeCategorical {
+			params[name] = t.sampleCategorical(rng, name, spec, good, bad)
+		} else {
+			params[name] = t.sampleNumeric(rng, name, spec, good, bad)
+		}
+	}
+	return params, true
+}
+
+// sampleNumeric 在内部尺度上对数值参数做 TPE 采样
+func (t *tpeSampler) sampleNumeric(rng *rand.Rand, name string, spec *domain.ParameterSpec, good, bad []*domain.Trial) interface{} {
+	low, high := internalBounds(spec)
+	l := newParzen(observedValues(name, spec, good), low, high)
+	g := newParzen(observedValues(name, spec, bad), low, high)
+
+	best, bestScore := 0.0, math.Inf(-1)
+	for i := 0; i < tpeCandidates; i++ {
+		x := l.sample(rng)
+		if score := l.logPDF(x) - g.logPDF(x); score > bestScore {
+			best, bestScore = x, score
+		}
+	}
+	return fromInternal(spec, best)
+}
+
+// sampleCategorical 对类别参数做 TPE 采样，频率带一个伪计数以避免概率为 0
+func (t *tpeSampler) sampleCategorical(rng *rand.Rand, name string, spec *domain.ParameterSpec, good, bad []*domain.Trial) interface{} {
+	lw := categoryWeights(name, spec, good)
+	gw := categoryWeights(name, spec, bad)
+
+	best, bestScore := 0, math.Inf(-1)
+	for i := 0; i < tpeCandidates; i++ {
+		k := sampleWeighted(rng, lw)
+		if score := math.Log(lw[k]) - math.Log(gw[k]); score > bestScore {
+			best, bestScore = k, score
+		}
+	}
+	return spec.Values[best]
+}
+
+// sampleUniform 在参数范围内均匀采样
+func sampleUniform(rng *rand.Rand, spec *domain.ParameterSpec) interface{} {
+	if spec.Type == domain.ParameterTypeCategorical {
+		return spec.Values[rng.Intn(len(spec.Values))]
+	}
+	low, high := internalBounds(spec)
+	return fromInternal(spec, low+rng.Float64()*(high-low))
+}
+
+// internalBounds 参数在内部尺度（log 参数取对数，int 参数两端各扩展半格）上的范围
+func internalBounds(spec *domain.ParameterSpec) (float64, float64) {
+	low, high := spec.Min, spec.Max
+	if spec.Type == domain.ParameterTypeInt {
+		low, high = low-0.5, high+0.5
+		if spec.Log {
+			low = math.Max(low, spec.Min/2)
+		}
+	}
+	if spec.Log {
+		return math.Log(low), math.Log(high)
+	}
+	return low, high
+}
+
+// toInternal 将参数值转换到内部尺度
+func toInternal(spec *domain.ParameterSpec, value float64) float64 {
+	if spec.Log {
+		return math.Log(value)
+	}
+	return value
+}
+
+// fromInternal 将内部尺度的值还原为参数值，并按步长量化、限制在范围内
+func fromInternal(spec *domain.ParameterSpec, x float64) interface{} {
+	v := x
+	if spec.Log {
+		v = math.Exp(x)
+	}
+
+	if spec.Type == domain.ParameterTypeInt {
+		step := math.Max(1, math.Trunc(spec.Step))
+		v = spec.Min + math.Round((v-spec.Min)/step)*step
+		return int(math.Max(spec.Min, math.Min(spec.Max, v)))
+	}
+
+	if spec.Step > 0 {
+		v = spec.Min + math.Round((v-spec.Min)/spec.Step)*spec.Step
+	}
+	return math.Max(spec.Min, math.Min(spec.Max, v))
+}
+
+// observedValues 取观测中某参数在内部尺度上的值，缺失或无法解析的值被忽略
+func observedValues(name string, spec *domain.ParameterSpec, trials []*domain.Trial) []float64 {
+	values := make([]float64, 0, len(trials))
+	for _, trial := range trials {
+		if v, ok := toFloat(trial.Hyperparameters[name]); ok && (!spec.Log || v > 0) {
+			values = append(values, toInternal(spec, v))
+		}
+	}
+	return values
+}
+
+// categoryWeights 各类别在观测中出现的（平滑后）频率
+func categoryWeights(name string, spec *domain.ParameterSpec, trials []*domain.Trial) []float64 {
+	weights := make([]float64, len(spec.Values))
+	for i := range weights {
+		weights[i] = 1
+	}
+	for _, trial := range trials {
+		value := fmt.Sprint(trial.Hyperparameters[name])
+		for i, candidate := range spec.Values {
+			if fmt.Sprint(candidate) == value {
+				weights[i]++
+				break
+			}
+		}
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	return weights
+}
+
+// sampleWeighted 按权重抽取下标
+func sampleWeighted(rng *rand.Rand, weights []float64) int {
+	r := rng.Float64()
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return len(weights) - 1
+}
+
+// parzen 一维 Parzen 窗估计：以每个观测为中心的高斯核，加一个覆盖整个范围的先验核
+type parzen struct {
+	mus    []float64
+	sigmas []float64
+	low    float64
+	high   float64
+}
+
+func newParzen(values []float64, low, high float64) *parzen {
+	width := high - low
+	p := &parzen{low: low, high: high}
+
+	// 带宽随观测数增加而收窄（Scott 规则），并设下限避免退化为点分布
+	sigma := width
+	if n := len(values); n > 0 {
+		sigma = math.Max(width*math.Pow(float64(n), -0.2)/2, width/100)
+	}
+	for _, v := range values {
+		p.mus = append(p.mus, v)
+		p.sigmas = append(p.sigmas, sigma)
+	}
+	p.mus = append(p.mus, (low+high)/2)
+	p.sigmas = append(p.sigmas, width)
+	return p
+}
+
+// sample 随机选择一个核并从中采样，超出范围时重试，仍失败则截断到边界
+func (p *parzen) sample(rng *rand.Rand) float64 {
+	if p.high <= p.low {
+		return p.low
+	}
+	k := rng.Intn(len(p.mus))
+	x := p.mus[k]
+	for i := 0; i < 10; i++ {
+		x = p.mus[k] + rng.NormFloat64()*p.sigmas[k]
+		if x >= p.low && x <= p.high {
+			return x
+		}
+	}
+	return math.Max(p.low, math.Min(p.high, x))
+}
+
+// logPDF 混合密度的对数
+func (p *parzen) logPDF(x float64) float64 {
+	if p.high <= p.low {
+		return 0
+	}
+	density := 0.0
+	for i, mu := range p.mus {
+		z := (x - mu) / p.sigmas[i]
+		density += math.Exp(-z*z/2) / (p.sigmas[i] * math.Sqrt(2*math.Pi))
+	}
+	return math.Log(density/float64(len(p.mus)) + 1e-300)
+}
+
+// sortedNames 按名称排序的参数列表
+func sortedNames(specs map[string]*domain.ParameterSpec) []string {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toFloat 解析数据库中（JSON 解码后）的数值
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
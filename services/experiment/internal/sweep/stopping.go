@@ -0,0 +1,179 @@
+package sweep
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/experiment/internal/domain"
+)
+
+// curve 试验目标指标的学习曲线，best[i] 为截至 steps[i] 的最优值
+type curve struct {
+	steps []int64
+	best  []float64
+}
+
+// newCurve 由按 step 升序的指标记录构建学习曲线，未上报 step 的记录视为上一条的下一步
+func newCurve(metrics []*domain.Metric, objective domain.SweepObjective) *curve {
+	c := &curve{}
+	step := int64(-1)
+	for _, m := range metrics {
+		if m.Step != nil && *m.Step > step {
+			step = *m.Step
+		} else {
+			step++
+		}
+
+		best := m.Value
+		if n := len(c.best); n > 0 && !objective.Better(best, c.best[n-1]) {
+			best = c.best[n-1]
+		}
+		c.steps = append(c.steps, step)
+		c.best = append(c.best, best)
+	}
+	return c
+}
+
+// empty 是否没有任何记录
+func (c *curve) empty() bool {
+	return c == nil || len(c.steps) == 0
+}
+
+// lastStep 最后一条记录的 step
+func (c *curve) lastStep() int64 {
+	return c.steps[len(c.steps)-1]
+}
+
+// final 截至最后一条记录的最优值
+func (c *curve) final() float64 {
+	return c.best[len(c.best)-1]
+}
+
+// bestAt 截至 step 的最优值，step 之前没有记录时返回 false
+func (c *curve) bestAt(step int64) (float64, bool) {
+	i := sort.Search(len(c.steps), func(i int) bool { return c.steps[i] > step })
+	if i == 0 {
+		return 0, false
+	}
+	return c.best[i-1], true
+}
+
+// stopper 早停判定器
+type stopper struct {
+	cfg       domain.EarlyStoppingConfig
+	objective domain.SweepObjective
+	// ashaRungs 记录每个试验已判定过的最高检查点，ASHA 在每个检查点只判定一次
+	ashaRungs map[uuid.UUID]int64
+}
+
+func newStopper(cfg domain.EarlyStoppingConfig, objective domain.SweepObjective, ashaRungs map[uuid.UUID]int64) *stopper {
+	cfg.ApplyDefaults()
+	return &stopper{cfg: cfg, objective: objective, ashaRungs: ashaRungs}
+}
+
+// shouldStop 判断运行中的试验是否应提前终止，返回终止原因；curves 为全部试验的学习曲线
+func (s *stopper) shouldStop(trial *domain.Trial, curves map[uuid.UUID]*curve) (string, bool) {
+	c := curves[trial.ID]
+	if c.empty() {
+		return "", false
+	}
+
+	switch s.cfg.Policy {
+	case domain.EarlyStoppingMedian:
+		return s.medianStop(trial, c, curves)
+	case domain.EarlyStoppingASHA:
+		return s.ashaStop(trial, c, curves)
+	default:
+		return "", false
+	}
+}
+
+// medianStop 中位数停止规则：截至当前 step 的最优值差于其他已达到该 step 的试验的中位数时终止
+func (s *stopper) medianStop(trial *domain.Trial, c *curve, curves map[uuid.UUID]*curve) (string, bool) {
+	step := c.lastStep()
+	if step < s.cfg.GraceSteps {
+		return "", false
+	}
+
+	var others []float64
+	for id, other := range curves {
+		if id == trial.ID || other.empty() || other.lastStep() < step {
+			continue
+		}
+		if v, ok := other.bestAt(step); ok {
+			others = append(others, v)
+		}
+	}
+	if len(others) < s.cfg.MinTrials {
+		return "", false
+	}
+
+	median := medianOf(others)
+	if s.objective.Better(median, c.final()) {
+		return fmt.Sprintf("pruned by median stopping rule at step %d: %s=%g, median=%g",
+			step, s.objective.Metric, c.final(), median), true
+	}
+	return "", false
+}
+
+// ashaStop 异步连续减半：试验到达检查点时，若不在该检查点全部结果的前 1/ReductionFactor 内则终止
+func (s *stopper) ashaStop(trial *domain.Trial, c *curve, curves map[uuid.UUID]*curve) (string, bool) {
+	rung, ok := s.highestRung(c.lastStep())
+	if !ok || rung <= s.ashaRungs[trial.ID] {
+		return "", false
+	}
+	s.ashaRungs[trial.ID] = rung
+
+	value, _ := c.bestAt(rung)
+	var results []float64
+	for _, other := range curves {
+		if other.empty() || other.lastStep() < rung {
+			continue
+		}
+		if v, ok := other.bestAt(rung); ok {
+			results = append(results, v)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return s.objective.Better(results[i], results[j]) })
+	keep := int(math.Max(1, math.Floor(float64(len(results))/s.cfg.ReductionFactor)))
+	cutoff := results[keep-1]
+	if s.objective.Better(cutoff, value) {
+		return fmt.Sprintf("pruned by ASHA at rung %d: %s=%g, cutoff=%g",
+			rung, s.objective.Metric, value, cutoff), true
+	}
+	return "", false
+}
+
+// highestRung 不超过 step 的最高检查点；达到 MaxSteps 的试验不再判定
+func (s *stopper) highestRung(step int64) (int64, bool) {
+	if step < s.cfg.MinSteps {
+		return 0, false
+	}
+
+	rung := s.cfg.MinSteps
+	for {
+		next := int64(math.Ceil(float64(rung) * s.cfg.ReductionFactor))
+		if next > step || (s.cfg.MaxSteps > 0 && next >= s.cfg.MaxSteps) {
+			break
+		}
+		rung = next
+	}
+	if s.cfg.MaxSteps > 0 && rung >= s.cfg.MaxSteps {
+		return 0, false
+	}
+	return rung, true
+}
+
+// medianOf 中位数
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,161 @@
+package sweep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Training service job statuses
+const (
+	JobStatusPending   = "pending"
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+	JobStatusStopping  = "stopping"
+)
+
+// CreateJobRequest 训练服务创建任务请求
+type CreateJobRequest struct {
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description,omitempty"`
+	ProjectID       string                 `json:"project_id"`
+	ExperimentID    string                 `json:"experiment_id,omitempty"`
+	RunID           string                 `json:"run_id,omitempty"`
+	ModelName       string                 `json:"model_name"`
+	DatasetPath     string                 `json:"dataset_path"`
+	OutputPath      string                 `json:"output_path"`
+	Framework       string                 `json:"framework"`
+	Image           string                 `json:"image"`
+	Command         []string               `json:"command,omitempty"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters,omitempty"`
+	Environment     map[string]string      `json:"environment,omitempty"`
+	GPUCount        int                    `json:"gpu_count"`
+	GPUType         string                 `json:"gpu_type,omitempty"`
+	CPUCount        int                    `json:"cpu_count"`
+	MemoryGB        int                    `json:"memory_gb"`
+	TimeoutHours    int                    `json:"timeout_hours"`
+	Priority        int                    `json:"priority"`
+}
+
+// TrainingJob 训练服务返回的任务信息
+type TrainingJob struct {
+	ID            uuid.UUID `json:"id"`
+	Status        string    `json:"status"`
+	StatusMessage string    `json:"status_message"`
+}
+
+// APIError 训练服务返回的错误
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("training service returned %d: %s", e.StatusCode, e.Message)
+}
+
+// IsClientError 是否为请求本身的问题（重试不会成功）
+func (e *APIError) IsClientError() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 && e.StatusCode != http.StatusTooManyRequests
+}
+
+// TrainingClient 训练服务客户端接口
+type TrainingClient interface {
+	CreateJob(ctx context.Context, userID uuid.UUID, req *CreateJobRequest) (*TrainingJob, error)
+	GetJob(ctx context.Context, userID, jobID uuid.UUID) (*TrainingJob, error)
+	StopJob(ctx context.Context, userID, jobID uuid.UUID) error
+}
+
+// httpTrainingClient 通过 REST API 访问训练服务
+type httpTrainingClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewTrainingClient 创建训练服务客户端
+func NewTrainingClient(baseURL string) TrainingClient {
+	return &httpTrainingClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *httpTrainingClient) CreateJob(ctx context.Context, userID uuid.UUID, req *CreateJobRequest) (*TrainingJob, error) {
+	var job TrainingJob
+	if err := c.do(ctx, http.MethodPost, "/api/v1/training/jobs", userID, req, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (c *httpTrainingClient) GetJob(ctx context.Context, userID, jobID uuid.UUID) (*TrainingJob, error) {
+	var job TrainingJob
+	if err := c.do(ctx, http.MethodGet, "/api/v1/training/jobs/"+jobID.String(), userID, nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (c *httpTrainingClient) StopJob(ctx context.Context, userID, jobID uuid.UUID) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/training/jobs/"+jobID.String()+"/stop", userID, nil, nil)
+}
+
+// do 发送请求并解析标准响应结构中的 data 字段
+func (c *httpTrainingClient) do(ctx context.Context, method, path string, userID uuid.UUID, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", userID.String())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call training service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data  json.RawMessage `json:"data"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode training service response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		if envelope.Error != nil {
+			apiErr.Message = envelope.Error.Message
+		}
+		return apiErr
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return fmt.Errorf("failed to decode training job: %w", err)
+		}
+	}
+	return nil
+}
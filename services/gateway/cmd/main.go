@@ -91,12 +91,100 @@ func main() {
 		protected.DELETE("/training/jobs/:id", forwardTo(services.Training))
 		protected.GET("/training/jobs/:id/logs", forwardTo(services.Training))
 		protected.POST("/training/jobs/:id/stop", forwardTo(services.Training))
+		protected.GET("/training/jobs/:id/attempts", forwardTo(services.Training))
+		protected.POST("/training/jobs/:id/resume", forwardTo(services.Training))
+		protected.POST("/training/jobs/:id/pause", forwardTo(services.Training))
+		protected.GET("/training/jobs/:id/metrics", forwardTo(services.Training))
+		protected.GET("/training/jobs/:id/metrics/ws", forwardTo(services.Training))
+		protected.GET("/training/jobs/:id/checkpoints", forwardTo(services.Training))
+		protected.GET("/training/jobs/:id/checkpoints/:checkpoint_id/download", forwardTo(services.Training))
+		protected.DELETE("/training/jobs/:id/checkpoints/:checkpoint_id", forwardTo(services.Training))
+		protected.GET("/training/jobs/:id/artifacts", forwardTo(services.Training))
+
+		// Pipeline routes
+		protected.POST("/training/pipelines", forwardTo(services.Training))
+		protected.GET("/training/pipelines", forwardTo(services.Training))
+		protected.GET("/training/pipelines/:id", forwardTo(services.Training))
+		protected.PATCH("/training/pipelines/:id", forwardTo(services.Training))
+		protected.DELETE("/training/pipelines/:id", forwardTo(services.Training))
+		protected.POST("/training/pipelines/:id/runs", forwardTo(services.Training))
+		protected.GET("/training/pipelines/:id/runs", forwardTo(services.Training))
+		protected.GET("/training/pipeline-runs/:id", forwardTo(services.Training))
+		protected.POST("/training/pipeline-runs/:id/cancel", forwardTo(services.Training))
+
+		// Template routes
+		protected.POST("/training/templates", forwardTo(services.Training))
+		protected.GET("/training/templates", forwardTo(services.Training))
+		protected.GET("/training/templates/builtin", forwardTo(services.Training))
+		protected.GET("/training/templates/:id", forwardTo(services.Training))
+		protected.DELETE("/training/templates/:id", forwardTo(services.Training))
+		protected.GET("/training/templates/:id/versions", forwardTo(services.Training))
+		protected.POST("/training/templates/:id/versions", forwardTo(services.Training))
+		protected.POST("/training/jobs/from-template", forwardTo(services.Training))
+
+		// Image policy routes
+		protected.GET("/training/image-policies/:org_id", forwardTo(services.Training))
+		protected.PUT("/training/image-policies/:org_id", forwardTo(services.Training))
+		protected.DELETE("/training/image-policies/:org_id", forwardTo(services.Training))
+
+		// Quota routes
+		protected.GET("/quotas", forwardTo(services.Training))
+
+		// Cluster routes
+		protected.GET("/cluster/gpus", forwardTo(services.Training))
+
+		// Webhook routes
+		protected.POST("/webhooks", forwardTo(services.Training))
+		protected.GET("/webhooks", forwardTo(services.Training))
+		protected.GET("/webhooks/:id", forwardTo(services.Training))
+		protected.PATCH("/webhooks/:id", forwardTo(services.Training))
+		protected.DELETE("/webhooks/:id", forwardTo(services.Training))
+		protected.GET("/webhooks/:id/deliveries", forwardTo(services.Training))
+		protected.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", forwardTo(services.Training))
+		protected.GET("/webhook-deliveries", forwardTo(services.Training))
+
+		// Secret routes
+		protected.POST("/secrets", forwardTo(services.Training))
+		protected.GET("/secrets", forwardTo(services.Training))
+		protected.GET("/secrets/:id", forwardTo(services.Training))
+		protected.PATCH("/secrets/:id", forwardTo(services.Training))
+		protected.DELETE("/secrets/:id", forwardTo(services.Training))
+		protected.GET("/secret-access-logs", forwardTo(services.Training))
 
 		// Inference routes
 		protected.GET("/inference/services", forwardTo(services.Inference))
 		protected.POST("/inference/services", forwardTo(services.Inference))
 		protected.GET("/inference/services/:id", forwardTo(services.Inference))
+		protected.PATCH("/inference/services/:id", forwardTo(services.Inference))
 		protected.DELETE("/inference/services/:id", forwardTo(services.Inference))
+		protected.POST("/inference/services/:id/start", forwardTo(services.Inference))
+		protected.POST("/inference/services/:id/stop", forwardTo(services.Inference))
+		protected.POST("/inference/services/:id/predict", forwardTo(services.Inference))
+		protected.POST("/inference/services/:id/predict/stream", forwardTo(services.Inference))
+		protected.GET("/inference/services/:id/requests", forwardTo(services.Inference))
+		protected.GET("/inference/usage", forwardTo(services.Inference))
+		protected.GET("/inference/endpoints", forwardTo(services.Inference))
+		protected.POST("/inference/endpoints", forwardTo(services.Inference))
+		protected.GET("/inference/endpoints/:id", forwardTo(services.Inference))
+		protected.PATCH("/inference/endpoints/:id", forwardTo(services.Inference))
+		protected.DELETE("/inference/endpoints/:id", forwardTo(services.Inference))
+		protected.POST("/inference/endpoints/:id/predict", forwardTo(services.Inference))
+		protected.POST("/inference/endpoints/:id/predict/stream", forwardTo(services.Inference))
+		protected.GET("/inference/endpoints/:id/rollouts", forwardTo(services.Inference))
+		protected.POST("/inference/endpoints/:id/rollouts", forwardTo(services.Inference))
+		protected.GET("/inference/endpoints/:id/rollouts/:rollout_id", forwardTo(services.Inference))
+		protected.POST("/inference/endpoints/:id/rollouts/:rollout_id/promote", forwardTo(services.Inference))
+		protected.POST("/inference/endpoints/:id/rollouts/:rollout_id/rollback", forwardTo(services.Inference))
+	}
+
+	// OpenAI-compatible routes (API key auth)
+	openai := router.Group("/v1")
+	openai.Use(middleware.APIKeyAuth(middleware.NewAPIKeyValidator(services.User, middleware.DefaultAPIKeyCacheTTL)))
+	{
+		openai.GET("/models", forwardTo(services.Inference))
+		openai.POST("/chat/completions", forwardTo(services.Inference))
+		openai.POST("/completions", forwardTo(services.Inference))
+		openai.POST("/embeddings", forwardTo(services.Inference))
 	}
 
 	srv := &http.Server{
@@ -152,6 +240,14 @@ func forwardTo(targetURL string) gin.HandlerFunc {
 			if email := middleware.GetEmail(c); email != "" {
 				req.Header.Set("X-User-Email", email)
 			}
+
+			// Only the gateway may attribute a request to an API key
+			req.Header.Del("X-API-Key-ID")
+			if keyID := middleware.GetAPIKeyID(c); keyID != "" {
+				req.Header.Set("X-API-Key-ID", keyID)
+				req.Header.Del("Authorization")
+				req.Header.Del("X-API-Key")
+			}
 		}
 
 		proxy.ServeHTTP(c.Writer, c.Request)
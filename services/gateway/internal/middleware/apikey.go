@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAPIKeyCacheTTL is how long a validation result is reused. A deleted
+	// key keeps working on this gateway for at most this long.
+	DefaultAPIKeyCacheTTL = time.Minute
+
+	// maxCachedAPIKeys bounds the cache so random keys cannot grow it without limit
+	maxCachedAPIKeys = 10000
+)
+
+// ErrInvalidAPIKey is returned when the user service rejects an API key
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyIdentity identifies the key and the user it belongs to
+type APIKeyIdentity struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+type apiKeyCacheEntry struct {
+	identity  *APIKeyIdentity // nil for rejected keys
+	expiresAt time.Time
+}
+
+// APIKeyValidator validates API keys against the user service and caches the results
+type APIKeyValidator struct {
+	validateURL string
+	client      *http.Client
+	ttl         time.Duration
+
+	mu    sync.Mutex
+	cache map[string]apiKeyCacheEntry
+}
+
+// NewAPIKeyValidator creates a validator backed by the user service
+func NewAPIKeyValidator(userServiceURL string, ttl time.Duration) *APIKeyValidator {
+	if ttl <= 0 {
+		ttl = DefaultAPIKeyCacheTTL
+	}
+	return &APIKeyValidator{
+		validateURL: userServiceURL + "/api/v1/internal/api-keys/validate",
+		client:      &http.Client{Timeout: 10 * time.Second},
+		ttl:         ttl,
+		cache:       make(map[string]apiKeyCacheEntry),
+	}
+}
+
+// Validate returns the identity of the key, or ErrInvalidAPIKey if it was rejected
+func (v *APIKeyValidator) Validate(ctx context.Context, key string) (*APIKeyIdentity, error) {
+	sum := sha256.Sum256([]byte(key))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	if identity, ok := v.cached(cacheKey); ok {
+		if identity == nil {
+			return nil, ErrInvalidAPIKey
+		}
+		return identity, nil
+	}
+
+	identity, err := v.validate(ctx, key)
+	if err != nil && !errors.Is(err, ErrInvalidAPIKey) {
+		// Do not cache transient failures of the user service
+		return nil, err
+	}
+	v.store(cacheKey, identity)
+	return identity, err
+}
+
+func (v *APIKeyValidator) validate(ctx context.Context, key string) (*APIKeyIdentity, error) {
+	body, err := json.Marshal(map[string]string{"key": key})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.validateURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build api key validation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach user service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return nil, ErrInvalidAPIKey
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("user service returned %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data APIKeyIdentity `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode api key validation response: %w", err)
+	}
+	return &result.Data, nil
+}
+
+func (v *APIKeyValidator) cached(cacheKey string) (*APIKeyIdentity, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.cache[cacheKey]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.identity, true
+}
+
+func (v *APIKeyValidator) store(cacheKey string, identity *APIKeyIdentity) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	if len(v.cache) >= maxCachedAPIKeys {
+		for k, entry := range v.cache {
+			if now.After(entry.expiresAt) {
+				delete(v.cache, k)
+			}
+		}
+		if len(v.cache) >= maxCachedAPIKeys {
+			v.cache = make(map[string]apiKeyCacheEntry)
+		}
+	}
+	v.cache[cacheKey] = apiKeyCacheEntry{identity: identity, expiresAt: now.Add(v.ttl)}
+}
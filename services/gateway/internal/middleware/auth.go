@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -10,9 +11,10 @@ import (
 )
 
 const (
-	ContextUserID = "user_id"
-	ContextEmail  = "email"
-	ContextRole   = "role"
+	ContextUserID   = "user_id"
+	ContextEmail    = "email"
+	ContextRole     = "role"
+	ContextAPIKeyID = "api_key_id"
 )
 
 func Auth(jwtSecret string) gin.HandlerFunc {
@@ -49,17 +51,36 @@ func Auth(jwtSecret string) gin.HandlerFunc {
 	}
 }
 
-func APIKeyAuth() gin.HandlerFunc {
+// APIKeyAuth authenticates requests with a platform API key, sent either as
+// "Authorization: Bearer <key>" (OpenAI clients) or in the X-API-Key header
+func APIKeyAuth(validator *APIKeyValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
+		if apiKey == "" {
+			parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+			if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+				apiKey = strings.TrimSpace(parts[1])
+			}
+		}
 		if apiKey == "" {
 			response.Error(c, http.StatusUnauthorized, "missing api key")
 			c.Abort()
 			return
 		}
 
-		// TODO: Validate API key against database
-		// For MVP, we'll implement this in user service
+		identity, err := validator.Validate(c.Request.Context(), apiKey)
+		if err != nil {
+			if errors.Is(err, ErrInvalidAPIKey) {
+				response.Error(c, http.StatusUnauthorized, "invalid api key")
+			} else {
+				response.Error(c, http.StatusServiceUnavailable, "failed to validate api key")
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextUserID, identity.UserID)
+		c.Set(ContextAPIKeyID, identity.ID)
 
 		c.Next()
 	}
@@ -82,3 +103,12 @@ func GetEmail(c *gin.Context) string {
 	}
 	return ""
 }
+
+// GetAPIKeyID gets the API key ID from context, empty for JWT-authenticated requests
+func GetAPIKeyID(c *gin.Context) string {
+	keyID, _ := c.Get(ContextAPIKeyID)
+	if id, ok := keyID.(string); ok {
+		return id
+	}
+	return ""
+}
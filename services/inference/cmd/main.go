@@ -11,11 +11,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/database"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/quota"
+	pkgredis "github.com/plucky-groove3/ai-train-infer-platform/pkg/redis"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/config"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/docker"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/handler"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/proxy"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/service"
 )
@@ -60,13 +65,67 @@ func main() {
 
 	// 初始化仓库
 	serviceRepo := repository.NewServiceRepository(db)
+	if err := serviceRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate inference services table", zap.Error(err))
+	}
 	modelRepo := repository.NewModelRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+	requestRepo := repository.NewRequestRepository(db)
+	if err := requestRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate inference requests table", zap.Error(err))
+	}
+	endpointRepo := repository.NewEndpointRepository(db)
+	if err := endpointRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate inference endpoints tables", zap.Error(err))
+	}
+
+	// 初始化配额检查器
+	quotaChecker := quota.NewChecker(db)
+
+	// 初始化密钥存储，与训练服务共用密钥表和主密钥
+	var secretStore secrets.Manager
+	if cfg.SecretsMasterKey != "" {
+		masterKey, err := secrets.ParseMasterKey(cfg.SecretsMasterKey)
+		if err != nil {
+			logger.Fatal("Invalid SECRETS_MASTER_KEY", zap.Error(err))
+		}
+		keyProvider, err := secrets.NewLocalKeyProvider(cfg.SecretsMasterKeyID, masterKey)
+		if err != nil {
+			logger.Fatal("Failed to create secrets key provider", zap.Error(err))
+		}
+		secretStore = secrets.NewManager(db, keyProvider)
+	}
 
 	// 初始化服务
-	inferenceService := service.NewInferenceService(cfg, serviceRepo, modelRepo, dockerExec)
+	inferenceService := service.NewInferenceService(cfg, serviceRepo, modelRepo, dockerExec, quotaChecker, secretStore)
+	endpointService := service.NewEndpointService(serviceRepo, endpointRepo, requestRepo)
+
+	// 初始化事件总线，服务生命周期事件发布到各服务共用的事件流
+	eventRedis, err := pkgredis.NewFromURL(cfg.RedisURL)
+	if err != nil {
+		logger.Warn("Failed to connect to event bus, service events are disabled", zap.Error(err))
+	} else {
+		defer eventRedis.Close()
+		inferenceService.SetEventPublisher(events.NewBus(eventRedis, cfg.EventStream, cfg.EventStreamMaxLen))
+	}
+
+	// 启动灰度发布控制器
+	rolloutController := service.NewRolloutController(endpointService, cfg.RolloutCheckInterval)
+	rolloutController.Start()
+
+	// 初始化推理请求代理，缩容到零的服务由推理服务冷启动
+	predictProxy := proxy.NewProxy(serviceRepo, endpointRepo, projectRepo, requestRepo, inferenceService, proxy.Config{Timeout: cfg.PredictTimeout})
+	inferenceService.SetPortPublisher(predictProxy)
+
+	// 启动副本自动扩缩容
+	autoscaler := service.NewAutoscaler(inferenceService, serviceRepo, requestRepo, predictProxy, cfg)
+	autoscaler.Start()
 
 	// 初始化处理器
-	serviceHandler := handler.NewServiceHandler(inferenceService)
+	serviceHandler := handler.NewServiceHandler(inferenceService, projectRepo)
+	endpointHandler := handler.NewEndpointHandler(endpointService, inferenceService, projectRepo)
+	predictHandler := handler.NewPredictHandler(predictProxy)
+	openaiHandler := handler.NewOpenAIHandler(predictProxy)
 
 	// 设置 Gin 模式
 	if cfg.Environment == "production" {
@@ -96,8 +155,13 @@ func main() {
 	{
 		// 注册推理服务路由
 		serviceHandler.RegisterRoutes(v1)
+		endpointHandler.RegisterRoutes(v1)
+		predictHandler.RegisterRoutes(v1)
 	}
 
+	// OpenAI 兼容接口，由网关以 API Key 认证后转发
+	openaiHandler.RegisterRoutes(router.Group("/v1"))
+
 	// 创建 HTTP 服务器
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -120,6 +184,12 @@ func main() {
 
 	logger.Info("Shutting down Inference Service...")
 
+	// 停止灰度发布控制器
+	rolloutController.Stop()
+
+	// 停止自动扩缩容，避免在停止服务时创建副本
+	autoscaler.Stop()
+
 	// 停止所有运行中的服务
 	if err := inferenceService.StopAll(context.Background()); err != nil {
 		logger.Error("Failed to stop all services", zap.Error(err))
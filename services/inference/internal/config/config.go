@@ -19,6 +19,11 @@ type Config struct {
 	LogOutput string // stdout, file, or both
 	LogPath   string
 
+	// Redis（生命周期事件）
+	RedisURL          string
+	EventStream       string // 各服务共用的生命周期事件流
+	EventStreamMaxLen int64  // 事件流最大长度
+
 	// Docker
 	DockerHost       string
 	DockerNetwork    string
@@ -36,6 +41,29 @@ type Config struct {
 	MaxConcurrentServices int
 	HealthCheckInterval  time.Duration
 	ModelDownloadTimeout time.Duration
+	HealthCheckTimeout   time.Duration // 启动后等待健康检查通过的最长时间
+	StopTimeout          int           // 停止容器时等待退出的秒数，超时后强制结束
+
+	// 主机端口分配范围，推理服务启动时从中选择未被占用的端口
+	HostPortMin int
+	HostPortMax int
+	PublicHost  string // 对外访问地址中的主机名
+
+	// 推理代理
+	PredictTimeout time.Duration // 转发单个推理请求的最长时间
+
+	// 灰度发布
+	RolloutCheckInterval time.Duration // 评估进行中发布的间隔
+
+	// 自动扩缩容
+	AutoscaleInterval time.Duration // 评估副本数的间隔
+	AutoscaleWindow   time.Duration // 计算 p95 延迟的时间窗口
+	ScaleDownDelay    time.Duration // 负载持续低于目标多久后缩容
+	ScaleToZeroAfter  time.Duration // min_replicas 为 0 的服务空闲多久后缩容到零
+
+	// 密钥配置，与训练服务使用同一主密钥
+	SecretsMasterKey   string // 加密项目密钥的 32 字节主密钥（base64 或 hex），为空时不能引用密钥
+	SecretsMasterKeyID string
 }
 
 // Load 加载配置
@@ -51,6 +79,10 @@ func Load() *Config {
 		LogOutput: getEnv("LOG_OUTPUT", "stdout"),
 		LogPath:   getEnv("LOG_PATH", "logs/inference.log"),
 
+		RedisURL:          getEnv("REDIS_URL", "redis://localhost:6379"),
+		EventStream:       getEnv("EVENT_STREAM", "aitip:events"),
+		EventStreamMaxLen: int64(getEnvInt("EVENT_STREAM_MAX_LEN", 100000)),
+
 		DockerHost:       getEnv("DOCKER_HOST", ""),
 		DockerNetwork:    getEnv("DOCKER_NETWORK", "aitip-network"),
 		ModelCachePath:   getEnv("MODEL_CACHE_PATH", "/var/aitip/models"),
@@ -62,9 +94,27 @@ func Load() *Config {
 		MinIOUseSSL:    getEnvBool("MINIO_USE_SSL", false),
 
 		DefaultInferencePort:  getEnvInt("DEFAULT_INFERENCE_PORT", 8000),
-		MaxConcurrentServices: getEnvInt("MAX_CONCURRENT_SERVICES", "10"),
+		MaxConcurrentServices: getEnvInt("MAX_CONCURRENT_SERVICES", 10),
 		HealthCheckInterval:   parseDuration(getEnv("HEALTH_CHECK_INTERVAL", "30s")),
 		ModelDownloadTimeout:  parseDuration(getEnv("MODEL_DOWNLOAD_TIMEOUT", "10m")),
+		HealthCheckTimeout:    parseDuration(getEnv("HEALTH_CHECK_TIMEOUT", "10m")),
+		StopTimeout:           getEnvInt("STOP_TIMEOUT_SECONDS", 30),
+
+		HostPortMin: getEnvInt("HOST_PORT_MIN", 30000),
+		HostPortMax: getEnvInt("HOST_PORT_MAX", 30999),
+		PublicHost:  getEnv("PUBLIC_HOST", "localhost"),
+
+		PredictTimeout: parseDuration(getEnv("PREDICT_TIMEOUT", "5m")),
+
+		RolloutCheckInterval: parseDuration(getEnv("ROLLOUT_CHECK_INTERVAL", "15s")),
+
+		AutoscaleInterval: parseDuration(getEnv("AUTOSCALE_INTERVAL", "15s")),
+		AutoscaleWindow:   parseDuration(getEnv("AUTOSCALE_WINDOW", "1m")),
+		ScaleDownDelay:    parseDuration(getEnv("SCALE_DOWN_DELAY", "5m")),
+		ScaleToZeroAfter:  parseDuration(getEnv("SCALE_TO_ZERO_AFTER", "15m")),
+
+		SecretsMasterKey:   getEnv("SECRETS_MASTER_KEY", ""),
+		SecretsMasterKeyID: getEnv("SECRETS_MASTER_KEY_ID", "local-1"),
 	}
 }
 
@@ -19,9 +19,22 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
 )
 
+// Runtime 推理容器运行时，Executor 是其 Docker 实现
+//
+// 推理服务和自动扩缩容只通过该接口管理副本容器，可以替换为不启动真实容器的实现。
+type Runtime interface {
+	CreateContainer(ctx context.Context, service *domain.InferenceService, replica int, modelPath string, resolved []secrets.Resolved) (string, string, error)
+	StartContainer(ctx context.Context, containerID string) error
+	StopContainer(ctx context.Context, containerID string, timeout int) error
+	RemoveContainer(ctx context.Context, containerID string, force bool) error
+	GetContainerInfo(ctx context.Context, containerID string) (*domain.ContainerInfo, error)
+	HealthCheck(ctx context.Context, containerID string) (bool, error)
+}
+
 // Executor Docker 执行器
 type Executor struct {
 	client       *client.Client
@@ -66,9 +79,14 @@ func (e *Executor) Close() error {
 	return e.client.Close()
 }
 
-// CreateContainer 创建推理容器
-func (e *Executor) CreateContainer(ctx context.Context, service *domain.InferenceService, modelPath string) (string, string, error) {
+// CreateContainer 创建推理服务的第 replica 个副本容器，resolved 为启动时解密的密钥，注入为环境变量或写入容器文件
+//
+// 副本不映射主机端口，只能在 Docker 网络内通过容器名访问；服务的主机端口由推理服务监听，经负载均衡转发到各副本。
+func (e *Executor) CreateContainer(ctx context.Context, service *domain.InferenceService, replica int, modelPath string, resolved []secrets.Resolved) (string, string, error) {
 	containerName := fmt.Sprintf("inference-%s", service.ID.String()[:8])
+	if replica > 0 {
+		containerName = fmt.Sprintf("%s-%d", containerName, replica)
+	}
 	
 	// 获取镜像
 	image := e.getImageForType(service.Type)
@@ -79,21 +97,15 @@ func (e *Executor) CreateContainer(ctx context.Context, service *domain.Inferenc
 		return "", "", fmt.Errorf("failed to pull image: %w", err)
 	}
 
-	// 准备端口映射
-	hostPort := strconv.Itoa(service.HostPort)
+	// 准备端口
 	containerPort := strconv.Itoa(service.ContainerPort)
-	
-	portBindings := nat.PortMap{
-		nat.Port(containerPort + "/tcp"): []nat.PortBinding{
-			{HostIP: "0.0.0.0", HostPort: hostPort},
-		},
-	}
 	exposedPorts := nat.PortSet{
 		nat.Port(containerPort + "/tcp"): struct{}{},
 	}
 
 	// 准备环境变量
 	env := e.buildEnvironment(service, modelPath)
+	env = append(env, secrets.Env(resolved)...)
 
 	// 准备挂载
 	mounts := e.buildMounts(service, modelPath)
@@ -127,17 +139,17 @@ func (e *Executor) CreateContainer(ctx context.Context, service *domain.Inferenc
 			"service_id":    service.ID.String(),
 			"project_id":    service.ProjectID.String(),
 			"model_id":      service.ModelID.String(),
+			"replica":       strconv.Itoa(replica),
 		},
 	}
 
 	// 主机配置
 	hostConfig := &container.HostConfig{
-		PortBindings: portBindings,
 		Mounts:       mounts,
 		Resources:    resources,
 		NetworkMode:  container.NetworkMode(e.network),
 		RestartPolicy: container.RestartPolicy{
-			Name:              "unless-stopped",
+			Name:              "on-failure",
 			MaximumRetryCount: 3,
 		},
 	}
@@ -148,6 +160,18 @@ func (e *Executor) CreateContainer(ctx context.Context, service *domain.Inferenc
 		return "", "", fmt.Errorf("failed to create container: %w", err)
 	}
 
+	// 以文件形式挂载的密钥在启动前写入容器
+	if files := secrets.Files(resolved); len(files) > 0 {
+		archive, err := secrets.Archive(files)
+		if err == nil {
+			err = e.client.CopyToContainer(ctx, resp.ID, "/", archive, types.CopyToContainerOptions{})
+		}
+		if err != nil {
+			e.client.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+			return "", "", fmt.Errorf("failed to copy secret files to container: %w", err)
+		}
+	}
+
 	logger.Info("Container created",
 		zap.String("container_id", resp.ID[:12]),
 		zap.String("container_name", containerName),
@@ -166,7 +190,6 @@ func (e *Executor) StartContainer(ctx context.Context, containerID string) error
 
 // StopContainer 停止容器
 func (e *Executor) StopContainer(ctx context.Context, containerID string, timeout int) error {
-	timeoutDuration := time.Duration(timeout) * time.Second
 	if err := e.client.ContainerStop(ctx, containerID, container.StopOptions{
 		Timeout: &timeout,
 	}); err != nil {
@@ -246,8 +269,8 @@ func (e *Executor) HealthCheck(ctx context.Context, containerID string) (bool, e
 	return true, nil
 }
 
-// GetContainerLogs 获取容器日志
-func (e *Executor) GetContainerLogs(ctx context.Context, containerID string, tail int) (string, error) {
+// GetContainerLogs 获取容器日志，redactor 非空时逐行去除注入的密钥值
+func (e *Executor) GetContainerLogs(ctx context.Context, containerID string, tail int, redactor *secrets.Redactor) (string, error) {
 	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
@@ -266,7 +289,14 @@ func (e *Executor) GetContainerLogs(ctx context.Context, containerID string, tai
 		return "", fmt.Errorf("failed to read logs: %w", err)
 	}
 
-	return string(logs), nil
+	if redactor == nil {
+		return string(logs), nil
+	}
+	lines := strings.Split(string(logs), "\n")
+	for i, line := range lines {
+		lines[i] = redactor.Redact(line)
+	}
+	return strings.Join(lines, "\n"), nil
 }
 
 // ListContainers 列出推理容器
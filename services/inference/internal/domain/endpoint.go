@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EndpointBackend 端点后面的一个部署及其相对流量权重
+type EndpointBackend struct {
+	ServiceID uuid.UUID `json:"service_id" binding:"required"`
+	Weight    int       `json:"weight" binding:"min=0,max=1000"`
+}
+
+// HeaderRoute 请求头取值与部署的对应关系，命中时优先于权重
+type HeaderRoute struct {
+	Header string               `json:"header"`
+	Values map[string]uuid.UUID `json:"values"`
+}
+
+// InferenceEndpoint 推理端点
+//
+// 端点把请求分发到多个同类型的部署（通常是同一模型的不同版本）：请求头命中 HeaderRoute 时
+// 转发到对应部署，否则按权重选择。Sticky 为 true 时按用户 ID 哈希选择，权重不变时同一用户
+// 总是命中同一部署。灰度发布进行中时由 ActiveRolloutID 指向发布记录，期间权重由发布控制。
+type InferenceEndpoint struct {
+	ID              uuid.UUID         `json:"id" gorm:"type:uuid;primary_key"`
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	ProjectID       uuid.UUID         `json:"project_id" gorm:"type:uuid;index"`
+	UserID          uuid.UUID         `json:"user_id" gorm:"type:uuid"`
+	Type            InferenceType     `json:"type"`
+	Backends        []EndpointBackend `json:"backends" gorm:"serializer:json"`
+	HeaderRoute     *HeaderRoute      `json:"header_route,omitempty" gorm:"serializer:json"`
+	Sticky          bool              `json:"sticky"`
+	ActiveRolloutID *uuid.UUID        `json:"active_rollout_id,omitempty" gorm:"type:uuid"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// TableName 表名
+func (InferenceEndpoint) TableName() string {
+	return "inference_endpoints"
+}
+
+// Backend 获取部署在端点中的配置
+func (e *InferenceEndpoint) Backend(serviceID uuid.UUID) (EndpointBackend, bool) {
+	for _, b := range e.Backends {
+		if b.ServiceID == serviceID {
+			return b, true
+		}
+	}
+	return EndpointBackend{}, false
+}
+
+// RolloutStatus 灰度发布状态
+type RolloutStatus string
+
+const (
+	RolloutStatusRunning    RolloutStatus = "running"     // 发布中
+	RolloutStatusSucceeded  RolloutStatus = "succeeded"   // 全部流量已切换到 canary
+	RolloutStatusRolledBack RolloutStatus = "rolled_back" // 已恢复发布前的流量分配
+)
+
+// RolloutPolicy 灰度发布的步骤和健康门限
+type RolloutPolicy struct {
+	Steps               []int   `json:"steps"`                 // 每一步 canary 占 baseline 原有流量的百分比，递增且以 100 结束
+	StepIntervalSeconds int     `json:"step_interval_seconds"` // 每一步的最短观察时间
+	MinRequests         int64   `json:"min_requests"`          // canary 在一步内至少处理的请求数，达到后才评估门限和进入下一步
+	MaxErrorRate        float64 `json:"max_error_rate"`        // canary 5xx 比例上限
+	MaxP95LatencyMs     float64 `json:"max_p95_latency_ms"`    // canary p95 延迟上限，0 表示不检查
+}
+
+// RolloutStats 部署在一段时间内的请求统计
+type RolloutStats struct {
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	ErrorRate    float64 `json:"error_rate"`
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+}
+
+// Rollout 灰度发布记录，按步骤把 baseline 的流量逐步切换到 canary，超过门限时自动回滚
+type Rollout struct {
+	ID                uuid.UUID         `json:"id" gorm:"type:uuid;primary_key"`
+	EndpointID        uuid.UUID         `json:"endpoint_id" gorm:"type:uuid;index"`
+	BaselineServiceID uuid.UUID         `json:"baseline_service_id" gorm:"type:uuid"`
+	CanaryServiceID   uuid.UUID         `json:"canary_service_id" gorm:"type:uuid"`
+	Policy            RolloutPolicy     `json:"policy" gorm:"serializer:json"`
+	PreviousBackends  []EndpointBackend `json:"previous_backends" gorm:"serializer:json"` // 发布前的流量分配，回滚时恢复
+	Step              int               `json:"step"`                                     // 当前步骤在 Policy.Steps 中的下标
+	Status            RolloutStatus     `json:"status" gorm:"index"`
+	StatusMessage     string            `json:"status_message"`
+	LastStats         *RolloutStats     `json:"last_stats,omitempty" gorm:"serializer:json"` // 当前步骤最近一次评估的 canary 统计
+	StepStartedAt     time.Time         `json:"step_started_at"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+	FinishedAt        *time.Time        `json:"finished_at,omitempty"`
+}
+
+// TableName 表名
+func (Rollout) TableName() string {
+	return "inference_rollouts"
+}
+
+// CanaryPercent 当前步骤 canary 的流量百分比
+func (r *Rollout) CanaryPercent() int {
+	return r.Policy.Steps[r.Step]
+}
+
+// Finish 结束发布
+func (r *Rollout) Finish(status RolloutStatus, message string) {
+	now := time.Now()
+	r.Status = status
+	r.StatusMessage = message
+	r.FinishedAt = &now
+}
+
+// CreateEndpointRequest 创建推理端点请求
+type CreateEndpointRequest struct {
+	Name        string            `json:"name" binding:"required,max=255"`
+	Description string            `json:"description" binding:"max=1000"`
+	ProjectID   string            `json:"project_id" binding:"required,uuid"`
+	Backends    []EndpointBackend `json:"backends" binding:"required,min=1,max=10,dive"`
+	HeaderRoute *HeaderRoute      `json:"header_route"`
+	Sticky      bool              `json:"sticky"`
+}
+
+// UpdateEndpointRequest 更新推理端点请求，header_route 的 header 为空时删除请求头路由
+type UpdateEndpointRequest struct {
+	Name        string            `json:"name" binding:"omitempty,max=255"`
+	Description string            `json:"description" binding:"omitempty,max=1000"`
+	Backends    []EndpointBackend `json:"backends" binding:"omitempty,max=10,dive"`
+	HeaderRoute *HeaderRoute      `json:"header_route"`
+	Sticky      *bool             `json:"sticky"`
+}
+
+// ListEndpointsRequest 列出推理端点请求
+type ListEndpointsRequest struct {
+	ProjectID string `form:"project_id" binding:"omitempty,uuid"`
+	Page      int    `form:"page,default=1" binding:"min=1"`
+	PageSize  int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// StartRolloutRequest 开始灰度发布请求，未填写的策略项使用默认值
+type StartRolloutRequest struct {
+	CanaryServiceID     string   `json:"canary_service_id" binding:"required,uuid"`
+	BaselineServiceID   string   `json:"baseline_service_id" binding:"omitempty,uuid"` // 默认为权重最高的其他部署
+	Steps               []int    `json:"steps" binding:"omitempty,max=20,dive,min=1,max=100"`
+	StepIntervalSeconds int      `json:"step_interval_seconds" binding:"omitempty,min=10,max=86400"`
+	MinRequests         *int64   `json:"min_requests" binding:"omitempty,min=0"`
+	MaxErrorRate        *float64 `json:"max_error_rate" binding:"omitempty,min=0,max=1"`
+	MaxP95LatencyMs     float64  `json:"max_p95_latency_ms" binding:"min=0"`
+}
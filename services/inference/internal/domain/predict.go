@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PredictRequest 统一的推理请求，按服务类型转换为 vLLM /v1/completions 或 Triton KServe v2 请求
+type PredictRequest struct {
+	// 文本生成（vllm）
+	Prompt      string   `json:"prompt,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty" binding:"min=0"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+
+	// 张量推理（triton）
+	Model   string        `json:"model,omitempty"` // Triton 模型名，为空时使用服务配置中的 model_name
+	Inputs  []TensorInput `json:"inputs,omitempty" binding:"omitempty,dive"`
+	Outputs []string      `json:"outputs,omitempty"` // 需要返回的输出，为空时返回全部
+
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // 原样传给后端的附加参数
+}
+
+// TensorInput Triton 输入张量
+type TensorInput struct {
+	Name     string      `json:"name" binding:"required"`
+	Shape    []int64     `json:"shape" binding:"required"`
+	Datatype string      `json:"datatype" binding:"required"`
+	Data     interface{} `json:"data" binding:"required"`
+}
+
+// TensorOutput Triton 输出张量
+type TensorOutput struct {
+	Name     string      `json:"name"`
+	Shape    []int64     `json:"shape"`
+	Datatype string      `json:"datatype"`
+	Data     interface{} `json:"data"`
+}
+
+// TokenUsage 文本生成的 token 数
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// PredictResponse 统一的推理响应
+type PredictResponse struct {
+	ServiceID uuid.UUID     `json:"service_id"`
+	Type      InferenceType `json:"type"`
+
+	// vllm
+	Text         string      `json:"text,omitempty"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+	Usage        *TokenUsage `json:"usage,omitempty"`
+
+	// triton
+	ModelName    string         `json:"model_name,omitempty"`
+	ModelVersion string         `json:"model_version,omitempty"`
+	Outputs      []TensorOutput `json:"outputs,omitempty"`
+
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// PredictChunk 流式推理的一段输出，最后一段带有 token 数
+type PredictChunk struct {
+	Text         string      `json:"text"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+	Usage        *TokenUsage `json:"usage,omitempty"`
+}
+
+// RequestRecord 经过推理代理的请求记录
+type RequestRecord struct {
+	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	ServiceID        uuid.UUID  `json:"service_id" gorm:"type:uuid;index:idx_inference_request_service"`
+	ProjectID        uuid.UUID  `json:"project_id" gorm:"type:uuid;index"`
+	UserID           uuid.UUID  `json:"user_id" gorm:"type:uuid;index:idx_inference_request_user"`
+	APIKeyID         *uuid.UUID `json:"api_key_id,omitempty" gorm:"type:uuid"`  // 通过 OpenAI 兼容接口使用 API Key 调用时记录
+	EndpointID       *uuid.UUID `json:"endpoint_id,omitempty" gorm:"type:uuid"` // 经推理端点路由时记录
+	Endpoint         string     `json:"endpoint"`                               // predict、predict/stream 或 OpenAI 接口路径
+	Stream           bool       `json:"stream"`
+	StatusCode       int        `json:"status_code"` // 返回给调用方的 HTTP 状态码
+	Error            string     `json:"error,omitempty"`
+	LatencyMs        int64      `json:"latency_ms"`
+	PromptTokens     int        `json:"prompt_tokens"`
+	CompletionTokens int        `json:"completion_tokens"`
+	TotalTokens      int        `json:"total_tokens"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"index:idx_inference_request_service;index:idx_inference_request_user"`
+}
+
+// TableName 表名
+func (RequestRecord) TableName() string {
+	return "inference_requests"
+}
+
+// ListRequestsRequest 列出请求记录
+type ListRequestsRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=50" binding:"min=1,max=200"`
+}
+
+// UsageRequest 查询用量，时间范围为 [from, to)，默认从本月第一天到现在
+type UsageRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// KeyUsage 单个 API Key 的用量汇总，APIKeyID 为空表示通过平台令牌发起的请求
+type KeyUsage struct {
+	APIKeyID         *uuid.UUID `json:"api_key_id"`
+	Requests         int64      `json:"requests"`
+	Errors           int64      `json:"errors"`
+	PromptTokens     int64      `json:"prompt_tokens"`
+	CompletionTokens int64      `json:"completion_tokens"`
+	TotalTokens      int64      `json:"total_tokens"`
+}
+
+// UsageResponse 用量查询结果
+type UsageResponse struct {
+	From time.Time   `json:"from"`
+	To   time.Time   `json:"to"`
+	Keys []*KeyUsage `json:"keys"`
+}
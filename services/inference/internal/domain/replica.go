@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"time"
+)
+
+// ReplicaStatus 副本状态
+type ReplicaStatus string
+
+const (
+	ReplicaStatusStarting ReplicaStatus = "starting" // 容器已启动，等待健康检查
+	ReplicaStatusReady    ReplicaStatus = "ready"    // 接收请求
+	ReplicaStatusDraining ReplicaStatus = "draining" // 缩容中，不再分配新请求，处理完已有请求后删除
+)
+
+// Replica 推理服务的一个副本容器
+type Replica struct {
+	Index         int           `json:"index"`
+	ContainerID   string        `json:"container_id"`
+	ContainerName string        `json:"container_name"`
+	InternalURL   string        `json:"internal_url"`
+	Status        ReplicaStatus `json:"status"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ReadyAt       *time.Time    `json:"ready_at,omitempty"`
+	DrainingAt    *time.Time    `json:"draining_at,omitempty"`
+}
+
+// ServiceLoad 负载均衡器记录的服务负载
+type ServiceLoad struct {
+	InFlight      int            `json:"in_flight"`       // 正在副本上处理的请求数
+	Queued        int            `json:"queued"`          // 等待可用副本的请求数
+	Replicas      map[string]int `json:"replicas"`        // 容器 ID -> 正在处理的请求数
+	LastRequestAt time.Time      `json:"last_request_at"` // 最近一个请求到达的时间，服务重启后为零值
+}
+
+// defaultTargetInFlight 未设置 TargetInFlight 时每个副本的目标并发请求数
+const defaultTargetInFlight = 8
+
+// ReplicaBounds 副本数范围，MaxReplicas 为 0 的旧记录固定为 1 个副本
+func (s *InferenceService) ReplicaBounds() (int, int) {
+	if s.MaxReplicas <= 0 {
+		return 1, 1
+	}
+	return s.MinReplicas, s.MaxReplicas
+}
+
+// TargetInFlightPerReplica 每个副本的目标并发请求数
+func (s *InferenceService) TargetInFlightPerReplica() int {
+	if s.TargetInFlight <= 0 {
+		return defaultTargetInFlight
+	}
+	return s.TargetInFlight
+}
+
+// ReservedGPUs 服务按最大副本数占用的 GPU 配额
+func (s *InferenceService) ReservedGPUs() int {
+	_, maxReplicas := s.ReplicaBounds()
+	return s.GPUCount * maxReplicas
+}
+
+// ReadyReplicas 可以接收请求的副本
+func (s *InferenceService) ReadyReplicas() []Replica {
+	return s.replicasIn(ReplicaStatusReady)
+}
+
+// ActiveReplicas 就绪和启动中的副本，不含缩容中的副本
+func (s *InferenceService) ActiveReplicas() []Replica {
+	return s.replicasIn(ReplicaStatusStarting, ReplicaStatusReady)
+}
+
+// FindReplica 根据容器 ID 查找副本
+func (s *InferenceService) FindReplica(containerID string) *Replica {
+	for i := range s.Replicas {
+		if s.Replicas[i].ContainerID == containerID {
+			return &s.Replicas[i]
+		}
+	}
+	return nil
+}
+
+// AddReplica 添加副本
+func (s *InferenceService) AddReplica(replica Replica) {
+	s.Replicas = append(s.Replicas, replica)
+	s.countReplicas()
+}
+
+// RemoveReplica 移除副本，副本不存在时返回 false
+func (s *InferenceService) RemoveReplica(containerID string) bool {
+	for i := range s.Replicas {
+		if s.Replicas[i].ContainerID == containerID {
+			s.Replicas = append(s.Replicas[:i], s.Replicas[i+1:]...)
+			s.countReplicas()
+			return true
+		}
+	}
+	return false
+}
+
+// SetReplicaStatus 更新副本状态，副本不存在时返回 false
+func (s *InferenceService) SetReplicaStatus(containerID string, status ReplicaStatus) bool {
+	replica := s.FindReplica(containerID)
+	if replica == nil {
+		return false
+	}
+
+	now := time.Now()
+	replica.Status = status
+	switch status {
+	case ReplicaStatusReady:
+		replica.ReadyAt = &now
+	case ReplicaStatusDraining:
+		replica.DrainingAt = &now
+	}
+	s.countReplicas()
+	return true
+}
+
+// NextReplicaIndex 最小的未使用副本编号
+func (s *InferenceService) NextReplicaIndex() int {
+	used := make(map[int]bool, len(s.Replicas))
+	for _, replica := range s.Replicas {
+		used[replica.Index] = true
+	}
+	index := 0
+	for used[index] {
+		index++
+	}
+	return index
+}
+
+// replicasIn 筛选处于指定状态的副本
+func (s *InferenceService) replicasIn(statuses ...ReplicaStatus) []Replica {
+	var replicas []Replica
+	for _, replica := range s.Replicas {
+		for _, status := range statuses {
+			if replica.Status == status {
+				replicas = append(replicas, replica)
+				break
+			}
+		}
+	}
+	return replicas
+}
+
+// countReplicas 更新就绪副本数
+func (s *InferenceService) countReplicas() {
+	s.CurrentReplicas = len(s.ReadyReplicas())
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
 )
 
 // ServiceStatus 推理服务状态
@@ -28,17 +29,19 @@ const (
 
 // InferenceService 推理服务领域模型
 type InferenceService struct {
-	ID          uuid.UUID       `json:"id"`
+	ID          uuid.UUID       `json:"id" gorm:"type:uuid;primary_key"`
 	Name        string          `json:"name"`
 	Description string          `json:"description"`
-	ProjectID   uuid.UUID       `json:"project_id"`
-	ModelID     uuid.UUID       `json:"model_id"`
-	UserID      uuid.UUID       `json:"user_id"`
+	ProjectID   uuid.UUID       `json:"project_id" gorm:"type:uuid;index"`
+	ModelID     uuid.UUID       `json:"model_id" gorm:"type:uuid"`
+	ModelVersionID *uuid.UUID   `json:"model_version_id,omitempty" gorm:"type:uuid"` // 为空时部署模型记录中的版本
+	UserID      uuid.UUID       `json:"user_id" gorm:"type:uuid"`
 
 	// 推理配置
 	Type        InferenceType           `json:"type"`        // triton, vllm
-	Config      map[string]interface{}  `json:"config"`      // 推理配置
-	Environment map[string]string       `json:"environment"` // 环境变量
+	Config      map[string]interface{}  `json:"config" gorm:"serializer:json"`      // 推理配置
+	Environment map[string]string       `json:"environment" gorm:"serializer:json"` // 环境变量
+	SecretRefs  []secrets.Ref           `json:"secret_refs,omitempty" gorm:"serializer:json"` // 启动时解密注入的项目密钥
 
 	// 资源配置
 	GPUCount    int             `json:"gpu_count"`
@@ -55,14 +58,21 @@ type InferenceService struct {
 	StatusMessage string        `json:"status_message"`
 	HealthStatus  string        `json:"health_status"` // healthy, unhealthy, unknown
 
-	// Docker 执行信息
-	ContainerID   string        `json:"container_id"`
-	ContainerName string        `json:"container_name"`
+	// 副本，负载均衡器在就绪的副本之间分配请求
+	Replicas        []Replica   `json:"replicas" gorm:"serializer:json"`
+	CurrentReplicas int         `json:"current_replicas"` // 就绪的副本数
+	DesiredReplicas int         `json:"desired_replicas"` // 最近一次扩缩容设置的副本数
 	Image         string        `json:"image"`
 
-	// 端点信息
+	// 自动扩缩容
+	MinReplicas    int          `json:"min_replicas"`     // 为 0 时空闲后缩容到零，下一个请求触发冷启动
+	MaxReplicas    int          `json:"max_replicas"`
+	TargetInFlight int          `json:"target_in_flight"` // 每个副本的目标并发请求数
+	MaxInFlight    int          `json:"max_in_flight"`    // 每个副本的并发上限，超出的请求排队，0 表示不限制
+	TargetLatency  int          `json:"target_latency"`   // p95 延迟目标（毫秒），0 表示不按延迟扩容
+
+	// 端点信息，EndpointURL 上的请求由推理服务经负载均衡转发到各副本
 	EndpointURL   string        `json:"endpoint_url"`
-	InternalURL   string        `json:"internal_url"`
 
 	// 时间戳
 	CreatedAt     time.Time     `json:"created_at"`
@@ -120,21 +130,36 @@ type CreateServiceRequest struct {
 	Description string                 `json:"description" binding:"max=1000"`
 	ProjectID   string                 `json:"project_id" binding:"required,uuid"`
 	ModelID     string                 `json:"model_id" binding:"required,uuid"`
+	ModelVersionID string              `json:"model_version_id" binding:"omitempty,uuid"`
 	Type        InferenceType          `json:"type" binding:"required,oneof=triton vllm"`
 	Config      map[string]interface{} `json:"config"`
 	Environment map[string]string      `json:"environment"`
+	SecretRefs  []secrets.Ref          `json:"secret_refs" binding:"omitempty,dive"`
 	GPUCount    int                    `json:"gpu_count" binding:"min=0,max=8"`
 	GPUType     string                 `json:"gpu_type" binding:"max=50"`
 	CPUCount    int                    `json:"cpu_count" binding:"min=1,max=64"`
 	MemoryGB    int                    `json:"memory_gb" binding:"min=1,max=256"`
+
+	// 自动扩缩容，min_replicas 默认为 1，max_replicas 默认为 min_replicas 和 1 中的较大者
+	MinReplicas    *int `json:"min_replicas" binding:"omitempty,min=0,max=16"`
+	MaxReplicas    int  `json:"max_replicas" binding:"omitempty,min=1,max=16"`
+	TargetInFlight int  `json:"target_in_flight" binding:"omitempty,min=1,max=1000"`
+	MaxInFlight    int  `json:"max_in_flight" binding:"omitempty,min=1,max=10000"`
+	TargetLatency  int  `json:"target_latency" binding:"omitempty,min=1,max=600000"`
 }
 
-// UpdateServiceRequest 更新推理服务请求
+// UpdateServiceRequest 更新推理服务请求，扩缩容设置立即生效，max_in_flight 和 target_latency 为 0 时取消限制
 type UpdateServiceRequest struct {
 	Name        string                 `json:"name" binding:"omitempty,max=255"`
 	Description string                 `json:"description" binding:"omitempty,max=1000"`
 	Config      map[string]interface{} `json:"config"`
 	Environment map[string]string      `json:"environment"`
+
+	MinReplicas    *int `json:"min_replicas" binding:"omitempty,min=0,max=16"`
+	MaxReplicas    *int `json:"max_replicas" binding:"omitempty,min=1,max=16"`
+	TargetInFlight *int `json:"target_in_flight" binding:"omitempty,min=1,max=1000"`
+	MaxInFlight    *int `json:"max_in_flight" binding:"omitempty,min=0,max=10000"`
+	TargetLatency  *int `json:"target_latency" binding:"omitempty,min=0,max=600000"`
 }
 
 // ListServicesRequest 列出推理服务请求
@@ -152,50 +177,70 @@ type ServiceResponse struct {
 	Description   string                 `json:"description"`
 	ProjectID     uuid.UUID              `json:"project_id"`
 	ModelID       uuid.UUID              `json:"model_id"`
+	ModelVersionID *uuid.UUID            `json:"model_version_id,omitempty"`
 	UserID        uuid.UUID              `json:"user_id"`
 	Type          InferenceType          `json:"type"`
 	Status        ServiceStatus          `json:"status"`
 	StatusMessage string                 `json:"status_message,omitempty"`
 	HealthStatus  string                 `json:"health_status"`
 	GPUCount      int                    `json:"gpu_count"`
-	ContainerID   string                 `json:"container_id,omitempty"`
-	ContainerName string                 `json:"container_name,omitempty"`
 	Image         string                 `json:"image,omitempty"`
 	EndpointURL   string                 `json:"endpoint_url,omitempty"`
-	InternalURL   string                 `json:"internal_url,omitempty"`
 	HostPort      int                    `json:"host_port,omitempty"`
+	Replicas        []Replica            `json:"replicas"`
+	CurrentReplicas int                  `json:"current_replicas"`
+	DesiredReplicas int                  `json:"desired_replicas"`
+	MinReplicas     int                  `json:"min_replicas"`
+	MaxReplicas     int                  `json:"max_replicas"`
+	TargetInFlight  int                  `json:"target_in_flight"`
+	MaxInFlight     int                  `json:"max_in_flight,omitempty"`
+	TargetLatency   int                  `json:"target_latency,omitempty"`
 	CreatedAt     time.Time              `json:"created_at"`
 	UpdatedAt     time.Time              `json:"updated_at"`
 	StartedAt     *time.Time             `json:"started_at,omitempty"`
 	StoppedAt     *time.Time             `json:"stopped_at,omitempty"`
 	Config        map[string]interface{} `json:"config,omitempty"`
+	SecretRefs    []secrets.Ref          `json:"secret_refs,omitempty"`
 }
 
 // ToResponse 转换为响应
 func (s *InferenceService) ToResponse() *ServiceResponse {
+	minReplicas, maxReplicas := s.ReplicaBounds()
+	replicas := s.Replicas
+	if replicas == nil {
+		replicas = []Replica{}
+	}
+
 	return &ServiceResponse{
 		ID:            s.ID,
 		Name:          s.Name,
 		Description:   s.Description,
 		ProjectID:     s.ProjectID,
 		ModelID:       s.ModelID,
+		ModelVersionID: s.ModelVersionID,
 		UserID:        s.UserID,
 		Type:          s.Type,
 		Status:        s.Status,
 		StatusMessage: s.StatusMessage,
 		HealthStatus:  s.HealthStatus,
 		GPUCount:      s.GPUCount,
-		ContainerID:   s.ContainerID,
-		ContainerName: s.ContainerName,
 		Image:         s.Image,
 		EndpointURL:   s.EndpointURL,
-		InternalURL:   s.InternalURL,
 		HostPort:      s.HostPort,
+		Replicas:        replicas,
+		CurrentReplicas: s.CurrentReplicas,
+		DesiredReplicas: s.DesiredReplicas,
+		MinReplicas:     minReplicas,
+		MaxReplicas:     maxReplicas,
+		TargetInFlight:  s.TargetInFlightPerReplica(),
+		MaxInFlight:     s.MaxInFlight,
+		TargetLatency:   s.TargetLatency,
 		CreatedAt:     s.CreatedAt,
 		UpdatedAt:     s.UpdatedAt,
 		StartedAt:     s.StartedAt,
 		StoppedAt:     s.StoppedAt,
 		Config:        s.Config,
+		SecretRefs:    s.SecretRefs,
 	}
 }
 
@@ -209,12 +254,22 @@ type StopServiceRequest struct {
 	Force bool `json:"force"` // 是否强制停止
 }
 
+// 推理服务事件类型
+const (
+	ServiceEventStarted = "started" // 第一个副本通过健康检查，服务转为 running
+	ServiceEventStopped = "stopped"
+	ServiceEventFailed  = "failed" // 部署、健康检查或停止失败，服务转为 error
+	ServiceEventScaled  = "scaled" // 运行中服务的副本数变化，包括冷启动
+)
+
 // ServiceEvent 推理服务事件
 type ServiceEvent struct {
-	Type      string        `json:"type"` // created, started, stopped, error
+	Type      string        `json:"type"` // started, stopped, failed, scaled
 	ServiceID uuid.UUID     `json:"service_id"`
+	ProjectID uuid.UUID     `json:"project_id"`
 	Status    ServiceStatus `json:"status"`
 	Message   string        `json:"message"`
+	Replicas  int           `json:"replicas"` // 期望副本数
 	Timestamp time.Time     `json:"timestamp"`
 }
 
@@ -241,8 +296,17 @@ type PortBinding struct {
 // ModelInfo 模型信息
 type ModelInfo struct {
 	ID          uuid.UUID `json:"id"`
+	ProjectID   uuid.UUID `json:"project_id"`
 	Name        string    `json:"name"`
 	Version     string    `json:"version"`
 	StoragePath string    `json:"storage_path"`
 	Format      string    `json:"format"` // triton, pytorch, safetensors, etc.
 }
+
+// ModelVersionInfo 模型版本信息
+type ModelVersionInfo struct {
+	ID          uuid.UUID `json:"id"`
+	ModelID     uuid.UUID `json:"model_id"`
+	Version     string    `json:"version"`
+	StoragePath string    `json:"storage_path"`
+}
@@ -0,0 +1,358 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/service"
+)
+
+// EndpointHandler 推理端点处理器，调用方必须能访问端点的项目及其引用的每个部署
+type EndpointHandler struct {
+	service     service.EndpointService
+	services    service.InferenceService
+	projectRepo repository.ProjectRepository
+}
+
+// NewEndpointHandler 创建推理端点处理器
+func NewEndpointHandler(service service.EndpointService, services service.InferenceService, projectRepo repository.ProjectRepository) *EndpointHandler {
+	return &EndpointHandler{service: service, services: services, projectRepo: projectRepo}
+}
+
+// RegisterRoutes 注册路由
+func (h *EndpointHandler) RegisterRoutes(router *gin.RouterGroup) {
+	endpoints := router.Group("/inference/endpoints")
+	{
+		endpoints.POST("", h.Create)
+		endpoints.GET("", h.List)
+		endpoints.GET("/:id", h.Get)
+		endpoints.PATCH("/:id", h.Update)
+		endpoints.DELETE("/:id", h.Delete)
+		endpoints.POST("/:id/rollouts", h.StartRollout)
+		endpoints.GET("/:id/rollouts", h.ListRollouts)
+		endpoints.GET("/:id/rollouts/:rollout_id", h.GetRollout)
+		endpoints.POST("/:id/rollouts/:rollout_id/promote", h.PromoteRollout)
+		endpoints.POST("/:id/rollouts/:rollout_id/rollback", h.RollbackRollout)
+	}
+}
+
+// Create 创建推理端点
+func (h *EndpointHandler) Create(c *gin.Context) {
+	var req domain.CreateEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+	if !h.checkBackends(c, userID, backendIDs(req.Backends, req.HeaderRoute), false) {
+		return
+	}
+
+	endpoint, err := h.service.CreateEndpoint(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, endpoint)
+}
+
+// List 列出推理端点
+func (h *EndpointHandler) List(c *gin.Context) {
+	var req domain.ListEndpointsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	// 不带项目时会列出所有项目的端点
+	if req.ProjectID == "" {
+		response.Error(c, http.StatusBadRequest, "project_id is required")
+		return
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	endpoints, total, err := h.service.ListEndpoints(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.SuccessWithMeta(c, endpoints, pageMeta(req.Page, req.PageSize, total))
+}
+
+// Get 获取推理端点
+func (h *EndpointHandler) Get(c *gin.Context) {
+	endpoint, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	response.Success(c, endpoint)
+}
+
+// Update 更新推理端点
+func (h *EndpointHandler) Update(c *gin.Context) {
+	var req domain.UpdateEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	current, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	if !h.checkBackends(c, userID, backendIDs(req.Backends, req.HeaderRoute), false) {
+		return
+	}
+
+	endpoint, err := h.service.UpdateEndpoint(c.Request.Context(), current.ID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, endpoint)
+}
+
+// Delete 删除推理端点，不影响端点后面的部署
+func (h *EndpointHandler) Delete(c *gin.Context) {
+	current, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEndpoint(c.Request.Context(), current.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Endpoint deleted successfully"})
+}
+
+// StartRollout 开始灰度发布
+func (h *EndpointHandler) StartRollout(c *gin.Context) {
+	var req domain.StartRolloutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	current, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	var ids []uuid.UUID
+	for _, raw := range []string{req.CanaryServiceID, req.BaselineServiceID} {
+		if id, err := uuid.Parse(raw); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if !h.checkBackends(c, userID, ids, false) {
+		return
+	}
+
+	rollout, err := h.service.StartRollout(c.Request.Context(), current.ID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, rollout)
+}
+
+// ListRollouts 列出端点的发布记录
+func (h *EndpointHandler) ListRollouts(c *gin.Context) {
+	current, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	rollouts, err := h.service.ListRollouts(c.Request.Context(), current.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, rollouts)
+}
+
+// GetRollout 获取发布记录
+func (h *EndpointHandler) GetRollout(c *gin.Context) {
+	rid, ok := rolloutID(c)
+	if !ok {
+		return
+	}
+	current, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	rollout, err := h.service.GetRollout(c.Request.Context(), current.ID, rid)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, rollout)
+}
+
+// PromoteRollout 跳过剩余步骤，把全部流量切换到 canary
+func (h *EndpointHandler) PromoteRollout(c *gin.Context) {
+	rid, ok := rolloutID(c)
+	if !ok {
+		return
+	}
+	current, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	rollout, err := h.service.PromoteRollout(c.Request.Context(), current.ID, rid)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, rollout)
+}
+
+// RollbackRollout 手动回滚，恢复发布前的流量分配
+func (h *EndpointHandler) RollbackRollout(c *gin.Context) {
+	rid, ok := rolloutID(c)
+	if !ok {
+		return
+	}
+	current, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	rollout, err := h.service.RollbackRollout(c.Request.Context(), current.ID, rid)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, rollout)
+}
+
+// authorize 获取路径中的端点，检查调用方能否访问端点的项目和端点当前的全部部署，失败时写入错误响应
+func (h *EndpointHandler) authorize(c *gin.Context) (*domain.InferenceEndpoint, uuid.UUID, bool) {
+	id, ok := endpointID(c)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	endpoint, err := h.service.GetEndpoint(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return nil, uuid.Nil, false
+	}
+	if !checkProject(c, h.projectRepo, userID, endpoint.ProjectID) {
+		return nil, uuid.Nil, false
+	}
+	// 已删除的部署不再阻止管理端点，例如把它从端点中移除
+	if !h.checkBackends(c, userID, backendIDs(endpoint.Backends, endpoint.HeaderRoute), true) {
+		return nil, uuid.Nil, false
+	}
+	return endpoint, userID, true
+}
+
+// checkBackends 检查调用方能否访问每个部署所属的项目，allowMissing 为 false 时不存在的部署返回 400
+func (h *EndpointHandler) checkBackends(c *gin.Context, userID uuid.UUID, ids []uuid.UUID, allowMissing bool) bool {
+	checked := make(map[uuid.UUID]bool)
+	for _, id := range ids {
+		if checked[id] {
+			continue
+		}
+		checked[id] = true
+
+		svc, err := h.services.GetService(c.Request.Context(), id)
+		if err != nil {
+			if !errors.Is(err, apperrors.ErrNotFound) {
+				respondError(c, err)
+				return false
+			}
+			if allowMissing {
+				continue
+			}
+			respondError(c, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("inference service not found: %s", id)))
+			return false
+		}
+		if !checkProject(c, h.projectRepo, userID, svc.ProjectID) {
+			return false
+		}
+	}
+	return true
+}
+
+// backendIDs 返回部署列表和请求头路由引用的全部部署 ID
+func backendIDs(backends []domain.EndpointBackend, route *domain.HeaderRoute) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(backends))
+	for _, backend := range backends {
+		ids = append(ids, backend.ServiceID)
+	}
+	if route != nil {
+		for _, id := range route.Values {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// endpointID 解析路径中的端点 ID，无效时写入错误响应
+func endpointID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid endpoint ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// rolloutID 解析路径中的发布 ID，无效时写入错误响应
+func rolloutID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("rollout_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid rollout ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
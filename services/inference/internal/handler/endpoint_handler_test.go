@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/service"
+)
+
+// fakeEndpointService 只保存一个端点，记录调用过的方法
+type fakeEndpointService struct {
+	service.EndpointService
+
+	endpoint *domain.InferenceEndpoint
+	calls    []string
+}
+
+func (s *fakeEndpointService) record(name string) {
+	s.calls = append(s.calls, name)
+}
+
+func (s *fakeEndpointService) CreateEndpoint(ctx context.Context, userID uuid.UUID, req *domain.CreateEndpointRequest) (*domain.InferenceEndpoint, error) {
+	s.record("create")
+	return s.endpoint, nil
+}
+
+func (s *fakeEndpointService) GetEndpoint(ctx context.Context, id uuid.UUID) (*domain.InferenceEndpoint, error) {
+	return s.endpoint, nil
+}
+
+func (s *fakeEndpointService) ListEndpoints(ctx context.Context, req *domain.ListEndpointsRequest) ([]*domain.InferenceEndpoint, int64, error) {
+	s.record("list")
+	return []*domain.InferenceEndpoint{s.endpoint}, 1, nil
+}
+
+func (s *fakeEndpointService) UpdateEndpoint(ctx context.Context, id uuid.UUID, req *domain.UpdateEndpointRequest) (*domain.InferenceEndpoint, error) {
+	s.record("update")
+	return s.endpoint, nil
+}
+
+func (s *fakeEndpointService) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	s.record("delete")
+	return nil
+}
+
+func (s *fakeEndpointService) StartRollout(ctx context.Context, endpointID uuid.UUID, req *domain.StartRolloutRequest) (*domain.Rollout, error) {
+	s.record("rollout")
+	return &domain.Rollout{ID: uuid.New(), EndpointID: endpointID}, nil
+}
+
+func (s *fakeEndpointService) ListRollouts(ctx context.Context, endpointID uuid.UUID) ([]*domain.Rollout, error) {
+	s.record("rollouts")
+	return nil, nil
+}
+
+func (s *fakeEndpointService) RollbackRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error) {
+	s.record("rollback")
+	return &domain.Rollout{ID: rolloutID, EndpointID: endpointID}, nil
+}
+
+// fakeBackendServices 按 ID 查询部署，不存在时返回 404
+type fakeBackendServices struct {
+	service.InferenceService
+	services map[uuid.UUID]*domain.InferenceService
+}
+
+func (s *fakeBackendServices) GetService(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error) {
+	svc, ok := s.services[id]
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("inference service not found: %s", id))
+	}
+	return svc, nil
+}
+
+func TestEndpointHandlerProjectAccess(t *testing.T) {
+	project, otherProject := uuid.New(), uuid.New()
+	own := &domain.InferenceService{ID: uuid.New(), ProjectID: project}
+	canary := &domain.InferenceService{ID: uuid.New(), ProjectID: project}
+	foreign := &domain.InferenceService{ID: uuid.New(), ProjectID: otherProject}
+	endpoint := &domain.InferenceEndpoint{
+		ID:        uuid.New(),
+		ProjectID: project,
+		Backends:  []domain.EndpointBackend{{ServiceID: own.ID, Weight: 100}},
+	}
+	endpointPath := "/api/v1/inference/endpoints/" + endpoint.ID.String()
+
+	createBody := func(serviceID uuid.UUID) string {
+		return fmt.Sprintf(`{"name":"chat","project_id":"%s","backends":[{"service_id":"%s","weight":100}]}`, project, serviceID)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		allowed    bool
+		wantStatus int
+		wantCall   string
+	}{
+		{"create allowed", http.MethodPost, "/api/v1/inference/endpoints", createBody(own.ID), true, http.StatusCreated, "create"},
+		{"create forbidden", http.MethodPost, "/api/v1/inference/endpoints", createBody(own.ID), false, http.StatusForbidden, ""},
+		{"create with foreign backend", http.MethodPost, "/api/v1/inference/endpoints", createBody(foreign.ID), true, http.StatusForbidden, ""},
+		{"create with unknown backend", http.MethodPost, "/api/v1/inference/endpoints", createBody(uuid.New()), true, http.StatusBadRequest, ""},
+		{"list without project", http.MethodGet, "/api/v1/inference/endpoints", "", true, http.StatusBadRequest, ""},
+		{"list allowed", http.MethodGet, "/api/v1/inference/endpoints?project_id=" + project.String(), "", true, http.StatusOK, "list"},
+		{"list forbidden", http.MethodGet, "/api/v1/inference/endpoints?project_id=" + project.String(), "", false, http.StatusForbidden, ""},
+		{"get allowed", http.MethodGet, endpointPath, "", true, http.StatusOK, ""},
+		{"get forbidden", http.MethodGet, endpointPath, "", false, http.StatusForbidden, ""},
+		{"update with foreign backend", http.MethodPatch, endpointPath, fmt.Sprintf(`{"backends":[{"service_id":"%s","weight":100}]}`, foreign.ID), true, http.StatusForbidden, ""},
+		{"update allowed", http.MethodPatch, endpointPath, `{"name":"chat-v2"}`, true, http.StatusOK, "update"},
+		{"delete forbidden", http.MethodDelete, endpointPath, "", false, http.StatusForbidden, ""},
+		{"delete allowed", http.MethodDelete, endpointPath, "", true, http.StatusOK, "delete"},
+		{"rollout allowed", http.MethodPost, endpointPath + "/rollouts", fmt.Sprintf(`{"canary_service_id":"%s"}`, canary.ID), true, http.StatusCreated, "rollout"},
+		{"rollout with foreign canary", http.MethodPost, endpointPath + "/rollouts", fmt.Sprintf(`{"canary_service_id":"%s"}`, foreign.ID), true, http.StatusForbidden, ""},
+		{"rollouts forbidden", http.MethodGet, endpointPath + "/rollouts", "", false, http.StatusForbidden, ""},
+		{"rollback forbidden", http.MethodPost, endpointPath + "/rollouts/" + uuid.NewString() + "/rollback", "", false, http.StatusForbidden, ""},
+		{"rollback allowed", http.MethodPost, endpointPath + "/rollouts/" + uuid.NewString() + "/rollback", "", true, http.StatusOK, "rollback"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := &fakeEndpointService{endpoint: endpoint}
+			backends := &fakeBackendServices{services: map[uuid.UUID]*domain.InferenceService{
+				own.ID: own, canary.ID: canary, foreign.ID: foreign,
+			}}
+			projects := &fakeProjectRepo{allowed: map[uuid.UUID]bool{project: tt.allowed}}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewEndpointHandler(endpoints, backends, projects).RegisterRoutes(router.Group("/api/v1"))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-ID", uuid.New().String())
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			var wantCalls []string
+			if tt.wantCall != "" {
+				wantCalls = []string{tt.wantCall}
+			}
+			if strings.Join(endpoints.calls, ",") != strings.Join(wantCalls, ",") {
+				t.Errorf("calls = %v, want %v", endpoints.calls, wantCalls)
+			}
+		})
+	}
+}
+
+func TestEndpointHandlerIgnoresDeletedBackends(t *testing.T) {
+	project := uuid.New()
+	endpoint := &domain.InferenceEndpoint{
+		ID:        uuid.New(),
+		ProjectID: project,
+		Backends:  []domain.EndpointBackend{{ServiceID: uuid.New(), Weight: 100}},
+	}
+	endpoints := &fakeEndpointService{endpoint: endpoint}
+	backends := &fakeBackendServices{services: map[uuid.UUID]*domain.InferenceService{}}
+	projects := &fakeProjectRepo{allowed: map[uuid.UUID]bool{project: true}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewEndpointHandler(endpoints, backends, projects).RegisterRoutes(router.Group("/api/v1"))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/inference/endpoints/"+endpoint.ID.String(), nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/proxy"
+)
+
+// maxOpenAIBody OpenAI 兼容请求体的最大字节数
+const maxOpenAIBody = 32 << 20
+
+// OpenAIHandler OpenAI 兼容接口处理器，错误按 OpenAI 格式返回以便现有客户端解析
+type OpenAIHandler struct {
+	proxy proxy.Proxy
+}
+
+// NewOpenAIHandler 创建 OpenAI 兼容接口处理器
+func NewOpenAIHandler(proxy proxy.Proxy) *OpenAIHandler {
+	return &OpenAIHandler{proxy: proxy}
+}
+
+// RegisterRoutes 注册路由，router 为 /v1 路由组
+func (h *OpenAIHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/models", h.ListModels)
+	router.POST("/chat/completions", h.Forward)
+	router.POST("/completions", h.Forward)
+	router.POST("/embeddings", h.Forward)
+}
+
+// ListModels 以 OpenAI 模型列表格式返回可用的 vllm 服务
+func (h *OpenAIHandler) ListModels(c *gin.Context) {
+	userID, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	services, err := h.proxy.Models(c.Request.Context(), userID)
+	if err != nil {
+		openAIError(c, err)
+		return
+	}
+
+	models := make([]gin.H, len(services))
+	for i, svc := range services {
+		created := svc.CreatedAt
+		if svc.StartedAt != nil {
+			created = *svc.StartedAt
+		}
+		models[i] = gin.H{
+			"id":       svc.Name,
+			"object":   "model",
+			"created":  created.Unix(),
+			"owned_by": svc.ProjectID.String(),
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"object": "list", "data": models})
+}
+
+// Forward 将请求转发到 model 对应的 vllm 服务
+func (h *OpenAIHandler) Forward(c *gin.Context) {
+	userID, ok := h.caller(c)
+	if !ok {
+		return
+	}
+
+	req := &proxy.OpenAIRequest{UserID: userID, Path: c.FullPath()}
+	if header := c.GetHeader("X-API-Key-ID"); header != "" {
+		keyID, err := uuid.Parse(header)
+		if err != nil {
+			openAIError(c, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid X-API-Key-ID header"))
+			return
+		}
+		req.APIKeyID = &keyID
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxOpenAIBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			openAIError(c, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxOpenAIBody)))
+			return
+		}
+		openAIError(c, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err)))
+		return
+	}
+	req.Body = body
+
+	if err := h.proxy.OpenAI(c.Request.Context(), req, c.Writer); err != nil {
+		openAIError(c, err)
+	}
+}
+
+// caller 获取网关转发的用户 ID
+func (h *OpenAIHandler) caller(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		openAIError(c, apperrors.Wrap(apperrors.ErrUnauthorized, http.StatusUnauthorized, "missing or invalid caller identity"))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// openAIError 以 OpenAI 错误格式返回错误
+func openAIError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	message := err.Error()
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		status = appErr.Code
+		message = appErr.Message
+	}
+
+	errType := "invalid_request_error"
+	var code interface{}
+	switch {
+	case status == http.StatusUnauthorized:
+		errType = "authentication_error"
+	case status == http.StatusForbidden:
+		errType = "permission_error"
+	case status == http.StatusNotFound:
+		code = "model_not_found"
+	case status >= http.StatusInternalServerError:
+		errType = "server_error"
+	}
+
+	c.JSON(status, gin.H{"error": gin.H{
+		"message": message,
+		"type":    errType,
+		"param":   nil,
+		"code":    code,
+	}})
+}
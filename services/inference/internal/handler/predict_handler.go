@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/proxy"
+)
+
+// PredictHandler 推理请求处理器
+type PredictHandler struct {
+	proxy proxy.Proxy
+}
+
+// NewPredictHandler 创建推理请求处理器
+func NewPredictHandler(proxy proxy.Proxy) *PredictHandler {
+	return &PredictHandler{proxy: proxy}
+}
+
+// RegisterRoutes 注册路由
+func (h *PredictHandler) RegisterRoutes(router *gin.RouterGroup) {
+	services := router.Group("/inference/services/:id")
+	{
+		services.POST("/predict", h.Predict)
+		services.POST("/predict/stream", h.Stream)
+		services.GET("/requests", h.ListRequests)
+	}
+	endpoints := router.Group("/inference/endpoints/:id")
+	{
+		endpoints.POST("/predict", h.PredictEndpoint)
+		endpoints.POST("/predict/stream", h.StreamEndpoint)
+	}
+	router.GET("/inference/usage", h.Usage)
+}
+
+// Predict 转发推理请求
+func (h *PredictHandler) Predict(c *gin.Context) {
+	id, ok := serviceID(c)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.PredictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	result, err := h.proxy.Predict(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Stream 以 SSE 转发流式推理输出
+func (h *PredictHandler) Stream(c *gin.Context) {
+	id, ok := serviceID(c)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.PredictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	writeStream(c, func(emit func(*domain.PredictChunk) error) error {
+		return h.proxy.Stream(c.Request.Context(), userID, id, &req, emit)
+	})
+}
+
+// PredictEndpoint 经推理端点转发推理请求
+func (h *PredictHandler) PredictEndpoint(c *gin.Context) {
+	id, ok := endpointID(c)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.PredictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	result, err := h.proxy.PredictEndpoint(c.Request.Context(), userID, id, c.Request.Header, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// StreamEndpoint 经推理端点以 SSE 转发流式推理输出
+func (h *PredictHandler) StreamEndpoint(c *gin.Context) {
+	id, ok := endpointID(c)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.PredictRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	writeStream(c, func(emit func(*domain.PredictChunk) error) error {
+		return h.proxy.StreamEndpoint(c.Request.Context(), userID, id, c.Request.Header, &req, emit)
+	})
+}
+
+// writeStream 以 SSE 输出 run 产生的每段结果，以 data: [DONE] 结束，开始输出后的错误以 error 事件返回
+func writeStream(c *gin.Context, run func(emit func(*domain.PredictChunk) error) error) {
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Status(http.StatusOK)
+	}
+	write := func(event string, data []byte) error {
+		if event != "" {
+			if _, err := fmt.Fprintf(c.Writer, "event: %s\n", event); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	}
+
+	err := run(func(chunk *domain.PredictChunk) error {
+		start()
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		return write("", data)
+	})
+	if err != nil {
+		if !started {
+			respondError(c, err)
+			return
+		}
+		data, _ := json.Marshal(gin.H{"message": err.Error()})
+		write("error", data)
+		return
+	}
+
+	start()
+	write("", []byte("[DONE]"))
+}
+
+// ListRequests 列出服务的请求记录
+func (h *PredictHandler) ListRequests(c *gin.Context) {
+	id, ok := serviceID(c)
+	if !ok {
+		return
+	}
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.ListRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	records, total, err := h.proxy.ListRequests(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.SuccessWithMeta(c, records, pageMeta(req.Page, req.PageSize, total))
+}
+
+// Usage 按 API Key 汇总调用方的用量
+func (h *PredictHandler) Usage(c *gin.Context) {
+	userID, ok := callerID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UsageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	usage, err := h.proxy.Usage(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, usage)
+}
+
+// callerID 获取网关认证后转发的用户 ID，推理请求不使用默认用户
+func callerID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Missing or invalid caller identity")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
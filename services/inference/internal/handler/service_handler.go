@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/service"
+)
+
+// ServiceHandler 推理服务处理器
+type ServiceHandler struct {
+	service     service.InferenceService
+	projectRepo repository.ProjectRepository
+}
+
+// NewServiceHandler 创建推理服务处理器
+func NewServiceHandler(service service.InferenceService, projectRepo repository.ProjectRepository) *ServiceHandler {
+	return &ServiceHandler{service: service, projectRepo: projectRepo}
+}
+
+// RegisterRoutes 注册路由
+func (h *ServiceHandler) RegisterRoutes(router *gin.RouterGroup) {
+	services := router.Group("/inference/services")
+	{
+		services.POST("", h.Create)
+		services.GET("", h.List)
+		services.GET("/:id", h.Get)
+		services.PATCH("/:id", h.Update)
+		services.DELETE("/:id", h.Delete)
+		services.POST("/:id/start", h.Start)
+		services.POST("/:id/stop", h.Stop)
+	}
+}
+
+// Create 创建推理服务
+func (h *ServiceHandler) Create(c *gin.Context) {
+	var req domain.CreateServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	svc, err := h.service.CreateService(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, svc.ToResponse())
+}
+
+// List 列出推理服务
+func (h *ServiceHandler) List(c *gin.Context) {
+	var req domain.ListServicesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	// 不带项目时会列出所有项目的服务
+	if req.ProjectID == "" {
+		response.Error(c, http.StatusBadRequest, "project_id is required")
+		return
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	services, total, err := h.service.ListServices(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	responses := make([]*domain.ServiceResponse, len(services))
+	for i, svc := range services {
+		responses[i] = svc.ToResponse()
+	}
+
+	response.SuccessWithMeta(c, responses, pageMeta(req.Page, req.PageSize, total))
+}
+
+// Get 获取推理服务
+func (h *ServiceHandler) Get(c *gin.Context) {
+	svc, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	response.Success(c, svc.ToResponse())
+}
+
+// Update 更新推理服务
+func (h *ServiceHandler) Update(c *gin.Context) {
+	current, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	svc, err := h.service.UpdateService(c.Request.Context(), current.ID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, svc.ToResponse())
+}
+
+// Delete 删除推理服务
+func (h *ServiceHandler) Delete(c *gin.Context) {
+	svc, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteService(c.Request.Context(), svc.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Service deleted successfully"})
+}
+
+// Start 启动推理服务，请求体可省略
+func (h *ServiceHandler) Start(c *gin.Context) {
+	current, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req domain.StartServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	svc, err := h.service.StartService(c.Request.Context(), current.ID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, svc.ToResponse())
+}
+
+// Stop 停止推理服务，请求体可省略
+func (h *ServiceHandler) Stop(c *gin.Context) {
+	current, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	var req domain.StopServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	svc, err := h.service.StopService(c.Request.Context(), current.ID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, svc.ToResponse())
+}
+
+// authorize 获取路径中的服务并检查调用方能否访问其所属项目，失败时写入错误响应
+func (h *ServiceHandler) authorize(c *gin.Context) (*domain.InferenceService, bool) {
+	id, ok := serviceID(c)
+	if !ok {
+		return nil, false
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	svc, err := h.service.GetService(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	if !checkProject(c, h.projectRepo, userID, svc.ProjectID) {
+		return nil, false
+	}
+	return svc, true
+}
+
+// checkProject 检查调用方能否访问项目，无权访问时写入 403 响应
+func checkProject(c *gin.Context, projects repository.ProjectRepository, userID, projectID uuid.UUID) bool {
+	ok, err := projects.CanAccess(c.Request.Context(), userID, projectID)
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+	if !ok {
+		respondError(c, apperrors.Wrap(apperrors.ErrForbidden, http.StatusForbidden, fmt.Sprintf("no access to project %s", projectID)))
+		return false
+	}
+	return true
+}
+
+// serviceID 解析路径中的服务 ID，无效时写入错误响应
+func serviceID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid service ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// contextUserID 获取网关转发的用户 ID
+func contextUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr := c.GetHeader("X-User-ID")
+	if userIDStr == "" {
+		// 临时使用默认用户 ID
+		userIDStr = "00000000-0000-0000-0000-000000000001"
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user_id")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// pageMeta 构造分页信息
+func pageMeta(page, pageSize int, total int64) *response.MetaInfo {
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &response.MetaInfo{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+}
+
+// respondError 按错误类型写入响应
+func respondError(c *gin.Context, err error) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		response.AppError(c, appErr)
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/service"
+)
+
+// fakeInferenceService 只实现查询和删除，其余方法未实现
+type fakeInferenceService struct {
+	service.InferenceService
+
+	svc     *domain.InferenceService
+	deleted []uuid.UUID
+}
+
+func (s *fakeInferenceService) GetService(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error) {
+	return s.svc, nil
+}
+
+func (s *fakeInferenceService) DeleteService(ctx context.Context, id uuid.UUID) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+// fakeProjectRepo 只允许访问 allowed 中的项目
+type fakeProjectRepo struct {
+	allowed map[uuid.UUID]bool
+}
+
+func (r *fakeProjectRepo) CanAccess(ctx context.Context, userID, projectID uuid.UUID) (bool, error) {
+	return r.allowed[projectID], nil
+}
+
+func newTestServiceRouter(services *fakeInferenceService, projects *fakeProjectRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewServiceHandler(services, projects).RegisterRoutes(router.Group("/api/v1"))
+	return router
+}
+
+func TestServiceHandlerProjectAccess(t *testing.T) {
+	svc := &domain.InferenceService{ID: uuid.New(), ProjectID: uuid.New(), Status: domain.ServiceStatusStopped}
+	otherProject := uuid.New()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		allowed     bool
+		wantStatus  int
+		wantDeleted bool
+	}{
+		{"get allowed", http.MethodGet, "/api/v1/inference/services/" + svc.ID.String(), true, http.StatusOK, false},
+		{"get forbidden", http.MethodGet, "/api/v1/inference/services/" + svc.ID.String(), false, http.StatusForbidden, false},
+		{"delete allowed", http.MethodDelete, "/api/v1/inference/services/" + svc.ID.String(), true, http.StatusOK, true},
+		{"delete forbidden", http.MethodDelete, "/api/v1/inference/services/" + svc.ID.String(), false, http.StatusForbidden, false},
+		{"start forbidden", http.MethodPost, "/api/v1/inference/services/" + svc.ID.String() + "/start", false, http.StatusForbidden, false},
+		{"stop forbidden", http.MethodPost, "/api/v1/inference/services/" + svc.ID.String() + "/stop", false, http.StatusForbidden, false},
+		{"update forbidden", http.MethodPatch, "/api/v1/inference/services/" + svc.ID.String(), false, http.StatusForbidden, false},
+		{"list without project", http.MethodGet, "/api/v1/inference/services", true, http.StatusBadRequest, false},
+		{"list other project", http.MethodGet, "/api/v1/inference/services?project_id=" + otherProject.String(), true, http.StatusForbidden, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := &fakeInferenceService{svc: svc}
+			projects := &fakeProjectRepo{allowed: map[uuid.UUID]bool{svc.ProjectID: tt.allowed}}
+			router := newTestServiceRouter(services, projects)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("X-User-ID", uuid.New().String())
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if deleted := len(services.deleted) > 0; deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// queuePollInterval 排队请求重新读取服务副本的间隔
+const queuePollInterval = time.Second
+
+// Waker 为缩容到零的服务启动副本
+type Waker interface {
+	Wake(ctx context.Context, serviceID uuid.UUID) error
+}
+
+// balancer 在服务的就绪副本之间分配请求，记录每个副本正在处理的请求数
+type balancer struct {
+	mu       sync.Mutex
+	services map[uuid.UUID]*serviceLoad
+}
+
+// serviceLoad 一个服务的负载
+type serviceLoad struct {
+	inFlight    map[string]int // 容器 ID -> 正在处理的请求数
+	queued      int
+	lastRequest time.Time
+	next        int           // 负载相同时轮询的起点
+	released    chan struct{} // 有请求结束时关闭并替换，用于唤醒排队的请求
+}
+
+// newBalancer 创建负载均衡器
+func newBalancer() *balancer {
+	return &balancer{services: make(map[uuid.UUID]*serviceLoad)}
+}
+
+// get 获取服务的负载记录，调用方需持有锁
+func (b *balancer) get(serviceID uuid.UUID) *serviceLoad {
+	load, ok := b.services[serviceID]
+	if !ok {
+		load = &serviceLoad{inFlight: make(map[string]int), released: make(chan struct{})}
+		b.services[serviceID] = load
+	}
+	return load
+}
+
+// pick 选择正在处理的请求最少且未达到 MaxInFlight 的就绪副本，负载相同时轮询
+//
+// 没有可用副本时返回 nil 和一个在下次有请求结束时关闭的 channel。
+func (b *balancer) pick(svc *domain.InferenceService) (*domain.Replica, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	load := b.get(svc.ID)
+	replicas := svc.ReadyReplicas()
+	var picked *domain.Replica
+	for i := range replicas {
+		replica := &replicas[(load.next+i)%len(replicas)]
+		n := load.inFlight[replica.ContainerID]
+		if svc.MaxInFlight > 0 && n >= svc.MaxInFlight {
+			continue
+		}
+		if picked == nil || n < load.inFlight[picked.ContainerID] {
+			picked = replica
+		}
+	}
+	if picked == nil {
+		return nil, load.released
+	}
+
+	load.next++
+	load.inFlight[picked.ContainerID]++
+	return picked, nil
+}
+
+// release 结束副本上的一个请求
+func (b *balancer) release(serviceID uuid.UUID, containerID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	load := b.get(serviceID)
+	if load.inFlight[containerID]--; load.inFlight[containerID] <= 0 {
+		delete(load.inFlight, containerID)
+	}
+	close(load.released)
+	load.released = make(chan struct{})
+}
+
+// arrive 记录请求到达时间
+func (b *balancer) arrive(serviceID uuid.UUID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.get(serviceID).lastRequest = time.Now()
+}
+
+// queue 调整服务的排队请求数
+func (b *balancer) queue(serviceID uuid.UUID, delta int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.get(serviceID).queued += delta
+}
+
+// load 返回服务负载的快照
+func (b *balancer) load(serviceID uuid.UUID) domain.ServiceLoad {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	load, ok := b.services[serviceID]
+	if !ok {
+		return domain.ServiceLoad{Replicas: map[string]int{}}
+	}
+	result := domain.ServiceLoad{
+		Queued:        load.queued,
+		Replicas:      make(map[string]int, len(load.inFlight)),
+		LastRequestAt: load.lastRequest,
+	}
+	for containerID, n := range load.inFlight {
+		result.InFlight += n
+		result.Replicas[containerID] = n
+	}
+	return result
+}
+
+// Load 返回负载均衡器记录的服务负载
+func (p *proxy) Load(serviceID uuid.UUID) domain.ServiceLoad {
+	return p.balancer.load(serviceID)
+}
+
+// acquire 为请求选择副本，返回副本地址和请求结束时调用的 release
+//
+// 没有可用副本时排队等待，直到有请求结束、新副本就绪或 ctx 结束；
+// 服务没有就绪或启动中的副本时先触发一次冷启动，冷启动的副本也失败时返回 503。
+func (p *proxy) acquire(ctx context.Context, svc *domain.InferenceService) (string, func(), error) {
+	p.balancer.arrive(svc.ID)
+
+	replica, released := p.balancer.pick(svc)
+	if replica == nil {
+		p.balancer.queue(svc.ID, 1)
+		defer p.balancer.queue(svc.ID, -1)
+
+		ticker := time.NewTicker(queuePollInterval)
+		defer ticker.Stop()
+
+		woken := false
+		for replica == nil {
+			if len(svc.ActiveReplicas()) == 0 {
+				if woken || p.waker == nil {
+					return "", nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, fmt.Sprintf("service has no available replica: %s", svc.StatusMessage))
+				}
+				if err := p.waker.Wake(ctx, svc.ID); err != nil {
+					return "", nil, p.queueError(ctx, err)
+				}
+				woken = true
+
+				var err error
+				if svc, err = p.reload(ctx, svc.ID); err != nil {
+					return "", nil, p.queueError(ctx, err)
+				}
+				if replica, released = p.balancer.pick(svc); replica != nil {
+					break
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return "", nil, p.queueError(ctx, ctx.Err())
+			case <-released:
+			case <-ticker.C:
+				var err error
+				if svc, err = p.reload(ctx, svc.ID); err != nil {
+					return "", nil, p.queueError(ctx, err)
+				}
+			}
+			replica, released = p.balancer.pick(svc)
+		}
+	}
+
+	serviceID, containerID := svc.ID, replica.ContainerID
+	return replica.InternalURL, func() { p.balancer.release(serviceID, containerID) }, nil
+}
+
+// reload 重新读取排队中的服务，服务已不在运行时返回错误
+func (p *proxy) reload(ctx context.Context, serviceID uuid.UUID) (*domain.InferenceService, error) {
+	svc, err := p.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("service is not running: %s", svc.Status))
+	}
+	return svc, nil
+}
+
+// queueError 转换排队期间的错误，超时时返回 504
+func (p *proxy) queueError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return apperrors.Wrap(apperrors.ErrTimeout, http.StatusGatewayTimeout, fmt.Sprintf("no inference replica became available within %s", p.cfg.Timeout))
+	}
+	return err
+}
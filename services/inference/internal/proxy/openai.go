@@ -0,0 +1,290 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// OpenAIRequest OpenAI 兼容接口的一次调用，Body 为调用方的原始请求体
+type OpenAIRequest struct {
+	UserID   uuid.UUID
+	APIKeyID *uuid.UUID
+	Path     string // /v1/chat/completions、/v1/completions 或 /v1/embeddings
+	Body     []byte
+}
+
+// openAIUsageEvent 从响应或流式事件中读取 token 数
+type openAIUsageEvent struct {
+	Choices []json.RawMessage  `json:"choices"`
+	Usage   *domain.TokenUsage `json:"usage"`
+}
+
+// Models 列出调用方可以访问的运行中 vllm 服务，同名服务只返回最近启动的一个
+func (p *proxy) Models(ctx context.Context, userID uuid.UUID) ([]*domain.InferenceService, error) {
+	services, err := p.serviceRepo.ListRunningByType(ctx, domain.InferenceTypeVLLM, "")
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(services))
+	models := make([]*domain.InferenceService, 0, len(services))
+	for _, svc := range services {
+		if seen[svc.Name] {
+			continue
+		}
+		ok, err := p.projectRepo.CanAccess(ctx, userID, svc.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			seen[svc.Name] = true
+			models = append(models, svc)
+		}
+	}
+	return models, nil
+}
+
+// OpenAI 转发 OpenAI 兼容请求
+//
+// 请求体中的 model 是推理服务名，转发前替换为 vLLM 的模型名，其余字段原样转发。
+// 流式请求总是向 vLLM 请求 usage 以便记录 token 数，调用方未要求时从输出中去掉该事件。
+// 开始写入响应后返回的错误只用于记录，调用方无法再收到错误状态码。
+func (p *proxy) OpenAI(ctx context.Context, req *OpenAIRequest, w http.ResponseWriter) error {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+	}
+
+	var model string
+	if raw, ok := body["model"]; ok {
+		if err := json.Unmarshal(raw, &model); err != nil {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "model must be a string")
+		}
+	}
+	if model == "" {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "model is required")
+	}
+
+	var stream bool
+	if raw, ok := body["stream"]; ok && json.Unmarshal(raw, &stream) != nil {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "stream must be a boolean")
+	}
+
+	svc, err := p.resolveModel(ctx, req.UserID, model)
+	if err != nil {
+		return err
+	}
+
+	servedModel := defaultServedModel
+	if name := configString(svc, "served_model_name"); name != "" {
+		servedModel = name
+	}
+	body["model"], _ = json.Marshal(servedModel)
+
+	stripUsage := false
+	if stream {
+		stripUsage, err = requestStreamUsage(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode backend request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	c := call{userID: req.UserID, apiKeyID: req.APIKeyID, endpoint: req.Path, stream: stream}
+	started := time.Now()
+
+	baseURL, release, err := p.acquire(ctx, svc)
+	if err != nil {
+		p.record(svc, c, apperrors.CodeFromError(err), err, time.Since(started), nil)
+		return err
+	}
+	defer release()
+
+	backendReq, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+req.Path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build backend request: %w", err)
+	}
+	backendReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(backendReq)
+	if err != nil {
+		err = p.upstreamError(ctx, err)
+		p.record(svc, c, apperrors.CodeFromError(err), err, time.Since(started), nil)
+		return err
+	}
+	defer resp.Body.Close()
+
+	// vLLM 的错误响应已是 OpenAI 格式，与非流式响应一样原样返回
+	if !stream || resp.StatusCode >= 300 {
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			err = p.upstreamError(ctx, fmt.Errorf("failed to read backend response: %w", err))
+			p.record(svc, c, apperrors.CodeFromError(err), err, time.Since(started), nil)
+			return err
+		}
+		usage, err := relayBody(resp, data, w)
+		p.record(svc, c, resp.StatusCode, err, time.Since(started), usage)
+		return nil
+	}
+
+	usage, err := p.relayStream(ctx, resp, w, stripUsage)
+	p.record(svc, c, resp.StatusCode, err, time.Since(started), usage)
+	return nil
+}
+
+// resolveModel 查找调用方可以访问的同名运行中 vllm 服务，有多个时使用最近启动的
+func (p *proxy) resolveModel(ctx context.Context, userID uuid.UUID, model string) (*domain.InferenceService, error) {
+	services, err := p.serviceRepo.ListRunningByType(ctx, domain.InferenceTypeVLLM, model)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, svc := range services {
+		ok, err := p.projectRepo.CanAccess(ctx, userID, svc.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return svc, nil
+		}
+	}
+	return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("the model `%s` does not exist or you do not have access to it", model))
+}
+
+// requestStreamUsage 设置 stream_options.include_usage，返回调用方是否未要求 usage
+func requestStreamUsage(body map[string]json.RawMessage) (bool, error) {
+	options := map[string]json.RawMessage{}
+	if raw, ok := body["stream_options"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &options); err != nil {
+			return false, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "stream_options must be an object")
+		}
+	}
+
+	var includeUsage bool
+	if raw, ok := options["include_usage"]; ok {
+		json.Unmarshal(raw, &includeUsage)
+	}
+	if includeUsage {
+		return false, nil
+	}
+
+	options["include_usage"] = json.RawMessage("true")
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode stream_options: %w", err)
+	}
+	body["stream_options"] = raw
+	return true, nil
+}
+
+// relayBody 原样返回后端响应，成功时从中读取 token 数，失败时返回后端的错误消息
+func relayBody(resp *http.Response, data []byte, w http.ResponseWriter) (*domain.TokenUsage, error) {
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	w.Write(data)
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("inference backend returned %d: %s", resp.StatusCode, backendMessage(data))
+	}
+
+	var event openAIUsageEvent
+	if json.Unmarshal(data, &event) != nil {
+		return nil, nil
+	}
+	return event.Usage, nil
+}
+
+// relayStream 逐行转发 SSE 响应并记录最后一次出现的 token 数
+func (p *proxy) relayStream(ctx context.Context, resp *http.Response, w http.ResponseWriter, stripUsage bool) (*domain.TokenUsage, error) {
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(resp.StatusCode)
+
+	var usage *domain.TokenUsage
+	skipBlank := false
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if skipBlank {
+				skipBlank = false
+				continue
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return usage, err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			continue
+		}
+
+		if data, ok := strings.CutPrefix(line, "data:"); ok {
+			var event openAIUsageEvent
+			if json.Unmarshal([]byte(strings.TrimSpace(data)), &event) == nil && event.Usage != nil {
+				usage = event.Usage
+				// 只有 usage 的事件是代理额外要求的，连同其后的空行一起去掉
+				if stripUsage && len(event.Choices) == 0 {
+					skipBlank = true
+					continue
+				}
+			}
+		}
+
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return usage, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return usage, p.upstreamError(ctx, err)
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return usage, nil
+}
+
+// Usage 按 API Key 汇总调用方的用量
+func (p *proxy) Usage(ctx context.Context, userID uuid.UUID, req *domain.UsageRequest) (*domain.UsageResponse, error) {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if req.From != nil {
+		from = *req.From
+	}
+	to := now
+	if req.To != nil {
+		to = *req.To
+	}
+	if !from.Before(to) {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "from must be before to")
+	}
+
+	keys, err := p.requestRepo.UsageByKey(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UsageResponse{From: from, To: to, Keys: keys}, nil
+}
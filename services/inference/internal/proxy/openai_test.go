@@ -0,0 +1,280 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// fakeRunningRepo 按启动时间倒序排列的运行中服务
+type fakeRunningRepo struct {
+	repository.ServiceRepository
+	services []*domain.InferenceService
+}
+
+func (r *fakeRunningRepo) ListRunningByType(ctx context.Context, serviceType domain.InferenceType, name string) ([]*domain.InferenceService, error) {
+	var services []*domain.InferenceService
+	for _, svc := range r.services {
+		if svc.Type == serviceType && (name == "" || svc.Name == name) {
+			services = append(services, svc)
+		}
+	}
+	return services, nil
+}
+
+// fakeProjectRepo 调用方可以访问的项目
+type fakeProjectRepo struct {
+	repository.ProjectRepository
+	allowed map[uuid.UUID]bool
+}
+
+func (r *fakeProjectRepo) CanAccess(ctx context.Context, userID, projectID uuid.UUID) (bool, error) {
+	return r.allowed[projectID], nil
+}
+
+// fakeRequestRepo 记录保存的请求记录
+type fakeRequestRepo struct {
+	repository.RequestRepository
+
+	mu      sync.Mutex
+	records []*domain.RequestRecord
+}
+
+func (r *fakeRequestRepo) Create(ctx context.Context, record *domain.RequestRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+	return nil
+}
+
+// vllmService 运行中、只有一个就绪副本的 vllm 服务
+func vllmService(name string, projectID uuid.UUID, internalURL string, config map[string]interface{}) *domain.InferenceService {
+	return &domain.InferenceService{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Name:      name,
+		Type:      domain.InferenceTypeVLLM,
+		Status:    domain.ServiceStatusRunning,
+		Config:    config,
+		Replicas:  []domain.Replica{{ContainerID: name, InternalURL: internalURL, Status: domain.ReplicaStatusReady}},
+	}
+}
+
+func TestRequestStreamUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantStrip bool
+		wantOpts  string
+		wantErr   bool
+	}{
+		{"no stream options", `{}`, true, `{"include_usage":true}`, false},
+		{"null stream options", `{"stream_options":null}`, true, `{"include_usage":true}`, false},
+		{"usage not requested", `{"stream_options":{"include_usage":false}}`, true, `{"include_usage":true}`, false},
+		{"other options are kept", `{"stream_options":{"continuous_usage_stats":true}}`, true, `{"continuous_usage_stats":true,"include_usage":true}`, false},
+		{"usage requested", `{"stream_options":{"include_usage":true}}`, false, `{"include_usage":true}`, false},
+		{"invalid stream options", `{"stream_options":"yes"}`, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.body), &body); err != nil {
+				t.Fatal(err)
+			}
+			strip, err := requestStreamUsage(body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("requestStreamUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if strip != tt.wantStrip {
+				t.Errorf("strip = %v, want %v", strip, tt.wantStrip)
+			}
+			if got := string(body["stream_options"]); got != tt.wantOpts {
+				t.Errorf("stream_options = %s, want %s", got, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestProxyOpenAI(t *testing.T) {
+	usageEvent := `{"id":"c","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`
+	var backendPath string
+	var backendBody map[string]interface{}
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		backendPath = r.URL.Path
+		backendBody = nil
+		json.NewDecoder(r.Body).Decode(&backendBody)
+		if backendBody["max_tokens"] == 100000.0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":{"message":"max_tokens too large"}}`)
+			return
+		}
+		if backendBody["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "data: {\"id\":\"c\",\"choices\":[{\"text\":\"hi\"}]}\n\n")
+			io.WriteString(w, "data: "+usageEvent+"\n\n")
+			io.WriteString(w, "data: [DONE]\n\n")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id":"c","choices":[{"text":"hi"}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`)
+	}))
+	defer backend.Close()
+
+	userID := uuid.New()
+	apiKeyID := uuid.New()
+	project := uuid.New()
+	foreignProject := uuid.New()
+	repo := &fakeRunningRepo{services: []*domain.InferenceService{
+		vllmService("llama", project, backend.URL, map[string]interface{}{"served_model_name": "meta-llama/Llama-3-8B"}),
+		vllmService("llama", project, "http://127.0.0.1:1", nil), // 更早启动的同名服务
+		vllmService("secret", foreignProject, backend.URL, nil),
+	}}
+	usage := &domain.TokenUsage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int    // 返回的错误对应的状态码，0 表示已写入响应
+		wantCode   int    // 写入调用方的状态码
+		wantOutput string // 写入调用方的响应中应包含的内容
+		wantUsage  bool   // 调用方的流式响应中应包含 usage 事件
+		wantModel  string // 后端收到的模型名
+		wantRecord *domain.TokenUsage
+	}{
+		{name: "invalid json", path: "/v1/completions", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "missing model", path: "/v1/completions", body: `{"prompt":"hi"}`, wantStatus: http.StatusBadRequest},
+		{name: "model is not a string", path: "/v1/completions", body: `{"model":1}`, wantStatus: http.StatusBadRequest},
+		{name: "stream is not a boolean", path: "/v1/completions", body: `{"model":"llama","stream":"yes"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown model", path: "/v1/completions", body: `{"model":"gpt-4"}`, wantStatus: http.StatusNotFound},
+		{name: "model in another project", path: "/v1/completions", body: `{"model":"secret"}`, wantStatus: http.StatusNotFound},
+		{
+			name:       "completion",
+			path:       "/v1/completions",
+			body:       `{"model":"llama","prompt":"hello"}`,
+			wantCode:   http.StatusOK,
+			wantOutput: `"text":"hi"`,
+			wantModel:  "meta-llama/Llama-3-8B",
+			wantRecord: usage,
+		},
+		{
+			name:       "backend error is relayed",
+			path:       "/v1/chat/completions",
+			body:       `{"model":"llama","max_tokens":100000}`,
+			wantCode:   http.StatusBadRequest,
+			wantOutput: "max_tokens too large",
+			wantModel:  "meta-llama/Llama-3-8B",
+		},
+		{
+			name:       "stream hides the usage event it added",
+			path:       "/v1/completions",
+			body:       `{"model":"llama","prompt":"hello","stream":true}`,
+			wantCode:   http.StatusOK,
+			wantOutput: "data: [DONE]",
+			wantModel:  "meta-llama/Llama-3-8B",
+			wantRecord: usage,
+		},
+		{
+			name:       "stream keeps the usage event the caller asked for",
+			path:       "/v1/completions",
+			body:       `{"model":"llama","prompt":"hello","stream":true,"stream_options":{"include_usage":true}}`,
+			wantCode:   http.StatusOK,
+			wantOutput: "data: [DONE]",
+			wantUsage:  true,
+			wantModel:  "meta-llama/Llama-3-8B",
+			wantRecord: usage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := &fakeRequestRepo{}
+			p := NewProxy(repo, nil, &fakeProjectRepo{allowed: map[uuid.UUID]bool{project: true}}, requests, nil, Config{Timeout: 5 * time.Second}).(*proxy)
+			backendPath = ""
+			w := httptest.NewRecorder()
+
+			err := p.OpenAI(context.Background(), &OpenAIRequest{UserID: userID, APIKeyID: &apiKeyID, Path: tt.path, Body: []byte(tt.body)}, w)
+			if tt.wantStatus != 0 {
+				if got := errorStatus(err); got != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %v", got, tt.wantStatus, err)
+				}
+				if backendPath != "" {
+					t.Errorf("request reached the backend at %s", backendPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenAI() error = %v", err)
+			}
+
+			if w.Code != tt.wantCode || !strings.Contains(w.Body.String(), tt.wantOutput) {
+				t.Errorf("response = %d %s, want %d containing %q", w.Code, w.Body.String(), tt.wantCode, tt.wantOutput)
+			}
+			if got := strings.Contains(w.Body.String(), `"usage"`) && strings.Contains(w.Body.String(), `"choices":[]`); got != tt.wantUsage {
+				t.Errorf("usage event in response = %v, want %v:\n%s", got, tt.wantUsage, w.Body.String())
+			}
+			if backendPath != tt.path || backendBody["model"] != tt.wantModel {
+				t.Errorf("backend got %s with model %v, want %s with %s", backendPath, backendBody["model"], tt.path, tt.wantModel)
+			}
+
+			if len(requests.records) != 1 {
+				t.Fatalf("records = %d, want 1", len(requests.records))
+			}
+			record := requests.records[0]
+			if record.StatusCode != tt.wantCode || record.Endpoint != tt.path || record.APIKeyID == nil || *record.APIKeyID != apiKeyID {
+				t.Errorf("record = status %d, endpoint %s, api key %v", record.StatusCode, record.Endpoint, record.APIKeyID)
+			}
+			var gotUsage *domain.TokenUsage
+			if record.TotalTokens > 0 {
+				gotUsage = &domain.TokenUsage{PromptTokens: record.PromptTokens, CompletionTokens: record.CompletionTokens, TotalTokens: record.TotalTokens}
+			}
+			if !reflect.DeepEqual(gotUsage, tt.wantRecord) {
+				t.Errorf("recorded usage = %+v, want %+v", gotUsage, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestProxyModels(t *testing.T) {
+	project := uuid.New()
+	foreignProject := uuid.New()
+	latest := vllmService("llama", project, "", nil)
+	mistral := vllmService("mistral", project, "", nil)
+	repo := &fakeRunningRepo{services: []*domain.InferenceService{
+		vllmService("secret", foreignProject, "", nil),
+		latest,
+		vllmService("llama", project, "", nil),
+		mistral,
+		{ID: uuid.New(), ProjectID: project, Name: "resnet", Type: domain.InferenceTypeTriton, Status: domain.ServiceStatusRunning},
+	}}
+	p := NewProxy(repo, nil, &fakeProjectRepo{allowed: map[uuid.UUID]bool{project: true}}, nil, nil, Config{}).(*proxy)
+
+	models, err := p.Models(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("Models() error = %v", err)
+	}
+	if want := []*domain.InferenceService{latest, mistral}; !reflect.DeepEqual(models, want) {
+		var names []string
+		for _, svc := range models {
+			names = append(names, svc.Name)
+		}
+		t.Errorf("models = %v, want [llama mistral] with the most recently started llama", names)
+	}
+}
@@ -0,0 +1,337 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// maxErrorBody 读取后端错误响应的最大字节数
+const maxErrorBody = 4096
+
+// Config 推理代理配置
+type Config struct {
+	Timeout time.Duration // 单个请求的最长时间，流式请求同样受此限制
+}
+
+// Proxy 推理请求代理接口
+//
+// 调用方需要有服务所属项目的访问权限。请求由负载均衡器分配到服务的就绪副本，
+// 每个转发的请求都会记录延迟（含排队时间）、状态码和 token 数。
+type Proxy interface {
+	// Predict 转发推理请求并返回统一格式的结果
+	Predict(ctx context.Context, userID, serviceID uuid.UUID, req *domain.PredictRequest) (*domain.PredictResponse, error)
+	// Stream 转发流式推理请求，每段输出调用一次 emit，仅支持 vllm 服务
+	Stream(ctx context.Context, userID, serviceID uuid.UUID, req *domain.PredictRequest, emit func(*domain.PredictChunk) error) error
+	// PredictEndpoint 按端点的流量策略选择运行中的部署并转发推理请求，header 用于请求头路由
+	PredictEndpoint(ctx context.Context, userID, endpointID uuid.UUID, header http.Header, req *domain.PredictRequest) (*domain.PredictResponse, error)
+	// StreamEndpoint 按端点的流量策略选择运行中的部署并转发流式推理请求
+	StreamEndpoint(ctx context.Context, userID, endpointID uuid.UUID, header http.Header, req *domain.PredictRequest, emit func(*domain.PredictChunk) error) error
+	// ListRequests 列出服务的请求记录
+	ListRequests(ctx context.Context, userID, serviceID uuid.UUID, req *domain.ListRequestsRequest) ([]*domain.RequestRecord, int64, error)
+	// Models 列出 OpenAI 兼容接口中调用方可用的模型，即可以访问的运行中 vllm 服务
+	Models(ctx context.Context, userID uuid.UUID) ([]*domain.InferenceService, error)
+	// OpenAI 按 model 字段把 OpenAI 兼容请求转发到同名 vllm 服务，响应原样写入 w
+	OpenAI(ctx context.Context, req *OpenAIRequest, w http.ResponseWriter) error
+	// Usage 按 API Key 汇总调用方的请求数和 token 数
+	Usage(ctx context.Context, userID uuid.UUID, req *domain.UsageRequest) (*domain.UsageResponse, error)
+	// Load 返回服务在本进程内的实时负载，用于自动扩缩容
+	Load(serviceID uuid.UUID) domain.ServiceLoad
+	// Publish 在服务的主机端口上监听，请求原样转发到负载最低的就绪副本，不做鉴权也不记录
+	Publish(serviceID uuid.UUID, port int) error
+	// Unpublish 关闭服务主机端口上的监听
+	Unpublish(serviceID uuid.UUID)
+}
+
+// call 一次转发的调用方信息，用于保存请求记录
+type call struct {
+	userID     uuid.UUID
+	apiKeyID   *uuid.UUID
+	endpointID *uuid.UUID
+	endpoint   string
+	stream     bool
+}
+
+// proxy 推理请求代理实现
+type proxy struct {
+	cfg          Config
+	client       *http.Client
+	serviceRepo  repository.ServiceRepository
+	endpointRepo repository.EndpointRepository
+	projectRepo  repository.ProjectRepository
+	requestRepo  repository.RequestRepository
+	waker        Waker
+	balancer     *balancer
+
+	mu        sync.Mutex
+	published map[uuid.UUID]*publishedPort // 服务 ID -> 主机端口上的监听
+}
+
+// NewProxy 创建推理请求代理，waker 为 nil 时不支持缩容到零后的冷启动
+func NewProxy(serviceRepo repository.ServiceRepository, endpointRepo repository.EndpointRepository, projectRepo repository.ProjectRepository, requestRepo repository.RequestRepository, waker Waker, cfg Config) Proxy {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Minute
+	}
+
+	return &proxy{
+		cfg:          cfg,
+		client:       &http.Client{},
+		serviceRepo:  serviceRepo,
+		endpointRepo: endpointRepo,
+		projectRepo:  projectRepo,
+		requestRepo:  requestRepo,
+		waker:        waker,
+		balancer:     newBalancer(),
+		published:    make(map[uuid.UUID]*publishedPort),
+	}
+}
+
+// Predict 转发推理请求
+func (p *proxy) Predict(ctx context.Context, userID, serviceID uuid.UUID, req *domain.PredictRequest) (*domain.PredictResponse, error) {
+	svc, err := p.runningService(ctx, userID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return p.predict(ctx, svc, call{userID: userID, endpoint: "predict"}, req)
+}
+
+// Stream 转发流式推理请求
+func (p *proxy) Stream(ctx context.Context, userID, serviceID uuid.UUID, req *domain.PredictRequest, emit func(*domain.PredictChunk) error) error {
+	svc, err := p.runningService(ctx, userID, serviceID)
+	if err != nil {
+		return err
+	}
+	return p.stream(ctx, svc, call{userID: userID, endpoint: "predict/stream", stream: true}, req, emit)
+}
+
+// predict 按服务类型转发推理请求并记录
+func (p *proxy) predict(ctx context.Context, svc *domain.InferenceService, c call, req *domain.PredictRequest) (*domain.PredictResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	started := time.Now()
+	var resp *domain.PredictResponse
+	baseURL, release, err := p.acquire(ctx, svc)
+	if err == nil {
+		switch svc.Type {
+		case domain.InferenceTypeVLLM:
+			resp, err = p.complete(ctx, svc, baseURL, req)
+		case domain.InferenceTypeTriton:
+			resp, err = p.infer(ctx, svc, baseURL, req)
+		default:
+			err = apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("unsupported service type: %s", svc.Type))
+		}
+		release()
+	}
+	latency := time.Since(started)
+
+	var usage *domain.TokenUsage
+	if resp != nil {
+		usage = resp.Usage
+	}
+	p.record(svc, c, apperrors.CodeFromError(err), err, latency, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.ServiceID = svc.ID
+	resp.Type = svc.Type
+	resp.LatencyMs = latency.Milliseconds()
+	return resp, nil
+}
+
+// stream 转发流式推理请求并记录
+func (p *proxy) stream(ctx context.Context, svc *domain.InferenceService, c call, req *domain.PredictRequest, emit func(*domain.PredictChunk) error) error {
+	if svc.Type != domain.InferenceTypeVLLM {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "streaming is only supported for vllm services")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	started := time.Now()
+	streaming := false
+	var usage *domain.TokenUsage
+	baseURL, release, err := p.acquire(ctx, svc)
+	if err == nil {
+		usage, err = p.completeStream(ctx, svc, baseURL, req, func(chunk *domain.PredictChunk) error {
+			streaming = true
+			return emit(chunk)
+		})
+		release()
+	}
+
+	// 开始输出后状态码已经确定，之后的错误只记录在 Error 中
+	status := apperrors.CodeFromError(err)
+	if err == nil || streaming {
+		status = http.StatusOK
+	}
+	p.record(svc, c, status, err, time.Since(started), usage)
+	return err
+}
+
+// ListRequests 列出服务的请求记录
+func (p *proxy) ListRequests(ctx context.Context, userID, serviceID uuid.UUID, req *domain.ListRequestsRequest) ([]*domain.RequestRecord, int64, error) {
+	if _, err := p.authorize(ctx, userID, serviceID); err != nil {
+		return nil, 0, err
+	}
+	return p.requestRepo.ListByService(ctx, serviceID, req.Page, req.PageSize)
+}
+
+// authorize 获取服务并检查调用方能否访问其所属项目
+func (p *proxy) authorize(ctx context.Context, userID, serviceID uuid.UUID) (*domain.InferenceService, error) {
+	svc, err := p.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("inference service not found: %s", serviceID))
+		}
+		return nil, err
+	}
+
+	ok, err := p.projectRepo.CanAccess(ctx, userID, svc.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.ErrForbidden, http.StatusForbidden, fmt.Sprintf("no access to project %s", svc.ProjectID))
+	}
+	return svc, nil
+}
+
+// runningService 获取调用方可以访问的运行中服务
+func (p *proxy) runningService(ctx context.Context, userID, serviceID uuid.UUID) (*domain.InferenceService, error) {
+	svc, err := p.authorize(ctx, userID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("service is not running: %s", svc.Status))
+	}
+	return svc, nil
+}
+
+// post 向后端发送 JSON 请求，非 2xx 响应转换为错误
+func (p *proxy) post(ctx context.Context, url string, body interface{}) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode backend request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build backend request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, p.upstreamError(ctx, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, backendError(resp)
+	}
+	return resp, nil
+}
+
+// upstreamError 转换连接后端或读取响应时的错误
+func (p *proxy) upstreamError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return apperrors.Wrap(apperrors.ErrTimeout, http.StatusGatewayTimeout, fmt.Sprintf("inference backend did not respond within %s", p.cfg.Timeout))
+	}
+	return apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusBadGateway, fmt.Sprintf("failed to reach inference backend: %v", err))
+}
+
+// backendError 转换后端的错误响应：4xx 视为请求参数错误，其余视为后端故障
+func backendError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	message := fmt.Sprintf("inference backend returned %d: %s", resp.StatusCode, backendMessage(body))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, message)
+	}
+	return apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusBadGateway, message)
+}
+
+// backendMessage 从后端错误响应中提取错误消息
+func backendMessage(body []byte) string {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+	message := strings.TrimSpace(string(body))
+
+	// vLLM 原生接口返回 {"message": ...}，OpenAI 兼容接口返回 {"error": {"message": ...}}，Triton 返回 {"error": ...}
+	var parsed struct {
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) != nil {
+		return message
+	}
+	if parsed.Message != "" {
+		return parsed.Message
+	}
+	var text string
+	if json.Unmarshal(parsed.Error, &text) == nil && text != "" {
+		return text
+	}
+	var nested struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(parsed.Error, &nested) == nil && nested.Message != "" {
+		return nested.Message
+	}
+	return message
+}
+
+// record 保存请求记录，失败时只记录警告
+func (p *proxy) record(svc *domain.InferenceService, c call, status int, err error, latency time.Duration, usage *domain.TokenUsage) {
+	record := &domain.RequestRecord{
+		ServiceID:  svc.ID,
+		ProjectID:  svc.ProjectID,
+		UserID:     c.userID,
+		APIKeyID:   c.apiKeyID,
+		EndpointID: c.endpointID,
+		Endpoint:   c.endpoint,
+		Stream:     c.stream,
+		StatusCode: status,
+		LatencyMs:  latency.Milliseconds(),
+	}
+	if err != nil {
+		record.Error = apperrors.MessageFromError(err)
+	}
+	if usage != nil {
+		record.PromptTokens = usage.PromptTokens
+		record.CompletionTokens = usage.CompletionTokens
+		record.TotalTokens = usage.TotalTokens
+	}
+
+	// 请求可能已被调用方取消，记录使用独立的上下文
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.requestRepo.Create(ctx, record); err != nil {
+		logger.Warn("Failed to record inference request", zap.String("service_id", svc.ID.String()), zap.Error(err))
+	}
+}
+
+// configString 读取服务配置中的字符串项
+func configString(svc *domain.InferenceService, key string) string {
+	if value, ok := svc.Config[key].(string); ok {
+		return value
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// publishedPort 服务主机端口上的监听
+type publishedPort struct {
+	port   int
+	server *http.Server
+}
+
+// Publish 在服务的主机端口上监听，已在其他端口监听时先关闭旧的监听
+func (p *proxy) Publish(serviceID uuid.UUID, port int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if current, ok := p.published[serviceID]; ok {
+		if current.port == port {
+			return nil
+		}
+		current.server.Close()
+		delete(p.published, serviceID)
+	}
+
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return fmt.Errorf("failed to listen on host port %d: %w", port, err)
+	}
+	server := &http.Server{
+		Handler:           p.forwarder(serviceID),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Warn("Published port stopped", zap.String("service_id", serviceID.String()), zap.Int("port", port), zap.Error(err))
+		}
+	}()
+	p.published[serviceID] = &publishedPort{port: port, server: server}
+	return nil
+}
+
+// Unpublish 关闭服务主机端口上的监听和其上的连接
+func (p *proxy) Unpublish(serviceID uuid.UUID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if current, ok := p.published[serviceID]; ok {
+		current.server.Close()
+		delete(p.published, serviceID)
+	}
+}
+
+// forwarder 把主机端口上的请求原样转发到负载最低的就绪副本，与 predict 接口共用负载均衡和排队
+func (p *proxy) forwarder(serviceID uuid.UUID) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), p.cfg.Timeout)
+		defer cancel()
+
+		target, release, err := p.acquireRunning(ctx, serviceID)
+		if err != nil {
+			writeForwardError(w, err)
+			return
+		}
+		defer release()
+
+		upstream := &httputil.ReverseProxy{
+			Rewrite: func(req *httputil.ProxyRequest) {
+				req.SetURL(target)
+				req.SetXForwarded()
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				writeForwardError(w, p.upstreamError(r.Context(), err))
+			},
+		}
+		upstream.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// acquireRunning 为运行中的服务选择副本，返回副本地址和请求结束时调用的 release
+func (p *proxy) acquireRunning(ctx context.Context, serviceID uuid.UUID) (*url.URL, func(), error) {
+	svc, err := p.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return nil, nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, fmt.Sprintf("service is not running: %s", svc.Status))
+	}
+
+	address, release, err := p.acquire(ctx, svc)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := url.Parse(address)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("invalid replica address %q: %w", address, err)
+	}
+	return target, release, nil
+}
+
+// writeForwardError 以纯文本写入转发失败的原因，非 AppError 返回 502
+func writeForwardError(w http.ResponseWriter, err error) {
+	status, message := http.StatusBadGateway, err.Error()
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		status, message = appErr.Code, appErr.Message
+	}
+	http.Error(w, message, status)
+}
@@ -0,0 +1,100 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// fakeServiceRepo 只返回一个服务
+type fakeServiceRepo struct {
+	repository.ServiceRepository
+	svc *domain.InferenceService
+}
+
+func (r *fakeServiceRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error) {
+	svc := *r.svc
+	return &svc, nil
+}
+
+// freePort 返回一个空闲的本地端口
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestPublishBalancesReplicas(t *testing.T) {
+	replicas := make([]domain.Replica, 2)
+	for i := range replicas {
+		name := fmt.Sprintf("replica-%d", i)
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", name, r.URL.Path)
+		}))
+		defer backend.Close()
+		replicas[i] = domain.Replica{Index: i, ContainerID: name, InternalURL: backend.URL, Status: domain.ReplicaStatusReady}
+	}
+
+	svc := &domain.InferenceService{ID: uuid.New(), Status: domain.ServiceStatusRunning, Replicas: replicas}
+	p := NewProxy(&fakeServiceRepo{svc: svc}, nil, nil, nil, nil, Config{Timeout: 5 * time.Second}).(*proxy)
+
+	port := freePort(t)
+	if err := p.Publish(svc.ID, port); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	defer p.Unpublish(svc.ID)
+
+	get := func() (int, string) {
+		resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/v1/models", port))
+		if err != nil {
+			t.Fatalf("GET error = %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	tests := []struct {
+		name       string
+		status     domain.ServiceStatus
+		wantStatus int
+		wantBody   string
+	}{
+		{"first replica", domain.ServiceStatusRunning, http.StatusOK, "replica-0 /v1/models"},
+		{"second replica", domain.ServiceStatusRunning, http.StatusOK, "replica-1 /v1/models"},
+		{"round robin", domain.ServiceStatusRunning, http.StatusOK, "replica-0 /v1/models"},
+		{"service stopped", domain.ServiceStatusStopped, http.StatusServiceUnavailable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc.Status = tt.status
+			status, body := get()
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", status, tt.wantStatus, body)
+			}
+			if tt.wantBody != "" && body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+
+	p.Unpublish(svc.ID)
+	if _, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", port)); err == nil {
+		t.Error("GET after Unpublish succeeded, want connection error")
+	}
+}
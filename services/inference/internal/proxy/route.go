@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// PredictEndpoint 经推理端点转发推理请求
+func (p *proxy) PredictEndpoint(ctx context.Context, userID, endpointID uuid.UUID, header http.Header, req *domain.PredictRequest) (*domain.PredictResponse, error) {
+	svc, err := p.route(ctx, userID, endpointID, header)
+	if err != nil {
+		return nil, err
+	}
+	return p.predict(ctx, svc, call{userID: userID, endpointID: &endpointID, endpoint: "predict"}, req)
+}
+
+// StreamEndpoint 经推理端点转发流式推理请求
+func (p *proxy) StreamEndpoint(ctx context.Context, userID, endpointID uuid.UUID, header http.Header, req *domain.PredictRequest, emit func(*domain.PredictChunk) error) error {
+	svc, err := p.route(ctx, userID, endpointID, header)
+	if err != nil {
+		return err
+	}
+	return p.stream(ctx, svc, call{userID: userID, endpointID: &endpointID, endpoint: "predict/stream", stream: true}, req, emit)
+}
+
+// route 为请求选择端点的部署
+//
+// 请求头命中 HeaderRoute 时使用对应部署，该部署未运行时返回 503；
+// 否则在运行中且权重大于 0 的部署之间按权重选择，未运行的部署被跳过。
+func (p *proxy) route(ctx context.Context, userID, endpointID uuid.UUID, header http.Header) (*domain.InferenceService, error) {
+	endpoint, err := p.endpointRepo.GetByID(ctx, endpointID)
+	if err != nil {
+		if errors.Is(err, repository.ErrEndpointNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("inference endpoint not found: %s", endpointID))
+		}
+		return nil, err
+	}
+
+	ok, err := p.projectRepo.CanAccess(ctx, userID, endpoint.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.ErrForbidden, http.StatusForbidden, fmt.Sprintf("no access to project %s", endpoint.ProjectID))
+	}
+
+	if route := endpoint.HeaderRoute; route != nil {
+		if serviceID, ok := route.Values[header.Get(route.Header)]; ok {
+			svc, err := p.backend(ctx, serviceID)
+			if err != nil {
+				return nil, err
+			}
+			if svc == nil {
+				return nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, fmt.Sprintf("backend %s selected by %s is not running", serviceID, route.Header))
+			}
+			return svc, nil
+		}
+	}
+
+	candidates := make([]domain.EndpointBackend, 0, len(endpoint.Backends))
+	services := make(map[uuid.UUID]*domain.InferenceService, len(endpoint.Backends))
+	for _, backend := range endpoint.Backends {
+		if backend.Weight <= 0 {
+			continue
+		}
+		svc, err := p.backend(ctx, backend.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if svc != nil {
+			candidates = append(candidates, backend)
+			services[backend.ServiceID] = svc
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, "endpoint has no running backend")
+	}
+
+	return services[pickBackend(endpoint, candidates, userID)], nil
+}
+
+// backend 获取可以接收请求的部署，部署已删除或未运行时返回 nil
+func (p *proxy) backend(ctx context.Context, serviceID uuid.UUID) (*domain.InferenceService, error) {
+	svc, err := p.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return nil, nil
+	}
+	return svc, nil
+}
+
+// pickBackend 按权重选择部署，Sticky 端点用端点和用户 ID 的哈希代替随机数
+//
+// 哈希值落在累计权重的区间上，权重变化时只有边界附近的用户会换到相邻的部署。
+func pickBackend(endpoint *domain.InferenceEndpoint, backends []domain.EndpointBackend, userID uuid.UUID) uuid.UUID {
+	total := 0
+	for _, backend := range backends {
+		total += backend.Weight
+	}
+
+	var n int
+	if endpoint.Sticky {
+		h := fnv.New32a()
+		h.Write(endpoint.ID[:])
+		h.Write(userID[:])
+		n = int(h.Sum32() % uint32(total))
+	} else {
+		n = rand.Intn(total)
+	}
+
+	for _, backend := range backends {
+		if n < backend.Weight {
+			return backend.ServiceID
+		}
+		n -= backend.Weight
+	}
+	return backends[len(backends)-1].ServiceID
+}
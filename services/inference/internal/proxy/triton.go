@@ -0,0 +1,62 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// inferResponse Triton KServe v2 推理响应
+type inferResponse struct {
+	ModelName    string                `json:"model_name"`
+	ModelVersion string                `json:"model_version"`
+	Outputs      []domain.TensorOutput `json:"outputs"`
+}
+
+// infer 调用副本 baseURL 上的 Triton KServe v2 推理接口
+func (p *proxy) infer(ctx context.Context, svc *domain.InferenceService, baseURL string, req *domain.PredictRequest) (*domain.PredictResponse, error) {
+	if len(req.Inputs) == 0 {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "inputs are required for triton services")
+	}
+	model := req.Model
+	if model == "" {
+		model = configString(svc, "model_name")
+	}
+	if model == "" {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "model is required for triton services unless the service sets config.model_name")
+	}
+
+	body := map[string]interface{}{"inputs": req.Inputs}
+	if len(req.Outputs) > 0 {
+		outputs := make([]map[string]string, len(req.Outputs))
+		for i, name := range req.Outputs {
+			outputs[i] = map[string]string{"name": name}
+		}
+		body["outputs"] = outputs
+	}
+	if len(req.Parameters) > 0 {
+		body["parameters"] = req.Parameters
+	}
+
+	resp, err := p.post(ctx, baseURL+"/v2/models/"+url.PathEscape(model)+"/infer", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result inferResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, p.upstreamError(ctx, fmt.Errorf("failed to decode inference response: %w", err))
+	}
+
+	return &domain.PredictResponse{
+		ModelName:    result.ModelName,
+		ModelVersion: result.ModelVersion,
+		Outputs:      result.Outputs,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// backendCall 后端收到的请求
+type backendCall struct {
+	path string
+	body map[string]interface{}
+}
+
+// fakeBackend 记录收到的请求并返回固定响应的推理后端
+func fakeBackend(t *testing.T, status int, response string) (*httptest.Server, *backendCall) {
+	t.Helper()
+	got := &backendCall{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&got.body); err != nil {
+			t.Errorf("decode backend request: %v", err)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, got
+}
+
+// testProxy 不访问仓库的代理，只用于调用后端转换逻辑
+func testProxy() *proxy {
+	return NewProxy(nil, nil, nil, nil, nil, Config{Timeout: 5 * time.Second}).(*proxy)
+}
+
+// errorStatus 返回错误对应的 HTTP 状态码，非 AppError 返回 500
+func errorStatus(err error) int {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+func TestProxyInfer(t *testing.T) {
+	input := domain.TensorInput{Name: "INPUT0", Shape: []int64{1, 2}, Datatype: "FP32", Data: []float64{1, 2}}
+	response := `{"model_name":"resnet","model_version":"3","outputs":[{"name":"OUTPUT0","shape":[1],"datatype":"FP32","data":[0.5]}]}`
+
+	tests := []struct {
+		name       string
+		config     map[string]interface{}
+		req        domain.PredictRequest
+		status     int
+		wantPath   string
+		wantBody   map[string]interface{}
+		wantStatus int // 0 表示成功
+	}{
+		{
+			name:     "model from request",
+			req:      domain.PredictRequest{Model: "resnet", Inputs: []domain.TensorInput{input}},
+			status:   http.StatusOK,
+			wantPath: "/v2/models/resnet/infer",
+			wantBody: map[string]interface{}{
+				"inputs": []interface{}{map[string]interface{}{"name": "INPUT0", "shape": []interface{}{1.0, 2.0}, "datatype": "FP32", "data": []interface{}{1.0, 2.0}}},
+			},
+		},
+		{
+			name:     "model from service config with outputs and parameters",
+			config:   map[string]interface{}{"model_name": "resnet"},
+			req:      domain.PredictRequest{Inputs: []domain.TensorInput{input}, Outputs: []string{"OUTPUT0"}, Parameters: map[string]interface{}{"priority": 1.0}},
+			status:   http.StatusOK,
+			wantPath: "/v2/models/resnet/infer",
+			wantBody: map[string]interface{}{
+				"inputs":     []interface{}{map[string]interface{}{"name": "INPUT0", "shape": []interface{}{1.0, 2.0}, "datatype": "FP32", "data": []interface{}{1.0, 2.0}}},
+				"outputs":    []interface{}{map[string]interface{}{"name": "OUTPUT0"}},
+				"parameters": map[string]interface{}{"priority": 1.0},
+			},
+		},
+		{name: "no inputs", req: domain.PredictRequest{Model: "resnet"}, wantStatus: http.StatusBadRequest},
+		{name: "no model", req: domain.PredictRequest{Inputs: []domain.TensorInput{input}}, wantStatus: http.StatusBadRequest},
+		{name: "backend rejects request", req: domain.PredictRequest{Model: "resnet", Inputs: []domain.TensorInput{input}}, status: http.StatusBadRequest, wantStatus: http.StatusBadRequest},
+		{name: "backend fails", req: domain.PredictRequest{Model: "resnet", Inputs: []domain.TensorInput{input}}, status: http.StatusInternalServerError, wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := response
+			if tt.status >= 300 {
+				body = `{"error":"bad input"}`
+			}
+			backend, got := fakeBackend(t, tt.status, body)
+			svc := &domain.InferenceService{Type: domain.InferenceTypeTriton, Config: tt.config}
+
+			resp, err := testProxy().infer(context.Background(), svc, backend.URL, &tt.req)
+			if tt.wantStatus != 0 {
+				if got := errorStatus(err); got != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %v", got, tt.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("infer() error = %v", err)
+			}
+
+			if got.path != tt.wantPath {
+				t.Errorf("path = %s, want %s", got.path, tt.wantPath)
+			}
+			if !reflect.DeepEqual(got.body, tt.wantBody) {
+				t.Errorf("body = %v, want %v", got.body, tt.wantBody)
+			}
+			if resp.ModelName != "resnet" || resp.ModelVersion != "3" || len(resp.Outputs) != 1 || resp.Outputs[0].Name != "OUTPUT0" {
+				t.Errorf("response = %+v", resp)
+			}
+		})
+	}
+}
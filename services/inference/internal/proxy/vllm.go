@@ -0,0 +1,145 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// defaultServedModel vLLM 未设置 --served-model-name 时以 --model 参数作为模型名，模型挂载在 /models
+const defaultServedModel = "/models"
+
+// completionResponse vLLM /v1/completions 的响应，流式请求的每个事件结构相同
+type completionResponse struct {
+	Choices []struct {
+		Text         string  `json:"text"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *domain.TokenUsage `json:"usage"`
+}
+
+// completionBody 构造 vLLM /v1/completions 请求，parameters 中的字段不覆盖统一请求的字段
+func completionBody(svc *domain.InferenceService, req *domain.PredictRequest, stream bool) (map[string]interface{}, error) {
+	if req.Prompt == "" {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "prompt is required for vllm services")
+	}
+
+	body := make(map[string]interface{}, len(req.Parameters)+8)
+	for key, value := range req.Parameters {
+		body[key] = value
+	}
+
+	body["model"] = defaultServedModel
+	if name := configString(svc, "served_model_name"); name != "" {
+		body["model"] = name
+	}
+	body["prompt"] = req.Prompt
+	if req.MaxTokens > 0 {
+		body["max_tokens"] = req.MaxTokens
+	}
+	if req.Temperature != nil {
+		body["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		body["top_p"] = *req.TopP
+	}
+	if len(req.Stop) > 0 {
+		body["stop"] = req.Stop
+	}
+	if stream {
+		body["stream"] = true
+		body["stream_options"] = map[string]interface{}{"include_usage": true}
+	}
+	return body, nil
+}
+
+// complete 调用副本 baseURL 上的 vLLM 生成文本
+func (p *proxy) complete(ctx context.Context, svc *domain.InferenceService, baseURL string, req *domain.PredictRequest) (*domain.PredictResponse, error) {
+	body, err := completionBody(svc, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.post(ctx, baseURL+"/v1/completions", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var completion completionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, p.upstreamError(ctx, fmt.Errorf("failed to decode completion: %w", err))
+	}
+
+	result := &domain.PredictResponse{Usage: completion.Usage}
+	if len(completion.Choices) > 0 {
+		result.Text = completion.Choices[0].Text
+		if reason := completion.Choices[0].FinishReason; reason != nil {
+			result.FinishReason = *reason
+		}
+	}
+	return result, nil
+}
+
+// completeStream 调用 vLLM 流式生成文本，逐段转发，结束时输出 token 数并返回
+func (p *proxy) completeStream(ctx context.Context, svc *domain.InferenceService, baseURL string, req *domain.PredictRequest, emit func(*domain.PredictChunk) error) (*domain.TokenUsage, error) {
+	body, err := completionBody(svc, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.post(ctx, baseURL+"/v1/completions", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var usage *domain.TokenUsage
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var event completionResponse
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return usage, p.upstreamError(ctx, fmt.Errorf("failed to decode completion event: %w", err))
+		}
+		if event.Usage != nil {
+			usage = event.Usage
+		}
+		if len(event.Choices) == 0 {
+			continue
+		}
+
+		chunk := &domain.PredictChunk{Text: event.Choices[0].Text}
+		if reason := event.Choices[0].FinishReason; reason != nil {
+			chunk.FinishReason = *reason
+		}
+		if err := emit(chunk); err != nil {
+			return usage, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return usage, p.upstreamError(ctx, err)
+	}
+
+	if usage != nil {
+		if err := emit(&domain.PredictChunk{Usage: usage}); err != nil {
+			return usage, err
+		}
+	}
+	return usage, nil
+}
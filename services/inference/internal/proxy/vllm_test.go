@@ -0,0 +1,193 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+func TestCompletionBody(t *testing.T) {
+	temperature := 0.7
+	topP := 0.9
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		req     domain.PredictRequest
+		stream  bool
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "default served model",
+			req:  domain.PredictRequest{Prompt: "hi"},
+			want: map[string]interface{}{"model": defaultServedModel, "prompt": "hi"},
+		},
+		{
+			name:   "all sampling fields",
+			config: map[string]interface{}{"served_model_name": "llama"},
+			req:    domain.PredictRequest{Prompt: "hi", MaxTokens: 16, Temperature: &temperature, TopP: &topP, Stop: []string{"\n"}},
+			want: map[string]interface{}{
+				"model": "llama", "prompt": "hi", "max_tokens": 16, "temperature": 0.7, "top_p": 0.9, "stop": []string{"\n"},
+			},
+		},
+		{
+			name: "parameters do not override request fields",
+			req:  domain.PredictRequest{Prompt: "hi", MaxTokens: 16, Parameters: map[string]interface{}{"max_tokens": 1, "prompt": "other", "seed": 42}},
+			want: map[string]interface{}{"model": defaultServedModel, "prompt": "hi", "max_tokens": 16, "seed": 42},
+		},
+		{
+			name:   "stream with usage",
+			req:    domain.PredictRequest{Prompt: "hi"},
+			stream: true,
+			want: map[string]interface{}{
+				"model": defaultServedModel, "prompt": "hi", "stream": true,
+				"stream_options": map[string]interface{}{"include_usage": true},
+			},
+		},
+		{name: "no prompt", req: domain.PredictRequest{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &domain.InferenceService{Type: domain.InferenceTypeVLLM, Config: tt.config}
+			got, err := completionBody(svc, &tt.req, tt.stream)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("completionBody() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completionBody() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyComplete(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		response   string
+		want       domain.PredictResponse
+		wantStatus int // 0 表示成功
+	}{
+		{
+			name:     "completion",
+			status:   http.StatusOK,
+			response: `{"choices":[{"text":"hello","finish_reason":"stop"}],"usage":{"prompt_tokens":2,"completion_tokens":1,"total_tokens":3}}`,
+			want:     domain.PredictResponse{Text: "hello", FinishReason: "stop", Usage: &domain.TokenUsage{PromptTokens: 2, CompletionTokens: 1, TotalTokens: 3}},
+		},
+		{
+			name:     "no choices",
+			status:   http.StatusOK,
+			response: `{"choices":[]}`,
+			want:     domain.PredictResponse{},
+		},
+		{name: "backend rejects request", status: http.StatusBadRequest, response: `{"error":{"message":"max_tokens too large"}}`, wantStatus: http.StatusBadRequest},
+		{name: "malformed response", status: http.StatusOK, response: `not json`, wantStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, got := fakeBackend(t, tt.status, tt.response)
+			svc := &domain.InferenceService{Type: domain.InferenceTypeVLLM}
+
+			resp, err := testProxy().complete(context.Background(), svc, backend.URL, &domain.PredictRequest{Prompt: "hi"})
+			if got.path != "/v1/completions" {
+				t.Errorf("path = %s, want /v1/completions", got.path)
+			}
+			if tt.wantStatus != 0 {
+				if got := errorStatus(err); got != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %v", got, tt.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("complete() error = %v", err)
+			}
+			if !reflect.DeepEqual(*resp, tt.want) {
+				t.Errorf("complete() = %+v, want %+v", *resp, tt.want)
+			}
+		})
+	}
+}
+
+func TestProxyCompleteStream(t *testing.T) {
+	usage := &domain.TokenUsage{PromptTokens: 2, CompletionTokens: 2, TotalTokens: 4}
+	errStop := errors.New("client went away")
+
+	tests := []struct {
+		name      string
+		events    []string
+		emitErr   error
+		want      []domain.PredictChunk
+		wantUsage *domain.TokenUsage
+		wantErr   bool
+	}{
+		{
+			name: "chunks then usage",
+			events: []string{
+				`{"choices":[{"text":"hel","finish_reason":null}]}`,
+				`{"choices":[{"text":"lo","finish_reason":"stop"}]}`,
+				`{"choices":[],"usage":{"prompt_tokens":2,"completion_tokens":2,"total_tokens":4}}`,
+				`[DONE]`,
+			},
+			want:      []domain.PredictChunk{{Text: "hel"}, {Text: "lo", FinishReason: "stop"}, {Usage: usage}},
+			wantUsage: usage,
+		},
+		{
+			name:   "no usage reported",
+			events: []string{`{"choices":[{"text":"hi","finish_reason":"length"}]}`, `[DONE]`},
+			want:   []domain.PredictChunk{{Text: "hi", FinishReason: "length"}},
+		},
+		{
+			name:    "malformed event",
+			events:  []string{`{"choices":[{"text":"hi"}]}`, `{broken`},
+			want:    []domain.PredictChunk{{Text: "hi"}},
+			wantErr: true,
+		},
+		{
+			name:    "client stops reading",
+			events:  []string{`{"choices":[{"text":"hi"}]}`, `{"choices":[{"text":"there"}]}`},
+			emitErr: errStop,
+			want:    []domain.PredictChunk{{Text: "hi"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sse strings.Builder
+			for _, event := range tt.events {
+				sse.WriteString("data: " + event + "\n\n")
+			}
+			backend, got := fakeBackend(t, http.StatusOK, sse.String())
+			svc := &domain.InferenceService{Type: domain.InferenceTypeVLLM}
+
+			var chunks []domain.PredictChunk
+			gotUsage, err := testProxy().completeStream(context.Background(), svc, backend.URL, &domain.PredictRequest{Prompt: "hi"}, func(chunk *domain.PredictChunk) error {
+				chunks = append(chunks, *chunk)
+				return tt.emitErr
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("completeStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.emitErr != nil && !errors.Is(err, tt.emitErr) {
+				t.Errorf("error = %v, want %v", err, tt.emitErr)
+			}
+			if got.body["stream"] != true {
+				t.Errorf("backend request stream = %v, want true", got.body["stream"])
+			}
+			if !reflect.DeepEqual(chunks, tt.want) {
+				t.Errorf("chunks = %+v, want %+v", chunks, tt.want)
+			}
+			if !reflect.DeepEqual(gotUsage, tt.wantUsage) {
+				t.Errorf("usage = %+v, want %+v", gotUsage, tt.wantUsage)
+			}
+		})
+	}
+}
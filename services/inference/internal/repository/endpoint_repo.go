@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+var (
+	ErrEndpointNotFound = errors.New("inference endpoint not found")
+	ErrRolloutNotFound  = errors.New("rollout not found")
+)
+
+// EndpointRepository 推理端点和灰度发布仓库接口
+type EndpointRepository interface {
+	Create(ctx context.Context, endpoint *domain.InferenceEndpoint) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.InferenceEndpoint, error)
+	List(ctx context.Context, projectID *uuid.UUID, page, pageSize int) ([]*domain.InferenceEndpoint, int64, error)
+	Update(ctx context.Context, endpoint *domain.InferenceEndpoint) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// CreateRollout 在同一事务中创建发布记录并保存端点的流量分配
+	CreateRollout(ctx context.Context, rollout *domain.Rollout, endpoint *domain.InferenceEndpoint) error
+	GetRollout(ctx context.Context, id uuid.UUID) (*domain.Rollout, error)
+	ListRollouts(ctx context.Context, endpointID uuid.UUID) ([]*domain.Rollout, error)
+	ListRunningRollouts(ctx context.Context) ([]*domain.Rollout, error)
+	UpdateRollout(ctx context.Context, rollout *domain.Rollout) error
+	// SaveRollout 在同一事务中保存发布记录和端点的流量分配
+	SaveRollout(ctx context.Context, rollout *domain.Rollout, endpoint *domain.InferenceEndpoint) error
+
+	AutoMigrate() error
+}
+
+// endpointRepository 推理端点和灰度发布仓库实现
+type endpointRepository struct {
+	db *gorm.DB
+}
+
+// NewEndpointRepository 创建推理端点仓库
+func NewEndpointRepository(db *gorm.DB) EndpointRepository {
+	return &endpointRepository{db: db}
+}
+
+// Create 创建推理端点
+func (r *endpointRepository) Create(ctx context.Context, endpoint *domain.InferenceEndpoint) error {
+	if endpoint.ID == uuid.Nil {
+		endpoint.ID = uuid.New()
+	}
+	now := time.Now()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+
+	if err := r.db.WithContext(ctx).Create(endpoint).Error; err != nil {
+		return fmt.Errorf("failed to create inference endpoint: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据 ID 获取推理端点
+func (r *endpointRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.InferenceEndpoint, error) {
+	var endpoint domain.InferenceEndpoint
+	if err := r.db.WithContext(ctx).First(&endpoint, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrEndpointNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get inference endpoint: %w", err)
+	}
+	return &endpoint, nil
+}
+
+// List 列出推理端点，projectID 为 nil 时列出全部
+func (r *endpointRepository) List(ctx context.Context, projectID *uuid.UUID, page, pageSize int) ([]*domain.InferenceEndpoint, int64, error) {
+	var endpoints []*domain.InferenceEndpoint
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.InferenceEndpoint{})
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count inference endpoints: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&endpoints).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list inference endpoints: %w", err)
+	}
+	return endpoints, total, nil
+}
+
+// Update 更新推理端点
+func (r *endpointRepository) Update(ctx context.Context, endpoint *domain.InferenceEndpoint) error {
+	endpoint.UpdatedAt = time.Now()
+	if err := r.db.WithContext(ctx).Save(endpoint).Error; err != nil {
+		return fmt.Errorf("failed to update inference endpoint: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除推理端点及其发布记录
+func (r *endpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", id).Delete(&domain.Rollout{}).Error; err != nil {
+			return fmt.Errorf("failed to delete rollouts: %w", err)
+		}
+		result := tx.Delete(&domain.InferenceEndpoint{}, "id = ?", id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete inference endpoint: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s", ErrEndpointNotFound, id)
+		}
+		return nil
+	})
+}
+
+// CreateRollout 创建发布记录并保存端点
+func (r *endpointRepository) CreateRollout(ctx context.Context, rollout *domain.Rollout, endpoint *domain.InferenceEndpoint) error {
+	if rollout.ID == uuid.Nil {
+		rollout.ID = uuid.New()
+	}
+	now := time.Now()
+	rollout.CreatedAt = now
+	rollout.UpdatedAt = now
+	endpoint.UpdatedAt = now
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rollout).Error; err != nil {
+			return fmt.Errorf("failed to create rollout: %w", err)
+		}
+		if err := tx.Save(endpoint).Error; err != nil {
+			return fmt.Errorf("failed to save inference endpoint: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetRollout 根据 ID 获取发布记录
+func (r *endpointRepository) GetRollout(ctx context.Context, id uuid.UUID) (*domain.Rollout, error) {
+	var rollout domain.Rollout
+	if err := r.db.WithContext(ctx).First(&rollout, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrRolloutNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get rollout: %w", err)
+	}
+	return &rollout, nil
+}
+
+// ListRollouts 按时间倒序列出端点的发布记录
+func (r *endpointRepository) ListRollouts(ctx context.Context, endpointID uuid.UUID) ([]*domain.Rollout, error) {
+	var rollouts []*domain.Rollout
+	if err := r.db.WithContext(ctx).Where("endpoint_id = ?", endpointID).Order("created_at DESC").Find(&rollouts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list rollouts: %w", err)
+	}
+	return rollouts, nil
+}
+
+// ListRunningRollouts 列出所有进行中的发布
+func (r *endpointRepository) ListRunningRollouts(ctx context.Context) ([]*domain.Rollout, error) {
+	var rollouts []*domain.Rollout
+	if err := r.db.WithContext(ctx).Where("status = ?", domain.RolloutStatusRunning).Find(&rollouts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list running rollouts: %w", err)
+	}
+	return rollouts, nil
+}
+
+// UpdateRollout 更新发布记录
+func (r *endpointRepository) UpdateRollout(ctx context.Context, rollout *domain.Rollout) error {
+	rollout.UpdatedAt = time.Now()
+	if err := r.db.WithContext(ctx).Save(rollout).Error; err != nil {
+		return fmt.Errorf("failed to update rollout: %w", err)
+	}
+	return nil
+}
+
+// SaveRollout 保存发布记录和端点
+func (r *endpointRepository) SaveRollout(ctx context.Context, rollout *domain.Rollout, endpoint *domain.InferenceEndpoint) error {
+	now := time.Now()
+	rollout.UpdatedAt = now
+	endpoint.UpdatedAt = now
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(rollout).Error; err != nil {
+			return fmt.Errorf("failed to save rollout: %w", err)
+		}
+		if err := tx.Save(endpoint).Error; err != nil {
+			return fmt.Errorf("failed to save inference endpoint: %w", err)
+		}
+		return nil
+	})
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *endpointRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.InferenceEndpoint{}, &domain.Rollout{})
+}
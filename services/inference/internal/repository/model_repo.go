@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -9,10 +10,17 @@ import (
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
 )
 
+// ErrModelNotFound 模型不存在
+var ErrModelNotFound = errors.New("model not found")
+
+// ErrModelVersionNotFound 模型版本不存在
+var ErrModelVersionNotFound = errors.New("model version not found")
+
 // ModelRepository 模型仓库接口
 type ModelRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.ModelInfo, error)
 	GetByProjectID(ctx context.Context, projectID uuid.UUID) ([]*domain.ModelInfo, error)
+	GetVersion(ctx context.Context, id uuid.UUID) (*domain.ModelVersionInfo, error)
 }
 
 // modelRepository 模型仓库实现
@@ -47,13 +55,14 @@ func (r *modelRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Mo
 	result := r.db.WithContext(ctx).First(&model, "id = ?", id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("model not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrModelNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get model: %w", result.Error)
 	}
 
 	return &domain.ModelInfo{
 		ID:          model.ID,
+		ProjectID:   model.ProjectID,
 		Name:        model.Name,
 		Version:     model.Version,
 		StoragePath: model.StoragePath,
@@ -76,6 +85,7 @@ func (r *modelRepository) GetByProjectID(ctx context.Context, projectID uuid.UUI
 	for i, m := range models {
 		modelInfos[i] = &domain.ModelInfo{
 			ID:          m.ID,
+			ProjectID:   m.ProjectID,
 			Name:        m.Name,
 			Version:     m.Version,
 			StoragePath: m.StoragePath,
@@ -86,6 +96,38 @@ func (r *modelRepository) GetByProjectID(ctx context.Context, projectID uuid.UUI
 	return modelInfos, nil
 }
 
+// ModelVersion 模型版本数据库模型结构
+type ModelVersion struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key"`
+	ModelID     uuid.UUID `gorm:"type:uuid"`
+	Version     string
+	StoragePath string
+}
+
+// TableName 表名
+func (ModelVersion) TableName() string {
+	return "model_versions"
+}
+
+// GetVersion 根据 ID 获取模型版本
+func (r *modelRepository) GetVersion(ctx context.Context, id uuid.UUID) (*domain.ModelVersionInfo, error) {
+	var version ModelVersion
+	result := r.db.WithContext(ctx).First(&version, "id = ?", id)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrModelVersionNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get model version: %w", result.Error)
+	}
+
+	return &domain.ModelVersionInfo{
+		ID:          version.ID,
+		ModelID:     version.ModelID,
+		Version:     version.Version,
+		StoragePath: version.StoragePath,
+	}, nil
+}
+
 // detectFormat 检测模型格式
 func (r *modelRepository) detectFormat(storagePath string) string {
 	// 简单检测模型格式
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+)
+
+// ProjectRepository 项目访问权限查询接口
+type ProjectRepository interface {
+	// CanAccess 用户是项目所有者，或与项目属于同一组织
+	CanAccess(ctx context.Context, userID, projectID uuid.UUID) (bool, error)
+}
+
+// projectRepository 项目访问权限查询实现
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository 创建项目访问权限查询
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+// CanAccess 检查用户能否访问项目
+func (r *projectRepository) CanAccess(ctx context.Context, userID, projectID uuid.UUID) (bool, error) {
+	userOrg := r.db.Model(&models.User{}).Select("org_id").Where("id = ? AND org_id IS NOT NULL", userID)
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Project{}).
+		Where("id = ?", projectID).
+		Where("owner_id = ? OR org_id IN (?)", userID, userOrg).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check project access: %w", err)
+	}
+	return count > 0, nil
+}
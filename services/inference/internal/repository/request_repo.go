@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// RequestRepository 推理请求记录仓库接口
+type RequestRepository interface {
+	Create(ctx context.Context, record *domain.RequestRecord) error
+	ListByService(ctx context.Context, serviceID uuid.UUID, page, pageSize int) ([]*domain.RequestRecord, int64, error)
+	UsageByKey(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*domain.KeyUsage, error)
+	ServiceStats(ctx context.Context, serviceID uuid.UUID, since time.Time) (*domain.RolloutStats, error)
+	AutoMigrate() error
+}
+
+// requestRepository 推理请求记录仓库实现
+type requestRepository struct {
+	db *gorm.DB
+}
+
+// NewRequestRepository 创建推理请求记录仓库
+func NewRequestRepository(db *gorm.DB) RequestRepository {
+	return &requestRepository{db: db}
+}
+
+// Create 保存请求记录
+func (r *requestRepository) Create(ctx context.Context, record *domain.RequestRecord) error {
+	if record.ID == uuid.Nil {
+		record.ID = uuid.New()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	if err := r.db.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to create inference request record: %w", err)
+	}
+	return nil
+}
+
+// ListByService 按时间倒序列出服务的请求记录
+func (r *requestRepository) ListByService(ctx context.Context, serviceID uuid.UUID, page, pageSize int) ([]*domain.RequestRecord, int64, error) {
+	var records []*domain.RequestRecord
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.RequestRecord{}).Where("service_id = ?", serviceID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count inference requests: %w", err)
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list inference requests: %w", err)
+	}
+	return records, total, nil
+}
+
+// UsageByKey 按 API Key 汇总用户在 [from, to) 内的请求数和 token 数，未使用 API Key 的请求汇总为一行
+func (r *requestRepository) UsageByKey(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*domain.KeyUsage, error) {
+	var usage []*domain.KeyUsage
+	result := r.db.WithContext(ctx).Model(&domain.RequestRecord{}).
+		Select("api_key_id, COUNT(*) AS requests, "+
+			"COUNT(CASE WHEN status_code >= 400 THEN 1 END) AS errors, "+
+			"COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens, "+
+			"COALESCE(SUM(completion_tokens), 0) AS completion_tokens, "+
+			"COALESCE(SUM(total_tokens), 0) AS total_tokens").
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", userID, from, to).
+		Group("api_key_id").
+		Order("total_tokens DESC").
+		Scan(&usage)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to aggregate inference usage: %w", result.Error)
+	}
+	return usage, nil
+}
+
+// ServiceStats 统计服务自 since 以来的请求数、5xx 数和 p95 延迟
+func (r *requestRepository) ServiceStats(ctx context.Context, serviceID uuid.UUID, since time.Time) (*domain.RolloutStats, error) {
+	var stats domain.RolloutStats
+	result := r.db.WithContext(ctx).Model(&domain.RequestRecord{}).
+		Select("COUNT(*) AS requests, "+
+			"COUNT(CASE WHEN status_code >= 500 THEN 1 END) AS errors, "+
+			"COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), 0) AS p95_latency_ms").
+		Where("service_id = ? AND created_at >= ?", serviceID, since).
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to aggregate service stats: %w", result.Error)
+	}
+	if stats.Requests > 0 {
+		stats.ErrorRate = float64(stats.Errors) / float64(stats.Requests)
+	}
+	return &stats, nil
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *requestRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.RequestRecord{})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,6 +11,9 @@ import (
 	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
 )
 
+// ErrServiceNotFound 推理服务不存在
+var ErrServiceNotFound = errors.New("inference service not found")
+
 // ServiceRepository 推理服务仓库接口
 type ServiceRepository interface {
 	Create(ctx context.Context, service *domain.InferenceService) error
@@ -20,6 +24,11 @@ type ServiceRepository interface {
 	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ServiceStatus, message string) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetRunningServices(ctx context.Context) ([]*domain.InferenceService, error)
+	UsedHostPorts(ctx context.Context) ([]int, error)
+	ListRunningByType(ctx context.Context, serviceType domain.InferenceType, name string) ([]*domain.InferenceService, error)
+	// WithTx 返回在事务 tx 中读写的仓库
+	WithTx(tx *gorm.DB) ServiceRepository
+	AutoMigrate() error
 }
 
 // serviceRepository 推理服务仓库实现
@@ -32,6 +41,11 @@ func NewServiceRepository(db *gorm.DB) ServiceRepository {
 	return &serviceRepository{db: db}
 }
 
+// WithTx 返回在事务 tx 中读写的仓库
+func (r *serviceRepository) WithTx(tx *gorm.DB) ServiceRepository {
+	return &serviceRepository{db: tx}
+}
+
 // Create 创建推理服务
 func (r *serviceRepository) Create(ctx context.Context, service *domain.InferenceService) error {
 	if service.ID == uuid.Nil {
@@ -54,7 +68,7 @@ func (r *serviceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.
 	result := r.db.WithContext(ctx).First(&service, "id = ?", id)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("inference service not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, id)
 		}
 		return nil, fmt.Errorf("failed to get inference service: %w", result.Error)
 	}
@@ -155,7 +169,7 @@ func (r *serviceRepository) UpdateStatus(ctx context.Context, id uuid.UUID, stat
 		return fmt.Errorf("failed to update inference service status: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("inference service not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, id)
 	}
 	return nil
 }
@@ -167,7 +181,7 @@ func (r *serviceRepository) Delete(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("failed to delete inference service: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("inference service not found: %s", id)
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, id)
 	}
 	return nil
 }
@@ -186,3 +200,39 @@ func (r *serviceRepository) GetRunningServices(ctx context.Context) ([]*domain.I
 	}
 	return services, nil
 }
+
+// UsedHostPorts 获取未停止的服务占用的主机端口
+func (r *serviceRepository) UsedHostPorts(ctx context.Context) ([]int, error) {
+	var ports []int
+	result := r.db.WithContext(ctx).Model(&domain.InferenceService{}).
+		Where("host_port > 0").
+		Where("status IN ?", []domain.ServiceStatus{
+			domain.ServiceStatusDeploying,
+			domain.ServiceStatusRunning,
+			domain.ServiceStatusStopping,
+		}).
+		Pluck("host_port", &ports)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get used host ports: %w", result.Error)
+	}
+	return ports, nil
+}
+
+// ListRunningByType 按启动时间倒序列出指定类型的运行中服务，name 不为空时只返回同名服务
+func (r *serviceRepository) ListRunningByType(ctx context.Context, serviceType domain.InferenceType, name string) ([]*domain.InferenceService, error) {
+	var services []*domain.InferenceService
+	query := r.db.WithContext(ctx).
+		Where("type = ? AND status = ?", serviceType, domain.ServiceStatusRunning)
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+	if err := query.Order("started_at DESC").Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("failed to list running services: %w", err)
+	}
+	return services, nil
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *serviceRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.InferenceService{})
+}
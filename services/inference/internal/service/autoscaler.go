@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/config"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// LoadReporter 提供服务的实时负载，由负载均衡器实现
+type LoadReporter interface {
+	Load(serviceID uuid.UUID) domain.ServiceLoad
+}
+
+// Autoscaler 定期根据负载调整运行中服务的副本数，并删除排空的副本
+//
+// 负载只记录在本进程内，服务重启后从 Autoscaler 启动时开始计算空闲时间。
+type Autoscaler struct {
+	services    InferenceService
+	serviceRepo repository.ServiceRepository
+	requestRepo repository.RequestRepository
+	loads       LoadReporter
+	cfg         *config.Config
+	startedAt   time.Time
+
+	states map[uuid.UUID]*scaleState // 只在评估循环中访问
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewAutoscaler 创建副本自动扩缩容控制器
+func NewAutoscaler(services InferenceService, serviceRepo repository.ServiceRepository, requestRepo repository.RequestRepository, loads LoadReporter, cfg *config.Config) *Autoscaler {
+	return &Autoscaler{
+		services:    services,
+		serviceRepo: serviceRepo,
+		requestRepo: requestRepo,
+		loads:       loads,
+		cfg:         cfg,
+		startedAt:   time.Now(),
+		states:      make(map[uuid.UUID]*scaleState),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start 开始定期评估
+func (a *Autoscaler) Start() {
+	a.wg.Add(1)
+	go a.loop()
+
+	logger.Info("Autoscaler started", zap.Duration("interval", a.interval()))
+}
+
+// Stop 停止定期评估
+func (a *Autoscaler) Stop() {
+	close(a.stopCh)
+	a.wg.Wait()
+}
+
+// interval 评估间隔
+func (a *Autoscaler) interval() time.Duration {
+	if a.cfg.AutoscaleInterval <= 0 {
+		return 15 * time.Second
+	}
+	return a.cfg.AutoscaleInterval
+}
+
+// loop 定期评估循环
+func (a *Autoscaler) loop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		if err := a.Evaluate(ctx); err != nil {
+			logger.Error("Autoscaler evaluation failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// Evaluate 评估所有运行中的服务，单个服务失败不影响其他服务
+func (a *Autoscaler) Evaluate(ctx context.Context) error {
+	services, err := a.serviceRepo.GetRunningServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	running := make(map[uuid.UUID]bool, len(services))
+	var errs []error
+	for _, svc := range services {
+		if svc.Status != domain.ServiceStatusRunning {
+			continue
+		}
+		running[svc.ID] = true
+		if err := a.evaluate(ctx, svc.ID); err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", svc.ID, err))
+		}
+	}
+
+	for id := range a.states {
+		if !running[id] {
+			delete(a.states, id)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// evaluate 检查副本、删除排空的副本并按负载调整副本数
+func (a *Autoscaler) evaluate(ctx context.Context, id uuid.UUID) error {
+	if err := a.services.CheckReplicas(ctx, id); err != nil {
+		return err
+	}
+	svc, err := a.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return nil
+	}
+
+	now := time.Now()
+	load := a.loads.Load(id)
+	for _, replica := range svc.Replicas {
+		if replica.Status != domain.ReplicaStatusDraining {
+			continue
+		}
+		// 超过单个请求的最长时间后，剩余请求已经超时
+		if load.Replicas[replica.ContainerID] > 0 && replica.DrainingAt != nil && now.Sub(*replica.DrainingAt) < a.cfg.PredictTimeout {
+			continue
+		}
+		if err := a.services.RemoveReplica(ctx, id, replica.ContainerID); err != nil {
+			return err
+		}
+	}
+
+	stats, err := a.requestRepo.ServiceStats(ctx, id, now.Add(-a.cfg.AutoscaleWindow))
+	if err != nil {
+		return err
+	}
+
+	lastActive := a.startedAt
+	if svc.StartedAt != nil && svc.StartedAt.After(lastActive) {
+		lastActive = *svc.StartedAt
+	}
+	if load.LastRequestAt.After(lastActive) {
+		lastActive = load.LastRequestAt
+	}
+
+	current := len(svc.ActiveReplicas())
+	policy := scalePolicy(svc, a.cfg)
+	want, reason := policy.Recommend(ScaleMetrics{
+		Replicas:     current,
+		InFlight:     load.InFlight,
+		Queued:       load.Queued,
+		Requests:     stats.Requests,
+		P95LatencyMs: stats.P95LatencyMs,
+		Idle:         now.Sub(lastActive),
+	})
+
+	state := a.states[id]
+	if state == nil {
+		state = &scaleState{}
+		a.states[id] = state
+	}
+	if want == 0 || current > policy.MaxReplicas {
+		// 缩容到零已经等待了 ScaleToZeroAfter，超过上限（用户调低了 max_replicas）时立即缩容
+		*state = scaleState{}
+	} else {
+		want = state.stabilize(current, want, now, a.cfg.ScaleDownDelay)
+	}
+
+	if want == current {
+		return nil
+	}
+	return a.services.Scale(ctx, id, want, reason)
+}
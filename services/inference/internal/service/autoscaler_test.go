@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/config"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/docker"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// fakeRuntime 只记录容器的创建和删除，所有容器都立即健康
+type fakeRuntime struct {
+	docker.Runtime
+
+	mu      sync.Mutex
+	next    int
+	removed []string
+}
+
+func (r *fakeRuntime) CreateContainer(ctx context.Context, svc *domain.InferenceService, replica int, modelPath string, resolved []secrets.Resolved) (string, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	return fmt.Sprintf("created-%d", r.next), fmt.Sprintf("replica-%d", replica), nil
+}
+
+func (r *fakeRuntime) StartContainer(ctx context.Context, containerID string) error {
+	return nil
+}
+
+func (r *fakeRuntime) RemoveContainer(ctx context.Context, containerID string, force bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removed = append(r.removed, containerID)
+	return nil
+}
+
+func (r *fakeRuntime) HealthCheck(ctx context.Context, containerID string) (bool, error) {
+	return true, nil
+}
+
+func (r *fakeRuntime) removedContainers() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.removed...)
+}
+
+// fakeServiceRepo 内存中的服务表，读写都复制服务，与数据库行为一致
+type fakeServiceRepo struct {
+	repository.ServiceRepository
+
+	mu       sync.Mutex
+	services map[uuid.UUID]*domain.InferenceService
+}
+
+func cloneService(svc *domain.InferenceService) *domain.InferenceService {
+	c := *svc
+	c.Replicas = append([]domain.Replica(nil), svc.Replicas...)
+	return &c
+}
+
+func (r *fakeServiceRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	svc, ok := r.services[id]
+	if !ok {
+		return nil, repository.ErrServiceNotFound
+	}
+	return cloneService(svc), nil
+}
+
+func (r *fakeServiceRepo) Update(ctx context.Context, svc *domain.InferenceService) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.services[svc.ID] = cloneService(svc)
+	return nil
+}
+
+func (r *fakeServiceRepo) GetRunningServices(ctx context.Context) ([]*domain.InferenceService, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*domain.InferenceService
+	for _, svc := range r.services {
+		if svc.Status == domain.ServiceStatusRunning {
+			result = append(result, cloneService(svc))
+		}
+	}
+	return result, nil
+}
+
+// fakeModelRepo 所有模型都使用同一存储路径
+type fakeModelRepo struct {
+	repository.ModelRepository
+}
+
+func (r *fakeModelRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.ModelInfo, error) {
+	return &domain.ModelInfo{ID: id, StoragePath: "models/" + id.String()}, nil
+}
+
+// fakeRequestRepo 返回固定的请求统计
+type fakeRequestRepo struct {
+	repository.RequestRepository
+	stats domain.RolloutStats
+}
+
+func (r *fakeRequestRepo) ServiceStats(ctx context.Context, serviceID uuid.UUID, since time.Time) (*domain.RolloutStats, error) {
+	stats := r.stats
+	return &stats, nil
+}
+
+// fakeLoads 可在测试中修改的服务负载
+type fakeLoads struct {
+	mu   sync.Mutex
+	load domain.ServiceLoad
+}
+
+func (l *fakeLoads) Load(serviceID uuid.UUID) domain.ServiceLoad {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.load
+}
+
+func (l *fakeLoads) set(load domain.ServiceLoad) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.load = load
+}
+
+type autoscalerFixture struct {
+	autoscaler *Autoscaler
+	runtime    *fakeRuntime
+	repo       *fakeServiceRepo
+	loads      *fakeLoads
+	serviceID  uuid.UUID
+}
+
+// newAutoscalerFixture 创建一个运行中的服务，带有 ready 个就绪副本
+func newAutoscalerFixture(t *testing.T, ready int, scaleDownDelay time.Duration) *autoscalerFixture {
+	t.Helper()
+	cfg := &config.Config{
+		ModelDownloadTimeout: 5 * time.Second,
+		HealthCheckTimeout:   5 * time.Second,
+		PredictTimeout:       time.Minute,
+		AutoscaleInterval:    10 * time.Millisecond,
+		AutoscaleWindow:      time.Minute,
+		ScaleDownDelay:       scaleDownDelay,
+		ScaleToZeroAfter:     time.Hour,
+	}
+
+	now := time.Now()
+	svc := &domain.InferenceService{
+		ID:             uuid.New(),
+		ProjectID:      uuid.New(),
+		ModelID:        uuid.New(),
+		Status:         domain.ServiceStatusRunning,
+		ContainerPort:  8000,
+		MinReplicas:    1,
+		MaxReplicas:    4,
+		TargetInFlight: 2,
+	}
+	for i := 0; i < ready; i++ {
+		svc.AddReplica(domain.Replica{
+			Index:       i,
+			ContainerID: fmt.Sprintf("existing-%d", i),
+			Status:      domain.ReplicaStatusReady,
+			CreatedAt:   now,
+			ReadyAt:     &now,
+		})
+	}
+	svc.DesiredReplicas = ready
+
+	runtime := &fakeRuntime{}
+	repo := &fakeServiceRepo{services: map[uuid.UUID]*domain.InferenceService{svc.ID: svc}}
+	loads := &fakeLoads{}
+	services := NewInferenceService(cfg, repo, &fakeModelRepo{}, runtime, nil, nil)
+
+	return &autoscalerFixture{
+		autoscaler: NewAutoscaler(services, repo, &fakeRequestRepo{}, loads, cfg),
+		runtime:    runtime,
+		repo:       repo,
+		loads:      loads,
+		serviceID:  svc.ID,
+	}
+}
+
+func (f *autoscalerFixture) service(t *testing.T) *domain.InferenceService {
+	t.Helper()
+	svc, err := f.repo.GetByID(context.Background(), f.serviceID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	return svc
+}
+
+// waitReady 等待新副本通过健康检查
+func (f *autoscalerFixture) waitReady(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(f.service(t).ReadyReplicas()) == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("ready replicas = %d, want %d", len(f.service(t).ReadyReplicas()), n)
+}
+
+func TestAutoscalerScalesUp(t *testing.T) {
+	f := newAutoscalerFixture(t, 1, time.Minute)
+	f.loads.set(domain.ServiceLoad{InFlight: 5, Replicas: map[string]int{"existing-0": 5}})
+
+	if err := f.autoscaler.Evaluate(context.Background()); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	svc := f.service(t)
+	if svc.DesiredReplicas != 3 || len(svc.ActiveReplicas()) != 3 {
+		t.Errorf("desired/active replicas = %d/%d, want 3/3", svc.DesiredReplicas, len(svc.ActiveReplicas()))
+	}
+	f.waitReady(t, 3)
+}
+
+func TestAutoscalerHoldsDuringCooldown(t *testing.T) {
+	f := newAutoscalerFixture(t, 3, time.Hour)
+
+	if err := f.autoscaler.Evaluate(context.Background()); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	svc := f.service(t)
+	if len(svc.ActiveReplicas()) != 3 || len(svc.Replicas) != 3 {
+		t.Errorf("active/total replicas = %d/%d, want 3/3", len(svc.ActiveReplicas()), len(svc.Replicas))
+	}
+}
+
+func TestAutoscalerScalesDownAndRemovesDrainedReplicas(t *testing.T) {
+	f := newAutoscalerFixture(t, 3, 0)
+	ctx := context.Background()
+
+	// 第一轮把多余的副本标记为排空，仍有请求的副本保留到请求结束
+	f.loads.set(domain.ServiceLoad{Replicas: map[string]int{"existing-2": 1}})
+	if err := f.autoscaler.Evaluate(ctx); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	svc := f.service(t)
+	if svc.DesiredReplicas != 1 || len(svc.ActiveReplicas()) != 1 {
+		t.Fatalf("desired/active replicas = %d/%d, want 1/1", svc.DesiredReplicas, len(svc.ActiveReplicas()))
+	}
+	if svc.ActiveReplicas()[0].ContainerID != "existing-0" {
+		t.Errorf("kept replica %s, want existing-0", svc.ActiveReplicas()[0].ContainerID)
+	}
+	if len(svc.Replicas) != 3 {
+		t.Fatalf("replicas = %d, want draining replicas kept", len(svc.Replicas))
+	}
+
+	// 第二轮删除已经没有请求的排空副本
+	if err := f.autoscaler.Evaluate(ctx); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if removed := f.runtime.removedContainers(); len(removed) != 1 || removed[0] != "existing-1" {
+		t.Errorf("removed containers = %v, want [existing-1]", removed)
+	}
+
+	// 请求结束后删除剩余的排空副本
+	f.loads.set(domain.ServiceLoad{})
+	if err := f.autoscaler.Evaluate(ctx); err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if svc := f.service(t); len(svc.Replicas) != 1 {
+		t.Errorf("replicas = %d, want 1", len(svc.Replicas))
+	}
+}
+
+func TestAutoscalerLoop(t *testing.T) {
+	f := newAutoscalerFixture(t, 1, time.Minute)
+	f.loads.set(domain.ServiceLoad{InFlight: 8})
+
+	f.autoscaler.Start()
+	defer f.autoscaler.Stop()
+
+	f.waitReady(t, 4)
+	if svc := f.service(t); svc.DesiredReplicas != 4 {
+		t.Errorf("desired replicas = %d, want 4", svc.DesiredReplicas)
+	}
+}
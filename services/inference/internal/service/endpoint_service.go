@@ -0,0 +1,581 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// 灰度发布策略的默认值
+var defaultRolloutSteps = []int{10, 25, 50, 100}
+
+const (
+	defaultStepIntervalSeconds = 300
+	defaultMinRequests         = 20
+	defaultMaxErrorRate        = 0.05
+)
+
+// EndpointService 推理端点和灰度发布管理接口
+type EndpointService interface {
+	CreateEndpoint(ctx context.Context, userID uuid.UUID, req *domain.CreateEndpointRequest) (*domain.InferenceEndpoint, error)
+	GetEndpoint(ctx context.Context, id uuid.UUID) (*domain.InferenceEndpoint, error)
+	ListEndpoints(ctx context.Context, req *domain.ListEndpointsRequest) ([]*domain.InferenceEndpoint, int64, error)
+	UpdateEndpoint(ctx context.Context, id uuid.UUID, req *domain.UpdateEndpointRequest) (*domain.InferenceEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+
+	// 灰度发布
+	StartRollout(ctx context.Context, endpointID uuid.UUID, req *domain.StartRolloutRequest) (*domain.Rollout, error)
+	ListRollouts(ctx context.Context, endpointID uuid.UUID) ([]*domain.Rollout, error)
+	GetRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error)
+	PromoteRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error)
+	RollbackRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error)
+	// EvaluateRollouts 评估进行中的发布：canary 超出门限或不再运行时回滚，观察期满时进入下一步
+	EvaluateRollouts(ctx context.Context) error
+}
+
+// endpointService 推理端点和灰度发布管理实现
+type endpointService struct {
+	serviceRepo  repository.ServiceRepository
+	endpointRepo repository.EndpointRepository
+	requestRepo  repository.RequestRepository
+
+	// 串行化对端点流量分配的修改，发布评估和用户操作不会互相覆盖
+	mu sync.Mutex
+}
+
+// NewEndpointService 创建推理端点管理实例
+func NewEndpointService(serviceRepo repository.ServiceRepository, endpointRepo repository.EndpointRepository, requestRepo repository.RequestRepository) EndpointService {
+	return &endpointService{
+		serviceRepo:  serviceRepo,
+		endpointRepo: endpointRepo,
+		requestRepo:  requestRepo,
+	}
+}
+
+// CreateEndpoint 创建推理端点，所有部署必须属于同一项目且类型相同
+func (s *endpointService) CreateEndpoint(ctx context.Context, userID uuid.UUID, req *domain.CreateEndpointRequest) (*domain.InferenceEndpoint, error) {
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+	}
+
+	endpoint := &domain.InferenceEndpoint{
+		Name:        req.Name,
+		Description: req.Description,
+		ProjectID:   projectID,
+		UserID:      userID,
+		Backends:    req.Backends,
+		Sticky:      req.Sticky,
+	}
+	if req.HeaderRoute != nil && req.HeaderRoute.Header != "" {
+		endpoint.HeaderRoute = req.HeaderRoute
+	}
+	if err := s.validate(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	if err := s.endpointRepo.Create(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Inference endpoint created", zap.String("endpoint_id", endpoint.ID.String()), zap.Int("backends", len(endpoint.Backends)))
+	return endpoint, nil
+}
+
+// GetEndpoint 获取推理端点
+func (s *endpointService) GetEndpoint(ctx context.Context, id uuid.UUID) (*domain.InferenceEndpoint, error) {
+	return s.loadEndpoint(ctx, id)
+}
+
+// ListEndpoints 列出推理端点
+func (s *endpointService) ListEndpoints(ctx context.Context, req *domain.ListEndpointsRequest) ([]*domain.InferenceEndpoint, int64, error) {
+	var projectID *uuid.UUID
+	if req.ProjectID != "" {
+		id, err := uuid.Parse(req.ProjectID)
+		if err != nil {
+			return nil, 0, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+		}
+		projectID = &id
+	}
+	return s.endpointRepo.List(ctx, projectID, req.Page, req.PageSize)
+}
+
+// UpdateEndpoint 更新推理端点，发布进行中时不能修改部署和权重
+func (s *endpointService) UpdateEndpoint(ctx context.Context, id uuid.UUID, req *domain.UpdateEndpointRequest) (*domain.InferenceEndpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint, err := s.loadEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		endpoint.Name = req.Name
+	}
+	if req.Description != "" {
+		endpoint.Description = req.Description
+	}
+	if req.Sticky != nil {
+		endpoint.Sticky = *req.Sticky
+	}
+	if req.Backends != nil {
+		if endpoint.ActiveRolloutID != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("backends cannot be changed while rollout %s is running", *endpoint.ActiveRolloutID))
+		}
+		endpoint.Backends = req.Backends
+	}
+	if req.HeaderRoute != nil {
+		endpoint.HeaderRoute = req.HeaderRoute
+		if req.HeaderRoute.Header == "" {
+			endpoint.HeaderRoute = nil
+		}
+	}
+	if err := s.validate(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint 删除推理端点，发布进行中时需要先推进或回滚
+func (s *endpointService) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint, err := s.loadEndpoint(ctx, id)
+	if err != nil {
+		return err
+	}
+	if endpoint.ActiveRolloutID != nil {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("rollout %s is running: promote or roll it back first", *endpoint.ActiveRolloutID))
+	}
+	return s.endpointRepo.Delete(ctx, id)
+}
+
+// StartRollout 开始灰度发布
+//
+// canary 先获得 baseline 与 canary 原有权重之和的 Steps[0]%，其余部署的权重不变。
+// canary 必须处于运行状态，且与端点属于同一项目、类型相同。
+func (s *endpointService) StartRollout(ctx context.Context, endpointID uuid.UUID, req *domain.StartRolloutRequest) (*domain.Rollout, error) {
+	policy, err := rolloutPolicy(req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	endpoint, err := s.loadEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.ActiveRolloutID != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("rollout %s is already running", *endpoint.ActiveRolloutID))
+	}
+
+	canaryID, err := uuid.Parse(req.CanaryServiceID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid canary_service_id")
+	}
+	canary, err := s.loadBackendService(ctx, endpoint, canaryID)
+	if err != nil {
+		return nil, err
+	}
+	if canary.Status != domain.ServiceStatusRunning {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("canary service is not running: %s", canary.Status))
+	}
+
+	baselineID, err := rolloutBaseline(endpoint, canaryID, req.BaselineServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rollout := &domain.Rollout{
+		ID:                uuid.New(),
+		EndpointID:        endpoint.ID,
+		BaselineServiceID: baselineID,
+		CanaryServiceID:   canaryID,
+		Policy:            policy,
+		PreviousBackends:  append([]domain.EndpointBackend(nil), endpoint.Backends...),
+		Status:            domain.RolloutStatusRunning,
+		StatusMessage:     fmt.Sprintf("Canary at %d%%", policy.Steps[0]),
+		StepStartedAt:     time.Now(),
+	}
+	endpoint.Backends = rolloutBackends(rollout, policy.Steps[0])
+	endpoint.ActiveRolloutID = &rollout.ID
+
+	if err := s.endpointRepo.CreateRollout(ctx, rollout, endpoint); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Rollout started",
+		zap.String("endpoint_id", endpoint.ID.String()),
+		zap.String("rollout_id", rollout.ID.String()),
+		zap.String("baseline_service_id", baselineID.String()),
+		zap.String("canary_service_id", canaryID.String()),
+	)
+	return rollout, nil
+}
+
+// ListRollouts 列出端点的发布记录
+func (s *endpointService) ListRollouts(ctx context.Context, endpointID uuid.UUID) ([]*domain.Rollout, error) {
+	if _, err := s.loadEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	return s.endpointRepo.ListRollouts(ctx, endpointID)
+}
+
+// GetRollout 获取发布记录
+func (s *endpointService) GetRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error) {
+	return s.loadRollout(ctx, endpointID, rolloutID)
+}
+
+// PromoteRollout 跳过剩余步骤，把 baseline 的全部流量切换到 canary
+func (s *endpointService) PromoteRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rollout, endpoint, err := s.loadRunningRollout(ctx, endpointID, rolloutID)
+	if err != nil {
+		return nil, err
+	}
+
+	rollout.Finish(domain.RolloutStatusSucceeded, "Promoted by user")
+	endpoint.Backends = rolloutBackends(rollout, 100)
+	endpoint.ActiveRolloutID = nil
+	if err := s.endpointRepo.SaveRollout(ctx, rollout, endpoint); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Rollout promoted", zap.String("rollout_id", rollout.ID.String()))
+	return rollout, nil
+}
+
+// RollbackRollout 结束发布并恢复发布前的流量分配
+func (s *endpointService) RollbackRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rollout, endpoint, err := s.loadRunningRollout(ctx, endpointID, rolloutID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rollback(ctx, rollout, endpoint, "Rolled back by user"); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// EvaluateRollouts 评估所有进行中的发布
+func (s *endpointService) EvaluateRollouts(ctx context.Context) error {
+	rollouts, err := s.endpointRepo.ListRunningRollouts(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, rollout := range rollouts {
+		if err := s.evaluate(ctx, rollout.EndpointID, rollout.ID); err != nil {
+			errs = append(errs, fmt.Errorf("rollout %s: %w", rollout.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// evaluate 评估一个发布的当前步骤
+//
+// canary 在本步处理的请求达到 MinRequests 后开始检查门限，超出时立即回滚；
+// 观察时间达到 StepIntervalSeconds 且门限未超出时进入下一步，最后一步通过后发布成功。
+func (s *endpointService) evaluate(ctx context.Context, endpointID, rolloutID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 发布可能已被用户推进或回滚
+	rollout, err := s.endpointRepo.GetRollout(ctx, rolloutID)
+	if err != nil {
+		return err
+	}
+	if rollout.Status != domain.RolloutStatusRunning {
+		return nil
+	}
+	endpoint, err := s.endpointRepo.GetByID(ctx, endpointID)
+	if err != nil {
+		return err
+	}
+
+	canary, err := s.serviceRepo.GetByID(ctx, rollout.CanaryServiceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return s.rollback(ctx, rollout, endpoint, "Canary service was deleted")
+		}
+		return err
+	}
+	if canary.Status != domain.ServiceStatusRunning {
+		return s.rollback(ctx, rollout, endpoint, fmt.Sprintf("Canary service is %s: %s", canary.Status, canary.StatusMessage))
+	}
+
+	stats, err := s.requestRepo.ServiceStats(ctx, canary.ID, rollout.StepStartedAt)
+	if err != nil {
+		return err
+	}
+	rollout.LastStats = stats
+
+	policy := rollout.Policy
+	if stats.Requests > 0 && stats.Requests >= policy.MinRequests {
+		if reason := thresholdBreach(policy, stats); reason != "" {
+			return s.rollback(ctx, rollout, endpoint, reason)
+		}
+	}
+
+	interval := time.Duration(policy.StepIntervalSeconds) * time.Second
+	if time.Since(rollout.StepStartedAt) < interval || stats.Requests < policy.MinRequests {
+		return s.endpointRepo.UpdateRollout(ctx, rollout)
+	}
+
+	if rollout.Step == len(policy.Steps)-1 {
+		rollout.Finish(domain.RolloutStatusSucceeded, "All steps passed")
+		endpoint.ActiveRolloutID = nil
+		if err := s.endpointRepo.SaveRollout(ctx, rollout, endpoint); err != nil {
+			return err
+		}
+		logger.Info("Rollout succeeded", zap.String("rollout_id", rollout.ID.String()))
+		return nil
+	}
+
+	rollout.Step++
+	rollout.StepStartedAt = time.Now()
+	rollout.LastStats = nil
+	rollout.StatusMessage = fmt.Sprintf("Canary at %d%%", rollout.CanaryPercent())
+	endpoint.Backends = rolloutBackends(rollout, rollout.CanaryPercent())
+	if err := s.endpointRepo.SaveRollout(ctx, rollout, endpoint); err != nil {
+		return err
+	}
+
+	logger.Info("Rollout advanced",
+		zap.String("rollout_id", rollout.ID.String()),
+		zap.Int("canary_percent", rollout.CanaryPercent()),
+	)
+	return nil
+}
+
+// rollback 结束发布并恢复发布前的流量分配
+func (s *endpointService) rollback(ctx context.Context, rollout *domain.Rollout, endpoint *domain.InferenceEndpoint, reason string) error {
+	rollout.Finish(domain.RolloutStatusRolledBack, reason)
+	endpoint.Backends = rollout.PreviousBackends
+	endpoint.ActiveRolloutID = nil
+	if err := s.endpointRepo.SaveRollout(ctx, rollout, endpoint); err != nil {
+		return err
+	}
+
+	logger.Warn("Rollout rolled back",
+		zap.String("rollout_id", rollout.ID.String()),
+		zap.String("reason", reason),
+	)
+	return nil
+}
+
+// validate 校验端点的部署和请求头路由，并设置端点类型
+func (s *endpointService) validate(ctx context.Context, endpoint *domain.InferenceEndpoint) error {
+	seen := make(map[uuid.UUID]bool, len(endpoint.Backends))
+	total := 0
+	for _, backend := range endpoint.Backends {
+		if seen[backend.ServiceID] {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("duplicate backend: %s", backend.ServiceID))
+		}
+		seen[backend.ServiceID] = true
+		total += backend.Weight
+
+		if _, err := s.loadBackendService(ctx, endpoint, backend.ServiceID); err != nil {
+			return err
+		}
+	}
+	if total == 0 {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "at least one backend must have a positive weight")
+	}
+
+	if route := endpoint.HeaderRoute; route != nil {
+		if len(route.Values) == 0 {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "header_route.values must not be empty")
+		}
+		for value, serviceID := range route.Values {
+			if !seen[serviceID] {
+				return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("header_route value %q targets %s, which is not a backend", value, serviceID))
+			}
+		}
+	}
+	return nil
+}
+
+// loadBackendService 获取可以作为端点部署的服务，端点尚无类型时取该服务的类型
+func (s *endpointService) loadBackendService(ctx context.Context, endpoint *domain.InferenceEndpoint, serviceID uuid.UUID) (*domain.InferenceService, error) {
+	svc, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("inference service not found: %s", serviceID))
+		}
+		return nil, err
+	}
+	if svc.ProjectID != endpoint.ProjectID {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("service %s does not belong to project %s", serviceID, endpoint.ProjectID))
+	}
+	if endpoint.Type == "" {
+		endpoint.Type = svc.Type
+	}
+	if svc.Type != endpoint.Type {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("service %s is %s but the endpoint serves %s", serviceID, svc.Type, endpoint.Type))
+	}
+	return svc, nil
+}
+
+// loadEndpoint 获取推理端点，不存在时返回 404
+func (s *endpointService) loadEndpoint(ctx context.Context, id uuid.UUID) (*domain.InferenceEndpoint, error) {
+	endpoint, err := s.endpointRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrEndpointNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("inference endpoint not found: %s", id))
+		}
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// loadRollout 获取端点的发布记录，不存在时返回 404
+func (s *endpointService) loadRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, error) {
+	rollout, err := s.endpointRepo.GetRollout(ctx, rolloutID)
+	if err != nil && !errors.Is(err, repository.ErrRolloutNotFound) {
+		return nil, err
+	}
+	if err != nil || rollout.EndpointID != endpointID {
+		return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("rollout not found: %s", rolloutID))
+	}
+	return rollout, nil
+}
+
+// loadRunningRollout 获取进行中的发布及其端点，发布已结束时返回 409
+func (s *endpointService) loadRunningRollout(ctx context.Context, endpointID, rolloutID uuid.UUID) (*domain.Rollout, *domain.InferenceEndpoint, error) {
+	rollout, err := s.loadRollout(ctx, endpointID, rolloutID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rollout.Status != domain.RolloutStatusRunning {
+		return nil, nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("rollout is not running: %s", rollout.Status))
+	}
+	endpoint, err := s.loadEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rollout, endpoint, nil
+}
+
+// rolloutPolicy 根据请求生成发布策略，步骤必须递增且以 100 结束
+func rolloutPolicy(req *domain.StartRolloutRequest) (domain.RolloutPolicy, error) {
+	policy := domain.RolloutPolicy{
+		Steps:               defaultRolloutSteps,
+		StepIntervalSeconds: defaultStepIntervalSeconds,
+		MinRequests:         defaultMinRequests,
+		MaxErrorRate:        defaultMaxErrorRate,
+		MaxP95LatencyMs:     req.MaxP95LatencyMs,
+	}
+	if len(req.Steps) > 0 {
+		policy.Steps = req.Steps
+	}
+	if req.StepIntervalSeconds > 0 {
+		policy.StepIntervalSeconds = req.StepIntervalSeconds
+	}
+	if req.MinRequests != nil {
+		policy.MinRequests = *req.MinRequests
+	}
+	if req.MaxErrorRate != nil {
+		policy.MaxErrorRate = *req.MaxErrorRate
+	}
+
+	for i, step := range policy.Steps {
+		if i > 0 && step <= policy.Steps[i-1] {
+			return policy, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "steps must be strictly increasing")
+		}
+	}
+	if policy.Steps[len(policy.Steps)-1] != 100 {
+		return policy, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "the last step must be 100")
+	}
+	return policy, nil
+}
+
+// rolloutBaseline 确定发布的 baseline：未指定时使用 canary 以外权重最高的部署
+func rolloutBaseline(endpoint *domain.InferenceEndpoint, canaryID uuid.UUID, requested string) (uuid.UUID, error) {
+	if requested != "" {
+		baselineID, err := uuid.Parse(requested)
+		if err != nil {
+			return uuid.Nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid baseline_service_id")
+		}
+		if baselineID == canaryID {
+			return uuid.Nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "baseline and canary must be different services")
+		}
+		backend, ok := endpoint.Backend(baselineID)
+		if !ok || backend.Weight == 0 {
+			return uuid.Nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("baseline %s must be a backend with a positive weight", baselineID))
+		}
+		return baselineID, nil
+	}
+
+	var baseline domain.EndpointBackend
+	for _, backend := range endpoint.Backends {
+		if backend.ServiceID != canaryID && backend.Weight > baseline.Weight {
+			baseline = backend
+		}
+	}
+	if baseline.Weight == 0 {
+		return uuid.Nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "endpoint has no other backend with traffic to shift to the canary")
+	}
+	return baseline.ServiceID, nil
+}
+
+// rolloutBackends 计算发布中的流量分配
+//
+// baseline 和 canary 原有权重之和按 percent 分给 canary，其余部署保持发布前的权重。
+// canary 排在最后，Sticky 端点中已分配到 canary 的用户在后续步骤中不会回到 baseline。
+func rolloutBackends(rollout *domain.Rollout, percent int) []domain.EndpointBackend {
+	share := 0
+	backends := make([]domain.EndpointBackend, 0, len(rollout.PreviousBackends)+1)
+	for _, backend := range rollout.PreviousBackends {
+		if backend.ServiceID == rollout.BaselineServiceID || backend.ServiceID == rollout.CanaryServiceID {
+			share += backend.Weight
+			continue
+		}
+		backends = append(backends, backend)
+	}
+
+	canaryWeight := share * percent / 100
+	if percent > 0 && canaryWeight == 0 {
+		canaryWeight = 1
+	}
+	return append(backends,
+		domain.EndpointBackend{ServiceID: rollout.BaselineServiceID, Weight: share - canaryWeight},
+		domain.EndpointBackend{ServiceID: rollout.CanaryServiceID, Weight: canaryWeight},
+	)
+}
+
+// thresholdBreach 检查 canary 统计是否超出门限，返回原因
+func thresholdBreach(policy domain.RolloutPolicy, stats *domain.RolloutStats) string {
+	if stats.ErrorRate > policy.MaxErrorRate {
+		return fmt.Sprintf("Canary error rate %.1f%% exceeded %.1f%% (%d of %d requests)", stats.ErrorRate*100, policy.MaxErrorRate*100, stats.Errors, stats.Requests)
+	}
+	if policy.MaxP95LatencyMs > 0 && stats.P95LatencyMs > policy.MaxP95LatencyMs {
+		return fmt.Sprintf("Canary p95 latency %.0fms exceeded %.0fms", stats.P95LatencyMs, policy.MaxP95LatencyMs)
+	}
+	return ""
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// fakeEndpointRepo 内存中的端点和发布表，只保存一个端点和一个发布
+type fakeEndpointRepo struct {
+	repository.EndpointRepository
+
+	endpoint *domain.InferenceEndpoint
+	rollout  *domain.Rollout
+}
+
+func (r *fakeEndpointRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.InferenceEndpoint, error) {
+	endpoint := *r.endpoint
+	endpoint.Backends = append([]domain.EndpointBackend(nil), r.endpoint.Backends...)
+	return &endpoint, nil
+}
+
+func (r *fakeEndpointRepo) GetRollout(ctx context.Context, id uuid.UUID) (*domain.Rollout, error) {
+	rollout := *r.rollout
+	return &rollout, nil
+}
+
+func (r *fakeEndpointRepo) UpdateRollout(ctx context.Context, rollout *domain.Rollout) error {
+	r.rollout = rollout
+	return nil
+}
+
+func (r *fakeEndpointRepo) SaveRollout(ctx context.Context, rollout *domain.Rollout, endpoint *domain.InferenceEndpoint) error {
+	r.rollout = rollout
+	r.endpoint = endpoint
+	return nil
+}
+
+func TestRolloutBackends(t *testing.T) {
+	baseline, canary, other := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name     string
+		previous []domain.EndpointBackend
+		percent  int
+		want     []domain.EndpointBackend
+	}{
+		{
+			name:     "first step",
+			previous: []domain.EndpointBackend{{ServiceID: baseline, Weight: 100}},
+			percent:  10,
+			want:     []domain.EndpointBackend{{ServiceID: baseline, Weight: 90}, {ServiceID: canary, Weight: 10}},
+		},
+		{
+			name:     "last step moves all traffic",
+			previous: []domain.EndpointBackend{{ServiceID: baseline, Weight: 100}},
+			percent:  100,
+			want:     []domain.EndpointBackend{{ServiceID: baseline, Weight: 0}, {ServiceID: canary, Weight: 100}},
+		},
+		{
+			name:     "other backends keep their weights",
+			previous: []domain.EndpointBackend{{ServiceID: other, Weight: 30}, {ServiceID: baseline, Weight: 70}},
+			percent:  50,
+			want:     []domain.EndpointBackend{{ServiceID: other, Weight: 30}, {ServiceID: baseline, Weight: 35}, {ServiceID: canary, Weight: 35}},
+		},
+		{
+			name:     "canary that is already a backend shares its weight",
+			previous: []domain.EndpointBackend{{ServiceID: baseline, Weight: 80}, {ServiceID: canary, Weight: 20}},
+			percent:  25,
+			want:     []domain.EndpointBackend{{ServiceID: baseline, Weight: 75}, {ServiceID: canary, Weight: 25}},
+		},
+		{
+			name:     "small share rounds up to one",
+			previous: []domain.EndpointBackend{{ServiceID: baseline, Weight: 5}},
+			percent:  10,
+			want:     []domain.EndpointBackend{{ServiceID: baseline, Weight: 4}, {ServiceID: canary, Weight: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollout := &domain.Rollout{BaselineServiceID: baseline, CanaryServiceID: canary, PreviousBackends: tt.previous}
+			if got := rolloutBackends(rollout, tt.percent); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rolloutBackends() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointServiceEvaluate(t *testing.T) {
+	baseline, canary := uuid.New(), uuid.New()
+	previous := []domain.EndpointBackend{{ServiceID: baseline, Weight: 100}}
+	policy := domain.RolloutPolicy{
+		Steps:               []int{10, 50, 100},
+		StepIntervalSeconds: 60,
+		MinRequests:         20,
+		MaxErrorRate:        0.05,
+		MaxP95LatencyMs:     800,
+	}
+
+	tests := []struct {
+		name          string
+		step          int
+		stepAge       time.Duration
+		canaryStatus  domain.ServiceStatus
+		canaryDeleted bool
+		stats         domain.RolloutStats
+		wantStatus    domain.RolloutStatus
+		wantStep      int
+		wantBackends  []domain.EndpointBackend
+	}{
+		{
+			name:         "hold until interval passes",
+			stepAge:      30 * time.Second,
+			canaryStatus: domain.ServiceStatusRunning,
+			stats:        domain.RolloutStats{Requests: 50},
+			wantStatus:   domain.RolloutStatusRunning,
+			wantBackends: []domain.EndpointBackend{{ServiceID: baseline, Weight: 90}, {ServiceID: canary, Weight: 10}},
+		},
+		{
+			name:         "hold until min requests",
+			stepAge:      time.Hour,
+			canaryStatus: domain.ServiceStatusRunning,
+			stats:        domain.RolloutStats{Requests: 5, Errors: 5, ErrorRate: 1},
+			wantStatus:   domain.RolloutStatusRunning,
+			wantBackends: []domain.EndpointBackend{{ServiceID: baseline, Weight: 90}, {ServiceID: canary, Weight: 10}},
+		},
+		{
+			name:         "advance to next step",
+			stepAge:      time.Hour,
+			canaryStatus: domain.ServiceStatusRunning,
+			stats:        domain.RolloutStats{Requests: 50, Errors: 1, ErrorRate: 0.02, P95LatencyMs: 300},
+			wantStatus:   domain.RolloutStatusRunning,
+			wantStep:     1,
+			wantBackends: []domain.EndpointBackend{{ServiceID: baseline, Weight: 50}, {ServiceID: canary, Weight: 50}},
+		},
+		{
+			name:         "succeed after last step",
+			step:         2,
+			stepAge:      time.Hour,
+			canaryStatus: domain.ServiceStatusRunning,
+			stats:        domain.RolloutStats{Requests: 50},
+			wantStatus:   domain.RolloutStatusSucceeded,
+			wantStep:     2,
+			wantBackends: []domain.EndpointBackend{{ServiceID: baseline, Weight: 0}, {ServiceID: canary, Weight: 100}},
+		},
+		{
+			name:         "roll back on error rate before interval",
+			stepAge:      time.Second,
+			canaryStatus: domain.ServiceStatusRunning,
+			stats:        domain.RolloutStats{Requests: 40, Errors: 10, ErrorRate: 0.25},
+			wantStatus:   domain.RolloutStatusRolledBack,
+			wantBackends: previous,
+		},
+		{
+			name:         "roll back on p95 latency",
+			step:         1,
+			stepAge:      time.Hour,
+			canaryStatus: domain.ServiceStatusRunning,
+			stats:        domain.RolloutStats{Requests: 40, P95LatencyMs: 1200},
+			wantStatus:   domain.RolloutStatusRolledBack,
+			wantStep:     1,
+			wantBackends: previous,
+		},
+		{
+			name:         "roll back when canary stopped",
+			canaryStatus: domain.ServiceStatusStopped,
+			wantStatus:   domain.RolloutStatusRolledBack,
+			wantBackends: previous,
+		},
+		{
+			name:          "roll back when canary deleted",
+			canaryDeleted: true,
+			wantStatus:    domain.RolloutStatusRolledBack,
+			wantBackends:  previous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollout := &domain.Rollout{
+				ID:                uuid.New(),
+				EndpointID:        uuid.New(),
+				BaselineServiceID: baseline,
+				CanaryServiceID:   canary,
+				Policy:            policy,
+				PreviousBackends:  previous,
+				Step:              tt.step,
+				Status:            domain.RolloutStatusRunning,
+				StepStartedAt:     time.Now().Add(-tt.stepAge),
+			}
+			endpoint := &domain.InferenceEndpoint{
+				ID:              rollout.EndpointID,
+				Backends:        rolloutBackends(rollout, rollout.CanaryPercent()),
+				ActiveRolloutID: &rollout.ID,
+			}
+			services := map[uuid.UUID]*domain.InferenceService{}
+			if !tt.canaryDeleted {
+				services[canary] = &domain.InferenceService{ID: canary, Status: tt.canaryStatus}
+			}
+			endpoints := &fakeEndpointRepo{endpoint: endpoint, rollout: rollout}
+			s := &endpointService{
+				serviceRepo:  &fakeServiceRepo{services: services},
+				endpointRepo: endpoints,
+				requestRepo:  &fakeRequestRepo{stats: tt.stats},
+			}
+
+			if err := s.evaluate(context.Background(), rollout.EndpointID, rollout.ID); err != nil {
+				t.Fatalf("evaluate() error = %v", err)
+			}
+
+			got := endpoints.rollout
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s (%s)", got.Status, tt.wantStatus, got.StatusMessage)
+			}
+			if got.Step != tt.wantStep {
+				t.Errorf("step = %d, want %d", got.Step, tt.wantStep)
+			}
+			if !reflect.DeepEqual(endpoints.endpoint.Backends, tt.wantBackends) {
+				t.Errorf("backends = %v, want %v", endpoints.endpoint.Backends, tt.wantBackends)
+			}
+			if active := endpoints.endpoint.ActiveRolloutID != nil; active != (tt.wantStatus == domain.RolloutStatusRunning) {
+				t.Errorf("active rollout set = %v with status %s", active, got.Status)
+			}
+		})
+	}
+}
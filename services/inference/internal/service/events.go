@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// SetEventPublisher 设置事件发布器，未设置时不发布服务事件
+func (s *inferenceService) SetEventPublisher(publisher events.Publisher) {
+	s.events = publisher
+}
+
+// SetPortPublisher 设置主机端口的发布器，未设置时主机端口不可访问
+func (s *inferenceService) SetPortPublisher(publisher PortPublisher) {
+	s.publisher = publisher
+}
+
+// emit 以 inference.service.<type> 类型发布服务事件
+//
+// 发布在后台进行，不阻塞持有服务锁的操作；失败时只记录日志。
+func (s *inferenceService) emit(eventType string, svc *domain.InferenceService, message string) {
+	if s.events == nil {
+		return
+	}
+
+	event := &domain.ServiceEvent{
+		Type:      eventType,
+		ServiceID: svc.ID,
+		ProjectID: svc.ProjectID,
+		Status:    svc.Status,
+		Message:   message,
+		Replicas:  svc.DesiredReplicas,
+		Timestamp: time.Now(),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := s.events.Publish(ctx, &events.Event{
+			Type:       "inference.service." + event.Type,
+			Source:     events.SourceInference,
+			ProjectID:  event.ProjectID,
+			ResourceID: event.ServiceID,
+			Status:     string(event.Status),
+			Message:    event.Message,
+			Data: map[string]interface{}{
+				"replicas": event.Replicas,
+			},
+			Timestamp: event.Timestamp,
+		})
+		if err != nil {
+			logger.Warn("Failed to publish service event",
+				zap.String("service_id", event.ServiceID.String()),
+				zap.String("type", event.Type),
+				zap.Error(err),
+			)
+		}
+	}()
+}
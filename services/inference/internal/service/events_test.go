@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// fakePublisher 记录发布的事件
+type fakePublisher struct {
+	published chan *events.Event
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event *events.Event) error {
+	p.published <- event
+	return nil
+}
+
+func TestEmitPublishesServiceEvent(t *testing.T) {
+	publisher := &fakePublisher{published: make(chan *events.Event, 1)}
+	s := &inferenceService{}
+	s.SetEventPublisher(publisher)
+
+	svc := &domain.InferenceService{
+		ID:              uuid.New(),
+		ProjectID:       uuid.New(),
+		Status:          domain.ServiceStatusRunning,
+		DesiredReplicas: 3,
+	}
+	s.emit(domain.ServiceEventScaled, svc, "Scaled from 1 to 3 replicas")
+
+	select {
+	case event := <-publisher.published:
+		if event.Type != "inference.service.scaled" || event.Source != events.SourceInference {
+			t.Errorf("type/source = %s/%s", event.Type, event.Source)
+		}
+		if event.ProjectID != svc.ProjectID || event.ResourceID != svc.ID {
+			t.Errorf("project/resource = %s/%s, want %s/%s", event.ProjectID, event.ResourceID, svc.ProjectID, svc.ID)
+		}
+		if event.Status != string(domain.ServiceStatusRunning) {
+			t.Errorf("status = %s, want running", event.Status)
+		}
+		if event.Data["replicas"] != 3 {
+			t.Errorf("replicas = %v, want 3", event.Data["replicas"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event was not published")
+	}
+}
+
+func TestEmitWithoutPublisher(t *testing.T) {
+	s := &inferenceService{}
+	s.emit(domain.ServiceEventStopped, &domain.InferenceService{ID: uuid.New()}, "Stopped by user")
+}
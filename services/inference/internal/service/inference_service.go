@@ -0,0 +1,846 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/quota"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/config"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/docker"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// healthPollInterval 等待服务健康时检查容器的间隔
+const healthPollInterval = 2 * time.Second
+
+// secretResourceType 审计记录中推理服务的资源类型
+const secretResourceType = "inference_service"
+
+// InferenceService 推理服务管理接口
+type InferenceService interface {
+	CreateService(ctx context.Context, userID uuid.UUID, req *domain.CreateServiceRequest) (*domain.InferenceService, error)
+	GetService(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error)
+	ListServices(ctx context.Context, req *domain.ListServicesRequest) ([]*domain.InferenceService, int64, error)
+	UpdateService(ctx context.Context, id uuid.UUID, req *domain.UpdateServiceRequest) (*domain.InferenceService, error)
+	DeleteService(ctx context.Context, id uuid.UUID) error
+
+	// 启停
+	StartService(ctx context.Context, id uuid.UUID, req *domain.StartServiceRequest) (*domain.InferenceService, error)
+	StopService(ctx context.Context, id uuid.UUID, req *domain.StopServiceRequest) (*domain.InferenceService, error)
+	StopAll(ctx context.Context) error
+
+	// 副本，Scale、RemoveReplica 和 CheckReplicas 在服务有其他操作进行中时不做处理
+	// Scale 把运行中服务的就绪和启动中副本数调整为 replicas，缩容的就绪副本转为 draining
+	Scale(ctx context.Context, id uuid.UUID, replicas int, reason string) error
+	// RemoveReplica 删除副本容器，用于删除排空后的副本
+	RemoveReplica(ctx context.Context, id uuid.UUID, containerID string) error
+	// CheckReplicas 删除容器已退出或已不存在的就绪副本
+	CheckReplicas(ctx context.Context, id uuid.UUID) error
+	// Wake 运行中的服务没有就绪或启动中的副本时启动一个副本，用于缩容到零后的冷启动
+	Wake(ctx context.Context, id uuid.UUID) error
+
+	// SetEventPublisher 设置服务启动、停止、失败和扩缩容事件的发布器
+	SetEventPublisher(publisher events.Publisher)
+	// SetPortPublisher 设置在服务主机端口上转发请求的发布器
+	SetPortPublisher(publisher PortPublisher)
+}
+
+// PortPublisher 在服务的主机端口上监听，把请求经负载均衡转发到服务的就绪副本
+type PortPublisher interface {
+	Publish(serviceID uuid.UUID, port int) error
+	Unpublish(serviceID uuid.UUID)
+}
+
+// inferenceService 推理服务管理实现
+type inferenceService struct {
+	cfg         *config.Config
+	serviceRepo repository.ServiceRepository
+	modelRepo   repository.ModelRepository
+	executor    docker.Runtime
+	quota       quota.Checker
+	secrets     secrets.Manager
+	ports       *portAllocator
+	events      events.Publisher
+	publisher   PortPublisher
+
+	mu   sync.Mutex
+	busy map[uuid.UUID]bool // 正在启动、停止或删除的服务
+}
+
+// NewInferenceService 创建推理服务管理实例，secretStore 为 nil 时不支持 secret_refs
+func NewInferenceService(cfg *config.Config, serviceRepo repository.ServiceRepository, modelRepo repository.ModelRepository, executor docker.Runtime, quotaChecker quota.Checker, secretStore secrets.Manager) InferenceService {
+	return &inferenceService{
+		cfg:         cfg,
+		serviceRepo: serviceRepo,
+		modelRepo:   modelRepo,
+		executor:    executor,
+		quota:       quotaChecker,
+		secrets:     secretStore,
+		ports:       newPortAllocator(serviceRepo, cfg.HostPortMin, cfg.HostPortMax),
+		busy:        make(map[uuid.UUID]bool),
+	}
+}
+
+// CreateService 创建推理服务，创建后处于 pending 状态，需要调用 StartService 部署
+func (s *inferenceService) CreateService(ctx context.Context, userID uuid.UUID, req *domain.CreateServiceRequest) (*domain.InferenceService, error) {
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+	}
+	modelID, err := uuid.Parse(req.ModelID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid model_id")
+	}
+
+	model, err := s.loadModel(ctx, modelID)
+	if err != nil {
+		return nil, err
+	}
+	if model.ProjectID != projectID {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("model %s does not belong to project %s", modelID, projectID))
+	}
+
+	var modelVersionID *uuid.UUID
+	if req.ModelVersionID != "" {
+		id, err := uuid.Parse(req.ModelVersionID)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid model_version_id")
+		}
+		version, err := s.loadModelVersion(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if version.ModelID != modelID {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("model version %s does not belong to model %s", id, modelID))
+		}
+		modelVersionID = &id
+	}
+
+	minReplicas := 1
+	if req.MinReplicas != nil {
+		minReplicas = *req.MinReplicas
+	}
+	maxReplicas := req.MaxReplicas
+	if maxReplicas == 0 {
+		maxReplicas = max(minReplicas, 1)
+	}
+	if minReplicas > maxReplicas {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "min_replicas must not exceed max_replicas")
+	}
+
+	svc := &domain.InferenceService{
+		ID:             uuid.New(),
+		Name:           req.Name,
+		Description:    req.Description,
+		ProjectID:      projectID,
+		ModelID:        modelID,
+		ModelVersionID: modelVersionID,
+		UserID:         userID,
+		Type:           req.Type,
+		Config:         req.Config,
+		Environment:    req.Environment,
+		GPUCount:       req.GPUCount,
+		GPUType:        req.GPUType,
+		CPUCount:       req.CPUCount,
+		MemoryGB:       req.MemoryGB,
+		MinReplicas:    minReplicas,
+		MaxReplicas:    maxReplicas,
+		TargetInFlight: req.TargetInFlight,
+		MaxInFlight:    req.MaxInFlight,
+		TargetLatency:  req.TargetLatency,
+		ContainerPort:  s.cfg.DefaultInferencePort,
+		Status:         domain.ServiceStatusPending,
+		HealthStatus:   "unknown",
+	}
+
+	// 校验引用的密钥，密钥值只在启动容器时读取
+	if len(req.SecretRefs) > 0 {
+		if s.secrets == nil {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "secret_refs are not supported: the secrets store is not configured")
+		}
+		if err := s.secrets.Check(ctx, projectID, req.SecretRefs); err != nil {
+			return nil, err
+		}
+		svc.SecretRefs = req.SecretRefs
+	}
+
+	// pending 状态的服务已按最大副本数计入 GPU 配额，配额检查和写入在同一事务中，并发创建不会一起超出配额
+	if s.quota == nil {
+		if err := s.serviceRepo.Create(ctx, svc); err != nil {
+			return nil, err
+		}
+	} else if err := s.quota.Reserve(ctx, svc.ProjectID, quotaRequest(svc), func(tx *gorm.DB) error {
+		return s.serviceRepo.WithTx(tx).Create(ctx, svc)
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Inference service created",
+		zap.String("service_id", svc.ID.String()),
+		zap.String("model_id", modelID.String()),
+	)
+	return svc, nil
+}
+
+// GetService 获取推理服务
+func (s *inferenceService) GetService(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error) {
+	return s.load(ctx, id)
+}
+
+// ListServices 列出推理服务
+func (s *inferenceService) ListServices(ctx context.Context, req *domain.ListServicesRequest) ([]*domain.InferenceService, int64, error) {
+	var projectID *uuid.UUID
+	if req.ProjectID != "" {
+		id, err := uuid.Parse(req.ProjectID)
+		if err != nil {
+			return nil, 0, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+		}
+		projectID = &id
+	}
+	return s.serviceRepo.List(ctx, projectID, req.Status, req.Page, req.PageSize)
+}
+
+// UpdateService 更新推理服务，配置和环境变量在下次启动时生效，扩缩容设置在下一次评估时生效
+func (s *inferenceService) UpdateService(ctx context.Context, id uuid.UUID, req *domain.UpdateServiceRequest) (*domain.InferenceService, error) {
+	if !s.acquire(id) {
+		return nil, busyError(id)
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	minReplicas, maxReplicas := svc.ReplicaBounds()
+	oldMax := maxReplicas
+	if req.MinReplicas != nil {
+		minReplicas = *req.MinReplicas
+	}
+	if req.MaxReplicas != nil {
+		maxReplicas = *req.MaxReplicas
+	}
+	if minReplicas > maxReplicas {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "min_replicas must not exceed max_replicas")
+	}
+	// 未停止的服务已按原最大副本数计入配额，只需检查增加的部分
+	if maxReplicas > oldMax && !svc.IsTerminal() && s.quota != nil {
+		if err := s.quota.Check(ctx, svc.ProjectID, quota.Request{
+			GPUs:     svc.GPUCount * (maxReplicas - oldMax),
+			MemoryGB: svc.MemoryGB,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	svc.MinReplicas = minReplicas
+	svc.MaxReplicas = maxReplicas
+	if req.TargetInFlight != nil {
+		svc.TargetInFlight = *req.TargetInFlight
+	}
+	if req.MaxInFlight != nil {
+		svc.MaxInFlight = *req.MaxInFlight
+	}
+	if req.TargetLatency != nil {
+		svc.TargetLatency = *req.TargetLatency
+	}
+
+	if req.Name != "" {
+		svc.Name = req.Name
+	}
+	if req.Description != "" {
+		svc.Description = req.Description
+	}
+	if req.Config != nil {
+		svc.Config = req.Config
+	}
+	if req.Environment != nil {
+		svc.Environment = req.Environment
+	}
+
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// DeleteService 删除推理服务，运行中的服务先强制停止
+func (s *inferenceService) DeleteService(ctx context.Context, id uuid.UUID) error {
+	if !s.acquire(id) {
+		return busyError(id)
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.teardown(ctx, svc, true); err != nil {
+		return fmt.Errorf("failed to stop service: %w", err)
+	}
+	return s.serviceRepo.Delete(ctx, id)
+}
+
+// StartService 部署并启动推理服务
+//
+// 启动 max(min_replicas, 1) 个副本容器后服务处于 deploying 状态，后台等待健康检查，
+// 第一个副本通过后转为 running，全部副本超时或退出时转为 error。
+// WaitForHealthy 为 true 时等待所有副本的健康检查结束再返回。
+func (s *inferenceService) StartService(ctx context.Context, id uuid.UUID, req *domain.StartServiceRequest) (*domain.InferenceService, error) {
+	svc, err := s.start(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 健康检查在后台进行，请求取消不影响服务状态的更新
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, replica := range svc.Replicas {
+		wg.Add(1)
+		go func(containerID string) {
+			defer wg.Done()
+			s.awaitReplica(id, containerID)
+		}(replica.ContainerID)
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	if !req.WaitForHealthy {
+		return svc, nil
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	svc, err = s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, fmt.Sprintf("service did not become healthy: %s", svc.StatusMessage))
+	}
+	return svc, nil
+}
+
+// start 检查状态和配额，分配端口并启动容器，成功后服务处于 deploying 状态
+func (s *inferenceService) start(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error) {
+	if !s.acquire(id) {
+		return nil, busyError(id)
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.CanStart() {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("service cannot be started in status: %s", svc.Status))
+	}
+
+	if err := s.checkCapacity(ctx); err != nil {
+		return nil, err
+	}
+	// 已停止和出错的服务不计入配额，重新启动前需要再次检查
+	if svc.Status != domain.ServiceStatusPending {
+		if err := s.checkQuota(ctx, svc); err != nil {
+			return nil, err
+		}
+	}
+
+	modelPath, err := s.modelPath(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	// 清理上次出错时残留的容器
+	for _, replica := range svc.Replicas {
+		if err := s.executor.RemoveContainer(ctx, replica.ContainerID, true); err != nil {
+			logger.Warn("Failed to remove stale container", zap.String("service_id", svc.ID.String()), zap.Error(err))
+		}
+	}
+	svc.Replicas = nil
+	svc.CurrentReplicas = 0
+
+	port, err := s.ports.Allocate(ctx, svc.ID, svc.HostPort)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, err.Error())
+	}
+	svc.HostPort = port
+	if s.publisher != nil {
+		if err := s.publisher.Publish(svc.ID, port); err != nil {
+			s.ports.Release(svc.ID)
+			return nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, err.Error())
+		}
+	}
+	if svc.ContainerPort == 0 {
+		svc.ContainerPort = s.cfg.DefaultInferencePort
+	}
+	svc.HealthStatus = "unknown"
+	svc.UpdateStatus(domain.ServiceStatusDeploying, "Creating container")
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		s.unpublish(svc.ID)
+		return nil, err
+	}
+
+	if err := s.deploy(svc, modelPath); err != nil {
+		s.unpublish(svc.ID)
+		svc.UpdateStatus(domain.ServiceStatusError, err.Error())
+		if updateErr := s.serviceRepo.Update(context.Background(), svc); updateErr != nil {
+			logger.Error("Failed to record service failure", zap.String("service_id", svc.ID.String()), zap.Error(updateErr))
+		}
+		s.emit(domain.ServiceEventFailed, svc, err.Error())
+		return nil, err
+	}
+
+	logger.Info("Inference service deploying",
+		zap.String("service_id", svc.ID.String()),
+		zap.Int("replicas", len(svc.Replicas)),
+		zap.Int("host_port", svc.HostPort),
+	)
+	return svc, nil
+}
+
+// deploy 创建并启动初始副本，记录副本和访问地址，任一副本启动失败时删除已启动的副本
+func (s *inferenceService) deploy(svc *domain.InferenceService, modelPath string) error {
+	// 拉取镜像可能较慢，不受请求超时影响
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ModelDownloadTimeout)
+	defer cancel()
+
+	resolved, err := s.resolveSecrets(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	minReplicas, _ := svc.ReplicaBounds()
+	replicas := max(minReplicas, 1)
+	for i := 0; i < replicas; i++ {
+		replica, err := s.createReplica(ctx, svc, i, modelPath, resolved)
+		if err != nil {
+			for _, created := range svc.Replicas {
+				if removeErr := s.executor.RemoveContainer(ctx, created.ContainerID, true); removeErr != nil {
+					logger.Warn("Failed to remove container", zap.String("container_id", created.ContainerID), zap.Error(removeErr))
+				}
+			}
+			svc.Replicas = nil
+			return err
+		}
+		svc.AddReplica(*replica)
+	}
+
+	if info, err := s.executor.GetContainerInfo(ctx, svc.Replicas[0].ContainerID); err == nil {
+		svc.Image = info.Image
+	}
+	svc.EndpointURL = fmt.Sprintf("http://%s:%d", s.cfg.PublicHost, svc.HostPort)
+	svc.DesiredReplicas = replicas
+	svc.UpdateStatus(domain.ServiceStatusDeploying, "Waiting for health check")
+	return s.serviceRepo.Update(ctx, svc)
+}
+
+// createReplica 创建并启动第 index 个副本容器
+func (s *inferenceService) createReplica(ctx context.Context, svc *domain.InferenceService, index int, modelPath string, resolved []secrets.Resolved) (*domain.Replica, error) {
+	containerID, containerName, err := s.executor.CreateContainer(ctx, svc, index, modelPath, resolved)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.executor.StartContainer(ctx, containerID); err != nil {
+		if removeErr := s.executor.RemoveContainer(ctx, containerID, true); removeErr != nil {
+			logger.Warn("Failed to remove container", zap.String("container_id", containerID), zap.Error(removeErr))
+		}
+		return nil, err
+	}
+
+	return &domain.Replica{
+		Index:         index,
+		ContainerID:   containerID,
+		ContainerName: containerName,
+		InternalURL:   fmt.Sprintf("http://%s:%d", containerName, svc.ContainerPort),
+		Status:        domain.ReplicaStatusStarting,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// resolveSecrets 解密服务引用的密钥，每次读取都会写入审计记录
+func (s *inferenceService) resolveSecrets(ctx context.Context, svc *domain.InferenceService) ([]secrets.Resolved, error) {
+	if len(svc.SecretRefs) == 0 {
+		return nil, nil
+	}
+	if s.secrets == nil {
+		return nil, fmt.Errorf("service references secrets but the secrets store is not configured")
+	}
+
+	userID := svc.UserID
+	resolved, err := s.secrets.Resolve(ctx, svc.ProjectID, svc.SecretRefs, secrets.Access{
+		Action:     secrets.ActionInject,
+		ActorID:    &userID,
+		Resource:   secretResourceType,
+		ResourceID: svc.ID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	return resolved, nil
+}
+
+// awaitReplica 等待副本的健康检查通过，超时或容器退出时删除副本
+func (s *inferenceService) awaitReplica(id uuid.UUID, containerID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.HealthCheckTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+
+	for {
+		healthy, err := s.executor.HealthCheck(ctx, containerID)
+		if err == nil && healthy {
+			s.finishReplica(id, containerID, true, "Service is healthy")
+			return
+		}
+		if err != nil {
+			info, infoErr := s.executor.GetContainerInfo(ctx, containerID)
+			if infoErr != nil && !s.replicaStarting(ctx, id, containerID) {
+				// 服务在等待期间被停止、删除或缩容
+				return
+			}
+			// 容器在重启策略用尽后保持 exited 状态
+			if infoErr == nil && (info.State == "exited" || info.State == "dead") {
+				s.finishReplica(id, containerID, false, fmt.Sprintf("Container exited with code %d", info.ExitCode))
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			s.finishReplica(id, containerID, false, fmt.Sprintf("Health check did not pass within %s", s.cfg.HealthCheckTimeout))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replicaStarting 检查副本是否仍在等待健康检查
+func (s *inferenceService) replicaStarting(ctx context.Context, id uuid.UUID, containerID string) bool {
+	svc, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return !errors.Is(err, repository.ErrServiceNotFound)
+	}
+	replica := svc.FindReplica(containerID)
+	return replica != nil && replica.Status == domain.ReplicaStatusStarting
+}
+
+// finishReplica 结束副本的健康检查，副本在等待期间被删除时不做处理
+//
+// 健康的副本转为 ready，部署中的服务随之转为 running；失败的副本被删除，
+// 部署中的服务没有剩余副本时转为 error。
+func (s *inferenceService) finishReplica(id uuid.UUID, containerID string, healthy bool, message string) {
+	// 等待进行中的停止或删除操作结束，避免同时修改服务
+	for !s.acquire(id) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	defer s.release(id)
+
+	ctx := context.Background()
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return
+	}
+	replica := svc.FindReplica(containerID)
+	if replica == nil || replica.Status != domain.ReplicaStatusStarting {
+		return
+	}
+	index := replica.Index
+
+	var event string
+	if healthy {
+		svc.SetReplicaStatus(containerID, domain.ReplicaStatusReady)
+		if svc.Status == domain.ServiceStatusDeploying {
+			svc.SetHealthStatus(true)
+			svc.UpdateStatus(domain.ServiceStatusRunning, message)
+			event = domain.ServiceEventStarted
+		} else {
+			svc.StatusMessage = fmt.Sprintf("Replica %d is ready", index)
+		}
+	} else {
+		if err := s.executor.RemoveContainer(ctx, containerID, true); err != nil {
+			logger.Warn("Failed to remove unhealthy container", zap.String("service_id", id.String()), zap.Error(err))
+		}
+		svc.RemoveReplica(containerID)
+		if svc.Status == domain.ServiceStatusDeploying && len(svc.ActiveReplicas()) == 0 {
+			if err := s.teardown(ctx, svc, true); err != nil {
+				logger.Warn("Failed to remove replicas", zap.String("service_id", id.String()), zap.Error(err))
+			}
+			svc.SetHealthStatus(false)
+			svc.UpdateStatus(domain.ServiceStatusError, message)
+			event = domain.ServiceEventFailed
+		} else {
+			svc.StatusMessage = fmt.Sprintf("Replica %d failed: %s", index, message)
+		}
+	}
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		logger.Error("Failed to update service status", zap.String("service_id", id.String()), zap.Error(err))
+		return
+	}
+	if event != "" {
+		s.emit(event, svc, message)
+	}
+
+	logger.Info("Inference replica health check finished",
+		zap.String("service_id", id.String()),
+		zap.Int("replica", index),
+		zap.Bool("healthy", healthy),
+		zap.String("message", message),
+	)
+}
+
+// StopService 停止推理服务并删除容器，Force 为 true 时不等待容器退出
+func (s *inferenceService) StopService(ctx context.Context, id uuid.UUID, req *domain.StopServiceRequest) (*domain.InferenceService, error) {
+	if !s.acquire(id) {
+		return nil, busyError(id)
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !svc.CanStop() {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("service cannot be stopped in status: %s", svc.Status))
+	}
+
+	if err := s.stop(ctx, svc, req.Force, "Stopped by user"); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// StopAll 停止所有运行中的服务，用于服务关闭
+func (s *inferenceService) StopAll(ctx context.Context) error {
+	services, err := s.serviceRepo.GetRunningServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, svc := range services {
+		if !s.acquire(svc.ID) {
+			continue
+		}
+		if err := s.stop(ctx, svc, false, "Stopped on inference service shutdown"); err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", svc.ID, err))
+		}
+		s.release(svc.ID)
+	}
+	return errors.Join(errs...)
+}
+
+// stop 经过 stopping 状态停止服务，失败时转为 error
+func (s *inferenceService) stop(ctx context.Context, svc *domain.InferenceService, force bool, message string) error {
+	svc.UpdateStatus(domain.ServiceStatusStopping, "Stopping container")
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		return err
+	}
+
+	if err := s.teardown(ctx, svc, force); err != nil {
+		svc.UpdateStatus(domain.ServiceStatusError, fmt.Sprintf("Failed to stop container: %v", err))
+		if updateErr := s.serviceRepo.Update(ctx, svc); updateErr != nil {
+			logger.Error("Failed to record service failure", zap.String("service_id", svc.ID.String()), zap.Error(updateErr))
+		}
+		s.emit(domain.ServiceEventFailed, svc, svc.StatusMessage)
+		return err
+	}
+
+	svc.UpdateStatus(domain.ServiceStatusStopped, message)
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		return err
+	}
+	s.emit(domain.ServiceEventStopped, svc, message)
+
+	logger.Info("Inference service stopped", zap.String("service_id", svc.ID.String()), zap.Bool("force", force))
+	return nil
+}
+
+// teardown 删除服务的所有副本容器并释放端口，force 为 false 时先等待容器退出
+//
+// 部分容器删除失败时服务只保留这些副本并返回错误。主机端口保留在记录中，下次启动时空闲则优先复用。
+func (s *inferenceService) teardown(ctx context.Context, svc *domain.InferenceService, force bool) error {
+	errs := make([]error, len(svc.Replicas))
+	var wg sync.WaitGroup
+	for i, replica := range svc.Replicas {
+		wg.Add(1)
+		go func(i int, containerID string) {
+			defer wg.Done()
+			errs[i] = s.removeContainer(ctx, containerID, force)
+		}(i, replica.ContainerID)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		var remaining []domain.Replica
+		for i, replica := range svc.Replicas {
+			if errs[i] != nil {
+				remaining = append(remaining, replica)
+			}
+		}
+		svc.Replicas = remaining
+		svc.CurrentReplicas = len(svc.ReadyReplicas())
+		return err
+	}
+
+	s.unpublish(svc.ID)
+	svc.Replicas = nil
+	svc.CurrentReplicas = 0
+	svc.DesiredReplicas = 0
+	svc.EndpointURL = ""
+	svc.HealthStatus = "unknown"
+	return nil
+}
+
+// unpublish 关闭服务主机端口上的监听并释放端口
+func (s *inferenceService) unpublish(id uuid.UUID) {
+	if s.publisher != nil {
+		s.publisher.Unpublish(id)
+	}
+	s.ports.Release(id)
+}
+
+// removeContainer 删除容器，force 为 false 时先等待容器退出
+func (s *inferenceService) removeContainer(ctx context.Context, containerID string, force bool) error {
+	if !force {
+		if err := s.executor.StopContainer(ctx, containerID, s.cfg.StopTimeout); err != nil {
+			return err
+		}
+	}
+	return s.executor.RemoveContainer(ctx, containerID, force)
+}
+
+// checkCapacity 检查运行中的服务数是否达到上限
+func (s *inferenceService) checkCapacity(ctx context.Context) error {
+	if s.cfg.MaxConcurrentServices <= 0 {
+		return nil
+	}
+
+	running, err := s.serviceRepo.GetRunningServices(ctx)
+	if err != nil {
+		return err
+	}
+	if len(running) >= s.cfg.MaxConcurrentServices {
+		return apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, fmt.Sprintf("maximum of %d concurrent inference services reached", s.cfg.MaxConcurrentServices))
+	}
+	return nil
+}
+
+// checkQuota 检查项目/组织的 GPU 和内存配额，GPU 按最大副本数计算
+func (s *inferenceService) checkQuota(ctx context.Context, svc *domain.InferenceService) error {
+	if s.quota == nil {
+		return nil
+	}
+	return s.quota.Check(ctx, svc.ProjectID, quotaRequest(svc))
+}
+
+// quotaRequest 服务占用的配额
+func quotaRequest(svc *domain.InferenceService) quota.Request {
+	return quota.Request{
+		GPUs:     svc.ReservedGPUs(),
+		MemoryGB: svc.MemoryGB,
+	}
+}
+
+// load 获取推理服务，不存在时返回 404
+func (s *inferenceService) load(ctx context.Context, id uuid.UUID) (*domain.InferenceService, error) {
+	svc, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("inference service not found: %s", id))
+		}
+		return nil, err
+	}
+	return svc, nil
+}
+
+// loadModel 获取模型，不存在时返回 404
+func (s *inferenceService) loadModel(ctx context.Context, id uuid.UUID) (*domain.ModelInfo, error) {
+	model, err := s.modelRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrModelNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("model not found: %s", id))
+		}
+		return nil, err
+	}
+	return model, nil
+}
+
+// loadModelVersion 获取模型版本，不存在时返回 404
+func (s *inferenceService) loadModelVersion(ctx context.Context, id uuid.UUID) (*domain.ModelVersionInfo, error) {
+	version, err := s.modelRepo.GetVersion(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrModelVersionNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("model version not found: %s", id))
+		}
+		return nil, err
+	}
+	return version, nil
+}
+
+// modelPath 获取服务要挂载的模型目录，指定了模型版本时使用该版本的存储路径
+func (s *inferenceService) modelPath(ctx context.Context, svc *domain.InferenceService) (string, error) {
+	if svc.ModelVersionID != nil {
+		version, err := s.loadModelVersion(ctx, *svc.ModelVersionID)
+		if err != nil {
+			return "", err
+		}
+		if version.StoragePath == "" {
+			return "", apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("model version %s has no storage path", version.ID))
+		}
+		return version.StoragePath, nil
+	}
+
+	model, err := s.loadModel(ctx, svc.ModelID)
+	if err != nil {
+		return "", err
+	}
+	if model.StoragePath == "" {
+		return "", apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("model %s has no storage path", model.ID))
+	}
+	return model.StoragePath, nil
+}
+
+// acquire 标记服务正在被操作，已有操作进行中时返回 false
+func (s *inferenceService) acquire(id uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.busy[id] {
+		return false
+	}
+	s.busy[id] = true
+	return true
+}
+
+// release 清除服务的操作标记
+func (s *inferenceService) release(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.busy, id)
+}
+
+// busyError 服务已有操作进行中
+func busyError(id uuid.UUID) error {
+	return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("another operation is in progress for service %s", id))
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/repository"
+)
+
+// portAllocator 在配置的范围内为推理服务分配主机端口
+//
+// 已启动服务的端口记录在数据库中，分配到写入数据库之间的端口保存在内存中，
+// 避免并发启动的服务拿到同一个端口。端口还会在本机试探监听，跳过被其他进程占用的端口。
+type portAllocator struct {
+	repo     repository.ServiceRepository
+	min, max int
+
+	mu       sync.Mutex
+	reserved map[int]uuid.UUID // 端口 -> 服务 ID
+}
+
+// newPortAllocator 创建端口分配器
+func newPortAllocator(repo repository.ServiceRepository, min, max int) *portAllocator {
+	return &portAllocator{
+		repo:     repo,
+		min:      min,
+		max:      max,
+		reserved: make(map[int]uuid.UUID),
+	}
+}
+
+// Allocate 为服务分配空闲端口，服务之前使用的端口空闲时优先复用
+func (a *portAllocator) Allocate(ctx context.Context, serviceID uuid.UUID, preferred int) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	used, err := a.repo.UsedHostPorts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	taken := make(map[int]bool, len(used)+len(a.reserved))
+	for _, port := range used {
+		taken[port] = true
+	}
+	for port, owner := range a.reserved {
+		if owner != serviceID {
+			taken[port] = true
+		}
+	}
+
+	candidates := make([]int, 0, a.max-a.min+2)
+	if preferred >= a.min && preferred <= a.max {
+		candidates = append(candidates, preferred)
+	}
+	for port := a.min; port <= a.max; port++ {
+		candidates = append(candidates, port)
+	}
+
+	for _, port := range candidates {
+		if taken[port] || !portFree(port) {
+			continue
+		}
+		a.releaseLocked(serviceID)
+		a.reserved[port] = serviceID
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free host port in range %d-%d", a.min, a.max)
+}
+
+// Release 释放服务在内存中保留的端口
+func (a *portAllocator) Release(serviceID uuid.UUID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.releaseLocked(serviceID)
+}
+
+func (a *portAllocator) releaseLocked(serviceID uuid.UUID) {
+	for port, owner := range a.reserved {
+		if owner == serviceID {
+			delete(a.reserved, port)
+		}
+	}
+}
+
+// portFree 试探端口在本机是否可以监听
+func portFree(port int) bool {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
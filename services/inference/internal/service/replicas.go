@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+// Scale 调整运行中服务的副本数
+//
+// 扩容时立即创建副本容器并在后台等待健康检查；缩容时直接删除启动中的副本，
+// 再把编号最大的就绪副本转为 draining，负载均衡器不再向其分配请求。
+func (s *inferenceService) Scale(ctx context.Context, id uuid.UUID, replicas int, reason string) error {
+	if !s.acquire(id) {
+		return nil
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return nil
+	}
+
+	minReplicas, maxReplicas := svc.ReplicaBounds()
+	replicas = min(max(replicas, minReplicas), maxReplicas)
+	active := svc.ActiveReplicas()
+	if replicas == len(active) {
+		return nil
+	}
+
+	var created []string
+	var scaleErr error
+	if replicas > len(active) {
+		created, scaleErr = s.addReplicas(svc, replicas-len(active))
+	} else {
+		s.shrink(ctx, svc, active, len(active)-replicas)
+	}
+
+	svc.DesiredReplicas = replicas
+	svc.StatusMessage = fmt.Sprintf("Scaled from %d to %d replicas: %s", len(active), replicas, reason)
+	if scaleErr != nil {
+		svc.StatusMessage = fmt.Sprintf("Failed to scale to %d replicas: %v", replicas, scaleErr)
+	}
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		return err
+	}
+	s.awaitReplicas(id, created)
+	s.emit(domain.ServiceEventScaled, svc, svc.StatusMessage)
+
+	logger.Info("Inference service scaled",
+		zap.String("service_id", id.String()),
+		zap.Int("from", len(active)),
+		zap.Int("to", replicas),
+		zap.String("reason", reason),
+	)
+	return scaleErr
+}
+
+// shrink 缩减 n 个副本，优先删除启动中的副本，其次按编号从大到小排空就绪副本
+func (s *inferenceService) shrink(ctx context.Context, svc *domain.InferenceService, active []domain.Replica, n int) {
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].Status != active[j].Status {
+			return active[i].Status == domain.ReplicaStatusStarting
+		}
+		return active[i].Index > active[j].Index
+	})
+
+	for _, replica := range active[:n] {
+		if replica.Status == domain.ReplicaStatusReady {
+			svc.SetReplicaStatus(replica.ContainerID, domain.ReplicaStatusDraining)
+			continue
+		}
+		if err := s.executor.RemoveContainer(ctx, replica.ContainerID, true); err != nil {
+			logger.Warn("Failed to remove starting replica", zap.String("container_id", replica.ContainerID), zap.Error(err))
+			continue
+		}
+		svc.RemoveReplica(replica.ContainerID)
+	}
+}
+
+// RemoveReplica 删除副本容器，排空后的副本不再有请求，直接强制删除
+func (s *inferenceService) RemoveReplica(ctx context.Context, id uuid.UUID, containerID string) error {
+	if !s.acquire(id) {
+		return nil
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	replica := svc.FindReplica(containerID)
+	if replica == nil {
+		return nil
+	}
+	index := replica.Index
+
+	if err := s.executor.RemoveContainer(ctx, containerID, true); err != nil {
+		return err
+	}
+	svc.RemoveReplica(containerID)
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		return err
+	}
+
+	logger.Info("Inference replica removed", zap.String("service_id", id.String()), zap.Int("replica", index))
+	return nil
+}
+
+// CheckReplicas 删除容器已退出或已不存在的就绪副本，健康检查暂时失败的副本保留
+func (s *inferenceService) CheckReplicas(ctx context.Context, id uuid.UUID) error {
+	if !s.acquire(id) {
+		return nil
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, replica := range svc.ReadyReplicas() {
+		if _, err := s.executor.HealthCheck(ctx, replica.ContainerID); err == nil {
+			continue
+		}
+		info, err := s.executor.GetContainerInfo(ctx, replica.ContainerID)
+		if err == nil && info.State != "exited" && info.State != "dead" {
+			continue
+		}
+
+		message := "Container no longer exists"
+		if err == nil {
+			message = fmt.Sprintf("Container exited with code %d", info.ExitCode)
+			if removeErr := s.executor.RemoveContainer(ctx, replica.ContainerID, true); removeErr != nil {
+				logger.Warn("Failed to remove exited replica", zap.String("container_id", replica.ContainerID), zap.Error(removeErr))
+				continue
+			}
+		}
+		svc.RemoveReplica(replica.ContainerID)
+		svc.StatusMessage = fmt.Sprintf("Replica %d failed: %s", replica.Index, message)
+		changed = true
+
+		logger.Warn("Inference replica failed",
+			zap.String("service_id", id.String()),
+			zap.Int("replica", replica.Index),
+			zap.String("message", message),
+		)
+	}
+
+	if !changed {
+		return nil
+	}
+	return s.serviceRepo.Update(ctx, svc)
+}
+
+// Wake 为没有副本的运行中服务启动一个副本，有其他操作进行中时等待其结束
+func (s *inferenceService) Wake(ctx context.Context, id uuid.UUID) error {
+	for !s.acquire(id) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	defer s.release(id)
+
+	svc, err := s.load(ctx, id)
+	if err != nil {
+		return err
+	}
+	if svc.Status != domain.ServiceStatusRunning {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("service is not running: %s", svc.Status))
+	}
+	if len(svc.ActiveReplicas()) > 0 {
+		return nil
+	}
+
+	created, err := s.addReplicas(svc, 1)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusServiceUnavailable, fmt.Sprintf("failed to start a replica: %v", err))
+	}
+	svc.DesiredReplicas = 1
+	svc.StatusMessage = "Cold start"
+	if err := s.serviceRepo.Update(ctx, svc); err != nil {
+		return err
+	}
+	s.awaitReplicas(id, created)
+	s.emit(domain.ServiceEventScaled, svc, svc.StatusMessage)
+
+	logger.Info("Inference service cold start", zap.String("service_id", id.String()))
+	return nil
+}
+
+// addReplicas 创建 n 个副本容器，返回已创建的容器 ID，出错时已创建的副本保留在服务中
+func (s *inferenceService) addReplicas(svc *domain.InferenceService, n int) ([]string, error) {
+	// 与首次部署一样，拉取镜像不受调用方超时影响
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ModelDownloadTimeout)
+	defer cancel()
+
+	modelPath, err := s.modelPath(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := s.resolveSecrets(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []string
+	for i := 0; i < n; i++ {
+		replica, err := s.createReplica(ctx, svc, svc.NextReplicaIndex(), modelPath, resolved)
+		if err != nil {
+			return created, err
+		}
+		svc.AddReplica(*replica)
+		created = append(created, replica.ContainerID)
+	}
+	return created, nil
+}
+
+// awaitReplicas 在后台等待新副本的健康检查
+func (s *inferenceService) awaitReplicas(id uuid.UUID, containerIDs []string) {
+	for _, containerID := range containerIDs {
+		go s.awaitReplica(id, containerID)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+)
+
+// RolloutController 定期评估进行中的灰度发布
+//
+// 发布状态保存在数据库中，服务重启后控制器会继续推进之前的发布。
+type RolloutController struct {
+	endpoints EndpointService
+	interval  time.Duration
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewRolloutController 创建灰度发布控制器
+func NewRolloutController(endpoints EndpointService, interval time.Duration) *RolloutController {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	return &RolloutController{
+		endpoints: endpoints,
+		interval:  interval,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start 开始定期评估
+func (c *RolloutController) Start() {
+	c.wg.Add(1)
+	go c.loop()
+
+	logger.Info("Rollout controller started", zap.Duration("interval", c.interval))
+}
+
+// Stop 停止定期评估
+func (c *RolloutController) Stop() {
+	close(c.stopCh)
+	c.wg.Wait()
+}
+
+// loop 定期评估循环
+func (c *RolloutController) loop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		if err := c.endpoints.EvaluateRollouts(ctx); err != nil {
+			logger.Error("Rollout evaluation failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/config"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/inference/internal/domain"
+)
+
+const (
+	// minLatencySamples 按延迟扩缩容所需的最少请求数，样本太少时 p95 不可靠
+	minLatencySamples = 20
+	// latencyHoldRatio p95 延迟超过目标的该比例时不缩容
+	latencyHoldRatio = 0.8
+)
+
+// ScalePolicy 服务的扩缩容策略
+type ScalePolicy struct {
+	MinReplicas      int
+	MaxReplicas      int
+	TargetInFlight   int           // 每个副本的目标并发请求数
+	TargetLatencyMs  float64       // p95 延迟目标，0 表示不按延迟扩容
+	ScaleToZeroAfter time.Duration // MinReplicas 为 0 时空闲多久后缩容到零，0 表示不缩容到零
+}
+
+// ScaleMetrics 一个评估周期内观察到的服务负载
+type ScaleMetrics struct {
+	Replicas     int           // 就绪和启动中的副本数
+	InFlight     int           // 正在处理的请求数
+	Queued       int           // 等待可用副本的请求数
+	Requests     int64         // 统计窗口内的请求数
+	P95LatencyMs float64       // 统计窗口内的 p95 延迟
+	Idle         time.Duration // 距最近一个请求的时间
+}
+
+// Recommend 根据负载计算期望副本数和原因
+//
+// 并发请求数（含排队）按 TargetInFlight 折算副本数；p95 延迟超过目标时在当前副本数上加一，
+// 接近目标时不低于当前副本数。没有负载时保留一个副本，直到空闲超过 ScaleToZeroAfter 且允许缩容到零。
+func (p ScalePolicy) Recommend(m ScaleMetrics) (int, string) {
+	target := max(p.TargetInFlight, 1)
+	want := (m.InFlight + m.Queued + target - 1) / target
+	reason := fmt.Sprintf("%d in-flight and %d queued requests", m.InFlight, m.Queued)
+
+	if p.TargetLatencyMs > 0 && m.Replicas > 0 && m.Requests >= minLatencySamples {
+		switch {
+		case m.P95LatencyMs > p.TargetLatencyMs && m.Replicas+1 > want:
+			want = m.Replicas + 1
+			reason = fmt.Sprintf("p95 latency %.0fms above target %.0fms", m.P95LatencyMs, p.TargetLatencyMs)
+		case m.P95LatencyMs > p.TargetLatencyMs*latencyHoldRatio && m.Replicas > want:
+			want = m.Replicas
+			reason = fmt.Sprintf("p95 latency %.0fms close to target %.0fms", m.P95LatencyMs, p.TargetLatencyMs)
+		}
+	}
+
+	if want == 0 && m.Replicas > 0 {
+		if p.MinReplicas == 0 && p.ScaleToZeroAfter > 0 && m.Idle >= p.ScaleToZeroAfter {
+			reason = fmt.Sprintf("idle for %s", m.Idle.Round(time.Second))
+		} else {
+			want = 1
+		}
+	}
+
+	if want < p.MinReplicas {
+		return p.MinReplicas, fmt.Sprintf("minimum of %d replicas", p.MinReplicas)
+	}
+	if want > p.MaxReplicas {
+		return p.MaxReplicas, fmt.Sprintf("maximum of %d replicas (%s)", p.MaxReplicas, reason)
+	}
+	return want, reason
+}
+
+// scalePolicy 由服务配置生成扩缩容策略
+func scalePolicy(svc *domain.InferenceService, cfg *config.Config) ScalePolicy {
+	minReplicas, maxReplicas := svc.ReplicaBounds()
+	return ScalePolicy{
+		MinReplicas:      minReplicas,
+		MaxReplicas:      maxReplicas,
+		TargetInFlight:   svc.TargetInFlightPerReplica(),
+		TargetLatencyMs:  float64(svc.TargetLatency),
+		ScaleToZeroAfter: cfg.ScaleToZeroAfter,
+	}
+}
+
+// scaleState 服务的缩容稳定窗口
+type scaleState struct {
+	lowSince time.Time // 期望副本数开始低于当前副本数的时间
+	lowMax   int       // 窗口内期望副本数的最大值
+}
+
+// stabilize 扩容立即生效；缩容需要期望副本数持续低于当前副本数 delay 时间，并缩到窗口内的最大期望值
+func (st *scaleState) stabilize(current, want int, now time.Time, delay time.Duration) int {
+	if want >= current {
+		st.lowSince = time.Time{}
+		return want
+	}
+
+	if st.lowSince.IsZero() {
+		st.lowSince = now
+		st.lowMax = want
+	}
+	st.lowMax = max(st.lowMax, want)
+	if now.Sub(st.lowSince) < delay {
+		return current
+	}
+
+	target := st.lowMax
+	st.lowSince = time.Time{}
+	return target
+}
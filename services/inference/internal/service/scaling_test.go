@@ -0,0 +1,151 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScalePolicyRecommend(t *testing.T) {
+	policy := ScalePolicy{
+		MinReplicas:      1,
+		MaxReplicas:      5,
+		TargetInFlight:   4,
+		ScaleToZeroAfter: 10 * time.Minute,
+	}
+	latencyPolicy := policy
+	latencyPolicy.TargetLatencyMs = 200
+	zeroPolicy := policy
+	zeroPolicy.MinReplicas = 0
+
+	tests := []struct {
+		name    string
+		policy  ScalePolicy
+		metrics ScaleMetrics
+		want    int
+	}{
+		{
+			name:    "scale up for in-flight and queued requests",
+			policy:  policy,
+			metrics: ScaleMetrics{Replicas: 1, InFlight: 10, Queued: 2},
+			want:    3,
+		},
+		{
+			name:    "scale down when load drops",
+			policy:  policy,
+			metrics: ScaleMetrics{Replicas: 4, InFlight: 3},
+			want:    1,
+		},
+		{
+			name:    "clamp to max replicas",
+			policy:  policy,
+			metrics: ScaleMetrics{Replicas: 2, InFlight: 100},
+			want:    5,
+		},
+		{
+			name:    "clamp to min replicas",
+			policy:  ScalePolicy{MinReplicas: 2, MaxReplicas: 5, TargetInFlight: 4},
+			metrics: ScaleMetrics{Replicas: 3},
+			want:    2,
+		},
+		{
+			name:    "keep one replica while idle time is short",
+			policy:  zeroPolicy,
+			metrics: ScaleMetrics{Replicas: 2, Idle: time.Minute},
+			want:    1,
+		},
+		{
+			name:    "scale to zero after idle",
+			policy:  zeroPolicy,
+			metrics: ScaleMetrics{Replicas: 1, Idle: 15 * time.Minute},
+			want:    0,
+		},
+		{
+			name:    "no scale to zero with min replicas",
+			policy:  policy,
+			metrics: ScaleMetrics{Replicas: 1, Idle: time.Hour},
+			want:    1,
+		},
+		{
+			name:    "add a replica when p95 latency is above target",
+			policy:  latencyPolicy,
+			metrics: ScaleMetrics{Replicas: 2, InFlight: 4, Requests: 50, P95LatencyMs: 300},
+			want:    3,
+		},
+		{
+			name:    "hold replicas when p95 latency is close to target",
+			policy:  latencyPolicy,
+			metrics: ScaleMetrics{Replicas: 3, InFlight: 4, Requests: 50, P95LatencyMs: 180},
+			want:    3,
+		},
+		{
+			name:    "ignore latency with too few samples",
+			policy:  latencyPolicy,
+			metrics: ScaleMetrics{Replicas: 3, InFlight: 4, Requests: minLatencySamples - 1, P95LatencyMs: 300},
+			want:    1,
+		},
+		{
+			name:    "latency scale up respects max replicas",
+			policy:  latencyPolicy,
+			metrics: ScaleMetrics{Replicas: 5, InFlight: 4, Requests: 50, P95LatencyMs: 300},
+			want:    5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := tt.policy.Recommend(tt.metrics)
+			if got != tt.want {
+				t.Errorf("Recommend() = %d (%s), want %d", got, reason, tt.want)
+			}
+			if reason == "" {
+				t.Error("Recommend() returned an empty reason")
+			}
+		})
+	}
+}
+
+// stabilizeStep 一次评估的当前副本数、期望副本数、距开始的时间和预期结果
+type stabilizeStep struct {
+	current, want int
+	after         time.Duration
+	result        int
+}
+
+func TestScaleStateStabilize(t *testing.T) {
+	delay := time.Minute
+	start := time.Now()
+
+	tests := []struct {
+		name  string
+		steps []stabilizeStep
+	}{
+		{
+			name:  "scale up immediately",
+			steps: []stabilizeStep{{2, 4, 0, 4}},
+		},
+		{
+			name:  "hold current replicas during cooldown",
+			steps: []stabilizeStep{{4, 1, 0, 4}, {4, 1, 30 * time.Second, 4}},
+		},
+		{
+			name:  "scale down to the window maximum after cooldown",
+			steps: []stabilizeStep{{4, 1, 0, 4}, {4, 3, 20 * time.Second, 4}, {4, 2, time.Minute, 3}},
+		},
+		{
+			name:  "load increase restarts cooldown",
+			steps: []stabilizeStep{{4, 1, 0, 4}, {4, 4, 50 * time.Second, 4}, {4, 1, 70 * time.Second, 4}, {4, 1, 90 * time.Second, 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var state scaleState
+			for i, step := range tt.steps {
+				got := state.stabilize(step.current, step.want, start.Add(step.after), delay)
+				if got != step.result {
+					t.Errorf("step %d: stabilize(%d, %d) = %d, want %d", i, step.current, step.want, got, step.result)
+				}
+			}
+		})
+	}
+}
@@ -2,23 +2,39 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/database"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/quota"
+	pkgminio "github.com/plucky-groove3/ai-train-infer-platform/pkg/minio"
 	pkgRedis "github.com/plucky-groove3/ai-train-infer-platform/pkg/redis"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/webhook"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/admission"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/artifact"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/checkpoint"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/config"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/dataset"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/handler"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/logarchive"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/pipeline"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/scheduler"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/service"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/template"
 )
 
 func main() {
@@ -59,22 +75,299 @@ func main() {
 	defer redisClient.Close()
 	logger.Info("Redis connected")
 
+	// 初始化对象存储
+	minioCfg := pkgminio.DefaultConfig()
+	minioCfg.Endpoint = cfg.MinIOEndpoint
+	minioCfg.AccessKeyID = cfg.MinIOAccessKey
+	minioCfg.SecretAccessKey = cfg.MinIOSecretKey
+	minioCfg.UseSSL = cfg.MinIOUseSSL
+	storage, err := pkgminio.New(minioCfg)
+	if err != nil {
+		logger.Fatal("Failed to create MinIO client", zap.Error(err))
+	}
+
 	// 初始化仓库
 	jobRepo := repository.NewJobRepository(db)
+	if err := jobRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate training jobs table", zap.Error(err))
+	}
 	logRepo := repository.NewLogRepository(redisClient.GetClient(), cfg.LogStreamMaxLen)
+	projectRepo := repository.NewProjectRepository(db)
+	attemptRepo := repository.NewAttemptRepository(db)
+	if err := attemptRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate job attempts table", zap.Error(err))
+	}
+	checkpointRepo := repository.NewCheckpointRepository(db)
+	if err := checkpointRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate checkpoints table", zap.Error(err))
+	}
+	logSegmentRepo := repository.NewLogSegmentRepository(db)
+	if err := logSegmentRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate log segments table", zap.Error(err))
+	}
+	pipelineRepo := repository.NewPipelineRepository(db)
+	if err := pipelineRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate pipeline tables", zap.Error(err))
+	}
+	modelRepo := repository.NewModelRepository(db)
+	templateRepo := repository.NewTemplateRepository(db)
+	if err := templateRepo.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate job templates table", zap.Error(err))
+	}
+	metricCollector := executor.NewMetricCollector(db)
+	if err := metricCollector.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate training metrics table", zap.Error(err))
+	}
+	defer metricCollector.Stop()
+
+	// 初始化事件总线和 Webhook 投递
+	eventBus := events.NewBus(redisClient, cfg.EventStream, cfg.EventStreamMaxLen)
+	webhookConfig := webhook.DefaultConfig()
+	webhookConfig.MaxAttempts = cfg.WebhookMaxAttempts
+	webhookConfig.Timeout = cfg.WebhookTimeout
+	webhookDispatcher := webhook.NewDispatcher(db, eventBus, webhookConfig)
+	if err := webhookDispatcher.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate webhook tables", zap.Error(err))
+	}
+
+	// 初始化密钥存储，未配置主密钥时任务不能引用密钥
+	var secretStore secrets.Manager
+	if cfg.SecretsMasterKey != "" {
+		masterKey, err := secrets.ParseMasterKey(cfg.SecretsMasterKey)
+		if err != nil {
+			logger.Fatal("Invalid SECRETS_MASTER_KEY", zap.Error(err))
+		}
+		keyProvider, err := secrets.NewLocalKeyProvider(cfg.SecretsMasterKeyID, masterKey)
+		if err != nil {
+			logger.Fatal("Failed to create secrets key provider", zap.Error(err))
+		}
+		secretStore = secrets.NewManager(db, keyProvider)
+		if err := secretStore.AutoMigrate(); err != nil {
+			logger.Fatal("Failed to migrate secrets tables", zap.Error(err))
+		}
+	} else {
+		logger.Warn("SECRETS_MASTER_KEY not set, secrets store disabled")
+	}
+
+	// 初始化指标服务，训练容器通过带任务令牌的接口或 AITIP_METRIC 日志行上报指标
+	metricsSecret := cfg.MetricsTokenSecret
+	if metricsSecret == "" {
+		metricsSecret = randomSecret()
+		logger.Warn("METRICS_TOKEN_SECRET not set, using a random secret; metrics tokens of running jobs become invalid after restart")
+	}
+	var runMetrics service.RunMetricsForwarder
+	if cfg.ExperimentServiceURL != "" {
+		runMetrics = service.NewExperimentMetricsClient(cfg.ExperimentServiceURL)
+	}
+	metricService := service.NewMetricService(jobRepo, metricCollector, runMetrics, metricsSecret)
+	metricsEndpoint := &executor.MetricsEndpoint{BaseURL: cfg.MetricsReportURL, Secret: metricsSecret}
 
 	// 初始化执行器
-	dockerExec := executor.NewDockerExecutor(
-		logRepo,
-		cfg.DockerNetwork,
-		cfg.DockerVolumeBase,
+	var (
+		jobExecutor  executor.Executor
+		reconcilable executor.Reconcilable
+		dockerExec   *executor.DockerExecutor
+		gpuAllocator *executor.GPUAllocator
 	)
+	gpuCapacity := cfg.SchedulerGPUCapacity
+	switch cfg.Executor {
+	case "kubernetes":
+		kubeExec, err := newKubernetesExecutor(cfg, logRepo)
+		if err != nil {
+			logger.Fatal("Failed to create kubernetes executor", zap.Error(err))
+		}
+		kubeExec.SetMetricsHandler(metricService.OnMetrics)
+		kubeExec.SetMetricsEndpoint(metricsEndpoint)
+		kubeExec.SetSecretManager(secretStore)
+		jobExecutor, reconcilable = kubeExec, kubeExec
+		if gpuCapacity < 0 {
+			if gpuCapacity, err = kubeExec.GetGPUCount(context.Background()); err != nil {
+				logger.Fatal("Failed to detect cluster GPU capacity", zap.Error(err))
+			}
+		}
+	case "docker":
+		dockerExec, err = executor.NewDockerExecutor(
+			logRepo,
+			cfg.DockerNetwork,
+			cfg.DockerVolumeBase,
+		)
+		if err != nil {
+			logger.Fatal("Failed to create docker executor", zap.Error(err))
+		}
+		dockerExec.SetMetricsHandler(metricService.OnMetrics)
+		dockerExec.SetMetricsEndpoint(metricsEndpoint)
+		dockerExec.SetMetricsBroadcaster(metricCollector)
+		dockerExec.SetSecretManager(secretStore)
+		jobExecutor, reconcilable = dockerExec, dockerExec
+		gpuAllocator = dockerExec.GPUAllocator()
+		if gpuCapacity < 0 {
+			gpuCapacity = dockerExec.GetGPUCount()
+		}
+	default:
+		logger.Fatal("Unknown training executor", zap.String("executor", cfg.Executor))
+	}
+	logger.Info("Training executor initialized", zap.String("executor", cfg.Executor))
+
+	// 初始化调度器
+	jobScheduler := scheduler.NewScheduler(jobRepo, logRepo, attemptRepo, jobExecutor, scheduler.Capacity{
+		CPU:      cfg.SchedulerCPUCapacity,
+		MemoryGB: cfg.SchedulerMemoryGB,
+		GPU:      gpuCapacity,
+		MaxJobs:  cfg.MaxConcurrentJobs,
+	}, cfg.SchedulerInterval)
+	if gpuAllocator != nil {
+		jobScheduler.SetGPUAllocator(gpuAllocator)
+	}
+	timeouts := executor.DefaultTimeoutConfig()
+	timeouts.StartupTimeout = cfg.JobStartupTimeout
+	timeouts.OverallTimeout = cfg.DefaultTimeout
+	timeouts.StallTimeout = cfg.JobStallTimeout
+	jobScheduler.SetTimeouts(timeouts)
+	jobScheduler.SetEventHandler(service.NewJobEventPublisher(eventBus))
+
+	// 初始化检查点注册表
+	registry := checkpoint.NewRegistry(checkpointRepo, jobRepo, logRepo, storage, checkpoint.Config{
+		Bucket:       cfg.MinIOBucket,
+		BestMetric:   cfg.CheckpointBestMetric,
+		BestMode:     cfg.CheckpointBestMode,
+		ScanInterval: cfg.CheckpointScanInterval,
+		CacheDir:     cfg.CheckpointCacheDir,
+	})
+	if dockerExec != nil {
+		dockerExec.SetCheckpointHandler(registry.OnAnnouncement)
+	}
+	jobScheduler.SetCheckpoints(registry)
+	if err := registry.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start checkpoint registry", zap.Error(err))
+	}
+	defer registry.Stop()
+
+	// 初始化数据集准备和产物上传：对象存储中的数据集在启动容器前下载或生成下载地址，任务结束后上传输出目录
+	datasetMode := cfg.DatasetMode
+	if datasetMode == "" {
+		datasetMode = dataset.ModeCache
+		if cfg.Executor == "kubernetes" {
+			datasetMode = dataset.ModeInit
+		}
+	}
+	switch datasetMode {
+	case dataset.ModeCache, dataset.ModeInit:
+		if (datasetMode == dataset.ModeCache) != (cfg.Executor == "docker") {
+			logger.Fatal("Dataset mode is not supported by the executor", zap.String("mode", datasetMode), zap.String("executor", cfg.Executor))
+		}
+	case dataset.ModeURL:
+	default:
+		logger.Fatal("Unknown dataset mode", zap.String("mode", datasetMode))
+	}
+	jobScheduler.SetDatasets(dataset.NewMaterializer(storage, dataset.Config{
+		Mode:      datasetMode,
+		CacheDir:  cfg.DatasetCacheDir,
+		URLExpiry: cfg.DatasetURLExpiry,
+	}))
+	artifactUploader := artifact.NewUploader(jobRepo, logRepo, storage, artifact.Config{Bucket: cfg.ArtifactBucket})
+	if err := artifactUploader.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start artifact uploader", zap.Error(err))
+	}
+	jobScheduler.SetArtifacts(artifactUploader)
+
+	// 初始化日志归档
+	logArchiver := logarchive.NewArchiver(logRepo, logSegmentRepo, jobRepo, storage, logarchive.Config{
+		Bucket:       cfg.LogArchiveBucket,
+		Interval:     cfg.LogArchiveInterval,
+		SegmentLines: cfg.LogArchiveSegmentLines,
+		MaxAge:       cfg.LogArchiveMaxAge,
+	})
+	if err := logArchiver.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start log archiver", zap.Error(err))
+	}
+	defer logArchiver.Stop()
+
+	// 先与容器状态协调，再开始调度，避免重启前的任务占用的资源被重复分配
+	reconciler := scheduler.NewReconciler(jobRepo, logRepo, reconcilable, jobScheduler, cfg.ReconcileInterval)
+	if err := reconciler.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start reconciler", zap.Error(err))
+	}
+	defer reconciler.Stop()
+
+	if err := jobScheduler.Start(context.Background()); err != nil {
+		logger.Fatal("Failed to start scheduler", zap.Error(err))
+	}
+	defer jobScheduler.Stop()
+
+	// 终止超时和停滞的任务
+	watchdog := scheduler.NewWatchdog(jobRepo, logRepo, jobScheduler, timeouts, cfg.WatchdogInterval)
+	metricService.SetActivityHandler(watchdog.Touch)
+	watchdog.Start()
+	defer watchdog.Stop()
+
+	webhookDispatcher.Start()
+	defer webhookDispatcher.Stop()
+
+	// 初始化配额检查器
+	quotaChecker := quota.NewChecker(db)
+
+	// 初始化其他服务的客户端
+	var (
+		inferenceClient pipeline.InferenceClient
+		datasetClient   pipeline.DatasetClient
+	)
+	if cfg.InferenceServiceURL != "" {
+		inferenceClient = pipeline.NewInferenceClient(cfg.InferenceServiceURL)
+	}
+	if cfg.DataServiceURL != "" {
+		datasetClient = pipeline.NewDatasetClient(cfg.DataServiceURL)
+	}
+
+	// 初始化准入控制，任务保存前校验镜像、宿主机挂载路径和 GPU
+	admissionConfig := admission.Config{
+		DefaultPolicy: admission.ImagePolicy{
+			AllowedRegistries: admission.ParseList(cfg.AdmissionAllowedRegistries),
+			AllowedImages:     admission.ParseList(cfg.AdmissionAllowedImages),
+			RequireDigest:     cfg.AdmissionRequireDigest,
+		},
+		HostPaths:    cfg.Executor == "docker",
+		DatasetRoots: admission.ParseList(cfg.AdmissionDatasetRoots),
+		OutputRoots:  append(admission.ParseList(cfg.AdmissionOutputRoots), cfg.PipelineOutputBase), // 流水线步骤的默认输出目录
+		GPUCapacity:  gpuCapacity,
+	}
+	if err := admissionConfig.DefaultPolicy.Validate(); err != nil {
+		logger.Fatal("Invalid default image policy", zap.Error(err))
+	}
+	if gpuAllocator != nil {
+		admissionConfig.GPUs = gpuAllocator
+	}
+	admissionController := admission.NewController(db, admissionConfig, datasetClient)
+	if err := admissionController.AutoMigrate(); err != nil {
+		logger.Fatal("Failed to migrate image policies table", zap.Error(err))
+	}
 
 	// 初始化服务
-	jobService := service.NewJobService(cfg, jobRepo, logRepo, dockerExec)
+	jobService := service.NewJobService(cfg, jobRepo, logRepo, attemptRepo, checkpointRepo, jobExecutor, jobScheduler, quotaChecker, logArchiver, secretStore, admissionController)
+	checkpointService := service.NewCheckpointService(jobRepo, checkpointRepo, registry)
+	artifactService := service.NewArtifactService(jobRepo, artifactUploader)
+
+	// 初始化流水线引擎，训练步骤复用任务服务创建任务，部署步骤调用推理服务
+	pipelineEngine := pipeline.NewEngine(pipelineRepo, modelRepo, jobService, metricCollector, inferenceClient, datasetClient, pipeline.Config{
+		OutputBase: cfg.PipelineOutputBase,
+		Interval:   cfg.PipelineInterval,
+	})
+	pipelineEngine.SetEventPublisher(eventBus)
+	pipelineEngine.Start()
+	defer pipelineEngine.Stop()
+	pipelineService := service.NewPipelineService(pipelineRepo, pipelineEngine)
+	templateService := service.NewTemplateService(templateRepo, template.NewTemplateManager(), jobService)
 
 	// 初始化处理器
 	jobHandler := handler.NewJobHandler(jobService)
+	quotaHandler := handler.NewQuotaHandler(quotaChecker)
+	clusterHandler := handler.NewClusterHandler(gpuAllocator)
+	checkpointHandler := handler.NewCheckpointHandler(checkpointService)
+	artifactHandler := handler.NewArtifactHandler(artifactService)
+	metricHandler := handler.NewMetricHandler(metricService, metricCollector)
+	pipelineHandler := handler.NewPipelineHandler(pipelineService)
+	templateHandler := handler.NewTemplateHandler(templateService)
+	admissionHandler := handler.NewAdmissionHandler(admissionController, projectRepo)
+	webhookHandler := handler.NewWebhookHandler(webhook.NewManager(db, cfg.WebhookAllowHTTP, webhookDispatcher.Notify), projectRepo)
 
 	// 设置 Gin 模式
 	if cfg.Environment == "production" {
@@ -104,6 +397,36 @@ func main() {
 	{
 		// 注册任务路由
 		jobHandler.RegisterRoutes(v1)
+
+		// 注册检查点路由
+		checkpointHandler.RegisterRoutes(v1)
+		artifactHandler.RegisterRoutes(v1)
+
+		// 注册指标路由
+		metricHandler.RegisterRoutes(v1)
+
+		// 注册配额路由
+		quotaHandler.RegisterRoutes(v1)
+
+		// 注册集群资源路由
+		clusterHandler.RegisterRoutes(v1)
+
+		// 注册流水线路由
+		pipelineHandler.RegisterRoutes(v1)
+
+		// 注册任务模板路由
+		templateHandler.RegisterRoutes(v1)
+
+		// 注册准入策略路由
+		admissionHandler.RegisterRoutes(v1)
+
+		// 注册 Webhook 路由
+		webhookHandler.RegisterRoutes(v1)
+
+		// 注册密钥路由
+		if secretStore != nil {
+			handler.NewSecretHandler(secretStore, projectRepo).RegisterRoutes(v1)
+		}
 	}
 
 	// 创建 HTTP 服务器
@@ -139,6 +462,56 @@ func main() {
 	logger.Info("Training Service stopped")
 }
 
+// newKubernetesExecutor 根据配置创建 Kubernetes 执行器
+func newKubernetesExecutor(cfg *config.Config, logRepo repository.LogRepository) (*executor.KubernetesExecutor, error) {
+	clientset, err := executor.NewKubernetesClientset(cfg.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeSelector, err := executor.ParseNodeSelector(cfg.KubernetesNodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	tolerations, err := executor.ParseTolerations(cfg.KubernetesTolerations)
+	if err != nil {
+		return nil, err
+	}
+
+	var pullSecrets []string
+	for _, secret := range strings.Split(cfg.KubernetesImagePullSecrets, ",") {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			pullSecrets = append(pullSecrets, secret)
+		}
+	}
+
+	return executor.NewKubernetesExecutor(clientset, logRepo, executor.KubernetesConfig{
+		Namespace:        cfg.KubernetesNamespace,
+		ServiceAccount:   cfg.KubernetesServiceAccount,
+		NodeSelector:     nodeSelector,
+		Tolerations:      tolerations,
+		ImagePullSecrets: pullSecrets,
+		GPUResourceName:  cfg.KubernetesGPUResource,
+		GPUTypeLabel:     cfg.KubernetesGPUTypeLabel,
+		DataPVC:          cfg.KubernetesDataPVC,
+		OutputPVC:        cfg.KubernetesOutputPVC,
+		MinIOImage:       cfg.KubernetesMinIOImage,
+		MinIOEndpoint:    cfg.MinIOEndpoint,
+		MinIOAccessKey:   cfg.MinIOAccessKey,
+		MinIOSecretKey:   cfg.MinIOSecretKey,
+		MinIOUseSSL:      cfg.MinIOUseSSL,
+	}), nil
+}
+
+// randomSecret 生成随机密钥
+func randomSecret() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		logger.Fatal("Failed to generate secret", zap.Error(err))
+	}
+	return hex.EncodeToString(buf)
+}
+
 // corsMiddleware CORS 中间件
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,358 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	pkgminio "github.com/plucky-groove3/ai-train-infer-platform/pkg/minio"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/pipeline"
+)
+
+// 违规项代码
+const (
+	CodeInvalidImage      = "invalid_image"
+	CodeImageNotAllowed   = "image_not_allowed"
+	CodeDigestRequired    = "digest_required"
+	CodeDigestMismatch    = "digest_mismatch"
+	CodeInvalidPath       = "invalid_path"
+	CodePathNotAllowed    = "path_not_allowed"
+	CodeGPUUnavailable    = "gpu_unavailable"
+	CodeDatasetNotFound   = "dataset_not_found"
+	CodeDatasetNotReady   = "dataset_not_ready"
+	CodeDatasetForbidden  = "dataset_forbidden"
+	CodeDatasetConflict   = "dataset_conflict"
+	CodeDatasetUnresolved = "dataset_unresolved"
+	CodeDatasetVersion    = "dataset_version_mismatch"
+)
+
+// Violation 准入检查未通过的一项
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GPUInventory 宿主机 GPU 清单
+type GPUInventory interface {
+	// CountMatching 返回型号符合 gpuType 的 GPU 总数，gpuType 为空时返回全部
+	CountMatching(gpuType string) int
+}
+
+// Config 准入配置
+type Config struct {
+	DefaultPolicy ImagePolicy  // 组织未设置镜像策略时使用
+	HostPaths     bool         // 数据集和输出路径为宿主机目录（Docker 执行器），需要校验挂载根目录
+	DatasetRoots  []string     // 允许挂载的数据集目录，为空时不允许宿主机数据集路径
+	OutputRoots   []string     // 允许挂载的输出目录，为空时不允许宿主机输出路径
+	GPUCapacity   int          // 可调度的 GPU 总数
+	GPUs          GPUInventory // 按型号检查 GPU，为 nil 时只检查总数
+}
+
+// PutPolicyRequest 设置组织镜像策略请求
+type PutPolicyRequest struct {
+	AllowedRegistries []string `json:"allowed_registries" binding:"max=100"`
+	AllowedImages     []string `json:"allowed_images" binding:"max=500"`
+	RequireDigest     bool     `json:"require_digest"`
+}
+
+// Controller 任务准入控制接口
+type Controller interface {
+	// Admit 在任务保存前校验镜像、挂载路径和 GPU，datasetID 非空时解析为任务的数据集路径；
+	// 未通过时返回 *errors.AppError，Details 为全部违规项
+	Admit(ctx context.Context, userID uuid.UUID, job *domain.TrainingJob, datasetID string) error
+	// GetPolicy 获取组织的镜像策略，未设置时返回默认策略
+	GetPolicy(ctx context.Context, orgID uuid.UUID) (*ImagePolicy, error)
+	// PutPolicy 设置组织的镜像策略
+	PutPolicy(ctx context.Context, userID, orgID uuid.UUID, req *PutPolicyRequest) (*ImagePolicy, error)
+	// DeletePolicy 删除组织的镜像策略，之后使用默认策略
+	DeletePolicy(ctx context.Context, orgID uuid.UUID) error
+	// AutoMigrate 创建镜像策略表
+	AutoMigrate() error
+}
+
+// controller 准入控制实现
+type controller struct {
+	db       *gorm.DB
+	cfg      Config
+	datasets pipeline.DatasetClient
+}
+
+// NewController 创建准入控制器，datasets 为 nil 时不支持 dataset_id
+func NewController(db *gorm.DB, cfg Config, datasets pipeline.DatasetClient) Controller {
+	cfg.DatasetRoots = cleanRoots(cfg.DatasetRoots)
+	cfg.OutputRoots = cleanRoots(cfg.OutputRoots)
+	return &controller{db: db, cfg: cfg, datasets: datasets}
+}
+
+// AutoMigrate 创建镜像策略表
+func (c *controller) AutoMigrate() error {
+	return c.db.AutoMigrate(&ImagePolicy{})
+}
+
+// Admit 执行全部准入检查
+func (c *controller) Admit(ctx context.Context, userID uuid.UUID, job *domain.TrainingJob, datasetID string) error {
+	var violations []Violation
+
+	if datasetID != "" {
+		v, err := c.resolveDataset(ctx, userID, job, datasetID)
+		if err != nil {
+			return err
+		}
+		violations = append(violations, v...)
+	} else if job.DatasetVersion != "" {
+		violations = append(violations, Violation{Field: "dataset_version", Code: CodeDatasetConflict, Message: "dataset_version requires dataset_id"})
+	}
+	// 对象存储中的数据集只能来自任务所在项目的 bucket
+	if bucket, _, ok := job.StoredDataset(); ok && bucket != pkgminio.GetProjectBucketName(job.ProjectID.String()) {
+		violations = append(violations, Violation{
+			Field:   "dataset_path",
+			Code:    CodePathNotAllowed,
+			Message: fmt.Sprintf("dataset_path must be in the project bucket %s", pkgminio.GetProjectBucketName(job.ProjectID.String())),
+		})
+	}
+
+	policy, err := c.policyForProject(ctx, job.ProjectID)
+	if err != nil {
+		return err
+	}
+	violations = append(violations, policy.check(job.Image)...)
+
+	if c.cfg.HostPaths {
+		violations = append(violations, checkHostPath("dataset_path", job.DatasetPath, c.cfg.DatasetRoots)...)
+		violations = append(violations, checkHostPath("output_path", job.OutputPath, c.cfg.OutputRoots)...)
+	}
+
+	violations = append(violations, c.checkGPUs(job)...)
+
+	if len(violations) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("job rejected by admission: %s", violations[0].Message)
+	if len(violations) > 1 {
+		message = fmt.Sprintf("%s (and %d more)", message, len(violations)-1)
+	}
+	return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, message).WithDetails(violations)
+}
+
+// resolveDataset 将数据集 ID 解析为数据集路径并记录在任务上
+func (c *controller) resolveDataset(ctx context.Context, userID uuid.UUID, job *domain.TrainingJob, id string) ([]Violation, error) {
+	violation := func(code, message string) []Violation {
+		return []Violation{{Field: "dataset_id", Code: code, Message: message}}
+	}
+
+	if job.DatasetPath != "" {
+		return violation(CodeDatasetConflict, "dataset_id and dataset_path are mutually exclusive"), nil
+	}
+	if c.datasets == nil {
+		return violation(CodeDatasetUnresolved, "dataset_id is not supported: the data service is not configured"), nil
+	}
+	datasetID, err := uuid.Parse(id)
+	if err != nil {
+		return violation(CodeDatasetNotFound, fmt.Sprintf("invalid dataset_id: %s", id)), nil
+	}
+
+	dataset, err := c.datasets.GetDataset(ctx, userID, datasetID)
+	if err != nil {
+		var apiErr *pipeline.APIError
+		if errors.As(err, &apiErr) && apiErr.IsClientError() {
+			return violation(CodeDatasetNotFound, fmt.Sprintf("dataset %s: %s", id, apiErr.Message)), nil
+		}
+		return nil, apperrors.Wrap(apperrors.ErrServiceUnavailable, http.StatusBadGateway, fmt.Sprintf("failed to resolve dataset %s: %v", id, err))
+	}
+	if dataset.ProjectID != job.ProjectID {
+		return violation(CodeDatasetForbidden, fmt.Sprintf("dataset %s belongs to another project", id)), nil
+	}
+	if dataset.Status != "ready" {
+		return violation(CodeDatasetNotReady, fmt.Sprintf("dataset %s is %s", id, dataset.Status)), nil
+	}
+
+	if job.DatasetVersion != "" && job.DatasetVersion != dataset.Checksum {
+		return []Violation{{
+			Field:   "dataset_version",
+			Code:    CodeDatasetVersion,
+			Message: fmt.Sprintf("dataset %s has changed: checksum is %q, not %q", id, dataset.Checksum, job.DatasetVersion),
+		}}, nil
+	}
+
+	job.DatasetID = &dataset.ID
+	job.DatasetPath = "s3://" + dataset.StoragePath
+	job.DatasetVersion = dataset.Checksum
+	return nil, nil
+}
+
+// checkGPUs 检查集群是否有足够的 GPU 运行任务
+func (c *controller) checkGPUs(job *domain.TrainingJob) []Violation {
+	if job.GPUCount == 0 {
+		return nil
+	}
+
+	need := job.GPUCount * job.Distributed.Nodes()
+	if need > c.cfg.GPUCapacity {
+		return []Violation{{
+			Field:   "gpu_count",
+			Code:    CodeGPUUnavailable,
+			Message: fmt.Sprintf("job needs %d GPUs but only %d are available for scheduling", need, c.cfg.GPUCapacity),
+		}}
+	}
+	if c.cfg.GPUs != nil && job.GPUType != "" {
+		if matching := c.cfg.GPUs.CountMatching(job.GPUType); matching < need {
+			return []Violation{{
+				Field:   "gpu_type",
+				Code:    CodeGPUUnavailable,
+				Message: fmt.Sprintf("job needs %d %s GPUs but the host has %d", need, job.GPUType, matching),
+			}}
+		}
+	}
+	return nil
+}
+
+// checkHostPath 检查宿主机路径解析符号链接后是否位于允许的根目录下，未配置根目录时拒绝所有宿主机路径；
+// 对象存储路径不在宿主机上，不做检查
+func checkHostPath(field, value string, roots []string) []Violation {
+	if value == "" || strings.HasPrefix(value, "s3://") {
+		return nil
+	}
+	if len(roots) == 0 {
+		return []Violation{{Field: field, Code: CodePathNotAllowed, Message: fmt.Sprintf("%s must be an s3:// path: no host directories are allowed", field)}}
+	}
+	if !path.IsAbs(value) || path.Clean(value) != strings.TrimSuffix(value, "/") {
+		return []Violation{{Field: field, Code: CodeInvalidPath, Message: fmt.Sprintf("%s must be a clean absolute path", field)}}
+	}
+	resolved, err := resolveHostPath(value)
+	if err != nil {
+		return []Violation{{Field: field, Code: CodeInvalidPath, Message: fmt.Sprintf("%s %s cannot be resolved: %v", field, value, err)}}
+	}
+	for _, root := range roots {
+		if resolvedRoot, err := resolveHostPath(root); err == nil {
+			root = resolvedRoot
+		}
+		if root == "/" || resolved == root || strings.HasPrefix(resolved, root+"/") {
+			return nil
+		}
+	}
+	return []Violation{{
+		Field:   field,
+		Code:    CodePathNotAllowed,
+		Message: fmt.Sprintf("%s %s is outside the allowed directories (%s)", field, value, strings.Join(roots, ", ")),
+	}}
+}
+
+// resolveHostPath 解析路径中的符号链接，路径尚不存在时解析其最近的已存在上级目录
+func resolveHostPath(value string) (string, error) {
+	dir, rest := filepath.Clean(value), ""
+	for {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", err
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
+
+// cleanRoots 规范化根目录，忽略空项和相对路径
+func cleanRoots(roots []string) []string {
+	var cleaned []string
+	for _, root := range roots {
+		if root = strings.TrimSpace(root); path.IsAbs(root) {
+			cleaned = append(cleaned, path.Clean(root))
+		}
+	}
+	return cleaned
+}
+
+// policyForProject 获取项目所属组织的镜像策略
+func (c *controller) policyForProject(ctx context.Context, projectID uuid.UUID) (*ImagePolicy, error) {
+	var project models.Project
+	if err := c.db.WithContext(ctx).Select("id", "org_id").First(&project, "id = ?", projectID).Error; err != nil {
+		// 未登记的项目使用默认策略
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &c.cfg.DefaultPolicy, nil
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if project.OrgID == nil {
+		return &c.cfg.DefaultPolicy, nil
+	}
+	return c.GetPolicy(ctx, *project.OrgID)
+}
+
+// GetPolicy 获取组织的镜像策略
+func (c *controller) GetPolicy(ctx context.Context, orgID uuid.UUID) (*ImagePolicy, error) {
+	var policy ImagePolicy
+	if err := c.db.WithContext(ctx).First(&policy, "org_id = ?", orgID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			defaults := c.cfg.DefaultPolicy
+			defaults.OrgID = orgID
+			return &defaults, nil
+		}
+		return nil, fmt.Errorf("failed to get image policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// PutPolicy 设置组织的镜像策略
+func (c *controller) PutPolicy(ctx context.Context, userID, orgID uuid.UUID, req *PutPolicyRequest) (*ImagePolicy, error) {
+	policy := &ImagePolicy{
+		ID:                uuid.New(),
+		OrgID:             orgID,
+		AllowedRegistries: req.AllowedRegistries,
+		AllowedImages:     req.AllowedImages,
+		RequireDigest:     req.RequireDigest,
+		UpdatedBy:         userID,
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "org_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"allowed_registries", "allowed_images", "require_digest", "updated_by", "updated_at"}),
+	}).Create(policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to save image policy: %w", err)
+	}
+	return c.GetPolicy(ctx, orgID)
+}
+
+// DeletePolicy 删除组织的镜像策略
+func (c *controller) DeletePolicy(ctx context.Context, orgID uuid.UUID) error {
+	result := c.db.WithContext(ctx).Where("org_id = ?", orgID).Delete(&ImagePolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete image policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("image policy not found for organization: %s", orgID))
+	}
+	return nil
+}
+
+// ParseList 解析逗号分隔的配置项
+func ParseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
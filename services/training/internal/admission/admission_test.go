@@ -0,0 +1,140 @@
+package admission
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// violationCodes 返回违规项代码，便于比较
+func violationCodes(violations []Violation) string {
+	codes := make([]string, len(violations))
+	for i, v := range violations {
+		codes[i] = v.Code
+	}
+	return strings.Join(codes, ",")
+}
+
+func TestImagePolicyCheck(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("0a", 32)
+	otherDigest := "sha256:" + strings.Repeat("1b", 32)
+
+	tests := []struct {
+		name   string
+		policy ImagePolicy
+		image  string
+		want   string
+	}{
+		{"unrestricted", ImagePolicy{}, "python:3.11", ""},
+		{"invalid image", ImagePolicy{}, "Python:3.11", CodeInvalidImage},
+		{"digest required", ImagePolicy{RequireDigest: true}, "python:3.11", CodeDigestRequired},
+		{"digest present", ImagePolicy{RequireDigest: true}, "python@" + digest, ""},
+		{"allowed registry", ImagePolicy{AllowedRegistries: []string{"nvcr.io"}}, "nvcr.io/nvidia/pytorch:24.01-py3", ""},
+		{"allowed namespace", ImagePolicy{AllowedRegistries: []string{"docker.io/pytorch"}}, "pytorch/pytorch:2.1.0", ""},
+		{"registry alias", ImagePolicy{AllowedRegistries: []string{"index.docker.io/library"}}, "python:3.11", ""},
+		{"registry prefix is not a namespace", ImagePolicy{AllowedRegistries: []string{"docker.io/py"}}, "pytorch/pytorch:2.1.0", CodeImageNotAllowed},
+		{"registry not allowed", ImagePolicy{AllowedRegistries: []string{"nvcr.io"}}, "evil.example.com/nvcr.io/pytorch", CodeImageNotAllowed},
+		{"image glob", ImagePolicy{AllowedImages: []string{"python:3.11*"}}, "python:3.11-slim", ""},
+		{"image tag mismatch", ImagePolicy{AllowedImages: []string{"python:3.11*"}}, "python:3.12", CodeImageNotAllowed},
+		{"implicit latest", ImagePolicy{AllowedImages: []string{"python:latest"}}, "python", ""},
+		{"tag entry does not allow digests", ImagePolicy{AllowedImages: []string{"python:3.11"}}, "python:3.11@" + digest, CodeImageNotAllowed},
+		{"pinned digest", ImagePolicy{AllowedImages: []string{"myteam/trainer@" + digest}}, "myteam/trainer@" + digest, ""},
+		{"pinned digest mismatch", ImagePolicy{AllowedImages: []string{"myteam/trainer@" + digest}}, "myteam/trainer@" + otherDigest, CodeDigestMismatch},
+		{"pinned digest by tag", ImagePolicy{AllowedImages: []string{"myteam/trainer@" + digest}}, "myteam/trainer:v1", CodeDigestMismatch},
+		{"digest required and not allowed", ImagePolicy{RequireDigest: true, AllowedRegistries: []string{"nvcr.io"}}, "python:3.11", CodeDigestRequired + "," + CodeImageNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violationCodes(tt.policy.check(tt.image)); got != tt.want {
+				t.Errorf("check(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHostPath(t *testing.T) {
+	base := t.TempDir()
+	data := filepath.Join(base, "data")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{data, outside} {
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(data, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(data, filepath.Join(base, "data-link")); err != nil {
+		t.Fatal(err)
+	}
+	roots := []string{data}
+
+	tests := []struct {
+		name  string
+		value string
+		roots []string
+		want  string
+	}{
+		{"empty", "", roots, ""},
+		{"s3 path", "s3://bucket/dataset", roots, ""},
+		{"s3 path without roots", "s3://bucket/dataset", nil, ""},
+		{"host path without roots", data, nil, CodePathNotAllowed},
+		{"root itself", data, roots, ""},
+		{"inside root", filepath.Join(data, "mnist"), roots, ""},
+		{"trailing slash", data + "/mnist/", roots, ""},
+		{"not yet created", filepath.Join(data, "runs", "1", "out"), roots, ""},
+		{"outside root", outside, roots, CodePathNotAllowed},
+		{"sibling with root prefix", data + "2", roots, CodePathNotAllowed},
+		{"symlink out of root", filepath.Join(data, "escape"), roots, CodePathNotAllowed},
+		{"path below symlink out of root", filepath.Join(data, "escape", "new"), roots, CodePathNotAllowed},
+		{"symlink into root", filepath.Join(base, "data-link", "mnist"), roots, ""},
+		{"symlinked root", filepath.Join(data, "mnist"), []string{filepath.Join(base, "data-link")}, ""},
+		{"relative", "data/mnist", roots, CodeInvalidPath},
+		{"dot dot", data + "/../outside", roots, CodeInvalidPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violationCodes(checkHostPath("dataset_path", tt.value, tt.roots)); got != tt.want {
+				t.Errorf("checkHostPath(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeInventory 按型号记录宿主机 GPU 数
+type fakeInventory map[string]int
+
+func (f fakeInventory) CountMatching(gpuType string) int {
+	return f[gpuType]
+}
+
+func TestCheckGPUs(t *testing.T) {
+	c := &controller{cfg: Config{GPUCapacity: 8, GPUs: fakeInventory{"A100": 4, "T4": 4}}}
+
+	tests := []struct {
+		name string
+		job  domain.TrainingJob
+		want string
+	}{
+		{"no gpus", domain.TrainingJob{}, ""},
+		{"within capacity", domain.TrainingJob{GPUCount: 8}, ""},
+		{"over capacity", domain.TrainingJob{GPUCount: 9}, CodeGPUUnavailable},
+		{"distributed over capacity", domain.TrainingJob{GPUCount: 4, Distributed: &domain.DistributedConfig{NumNodes: 3}}, CodeGPUUnavailable},
+		{"matching type", domain.TrainingJob{GPUCount: 4, GPUType: "A100"}, ""},
+		{"not enough of type", domain.TrainingJob{GPUCount: 2, GPUType: "A100", Distributed: &domain.DistributedConfig{NumNodes: 3}}, CodeGPUUnavailable},
+		{"unknown type", domain.TrainingJob{GPUCount: 1, GPUType: "H100"}, CodeGPUUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violationCodes(c.checkGPUs(&tt.job)); got != tt.want {
+				t.Errorf("checkGPUs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package admission
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultRegistry 未指定仓库地址的镜像所在的仓库
+const defaultRegistry = "docker.io"
+
+// digestPattern 镜像摘要
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ImagePolicy 组织的镜像准入策略，AllowedRegistries 和 AllowedImages 都为空时不限制镜像来源
+type ImagePolicy struct {
+	ID                uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrgID             uuid.UUID `json:"org_id" gorm:"type:uuid;not null;uniqueIndex"`
+	AllowedRegistries []string  `json:"allowed_registries" gorm:"serializer:json"` // 仓库或仓库下的命名空间，例如 nvcr.io、docker.io/pytorch
+	AllowedImages     []string  `json:"allowed_images" gorm:"serializer:json"`     // 镜像，可带标签（支持通配符）或 @sha256 摘要
+	RequireDigest     bool      `json:"require_digest"`                            // 镜像必须以 @sha256 摘要引用
+	UpdatedBy         uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (ImagePolicy) TableName() string {
+	return "image_policies"
+}
+
+// restricted 是否限制镜像来源
+func (p *ImagePolicy) restricted() bool {
+	return len(p.AllowedRegistries) > 0 || len(p.AllowedImages) > 0
+}
+
+// Validate 校验策略中的仓库和镜像格式
+func (p *ImagePolicy) Validate() error {
+	for _, registry := range p.AllowedRegistries {
+		if normalizeRegistry(registry) == "" {
+			return fmt.Errorf("invalid registry %q", registry)
+		}
+	}
+	for _, image := range p.AllowedImages {
+		if _, err := parseImage(image); err != nil {
+			return fmt.Errorf("invalid image %q: %w", image, err)
+		}
+	}
+	return nil
+}
+
+// check 按策略校验镜像，返回违规项
+func (p *ImagePolicy) check(image string) []Violation {
+	ref, err := parseImage(image)
+	if err != nil {
+		return []Violation{{Field: "image", Code: CodeInvalidImage, Message: fmt.Sprintf("invalid image %q: %v", image, err)}}
+	}
+
+	var violations []Violation
+	if p.RequireDigest && ref.digest == "" {
+		violations = append(violations, Violation{
+			Field:   "image",
+			Code:    CodeDigestRequired,
+			Message: fmt.Sprintf("image %q must be pinned by digest (name@sha256:...)", image),
+		})
+	}
+	if !p.restricted() {
+		return violations
+	}
+
+	for _, registry := range p.AllowedRegistries {
+		if prefix := normalizeRegistry(registry); ref.name == prefix || strings.HasPrefix(ref.name, prefix+"/") {
+			return violations
+		}
+	}
+
+	var pinned []string
+	for _, entry := range p.AllowedImages {
+		allowed, err := parseImage(entry)
+		if err != nil {
+			continue
+		}
+		if ok, _ := path.Match(allowed.name, ref.name); !ok {
+			continue
+		}
+		switch {
+		case allowed.digest != "":
+			if ref.digest == allowed.digest {
+				return violations
+			}
+			pinned = append(pinned, allowed.digest)
+		case allowed.tag != "":
+			if ok, _ := path.Match(allowed.tag, ref.tagOrLatest()); ok && ref.digest == "" {
+				return violations
+			}
+		default:
+			return violations
+		}
+	}
+
+	if len(pinned) > 0 {
+		return append(violations, Violation{
+			Field:   "image",
+			Code:    CodeDigestMismatch,
+			Message: fmt.Sprintf("image %s is pinned to %s", ref.name, strings.Join(pinned, ", ")),
+		})
+	}
+	return append(violations, Violation{
+		Field:   "image",
+		Code:    CodeImageNotAllowed,
+		Message: fmt.Sprintf("image %q is not in the allowed registries or images", image),
+	})
+}
+
+// imageRef 解析后的镜像引用
+type imageRef struct {
+	name   string // 带仓库地址的完整名称，例如 docker.io/library/python
+	tag    string // 未指定时为空
+	digest string // 未指定时为空
+}
+
+// tagOrLatest 镜像标签，未指定时为 latest
+func (r imageRef) tagOrLatest() string {
+	if r.tag == "" {
+		return "latest"
+	}
+	return r.tag
+}
+
+// parseImage 解析镜像引用，按 Docker 的规则补全默认仓库和 library 命名空间
+func parseImage(image string) (imageRef, error) {
+	image = strings.TrimSpace(image)
+	if image == "" || strings.ContainsAny(image, " \t\n") {
+		return imageRef{}, fmt.Errorf("empty or malformed reference")
+	}
+
+	var ref imageRef
+	if name, digest, ok := strings.Cut(image, "@"); ok {
+		if !digestPattern.MatchString(digest) {
+			return imageRef{}, fmt.Errorf("digest must be sha256:<64 hex characters>")
+		}
+		image, ref.digest = name, digest
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, ref.tag = image[:i], image[i+1:]
+		if ref.tag == "" {
+			return imageRef{}, fmt.Errorf("empty tag")
+		}
+	}
+	if image == "" || strings.HasPrefix(image, "/") || strings.HasSuffix(image, "/") || strings.Contains(image, "//") {
+		return imageRef{}, fmt.Errorf("invalid repository name")
+	}
+	if image != strings.ToLower(image) {
+		return imageRef{}, fmt.Errorf("repository name must be lowercase")
+	}
+
+	first, rest, hasPath := strings.Cut(image, "/")
+	switch {
+	case hasPath && (strings.ContainsAny(first, ".:") || first == "localhost"):
+		ref.name = normalizeRegistry(first) + "/" + rest
+	case hasPath:
+		ref.name = defaultRegistry + "/" + image
+	default:
+		ref.name = defaultRegistry + "/library/" + image
+	}
+	return ref, nil
+}
+
+// normalizeRegistry 统一仓库地址的写法
+func normalizeRegistry(registry string) string {
+	registry = strings.Trim(strings.ToLower(strings.TrimSpace(registry)), "/")
+	for _, alias := range []string{"index.docker.io", "registry-1.docker.io"} {
+		if registry == alias || strings.HasPrefix(registry, alias+"/") {
+			return defaultRegistry + strings.TrimPrefix(registry, alias)
+		}
+	}
+	return registry
+}
@@ -0,0 +1,184 @@
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	pkgminio "github.com/plucky-groove3/ai-train-infer-platform/pkg/minio"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// presignedExpiry 下载链接有效期
+const presignedExpiry = 15 * time.Minute
+
+// Config 产物上传配置
+type Config struct {
+	Bucket string // 产物上传的 bucket，对象位于 artifacts/<job_id>/ 下
+}
+
+// Uploader 任务结束后将输出目录上传到对象存储
+//
+// 检查点由检查点注册表单独上传，这里跳过。重复上传时覆盖同名对象，
+// 因此重试和继续运行的任务最终保存的是最后一次尝试的输出。
+type Uploader struct {
+	jobRepo repository.JobRepository
+	logRepo repository.LogRepository
+	storage *pkgminio.Client
+	cfg     Config
+}
+
+// NewUploader 创建产物上传器
+func NewUploader(jobRepo repository.JobRepository, logRepo repository.LogRepository, storage *pkgminio.Client, cfg Config) *Uploader {
+	return &Uploader{
+		jobRepo: jobRepo,
+		logRepo: logRepo,
+		storage: storage,
+		cfg:     cfg,
+	}
+}
+
+// Start 确保 bucket 存在
+func (u *Uploader) Start(ctx context.Context) error {
+	if err := u.storage.MakeBucket(ctx, u.cfg.Bucket); err != nil {
+		return fmt.Errorf("failed to ensure artifact bucket: %w", err)
+	}
+	return nil
+}
+
+// prefix 任务产物的对象前缀
+func prefix(jobID uuid.UUID) string {
+	return path.Join("artifacts", jobID.String()) + "/"
+}
+
+// Upload 上传任务输出目录中检查点以外的文件，并记录产物位置
+func (u *Uploader) Upload(ctx context.Context, jobID uuid.UUID) {
+	job, err := u.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		logger.Warn("Failed to load job for artifact upload", zap.String("job_id", jobID.String()), zap.Error(err))
+		return
+	}
+	if job.OutputPath == "" {
+		return
+	}
+	if _, err := os.Stat(job.OutputPath); err != nil {
+		// Kubernetes 执行器的输出位于 PVC 中，训练服务未挂载时无法上传
+		logger.Warn("Output directory not accessible, skipping artifact upload",
+			zap.String("job_id", jobID.String()),
+			zap.String("output_path", job.OutputPath),
+			zap.Error(err),
+		)
+		return
+	}
+
+	skip := make(map[string]bool)
+	for _, ckpt := range executor.ListCheckpoints(job.OutputPath) {
+		skip[ckpt.Path] = true
+	}
+
+	var files int
+	var size int64
+	err = filepath.WalkDir(job.OutputPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if skip[p] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(job.OutputPath, p)
+		if err != nil {
+			return err
+		}
+		n, err := u.uploadFile(ctx, p, prefix(job.ID)+filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		files++
+		size += n
+		return nil
+	})
+	if err != nil {
+		logger.Warn("Failed to upload job artifacts", zap.String("job_id", jobID.String()), zap.Error(err))
+		u.appendLog(ctx, jobID, "WARN", fmt.Sprintf("Failed to upload output artifacts: %v", err))
+		return
+	}
+	if files == 0 {
+		return
+	}
+
+	location := path.Join(u.cfg.Bucket, prefix(job.ID)) + "/"
+	if err := u.jobRepo.UpdateArtifactPath(ctx, jobID, location); err != nil {
+		logger.Warn("Failed to record job artifacts", zap.String("job_id", jobID.String()), zap.Error(err))
+	}
+	u.appendLog(ctx, jobID, "INFO", fmt.Sprintf("Uploaded %d output files (%d bytes) to %s", files, size, location))
+}
+
+// uploadFile 上传单个文件，返回文件大小
+func (u *Uploader) uploadFile(ctx context.Context, filePath, objectName string) (int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat output file: %w", err)
+	}
+
+	if _, err := u.storage.UploadMultipart(ctx, u.cfg.Bucket, objectName, file, info.Size(), pkgminio.DefaultUploadOptions()); err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// List 列出任务已上传的产物及其预签名下载 URL
+func (u *Uploader) List(ctx context.Context, jobID uuid.UUID) ([]*domain.Artifact, error) {
+	objectPrefix := prefix(jobID)
+	artifacts := make([]*domain.Artifact, 0)
+	for object := range u.storage.ListObjects(ctx, u.cfg.Bucket, objectPrefix, true) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", object.Err)
+		}
+
+		url, err := u.storage.PresignedGetURL(ctx, u.cfg.Bucket, object.Key, presignedExpiry)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, &domain.Artifact{
+			Path:         strings.TrimPrefix(object.Key, objectPrefix),
+			Size:         object.Size,
+			LastModified: object.LastModified,
+			URL:          url.String(),
+			ExpiresAt:    time.Now().Add(presignedExpiry),
+		})
+	}
+	return artifacts, nil
+}
+
+// appendLog 记录系统日志
+func (u *Uploader) appendLog(ctx context.Context, jobID uuid.UUID, level, message string) {
+	u.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
+		Level:     level,
+		Source:    "system",
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
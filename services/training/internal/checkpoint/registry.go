@@ -0,0 +1,534 @@
+package checkpoint
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	pkgminio "github.com/plucky-groove3/ai-train-infer-platform/pkg/minio"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+const (
+	// stableAge 扫描发现的检查点需在该时间内未被修改才注册，避免上传写入中的文件
+	stableAge = 10 * time.Second
+	// presignedExpiry 下载链接有效期
+	presignedExpiry = 15 * time.Minute
+)
+
+var (
+	stepPattern  = regexp.MustCompile(`(?i)(?:step|checkpoint|ckpt|iter)[-_=]?(\d+)`)
+	epochPattern = regexp.MustCompile(`(?i)epoch[-_=]?(\d+)`)
+)
+
+// Config 检查点注册配置
+type Config struct {
+	Bucket       string        // 检查点上传的 bucket
+	BestMetric   string        // 用于选择最佳检查点的指标
+	BestMode     string        // min 或 max
+	ScanInterval time.Duration // 运行中任务输出目录的扫描间隔
+	CacheDir     string        // 从对象存储恢复检查点时的本地缓存目录
+}
+
+// Registry 检查点注册表
+//
+// 检查点来源有两种：定期扫描运行中任务的输出目录，以及训练脚本输出的
+// AITIP_CHECKPOINT 日志行。发现的检查点上传到对象存储并写入 checkpoints 表，
+// 同一宿主机路径只对应一条记录，内容变化时重新上传。
+type Registry struct {
+	repo    repository.CheckpointRepository
+	jobRepo repository.JobRepository
+	logRepo repository.LogRepository
+	storage *pkgminio.Client
+	cfg     Config
+
+	// 串行化注册过程，保证同一路径不会被扫描和日志声明同时上传
+	mu   sync.Mutex
+	seen map[string]time.Time // 已注册检查点的宿主机路径 -> 注册时的修改时间
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewRegistry 创建检查点注册表
+func NewRegistry(repo repository.CheckpointRepository, jobRepo repository.JobRepository, logRepo repository.LogRepository, storage *pkgminio.Client, cfg Config) *Registry {
+	if cfg.ScanInterval <= 0 {
+		cfg.ScanInterval = 30 * time.Second
+	}
+	if cfg.BestMetric == "" {
+		cfg.BestMetric = "val_loss"
+	}
+
+	return &Registry{
+		repo:    repo,
+		jobRepo: jobRepo,
+		logRepo: logRepo,
+		storage: storage,
+		cfg:     cfg,
+		seen:    make(map[string]time.Time),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start 确保 bucket 存在并启动扫描循环
+func (r *Registry) Start(ctx context.Context) error {
+	if err := r.storage.MakeBucket(ctx, r.cfg.Bucket); err != nil {
+		return fmt.Errorf("failed to ensure checkpoint bucket: %w", err)
+	}
+
+	r.wg.Add(1)
+	go r.loop()
+
+	logger.Info("Checkpoint registry started",
+		zap.String("bucket", r.cfg.Bucket),
+		zap.String("best_metric", r.cfg.BestMetric),
+		zap.String("best_mode", r.cfg.BestMode),
+		zap.Duration("scan_interval", r.cfg.ScanInterval),
+	)
+	return nil
+}
+
+// Stop 停止扫描循环
+func (r *Registry) Stop() {
+	close(r.stopCh)
+	r.wg.Wait()
+}
+
+// loop 扫描循环
+func (r *Registry) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.cfg.ScanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.cfg.ScanInterval*10)
+		r.scanRunning(ctx)
+		cancel()
+	}
+}
+
+// scanRunning 扫描所有运行中任务的输出目录
+func (r *Registry) scanRunning(ctx context.Context) {
+	jobs, err := r.jobRepo.ListByStatus(ctx, domain.JobStatusRunning)
+	if err != nil {
+		logger.Error("Failed to list running jobs for checkpoint scan", zap.Error(err))
+		return
+	}
+
+	for _, job := range jobs {
+		r.scanJob(ctx, job, stableAge)
+	}
+}
+
+// scanJob 扫描任务输出目录，只注册至少 minAge 内未被修改的检查点
+func (r *Registry) scanJob(ctx context.Context, job *domain.TrainingJob, minAge time.Duration) {
+	for _, local := range executor.ListCheckpoints(job.OutputPath) {
+		if minAge > 0 && time.Since(local.ModTime) < minAge {
+			continue
+		}
+		if err := r.register(ctx, job, local, nil); err != nil {
+			logger.Warn("Failed to register checkpoint",
+				zap.String("job_id", job.ID.String()),
+				zap.String("path", local.Path),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// OnAnnouncement 处理训练脚本声明的检查点
+func (r *Registry) OnAnnouncement(jobID uuid.UUID, announcement *domain.CheckpointAnnouncement) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	job, err := r.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		logger.Warn("Failed to load job for checkpoint announcement", zap.String("job_id", jobID.String()), zap.Error(err))
+		return
+	}
+
+	hostPath := executor.HostOutputPath(job.OutputPath, announcement.Path)
+	if hostPath == "" {
+		r.appendLog(ctx, jobID, "WARN", fmt.Sprintf("Ignoring checkpoint outside /output: %s", announcement.Path))
+		return
+	}
+
+	local := executor.StatCheckpoint(hostPath)
+	if local == nil {
+		r.appendLog(ctx, jobID, "WARN", fmt.Sprintf("Announced checkpoint not found: %s", announcement.Path))
+		return
+	}
+
+	if err := r.register(ctx, job, local, announcement); err != nil {
+		logger.Warn("Failed to register announced checkpoint",
+			zap.String("job_id", jobID.String()),
+			zap.String("path", hostPath),
+			zap.Error(err),
+		)
+	}
+}
+
+// Collect 任务结束后对输出目录做最后一次扫描
+func (r *Registry) Collect(ctx context.Context, jobID uuid.UUID) {
+	job, err := r.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		logger.Warn("Failed to load job for checkpoint collection", zap.String("job_id", jobID.String()), zap.Error(err))
+		return
+	}
+	r.scanJob(ctx, job, 0)
+}
+
+// register 上传检查点并创建或更新记录，announcement 为 nil 表示由扫描发现
+func (r *Registry) register(ctx context.Context, job *domain.TrainingJob, local *executor.LocalCheckpoint, announcement *domain.CheckpointAnnouncement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.repo.GetBySourcePath(ctx, job.ID, local.Path)
+	if err != nil && !errors.Is(err, repository.ErrCheckpointNotFound) {
+		return fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+
+	registeredAt, seen := r.seen[local.Path]
+	if existing == nil && seen && announcement == nil {
+		// 已注册过但记录不存在，说明被用户删除，扫描时不再重新注册
+		return nil
+	}
+
+	// 服务重启后没有修改时间记录，仅按大小判断是否变化
+	unchanged := existing != nil && existing.Size == local.Size && (!seen || !local.ModTime.After(registeredAt))
+	if unchanged && announcement == nil {
+		return nil
+	}
+
+	rel, err := filepath.Rel(job.OutputPath, local.Path)
+	if err != nil {
+		rel = local.Name
+	}
+	objectName := path.Join("checkpoints", job.ID.String(), filepath.ToSlash(rel))
+	if local.IsDir {
+		objectName += "/"
+	}
+
+	if !unchanged {
+		if err := r.upload(ctx, local, objectName); err != nil {
+			return err
+		}
+	}
+
+	ckpt := existing
+	if ckpt == nil {
+		ckpt = &models.Checkpoint{
+			TrainingJobID: job.ID,
+			SourcePath:    local.Path,
+			Metrics:       models.JSON{},
+		}
+	}
+	if ckpt.Metrics == nil {
+		ckpt.Metrics = models.JSON{}
+	}
+	ckpt.Name = local.Name
+	ckpt.StoragePath = objectName
+	ckpt.Size = local.Size
+
+	if announcement != nil {
+		ckpt.Step = announcement.Step
+		ckpt.Epoch = announcement.Epoch
+		for name, value := range announcement.Metrics {
+			ckpt.Metrics[name] = value
+		}
+	} else if existing == nil {
+		ckpt.Step, ckpt.Epoch = parseStepEpoch(local.Name)
+	}
+
+	if existing == nil {
+		err = r.repo.Create(ctx, ckpt)
+	} else {
+		err = r.repo.Update(ctx, ckpt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	r.seen[local.Path] = local.ModTime
+
+	if err := r.markBest(ctx, job.ID); err != nil {
+		logger.Warn("Failed to mark best checkpoint", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	if existing == nil {
+		r.appendLog(ctx, job.ID, "INFO", fmt.Sprintf("Registered checkpoint %s (step %d)", rel, ckpt.Step))
+	}
+	return nil
+}
+
+// upload 上传检查点文件，目录按相对路径逐个上传到 objectName 前缀下
+func (r *Registry) upload(ctx context.Context, local *executor.LocalCheckpoint, objectName string) error {
+	if !local.IsDir {
+		return r.uploadFile(ctx, local.Path, objectName)
+	}
+
+	return filepath.WalkDir(local.Path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(local.Path, p)
+		if err != nil {
+			return err
+		}
+		return r.uploadFile(ctx, p, objectName+filepath.ToSlash(rel))
+	})
+}
+
+// uploadFile 上传单个文件
+func (r *Registry) uploadFile(ctx context.Context, filePath, objectName string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat checkpoint file: %w", err)
+	}
+
+	if _, err := r.storage.UploadMultipart(ctx, r.cfg.Bucket, objectName, file, info.Size(), pkgminio.DefaultUploadOptions()); err != nil {
+		return fmt.Errorf("failed to upload checkpoint %s: %w", objectName, err)
+	}
+	return nil
+}
+
+// markBest 按配置的指标重新标记最佳检查点
+func (r *Registry) markBest(ctx context.Context, jobID uuid.UUID) error {
+	return r.repo.MarkBest(ctx, jobID, r.cfg.BestMetric, r.cfg.BestMode == "max")
+}
+
+// IsDir 检查点是否为目录
+func IsDir(ckpt *models.Checkpoint) bool {
+	return strings.HasSuffix(ckpt.StoragePath, "/")
+}
+
+// Materialize 返回检查点在本机的路径：原文件仍存在时直接使用，否则从对象存储下载到缓存目录
+func (r *Registry) Materialize(ctx context.Context, checkpointID uuid.UUID) (string, error) {
+	ckpt, err := r.repo.GetByID(ctx, checkpointID)
+	if err != nil {
+		return "", err
+	}
+
+	if ckpt.SourcePath != "" {
+		if _, err := os.Stat(ckpt.SourcePath); err == nil {
+			return ckpt.SourcePath, nil
+		}
+	}
+
+	dest := filepath.Join(r.cfg.CacheDir, ckpt.ID.String(), ckpt.Name)
+	if _, err := os.Stat(dest); err == nil {
+		return dest, nil
+	}
+
+	// 先下载到临时位置，完成后再重命名，避免中断留下不完整的缓存
+	tmp := dest + ".partial"
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create checkpoint cache dir: %w", err)
+	}
+
+	if IsDir(ckpt) {
+		for object := range r.storage.ListObjects(ctx, r.cfg.Bucket, ckpt.StoragePath, true) {
+			if object.Err != nil {
+				os.RemoveAll(tmp)
+				return "", fmt.Errorf("failed to list checkpoint objects: %w", object.Err)
+			}
+			target := filepath.Join(tmp, filepath.FromSlash(strings.TrimPrefix(object.Key, ckpt.StoragePath)))
+			if err := r.downloadFile(ctx, object.Key, target); err != nil {
+				os.RemoveAll(tmp)
+				return "", err
+			}
+		}
+	} else if err := r.downloadFile(ctx, ckpt.StoragePath, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("failed to finalize checkpoint cache: %w", err)
+	}
+	return dest, nil
+}
+
+// downloadFile 下载单个对象到本地文件
+func (r *Registry) downloadFile(ctx context.Context, objectName, target string) error {
+	reader, _, err := r.storage.Download(ctx, r.cfg.Bucket, objectName)
+	if err != nil {
+		return fmt.Errorf("failed to download checkpoint %s: %w", objectName, err)
+	}
+	defer reader.Close()
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint dir: %w", err)
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	return nil
+}
+
+// DownloadInfo 获取检查点的下载信息，单文件检查点返回预签名 URL
+func (r *Registry) DownloadInfo(ctx context.Context, ckpt *models.Checkpoint) (*domain.CheckpointDownload, error) {
+	if IsDir(ckpt) {
+		return &domain.CheckpointDownload{
+			Filename:    ckpt.Name + ".tar.gz",
+			ContentType: "application/gzip",
+			Archive:     true,
+		}, nil
+	}
+
+	url, err := r.storage.PresignedGetURL(ctx, r.cfg.Bucket, ckpt.StoragePath, presignedExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+	expiresAt := time.Now().Add(presignedExpiry)
+
+	return &domain.CheckpointDownload{
+		URL:         url.String(),
+		ExpiresAt:   &expiresAt,
+		Filename:    ckpt.Name,
+		ContentType: "application/octet-stream",
+	}, nil
+}
+
+// WriteArchive 将目录检查点打包为 tar.gz 写入 w
+func (r *Registry) WriteArchive(ctx context.Context, ckpt *models.Checkpoint, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for object := range r.storage.ListObjects(ctx, r.cfg.Bucket, ckpt.StoragePath, true) {
+		if object.Err != nil {
+			return fmt.Errorf("failed to list checkpoint objects: %w", object.Err)
+		}
+
+		reader, info, err := r.storage.Download(ctx, r.cfg.Bucket, object.Key)
+		if err != nil {
+			return fmt.Errorf("failed to download checkpoint %s: %w", object.Key, err)
+		}
+
+		header := &tar.Header{
+			Name:    path.Join(ckpt.Name, strings.TrimPrefix(object.Key, ckpt.StoragePath)),
+			Mode:    0644,
+			Size:    info.Size,
+			ModTime: info.LastModified,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			reader.Close()
+			return fmt.Errorf("failed to write archive header: %w", err)
+		}
+		_, err = io.Copy(tw, reader)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("failed to write archive entry: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Delete 删除检查点的对象和记录，删除的是最佳检查点时重新选择
+func (r *Registry) Delete(ctx context.Context, ckpt *models.Checkpoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if IsDir(ckpt) {
+		for object := range r.storage.ListObjects(ctx, r.cfg.Bucket, ckpt.StoragePath, true) {
+			if object.Err != nil {
+				return fmt.Errorf("failed to list checkpoint objects: %w", object.Err)
+			}
+			if err := r.storage.RemoveObject(ctx, r.cfg.Bucket, object.Key); err != nil {
+				return fmt.Errorf("failed to remove checkpoint object: %w", err)
+			}
+		}
+	} else if err := r.storage.RemoveObject(ctx, r.cfg.Bucket, ckpt.StoragePath); err != nil {
+		return fmt.Errorf("failed to remove checkpoint object: %w", err)
+	}
+
+	if err := r.repo.Delete(ctx, ckpt.ID); err != nil {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+
+	// 保留 seen 记录，避免任务仍在运行时被扫描重新注册
+	if ckpt.SourcePath != "" {
+		if _, ok := r.seen[ckpt.SourcePath]; !ok {
+			r.seen[ckpt.SourcePath] = time.Now()
+		}
+	}
+	os.RemoveAll(filepath.Join(r.cfg.CacheDir, ckpt.ID.String()))
+
+	if ckpt.IsBest {
+		return r.markBest(ctx, ckpt.TrainingJobID)
+	}
+	return nil
+}
+
+// appendLog 记录系统日志
+func (r *Registry) appendLog(ctx context.Context, jobID uuid.UUID, level, message string) {
+	r.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
+		Level:     level,
+		Source:    "system",
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
+
+// parseStepEpoch 从检查点名称中解析步数和轮数，例如 checkpoint-500、epoch_3.pt
+func parseStepEpoch(name string) (int64, *int64) {
+	var step int64
+	var epoch *int64
+
+	if m := stepPattern.FindStringSubmatch(name); m != nil {
+		step, _ = strconv.ParseInt(m[1], 10, 64)
+	}
+	if m := epochPattern.FindStringSubmatch(name); m != nil {
+		if v, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			epoch = &v
+		}
+	}
+	return step, epoch
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -22,11 +23,27 @@ type Config struct {
 	LogOutput string // stdout, file, or both
 	LogPath   string
 
+	// 执行器：docker 或 kubernetes
+	Executor string
+
 	// Docker
 	DockerHost        string
 	DockerNetwork     string
 	DockerVolumeBase  string
 
+	// Kubernetes
+	KubeConfig                 string // kubeconfig 路径，为空时使用集群内配置
+	KubernetesNamespace        string
+	KubernetesServiceAccount   string
+	KubernetesNodeSelector     string // key=value,key2=value2
+	KubernetesTolerations      string // key=value:Effect,key:Effect
+	KubernetesImagePullSecrets string // 逗号分隔
+	KubernetesGPUResource      string // GPU 扩展资源名
+	KubernetesGPUTypeLabel     string // 按 gpu_type 选择节点的节点标签
+	KubernetesDataPVC          string // 数据集 PVC，为空时从 MinIO 下载
+	KubernetesOutputPVC        string // 输出目录 PVC
+	KubernetesMinIOImage       string // 下载数据集的 init 容器镜像，解压数据集归档需要 tar、unzip 和 gunzip
+
 	// MinIO (模型存储)
 	MinIOEndpoint     string
 	MinIOAccessKey    string
@@ -38,6 +55,69 @@ type Config struct {
 	DefaultTimeout    time.Duration
 	MaxConcurrentJobs int
 	LogStreamMaxLen   int64  // Redis Stream 最大长度
+
+	// 调度配置
+	SchedulerInterval    time.Duration // 调度轮询间隔
+	SchedulerCPUCapacity int           // 可调度的 CPU 核数
+	SchedulerMemoryGB    int           // 可调度的内存（GB）
+	SchedulerGPUCapacity int           // 可调度的 GPU 数量，-1 表示自动检测
+	ReconcileInterval    time.Duration // 任务状态与容器状态的协调间隔
+
+	// 检查点配置
+	CheckpointBestMetric   string        // 选择最佳检查点的指标
+	CheckpointBestMode     string        // min 或 max
+	CheckpointScanInterval time.Duration // 输出目录扫描间隔
+	CheckpointCacheDir     string        // 从对象存储恢复检查点时的本地缓存目录
+
+	// 数据集和产物配置
+	DatasetMode      string        // 对象存储数据集的提供方式：cache、init 或 url，为空时 Docker 执行器为 cache，Kubernetes 执行器为 init
+	DatasetCacheDir  string        // cache 模式下载和解压数据集的本地缓存目录
+	DatasetURLExpiry time.Duration // url 模式预签名下载地址的有效期
+	ArtifactBucket   string        // 任务结束后上传输出目录的 bucket
+
+	// 事件配置
+	EventStream        string        // 各服务共用的生命周期事件流
+	EventStreamMaxLen  int64         // 事件流最大长度
+	WebhookAllowHTTP   bool          // 是否允许非 HTTPS 的回调地址（仅用于开发）
+	WebhookMaxAttempts int           // 单个事件最多投递次数，用尽后进入死信
+	WebhookTimeout     time.Duration // 单次投递请求超时
+
+	// 超时配置
+	JobStartupTimeout time.Duration // 拉取镜像、创建并启动容器的最长时间
+	JobStallTimeout   time.Duration // 运行中任务没有日志输出和指标上报的最长时间，0 表示不检测
+	WatchdogInterval  time.Duration // 检查任务超时和停滞的间隔
+
+	// 暂停配置
+	PauseSignal      string        // 暂停时发送给训练容器的信号，训练脚本收到后应保存检查点并退出
+	PauseGracePeriod time.Duration // 等待容器自行退出的时间，超时后强制停止
+
+	// 日志归档配置
+	LogArchiveBucket       string        // 归档日志段的 bucket
+	LogArchiveInterval     time.Duration // 检查日志流的间隔
+	LogArchiveSegmentLines int           // 单个归档段的最大行数
+	LogArchiveMaxAge       time.Duration // 运行中任务的日志在未归档状态停留的最长时间
+
+	// 指标上报配置
+	MetricsReportURL     string // 训练容器访问训练服务的地址
+	MetricsTokenSecret   string // 指标上报令牌签名密钥，为空时启动时随机生成
+	ExperimentServiceURL string // 指标转发到的实验服务地址，为空时不转发
+
+	// 流水线配置
+	PipelineOutputBase  string        // 未指定输出目录的训练步骤的输出根目录
+	PipelineInterval    time.Duration // 推进流水线运行的间隔
+	InferenceServiceURL string        // 部署步骤调用的推理服务地址，为空时部署步骤失败
+	DataServiceURL      string        // 解析 dataset_id 的数据服务地址，为空时不能通过 dataset_id 引用数据集
+
+	// 密钥配置
+	SecretsMasterKey   string // 加密项目密钥的 32 字节主密钥（base64 或 hex），为空时不启用密钥存储
+	SecretsMasterKeyID string // 主密钥 ID，随密文保存，轮换主密钥时使用新 ID
+
+	// 准入配置，组织未设置镜像策略时使用默认策略
+	AdmissionAllowedRegistries string // 默认允许的镜像仓库，逗号分隔，与 AdmissionAllowedImages 都为空时不限制
+	AdmissionAllowedImages     string // 默认允许的镜像，逗号分隔，可带标签或 @sha256 摘要
+	AdmissionRequireDigest     bool   // 默认要求镜像以摘要引用
+	AdmissionDatasetRoots      string // Docker 执行器允许挂载的数据集宿主机目录，逗号分隔，为空时只允许 s3:// 路径
+	AdmissionOutputRoots       string // Docker 执行器允许挂载的输出宿主机目录，逗号分隔，为空时只允许 s3:// 路径
 }
 
 // Load 加载配置
@@ -54,10 +134,24 @@ func Load() *Config {
 		LogOutput: getEnv("LOG_OUTPUT", "stdout"),
 		LogPath:   getEnv("LOG_PATH", "logs/training.log"),
 
+		Executor: getEnv("TRAINING_EXECUTOR", "docker"),
+
 		DockerHost:       getEnv("DOCKER_HOST", ""),
 		DockerNetwork:    getEnv("DOCKER_NETWORK", "aitip-network"),
 		DockerVolumeBase: getEnv("DOCKER_VOLUME_BASE", "/var/aitip/training"),
 
+		KubeConfig:                 getEnv("KUBECONFIG", ""),
+		KubernetesNamespace:        getEnv("KUBERNETES_NAMESPACE", "aitip-training"),
+		KubernetesServiceAccount:   getEnv("KUBERNETES_SERVICE_ACCOUNT", ""),
+		KubernetesNodeSelector:     getEnv("KUBERNETES_NODE_SELECTOR", ""),
+		KubernetesTolerations:      getEnv("KUBERNETES_TOLERATIONS", ""),
+		KubernetesImagePullSecrets: getEnv("KUBERNETES_IMAGE_PULL_SECRETS", ""),
+		KubernetesGPUResource:      getEnv("KUBERNETES_GPU_RESOURCE", "nvidia.com/gpu"),
+		KubernetesGPUTypeLabel:     getEnv("KUBERNETES_GPU_TYPE_LABEL", "nvidia.com/gpu.product"),
+		KubernetesDataPVC:          getEnv("KUBERNETES_DATA_PVC", ""),
+		KubernetesOutputPVC:        getEnv("KUBERNETES_OUTPUT_PVC", ""),
+		KubernetesMinIOImage:       getEnv("KUBERNETES_MINIO_IMAGE", "minio/mc:latest"),
+
 		MinIOEndpoint:  getEnv("MINIO_ENDPOINT", "localhost:9000"),
 		MinIOAccessKey: getEnv("MINIO_ACCESS_KEY", "minioadmin"),
 		MinIOSecretKey: getEnv("MINIO_SECRET_KEY", "minioadmin"),
@@ -67,6 +161,58 @@ func Load() *Config {
 		DefaultTimeout:    parseDuration(getEnv("DEFAULT_TIMEOUT", "24h")),
 		MaxConcurrentJobs: parseInt(getEnv("MAX_CONCURRENT_JOBS", "5")),
 		LogStreamMaxLen:   parseInt64(getEnv("LOG_STREAM_MAX_LEN", "10000")),
+
+		SchedulerInterval:    parseDuration(getEnv("SCHEDULER_INTERVAL", "10s")),
+		SchedulerCPUCapacity: getEnvInt("SCHEDULER_CPU_CAPACITY", 32),
+		SchedulerMemoryGB:    getEnvInt("SCHEDULER_MEMORY_GB", 128),
+		SchedulerGPUCapacity: getEnvInt("SCHEDULER_GPU_CAPACITY", -1),
+		ReconcileInterval:    parseDuration(getEnv("RECONCILE_INTERVAL", "1m")),
+
+		CheckpointBestMetric:   getEnv("CHECKPOINT_BEST_METRIC", "val_loss"),
+		CheckpointBestMode:     getEnv("CHECKPOINT_BEST_MODE", "min"),
+		CheckpointScanInterval: parseDuration(getEnv("CHECKPOINT_SCAN_INTERVAL", "30s")),
+		CheckpointCacheDir:     getEnv("CHECKPOINT_CACHE_DIR", "/var/aitip/training/checkpoints"),
+
+		DatasetMode:      getEnv("DATASET_MODE", ""),
+		DatasetCacheDir:  getEnv("DATASET_CACHE_DIR", "/var/aitip/training/datasets"),
+		DatasetURLExpiry: parseDuration(getEnv("DATASET_URL_EXPIRY", "24h")),
+		ArtifactBucket:   getEnv("ARTIFACT_BUCKET", "training-artifacts"),
+
+		EventStream:        getEnv("EVENT_STREAM", "aitip:events"),
+		EventStreamMaxLen:  parseInt64(getEnv("EVENT_STREAM_MAX_LEN", "100000")),
+		WebhookAllowHTTP:   getEnvBool("WEBHOOK_ALLOW_HTTP", false),
+		WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeout:     parseDuration(getEnv("WEBHOOK_TIMEOUT", "10s")),
+
+		JobStartupTimeout: parseDuration(getEnv("JOB_STARTUP_TIMEOUT", "15m")),
+		JobStallTimeout:   parseDuration(getEnv("JOB_STALL_TIMEOUT", "1h")),
+		WatchdogInterval:  parseDuration(getEnv("WATCHDOG_INTERVAL", "30s")),
+
+		PauseSignal:      getEnv("PAUSE_SIGNAL", "SIGUSR1"),
+		PauseGracePeriod: parseDuration(getEnv("PAUSE_GRACE_PERIOD", "2m")),
+
+		LogArchiveBucket:       getEnv("LOG_ARCHIVE_BUCKET", "training-logs"),
+		LogArchiveInterval:     parseDuration(getEnv("LOG_ARCHIVE_INTERVAL", "30s")),
+		LogArchiveSegmentLines: getEnvInt("LOG_ARCHIVE_SEGMENT_LINES", 5000),
+		LogArchiveMaxAge:       parseDuration(getEnv("LOG_ARCHIVE_MAX_AGE", "10m")),
+
+		MetricsReportURL:     getEnv("METRICS_REPORT_URL", "http://training:"+getEnv("PORT", "8081")),
+		MetricsTokenSecret:   getEnv("METRICS_TOKEN_SECRET", ""),
+		ExperimentServiceURL: getEnv("EXPERIMENT_SERVICE_URL", "http://experiment:8085"),
+
+		PipelineOutputBase:  getEnv("PIPELINE_OUTPUT_BASE", "/var/aitip/training/pipelines"),
+		PipelineInterval:    parseDuration(getEnv("PIPELINE_INTERVAL", "15s")),
+		InferenceServiceURL: getEnv("INFERENCE_SERVICE_URL", "http://inference:8084"),
+		DataServiceURL:      getEnv("DATA_SERVICE_URL", "http://data:8082"),
+
+		SecretsMasterKey:   getEnv("SECRETS_MASTER_KEY", ""),
+		SecretsMasterKeyID: getEnv("SECRETS_MASTER_KEY_ID", "local-1"),
+
+		AdmissionAllowedRegistries: getEnv("ADMISSION_ALLOWED_REGISTRIES", ""),
+		AdmissionAllowedImages:     getEnv("ADMISSION_ALLOWED_IMAGES", ""),
+		AdmissionRequireDigest:     getEnvBool("ADMISSION_REQUIRE_DIGEST", false),
+		AdmissionDatasetRoots:      getEnv("ADMISSION_DATASET_ROOTS", ""),
+		AdmissionOutputRoots:       getEnv("ADMISSION_OUTPUT_ROOTS", ""),
 	}
 }
 
@@ -84,6 +230,15 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
 func parseInt(value string) int {
 	var result int
 	for _, c := range value {
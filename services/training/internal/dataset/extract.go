@@ -0,0 +1,157 @@
+package dataset
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extract 将下载的数据集文件放到 dest 目录：按文件名解压 zip、tar、tar.gz/tgz 和 gz，其他文件以原名保存
+func extract(file, name, dest string) error {
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return extractZip(file, dest)
+	case strings.HasSuffix(lower, ".tar"):
+		return withFile(file, func(r io.Reader) error { return extractTar(r, dest) })
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return withFile(file, func(r io.Reader) error {
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			return extractTar(gz, dest)
+		})
+	case strings.HasSuffix(lower, ".gz"):
+		return withFile(file, func(r io.Reader) error {
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			return writeFile(filepath.Join(dest, filepath.Base(name[:len(name)-len(".gz")])), gz, 0644)
+		})
+	default:
+		return os.Rename(file, filepath.Join(dest, filepath.Base(name)))
+	}
+}
+
+// withFile 打开文件并交给 fn 读取
+func withFile(file string, fn func(io.Reader) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return fn(f)
+}
+
+// extractTar 解压 tar 流，只保留目录和普通文件
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target, err := entryPath(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, fileMode(header.FileInfo().Mode())); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractZip 解压 zip 文件，只保留目录和普通文件
+func extractZip(file, dest string) error {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, entry := range zr.File {
+		target, err := entryPath(dest, entry.Name)
+		if err != nil {
+			return err
+		}
+		mode := entry.Mode()
+		if mode.IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !mode.IsRegular() {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(target, rc, fileMode(mode))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryPath 归档条目在 dest 下的路径，拒绝指向 dest 之外的条目
+func entryPath(dest, name string) (string, error) {
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if target != dest && !strings.HasPrefix(target, dest+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %q escapes the dataset directory", name)
+	}
+	return target, nil
+}
+
+// writeFile 将 r 的内容写入文件，按需创建上级目录
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// fileMode 缓存文件的权限：所有用户可读，保留可执行位
+func fileMode(mode os.FileMode) os.FileMode {
+	if mode&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
@@ -0,0 +1,122 @@
+package dataset
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// archiveEntry 归档中的文件
+type archiveEntry struct {
+	name    string
+	content string
+}
+
+func tarArchive(t *testing.T, entries ...archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: e.name, Mode: 0600, Size: int64(len(e.content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, entries ...archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtract(t *testing.T) {
+	files := []archiveEntry{{"train/a.csv", "a"}, {"b.csv", "b"}}
+	escape := archiveEntry{"../escape.txt", "x"}
+
+	tests := []struct {
+		name    string
+		object  string
+		data    []byte
+		want    map[string]string // 解压后的相对路径 -> 内容
+		wantErr bool
+	}{
+		{"zip", "ds.zip", zipArchive(t, files...), map[string]string{"train/a.csv": "a", "b.csv": "b"}, false},
+		{"tar", "ds.tar", tarArchive(t, files...), map[string]string{"train/a.csv": "a", "b.csv": "b"}, false},
+		{"tar.gz", "ds.TAR.GZ", gzipped(t, tarArchive(t, files...)), map[string]string{"train/a.csv": "a", "b.csv": "b"}, false},
+		{"tgz", "ds.tgz", gzipped(t, tarArchive(t, files...)), map[string]string{"train/a.csv": "a", "b.csv": "b"}, false},
+		{"gz", "data/train.csv.gz", gzipped(t, []byte("rows")), map[string]string{"train.csv": "rows"}, false},
+		{"plain file", "data/train.parquet", []byte("parquet"), map[string]string{"train.parquet": "parquet"}, false},
+		{"zip entry escapes", "ds.zip", zipArchive(t, escape), nil, true},
+		{"tar entry escapes", "ds.tar", tarArchive(t, escape), nil, true},
+		{"corrupt gzip", "ds.tgz", []byte("not gzip"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := filepath.Join(dir, "download")
+			if err := os.WriteFile(file, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			dest := filepath.Join(dir, "dataset")
+
+			err := extract(file, tt.object, dest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, statErr := os.Stat(filepath.Join(dir, "escape.txt")); statErr == nil {
+				t.Fatal("archive entry was written outside the dataset directory")
+			}
+			for path, want := range tt.want {
+				target := filepath.Join(dest, filepath.FromSlash(path))
+				got, err := os.ReadFile(target)
+				if err != nil {
+					t.Fatalf("read %s: %v", path, err)
+				}
+				if string(got) != want {
+					t.Errorf("%s = %q, want %q", path, got, want)
+				}
+				if info, _ := os.Stat(target); info.Mode().Perm() != 0644 {
+					t.Errorf("%s mode = %v, want 0644", path, info.Mode().Perm())
+				}
+			}
+		})
+	}
+}
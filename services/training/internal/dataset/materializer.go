@@ -0,0 +1,186 @@
+package dataset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	pkgminio "github.com/plucky-groove3/ai-train-infer-platform/pkg/minio"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// 对象存储数据集提供给容器的方式
+const (
+	ModeCache = "cache" // 训练服务下载并解压到本地缓存目录，只读挂载到 /data（Docker 执行器）
+	ModeInit  = "init"  // 由执行器在训练容器启动前下载并解压到 /data（Kubernetes init 容器）
+	ModeURL   = "url"   // 不挂载 /data，通过 DATASET_URL 提供预签名下载地址，由训练脚本自行下载
+)
+
+// Config 数据集准备配置
+type Config struct {
+	Mode      string        // cache、init 或 url
+	CacheDir  string        // cache 模式的本地缓存目录，按数据集内容区分
+	URLExpiry time.Duration // url 模式预签名地址的有效期
+}
+
+// Materializer 为使用对象存储数据集（s3://<bucket>/<object>）的任务准备每次尝试的数据集
+//
+// cache 模式下同一内容的数据集只下载一次：缓存目录以数据集校验和与对象 ETag
+// 计算，数据集重新上传后 ETag 变化，会下载新的副本。zip、tar、tar.gz 和 gz
+// 文件解压后挂载，其他文件原样放在 /data 下。
+type Materializer struct {
+	storage *pkgminio.Client
+	cfg     Config
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex // 缓存目录 -> 下载锁，避免多个任务同时下载同一数据集
+}
+
+// NewMaterializer 创建数据集准备器
+func NewMaterializer(storage *pkgminio.Client, cfg Config) *Materializer {
+	if cfg.Mode == "" {
+		cfg.Mode = ModeCache
+	}
+	if cfg.URLExpiry <= 0 {
+		cfg.URLExpiry = 24 * time.Hour
+	}
+
+	return &Materializer{
+		storage: storage,
+		cfg:     cfg,
+		locks:   make(map[string]*sync.Mutex),
+	}
+}
+
+// Mode 数据集提供方式
+func (m *Materializer) Mode() string {
+	return m.cfg.Mode
+}
+
+// Prepare 准备任务本次尝试的数据集：cache 模式设置 job.DatasetMount，url 模式设置 job.DatasetURL，
+// init 模式由执行器下载，不做处理
+func (m *Materializer) Prepare(ctx context.Context, job *domain.TrainingJob) error {
+	bucket, object, ok := job.StoredDataset()
+	if !ok {
+		return nil
+	}
+	if object == "" {
+		return fmt.Errorf("dataset path %s has no object name", job.DatasetPath)
+	}
+
+	switch m.cfg.Mode {
+	case ModeCache:
+		dir, err := m.cache(ctx, bucket, object, job.DatasetVersion)
+		if err != nil {
+			return err
+		}
+		job.DatasetMount = dir
+	case ModeURL:
+		url, err := m.storage.PresignedGetURL(ctx, bucket, object, m.cfg.URLExpiry)
+		if err != nil {
+			return err
+		}
+		job.DatasetURL = url.String()
+	}
+	return nil
+}
+
+// cache 返回数据集在缓存目录中的位置，不存在时下载并解压
+func (m *Materializer) cache(ctx context.Context, bucket, object, checksum string) (string, error) {
+	info, err := m.storage.StatObject(ctx, bucket, object)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(m.cfg.CacheDir, cacheKey(bucket, object, checksum, info.ETag))
+	lock := m.lock(dir)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(dir); err == nil {
+		// 记录最近使用时间，便于按时间清理缓存
+		now := time.Now()
+		os.Chtimes(dir, now, now)
+		return dir, nil
+	}
+
+	// 先下载和解压到临时位置，完成后再重命名，避免中断留下不完整的缓存
+	if err := os.MkdirAll(m.cfg.CacheDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create dataset cache dir: %w", err)
+	}
+	download := dir + ".download"
+	tmp := dir + ".partial"
+	os.Remove(download)
+	os.RemoveAll(tmp)
+	defer os.Remove(download)
+
+	started := time.Now()
+	if err := m.download(ctx, bucket, object, download); err != nil {
+		return "", err
+	}
+	if err := extract(download, path.Base(object), tmp); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("failed to extract dataset %s: %w", object, err)
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		return "", fmt.Errorf("failed to finalize dataset cache: %w", err)
+	}
+
+	logger.Info("Dataset cached",
+		zap.String("object", path.Join(bucket, object)),
+		zap.String("dir", dir),
+		zap.Int64("size", info.Size),
+		zap.Duration("duration", time.Since(started)),
+	)
+	return dir, nil
+}
+
+// download 下载对象到本地文件
+func (m *Materializer) download(ctx context.Context, bucket, object, target string) error {
+	reader, _, err := m.storage.Download(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	file, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create dataset file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return fmt.Errorf("failed to download dataset %s: %w", object, err)
+	}
+	return nil
+}
+
+// lock 获取缓存目录的下载锁
+func (m *Materializer) lock(dir string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lock, ok := m.locks[dir]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[dir] = lock
+	}
+	return lock
+}
+
+// cacheKey 缓存目录名，由对象位置、数据集校验和与 ETag 决定
+func cacheKey(bucket, object, checksum, etag string) string {
+	sum := sha256.Sum256([]byte(bucket + "/" + object + "\n" + checksum + "\n" + etag))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,57 @@
+package dataset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+func TestMaterializerPrepareSkipsLocalDatasets(t *testing.T) {
+	tests := []struct {
+		name        string
+		datasetPath string
+		wantErr     bool
+	}{
+		{"host path", "/mnt/datasets/imagenet", false},
+		{"bucket without object", "s3://datasets/", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 不需要访问对象存储的情况下 storage 不会被使用
+			m := NewMaterializer(nil, Config{CacheDir: t.TempDir()})
+			job := &domain.TrainingJob{DatasetPath: tt.datasetPath}
+			if err := m.Prepare(context.Background(), job); (err != nil) != tt.wantErr {
+				t.Fatalf("Prepare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if job.DatasetMount != "" || job.DatasetURL != "" {
+				t.Errorf("mount = %q, url = %q, want both empty", job.DatasetMount, job.DatasetURL)
+			}
+		})
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := cacheKey("datasets", "imagenet.tar", "sha256:abc", "etag1")
+
+	tests := []struct {
+		name                           string
+		bucket, object, checksum, etag string
+		wantSame                       bool
+	}{
+		{"same content", "datasets", "imagenet.tar", "sha256:abc", "etag1", true},
+		{"reuploaded object", "datasets", "imagenet.tar", "sha256:abc", "etag2", false},
+		{"other version", "datasets", "imagenet.tar", "sha256:def", "etag1", false},
+		{"other object", "datasets", "imagenet-v2.tar", "sha256:abc", "etag1", false},
+		{"other bucket", "archive", "imagenet.tar", "sha256:abc", "etag1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cacheKey(tt.bucket, tt.object, tt.checksum, tt.etag); (got == base) != tt.wantSame {
+				t.Errorf("cacheKey() = %s, base %s, want same = %v", got, base, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Artifact 任务结束后上传到对象存储的输出文件
+type Artifact struct {
+	Path         string    `json:"path"` // 相对输出目录的路径
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	URL          string    `json:"url"` // 预签名下载 URL
+	ExpiresAt    time.Time `json:"expires_at"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// CheckpointAnnouncement 训练脚本通过 AITIP_CHECKPOINT 日志行声明的检查点
+type CheckpointAnnouncement struct {
+	Path    string             `json:"path"` // 容器内路径，需位于 /output 下
+	Step    int64              `json:"step"`
+	Epoch   *int64             `json:"epoch,omitempty"`
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+}
+
+// ResumeJobRequest 从检查点恢复训练请求
+//
+// 暂停中的任务原地继续，只使用 CheckpointID 和 Priority；其他任务以原配置创建新任务。
+type ResumeJobRequest struct {
+	CheckpointID string `json:"checkpoint_id" binding:"omitempty,uuid"` // 未指定时使用最佳检查点，没有最佳时使用最新的；暂停的任务默认使用暂停时的检查点
+	Name         string `json:"name" binding:"omitempty,max=255"`
+	OutputPath   string `json:"output_path" binding:"omitempty,max=500"`
+	Priority     *int   `json:"priority" binding:"omitempty,min=0,max=100"`
+}
+
+// CheckpointDownload 检查点下载信息
+type CheckpointDownload struct {
+	URL         string     `json:"url,omitempty"`        // 单文件检查点的预签名 URL
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // URL 过期时间
+	Filename    string     `json:"filename"`
+	ContentType string     `json:"content_type"`
+	Archive     bool       `json:"archive"` // 目录检查点，以 tar.gz 打包流式下载
+}
@@ -0,0 +1,67 @@
+package domain
+
+// DistributedBackend 分布式通信后端
+type DistributedBackend string
+
+const (
+	BackendNCCL DistributedBackend = "nccl"
+	BackendGloo DistributedBackend = "gloo"
+)
+
+// DefaultMasterPort torch.distributed 的默认 rendezvous 端口
+const DefaultMasterPort = 29500
+
+// DistributedConfig 多容器分布式训练配置（PyTorch DDP / torchrun）
+//
+// 每个节点对应一个容器，任务的 gpu_count、cpu_count、memory_gb 均为单节点的资源，
+// 总资源为单节点资源乘以 num_nodes。
+type DistributedConfig struct {
+	NumNodes     int                `json:"num_nodes" binding:"omitempty,min=1,max=16"`               // 节点（容器）数
+	ProcsPerNode int                `json:"procs_per_node" binding:"omitempty,min=1,max=8"`           // 每个节点的进程数，默认为单节点 GPU 数
+	Backend      DistributedBackend `json:"backend" binding:"omitempty,oneof=nccl gloo"`             // 默认有 GPU 时为 nccl，否则为 gloo
+	MasterPort   int                `json:"master_port,omitempty" binding:"omitempty,min=1024,max=65535"` // rendezvous 端口，默认 29500
+}
+
+// IsDistributed 是否需要以分布式方式运行
+func (c *DistributedConfig) IsDistributed() bool {
+	return c != nil && (c.Nodes() > 1 || c.ProcsPerNode > 1)
+}
+
+// Nodes 节点数，未配置时为 1
+func (c *DistributedConfig) Nodes() int {
+	if c == nil || c.NumNodes < 1 {
+		return 1
+	}
+	return c.NumNodes
+}
+
+// WorldSize 全部节点的进程总数
+func (c *DistributedConfig) WorldSize() int {
+	procs := 1
+	if c != nil && c.ProcsPerNode > 1 {
+		procs = c.ProcsPerNode
+	}
+	return c.Nodes() * procs
+}
+
+// ApplyDefaults 根据单节点 GPU 数补全默认值
+func (c *DistributedConfig) ApplyDefaults(gpuCount int) {
+	if c.NumNodes < 1 {
+		c.NumNodes = 1
+	}
+	if c.ProcsPerNode < 1 {
+		c.ProcsPerNode = 1
+		if gpuCount > 0 {
+			c.ProcsPerNode = gpuCount
+		}
+	}
+	if c.Backend == "" {
+		c.Backend = BackendGloo
+		if gpuCount > 0 {
+			c.Backend = BackendNCCL
+		}
+	}
+	if c.MasterPort == 0 {
+		c.MasterPort = DefaultMasterPort
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "testing"
+
+func TestDistributedConfigApplyDefaults(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           DistributedConfig
+		gpuCount      int
+		want          DistributedConfig
+		wantWorldSize int
+		wantDist      bool
+	}{
+		{
+			name:          "gpu nodes",
+			cfg:           DistributedConfig{NumNodes: 2},
+			gpuCount:      4,
+			want:          DistributedConfig{NumNodes: 2, ProcsPerNode: 4, Backend: BackendNCCL, MasterPort: DefaultMasterPort},
+			wantWorldSize: 8,
+			wantDist:      true,
+		},
+		{
+			name:          "cpu nodes",
+			cfg:           DistributedConfig{NumNodes: 3},
+			want:          DistributedConfig{NumNodes: 3, ProcsPerNode: 1, Backend: BackendGloo, MasterPort: DefaultMasterPort},
+			wantWorldSize: 3,
+			wantDist:      true,
+		},
+		{
+			name:          "single node multi gpu",
+			cfg:           DistributedConfig{},
+			gpuCount:      2,
+			want:          DistributedConfig{NumNodes: 1, ProcsPerNode: 2, Backend: BackendNCCL, MasterPort: DefaultMasterPort},
+			wantWorldSize: 2,
+			wantDist:      true,
+		},
+		{
+			name:          "single process",
+			cfg:           DistributedConfig{},
+			want:          DistributedConfig{NumNodes: 1, ProcsPerNode: 1, Backend: BackendGloo, MasterPort: DefaultMasterPort},
+			wantWorldSize: 1,
+		},
+		{
+			name:          "explicit values are kept",
+			cfg:           DistributedConfig{NumNodes: 2, ProcsPerNode: 1, Backend: BackendGloo, MasterPort: 23456},
+			gpuCount:      8,
+			want:          DistributedConfig{NumNodes: 2, ProcsPerNode: 1, Backend: BackendGloo, MasterPort: 23456},
+			wantWorldSize: 2,
+			wantDist:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.ApplyDefaults(tt.gpuCount)
+			if cfg != tt.want {
+				t.Errorf("ApplyDefaults() = %+v, want %+v", cfg, tt.want)
+			}
+			if got := cfg.WorldSize(); got != tt.wantWorldSize {
+				t.Errorf("WorldSize() = %d, want %d", got, tt.wantWorldSize)
+			}
+			if got := cfg.IsDistributed(); got != tt.wantDist {
+				t.Errorf("IsDistributed() = %v, want %v", got, tt.wantDist)
+			}
+		})
+	}
+}
+
+func TestDistributedConfigNil(t *testing.T) {
+	var cfg *DistributedConfig
+	if cfg.IsDistributed() || cfg.Nodes() != 1 || cfg.WorldSize() != 1 {
+		t.Errorf("nil config: distributed = %v, nodes = %d, world size = %d", cfg.IsDistributed(), cfg.Nodes(), cfg.WorldSize())
+	}
+}
@@ -1,9 +1,12 @@
 package domain
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
 )
 
 // JobStatus 训练任务状态
@@ -11,11 +14,20 @@ type JobStatus string
 
 const (
 	JobStatusPending    JobStatus = "pending"
+	JobStatusQueued     JobStatus = "queued"
 	JobStatusRunning    JobStatus = "running"
 	JobStatusCompleted  JobStatus = "completed"
 	JobStatusFailed     JobStatus = "failed"
 	JobStatusCancelled  JobStatus = "cancelled"
 	JobStatusStopping   JobStatus = "stopping"
+	JobStatusPausing    JobStatus = "pausing" // 已通知训练脚本保存检查点，等待容器退出
+	JobStatusPaused     JobStatus = "paused"  // 已释放资源，可从暂停时的检查点继续
+)
+
+// 任务终止原因，任务被强制结束时记录
+const (
+	TerminationTimeout = "timeout" // 超出运行时间上限或启动超时
+	TerminationStalled = "stalled" // 长时间没有日志输出和指标上报
 )
 
 // FrameworkType 框架类型
@@ -41,7 +53,12 @@ type TrainingJob struct {
 	ModelName       string          `json:"model_name"`
 	ModelVersion    string          `json:"model_version"`
 	DatasetPath     string          `json:"dataset_path"`
+	DatasetID       *uuid.UUID      `json:"dataset_id,omitempty" gorm:"type:uuid"` // 由 dataset_id 创建时的数据集，DatasetPath 为其存储路径
+	DatasetVersion  string          `json:"dataset_version,omitempty"`             // 创建任务时数据集的校验和
+	DatasetMount    string          `json:"-" gorm:"-"`                            // 本次尝试挂载到 /data 的宿主机目录（对象存储数据集的本地缓存）
+	DatasetURL      string          `json:"-" gorm:"-"`                            // 本次尝试的数据集预签名下载地址
 	OutputPath      string          `json:"output_path"`  // 模型输出路径
+	ArtifactPath    string          `json:"artifact_path,omitempty"`               // 输出目录上传到对象存储的位置 <bucket>/<prefix>/
 
 	// 训练配置
 	Framework       FrameworkType   `json:"framework"`
@@ -49,6 +66,7 @@ type TrainingJob struct {
 	Command         []string        `json:"command"`      // 训练命令
 	Hyperparameters map[string]interface{} `json:"hyperparameters"`
 	Environment     map[string]string `json:"environment"` // 环境变量
+	SecretRefs      []secrets.Ref   `json:"secret_refs,omitempty" gorm:"serializer:json"` // 启动容器时注入的密钥，只保存引用
 
 	// 资源配置
 	GPUCount        int             `json:"gpu_count"`
@@ -56,12 +74,31 @@ type TrainingJob struct {
 	CPUCount        int             `json:"cpu_count"`
 	MemoryGB        int             `json:"memory_gb"`
 	TimeoutHours    int             `json:"timeout_hours"`
+	Distributed     *DistributedConfig `json:"distributed,omitempty" gorm:"serializer:json"` // 分布式训练配置，资源为单节点的配置
+
+	// 调度信息
+	Priority        int             `json:"priority"`                          // 优先级，越大越先调度
+	QueuePosition   int             `json:"queue_position,omitempty" gorm:"-"` // 队列位置（从 1 开始，仅排队中有效）
+
+	// 重试信息
+	RetryPolicy     *RetryPolicy    `json:"retry_policy,omitempty" gorm:"serializer:json"`
+	Attempt         int             `json:"attempt"`                           // 当前尝试次数（从 1 开始）
+	NextRetryAt     *time.Time      `json:"next_retry_at,omitempty"`           // 重试退避结束时间，之前不会被调度
+	ResumeFrom      string          `json:"-" gorm:"-"`                        // 本次尝试恢复使用的检查点（宿主机路径）
+	ResumeCheckpointID *uuid.UUID   `json:"resume_checkpoint_id,omitempty"`    // 创建任务时指定的恢复检查点
+
+	// 暂停信息
+	Pauses           int            `json:"pauses"`                              // 暂停次数，暂停产生的尝试不计入重试次数
+	PausedAt         *time.Time     `json:"paused_at,omitempty"`                 // 最近一次暂停的时间
+	PausedCheckpoint string         `json:"paused_checkpoint,omitempty"`         // 暂停时输出目录中最新的检查点，继续时从此恢复
 
 	// 状态信息
 	Status          JobStatus       `json:"status"`
 	StatusMessage   string          `json:"status_message"`
 	Progress        float64         `json:"progress"`
 	ExitCode        *int            `json:"exit_code,omitempty"`
+	TerminationReason string        `json:"termination_reason,omitempty"` // 被强制结束的原因：timeout、stalled
+	RunSeconds      int64           `json:"run_seconds" gorm:"not null;default:0"` // 之前各次尝试累计运行的秒数，不含当前尝试
 
 	// Docker 执行信息
 	ContainerID     string          `json:"container_id"`
@@ -79,19 +116,48 @@ type TrainingJob struct {
 	UpdatedAt       time.Time       `json:"updated_at"`
 }
 
+// StoredDataset 对象存储中的数据集位置，DatasetPath 不是 s3:// 路径时 ok 为 false
+func (j *TrainingJob) StoredDataset() (bucket, object string, ok bool) {
+	return ParseObjectPath(j.DatasetPath)
+}
+
+// ParseObjectPath 解析 s3://<bucket>/<object> 形式的对象存储路径
+func ParseObjectPath(value string) (bucket, object string, ok bool) {
+	rest, found := strings.CutPrefix(value, "s3://")
+	if !found {
+		return "", "", false
+	}
+	bucket, object, _ = strings.Cut(rest, "/")
+	return bucket, object, bucket != ""
+}
+
 // IsTerminal 检查状态是否为终止状态
 func (j *TrainingJob) IsTerminal() bool {
 	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
 }
 
-// CanStart 检查任务是否可以开始
+// Runtime 任务累计运行时间：之前各次尝试的运行时间加上当前尝试已运行的时间
+func (j *TrainingJob) Runtime(now time.Time) time.Duration {
+	total := time.Duration(j.RunSeconds) * time.Second
+	if (j.Status == JobStatusRunning || j.Status == JobStatusPausing) && j.StartedAt != nil {
+		total += now.Sub(*j.StartedAt)
+	}
+	return total
+}
+
+// CanStart 检查任务是否可以开始（包括从暂停状态继续）
 func (j *TrainingJob) CanStart() bool {
-	return j.Status == JobStatusPending
+	return j.Status == JobStatusPending || j.Status == JobStatusQueued || j.Status == JobStatusPaused
 }
 
 // CanStop 检查任务是否可以停止
 func (j *TrainingJob) CanStop() bool {
-	return j.Status == JobStatusPending || j.Status == JobStatusRunning
+	return j.Status == JobStatusPending || j.Status == JobStatusQueued || j.Status == JobStatusRunning || j.Status == JobStatusPaused
+}
+
+// CanPause 检查任务是否可以暂停
+func (j *TrainingJob) CanPause() bool {
+	return j.Status == JobStatusRunning
 }
 
 // UpdateStatus 更新状态
@@ -122,6 +188,45 @@ func (j *TrainingJob) SetProgress(progress float64) {
 	j.UpdatedAt = time.Now()
 }
 
+// RetryPolicy 任务失败重试策略
+//
+// 未设置任何 RetryOn* 条件时，按执行器默认规则判断（通用错误退出码或网络类瞬时错误）。
+type RetryPolicy struct {
+	MaxAttempts          int      `json:"max_attempts" binding:"omitempty,min=1,max=10"`             // 总尝试次数（含首次）
+	InitialDelaySeconds  int      `json:"initial_delay_seconds" binding:"omitempty,min=1,max=3600"`  // 首次重试前的等待时间
+	BackoffMultiplier    float64  `json:"backoff_multiplier" binding:"omitempty,min=1,max=10"`       // 每次重试等待时间的倍数
+	MaxDelaySeconds      int      `json:"max_delay_seconds" binding:"omitempty,min=1,max=86400"`     // 等待时间上限
+	RetryOnExitCodes     []int    `json:"retry_on_exit_codes,omitempty"`                             // 可重试的退出码
+	RetryOnOOM           bool     `json:"retry_on_oom,omitempty"`                                    // OOM 时重试
+	RetryOnLogPatterns   []string `json:"retry_on_log_patterns,omitempty" binding:"omitempty,dive,min=1,max=200"` // 日志中出现这些内容（不区分大小写）时重试
+	ResumeFromCheckpoint *bool    `json:"resume_from_checkpoint,omitempty"`                          // 重试时从最新检查点恢复，默认开启
+}
+
+// ShouldResume 重试时是否从检查点恢复
+func (p *RetryPolicy) ShouldResume() bool {
+	return p == nil || p.ResumeFromCheckpoint == nil || *p.ResumeFromCheckpoint
+}
+
+// JobAttempt 任务的一次执行尝试，每次重试都会使用新的容器
+type JobAttempt struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID         uuid.UUID  `json:"job_id" gorm:"type:uuid;uniqueIndex:idx_job_attempt"`
+	Attempt       int        `json:"attempt" gorm:"uniqueIndex:idx_job_attempt"`
+	Status        JobStatus  `json:"status"`
+	StatusMessage string     `json:"status_message"`
+	ExitCode      *int       `json:"exit_code,omitempty"`
+	ContainerID   string     `json:"container_id,omitempty"`
+	ContainerName string     `json:"container_name,omitempty"`
+	ResumedFrom   string     `json:"resumed_from,omitempty"` // 恢复使用的检查点
+	StartedAt     time.Time  `json:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName 表名
+func (JobAttempt) TableName() string {
+	return "training_job_attempts"
+}
+
 // JobLog 训练日志
 type JobLog struct {
 	ID        uuid.UUID `json:"id"`
@@ -138,20 +243,28 @@ type CreateJobRequest struct {
 	Description     string                 `json:"description" binding:"max=1000"`
 	ProjectID       string                 `json:"project_id" binding:"required,uuid"`
 	ExperimentID    string                 `json:"experiment_id" binding:"omitempty,uuid"`
+	RunID           string                 `json:"run_id" binding:"omitempty,uuid"` // 关联的实验运行记录
 	ModelName       string                 `json:"model_name" binding:"required,max=255"`
 	ModelVersion    string                 `json:"model_version" binding:"max=50"`
-	DatasetPath     string                 `json:"dataset_path" binding:"required,max=500"`
+	DatasetPath     string                 `json:"dataset_path" binding:"required_without=DatasetID,max=500"`
+	DatasetID       string                 `json:"dataset_id" binding:"omitempty,uuid"` // 数据服务中的数据集，与 dataset_path 二选一
+	DatasetVersion  string                 `json:"dataset_version" binding:"omitempty,max=128"` // 固定数据集的校验和，与当前内容不一致时拒绝创建
 	OutputPath      string                 `json:"output_path" binding:"required,max=500"`
 	Framework       FrameworkType          `json:"framework" binding:"required,oneof=pytorch tensorflow other"`
 	Image           string                 `json:"image" binding:"required,max=500"`
 	Command         []string               `json:"command"`
 	Hyperparameters map[string]interface{} `json:"hyperparameters"`
 	Environment     map[string]string      `json:"environment"`
+	SecretRefs      []secrets.Ref          `json:"secret_refs" binding:"omitempty,dive"`
 	GPUCount        int                    `json:"gpu_count" binding:"min=0,max=8"`
 	GPUType         string                 `json:"gpu_type" binding:"max=50"`
 	CPUCount        int                    `json:"cpu_count" binding:"min=1,max=64"`
 	MemoryGB        int                    `json:"memory_gb" binding:"min=1,max=256"`
 	TimeoutHours    int                    `json:"timeout_hours" binding:"min=1,max=168"`
+	Priority        int                    `json:"priority" binding:"min=0,max=100"`
+	RetryPolicy     *RetryPolicy           `json:"retry_policy"`
+	ResumeCheckpointID string              `json:"resume_checkpoint_id" binding:"omitempty,uuid"`
+	Distributed     *DistributedConfig     `json:"distributed"`
 }
 
 // UpdateJobRequest 更新任务请求
@@ -160,11 +273,17 @@ type UpdateJobRequest struct {
 	Description string `json:"description" binding:"omitempty,max=1000"`
 }
 
+// PauseJobRequest 暂停任务请求，未指定的字段使用服务配置
+type PauseJobRequest struct {
+	Signal             string `json:"signal" binding:"omitempty,oneof=SIGTERM SIGINT SIGUSR1 SIGUSR2 SIGHUP"` // 通知训练脚本保存检查点的信号
+	GracePeriodSeconds int    `json:"grace_period_seconds" binding:"omitempty,min=1,max=3600"`                 // 等待容器自行退出的时间，超时后强制停止
+}
+
 // ListJobsRequest 列出任务请求
 type ListJobsRequest struct {
 	ProjectID    string     `form:"project_id" binding:"omitempty,uuid"`
 	ExperimentID string     `form:"experiment_id" binding:"omitempty,uuid"`
-	Status       JobStatus  `form:"status" binding:"omitempty,oneof=pending running completed failed cancelled stopping"`
+	Status       JobStatus  `form:"status" binding:"omitempty,oneof=pending queued running completed failed cancelled stopping pausing paused"`
 	Page         int        `form:"page,default=1" binding:"min=1"`
 	PageSize     int        `form:"page_size,default=20" binding:"min=1,max=100"`
 }
@@ -176,6 +295,7 @@ type JobResponse struct {
 	Description     string                 `json:"description"`
 	ProjectID       uuid.UUID              `json:"project_id"`
 	ExperimentID    *uuid.UUID             `json:"experiment_id,omitempty"`
+	RunID           *uuid.UUID             `json:"run_id,omitempty"`
 	UserID          uuid.UUID              `json:"user_id"`
 	ModelName       string                 `json:"model_name"`
 	ModelVersion    string                 `json:"model_version"`
@@ -183,7 +303,18 @@ type JobResponse struct {
 	Status          JobStatus              `json:"status"`
 	StatusMessage   string                 `json:"status_message"`
 	Progress        float64                `json:"progress"`
+	TerminationReason string               `json:"termination_reason,omitempty"`
+	RunSeconds      int64                  `json:"run_seconds"`
 	GPUCount        int                    `json:"gpu_count"`
+	Distributed     *DistributedConfig     `json:"distributed,omitempty"`
+	Priority        int                    `json:"priority"`
+	QueuePosition   int                    `json:"queue_position,omitempty"`
+	RetryPolicy     *RetryPolicy           `json:"retry_policy,omitempty"`
+	Attempt         int                    `json:"attempt"`
+	NextRetryAt     *time.Time             `json:"next_retry_at,omitempty"`
+	ResumeCheckpointID *uuid.UUID          `json:"resume_checkpoint_id,omitempty"`
+	Pauses          int                    `json:"pauses,omitempty"`
+	PausedAt        *time.Time             `json:"paused_at,omitempty"`
 	ContainerID     string                 `json:"container_id,omitempty"`
 	ModelID         *uuid.UUID             `json:"model_id,omitempty"`
 	QueuedAt        *time.Time             `json:"queued_at"`
@@ -192,6 +323,10 @@ type JobResponse struct {
 	CreatedAt       time.Time              `json:"created_at"`
 	UpdatedAt       time.Time              `json:"updated_at"`
 	Hyperparameters map[string]interface{} `json:"hyperparameters,omitempty"`
+	SecretRefs      []secrets.Ref          `json:"secret_refs,omitempty"`
+	DatasetID       *uuid.UUID             `json:"dataset_id,omitempty"`
+	DatasetVersion  string                 `json:"dataset_version,omitempty"`
+	ArtifactPath    string                 `json:"artifact_path,omitempty"`
 }
 
 // ToResponse 转换为响应
@@ -202,6 +337,7 @@ func (j *TrainingJob) ToResponse() *JobResponse {
 		Description:     j.Description,
 		ProjectID:       j.ProjectID,
 		ExperimentID:    j.ExperimentID,
+		RunID:           j.RunID,
 		UserID:          j.UserID,
 		ModelName:       j.ModelName,
 		ModelVersion:    j.ModelVersion,
@@ -209,7 +345,18 @@ func (j *TrainingJob) ToResponse() *JobResponse {
 		Status:          j.Status,
 		StatusMessage:   j.StatusMessage,
 		Progress:        j.Progress,
+		TerminationReason: j.TerminationReason,
+		RunSeconds:      j.RunSeconds,
 		GPUCount:        j.GPUCount,
+		Distributed:     j.Distributed,
+		Priority:        j.Priority,
+		QueuePosition:   j.QueuePosition,
+		RetryPolicy:     j.RetryPolicy,
+		Attempt:         j.Attempt,
+		NextRetryAt:     j.NextRetryAt,
+		ResumeCheckpointID: j.ResumeCheckpointID,
+		Pauses:          j.Pauses,
+		PausedAt:        j.PausedAt,
 		ContainerID:     j.ContainerID,
 		ModelID:         j.ModelID,
 		QueuedAt:        j.QueuedAt,
@@ -218,17 +365,88 @@ func (j *TrainingJob) ToResponse() *JobResponse {
 		CreatedAt:       j.CreatedAt,
 		UpdatedAt:       j.UpdatedAt,
 		Hyperparameters: j.Hyperparameters,
+		SecretRefs:      j.SecretRefs,
+		DatasetID:       j.DatasetID,
+		DatasetVersion:  j.DatasetVersion,
+		ArtifactPath:    j.ArtifactPath,
 	}
 }
 
 // LogEntry 日志条目（用于 SSE 流）
 type LogEntry struct {
+	ID        string    `json:"id,omitempty"` // 日志流中的条目 ID，按写入顺序递增，可用于分页
 	Level     string    `json:"level"`
 	Source    string    `json:"source"`
 	Message   string    `json:"message"`
+	Attempt   int       `json:"attempt,omitempty"` // 产生日志的尝试次数，0 表示与具体尝试无关
+	Rank      *int      `json:"rank,omitempty"`    // 分布式任务中产生日志的节点序号
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// LogFilter 日志过滤条件
+type LogFilter struct {
+	Attempt int            // 大于 0 时只返回该次尝试的日志
+	Rank    *int           // 非空时只返回该节点的日志
+	Levels  []string       // 非空时只返回这些级别的日志（不区分大小写）
+	Sources []string       // 非空时只返回这些来源的日志：stdout、stderr、system
+	Pattern *regexp.Regexp // 非空时只返回内容匹配的日志
+}
+
+// Match 检查日志是否满足过滤条件
+func (f LogFilter) Match(entry *LogEntry) bool {
+	if f.Attempt > 0 && entry.Attempt != f.Attempt {
+		return false
+	}
+	if f.Rank != nil && (entry.Rank == nil || *entry.Rank != *f.Rank) {
+		return false
+	}
+	if len(f.Levels) > 0 && !containsFold(f.Levels, entry.Level) {
+		return false
+	}
+	if len(f.Sources) > 0 && !containsFold(f.Sources, entry.Source) {
+		return false
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(entry.Message) {
+		return false
+	}
+	return true
+}
+
+// IsEmpty 是否没有任何过滤条件
+func (f LogFilter) IsEmpty() bool {
+	return f.Attempt <= 0 && f.Rank == nil && len(f.Levels) == 0 && len(f.Sources) == 0 && f.Pattern == nil
+}
+
+// containsFold 检查列表中是否包含 value（不区分大小写）
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogSegment 归档到对象存储的一段连续日志（gzip 压缩的 NDJSON，每行一个 LogEntry）
+type LogSegment struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	JobID       uuid.UUID `json:"job_id" gorm:"type:uuid;uniqueIndex:idx_job_log_segment"`
+	Seq         int       `json:"seq" gorm:"uniqueIndex:idx_job_log_segment"` // 段序号，从 1 开始
+	FirstID     string    `json:"first_id"`                                   // 段内第一条日志的条目 ID
+	LastID      string    `json:"last_id"`                                    // 段内最后一条日志的条目 ID
+	FirstTime   time.Time `json:"first_time"`
+	LastTime    time.Time `json:"last_time"`
+	Lines       int       `json:"lines"`
+	Size        int64     `json:"size"` // 压缩后的字节数
+	StoragePath string    `json:"storage_path"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (LogSegment) TableName() string {
+	return "training_log_segments"
+}
+
 // MetricRecord 指标记录（用于 WebSocket 推送）
 type MetricRecord struct {
 	ID          uuid.UUID       `json:"id"`
@@ -242,10 +460,24 @@ type MetricRecord struct {
 	CreatedAt   time.Time       `json:"created_at"`
 }
 
+// 任务事件类型，强制结束的任务以终止原因（timeout、stalled）作为事件类型
+const (
+	JobEventCreated   = "created"
+	JobEventStarted   = "started"
+	JobEventCompleted = "completed"
+	JobEventFailed    = "failed"
+	JobEventStopped   = "stopped"
+	JobEventRetrying  = "retrying"
+	JobEventPaused    = "paused"
+	JobEventResumed   = "resumed"
+)
+
 // JobEvent 任务事件
 type JobEvent struct {
-	Type      string    `json:"type"` // created, started, completed, failed, stopped
+	Type      string    `json:"type"` // created, started, completed, failed, stopped, retrying, paused, resumed, timeout, stalled
 	JobID     uuid.UUID `json:"job_id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	Attempt   int       `json:"attempt"`
 	Status    JobStatus `json:"status"`
 	Message   string    `json:"message"`
 	Timestamp time.Time `json:"timestamp"`
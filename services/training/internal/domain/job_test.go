@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrainingJobRuntime(t *testing.T) {
+	now := time.Now()
+	startedAt := now.Add(-30 * time.Minute)
+
+	tests := []struct {
+		name string
+		job  TrainingJob
+		want time.Duration
+	}{
+		{"first attempt", TrainingJob{Status: JobStatusRunning, StartedAt: &startedAt}, 30 * time.Minute},
+		{"retried attempt", TrainingJob{Status: JobStatusRunning, StartedAt: &startedAt, RunSeconds: 3600}, 90 * time.Minute},
+		{"pausing", TrainingJob{Status: JobStatusPausing, StartedAt: &startedAt, RunSeconds: 60}, 31 * time.Minute},
+		{"queued for retry", TrainingJob{Status: JobStatusQueued, StartedAt: &startedAt, RunSeconds: 3600}, time.Hour},
+		{"paused", TrainingJob{Status: JobStatusPaused, StartedAt: &startedAt, RunSeconds: 600}, 10 * time.Minute},
+		{"not started", TrainingJob{Status: JobStatusRunning}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.Runtime(now); got != tt.want {
+				t.Errorf("Runtime() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrainingJobTransitions(t *testing.T) {
+	tests := []struct {
+		status    JobStatus
+		wantStart bool
+		wantStop  bool
+		wantPause bool
+	}{
+		{JobStatusPending, true, true, false},
+		{JobStatusQueued, true, true, false},
+		{JobStatusRunning, false, true, true},
+		{JobStatusPausing, false, false, false},
+		{JobStatusPaused, true, true, false},
+		{JobStatusStopping, false, false, false},
+		{JobStatusCompleted, false, false, false},
+		{JobStatusFailed, false, false, false},
+		{JobStatusCancelled, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			job := &TrainingJob{Status: tt.status}
+			if got := job.CanStart(); got != tt.wantStart {
+				t.Errorf("CanStart() = %v, want %v", got, tt.wantStart)
+			}
+			if got := job.CanStop(); got != tt.wantStop {
+				t.Errorf("CanStop() = %v, want %v", got, tt.wantStop)
+			}
+			if got := job.CanPause(); got != tt.wantPause {
+				t.Errorf("CanPause() = %v, want %v", got, tt.wantPause)
+			}
+		})
+	}
+}
+
+func TestParseObjectPath(t *testing.T) {
+	tests := []struct {
+		value      string
+		wantBucket string
+		wantObject string
+		wantOK     bool
+	}{
+		{"s3://datasets/imagenet/train.tar", "datasets", "imagenet/train.tar", true},
+		{"s3://datasets", "datasets", "", true},
+		{"s3://", "", "", false},
+		{"/mnt/datasets/imagenet", "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			bucket, object, ok := ParseObjectPath(tt.value)
+			if bucket != tt.wantBucket || object != tt.wantObject || ok != tt.wantOK {
+				t.Errorf("ParseObjectPath() = %q, %q, %v, want %q, %q, %v", bucket, object, ok, tt.wantBucket, tt.wantObject, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MetricReport 训练脚本上报的一组指标，来自 AITIP_METRIC 日志行或指标上报接口，例如：
+// AITIP_METRIC {"step": 1200, "epoch": 3, "metrics": {"loss": 0.31, "val_acc": 0.92}}
+//
+// 也接受扁平形式 {"step": 1200, "loss": 0.31}，step、epoch、timestamp 以外的数值字段均视为指标。
+type MetricReport struct {
+	Step      *int               `json:"step,omitempty"`
+	Epoch     *int               `json:"epoch,omitempty"`
+	Timestamp *time.Time         `json:"timestamp,omitempty"`
+	Metrics   map[string]float64 `json:"metrics"`
+}
+
+// UnmarshalJSON 解析嵌套或扁平形式的指标
+func (r *MetricReport) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	r.Metrics = make(map[string]float64)
+	for key, raw := range fields {
+		var err error
+		switch key {
+		case "step":
+			err = json.Unmarshal(raw, &r.Step)
+		case "epoch":
+			err = json.Unmarshal(raw, &r.Epoch)
+		case "timestamp":
+			err = json.Unmarshal(raw, &r.Timestamp)
+		case "metrics":
+			err = json.Unmarshal(raw, &r.Metrics)
+		default:
+			var value float64
+			if json.Unmarshal(raw, &value) == nil {
+				r.Metrics[key] = value
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Validate 校验至少包含一个有限数值的指标
+func (r *MetricReport) Validate() error {
+	if len(r.Metrics) == 0 {
+		return fmt.Errorf("no metrics in report")
+	}
+	for key, value := range r.Metrics {
+		if key == "" {
+			return fmt.Errorf("metric key must not be empty")
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("metric %s is not a finite number", key)
+		}
+	}
+	return nil
+}
+
+// 指标曲线降采样方式
+const (
+	DownsampleLTTB   = "lttb"   // Largest-Triangle-Three-Buckets，保留曲线形状
+	DownsampleBucket = "bucket" // 分桶聚合为均值并保留最小值、最大值
+	DownsampleNone   = "none"
+)
+
+// MetricPoint 指标曲线上的一个点，分桶降采样时 Value 为桶内均值
+type MetricPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Step      *int      `json:"step,omitempty"`
+	Epoch     *int      `json:"epoch,omitempty"`
+	Value     float64   `json:"value"`
+	Min       *float64  `json:"min,omitempty"`
+	Max       *float64  `json:"max,omitempty"`
+	Count     int       `json:"count,omitempty"`
+}
+
+// MetricSeries 单个指标的曲线
+type MetricSeries struct {
+	Name       string        `json:"name"`
+	Points     []MetricPoint `json:"points"`
+	Total      int           `json:"total"`                // 降采样前的点数
+	Downsample string        `json:"downsample,omitempty"` // 实际使用的降采样方式，未降采样时为空
+}
+
+// MetricsQuery 指标查询参数
+type MetricsQuery struct {
+	Type       string `form:"type"`
+	MaxPoints  int    `form:"max_points" binding:"omitempty,min=3,max=10000"`
+	Downsample string `form:"downsample" binding:"omitempty,oneof=lttb bucket none"`
+}
@@ -0,0 +1,407 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PipelineStepType 流水线步骤类型
+type PipelineStepType string
+
+const (
+	PipelineStepTraining PipelineStepType = "training" // 运行训练任务（数据预处理、训练、评估）
+	PipelineStepDeploy   PipelineStepType = "deploy"   // 部署为推理服务
+)
+
+// PipelineRunStatus 流水线运行状态
+type PipelineRunStatus string
+
+const (
+	PipelineRunRunning   PipelineRunStatus = "running"
+	PipelineRunCompleted PipelineRunStatus = "completed"
+	PipelineRunFailed    PipelineRunStatus = "failed"
+	PipelineRunCancelled PipelineRunStatus = "cancelled"
+)
+
+// StepStatus 流水线步骤状态
+type StepStatus string
+
+const (
+	StepStatusPending   StepStatus = "pending" // 等待依赖完成
+	StepStatusRunning   StepStatus = "running" // 训练任务或推理服务已创建
+	StepStatusCompleted StepStatus = "completed"
+	StepStatusFailed    StepStatus = "failed"
+	StepStatusSkipped   StepStatus = "skipped" // 条件不满足或依赖未完成
+	StepStatusCancelled StepStatus = "cancelled"
+)
+
+// IsTerminal 步骤是否已结束
+func (s StepStatus) IsTerminal() bool {
+	return s != StepStatusPending && s != StepStatusRunning
+}
+
+// 条件比较运算符
+var conditionOperators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// stepNamePattern 步骤名，同时用于任务名和输出目录
+var stepNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]{0,62})$`)
+
+// PipelineCondition 步骤执行条件：上游训练步骤的指标满足阈值时才执行
+type PipelineCondition struct {
+	Step      string  `json:"step"`                // 上游训练步骤
+	Metric    string  `json:"metric"`              // 指标名，如 val_accuracy
+	Operator  string  `json:"operator"`            // >、>=、<、<=、==、!=
+	Value     float64 `json:"value"`               // 阈值
+	Aggregate string  `json:"aggregate,omitempty"` // last（默认）、min、max
+}
+
+// Evaluate 比较指标值与阈值
+func (c *PipelineCondition) Evaluate(value float64) bool {
+	return conditionOperators[c.Operator](value, c.Value)
+}
+
+// String 条件的可读形式
+func (c *PipelineCondition) String() string {
+	return fmt.Sprintf("%s.%s %s %g", c.Step, c.Metric, c.Operator, c.Value)
+}
+
+// PipelineDeploy 部署步骤配置，对应推理服务的创建请求
+type PipelineDeploy struct {
+	Name        string                 `json:"name,omitempty"`       // 推理服务名，默认为 <流水线名>-<步骤名>
+	ModelID     string                 `json:"model_id,omitempty"`   // 已注册的模型，为空时将上游步骤的输出注册为模型
+	ModelName   string                 `json:"model_name,omitempty"` // 注册模型时使用的名称，默认为训练任务的 model_name
+	Type        string                 `json:"type"`                 // triton 或 vllm
+	Config      map[string]interface{} `json:"config,omitempty"`
+	Environment map[string]string      `json:"environment,omitempty"`
+	GPUCount    int                    `json:"gpu_count"`
+	GPUType     string                 `json:"gpu_type,omitempty"`
+	CPUCount    int                    `json:"cpu_count"`
+	MemoryGB    int                    `json:"memory_gb"`
+}
+
+// PipelineStep 流水线步骤定义
+//
+// 训练步骤的 /data 依次取自 InputFrom 指定的上游步骤的 /output、DatasetID 解析出的数据集、
+// Job.DatasetPath；输出目录未指定时为 <输出根目录>/<运行 ID>/<步骤名>。
+type PipelineStep struct {
+	Name      string             `json:"name"`
+	Type      PipelineStepType   `json:"type,omitempty"` // 默认为 training
+	DependsOn []string           `json:"depends_on,omitempty"`
+	InputFrom string             `json:"input_from,omitempty"` // 使用其输出作为输入的上游步骤，只有一个上游训练步骤时可省略
+	DatasetID string             `json:"dataset_id,omitempty"` // 数据服务中的数据集
+	When      *PipelineCondition `json:"when,omitempty"`
+	Job       *CreateJobRequest  `json:"job,omitempty"`    // 训练步骤的任务模板，name、project_id、dataset_path、output_path 可省略
+	Deploy    *PipelineDeploy    `json:"deploy,omitempty"` // 部署步骤配置
+}
+
+// PipelineSpec 流水线定义
+type PipelineSpec struct {
+	Steps []*PipelineStep `json:"steps"`
+}
+
+// Step 按名称查找步骤
+func (s *PipelineSpec) Step(name string) *PipelineStep {
+	for _, step := range s.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// Validate 校验步骤定义和依赖关系，并补全默认值
+func (s *PipelineSpec) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("pipeline has no steps")
+	}
+
+	names := make(map[string]bool, len(s.Steps))
+	for _, step := range s.Steps {
+		if !stepNamePattern.MatchString(step.Name) {
+			return fmt.Errorf("invalid step name %q: use lowercase letters, digits, '-' and '_'", step.Name)
+		}
+		if names[step.Name] {
+			return fmt.Errorf("duplicate step name %q", step.Name)
+		}
+		names[step.Name] = true
+		if step.Type == "" {
+			step.Type = PipelineStepTraining
+		}
+	}
+
+	for _, step := range s.Steps {
+		for _, dep := range step.DependsOn {
+			if !names[dep] {
+				return fmt.Errorf("step %q depends on unknown step %q", step.Name, dep)
+			}
+			if dep == step.Name {
+				return fmt.Errorf("step %q depends on itself", step.Name)
+			}
+		}
+	}
+	if _, err := s.Order(); err != nil {
+		return err
+	}
+
+	for _, step := range s.Steps {
+		if err := s.validateStep(step); err != nil {
+			return fmt.Errorf("step %q: %w", step.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateStep 校验单个步骤，依赖关系已校验
+func (s *PipelineSpec) validateStep(step *PipelineStep) error {
+	ancestors := s.Ancestors(step.Name)
+
+	// 只有一个上游训练步骤时默认使用它的输出
+	if step.InputFrom == "" && step.DatasetID == "" && (step.Job == nil || step.Job.DatasetPath == "") {
+		var upstream []string
+		for _, dep := range step.DependsOn {
+			if s.Step(dep).Type == PipelineStepTraining {
+				upstream = append(upstream, dep)
+			}
+		}
+		if len(upstream) == 1 {
+			step.InputFrom = upstream[0]
+		}
+	}
+	if step.InputFrom != "" {
+		if !ancestors[step.InputFrom] {
+			return fmt.Errorf("input_from %q must be an upstream step", step.InputFrom)
+		}
+		if s.Step(step.InputFrom).Type != PipelineStepTraining {
+			return fmt.Errorf("input_from %q must be a training step", step.InputFrom)
+		}
+	}
+	if step.DatasetID != "" {
+		if _, err := uuid.Parse(step.DatasetID); err != nil {
+			return fmt.Errorf("invalid dataset_id %q", step.DatasetID)
+		}
+	}
+
+	if step.When != nil {
+		if !ancestors[step.When.Step] || s.Step(step.When.Step).Type != PipelineStepTraining {
+			return fmt.Errorf("condition must reference an upstream training step, got %q", step.When.Step)
+		}
+		if step.When.Metric == "" {
+			return fmt.Errorf("condition requires a metric")
+		}
+		if _, ok := conditionOperators[step.When.Operator]; !ok {
+			return fmt.Errorf("unknown condition operator %q", step.When.Operator)
+		}
+		switch step.When.Aggregate {
+		case "":
+			step.When.Aggregate = "last"
+		case "last", "min", "max":
+		default:
+			return fmt.Errorf("unknown condition aggregate %q", step.When.Aggregate)
+		}
+	}
+
+	switch step.Type {
+	case PipelineStepTraining:
+		if step.Job == nil {
+			return fmt.Errorf("training step requires job")
+		}
+		if step.Deploy != nil {
+			return fmt.Errorf("training step must not set deploy")
+		}
+		if step.InputFrom == "" && step.DatasetID == "" && step.Job.DatasetPath == "" {
+			return fmt.Errorf("training step requires input_from, dataset_id or job.dataset_path")
+		}
+	case PipelineStepDeploy:
+		if step.Deploy == nil {
+			return fmt.Errorf("deploy step requires deploy")
+		}
+		if step.Job != nil || step.DatasetID != "" {
+			return fmt.Errorf("deploy step must not set job or dataset_id")
+		}
+		if step.Deploy.Type != "triton" && step.Deploy.Type != "vllm" {
+			return fmt.Errorf("deploy.type must be triton or vllm")
+		}
+		if step.Deploy.ModelID != "" {
+			if _, err := uuid.Parse(step.Deploy.ModelID); err != nil {
+				return fmt.Errorf("invalid deploy.model_id %q", step.Deploy.ModelID)
+			}
+		} else if step.InputFrom == "" {
+			return fmt.Errorf("deploy step requires deploy.model_id or an upstream training step")
+		}
+	default:
+		return fmt.Errorf("unknown step type %q", step.Type)
+	}
+	return nil
+}
+
+// Order 按依赖关系排序的步骤名，存在环时返回错误
+func (s *PipelineSpec) Order() ([]string, error) {
+	indegree := make(map[string]int, len(s.Steps))
+	dependents := make(map[string][]string, len(s.Steps))
+	for _, step := range s.Steps {
+		indegree[step.Name] += 0
+		for _, dep := range step.DependsOn {
+			indegree[step.Name]++
+			dependents[dep] = append(dependents[dep], step.Name)
+		}
+	}
+
+	// 按定义顺序处理同一层的步骤，保证结果稳定
+	var order []string
+	done := make(map[string]bool, len(s.Steps))
+	for len(order) < len(s.Steps) {
+		progressed := false
+		for _, step := range s.Steps {
+			if done[step.Name] || indegree[step.Name] > 0 {
+				continue
+			}
+			done[step.Name] = true
+			order = append(order, step.Name)
+			for _, next := range dependents[step.Name] {
+				indegree[next]--
+			}
+			progressed = true
+		}
+		if !progressed {
+			return nil, fmt.Errorf("pipeline steps contain a dependency cycle")
+		}
+	}
+	return order, nil
+}
+
+// Ancestors 步骤的全部上游步骤
+func (s *PipelineSpec) Ancestors(name string) map[string]bool {
+	ancestors := make(map[string]bool)
+	queue := []string{name}
+	for len(queue) > 0 {
+		step := s.Step(queue[0])
+		queue = queue[1:]
+		if step == nil {
+			continue
+		}
+		for _, dep := range step.DependsOn {
+			if !ancestors[dep] {
+				ancestors[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return ancestors
+}
+
+// Pipeline 流水线
+type Pipeline struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID   uuid.UUID    `json:"project_id" gorm:"type:uuid;not null;index"`
+	UserID      uuid.UUID    `json:"user_id" gorm:"type:uuid"`
+	Name        string       `json:"name" gorm:"not null"`
+	Description string       `json:"description"`
+	Spec        PipelineSpec `json:"spec" gorm:"serializer:json"`
+	Runs        int          `json:"runs"` // 已创建的运行数，用于运行编号
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TableName 表名
+func (Pipeline) TableName() string {
+	return "pipelines"
+}
+
+// StepRun 一次运行中的步骤状态
+type StepRun struct {
+	Name        string           `json:"name"`
+	Type        PipelineStepType `json:"type"`
+	Status      StepStatus       `json:"status"`
+	Message     string           `json:"message,omitempty"`
+	JobID       *uuid.UUID       `json:"job_id,omitempty"`     // 训练步骤的任务
+	ServiceID   *uuid.UUID       `json:"service_id,omitempty"` // 部署步骤的推理服务
+	ModelID     *uuid.UUID       `json:"model_id,omitempty"`   // 部署的模型
+	DatasetPath string           `json:"dataset_path,omitempty"`
+	OutputPath  string           `json:"output_path,omitempty"`
+	StartedAt   *time.Time       `json:"started_at,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// Finish 结束步骤
+func (s *StepRun) Finish(status StepStatus, message string) {
+	now := time.Now()
+	s.Status = status
+	s.Message = message
+	s.CompletedAt = &now
+}
+
+// PipelineRun 流水线的一次运行，保存创建时的流水线定义，修改流水线不影响进行中的运行
+type PipelineRun struct {
+	ID          uuid.UUID         `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PipelineID  uuid.UUID         `json:"pipeline_id" gorm:"type:uuid;not null;index"`
+	ProjectID   uuid.UUID         `json:"project_id" gorm:"type:uuid;not null"`
+	UserID      uuid.UUID         `json:"user_id" gorm:"type:uuid"`
+	Number      int               `json:"number"` // 流水线内的运行编号，从 1 开始
+	Name        string            `json:"name"`   // 创建运行时的流水线名
+	Spec        PipelineSpec      `json:"spec" gorm:"serializer:json"`
+	Steps       []*StepRun        `json:"steps" gorm:"serializer:json"`
+	Status      PipelineRunStatus `json:"status" gorm:"not null;index"`
+	Message     string            `json:"message"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// TableName 表名
+func (PipelineRun) TableName() string {
+	return "pipeline_runs"
+}
+
+// Step 按名称查找步骤状态
+func (r *PipelineRun) Step(name string) *StepRun {
+	for _, step := range r.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// IsTerminal 运行是否已结束
+func (r *PipelineRun) IsTerminal() bool {
+	return r.Status != PipelineRunRunning
+}
+
+// CreatePipelineRequest 创建流水线请求，可以是 JSON 或 YAML
+type CreatePipelineRequest struct {
+	ProjectID   string       `json:"project_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Spec        PipelineSpec `json:"spec"`
+}
+
+// UpdatePipelineRequest 更新流水线请求，未设置的字段保持不变
+type UpdatePipelineRequest struct {
+	Name        *string       `json:"name"`
+	Description *string       `json:"description"`
+	Spec        *PipelineSpec `json:"spec"`
+}
+
+// ListPipelinesRequest 列出流水线请求
+type ListPipelinesRequest struct {
+	ProjectID string `form:"project_id" binding:"required,uuid"`
+	Page      int    `form:"page,default=1" binding:"min=1"`
+	PageSize  int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// ListPipelineRunsRequest 列出运行记录请求
+type ListPipelineRunsRequest struct {
+	Status   PipelineRunStatus `form:"status" binding:"omitempty,oneof=running completed failed cancelled"`
+	Page     int               `form:"page,default=1" binding:"min=1"`
+	PageSize int               `form:"page_size,default=20" binding:"min=1,max=100"`
+}
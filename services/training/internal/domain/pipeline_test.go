@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// trainingStep 从 dataset_path 读取数据的训练步骤
+func trainingStep(name string, dependsOn ...string) *PipelineStep {
+	return &PipelineStep{Name: name, DependsOn: dependsOn, Job: &CreateJobRequest{DatasetPath: "/data/" + name}}
+}
+
+func TestPipelineSpecOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []*PipelineStep
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "chain",
+			steps: []*PipelineStep{trainingStep("eval", "train"), trainingStep("train", "prep"), trainingStep("prep")},
+			want:  []string{"prep", "train", "eval"},
+		},
+		{
+			name:  "independent steps keep definition order",
+			steps: []*PipelineStep{trainingStep("b"), trainingStep("a"), trainingStep("c")},
+			want:  []string{"b", "a", "c"},
+		},
+		{
+			name: "diamond",
+			steps: []*PipelineStep{
+				trainingStep("deploy", "eval-a", "eval-b"),
+				trainingStep("eval-b", "train"),
+				trainingStep("eval-a", "train"),
+				trainingStep("train"),
+			},
+			want: []string{"train", "eval-b", "eval-a", "deploy"},
+		},
+		{
+			name:    "cycle",
+			steps:   []*PipelineStep{trainingStep("prep", "eval"), trainingStep("train", "prep"), trainingStep("eval", "train")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &PipelineSpec{Steps: tt.steps}
+			got, err := spec.Order()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Order() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Order() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineSpecValidate(t *testing.T) {
+	condition := func(step, operator string) *PipelineCondition {
+		return &PipelineCondition{Step: step, Metric: "val_accuracy", Operator: operator, Value: 0.9}
+	}
+
+	tests := []struct {
+		name    string
+		steps   func() []*PipelineStep
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			steps: func() []*PipelineStep { return []*PipelineStep{trainingStep("prep"), trainingStep("train", "prep")} },
+		},
+		{
+			name:    "empty",
+			steps:   func() []*PipelineStep { return nil },
+			wantErr: "no steps",
+		},
+		{
+			name:    "invalid name",
+			steps:   func() []*PipelineStep { return []*PipelineStep{trainingStep("Train")} },
+			wantErr: "invalid step name",
+		},
+		{
+			name:    "duplicate name",
+			steps:   func() []*PipelineStep { return []*PipelineStep{trainingStep("train"), trainingStep("train")} },
+			wantErr: "duplicate step name",
+		},
+		{
+			name:    "unknown dependency",
+			steps:   func() []*PipelineStep { return []*PipelineStep{trainingStep("train", "prep")} },
+			wantErr: "unknown step",
+		},
+		{
+			name:    "self dependency",
+			steps:   func() []*PipelineStep { return []*PipelineStep{trainingStep("train", "train")} },
+			wantErr: "depends on itself",
+		},
+		{
+			name:    "cycle",
+			steps:   func() []*PipelineStep { return []*PipelineStep{trainingStep("a", "b"), trainingStep("b", "a")} },
+			wantErr: "cycle",
+		},
+		{
+			name: "input from non-ancestor",
+			steps: func() []*PipelineStep {
+				train := trainingStep("train")
+				train.InputFrom = "prep"
+				return []*PipelineStep{trainingStep("prep"), train}
+			},
+			wantErr: "must be an upstream step",
+		},
+		{
+			name: "condition on non-ancestor",
+			steps: func() []*PipelineStep {
+				eval := trainingStep("eval", "prep")
+				eval.When = condition("train", ">=")
+				return []*PipelineStep{trainingStep("prep"), trainingStep("train"), eval}
+			},
+			wantErr: "condition must reference an upstream training step",
+		},
+		{
+			name: "unknown operator",
+			steps: func() []*PipelineStep {
+				eval := trainingStep("eval", "train")
+				eval.When = condition("train", "=~")
+				return []*PipelineStep{trainingStep("train"), eval}
+			},
+			wantErr: "unknown condition operator",
+		},
+		{
+			name: "deploy without model",
+			steps: func() []*PipelineStep {
+				return []*PipelineStep{{Name: "serve", Type: PipelineStepDeploy, Deploy: &PipelineDeploy{Type: "vllm"}}}
+			},
+			wantErr: "requires deploy.model_id or an upstream training step",
+		},
+		{
+			name: "deploy after training",
+			steps: func() []*PipelineStep {
+				return []*PipelineStep{trainingStep("train"), {Name: "serve", Type: PipelineStepDeploy, DependsOn: []string{"train"}, Deploy: &PipelineDeploy{Type: "triton"}}}
+			},
+		},
+		{
+			name: "training without input",
+			steps: func() []*PipelineStep {
+				return []*PipelineStep{{Name: "train", Job: &CreateJobRequest{}}}
+			},
+			wantErr: "requires input_from, dataset_id or job.dataset_path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &PipelineSpec{Steps: tt.steps()}
+			err := spec.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPipelineSpecValidateDefaults(t *testing.T) {
+	eval := &PipelineStep{Name: "eval", DependsOn: []string{"train"}, Job: &CreateJobRequest{}, When: &PipelineCondition{Step: "train", Metric: "loss", Operator: "<", Value: 0.1}}
+	spec := &PipelineSpec{Steps: []*PipelineStep{trainingStep("train"), eval}}
+
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if eval.Type != PipelineStepTraining {
+		t.Errorf("type = %q, want %q", eval.Type, PipelineStepTraining)
+	}
+	if eval.InputFrom != "train" {
+		t.Errorf("input_from = %q, want the only upstream training step", eval.InputFrom)
+	}
+	if eval.When.Aggregate != "last" {
+		t.Errorf("aggregate = %q, want last", eval.When.Aggregate)
+	}
+}
@@ -0,0 +1,272 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+)
+
+// 模板参数类型
+const (
+	ParameterTypeString = "string"
+	ParameterTypeInt    = "int"
+	ParameterTypeFloat  = "float"
+	ParameterTypeBool   = "bool"
+)
+
+// TemplateParameter 模板参数定义，实例化时按定义校验并写入任务超参数
+type TemplateParameter struct {
+	Type        string        `json:"type"` // string、int、float、bool
+	Description string        `json:"description,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Min         *float64      `json:"min,omitempty"` // int 和 float 的下限
+	Max         *float64      `json:"max,omitempty"` // int 和 float 的上限
+	Enum        []interface{} `json:"enum,omitempty"`
+}
+
+// Coerce 按参数定义校验取值，JSON 数字转换为 int 或 float64
+func (p *TemplateParameter) Coerce(value interface{}) (interface{}, error) {
+	var coerced interface{}
+	switch p.Type {
+	case ParameterTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		coerced = s
+	case ParameterTypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		coerced = b
+	case ParameterTypeInt, ParameterTypeFloat:
+		var f float64
+		switch v := value.(type) {
+		case float64:
+			f = v
+		case int:
+			f = float64(v)
+		case int64:
+			f = float64(v)
+		default:
+			return nil, fmt.Errorf("must be a number")
+		}
+		if p.Min != nil && f < *p.Min {
+			return nil, fmt.Errorf("must be >= %g", *p.Min)
+		}
+		if p.Max != nil && f > *p.Max {
+			return nil, fmt.Errorf("must be <= %g", *p.Max)
+		}
+		if p.Type == ParameterTypeInt {
+			if f != math.Trunc(f) {
+				return nil, fmt.Errorf("must be an integer")
+			}
+			coerced = int(f)
+		} else {
+			coerced = f
+		}
+	default:
+		return nil, fmt.Errorf("unknown parameter type %q", p.Type)
+	}
+
+	if len(p.Enum) > 0 {
+		for _, allowed := range p.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(coerced) {
+				return coerced, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %v", p.Enum)
+	}
+	return coerced, nil
+}
+
+// validate 校验参数定义本身
+func (p *TemplateParameter) validate() error {
+	switch p.Type {
+	case ParameterTypeString, ParameterTypeInt, ParameterTypeFloat, ParameterTypeBool:
+	default:
+		return fmt.Errorf("type must be one of string, int, float, bool")
+	}
+	if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+		return fmt.Errorf("min must not be greater than max")
+	}
+	if p.Default != nil {
+		if _, err := p.Coerce(p.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+// JobTemplate 训练任务模板，同一项目内按名称区分，每次修改生成新版本
+//
+// 内置模板由 TemplateManager 生成，不保存在数据库中，ProjectID 为空。
+// 设置 Generator 时实例化会按任务配置生成训练脚本并在容器中执行，Command 为空时使用该脚本。
+type JobTemplate struct {
+	ID              uuid.UUID                     `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID       *uuid.UUID                    `json:"project_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_job_template_version"`
+	Name            string                        `json:"name" gorm:"not null;size:255;uniqueIndex:idx_job_template_version"`
+	Version         int                           `json:"version" gorm:"not null;uniqueIndex:idx_job_template_version"`
+	Description     string                        `json:"description"`
+	Framework       FrameworkType                 `json:"framework" gorm:"size:50"`
+	Generator       string                        `json:"generator,omitempty" gorm:"size:50"` // pytorch、tensorflow、huggingface
+	Image           string                        `json:"image" gorm:"size:500"`
+	Command         []string                      `json:"command,omitempty" gorm:"serializer:json"`
+	ModelName       string                        `json:"model_name,omitempty" gorm:"size:255"`
+	GPUCount        int                           `json:"gpu_count"`
+	GPUType         string                        `json:"gpu_type,omitempty" gorm:"size:50"`
+	CPUCount        int                           `json:"cpu_count,omitempty"`
+	MemoryGB        int                           `json:"memory_gb,omitempty"`
+	TimeoutHours    int                           `json:"timeout_hours,omitempty"`
+	Hyperparameters map[string]interface{}        `json:"hyperparameters,omitempty" gorm:"serializer:json"`
+	Environment     map[string]string             `json:"environment,omitempty" gorm:"serializer:json"`
+	Parameters      map[string]*TemplateParameter `json:"parameters,omitempty" gorm:"serializer:json"` // 参数定义，为空时实例化可传任意参数
+	Builtin         bool                          `json:"builtin" gorm:"-"`
+	CreatedBy       uuid.UUID                     `json:"created_by" gorm:"type:uuid"`
+	CreatedAt       time.Time                     `json:"created_at"`
+}
+
+// TableName 表名
+func (JobTemplate) TableName() string {
+	return "job_templates"
+}
+
+// Validate 校验模板定义
+func (t *JobTemplate) Validate() error {
+	if t.Image == "" && t.Generator == "" {
+		return fmt.Errorf("image is required unless a generator is set")
+	}
+	for name, param := range t.Parameters {
+		if param == nil {
+			return fmt.Errorf("parameter %q has no definition", name)
+		}
+		if err := param.validate(); err != nil {
+			return fmt.Errorf("parameter %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ResolveParameters 合并模板默认超参数、参数默认值和实例化时传入的参数
+func (t *JobTemplate) ResolveParameters(values map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(t.Hyperparameters)+len(values))
+	for key, value := range t.Hyperparameters {
+		resolved[key] = value
+	}
+
+	if len(t.Parameters) == 0 {
+		for key, value := range values {
+			resolved[key] = value
+		}
+		return resolved, nil
+	}
+
+	for key := range values {
+		if _, ok := t.Parameters[key]; !ok {
+			return nil, fmt.Errorf("unknown parameter %q", key)
+		}
+	}
+	for name, param := range t.Parameters {
+		value, ok := values[name]
+		if !ok {
+			value = param.Default
+		}
+		if value == nil {
+			if param.Required {
+				return nil, fmt.Errorf("parameter %q is required", name)
+			}
+			continue
+		}
+		coerced, err := param.Coerce(value)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q %w", name, err)
+		}
+		resolved[name] = coerced
+	}
+	return resolved, nil
+}
+
+// CreateJobTemplateRequest 创建模板请求
+type CreateJobTemplateRequest struct {
+	ProjectID       string                        `json:"project_id" binding:"required,uuid"`
+	Name            string                        `json:"name" binding:"required,max=255"`
+	Description     string                        `json:"description" binding:"max=1000"`
+	Framework       FrameworkType                 `json:"framework" binding:"required,oneof=pytorch tensorflow other"`
+	Generator       string                        `json:"generator" binding:"omitempty,oneof=pytorch tensorflow huggingface"`
+	Image           string                        `json:"image" binding:"max=500"`
+	Command         []string                      `json:"command"`
+	ModelName       string                        `json:"model_name" binding:"max=255"`
+	GPUCount        int                           `json:"gpu_count" binding:"min=0,max=8"`
+	GPUType         string                        `json:"gpu_type" binding:"max=50"`
+	CPUCount        int                           `json:"cpu_count" binding:"omitempty,min=1,max=64"`
+	MemoryGB        int                           `json:"memory_gb" binding:"omitempty,min=1,max=256"`
+	TimeoutHours    int                           `json:"timeout_hours" binding:"omitempty,min=1,max=168"`
+	Hyperparameters map[string]interface{}        `json:"hyperparameters"`
+	Environment     map[string]string             `json:"environment"`
+	Parameters      map[string]*TemplateParameter `json:"parameters"`
+}
+
+// CreateJobTemplateVersionRequest 创建模板新版本请求，未设置的字段沿用基础版本
+type CreateJobTemplateVersionRequest struct {
+	Description     *string                       `json:"description" binding:"omitempty,max=1000"`
+	Framework       *FrameworkType                `json:"framework" binding:"omitempty,oneof=pytorch tensorflow other"`
+	Generator       *string                       `json:"generator" binding:"omitempty,oneof=pytorch tensorflow huggingface"`
+	Image           *string                       `json:"image" binding:"omitempty,max=500"`
+	Command         []string                      `json:"command"`
+	ModelName       *string                       `json:"model_name" binding:"omitempty,max=255"`
+	GPUCount        *int                          `json:"gpu_count" binding:"omitempty,min=0,max=8"`
+	GPUType         *string                       `json:"gpu_type" binding:"omitempty,max=50"`
+	CPUCount        *int                          `json:"cpu_count" binding:"omitempty,min=1,max=64"`
+	MemoryGB        *int                          `json:"memory_gb" binding:"omitempty,min=1,max=256"`
+	TimeoutHours    *int                          `json:"timeout_hours" binding:"omitempty,min=1,max=168"`
+	Hyperparameters map[string]interface{}        `json:"hyperparameters"`
+	Environment     map[string]string             `json:"environment"`
+	Parameters      map[string]*TemplateParameter `json:"parameters"`
+}
+
+// ListJobTemplatesRequest 列出模板请求，每个模板只返回最新版本
+type ListJobTemplatesRequest struct {
+	ProjectID string `form:"project_id" binding:"required,uuid"`
+	Page      int    `form:"page,default=1" binding:"min=1"`
+	PageSize  int    `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// CreateJobFromTemplateRequest 从模板创建任务请求
+//
+// 通过 template_id 指定模板版本，或通过 template 指定模板名（先查找项目模板，再查找内置模板），
+// version 为 0 时使用最新版本。其余字段覆盖模板中的对应值。
+type CreateJobFromTemplateRequest struct {
+	TemplateID         string                 `json:"template_id" binding:"omitempty,uuid"`
+	Template           string                 `json:"template" binding:"max=255"`
+	Version            int                    `json:"version" binding:"min=0"`
+	Name               string                 `json:"name" binding:"required,max=255"`
+	Description        string                 `json:"description" binding:"max=1000"`
+	ProjectID          string                 `json:"project_id" binding:"required,uuid"`
+	ExperimentID       string                 `json:"experiment_id" binding:"omitempty,uuid"`
+	RunID              string                 `json:"run_id" binding:"omitempty,uuid"`
+	ModelName          string                 `json:"model_name" binding:"max=255"`
+	ModelVersion       string                 `json:"model_version" binding:"max=50"`
+	DatasetPath        string                 `json:"dataset_path" binding:"required_without=DatasetID,max=500"`
+	DatasetID          string                 `json:"dataset_id" binding:"omitempty,uuid"`
+	DatasetVersion     string                 `json:"dataset_version" binding:"omitempty,max=128"`
+	OutputPath         string                 `json:"output_path" binding:"required,max=500"`
+	Parameters         map[string]interface{} `json:"parameters"`
+	Image              string                 `json:"image" binding:"max=500"`
+	Command            []string               `json:"command"`
+	Environment        map[string]string      `json:"environment"` // 与模板的环境变量合并
+	SecretRefs         []secrets.Ref          `json:"secret_refs" binding:"omitempty,dive"`
+	GPUCount           *int                   `json:"gpu_count" binding:"omitempty,min=0,max=8"`
+	GPUType            string                 `json:"gpu_type" binding:"max=50"`
+	CPUCount           *int                   `json:"cpu_count" binding:"omitempty,min=1,max=64"`
+	MemoryGB           *int                   `json:"memory_gb" binding:"omitempty,min=1,max=256"`
+	TimeoutHours       *int                   `json:"timeout_hours" binding:"omitempty,min=1,max=168"`
+	Priority           int                    `json:"priority" binding:"min=0,max=100"`
+	RetryPolicy        *RetryPolicy           `json:"retry_policy"`
+	ResumeCheckpointID string                 `json:"resume_checkpoint_id" binding:"omitempty,uuid"`
+	Distributed        *DistributedConfig     `json:"distributed"`
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func TestTemplateParameterCoerce(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   TemplateParameter
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{"string", TemplateParameter{Type: ParameterTypeString}, "text", "text", false},
+		{"string rejects number", TemplateParameter{Type: ParameterTypeString}, 1.0, nil, true},
+		{"bool", TemplateParameter{Type: ParameterTypeBool}, true, true, false},
+		{"int from json number", TemplateParameter{Type: ParameterTypeInt}, 32.0, 32, false},
+		{"int rejects fraction", TemplateParameter{Type: ParameterTypeInt}, 1.5, nil, true},
+		{"int below min", TemplateParameter{Type: ParameterTypeInt, Min: float64Ptr(1)}, 0.0, nil, true},
+		{"float", TemplateParameter{Type: ParameterTypeFloat}, 3, 3.0, false},
+		{"float above max", TemplateParameter{Type: ParameterTypeFloat, Max: float64Ptr(1)}, 1.5, nil, true},
+		{"float rejects string", TemplateParameter{Type: ParameterTypeFloat}, "0.1", nil, true},
+		{"enum", TemplateParameter{Type: ParameterTypeString, Enum: []interface{}{"a", "b"}}, "b", "b", false},
+		{"not in enum", TemplateParameter{Type: ParameterTypeString, Enum: []interface{}{"a", "b"}}, "c", nil, true},
+		{"numeric enum", TemplateParameter{Type: ParameterTypeInt, Enum: []interface{}{8.0, 16.0}}, 16.0, 16, false},
+		{"unknown type", TemplateParameter{Type: "list"}, "x", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.param.Coerce(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Coerce() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Coerce() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobTemplateValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    JobTemplate
+		wantErr bool
+	}{
+		{"image", JobTemplate{Image: "pytorch/pytorch"}, false},
+		{"generator without image", JobTemplate{Generator: "pytorch"}, false},
+		{"no image or generator", JobTemplate{}, true},
+		{"missing parameter definition", JobTemplate{Image: "img", Parameters: map[string]*TemplateParameter{"lr": nil}}, true},
+		{"unknown parameter type", JobTemplate{Image: "img", Parameters: map[string]*TemplateParameter{"lr": {Type: "double"}}}, true},
+		{"min above max", JobTemplate{Image: "img", Parameters: map[string]*TemplateParameter{"lr": {Type: ParameterTypeFloat, Min: float64Ptr(1), Max: float64Ptr(0)}}}, true},
+		{"invalid default", JobTemplate{Image: "img", Parameters: map[string]*TemplateParameter{"epochs": {Type: ParameterTypeInt, Default: "ten"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.tmpl.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobTemplateResolveParameters(t *testing.T) {
+	defined := &JobTemplate{
+		Hyperparameters: map[string]interface{}{"optimizer": "adam"},
+		Parameters: map[string]*TemplateParameter{
+			"epochs":  {Type: ParameterTypeInt, Default: 10},
+			"lr":      {Type: ParameterTypeFloat, Required: true},
+			"warmup":  {Type: ParameterTypeFloat},
+			"dataset": {Type: ParameterTypeString, Default: "train"},
+		},
+	}
+	free := &JobTemplate{Hyperparameters: map[string]interface{}{"optimizer": "adam", "epochs": 10}}
+
+	tests := []struct {
+		name    string
+		tmpl    *JobTemplate
+		values  map[string]interface{}
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "defaults and overrides",
+			tmpl:   defined,
+			values: map[string]interface{}{"lr": 0.01, "epochs": 3.0},
+			want:   map[string]interface{}{"optimizer": "adam", "epochs": 3, "lr": 0.01, "dataset": "train"},
+		},
+		{
+			name:    "missing required parameter",
+			tmpl:    defined,
+			values:  map[string]interface{}{"epochs": 3.0},
+			wantErr: true,
+		},
+		{
+			name:    "unknown parameter",
+			tmpl:    defined,
+			values:  map[string]interface{}{"lr": 0.01, "momentum": 0.9},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			tmpl:    defined,
+			values:  map[string]interface{}{"lr": "fast"},
+			wantErr: true,
+		},
+		{
+			name:   "no definitions accepts any parameter",
+			tmpl:   free,
+			values: map[string]interface{}{"epochs": 3.0, "momentum": 0.9},
+			want:   map[string]interface{}{"optimizer": "adam", "epochs": 3.0, "momentum": 0.9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tmpl.ResolveParameters(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveParameters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
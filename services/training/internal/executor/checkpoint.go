@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// checkpointSuffixes 常见的检查点文件后缀
+var checkpointSuffixes = []string{".ckpt", ".pt", ".pth", ".bin", ".safetensors", ".h5", ".keras"}
+
+// LocalCheckpoint 输出目录中的检查点
+type LocalCheckpoint struct {
+	Path    string    // 宿主机路径
+	Name    string    // 文件或目录名
+	IsDir   bool      // 是否为目录（如 HuggingFace 的 checkpoint-500/）
+	Size    int64     // 总大小（字节）
+	ModTime time.Time // 最后修改时间（目录取其中最新的文件）
+}
+
+// ListCheckpoints 列出任务输出目录中的检查点
+//
+// 包括 <output>/checkpoints 下的检查点文件和子目录，以及 <output> 下名称以
+// checkpoint 开头的文件或目录。
+func ListCheckpoints(outputPath string) []*LocalCheckpoint {
+	if outputPath == "" {
+		return nil
+	}
+
+	var result []*LocalCheckpoint
+
+	checkpointDir := filepath.Join(outputPath, "checkpoints")
+	if entries, err := os.ReadDir(checkpointDir); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() && !isCheckpointFile(entry.Name()) {
+				continue
+			}
+			if ckpt := StatCheckpoint(filepath.Join(checkpointDir, entry.Name())); ckpt != nil {
+				result = append(result, ckpt)
+			}
+		}
+	}
+
+	if entries, err := os.ReadDir(outputPath); err == nil {
+		for _, entry := range entries {
+			if !strings.HasPrefix(strings.ToLower(entry.Name()), "checkpoint") || entry.Name() == "checkpoints" {
+				continue
+			}
+			if ckpt := StatCheckpoint(filepath.Join(outputPath, entry.Name())); ckpt != nil {
+				result = append(result, ckpt)
+			}
+		}
+	}
+
+	return result
+}
+
+// FindLatestCheckpoint 在任务输出目录中查找最近写入的检查点，不存在时返回空字符串
+func FindLatestCheckpoint(outputPath string) string {
+	var latest *LocalCheckpoint
+	for _, ckpt := range ListCheckpoints(outputPath) {
+		if latest == nil || ckpt.ModTime.After(latest.ModTime) {
+			latest = ckpt
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Path
+}
+
+// StatCheckpoint 获取检查点的大小和修改时间，路径不存在时返回 nil
+func StatCheckpoint(path string) *LocalCheckpoint {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	ckpt := &LocalCheckpoint{
+		Path:    path,
+		Name:    filepath.Base(path),
+		IsDir:   info.IsDir(),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if !info.IsDir() {
+		return ckpt
+	}
+
+	ckpt.Size = 0
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if fi, err := d.Info(); err == nil {
+			ckpt.Size += fi.Size()
+			if fi.ModTime().After(ckpt.ModTime) {
+				ckpt.ModTime = fi.ModTime()
+			}
+		}
+		return nil
+	})
+	return ckpt
+}
+
+// isCheckpointFile 判断文件名是否为检查点文件
+func isCheckpointFile(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range checkpointSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainerOutputPath 将输出目录下的宿主机路径转换为容器内 /output 下的路径，不在输出目录下时返回空字符串
+func ContainerOutputPath(outputPath, hostPath string) string {
+	rel, err := filepath.Rel(outputPath, hostPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Join("/output", rel))
+}
+
+// HostOutputPath 将容器内 /output 下的路径转换为宿主机路径，不在 /output 下时返回空字符串
+func HostOutputPath(outputPath, containerPath string) string {
+	cleaned := filepath.Clean(containerPath)
+	if outputPath == "" || (cleaned != "/output" && !strings.HasPrefix(cleaned, "/output/")) {
+		return ""
+	}
+	return filepath.Join(outputPath, strings.TrimPrefix(cleaned, "/output"))
+}
@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// NodeContainer 任务的一个节点容器，单机任务只有 rank 0
+type NodeContainer struct {
+	Rank          int
+	ContainerID   string
+	ContainerName string
+	PID           int
+}
+
+// nodeContainerName 生成任务当前尝试中某个节点的容器名
+func nodeContainerName(job *domain.TrainingJob, rank int) string {
+	name := containerNameFor(job)
+	if rank > 0 {
+		name = fmt.Sprintf("%s-r%d", name, rank)
+	}
+	return name
+}
+
+// networkNameFor 生成分布式任务当前尝试的专用网络名
+func networkNameFor(job *domain.TrainingJob) string {
+	return fmt.Sprintf("aitip-ddp-%s-%d", job.ID.String()[:8], job.Attempt)
+}
+
+// distributedEnv 生成节点的 torch.distributed 环境变量，masterAddr 为 rank 0 节点的地址
+//
+// RANK/LOCAL_RANK 为该节点第一个进程的值，每个节点运行多个进程时应使用 torchrun
+// 启动（NNODES、NPROC_PER_NODE、NODE_RANK 可直接传给 torchrun），由其为各进程设置。
+func distributedEnv(job *domain.TrainingJob, rank int, masterAddr string) []string {
+	cfg := *job.Distributed
+	cfg.ApplyDefaults(job.GPUCount)
+
+	return []string{
+		fmt.Sprintf("MASTER_ADDR=%s", masterAddr),
+		fmt.Sprintf("MASTER_PORT=%d", cfg.MasterPort),
+		fmt.Sprintf("WORLD_SIZE=%d", cfg.WorldSize()),
+		fmt.Sprintf("RANK=%d", rank*cfg.ProcsPerNode),
+		"LOCAL_RANK=0",
+		fmt.Sprintf("NODE_RANK=%d", rank),
+		fmt.Sprintf("NNODES=%d", cfg.NumNodes),
+		fmt.Sprintf("NPROC_PER_NODE=%d", cfg.ProcsPerNode),
+		fmt.Sprintf("DIST_BACKEND=%s", cfg.Backend),
+	}
+}
+
+// visibleDevices 容器内可见的 GPU 序号（设备挂载后从 0 开始编号）
+func visibleDevices(gpuCount int) string {
+	ids := make([]string, gpuCount)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	return strings.Join(ids, ",")
+}
+
+// createNetwork 创建分布式任务专用的 bridge 网络，节点之间通过容器名互相访问
+func (e *DockerExecutor) createNetwork(ctx context.Context, job *domain.TrainingJob, name string) error {
+	_, err := e.client.NetworkCreate(ctx, name, types.NetworkCreate{
+		Driver:         "bridge",
+		CheckDuplicate: true,
+		Labels: map[string]string{
+			"aitip.job.id":      job.ID.String(),
+			"aitip.job.attempt": strconv.Itoa(job.Attempt),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create network %s: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveNetwork 删除分布式任务的专用网络
+func (e *DockerExecutor) RemoveNetwork(ctx context.Context, name string) error {
+	return e.client.NetworkRemove(ctx, name)
+}
+
+// removeNetwork 删除网络，失败时只记录日志
+func (e *DockerExecutor) removeNetwork(ctx context.Context, jobID uuid.UUID, name string) {
+	if name == "" {
+		return
+	}
+	if err := e.RemoveNetwork(ctx, name); err != nil {
+		logger.Warn("Failed to remove job network", zap.String("job_id", jobID.String()), zap.String("network", name), zap.Error(err))
+	}
+}
+
+// nodesFromContainers 按 aitip.job.rank 标签还原任务的节点容器
+func nodesFromContainers(containers []*domain.ContainerInfo) []*NodeContainer {
+	nodes := make([]*NodeContainer, 0, len(containers))
+	for _, c := range containers {
+		rank, _ := strconv.Atoi(c.Labels["aitip.job.rank"])
+		nodes = append(nodes, &NodeContainer{
+			Rank:          rank,
+			ContainerID:   c.ContainerID,
+			ContainerName: c.ContainerName,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Rank < nodes[j].Rank })
+	return nodes
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+func TestDistributedEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		distributed domain.DistributedConfig
+		gpuCount    int
+		rank        int
+		want        []string
+	}{
+		{
+			name:        "master node",
+			distributed: domain.DistributedConfig{NumNodes: 2},
+			gpuCount:    4,
+			rank:        0,
+			want: []string{
+				"MASTER_ADDR=master", "MASTER_PORT=29500", "WORLD_SIZE=8", "RANK=0", "LOCAL_RANK=0",
+				"NODE_RANK=0", "NNODES=2", "NPROC_PER_NODE=4", "DIST_BACKEND=nccl",
+			},
+		},
+		{
+			name:        "worker rank starts after earlier nodes' processes",
+			distributed: domain.DistributedConfig{NumNodes: 3, MasterPort: 23456},
+			gpuCount:    2,
+			rank:        2,
+			want: []string{
+				"MASTER_ADDR=master", "MASTER_PORT=23456", "WORLD_SIZE=6", "RANK=4", "LOCAL_RANK=0",
+				"NODE_RANK=2", "NNODES=3", "NPROC_PER_NODE=2", "DIST_BACKEND=nccl",
+			},
+		},
+		{
+			name:        "cpu nodes",
+			distributed: domain.DistributedConfig{NumNodes: 2},
+			rank:        1,
+			want: []string{
+				"MASTER_ADDR=master", "MASTER_PORT=29500", "WORLD_SIZE=2", "RANK=1", "LOCAL_RANK=0",
+				"NODE_RANK=1", "NNODES=2", "NPROC_PER_NODE=1", "DIST_BACKEND=gloo",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &domain.TrainingJob{GPUCount: tt.gpuCount, Distributed: &tt.distributed}
+			if got := distributedEnv(job, tt.rank, "master"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("distributedEnv() = %v, want %v", got, tt.want)
+			}
+			if tt.distributed.Backend != "" || tt.distributed.ProcsPerNode != 0 {
+				t.Errorf("distributedEnv() changed the job's config: %+v", tt.distributed)
+			}
+		})
+	}
+}
+
+func TestNodeContainerName(t *testing.T) {
+	job := &domain.TrainingJob{ID: uuid.MustParse("0123abcd-0000-0000-0000-000000000000")}
+
+	tests := []struct {
+		name    string
+		attempt int
+		rank    int
+		want    string
+	}{
+		{"first attempt master", 1, 0, "aitip-train-0123abcd"},
+		{"first attempt worker", 1, 2, "aitip-train-0123abcd-r2"},
+		{"retried master", 3, 0, "aitip-train-0123abcd-3"},
+		{"retried worker", 3, 1, "aitip-train-0123abcd-3-r1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job.Attempt = tt.attempt
+			if got := nodeContainerName(job, tt.rank); got != tt.want {
+				t.Errorf("nodeContainerName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodesFromContainers(t *testing.T) {
+	containers := []*domain.ContainerInfo{
+		{ContainerID: "c2", Labels: map[string]string{"aitip.job.rank": "2"}},
+		{ContainerID: "c0"},
+		{ContainerID: "c1", Labels: map[string]string{"aitip.job.rank": "1"}},
+	}
+
+	var got []string
+	for _, node := range nodesFromContainers(containers) {
+		got = append(got, node.ContainerID)
+	}
+	if want := []string{"c0", "c1", "c2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nodes = %v, want %v", got, want)
+	}
+}
@@ -1,13 +1,17 @@
 package executor
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -16,11 +20,13 @@ import (
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
 )
@@ -32,6 +38,23 @@ type Executor interface {
 	GetStatus(ctx context.Context, jobID uuid.UUID) (domain.JobStatus, error)
 	IsRunning(ctx context.Context, jobID uuid.UUID) bool
 	GetContainerStats(ctx context.Context, jobID uuid.UUID) (*ContainerStats, error)
+	SetExitHandler(handler ExitHandler)
+}
+
+// Reconcilable 支持服务重启后恢复运行中任务的执行器
+type Reconcilable interface {
+	IsTracked(jobID uuid.UUID) bool
+	ListManagedContainers(ctx context.Context) ([]*domain.ContainerInfo, error)
+	Reattach(ctx context.Context, job *domain.TrainingJob, containers []*domain.ContainerInfo) error
+	RemoveContainer(ctx context.Context, containerID string) error
+	RemoveNetwork(ctx context.Context, name string) error
+}
+
+// Pausable 支持暂停任务的执行器
+type Pausable interface {
+	// Pause 向任务发送 signal 让训练脚本保存检查点，等待 grace 后强制停止仍在运行的容器，
+	// 全部容器退出后返回并释放资源；暂停导致的退出不会触发 ExitHandler
+	Pause(ctx context.Context, jobID uuid.UUID, signal string, grace time.Duration) error
 }
 
 // ContainerStats 容器统计信息
@@ -44,6 +67,7 @@ type ContainerStats struct {
 	GPUMemoryUsed  uint64    `json:"gpu_memory_used"`
 	GPUMemoryTotal uint64    `json:"gpu_memory_total"`
 	GPUtilization  float64   `json:"gpu_utilization"`
+	GPUs           []GPUInfo `json:"gpus,omitempty"`
 	StartedAt      time.Time `json:"started_at"`
 }
 
@@ -57,27 +81,91 @@ type DockerExecutor struct {
 	network      string
 	volumeBase   string
 	gpuDetector  *GPUDetector
+	gpuAllocator *GPUAllocator
 	errorHandler *ErrorHandler
 	metricParser *MetricParser
-	maxRetries   int
-	retryDelay   time.Duration
+	exitHandler  ExitHandler
+
+	checkpointHandler CheckpointHandler
+	metricsHandler    MetricsHandler
+	metricsEndpoint   *MetricsEndpoint
+
+	metricsBroadcaster MetricsBroadcaster
+	secrets            secrets.Manager
 }
 
+// CheckpointHandler 训练脚本通过日志声明检查点时的回调
+type CheckpointHandler func(jobID uuid.UUID, announcement *domain.CheckpointAnnouncement)
+
+// checkpointLogPrefix 检查点声明日志行前缀，后跟 JSON，例如：
+// AITIP_CHECKPOINT {"path": "/output/checkpoints/epoch-3.pt", "step": 1200, "epoch": 3, "metrics": {"val_loss": 0.42}}
+const checkpointLogPrefix = "AITIP_CHECKPOINT "
+
+// ExitHandler 容器退出回调，用于将最终状态写回数据库
+type ExitHandler func(event *ExitEvent)
+
+// ExitEvent 容器退出事件
+type ExitEvent struct {
+	JobID       uuid.UUID
+	Attempt     int
+	Status      domain.JobStatus
+	Message     string
+	ExitCode    *int
+	Retryable   bool   // 按任务重试策略判断失败可重试
+	RetryReason string // 命中的重试条件
+}
+
+// logTailSize 为判断重试条件保留的最后日志行数
+const logTailSize = 50
+
 // JobProcess 任务进程信息
 type JobProcess struct {
 	JobID         uuid.UUID
-	ContainerID   string
-	ContainerName string
+	Attempt       int
+	ContainerID   string           // rank 0 节点的容器 ID
+	ContainerName string           // rank 0 节点的容器名
+	Nodes         []*NodeContainer // 全部节点的容器，单机任务只有一个
+	Network       string           // 分布式任务的专用网络，单机任务为空
 	CancelFunc    context.CancelFunc
 	StartedAt     time.Time
 	RetryCount    int
 	LastError     error
+
+	tailMu   sync.Mutex
+	logTail  []string
+	logsDone chan struct{}     // 所有节点的日志收集结束时关闭
+	redactor *secrets.Redactor // 从日志中去除注入的密钥值
+
+	pausing atomic.Bool // 正在暂停，容器退出由 Pause 处理
+}
+
+// distributed 是否为多节点任务
+func (p *JobProcess) distributed() bool {
+	return p.Network != ""
+}
+
+// appendTail 记录最后的日志行
+func (p *JobProcess) appendTail(line string) {
+	p.tailMu.Lock()
+	defer p.tailMu.Unlock()
+	p.logTail = append(p.logTail, line)
+	if len(p.logTail) > logTailSize {
+		p.logTail = p.logTail[len(p.logTail)-logTailSize:]
+	}
+}
+
+// tail 获取最后的日志行
+func (p *JobProcess) tail() []string {
+	p.tailMu.Lock()
+	defer p.tailMu.Unlock()
+	return append([]string(nil), p.logTail...)
 }
 
 // MetricsRepository 指标仓库接口
 type MetricsRepository interface {
 	SaveMetrics(ctx context.Context, jobID uuid.UUID, metrics *TrainingMetrics) error
 	GetMetrics(ctx context.Context, jobID uuid.UUID, metricType string) ([]*TrainingMetrics, error)
+	GetSeries(ctx context.Context, jobID uuid.UUID, metricType string) ([]*domain.MetricSeries, error)
 }
 
 // TrainingMetrics 训练指标
@@ -106,10 +194,21 @@ func NewDockerExecutor(logRepo repository.LogRepository, network, volumeBase str
 		return nil, fmt.Errorf("failed to connect to docker daemon: %w", err)
 	}
 
-	gpuDetector := NewGPUDetector(cli)
+	gpuSource := GPUSource(NvidiaSMISource)
+	gpuDetector := NewGPUDetector(cli, gpuSource)
 	errorHandler := NewErrorHandler()
 	metricParser := NewMetricParser()
 
+	// 有 GPU 时为每个任务分配具体设备，避免多个任务共用同一块 GPU
+	var gpuAllocator *GPUAllocator
+	if gpuDetector.IsAvailable() {
+		gpuAllocator = NewGPUAllocator(gpuSource)
+		if err := gpuAllocator.Refresh(ctx); err != nil {
+			logger.Warn("Failed to load GPU inventory, GPU assignment disabled", zap.Error(err))
+			gpuAllocator = nil
+		}
+	}
+
 	return &DockerExecutor{
 		client:       cli,
 		logRepo:      logRepo,
@@ -117,10 +216,9 @@ func NewDockerExecutor(logRepo repository.LogRepository, network, volumeBase str
 		network:      network,
 		volumeBase:   volumeBase,
 		gpuDetector:  gpuDetector,
+		gpuAllocator: gpuAllocator,
 		errorHandler: errorHandler,
 		metricParser: metricParser,
-		maxRetries:   3,
-		retryDelay:   5 * time.Second,
 	}, nil
 }
 
@@ -133,31 +231,129 @@ func (e *DockerExecutor) Start(ctx context.Context, job *domain.TrainingJob) err
 		return fmt.Errorf("job %s is already running", job.ID)
 	}
 
-	// 拉取镜像
+	// 拉取镜像，失败时只允许使用本地已有的同一摘要镜像，tag 可能已指向其他内容
 	if err := e.pullImage(ctx, job.Image); err != nil {
-		logger.Warn("Failed to pull image, will try to use local", zap.String("image", job.Image), zap.Error(err))
+		if !e.hasLocalDigest(ctx, job.Image) {
+			return fmt.Errorf("failed to pull image %s: %w", job.Image, err)
+		}
+		logger.Warn("Failed to pull image, using the local copy of the pinned digest", zap.String("image", job.Image), zap.Error(err))
+		e.appendSystemLog(job, "WARN", fmt.Sprintf("Failed to pull image %s, using the local copy of the pinned digest: %v", job.Image, err))
 	}
 
-	// 构建容器配置
-	config, hostConfig, err := e.buildContainerConfig(job)
+	// 解密引用的密钥，只注入容器，不写入任务记录
+	resolved, err := injectSecrets(ctx, e.secrets, job)
 	if err != nil {
-		return fmt.Errorf("failed to build container config: %w", err)
+		return err
 	}
 
-	// 创建容器
-	containerName := fmt.Sprintf("aitip-train-%s", job.ID.String()[:8])
+	// 为各节点分配 GPU，调度器派发时已预留的设备直接复用
+	var gpus [][]GPUInfo
+	if job.GPUCount > 0 && e.gpuAllocator != nil {
+		gpus, err = e.gpuAllocator.Allocate(job.ID, job.Distributed.Nodes(), job.GPUCount, job.GPUType)
+		if err != nil {
+			return fmt.Errorf("failed to allocate GPUs: %w", err)
+		}
+	}
+
+	// 分布式任务的节点通过专用网络互相访问
+	var networkName string
+	if job.Distributed.IsDistributed() {
+		networkName = networkNameFor(job)
+		if err := e.createNetwork(ctx, job, networkName); err != nil {
+			e.releaseGPUs(job.ID)
+			return err
+		}
+	}
+
+	process := &JobProcess{
+		JobID:      job.ID,
+		Attempt:    job.Attempt,
+		Network:    networkName,
+		StartedAt:  time.Now(),
+		RetryCount: job.Attempt - 1,
+		logsDone:   make(chan struct{}),
+		redactor:   secrets.NewRedactor(resolved),
+	}
+
+	// 创建并启动所有节点，每次尝试使用新的容器；任一节点失败时清理整个任务
+	for rank := 0; rank < job.Distributed.Nodes(); rank++ {
+		var nodeGPUs []GPUInfo
+		if rank < len(gpus) {
+			nodeGPUs = gpus[rank]
+		}
+		node, err := e.startNode(ctx, job, rank, networkName, nodeGPUs, resolved)
+		if err != nil {
+			e.removeNodes(ctx, process)
+			e.removeNetwork(ctx, job.ID, networkName)
+			e.releaseGPUs(job.ID)
+			return err
+		}
+		process.Nodes = append(process.Nodes, node)
+	}
+
+	master := process.Nodes[0]
+	process.ContainerID = master.ContainerID
+	process.ContainerName = master.ContainerName
+
+	// 创建任务上下文
+	execCtx, cancel := context.WithCancel(context.Background())
+	process.CancelFunc = cancel
+	e.jobs[job.ID] = process
+
+	job.ContainerID = master.ContainerID
+	job.ContainerName = master.ContainerName
+	job.PID = master.PID
+
+	// 启动日志收集
+	go e.collectNodeLogs(execCtx, process, nil)
+
+	// 启动资源用量采集
+	if e.metricsBroadcaster != nil {
+		go e.collectMetrics(execCtx, job.ID, master.ContainerID)
+	}
+
+	// 启动容器监控
+	go e.monitorNodes(execCtx, job, process)
+
+	return nil
+}
+
+// startNode 创建并启动任务的一个节点容器，gpus 为分配给该节点的 GPU，resolved 为需要注入的密钥
+func (e *DockerExecutor) startNode(ctx context.Context, job *domain.TrainingJob, rank int, networkName string, gpus []GPUInfo, resolved []secrets.Resolved) (*NodeContainer, error) {
+	config, hostConfig, err := e.buildContainerConfig(job, rank, networkName, gpus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build container config: %w", err)
+	}
+	config.Env = append(config.Env, secrets.Env(resolved)...)
+
+	containerName := nodeContainerName(job, rank)
 	resp, err := e.client.ContainerCreate(ctx, config, hostConfig, &network.NetworkingConfig{}, nil, containerName)
 	if err != nil {
-		return fmt.Errorf("failed to create container: %w", err)
+		return nil, fmt.Errorf("failed to create container: %w", err)
 	}
 
 	containerID := resp.ID
-	logger.Info("Container created", zap.String("job_id", job.ID.String()), zap.String("container_id", containerID))
+	logger.Info("Container created", zap.String("job_id", job.ID.String()), zap.Int("rank", rank), zap.String("container_id", containerID))
+
+	// 以文件形式挂载的密钥在启动前写入容器
+	if files := secrets.Files(resolved); len(files) > 0 {
+		if err := e.copySecretFiles(ctx, containerID, files); err != nil {
+			e.client.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true})
+			return nil, err
+		}
+	}
+
+	// 分布式节点同时接入服务网络，以便访问数据等服务
+	if networkName != "" && e.network != "" {
+		if err := e.client.NetworkConnect(ctx, e.network, containerID, nil); err != nil {
+			logger.Warn("Failed to connect node to service network", zap.String("job_id", job.ID.String()), zap.Int("rank", rank), zap.Error(err))
+		}
+	}
 
 	// 启动容器
 	if err := e.client.ContainerStart(ctx, containerID, types.ContainerStartOptions{}); err != nil {
 		e.client.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true})
-		return fmt.Errorf("failed to start container: %w", err)
+		return nil, fmt.Errorf("failed to start container: %w", err)
 	}
 
 	// 获取容器信息
@@ -166,31 +362,15 @@ func (e *DockerExecutor) Start(ctx context.Context, job *domain.TrainingJob) err
 		logger.Warn("Failed to inspect container", zap.Error(err))
 	}
 
-	// 创建任务上下文
-	execCtx, cancel := context.WithCancel(context.Background())
-
-	// 保存进程信息
-	process := &JobProcess{
-		JobID:         job.ID,
+	node := &NodeContainer{
+		Rank:          rank,
 		ContainerID:   containerID,
 		ContainerName: containerName,
-		CancelFunc:    cancel,
-		StartedAt:     time.Now(),
 	}
-	e.jobs[job.ID] = process
-
-	// 启动日志收集
-	go e.collectLogs(execCtx, job.ID, containerID)
-
-	// 启动指标收集
-	if e.metricsRepo != nil {
-		go e.collectMetrics(execCtx, job.ID, containerID)
+	if info.State != nil {
+		node.PID = info.State.Pid
 	}
-
-	// 启动容器监控
-	go e.monitorContainer(execCtx, job, containerID, info.State.Pid)
-
-	return nil
+	return node, nil
 }
 
 // Stop 停止训练任务
@@ -207,30 +387,107 @@ func (e *DockerExecutor) Stop(ctx context.Context, jobID uuid.UUID) error {
 		process.CancelFunc()
 	}
 
-	stopTimeout := 30
-	if err := e.client.ContainerStop(ctx, process.ContainerID, container.StopTimeout(&stopTimeout)); err != nil {
-		logger.Warn("Failed to stop container gracefully, forcing", zap.String("container_id", process.ContainerID), zap.Error(err))
-		if err := e.client.ContainerKill(ctx, process.ContainerID, "SIGKILL"); err != nil {
-			logger.Error("Failed to kill container", zap.Error(err))
-		}
-	}
-
 	done := make(chan struct{})
 	go func() {
-		e.client.ContainerWait(ctx, process.ContainerID, container.WaitConditionNotRunning)
+		e.stopNodes(ctx, process, nil)
 		close(done)
 	}()
 
 	select {
 	case <-done:
-		e.cleanupJob(jobID, process.ContainerID)
+		e.cleanupJob(process)
 		return nil
 	case <-time.After(35 * time.Second):
-		e.cleanupJob(jobID, process.ContainerID)
+		e.cleanupJob(process)
 		return fmt.Errorf("timeout waiting for job %s to stop", jobID)
 	}
 }
 
+// Pause 暂停任务：向所有节点发送 signal，等待 grace 后强制停止仍在运行的节点
+func (e *DockerExecutor) Pause(ctx context.Context, jobID uuid.UUID, signal string, grace time.Duration) error {
+	e.mu.RLock()
+	process, exists := e.jobs[jobID]
+	e.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("job %s is not running", jobID)
+	}
+	process.pausing.Store(true)
+
+	for _, node := range process.Nodes {
+		if err := e.client.ContainerKill(ctx, node.ContainerID, signal); err != nil {
+			logger.Warn("Failed to signal container", zap.String("container_id", node.ContainerID), zap.String("signal", signal), zap.Error(err))
+		}
+	}
+
+	// 等待训练脚本保存检查点后自行退出
+	waitCtx, cancel := context.WithTimeout(ctx, grace)
+	var wg sync.WaitGroup
+	for _, node := range process.Nodes {
+		wg.Add(1)
+		go func(node *NodeContainer) {
+			defer wg.Done()
+			statusCh, errCh := e.client.ContainerWait(waitCtx, node.ContainerID, container.WaitConditionNotRunning)
+			select {
+			case <-statusCh:
+			case <-errCh:
+			}
+		}(node)
+	}
+	wg.Wait()
+	timedOut := waitCtx.Err() != nil && ctx.Err() == nil
+	cancel()
+
+	if timedOut {
+		logger.Warn("Job did not exit within pause grace period, stopping", zap.String("job_id", jobID.String()), zap.Duration("grace", grace))
+		e.stopNodes(ctx, process, nil)
+	}
+
+	// 保留退出前输出的日志
+	select {
+	case <-process.logsDone:
+	case <-time.After(3 * time.Second):
+	}
+
+	if process.CancelFunc != nil {
+		process.CancelFunc()
+	}
+	e.cleanupJob(process)
+	return nil
+}
+
+// stopNodes 并行停止任务的节点容器（跳过 except），先优雅停止，失败时强制终止
+func (e *DockerExecutor) stopNodes(ctx context.Context, process *JobProcess, except *NodeContainer) {
+	var wg sync.WaitGroup
+	for _, node := range process.Nodes {
+		if node == except {
+			continue
+		}
+
+		wg.Add(1)
+		go func(node *NodeContainer) {
+			defer wg.Done()
+
+			stopTimeout := 30
+			if err := e.client.ContainerStop(ctx, node.ContainerID, container.StopTimeout(&stopTimeout)); err != nil {
+				logger.Warn("Failed to stop container gracefully, forcing", zap.String("container_id", node.ContainerID), zap.Error(err))
+				if err := e.client.ContainerKill(ctx, node.ContainerID, "SIGKILL"); err != nil {
+					logger.Error("Failed to kill container", zap.Error(err))
+				}
+			}
+			e.client.ContainerWait(ctx, node.ContainerID, container.WaitConditionNotRunning)
+		}(node)
+	}
+	wg.Wait()
+}
+
+// removeNodes 强制删除任务已创建的节点容器
+func (e *DockerExecutor) removeNodes(ctx context.Context, process *JobProcess) {
+	for _, node := range process.Nodes {
+		e.client.ContainerRemove(ctx, node.ContainerID, types.ContainerRemoveOptions{Force: true})
+	}
+}
+
 // GetStatus 获取任务状态
 func (e *DockerExecutor) GetStatus(ctx context.Context, jobID uuid.UUID) (domain.JobStatus, error) {
 	e.mu.RLock()
@@ -283,20 +540,91 @@ func (e *DockerExecutor) GetContainerStats(ctx context.Context, jobID uuid.UUID)
 		return nil, fmt.Errorf("job %s not found", jobID)
 	}
 
-	info, err := e.client.ContainerInspect(ctx, process.ContainerID)
+	stats, err := e.resourceStats(ctx, process.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+	stats.StartedAt = process.StartedAt
+
+	return stats, nil
+}
+
+// resourceStats 读取容器当前的 CPU、内存和 GPU 用量，容器未运行时只返回状态
+func (e *DockerExecutor) resourceStats(ctx context.Context, containerID string) (*ContainerStats, error) {
+	info, err := e.client.ContainerInspect(ctx, containerID)
 	if err != nil {
 		return nil, err
 	}
 
 	stats := &ContainerStats{
-		ContainerID: process.ContainerID,
+		ContainerID: containerID,
 		Status:      info.State.Status,
-		StartedAt:   process.StartedAt,
+	}
+	if !info.State.Running {
+		return stats, nil
+	}
+
+	resp, err := e.client.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var raw types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode container stats: %w", err)
+	}
+
+	stats.CPUUsage = cpuPercent(&raw)
+	stats.MemoryUsage = raw.MemoryStats.Usage
+	// 与 docker stats 一致，不计入可回收的页缓存
+	if inactive, ok := raw.MemoryStats.Stats["inactive_file"]; ok && inactive < stats.MemoryUsage {
+		stats.MemoryUsage -= inactive
+	} else if cache, ok := raw.MemoryStats.Stats["cache"]; ok && cache < stats.MemoryUsage {
+		stats.MemoryUsage -= cache
+	}
+	stats.MemoryLimit = raw.MemoryStats.Limit
+
+	if info.HostConfig != nil {
+		stats.GPUs = e.gpuDetector.GetGPUStats(deviceIndices(info.HostConfig.DeviceRequests))
+	}
+	for _, gpu := range stats.GPUs {
+		stats.GPUMemoryUsed += gpu.MemoryUsed
+		stats.GPUMemoryTotal += gpu.MemoryTotal
+		stats.GPUtilization += gpu.Utilization / float64(len(stats.GPUs))
 	}
 
 	return stats, nil
 }
 
+// cpuPercent 根据两次采样计算 CPU 使用率（100% 为一个核）
+func cpuPercent(stats *types.StatsJSON) float64 {
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// deviceIndices 获取设备请求中指定的 GPU 编号
+func deviceIndices(requests []container.DeviceRequest) []int {
+	var indices []int
+	for _, request := range requests {
+		for _, id := range request.DeviceIDs {
+			if index, err := strconv.Atoi(id); err == nil {
+				indices = append(indices, index)
+			}
+		}
+	}
+	return indices
+}
+
 // pullImage 拉取 Docker 镜像
 func (e *DockerExecutor) pullImage(ctx context.Context, image string) error {
 	logger.Info("Pulling image", zap.String("image", image))
@@ -316,8 +644,35 @@ func (e *DockerExecutor) pullImage(ctx context.Context, image string) error {
 	return nil
 }
 
-// buildContainerConfig 构建容器配置
-func (e *DockerExecutor) buildContainerConfig(job *domain.TrainingJob) (*container.Config, *container.HostConfig, error) {
+// hasLocalDigest 检查以摘要引用的镜像是否已在本地，以 tag 引用的镜像始终返回 false
+func (e *DockerExecutor) hasLocalDigest(ctx context.Context, image string) bool {
+	digest := pinnedDigest(image)
+	if digest == "" {
+		return false
+	}
+	inspect, _, err := e.client.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		return false
+	}
+	for _, repoDigest := range inspect.RepoDigests {
+		if strings.HasSuffix(repoDigest, "@"+digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// pinnedDigest 返回镜像引用中的 sha256 摘要，未以摘要引用时返回空
+func pinnedDigest(image string) string {
+	_, digest, ok := strings.Cut(image, "@")
+	if !ok || !strings.HasPrefix(digest, "sha256:") || len(digest) != len("sha256:")+64 {
+		return ""
+	}
+	return digest
+}
+
+// jobEnv 构建训练容器的基础环境变量（KEY=VALUE 形式）
+func jobEnv(job *domain.TrainingJob) []string {
 	env := []string{
 		fmt.Sprintf("JOB_ID=%s", job.ID.String()),
 		fmt.Sprintf("PROJECT_ID=%s", job.ProjectID.String()),
@@ -330,12 +685,36 @@ func (e *DockerExecutor) buildContainerConfig(job *domain.TrainingJob) (*contain
 		env = append(env, fmt.Sprintf("%s=%s", key, value))
 	}
 
+	env = append(env, fmt.Sprintf("AITIP_ATTEMPT=%d", job.Attempt))
+	if job.DatasetID != nil {
+		env = append(env, fmt.Sprintf("DATASET_ID=%s", job.DatasetID.String()))
+	}
+	if job.DatasetVersion != "" {
+		env = append(env, fmt.Sprintf("DATASET_VERSION=%s", job.DatasetVersion))
+	}
+	if job.DatasetURL != "" {
+		env = append(env, fmt.Sprintf("DATASET_URL=%s", job.DatasetURL))
+	}
+	if job.RunID != nil {
+		env = append(env, fmt.Sprintf("AITIP_RUN_ID=%s", job.RunID.String()))
+	}
+
 	if job.Hyperparameters != nil {
 		for key, value := range job.Hyperparameters {
 			env = append(env, fmt.Sprintf("HP_%s=%v", strings.ToUpper(key), value))
 		}
 	}
 
+	return env
+}
+
+// buildContainerConfig 构建节点容器配置，networkName 非空时为分布式任务的节点，gpus 为分配给节点的 GPU
+func (e *DockerExecutor) buildContainerConfig(job *domain.TrainingJob, rank int, networkName string, gpus []GPUInfo) (*container.Config, *container.HostConfig, error) {
+	env := append(jobEnv(job), metricsEnv(job, e.metricsEndpoint)...)
+	if networkName != "" {
+		env = append(env, distributedEnv(job, rank, nodeContainerName(job, 0))...)
+	}
+
 	config := &container.Config{
 		Image:        job.Image,
 		Env:          env,
@@ -347,22 +726,35 @@ func (e *DockerExecutor) buildContainerConfig(job *domain.TrainingJob) (*contain
 			"aitip.job.name":   job.Name,
 			"aitip.project.id": job.ProjectID.String(),
 			"aitip.framework":  string(job.Framework),
+			"aitip.job.attempt": strconv.Itoa(job.Attempt),
+			"aitip.job.rank":    strconv.Itoa(rank),
 		},
 	}
+	if networkName != "" {
+		config.Labels["aitip.job.network"] = networkName
+	}
 
 	if len(job.Command) > 0 {
 		config.Cmd = job.Command
 	}
 
+	// 不使用 AutoRemove：服务在容器退出后、读取退出码前中断时，协调器仍能从已退出的容器中读取退出码
 	hostConfig := &container.HostConfig{
-		AutoRemove: true,
-		Mounts:     []mount.Mount{},
+		Mounts: []mount.Mount{},
 	}
 
-	if job.DatasetPath != "" {
+	// 对象存储中的数据集挂载本地缓存，或由训练脚本通过 DATASET_URL 下载
+	datasetSource := job.DatasetPath
+	if _, _, ok := job.StoredDataset(); ok {
+		datasetSource = job.DatasetMount
+		if datasetSource == "" && job.DatasetURL == "" {
+			return nil, nil, fmt.Errorf("dataset %s has not been downloaded from object storage", job.DatasetPath)
+		}
+	}
+	if datasetSource != "" {
 		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
 			Type:     mount.TypeBind,
-			Source:   job.DatasetPath,
+			Source:   datasetSource,
 			Target:   "/data",
 			ReadOnly: true,
 		})
@@ -376,6 +768,21 @@ func (e *DockerExecutor) buildContainerConfig(job *domain.TrainingJob) (*contain
 		})
 	}
 
+	// 恢复训练的检查点：位于输出目录中时直接使用 /output 下的路径，否则只读挂载到 /checkpoint
+	if job.ResumeFrom != "" {
+		resumePath := ContainerOutputPath(job.OutputPath, job.ResumeFrom)
+		if resumePath == "" {
+			resumePath = "/checkpoint/" + filepath.Base(job.ResumeFrom)
+			hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   job.ResumeFrom,
+				Target:   resumePath,
+				ReadOnly: true,
+			})
+		}
+		config.Env = append(config.Env, fmt.Sprintf("RESUME_FROM_CHECKPOINT=%s", resumePath))
+	}
+
 	if e.volumeBase != "" {
 		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
 			Type:   mount.TypeBind,
@@ -395,16 +802,24 @@ func (e *DockerExecutor) buildContainerConfig(job *domain.TrainingJob) (*contain
 	hostConfig.ShmSize = 2 * 1024 * 1024 * 1024
 
 	if job.GPUCount > 0 {
-		if e.gpuDetector.IsAvailable() {
-			hostConfig.DeviceRequests = e.gpuDetector.GetDeviceRequests(job.GPUCount)
-			config.Env = append(config.Env, "NVIDIA_VISIBLE_DEVICES=all")
-			config.Env = append(config.Env, "CUDA_VISIBLE_DEVICES=0")
+		if len(gpus) > 0 {
+			// 只挂载分配给节点的 GPU，标签记录设备编号以便重启后恢复占用
+			hostConfig.DeviceRequests = gpuDeviceRequests(gpus)
+			config.Env = append(config.Env, fmt.Sprintf("NVIDIA_VISIBLE_DEVICES=%s", gpuVisibleDevices(gpus)))
+			config.Env = append(config.Env, fmt.Sprintf("CUDA_VISIBLE_DEVICES=%s", visibleDevices(len(gpus))))
+			config.Labels["aitip.gpu.devices"] = strings.Join(gpuIndexList(gpus), ",")
+		} else if e.gpuDetector.IsAvailable() {
+			// 没有设备清单时按节点序号划分连续的 GPU
+			hostConfig.DeviceRequests = e.gpuDetector.GetDeviceRequestsFrom(rank*job.GPUCount, job.GPUCount)
+			config.Env = append(config.Env, fmt.Sprintf("CUDA_VISIBLE_DEVICES=%s", visibleDevices(job.GPUCount)))
 		} else {
 			logger.Warn("GPU requested but not available", zap.String("job_id", job.ID.String()), zap.Int("gpu_count", job.GPUCount))
 		}
 	}
 
-	if e.network != "" {
+	if networkName != "" {
+		hostConfig.NetworkMode = container.NetworkMode(networkName)
+	} else if e.network != "" {
 		hostConfig.NetworkMode = container.NetworkMode(e.network)
 	}
 
@@ -417,14 +832,65 @@ func (e *DockerExecutor) buildContainerConfig(job *domain.TrainingJob) (*contain
 	return config, hostConfig, nil
 }
 
-// collectLogs 收集容器日志
-func (e *DockerExecutor) collectLogs(ctx context.Context, jobID uuid.UUID, containerID string) {
+// containerNameFor 生成任务当前尝试的容器名
+func containerNameFor(job *domain.TrainingJob) string {
+	if job.Attempt > 1 {
+		return fmt.Sprintf("aitip-train-%s-%d", job.ID.String()[:8], job.Attempt)
+	}
+	return fmt.Sprintf("aitip-train-%s", job.ID.String()[:8])
+}
+
+// logLevel 根据输出流和内容推断日志级别
+func logLevel(stream, line string) string {
+	if stream == "stderr" {
+		return "ERROR"
+	} else if strings.Contains(line, "WARN") || strings.Contains(line, "warning") {
+		return "WARN"
+	} else if strings.Contains(line, "ERROR") || strings.Contains(line, "error") {
+		return "ERROR"
+	}
+	return "INFO"
+}
+
+// collectNodeLogs 收集所有节点的日志，since 按节点顺序给出各节点的起始时间，全部结束后关闭 logsDone
+func (e *DockerExecutor) collectNodeLogs(ctx context.Context, process *JobProcess, since []time.Time) {
+	defer close(process.logsDone)
+
+	var wg sync.WaitGroup
+	for i, node := range process.Nodes {
+		var from time.Time
+		if i < len(since) {
+			from = since[i]
+		}
+
+		wg.Add(1)
+		go func(node *NodeContainer, from time.Time) {
+			defer wg.Done()
+			e.collectLogs(ctx, process, node, from)
+		}(node, from)
+	}
+	wg.Wait()
+}
+
+// collectLogs 收集节点容器日志，since 非零时只收集该时间之后的日志
+func (e *DockerExecutor) collectLogs(ctx context.Context, process *JobProcess, node *NodeContainer, since time.Time) {
+	jobID, containerID := process.JobID, node.ContainerID
+
+	// 分布式任务的日志标记节点序号
+	var rank *int
+	if process.distributed() {
+		r := node.Rank
+		rank = &r
+	}
 	options := types.ContainerLogsOptions{
 		ShowStdout: true,
 		ShowStderr: true,
 		Follow:     true,
 		Timestamps: true,
 	}
+	if !since.IsZero() {
+		options.Since = since.Format(time.RFC3339Nano)
+	}
 
 	reader, err := e.client.ContainerLogs(ctx, containerID, options)
 	if err != nil {
@@ -433,72 +899,119 @@ func (e *DockerExecutor) collectLogs(ctx context.Context, jobID uuid.UUID, conta
 	}
 	defer reader.Close()
 
-	buf := make([]byte, 8*1024)
-	var currentStream string
+	err = demuxLogLines(reader, func(stream, line string) {
+		e.handleLogLine(process, rank, stream, line)
+	})
+	if err != nil && ctx.Err() == nil {
+		logger.Error("Error reading logs", zap.String("job_id", jobID.String()), zap.Error(err))
+	}
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
+// demuxLogLines 用 stdcopy 拆分 Docker 多路复用的日志流，按完整的行回调 handle，
+// 跨帧或跨多次读取的行会先拼接完整；handle 不会被并发调用
+func demuxLogLines(reader io.Reader, handle func(stream, line string)) error {
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderrReader, stderrWriter := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(stdoutWriter, stderrWriter, reader)
+		stdoutWriter.CloseWithError(err)
+		stderrWriter.CloseWithError(err)
+	}()
 
-		n, err := reader.Read(buf)
-		if err != nil {
-			if err != io.EOF {
-				logger.Error("Error reading logs", zap.Error(err))
+	streams := []struct {
+		name   string
+		reader *io.PipeReader
+	}{
+		{"stdout", stdoutReader},
+		{"stderr", stderrReader},
+	}
+	errs := make([]error, len(streams))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, stream := range streams {
+		wg.Add(1)
+		go func(i int, name string, r *io.PipeReader) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				mu.Lock()
+				handle(name, scanner.Text())
+				mu.Unlock()
 			}
-			return
-		}
-
-		if n > 8 {
-			streamType := buf[0]
-			if streamType == 1 {
-				currentStream = "stdout"
-			} else if streamType == 2 {
-				currentStream = "stderr"
+			if err := scanner.Err(); err != nil {
+				errs[i] = err
+				// 继续读空管道，避免阻塞另一路输出
+				io.Copy(io.Discard, r)
 			}
+		}(i, stream.name, stream.reader)
+	}
+	wg.Wait()
 
-			data := string(buf[8:n])
-			lines := strings.Split(data, "\n")
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			for _, line := range lines {
-				if line = strings.TrimSpace(line); line == "" {
-					continue
-				}
+// handleLogLine 处理一行完整的容器日志：脱敏后检查检查点声明和指标上报，并写入日志流
+func (e *DockerExecutor) handleLogLine(process *JobProcess, rank *int, stream, line string) {
+	jobID := process.JobID
+	if line = strings.TrimSpace(line); line == "" {
+		return
+	}
+	timestamp, line := splitLogTimestamp(line)
+	if line == "" {
+		return
+	}
+	line = process.redactor.Redact(line)
 
-				level := "INFO"
-				if currentStream == "stderr" {
-					level = "ERROR"
-				} else if strings.Contains(line, "WARN") || strings.Contains(line, "warning") {
-					level = "WARN"
-				} else if strings.Contains(line, "ERROR") || strings.Contains(line, "error") {
-					level = "ERROR"
-				}
+	process.appendTail(line)
 
-				entry := &domain.LogEntry{
-					Level:     level,
-					Source:    currentStream,
-					Message:   line,
-					Timestamp: time.Now(),
-				}
+	if payload, ok := strings.CutPrefix(line, checkpointLogPrefix); ok {
+		e.handleCheckpointAnnouncement(jobID, payload)
+	}
 
-				if metrics := e.metricParser.Parse(line); metrics != nil {
-					entry.Message = fmt.Sprintf("[METRICS] %s", line)
-					if e.metricsRepo != nil {
-						go e.saveMetrics(context.Background(), jobID, metrics)
-					}
-				}
+	entry := &domain.LogEntry{
+		Level:     logLevel(stream, line),
+		Source:    stream,
+		Message:   line,
+		Attempt:   process.Attempt,
+		Rank:      rank,
+		Timestamp: timestamp,
+	}
 
-				logCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-				e.logRepo.AppendLog(logCtx, jobID, entry)
-				cancel()
-			}
+	metrics, err := e.metricParser.ParseLine(line)
+	if err != nil {
+		logger.Warn("Invalid metric report", zap.String("job_id", jobID.String()), zap.Error(err))
+	} else if metrics != nil {
+		entry.Message = fmt.Sprintf("[METRICS] %s", line)
+		// 分布式训练只采用主节点的指标，避免重复记录
+		if rank == nil || *rank == 0 {
+			e.reportMetrics(jobID, metrics)
 		}
 	}
+
+	logCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	e.logRepo.AppendLog(logCtx, jobID, entry)
+	cancel()
+}
+
+// splitLogTimestamp 拆分开启 Timestamps 的 Docker 日志行开头的 RFC3339 时间戳，
+// 没有时间戳时使用当前时间并返回原行
+func splitLogTimestamp(line string) (time.Time, string) {
+	prefix, rest, _ := strings.Cut(line, " ")
+	t, err := time.Parse(time.RFC3339Nano, prefix)
+	if err != nil {
+		return time.Now(), line
+	}
+	return t, strings.TrimSpace(rest)
 }
 
-// collectMetrics 收集容器指标
+// collectMetrics 定期采集主节点容器的资源用量并推送到实时指标通道
 func (e *DockerExecutor) collectMetrics(ctx context.Context, jobID uuid.UUID, containerID string) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
@@ -508,107 +1021,193 @@ func (e *DockerExecutor) collectMetrics(ctx context.Context, jobID uuid.UUID, co
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			stats, err := e.client.ContainerStats(ctx, containerID, false)
+			stats, err := e.resourceStats(ctx, containerID)
 			if err != nil {
+				if ctx.Err() == nil {
+					logger.Debug("Failed to collect container stats", zap.String("job_id", jobID.String()), zap.Error(err))
+				}
 				continue
 			}
-			_ = stats.Body.Close()
-
-			if e.gpuDetector.IsAvailable() {
-				_ = e.gpuDetector.GetGPUStats(containerID)
-			}
+			e.metricsBroadcaster.BroadcastMetric(jobID, "resources", stats)
 		}
 	}
 }
 
-// monitorContainer 监控容器状态
-func (e *DockerExecutor) monitorContainer(ctx context.Context, job *domain.TrainingJob, containerID string, pid int) {
-	statusCh, errCh := e.client.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
+// nodeExit 节点容器退出结果
+type nodeExit struct {
+	node   *NodeContainer
+	status container.WaitResponse
+	err    error
+}
+
+// monitorNodes 监控任务的所有节点容器：任一节点失败时停止其余节点，全部成功退出时任务完成
+func (e *DockerExecutor) monitorNodes(ctx context.Context, job *domain.TrainingJob, process *JobProcess) {
+	exits := make(chan nodeExit, len(process.Nodes))
+	for _, node := range process.Nodes {
+		go func(node *NodeContainer) {
+			statusCh, errCh := e.client.ContainerWait(ctx, node.ContainerID, container.WaitConditionNotRunning)
+			select {
+			case err := <-errCh:
+				exits <- nodeExit{node: node, err: err}
+			case status := <-statusCh:
+				exits <- nodeExit{node: node, status: status}
+			case <-ctx.Done():
+			}
+		}(node)
+	}
 
-	select {
-	case err := <-errCh:
-		if err != nil {
-			logger.Error("Container wait error", zap.String("job_id", job.ID.String()), zap.Error(err))
-			e.handleJobFailure(job.ID, containerID, err)
+	for remaining := len(process.Nodes); remaining > 0; remaining-- {
+		var exit nodeExit
+		select {
+		case exit = <-exits:
+		case <-ctx.Done():
+			return
+		}
+		if process.pausing.Load() {
+			return
+		}
+
+		if exit.err == nil && exit.status.StatusCode == 0 {
+			continue
 		}
-	case status := <-statusCh:
-		e.handleContainerExit(job.ID, containerID, status)
-	case <-ctx.Done():
+		if ctx.Err() != nil {
+			return
+		}
+
+		// 任一节点失败后其余节点无法继续通信，整体停止
+		if len(process.Nodes) > 1 {
+			e.appendSystemLog(job, "WARN", fmt.Sprintf("Node %d exited, stopping remaining nodes", exit.node.Rank))
+			stopCtx, cancel := context.WithTimeout(context.Background(), 40*time.Second)
+			e.stopNodes(stopCtx, process, exit.node)
+			cancel()
+		}
+
+		if exit.err != nil {
+			logger.Error("Container wait error", zap.String("job_id", job.ID.String()), zap.Int("rank", exit.node.Rank), zap.Error(exit.err))
+			e.handleJobFailure(job, process, exit.err)
+			return
+		}
+		e.handleContainerExit(job, process, exit.node, exit.status)
 		return
 	}
+
+	e.handleContainerExit(job, process, process.Nodes[0], container.WaitResponse{StatusCode: 0})
+}
+
+// appendSystemLog 记录当前尝试的系统日志
+func (e *DockerExecutor) appendSystemLog(job *domain.TrainingJob, level, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e.logRepo.AppendLog(ctx, job.ID, &domain.LogEntry{
+		Level:     level,
+		Source:    "system",
+		Message:   message,
+		Attempt:   job.Attempt,
+		Timestamp: time.Now(),
+	})
 }
 
-// handleContainerExit 处理容器退出
-func (e *DockerExecutor) handleContainerExit(jobID uuid.UUID, containerID string, status container.WaitResponse) {
+// handleContainerExit 处理任务退出，node 为决定任务结果的节点
+func (e *DockerExecutor) handleContainerExit(job *domain.TrainingJob, process *JobProcess, node *NodeContainer, status container.WaitResponse) {
+	jobID := job.ID
 	exitCode := status.StatusCode
-	logger.Info("Container exited", zap.String("job_id", jobID.String()), zap.Int64("exit_code", exitCode))
+	logger.Info("Container exited", zap.String("job_id", jobID.String()), zap.Int("rank", node.Rank), zap.Int64("exit_code", exitCode))
 
-	var finalStatus domain.JobStatus
-	var message string
+	code := int(exitCode)
+	event := &ExitEvent{
+		JobID:    jobID,
+		Attempt:  job.Attempt,
+		ExitCode: &code,
+	}
 
 	if exitCode == 0 {
-		finalStatus = domain.JobStatusCompleted
-		message = "Training completed successfully"
+		event.Status = domain.JobStatusCompleted
+		event.Message = "Training completed successfully"
 	} else {
-		if e.errorHandler.IsOOM(containerID, exitCode) {
-			finalStatus = domain.JobStatusFailed
-			message = "Training failed: Out of Memory (OOM)"
-		} else {
-			finalStatus = domain.JobStatusFailed
-			message = fmt.Sprintf("Training failed with exit code %d", exitCode)
+		// 等待日志收集完成，确保重试判断能看到最后的输出
+		select {
+		case <-process.logsDone:
+		case <-time.After(3 * time.Second):
 		}
+		logTail := process.tail()
 
-		process := e.jobs[jobID]
-		if process != nil && process.RetryCount < e.maxRetries {
-			if e.errorHandler.ShouldRetry(exitCode) {
-				logger.Info("Retrying job", zap.String("job_id", jobID.String()), zap.Int("attempt", process.RetryCount+1))
-			}
+		event.Status = domain.JobStatusFailed
+		if e.errorHandler.IsOOMExit(exitCode, logTail) {
+			event.Message = "Training failed: Out of Memory (OOM)"
+		} else {
+			event.Message = fmt.Sprintf("Training failed with exit code %d", exitCode)
+		}
+		if process.distributed() {
+			event.Message = fmt.Sprintf("%s on node %d", event.Message, node.Rank)
 		}
+
+		event.Retryable, event.RetryReason = e.errorHandler.ShouldRetryWithPolicy(job.RetryPolicy, exitCode, logTail)
 	}
 
 	level := "INFO"
-	if finalStatus == domain.JobStatusFailed {
+	if event.Status == domain.JobStatusFailed {
 		level = "ERROR"
 	}
+	e.appendSystemLog(job, level, event.Message)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	e.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
-		Level:     level,
-		Source:    "system",
-		Message:   message,
-		Timestamp: time.Now(),
-	})
-	cancel()
-
-	e.cleanupJob(jobID, containerID)
+	e.cleanupJob(process)
+	e.notifyExit(event)
 }
 
 // handleJobFailure 处理任务失败
-func (e *DockerExecutor) handleJobFailure(jobID uuid.UUID, containerID string, err error) {
-	logger.Error("Job failed", zap.String("job_id", jobID.String()), zap.Error(err))
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	e.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
-		Level:     "ERROR",
-		Source:    "system",
-		Message:   fmt.Sprintf("Job failed: %v", err),
-		Timestamp: time.Now(),
+func (e *DockerExecutor) handleJobFailure(job *domain.TrainingJob, process *JobProcess, err error) {
+	logger.Error("Job failed", zap.String("job_id", job.ID.String()), zap.Error(err))
+
+	message := fmt.Sprintf("Job failed: %v", err)
+	e.appendSystemLog(job, "ERROR", message)
+
+	e.cleanupJob(process)
+	e.notifyExit(&ExitEvent{
+		JobID:   job.ID,
+		Attempt: job.Attempt,
+		Status:  domain.JobStatusFailed,
+		Message: message,
 	})
-	cancel()
+}
 
-	e.cleanupJob(jobID, containerID)
+// notifyExit 通知容器退出
+func (e *DockerExecutor) notifyExit(event *ExitEvent) {
+	if e.exitHandler != nil {
+		e.exitHandler(event)
+	}
 }
 
-// cleanupJob 清理任务资源
-func (e *DockerExecutor) cleanupJob(jobID uuid.UUID, containerID string) {
+// cleanupJob 清理任务资源：停止并删除所有节点容器，删除专用网络
+//
+// 调用方需在此之前读取退出码；删除容器前等待日志收集结束，保留最后的输出。
+func (e *DockerExecutor) cleanupJob(process *JobProcess) {
 	e.mu.Lock()
-	delete(e.jobs, jobID)
+	if e.jobs[process.JobID] == process {
+		delete(e.jobs, process.JobID)
+	}
 	e.mu.Unlock()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	e.client.ContainerStop(ctx, containerID, container.StopTimeout(nil))
+	for _, node := range process.Nodes {
+		e.client.ContainerStop(ctx, node.ContainerID, container.StopTimeout(nil))
+	}
+	select {
+	case <-process.logsDone:
+	case <-time.After(3 * time.Second):
+	}
+	e.removeNodes(ctx, process)
+	e.removeNetwork(ctx, process.JobID, process.Network)
+	e.releaseGPUs(process.JobID)
+}
+
+// releaseGPUs 释放任务占用的 GPU
+func (e *DockerExecutor) releaseGPUs(jobID uuid.UUID) {
+	if e.gpuAllocator != nil {
+		e.gpuAllocator.Release(jobID)
+	}
 }
 
 // stopOrphanContainer 停止孤儿容器
@@ -633,11 +1232,218 @@ func (e *DockerExecutor) stopOrphanContainer(ctx context.Context, jobID uuid.UUI
 	return nil
 }
 
+// handleCheckpointAnnouncement 解析检查点声明并通知回调
+func (e *DockerExecutor) handleCheckpointAnnouncement(jobID uuid.UUID, payload string) {
+	if e.checkpointHandler == nil {
+		return
+	}
+
+	var announcement domain.CheckpointAnnouncement
+	if err := json.Unmarshal([]byte(payload), &announcement); err != nil || announcement.Path == "" {
+		logger.Warn("Invalid checkpoint announcement", zap.String("job_id", jobID.String()), zap.String("payload", payload))
+		return
+	}
+
+	go e.checkpointHandler(jobID, &announcement)
+}
+
+// SetCheckpointHandler 设置检查点声明回调
+func (e *DockerExecutor) SetCheckpointHandler(handler CheckpointHandler) {
+	e.checkpointHandler = handler
+}
+
+// SetExitHandler 设置容器退出回调
+func (e *DockerExecutor) SetExitHandler(handler ExitHandler) {
+	e.exitHandler = handler
+}
+
+// IsTracked 检查任务是否由当前执行器实例跟踪
+func (e *DockerExecutor) IsTracked(jobID uuid.UUID) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, exists := e.jobs[jobID]
+	return exists
+}
+
+// ListManagedContainers 列出所有带 aitip.job.id 标签的训练容器（包括已退出的）
+func (e *DockerExecutor) ListManagedContainers(ctx context.Context) ([]*domain.ContainerInfo, error) {
+	filterArgs := filters.NewArgs()
+	filterArgs.Add("label", "aitip.job.id")
+
+	containers, err := e.client.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filterArgs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := make([]*domain.ContainerInfo, 0, len(containers))
+	for _, c := range containers {
+		info := &domain.ContainerInfo{
+			ContainerID: c.ID,
+			Image:       c.Image,
+			Status:      c.Status,
+			State:       c.State,
+			Labels:      c.Labels,
+		}
+		if len(c.Names) > 0 {
+			info.ContainerName = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		// 已退出的容器需要 inspect 才能拿到退出码
+		if c.State == "exited" || c.State == "dead" {
+			if inspect, err := e.client.ContainerInspect(ctx, c.ID); err == nil {
+				info.ExitCode = inspect.State.ExitCode
+			}
+		}
+
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+// Reattach 重新接管服务重启前启动的全部节点容器，恢复日志收集和状态监控
+func (e *DockerExecutor) Reattach(ctx context.Context, job *domain.TrainingJob, containers []*domain.ContainerInfo) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.jobs[job.ID]; exists {
+		return nil
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no containers to reattach for job %s", job.ID)
+	}
+
+	nodes := nodesFromContainers(containers)
+	master := nodes[0]
+
+	inspect, err := e.client.ContainerInspect(ctx, master.ContainerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	master.PID = inspect.State.Pid
+
+	startedAt := time.Now()
+	if t, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt); err == nil {
+		startedAt = t
+	}
+
+	execCtx, cancel := context.WithCancel(context.Background())
+	process := &JobProcess{
+		JobID:         job.ID,
+		Attempt:       job.Attempt,
+		ContainerID:   master.ContainerID,
+		ContainerName: master.ContainerName,
+		Nodes:         nodes,
+		Network:       containers[0].Labels["aitip.job.network"],
+		CancelFunc:    cancel,
+		StartedAt:     startedAt,
+		RetryCount:    job.Attempt - 1,
+		logsDone:      make(chan struct{}),
+		redactor:      restoreRedactor(ctx, e.secrets, job),
+	}
+
+	// 从各节点最后一条已收集的日志之后继续收集，避免重复
+	since := make([]time.Time, len(nodes))
+	for i, node := range nodes {
+		var rank *int
+		if process.distributed() {
+			rank = &node.Rank
+		}
+		if since[i], err = e.logRepo.GetLastContainerLogTime(ctx, job.ID, rank); err != nil {
+			logger.Warn("Failed to get last log time, collecting from start", zap.String("job_id", job.ID.String()), zap.Int("rank", node.Rank), zap.Error(err))
+		}
+	}
+
+	e.jobs[job.ID] = process
+
+	// 恢复节点占用的 GPU
+	if e.gpuAllocator != nil {
+		for _, c := range containers {
+			if devices := c.Labels["aitip.gpu.devices"]; devices != "" {
+				rank, _ := strconv.Atoi(c.Labels["aitip.job.rank"])
+				e.gpuAllocator.Restore(job.ID, rank, parseGPUIndices(devices))
+			}
+		}
+	}
+
+	go e.collectNodeLogs(execCtx, process, since)
+
+	if e.metricsBroadcaster != nil {
+		go e.collectMetrics(execCtx, job.ID, master.ContainerID)
+	}
+
+	go e.monitorNodes(execCtx, job, process)
+
+	logger.Info("Reattached to containers",
+		zap.String("job_id", job.ID.String()),
+		zap.String("container_id", master.ContainerID),
+		zap.Int("nodes", len(nodes)),
+	)
+	return nil
+}
+
+// RemoveContainer 强制删除容器
+func (e *DockerExecutor) RemoveContainer(ctx context.Context, containerID string) error {
+	return e.client.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true})
+}
+
+// GetGPUCount 获取宿主机可用 GPU 数量
+func (e *DockerExecutor) GetGPUCount() int {
+	if e.gpuDetector == nil || !e.gpuDetector.IsAvailable() {
+		return 0
+	}
+	return e.gpuDetector.GetGPUCount()
+}
+
+// GPUAllocator 获取 GPU 分配器，宿主机没有可用 GPU 时为 nil
+func (e *DockerExecutor) GPUAllocator() *GPUAllocator {
+	return e.gpuAllocator
+}
+
+// SetGPUAllocator 替换 GPU 分配器，需在启动任务之前调用
+func (e *DockerExecutor) SetGPUAllocator(allocator *GPUAllocator) {
+	e.gpuAllocator = allocator
+}
+
+// SetSecretManager 设置密钥存储，任务引用的密钥在启动时解密注入
+func (e *DockerExecutor) SetSecretManager(manager secrets.Manager) {
+	e.secrets = manager
+}
+
 // SetMetricsRepository 设置指标仓库
 func (e *DockerExecutor) SetMetricsRepository(repo MetricsRepository) {
 	e.metricsRepo = repo
 }
 
+// SetMetricsHandler 设置指标回调，设置后指标交由回调处理而不是直接写入指标仓库
+func (e *DockerExecutor) SetMetricsHandler(handler MetricsHandler) {
+	e.metricsHandler = handler
+}
+
+// SetMetricsEndpoint 设置训练容器上报指标的接口，容器通过 AITIP_METRICS_URL 和 AITIP_METRICS_TOKEN 获取
+func (e *DockerExecutor) SetMetricsEndpoint(endpoint *MetricsEndpoint) {
+	e.metricsEndpoint = endpoint
+}
+
+// SetMetricsBroadcaster 设置实时指标通道，设置后定期推送容器的 CPU、内存和 GPU 用量
+func (e *DockerExecutor) SetMetricsBroadcaster(broadcaster MetricsBroadcaster) {
+	e.metricsBroadcaster = broadcaster
+}
+
+// reportMetrics 处理从日志解析到的指标
+func (e *DockerExecutor) reportMetrics(jobID uuid.UUID, metrics *TrainingMetrics) {
+	if e.metricsHandler != nil {
+		go e.metricsHandler(jobID, metrics)
+		return
+	}
+	if e.metricsRepo != nil {
+		go e.saveMetrics(context.Background(), jobID, metrics)
+	}
+}
+
 // saveMetrics 保存训练指标
 func (e *DockerExecutor) saveMetrics(ctx context.Context, jobID uuid.UUID, metrics *TrainingMetrics) {
 	if e.metricsRepo == nil {
@@ -680,16 +1486,18 @@ type MetricParser struct {
 // NewMetricParser 创建指标解析器
 func NewMetricParser() *MetricParser {
 	return &MetricParser{
-		pytorchLossPattern: regexp.MustCompile(`(?i)loss[:=\s]+([0-9.]+(?:e[+-]?[0-9]+)?)`),
-		pytorchAccPattern:  regexp.MustCompile(`(?i)accuracy|acc[:=\s]+([0-9.]+)`),
+		pytorchLossPattern: regexp.MustCompile(`(?i)\bloss[:=\s]+([0-9.]+(?:e[+-]?[0-9]+)?)`),
+		pytorchAccPattern:  regexp.MustCompile(`(?i)\b(?:accuracy|acc)[:=\s]+([0-9.]+)`),
 		tfLossPattern:      regexp.MustCompile(`(?i)loss:\s*([0-9.]+)`),
 		tfAccPattern:       regexp.MustCompile(`(?i)accuracy:\s*([0-9.]+)`),
 		epochPattern:       regexp.MustCompile(`(?i)epoch[:/\s]+(\d+)`),
-		stepPattern:        regexp.MustCompile(`(?i)step|batch[:/\s]+(\d+)`),
+		stepPattern:        regexp.MustCompile(`(?i)\b(?:step|batch)[:/\s]+(\d+)`),
 	}
 }
 
-// Parse 解析日志行中的指标
+// Parse 用正则从非结构化日志行中猜测指标，作为 AITIP_METRIC 协议的兜底
+//
+// 只有 epoch/step 而没有指标值的行不视为指标。
 func (p *MetricParser) Parse(line string) *TrainingMetrics {
 	metrics := &TrainingMetrics{
 		Timestamp: time.Now(),
@@ -701,14 +1509,12 @@ func (p *MetricParser) Parse(line string) *TrainingMetrics {
 	if matches := p.epochPattern.FindStringSubmatch(line); len(matches) > 1 {
 		if epoch, err := strconv.Atoi(matches[1]); err == nil {
 			metrics.Epoch = epoch
-			hasMetrics = true
 		}
 	}
 
 	if matches := p.stepPattern.FindStringSubmatch(line); len(matches) > 1 {
 		if step, err := strconv.Atoi(matches[1]); err == nil {
 			metrics.Step = step
-			hasMetrics = true
 		}
 	}
 
@@ -0,0 +1,354 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// logFrame 构造一个 Docker 多路复用日志帧
+func logFrame(t *testing.T, stream stdcopy.StdType, payload string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := stdcopy.NewStdWriter(&buf, stream).Write([]byte(payload)); err != nil {
+		t.Fatalf("write frame: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// chunkReader 每次最多返回 size 字节，模拟日志跨多次读取到达
+type chunkReader struct {
+	data []byte
+	size int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := min(len(p), r.size, len(r.data))
+	copy(p, r.data[:n])
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// frameReader 将多个日志帧拼接后按 size 字节分块读取
+func frameReader(frames [][]byte, size int) io.Reader {
+	return &chunkReader{data: bytes.Join(frames, nil), size: size}
+}
+
+// newTestProcess 创建用于处理日志行的执行器和任务进程
+func newTestProcess(resolved []secrets.Resolved) (*DockerExecutor, *JobProcess, *fakeLogRepo) {
+	repo := &fakeLogRepo{}
+	e := &DockerExecutor{logRepo: repo, metricParser: NewMetricParser()}
+	process := &JobProcess{JobID: uuid.New(), Attempt: 1, redactor: secrets.NewRedactor(resolved)}
+	return e, process, repo
+}
+
+// collectTestLogs 将多路复用的日志流交给执行器逐行处理
+func collectTestLogs(t *testing.T, e *DockerExecutor, process *JobProcess, reader io.Reader) {
+	t.Helper()
+	err := demuxLogLines(reader, func(stream, line string) {
+		e.handleLogLine(process, nil, stream, line)
+	})
+	if err != nil {
+		t.Fatalf("demuxLogLines() error = %v", err)
+	}
+}
+
+func TestSplitLogTimestamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantTime time.Time
+		wantLine string
+	}{
+		{
+			name:     "timestamped line",
+			line:     "2026-10-16T09:38:36.123456789Z epoch 1 loss 0.5",
+			wantTime: time.Date(2026, 10, 16, 9, 38, 36, 123456789, time.UTC),
+			wantLine: "epoch 1 loss 0.5",
+		},
+		{
+			name:     "timestamp only",
+			line:     "2026-10-16T09:38:36Z",
+			wantTime: time.Date(2026, 10, 16, 9, 38, 36, 0, time.UTC),
+			wantLine: "",
+		},
+		{
+			name:     "no timestamp",
+			line:     "epoch 1 loss 0.5",
+			wantLine: "epoch 1 loss 0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTime, gotLine := splitLogTimestamp(tt.line)
+			if gotLine != tt.wantLine {
+				t.Errorf("line = %q, want %q", gotLine, tt.wantLine)
+			}
+			if !tt.wantTime.IsZero() && !gotTime.Equal(tt.wantTime) {
+				t.Errorf("time = %v, want %v", gotTime, tt.wantTime)
+			}
+			if tt.wantTime.IsZero() && time.Since(gotTime) > time.Minute {
+				t.Errorf("time = %v, want now", gotTime)
+			}
+		})
+	}
+}
+
+func TestPinnedDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{"tag", "pytorch/pytorch:2.1.0", ""},
+		{"no tag", "python", ""},
+		{"digest", "pytorch/pytorch@" + digest, digest},
+		{"tag and digest", "registry.example.com:5000/ml/trainer:v1@" + digest, digest},
+		{"short digest", "pytorch/pytorch@sha256:abcd", ""},
+		{"other algorithm", "pytorch/pytorch@sha512:" + strings.Repeat("ab", 64), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pinnedDigest(tt.image); got != tt.want {
+				t.Errorf("pinnedDigest(%q) = %q, want %q", tt.image, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimestampedMetricLine(t *testing.T) {
+	line := `2026-10-16T09:38:36.123456789Z AITIP_METRIC {"step": 1200, "epoch": 3, "metrics": {"loss": 0.31, "val_acc": 0.92}}`
+
+	timestamp, message := splitLogTimestamp(line)
+	if want := time.Date(2026, 10, 16, 9, 38, 36, 123456789, time.UTC); !timestamp.Equal(want) {
+		t.Fatalf("timestamp = %v, want %v", timestamp, want)
+	}
+
+	metrics, err := NewMetricParser().ParseLine(message)
+	if err != nil {
+		t.Fatalf("ParseLine() error = %v", err)
+	}
+	if metrics == nil {
+		t.Fatal("ParseLine() = nil, want metrics")
+	}
+	if metrics.Step != 1200 || metrics.Epoch != 3 {
+		t.Errorf("step/epoch = %d/%d, want 1200/3", metrics.Step, metrics.Epoch)
+	}
+	if metrics.Loss == nil || *metrics.Loss != 0.31 {
+		t.Errorf("loss = %v, want 0.31", metrics.Loss)
+	}
+	if got := metrics.Custom["val_acc"]; got != 0.92 {
+		t.Errorf("val_acc = %v, want 0.92", got)
+	}
+}
+
+func TestTimestampedCheckpointAnnouncement(t *testing.T) {
+	announced := make(chan *domain.CheckpointAnnouncement, 1)
+	e := &DockerExecutor{
+		checkpointHandler: func(jobID uuid.UUID, announcement *domain.CheckpointAnnouncement) {
+			announced <- announcement
+		},
+	}
+
+	line := `2026-10-16T09:38:36.123456789Z AITIP_CHECKPOINT {"path": "/output/checkpoints/epoch-3.pt", "step": 1200, "epoch": 3}`
+	_, message := splitLogTimestamp(line)
+	payload, ok := strings.CutPrefix(message, checkpointLogPrefix)
+	if !ok {
+		t.Fatalf("message %q has no checkpoint prefix", message)
+	}
+	e.handleCheckpointAnnouncement(uuid.New(), payload)
+
+	select {
+	case announcement := <-announced:
+		if announcement.Path != "/output/checkpoints/epoch-3.pt" || announcement.Step != 1200 {
+			t.Errorf("announcement = %+v", announcement)
+		}
+		if announcement.Epoch == nil || *announcement.Epoch != 3 {
+			t.Errorf("epoch = %v, want 3", announcement.Epoch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("checkpoint handler was not called")
+	}
+}
+
+func TestDemuxLogLines(t *testing.T) {
+	tests := []struct {
+		name       string
+		frames     func(t *testing.T) [][]byte
+		chunk      int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name: "several frames in one read",
+			frames: func(t *testing.T) [][]byte {
+				return [][]byte{
+					logFrame(t, stdcopy.Stdout, "epoch 1\n"),
+					logFrame(t, stdcopy.Stdout, "epoch 2\nepoch 3\n"),
+					logFrame(t, stdcopy.Stderr, "warning: slow io\n"),
+				}
+			},
+			chunk:      1 << 20,
+			wantStdout: []string{"epoch 1", "epoch 2", "epoch 3"},
+			wantStderr: []string{"warning: slow io"},
+		},
+		{
+			name: "line split across frames",
+			frames: func(t *testing.T) [][]byte {
+				return [][]byte{
+					logFrame(t, stdcopy.Stdout, "epoch 1 lo"),
+					logFrame(t, stdcopy.Stdout, "ss 0.5\nepoch 2\n"),
+				}
+			},
+			chunk:      1 << 20,
+			wantStdout: []string{"epoch 1 loss 0.5", "epoch 2"},
+		},
+		{
+			name: "frames split across reads",
+			frames: func(t *testing.T) [][]byte {
+				return [][]byte{
+					logFrame(t, stdcopy.Stdout, "epoch 1 loss 0.5\n"),
+					logFrame(t, stdcopy.Stderr, "warning: slow io\n"),
+					logFrame(t, stdcopy.Stdout, "epoch 2 loss 0.4\n"),
+				}
+			},
+			chunk:      3,
+			wantStdout: []string{"epoch 1 loss 0.5", "epoch 2 loss 0.4"},
+			wantStderr: []string{"warning: slow io"},
+		},
+		{
+			name: "last line without newline",
+			frames: func(t *testing.T) [][]byte {
+				return [][]byte{logFrame(t, stdcopy.Stdout, "done")}
+			},
+			chunk:      1 << 20,
+			wantStdout: []string{"done"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string][]string)
+			err := demuxLogLines(frameReader(tt.frames(t), tt.chunk), func(stream, line string) {
+				got[stream] = append(got[stream], line)
+			})
+			if err != nil {
+				t.Fatalf("demuxLogLines() error = %v", err)
+			}
+			if !reflect.DeepEqual(got["stdout"], tt.wantStdout) {
+				t.Errorf("stdout = %q, want %q", got["stdout"], tt.wantStdout)
+			}
+			if !reflect.DeepEqual(got["stderr"], tt.wantStderr) {
+				t.Errorf("stderr = %q, want %q", got["stderr"], tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestCollectTimestampedMetricFrames(t *testing.T) {
+	e, process, repo := newTestProcess(nil)
+	reported := make(chan *TrainingMetrics, 1)
+	e.metricsHandler = func(jobID uuid.UUID, metrics *TrainingMetrics) {
+		reported <- metrics
+	}
+
+	// 指标行被拆到两个帧中，帧头和时间戳又跨越多次读取
+	frames := [][]byte{
+		logFrame(t, stdcopy.Stdout, "2026-10-16T09:38:35Z epoch 3 started\n2026-10-16T09:38:36.5Z AITIP_METRIC {\"step\": 1200, "),
+		logFrame(t, stdcopy.Stdout, `"epoch": 3, "metrics": {"loss": 0.31}}`+"\n"),
+	}
+	collectTestLogs(t, e, process, frameReader(frames, 7))
+
+	if len(repo.entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(repo.entries))
+	}
+	if entry := repo.entries[0]; entry.Message != "epoch 3 started" || entry.Source != "stdout" {
+		t.Errorf("entry = %+v, want plain stdout line", entry)
+	}
+	entry := repo.entries[1]
+	if !strings.HasPrefix(entry.Message, "[METRICS] AITIP_METRIC") {
+		t.Errorf("message = %q, want metric report", entry.Message)
+	}
+	if want := time.Date(2026, 10, 16, 9, 38, 36, 500000000, time.UTC); !entry.Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", entry.Timestamp, want)
+	}
+
+	select {
+	case metrics := <-reported:
+		if metrics.Step != 1200 || metrics.Loss == nil || *metrics.Loss != 0.31 {
+			t.Errorf("metrics = %+v", metrics)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("metrics handler was not called")
+	}
+}
+
+func TestCollectCheckpointAnnouncementFrames(t *testing.T) {
+	e, process, repo := newTestProcess(nil)
+	announced := make(chan *domain.CheckpointAnnouncement, 1)
+	e.checkpointHandler = func(jobID uuid.UUID, announcement *domain.CheckpointAnnouncement) {
+		announced <- announcement
+	}
+
+	// 检查点声明跟在同一帧的另一行之后，并被拆到下一帧
+	frames := [][]byte{
+		logFrame(t, stdcopy.Stdout, "2026-10-16T09:38:35Z saving checkpoint\n2026-10-16T09:38:36Z AITIP_CHECKPOINT {\"path\": \"/output/checkpoints/"),
+		logFrame(t, stdcopy.Stdout, `epoch-3.pt", "step": 1200, "epoch": 3}`+"\n"),
+	}
+	collectTestLogs(t, e, process, frameReader(frames, 5))
+
+	select {
+	case announcement := <-announced:
+		if announcement.Path != "/output/checkpoints/epoch-3.pt" || announcement.Step != 1200 {
+			t.Errorf("announcement = %+v", announcement)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("checkpoint handler was not called")
+	}
+	if len(repo.entries) != 2 {
+		t.Errorf("entries = %d, want 2", len(repo.entries))
+	}
+}
+
+func TestCollectRedactsSecretsSplitAcrossFrames(t *testing.T) {
+	token := "hf_0123456789abcdef"
+	e, process, repo := newTestProcess([]secrets.Resolved{{Ref: secrets.Ref{Name: "HF_TOKEN"}, Value: token}})
+
+	// 密钥值被拆到两个帧中，逐块脱敏时两半都不匹配
+	frames := [][]byte{
+		logFrame(t, stdcopy.Stdout, "login with token hf_01234"),
+		logFrame(t, stdcopy.Stdout, "56789abcdef ok\n"),
+		logFrame(t, stdcopy.Stderr, "retrying with "+token+"\n"),
+	}
+	collectTestLogs(t, e, process, frameReader(frames, 4))
+
+	if len(repo.entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(repo.entries))
+	}
+	for _, entry := range repo.entries {
+		if strings.Contains(entry.Message, "hf_01234") || strings.Contains(entry.Message, "56789abcdef") {
+			t.Errorf("message %q leaks the secret", entry.Message)
+		}
+	}
+	for _, line := range process.tail() {
+		if strings.Contains(line, token) {
+			t.Errorf("tail line %q leaks the secret", line)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"math"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// DownsampleSeries 将指标曲线降采样到最多 maxPoints 个点，点数不超过 maxPoints 时保持不变
+func DownsampleSeries(series *domain.MetricSeries, method string, maxPoints int) {
+	if method == domain.DownsampleNone || maxPoints <= 0 || len(series.Points) <= maxPoints {
+		return
+	}
+
+	switch method {
+	case domain.DownsampleBucket:
+		series.Points = DownsampleBuckets(series.Points, maxPoints)
+	default:
+		method = domain.DownsampleLTTB
+		series.Points = DownsampleLTTB(series.Points, maxPoints)
+	}
+	series.Downsample = method
+}
+
+// DownsampleLTTB 使用 Largest-Triangle-Three-Buckets 算法保留 threshold 个点，
+// 首尾两点始终保留，其余每个桶选择与前一选中点和下一桶均值构成三角形面积最大的点
+func DownsampleLTTB(points []domain.MetricPoint, threshold int) []domain.MetricPoint {
+	if threshold < 3 || len(points) <= threshold {
+		return points
+	}
+
+	xs := pointXs(points)
+	sampled := make([]domain.MetricPoint, 0, threshold)
+	sampled = append(sampled, points[0])
+
+	bucketSize := float64(len(points)-2) / float64(threshold-2)
+	selected := 0
+	for i := 0; i < threshold-2; i++ {
+		// 下一个桶的均值点，最后一个桶使用末尾点
+		nextStart := int(float64(i+1)*bucketSize) + 1
+		nextEnd := int(float64(i+2)*bucketSize) + 1
+		if nextEnd > len(points) {
+			nextEnd = len(points)
+		}
+		var avgX, avgY float64
+		if nextStart >= nextEnd {
+			avgX, avgY = xs[len(points)-1], points[len(points)-1].Value
+		} else {
+			for j := nextStart; j < nextEnd; j++ {
+				avgX += xs[j]
+				avgY += points[j].Value
+			}
+			n := float64(nextEnd - nextStart)
+			avgX, avgY = avgX/n, avgY/n
+		}
+
+		start := int(float64(i)*bucketSize) + 1
+		end := int(float64(i+1)*bucketSize) + 1
+		ax, ay := xs[selected], points[selected].Value
+		maxArea := -1.0
+		next := start
+		for j := start; j < end; j++ {
+			area := math.Abs((ax-avgX)*(points[j].Value-ay) - (ax-xs[j])*(avgY-ay))
+			if area > maxArea {
+				maxArea = area
+				next = j
+			}
+		}
+
+		sampled = append(sampled, points[next])
+		selected = next
+	}
+
+	return append(sampled, points[len(points)-1])
+}
+
+// DownsampleBuckets 将点按顺序均分为 buckets 个桶，每个桶输出均值、最小值、最大值和点数，
+// 时间、step 和 epoch 取桶内第一个点
+func DownsampleBuckets(points []domain.MetricPoint, buckets int) []domain.MetricPoint {
+	if buckets <= 0 || len(points) <= buckets {
+		return points
+	}
+
+	bucketSize := float64(len(points)) / float64(buckets)
+	sampled := make([]domain.MetricPoint, 0, buckets)
+	for i := 0; i < buckets; i++ {
+		start := int(float64(i) * bucketSize)
+		end := int(float64(i+1) * bucketSize)
+		if i == buckets-1 {
+			end = len(points)
+		}
+		if start >= end {
+			continue
+		}
+
+		point := points[start]
+		lo, hi, sum := point.Value, point.Value, 0.0
+		for _, p := range points[start:end] {
+			sum += p.Value
+			lo = math.Min(lo, p.Value)
+			hi = math.Max(hi, p.Value)
+		}
+		point.Value = sum / float64(end-start)
+		point.Min = &lo
+		point.Max = &hi
+		point.Count = end - start
+		sampled = append(sampled, point)
+	}
+
+	return sampled
+}
+
+// pointXs 计算点的横坐标：全部点都有 step 时使用 step，否则使用时间
+func pointXs(points []domain.MetricPoint) []float64 {
+	useStep := true
+	for _, p := range points {
+		if p.Step == nil {
+			useStep = false
+			break
+		}
+	}
+
+	xs := make([]float64, len(points))
+	for i, p := range points {
+		if useStep {
+			xs[i] = float64(*p.Step)
+		} else {
+			xs[i] = float64(p.Timestamp.UnixNano()) / 1e9
+		}
+	}
+	return xs
+}
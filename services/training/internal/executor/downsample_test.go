@@ -0,0 +1,130 @@
+package executor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// stepPoints 按 step 0..len(values)-1 生成的指标点
+func stepPoints(values ...float64) []domain.MetricPoint {
+	start := time.Now()
+	points := make([]domain.MetricPoint, len(values))
+	for i, value := range values {
+		step := i
+		points[i] = domain.MetricPoint{Timestamp: start.Add(time.Duration(i) * time.Second), Step: &step, Value: value}
+	}
+	return points
+}
+
+// flatWithSpike 长度为 n、在 spike 处有一个尖峰的平坦曲线
+func flatWithSpike(n, spike int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 1
+	}
+	values[spike] = 100
+	return values
+}
+
+func TestDownsampleSeries(t *testing.T) {
+	tests := []struct {
+		name       string
+		points     int
+		method     string
+		maxPoints  int
+		wantPoints int
+		wantMethod string
+	}{
+		{"within limit", 50, domain.DownsampleLTTB, 100, 50, ""},
+		{"disabled", 500, domain.DownsampleNone, 100, 500, ""},
+		{"no limit", 500, domain.DownsampleLTTB, 0, 500, ""},
+		{"lttb", 500, domain.DownsampleLTTB, 100, 100, domain.DownsampleLTTB},
+		{"lttb by default", 500, "", 100, 100, domain.DownsampleLTTB},
+		{"bucket", 500, domain.DownsampleBucket, 100, 100, domain.DownsampleBucket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := &domain.MetricSeries{Points: stepPoints(make([]float64, tt.points)...)}
+			DownsampleSeries(series, tt.method, tt.maxPoints)
+			if len(series.Points) != tt.wantPoints || series.Downsample != tt.wantMethod {
+				t.Errorf("points = %d, downsample = %q, want %d, %q", len(series.Points), series.Downsample, tt.wantPoints, tt.wantMethod)
+			}
+		})
+	}
+}
+
+func TestDownsampleLTTB(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []float64
+		threshold int
+		wantSteps []int // 必须保留的 step
+	}{
+		{"keeps first and last", flatWithSpike(100, 50), 10, []int{0, 50, 99}},
+		{"keeps spike near the start", flatWithSpike(1000, 3), 20, []int{0, 3, 999}},
+		{"keeps spike near the end", flatWithSpike(1000, 995), 20, []int{0, 995, 999}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampled := DownsampleLTTB(stepPoints(tt.values...), tt.threshold)
+			if len(sampled) != tt.threshold {
+				t.Fatalf("len = %d, want %d", len(sampled), tt.threshold)
+			}
+			kept := make(map[int]bool, len(sampled))
+			last := -1
+			for _, p := range sampled {
+				if *p.Step <= last {
+					t.Fatalf("steps not increasing: %d after %d", *p.Step, last)
+				}
+				last = *p.Step
+				kept[*p.Step] = true
+			}
+			for _, step := range tt.wantSteps {
+				if !kept[step] {
+					t.Errorf("step %d was dropped", step)
+				}
+			}
+		})
+	}
+
+	// 阈值过小或点数不超过阈值时原样返回
+	points := stepPoints(1, 2, 3, 4)
+	if got := DownsampleLTTB(points, 2); len(got) != 4 {
+		t.Errorf("threshold 2: len = %d, want 4", len(got))
+	}
+}
+
+func TestDownsampleBuckets(t *testing.T) {
+	points := stepPoints(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+	tests := []struct {
+		buckets   int
+		wantSteps []int
+		wantMean  []float64
+		wantMin   []float64
+		wantMax   []float64
+		wantCount []int
+	}{
+		{2, []int{0, 5}, []float64{3, 8}, []float64{1, 6}, []float64{5, 10}, []int{5, 5}},
+		{3, []int{0, 3, 6}, []float64{2, 5, 8.5}, []float64{1, 4, 7}, []float64{3, 6, 10}, []int{3, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d buckets", tt.buckets), func(t *testing.T) {
+			sampled := DownsampleBuckets(points, tt.buckets)
+			if len(sampled) != tt.buckets {
+				t.Fatalf("len = %d, want %d", len(sampled), tt.buckets)
+			}
+			for i, p := range sampled {
+				if *p.Step != tt.wantSteps[i] || p.Value != tt.wantMean[i] || *p.Min != tt.wantMin[i] || *p.Max != tt.wantMax[i] || p.Count != tt.wantCount[i] {
+					t.Errorf("[%d] = step %d, mean %g, min %g, max %g, count %d", i, *p.Step, p.Value, *p.Min, *p.Max, p.Count)
+				}
+			}
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package executor
 
 import (
+	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
 )
 
 // ErrorHandler 错误处理器
@@ -78,6 +82,64 @@ func (h *ErrorHandler) ShouldRetry(exitCode int64) bool {
 	return false
 }
 
+// ShouldRetryWithPolicy 按任务的重试策略判断失败是否可重试，返回命中的原因
+func (h *ErrorHandler) ShouldRetryWithPolicy(policy *domain.RetryPolicy, exitCode int64, logTail []string) (bool, string) {
+	if policy == nil {
+		return false, ""
+	}
+
+	// 未配置重试条件时使用默认规则
+	if len(policy.RetryOnExitCodes) == 0 && !policy.RetryOnOOM && len(policy.RetryOnLogPatterns) == 0 {
+		if h.ShouldRetry(exitCode) {
+			return true, fmt.Sprintf("exit code %d (%s)", exitCode, h.GetExitCodeDescription(exitCode))
+		}
+		if pattern := matchLogPattern(logTail, h.retryablePatterns); pattern != "" {
+			return true, fmt.Sprintf("transient error %q", pattern)
+		}
+		return false, ""
+	}
+
+	for _, code := range policy.RetryOnExitCodes {
+		if int64(code) == exitCode {
+			return true, fmt.Sprintf("exit code %d", exitCode)
+		}
+	}
+	if policy.RetryOnOOM && h.IsOOMExit(exitCode, logTail) {
+		return true, "out of memory"
+	}
+	if pattern := matchLogPattern(logTail, policy.RetryOnLogPatterns); pattern != "" {
+		return true, fmt.Sprintf("log pattern %q", pattern)
+	}
+
+	return false, ""
+}
+
+// IsOOMExit 结合退出码和最后的日志判断是否为 OOM
+func (h *ErrorHandler) IsOOMExit(exitCode int64, logTail []string) bool {
+	if h.IsOOM("", exitCode) {
+		return true
+	}
+	for _, line := range logTail {
+		if h.IsOOMLog(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLogPattern 返回日志中首个命中的模式（不区分大小写）
+func matchLogPattern(lines []string, patterns []string) string {
+	for _, pattern := range patterns {
+		lowerPattern := strings.ToLower(pattern)
+		for _, line := range lines {
+			if strings.Contains(strings.ToLower(line), lowerPattern) {
+				return pattern
+			}
+		}
+	}
+	return ""
+}
+
 // GetExitCodeDescription 获取退出码描述
 func (h *ErrorHandler) GetExitCodeDescription(exitCode int64) string {
 	if desc, ok := h.exitCodeMap[int(exitCode)]; ok {
@@ -126,12 +188,48 @@ func DefaultRetryPolicy() *RetryPolicy {
 	}
 }
 
+// RetryPolicyFor 以默认策略为基础，应用任务配置的重试策略
+func RetryPolicyFor(policy *domain.RetryPolicy) *RetryPolicy {
+	p := DefaultRetryPolicy()
+	if policy == nil {
+		p.MaxRetries = 0
+		return p
+	}
+
+	if policy.MaxAttempts > 0 {
+		p.MaxRetries = policy.MaxAttempts - 1
+	}
+	if policy.InitialDelaySeconds > 0 {
+		p.RetryDelay = time.Duration(policy.InitialDelaySeconds) * time.Second
+	}
+	if policy.BackoffMultiplier >= 1 {
+		p.BackoffMult = policy.BackoffMultiplier
+	}
+	if policy.MaxDelaySeconds > 0 {
+		p.MaxDelay = time.Duration(policy.MaxDelaySeconds) * time.Second
+	}
+	return p
+}
+
+// Delay 计算第 retry 次重试（从 1 开始）前的等待时间
+func (p *RetryPolicy) Delay(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+	delay := time.Duration(float64(p.RetryDelay) * math.Pow(p.BackoffMult, float64(retry-1)))
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay < 0) {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
 // TimeoutConfig 超时配置
 type TimeoutConfig struct {
-	StartupTimeout  time.Duration
+	StartupTimeout  time.Duration // 启动任务（拉取镜像、创建并启动容器）的最长时间
 	HealthTimeout   time.Duration
 	ShutdownTimeout time.Duration
-	OverallTimeout  time.Duration
+	OverallTimeout  time.Duration // 任务未指定 timeout_hours 时所有尝试累计的运行时间上限
+	StallTimeout    time.Duration // 运行中任务没有日志输出和指标上报的最长时间，0 表示不检测
 }
 
 // DefaultTimeoutConfig 默认超时配置
@@ -141,5 +239,6 @@ func DefaultTimeoutConfig() *TimeoutConfig {
 		HealthTimeout:   30 * time.Second,
 		ShutdownTimeout: 30 * time.Second,
 		OverallTimeout:  24 * time.Hour,
+		StallTimeout:    time.Hour,
 	}
 }
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+func TestShouldRetryWithPolicy(t *testing.T) {
+	h := NewErrorHandler()
+
+	tests := []struct {
+		name       string
+		policy     *domain.RetryPolicy
+		exitCode   int64
+		logTail    []string
+		wantRetry  bool
+		wantReason string
+	}{
+		{"no policy", nil, 1, nil, false, ""},
+		{"default general error", &domain.RetryPolicy{}, 1, nil, true, "exit code 1 (general error)"},
+		{"default segfault", &domain.RetryPolicy{}, 139, nil, false, ""},
+		{"default transient log", &domain.RetryPolicy{}, 2, []string{"Error: Connection refused by peer"}, true, `transient error "connection refused"`},
+		{"default ignores oom", &domain.RetryPolicy{}, 137, nil, false, ""},
+		{"configured exit code", &domain.RetryPolicy{RetryOnExitCodes: []int{3, 42}}, 42, nil, true, "exit code 42"},
+		{"configured codes replace defaults", &domain.RetryPolicy{RetryOnExitCodes: []int{42}}, 1, []string{"connection refused"}, false, ""},
+		{"oom by exit code", &domain.RetryPolicy{RetryOnOOM: true}, 137, nil, true, "out of memory"},
+		{"oom by log", &domain.RetryPolicy{RetryOnOOM: true}, 1, []string{"RuntimeError: CUDA out of memory."}, true, "out of memory"},
+		{"no oom", &domain.RetryPolicy{RetryOnOOM: true}, 1, []string{"ValueError: bad shape"}, false, ""},
+		{"log pattern is case insensitive", &domain.RetryPolicy{RetryOnLogPatterns: []string{"NCCL error"}}, 1, []string{"torch.distributed: nccl ERROR in allreduce"}, true, `log pattern "NCCL error"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retry, reason := h.ShouldRetryWithPolicy(tt.policy, tt.exitCode, tt.logTail)
+			if retry != tt.wantRetry || reason != tt.wantReason {
+				t.Errorf("ShouldRetryWithPolicy() = %v, %q, want %v, %q", retry, reason, tt.wantRetry, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      *domain.RetryPolicy
+		wantRetries int
+		wantDelays  []time.Duration // 第 1、2、3... 次重试前的等待时间
+	}{
+		{"no policy", nil, 0, []time.Duration{5 * time.Second}},
+		{"defaults", &domain.RetryPolicy{}, 3, []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, 60 * time.Second}},
+		{"max attempts includes the first", &domain.RetryPolicy{MaxAttempts: 1}, 0, nil},
+		{
+			name:        "custom backoff capped at max delay",
+			policy:      &domain.RetryPolicy{MaxAttempts: 5, InitialDelaySeconds: 30, BackoffMultiplier: 3, MaxDelaySeconds: 300},
+			wantRetries: 4,
+			wantDelays:  []time.Duration{30 * time.Second, 90 * time.Second, 270 * time.Second, 300 * time.Second},
+		},
+		{
+			name:        "constant delay",
+			policy:      &domain.RetryPolicy{MaxAttempts: 3, InitialDelaySeconds: 10, BackoffMultiplier: 1},
+			wantRetries: 2,
+			wantDelays:  []time.Duration{10 * time.Second, 10 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RetryPolicyFor(tt.policy)
+			if p.MaxRetries != tt.wantRetries {
+				t.Errorf("MaxRetries = %d, want %d", p.MaxRetries, tt.wantRetries)
+			}
+			for i, want := range tt.wantDelays {
+				if got := p.Delay(i + 1); got != want {
+					t.Errorf("Delay(%d) = %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelayOverflow(t *testing.T) {
+	p := RetryPolicyFor(&domain.RetryPolicy{MaxAttempts: 10, InitialDelaySeconds: 3600, BackoffMultiplier: 10, MaxDelaySeconds: 86400})
+	if got := p.Delay(1000); got != 24*time.Hour {
+		t.Errorf("Delay(1000) = %s, want the 24h cap", got)
+	}
+	if got := p.Delay(0); got != time.Hour {
+		t.Errorf("Delay(0) = %s, want the first retry delay", got)
+	}
+}
@@ -20,6 +20,7 @@ import (
 // GPUDetector GPU 检测器
 type GPUDetector struct {
 	client        *client.Client
+	source        GPUSource
 	available     bool
 	cudaVersion   string
 	driverVersion string
@@ -31,6 +32,7 @@ type GPUDetector struct {
 // GPUInfo GPU 信息
 type GPUInfo struct {
 	Index       int     `json:"index"`
+	UUID        string  `json:"uuid"`
 	Name        string  `json:"name"`
 	MemoryTotal uint64  `json:"memory_total"`
 	MemoryUsed  uint64  `json:"memory_used"`
@@ -39,9 +41,12 @@ type GPUInfo struct {
 	PowerDraw   float64 `json:"power_draw"`
 }
 
-// NewGPUDetector 创建 GPU 检测器
-func NewGPUDetector(client *client.Client) *GPUDetector {
-	detector := &GPUDetector{client: client}
+// NewGPUDetector 创建 GPU 检测器，source 为 nil 时通过本机 nvidia-smi 查询
+func NewGPUDetector(client *client.Client, source GPUSource) *GPUDetector {
+	if source == nil {
+		source = NvidiaSMISource
+	}
+	detector := &GPUDetector{client: client, source: source}
 	detector.detect()
 	return detector
 }
@@ -95,36 +100,84 @@ func (d *GPUDetector) detectNvidiaRuntime() bool {
 
 // queryGPUInfo 查询 GPU 信息
 func (d *GPUDetector) queryGPUInfo() error {
-	output, err := exec.Command("nvidia-smi", "--query-gpu=index,name,memory.total,memory.used,utilization.gpu,temperature.gpu,power.draw", "--format=csv,noheader,nounits").Output()
+	gpus, err := d.queryGPUs()
 	if err != nil {
-		return fmt.Errorf("nvidia-smi failed: %w", err)
+		return err
 	}
 
-	lines := strings.Split(string(output), "\n")
-	var gpus []GPUInfo
+	d.gpuInfo = gpus
+	d.gpuCount = len(gpus)
+
+	if output, err := exec.Command("nvidia-smi", "--query-gpu=driver_version", "--format=csv,noheader,nounits").Output(); err == nil {
+		d.driverVersion = strings.TrimSpace(string(output))
+	}
+
+	if output, err := exec.Command("nvcc", "--version").Output(); err == nil {
+		re := regexp.MustCompile(`release (\d+\.\d+)`)
+		if matches := re.FindStringSubmatch(string(output)); len(matches) > 1 {
+			d.cudaVersion = matches[1]
+		}
+	}
+
+	return nil
+}
+
+// queryGPUs 通过设备来源查询全部 GPU 的当前状态
+func (d *GPUDetector) queryGPUs() ([]GPUInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	output, err := d.source(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGPUInfo(output), nil
+}
+
+// NvidiaSMISource 通过本机 nvidia-smi 查询 GPU，输出字段顺序为 gpuQueryFields
+func NvidiaSMISource(ctx context.Context) ([]byte, error) {
+	output, err := exec.CommandContext(ctx, "nvidia-smi", "--query-gpu="+gpuQueryFields, "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi failed: %w", err)
+	}
+	return output, nil
+}
+
+// gpuQueryFields nvidia-smi 查询的字段
+const gpuQueryFields = "index,uuid,name,memory.total,memory.used,utilization.gpu,temperature.gpu,power.draw"
 
-	for _, line := range lines {
+// ParseGPUInfo 解析 nvidia-smi --query-gpu=<gpuQueryFields> --format=csv,noheader,nounits 的输出，
+// 无法识别的行被忽略，不支持的数值（如 [N/A]）记为 0
+func ParseGPUInfo(output []byte) []GPUInfo {
+	var gpus []GPUInfo
+	for _, line := range strings.Split(string(output), "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
 
-		parts := strings.Split(line, ", ")
-		if len(parts) < 7 {
+		parts := strings.Split(line, ",")
+		if len(parts) < 8 {
 			continue
 		}
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
 
-		index, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
-		name := strings.TrimSpace(parts[1])
-		memTotal, _ := strconv.ParseUint(strings.TrimSpace(parts[2]), 10, 64)
-		memUsed, _ := strconv.ParseUint(strings.TrimSpace(parts[3]), 10, 64)
-		util, _ := strconv.ParseFloat(strings.TrimSpace(parts[4]), 64)
-		temp, _ := strconv.Atoi(strings.TrimSpace(parts[5]))
-		power, _ := strconv.ParseFloat(strings.TrimSpace(parts[6]), 64)
+		index, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		memTotal, _ := strconv.ParseUint(parts[3], 10, 64)
+		memUsed, _ := strconv.ParseUint(parts[4], 10, 64)
+		util, _ := strconv.ParseFloat(parts[5], 64)
+		temp, _ := strconv.Atoi(parts[6])
+		power, _ := strconv.ParseFloat(parts[7], 64)
 
 		gpus = append(gpus, GPUInfo{
 			Index:       index,
-			Name:        name,
+			UUID:        parts[1],
+			Name:        parts[2],
 			MemoryTotal: memTotal,
 			MemoryUsed:  memUsed,
 			Utilization: util,
@@ -133,21 +186,7 @@ func (d *GPUDetector) queryGPUInfo() error {
 		})
 	}
 
-	d.gpuInfo = gpus
-	d.gpuCount = len(gpus)
-
-	if output, err := exec.Command("nvidia-smi", "--query-gpu=driver_version", "--format=csv,noheader,nounits").Output(); err == nil {
-		d.driverVersion = strings.TrimSpace(string(output))
-	}
-
-	if output, err := exec.Command("nvcc", "--version").Output(); err == nil {
-		re := regexp.MustCompile(`release (\d+\.\d+)`)
-		if matches := re.FindStringSubmatch(string(output)); len(matches) > 1 {
-			d.cudaVersion = matches[1]
-		}
-	}
-
-	return nil
+	return gpus
 }
 
 // IsAvailable 检查 GPU 是否可用
@@ -159,6 +198,11 @@ func (d *GPUDetector) IsAvailable() bool {
 
 // GetDeviceRequests 获取 GPU 设备请求配置
 func (d *GPUDetector) GetDeviceRequests(gpuCount int) []container.DeviceRequest {
+	return d.GetDeviceRequestsFrom(0, gpuCount)
+}
+
+// GetDeviceRequestsFrom 获取从 offset 号 GPU 开始的连续 gpuCount 个设备的请求配置
+func (d *GPUDetector) GetDeviceRequestsFrom(offset, gpuCount int) []container.DeviceRequest {
 	if !d.IsAvailable() {
 		return nil
 	}
@@ -166,16 +210,19 @@ func (d *GPUDetector) GetDeviceRequests(gpuCount int) []container.DeviceRequest
 	if gpuCount > d.gpuCount {
 		gpuCount = d.gpuCount
 	}
+	if offset+gpuCount > d.gpuCount {
+		offset = d.gpuCount - gpuCount
+	}
 
 	deviceIDs := make([]string, gpuCount)
 	for i := 0; i < gpuCount; i++ {
-		deviceIDs[i] = strconv.Itoa(i)
+		deviceIDs[i] = strconv.Itoa(offset + i)
 	}
 
+	// 指定 DeviceIDs 时不能同时设置 Count
 	return []container.DeviceRequest{
 		{
 			Driver:       "nvidia",
-			Count:        gpuCount,
 			DeviceIDs:    deviceIDs,
 			Capabilities: [][]string{{"gpu"}},
 			Options: map[string]string{
@@ -207,22 +254,28 @@ func (d *GPUDetector) GetGPUInfo() []GPUInfo {
 	return result
 }
 
-// GetGPUStats 获取容器的 GPU 统计
-func (d *GPUDetector) GetGPUStats(containerID string) map[string]interface{} {
-	if !d.IsAvailable() {
+// GetGPUStats 获取指定 GPU 的当前用量
+func (d *GPUDetector) GetGPUStats(indices []int) []GPUInfo {
+	if !d.IsAvailable() || len(indices) == 0 {
 		return nil
 	}
 
-	output, err := exec.Command("nvidia-smi", "pmon", "-s", "um", "-c", "1", "-o", "T").Output()
+	gpus, err := d.queryGPUs()
 	if err != nil {
 		return nil
 	}
 
-	return map[string]interface{}{
-		"container_id": containerID,
-		"timestamp":    time.Now(),
-		"raw_output":   string(output),
+	wanted := make(map[int]bool, len(indices))
+	for _, index := range indices {
+		wanted[index] = true
+	}
+	var stats []GPUInfo
+	for _, gpu := range gpus {
+		if wanted[gpu.Index] {
+			stats = append(stats, gpu)
+		}
 	}
+	return stats
 }
 
 // GetRecommendedImage 获取推荐的 GPU 镜像
@@ -0,0 +1,290 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNoMatchingGPUs 宿主机上符合型号要求的 GPU 总数不足，任务永远无法调度
+	ErrNoMatchingGPUs = errors.New("not enough matching GPUs on host")
+	// ErrGPUsBusy 符合要求的空闲 GPU 暂时不足，需等待其他任务释放
+	ErrGPUsBusy = errors.New("not enough free GPUs")
+)
+
+// GPUSource 返回 nvidia-smi --query-gpu=<gpuQueryFields> --format=csv,noheader,nounits 格式的输出，
+// 生产环境使用 NvidiaSMISource，测试时可返回固定内容
+type GPUSource func(ctx context.Context) ([]byte, error)
+
+// GPUDevice 宿主机 GPU 及其占用情况
+type GPUDevice struct {
+	Index       int        `json:"index"`
+	UUID        string     `json:"uuid"`
+	Name        string     `json:"name"`
+	MemoryTotal uint64     `json:"memory_total"`
+	JobID       *uuid.UUID `json:"job_id,omitempty"`
+	Rank        *int       `json:"rank,omitempty"`
+	AllocatedAt *time.Time `json:"allocated_at,omitempty"`
+}
+
+// gpuAllocation 单个 GPU 的占用记录
+type gpuAllocation struct {
+	jobID uuid.UUID
+	rank  int
+	at    time.Time
+}
+
+// GPUAllocator 跟踪宿主机上的每个 GPU，为任务的各节点分配具体设备
+//
+// 分配只保存在内存中：服务重启后由 Reattach 根据容器标签恢复运行中任务的占用。
+type GPUAllocator struct {
+	source GPUSource
+
+	mu          sync.Mutex
+	devices     []GPUInfo
+	allocations map[int]*gpuAllocation // GPU 编号 -> 占用记录
+}
+
+// NewGPUAllocator 创建 GPU 分配器，需调用 Refresh 加载设备清单
+func NewGPUAllocator(source GPUSource) *GPUAllocator {
+	return &GPUAllocator{
+		source:      source,
+		allocations: make(map[int]*gpuAllocation),
+	}
+}
+
+// Refresh 重新查询设备清单，已有的分配保持不变
+func (a *GPUAllocator) Refresh(ctx context.Context) error {
+	output, err := a.source(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query GPUs: %w", err)
+	}
+
+	devices := ParseGPUInfo(output)
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Index < devices[j].Index })
+
+	a.mu.Lock()
+	a.devices = devices
+	a.mu.Unlock()
+	return nil
+}
+
+// Count 设备总数
+func (a *GPUAllocator) Count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.devices)
+}
+
+// CountMatching 型号符合 gpuType 的设备总数，不论是否被占用
+func (a *GPUAllocator) CountMatching(gpuType string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	count := 0
+	for _, device := range a.devices {
+		if MatchesGPUType(device.Name, gpuType) {
+			count++
+		}
+	}
+	return count
+}
+
+// Allocate 为任务的 nodes 个节点各分配 perNode 个符合 gpuType 的 GPU，返回按节点顺序排列的设备
+//
+// 任务已持有同样规模的分配时直接返回，因此调度器派发时预留的设备在执行器启动时可以复用。
+// 符合要求的设备总数不足时返回 ErrNoMatchingGPUs，暂时被占用时返回 ErrGPUsBusy。
+func (a *GPUAllocator) Allocate(jobID uuid.UUID, nodes, perNode int, gpuType string) ([][]GPUInfo, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if existing := a.allocationLocked(jobID); len(existing) == nodes && allSized(existing, perNode) {
+		return existing, nil
+	}
+	a.releaseLocked(jobID)
+
+	need := nodes * perNode
+	var matching, free []GPUInfo
+	for _, device := range a.devices {
+		if !MatchesGPUType(device.Name, gpuType) {
+			continue
+		}
+		matching = append(matching, device)
+		if _, taken := a.allocations[device.Index]; !taken {
+			free = append(free, device)
+		}
+	}
+	if len(matching) < need {
+		return nil, fmt.Errorf("%w: requested %d %s, host has %d", ErrNoMatchingGPUs, need, gpuLabel(gpuType), len(matching))
+	}
+	if len(free) < need {
+		return nil, fmt.Errorf("%w: requested %d %s, %d free", ErrGPUsBusy, need, gpuLabel(gpuType), len(free))
+	}
+
+	now := time.Now()
+	result := make([][]GPUInfo, nodes)
+	for rank := 0; rank < nodes; rank++ {
+		result[rank] = free[rank*perNode : (rank+1)*perNode]
+		for _, device := range result[rank] {
+			a.allocations[device.Index] = &gpuAllocation{jobID: jobID, rank: rank, at: now}
+		}
+	}
+	return result, nil
+}
+
+// Restore 恢复服务重启前节点已占用的 GPU
+func (a *GPUAllocator) Restore(jobID uuid.UUID, rank int, indices []int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	for _, index := range indices {
+		a.allocations[index] = &gpuAllocation{jobID: jobID, rank: rank, at: now}
+	}
+}
+
+// Release 释放任务占用的全部 GPU
+func (a *GPUAllocator) Release(jobID uuid.UUID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.releaseLocked(jobID)
+}
+
+// List 列出全部 GPU 及其占用的任务
+func (a *GPUAllocator) List() []GPUDevice {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	devices := make([]GPUDevice, 0, len(a.devices))
+	for _, info := range a.devices {
+		device := GPUDevice{
+			Index:       info.Index,
+			UUID:        info.UUID,
+			Name:        info.Name,
+			MemoryTotal: info.MemoryTotal,
+		}
+		if alloc, ok := a.allocations[info.Index]; ok {
+			jobID, rank, at := alloc.jobID, alloc.rank, alloc.at
+			device.JobID = &jobID
+			device.Rank = &rank
+			device.AllocatedAt = &at
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+// allocationLocked 按节点顺序返回任务当前持有的设备
+func (a *GPUAllocator) allocationLocked(jobID uuid.UUID) [][]GPUInfo {
+	var result [][]GPUInfo
+	for _, device := range a.devices {
+		alloc, ok := a.allocations[device.Index]
+		if !ok || alloc.jobID != jobID {
+			continue
+		}
+		for len(result) <= alloc.rank {
+			result = append(result, nil)
+		}
+		result[alloc.rank] = append(result[alloc.rank], device)
+	}
+	return result
+}
+
+// releaseLocked 释放任务占用的 GPU
+func (a *GPUAllocator) releaseLocked(jobID uuid.UUID) {
+	for index, alloc := range a.allocations {
+		if alloc.jobID == jobID {
+			delete(a.allocations, index)
+		}
+	}
+}
+
+// allSized 检查每个节点的设备数都为 size
+func allSized(nodes [][]GPUInfo, size int) bool {
+	for _, devices := range nodes {
+		if len(devices) != size {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesGPUType 检查 GPU 型号是否符合任务的 gpu_type，gpuType 为空时匹配任意型号
+//
+// 比较时忽略大小写，并将空格和下划线视为连字符，因此 "A100"、"a100-sxm4" 和节点标签形式的
+// "NVIDIA-A100-SXM4-80GB" 都能匹配 "NVIDIA A100-SXM4-80GB"。
+func MatchesGPUType(name, gpuType string) bool {
+	if gpuType == "" {
+		return true
+	}
+	return strings.Contains(normalizeGPUName(name), normalizeGPUName(gpuType))
+}
+
+// normalizeGPUName 统一 GPU 型号的大小写和分隔符
+func normalizeGPUName(name string) string {
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// gpuLabel 错误信息中的 GPU 描述
+func gpuLabel(gpuType string) string {
+	if gpuType == "" {
+		return "GPU(s)"
+	}
+	return gpuType + " GPU(s)"
+}
+
+// gpuDeviceRequests 为指定的 GPU 生成设备请求
+func gpuDeviceRequests(devices []GPUInfo) []container.DeviceRequest {
+	// 指定 DeviceIDs 时不能同时设置 Count
+	return []container.DeviceRequest{
+		{
+			Driver:       "nvidia",
+			DeviceIDs:    gpuIndexList(devices),
+			Capabilities: [][]string{{"gpu"}},
+			Options: map[string]string{
+				"nvidia-driver-capabilities": "compute,utility",
+			},
+		},
+	}
+}
+
+// gpuIndexList GPU 编号列表
+func gpuIndexList(devices []GPUInfo) []string {
+	ids := make([]string, len(devices))
+	for i, device := range devices {
+		ids[i] = strconv.Itoa(device.Index)
+	}
+	return ids
+}
+
+// gpuVisibleDevices NVIDIA_VISIBLE_DEVICES 的取值，优先使用 UUID
+func gpuVisibleDevices(devices []GPUInfo) string {
+	ids := make([]string, len(devices))
+	for i, device := range devices {
+		ids[i] = device.UUID
+		if ids[i] == "" {
+			ids[i] = strconv.Itoa(device.Index)
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// parseGPUIndices 解析容器标签中记录的 GPU 编号
+func parseGPUIndices(value string) []int {
+	var indices []int
+	for _, id := range strings.Split(value, ",") {
+		if index, err := strconv.Atoi(strings.TrimSpace(id)); err == nil {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
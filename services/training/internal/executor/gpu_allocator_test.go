@@ -0,0 +1,226 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testGPUOutput 4 块 A100 和 2 块 T4 的 nvidia-smi 输出，顺序故意打乱
+const testGPUOutput = `4, GPU-t4-0, Tesla T4, 15360, 0, 0, 40, 30.5
+0, GPU-a100-0, NVIDIA A100-SXM4-80GB, 81920, 1024, 10, 45, 80.1
+1, GPU-a100-1, NVIDIA A100-SXM4-80GB, 81920, 0, 0, 40, 60.0
+2, GPU-a100-2, NVIDIA A100-SXM4-80GB, 81920, 0, 0, 41, 61.0
+3, GPU-a100-3, NVIDIA A100-SXM4-80GB, 81920, 0, 0, 42, 62.0
+5, GPU-t4-1, Tesla T4, 15360, [N/A], [N/A], 39, [N/A]
+`
+
+// fixedGPUSource 返回固定输出的设备来源
+func fixedGPUSource(output string) GPUSource {
+	return func(ctx context.Context) ([]byte, error) {
+		return []byte(output), nil
+	}
+}
+
+func newTestAllocator(t *testing.T) *GPUAllocator {
+	t.Helper()
+	allocator := NewGPUAllocator(fixedGPUSource(testGPUOutput))
+	if err := allocator.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	return allocator
+}
+
+// indices 按节点返回分配到的 GPU 编号
+func indices(nodes [][]GPUInfo) [][]int {
+	result := make([][]int, len(nodes))
+	for i, devices := range nodes {
+		for _, device := range devices {
+			result[i] = append(result[i], device.Index)
+		}
+	}
+	return result
+}
+
+func TestGPUAllocatorRefresh(t *testing.T) {
+	allocator := newTestAllocator(t)
+
+	if got := allocator.Count(); got != 6 {
+		t.Errorf("Count() = %d, want 6", got)
+	}
+	if got := allocator.CountMatching("a100"); got != 4 {
+		t.Errorf("CountMatching(a100) = %d, want 4", got)
+	}
+	if got := allocator.CountMatching("NVIDIA-A100-SXM4-80GB"); got != 4 {
+		t.Errorf("CountMatching(node label form) = %d, want 4", got)
+	}
+	if got := allocator.CountMatching("t4"); got != 2 {
+		t.Errorf("CountMatching(t4) = %d, want 2", got)
+	}
+	if got := allocator.CountMatching("h100"); got != 0 {
+		t.Errorf("CountMatching(h100) = %d, want 0", got)
+	}
+
+	devices := allocator.List()
+	for i, device := range devices {
+		if device.Index != i {
+			t.Fatalf("List()[%d].Index = %d, want devices sorted by index", i, device.Index)
+		}
+	}
+}
+
+func TestGPUAllocatorRefreshError(t *testing.T) {
+	allocator := NewGPUAllocator(func(ctx context.Context) ([]byte, error) {
+		return nil, errors.New("nvidia-smi not found")
+	})
+	if err := allocator.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() succeeded, want error")
+	}
+	if got := allocator.Count(); got != 0 {
+		t.Errorf("Count() = %d, want 0", got)
+	}
+}
+
+func TestGPUAllocatorReserveAndRelease(t *testing.T) {
+	allocator := newTestAllocator(t)
+	jobA, jobB := uuid.New(), uuid.New()
+
+	// 分布式任务：2 个节点各 2 块 A100
+	got, err := allocator.Allocate(jobA, 2, 2, "a100")
+	if err != nil {
+		t.Fatalf("Allocate(jobA) error = %v", err)
+	}
+	if want := [][]int{{0, 1}, {2, 3}}; !equalIndices(indices(got), want) {
+		t.Errorf("Allocate(jobA) = %v, want %v", indices(got), want)
+	}
+
+	// 同样规模的再次分配复用已预留的设备
+	again, err := allocator.Allocate(jobA, 2, 2, "a100")
+	if err != nil {
+		t.Fatalf("Allocate(jobA) again error = %v", err)
+	}
+	if !equalIndices(indices(again), indices(got)) {
+		t.Errorf("Allocate(jobA) again = %v, want %v", indices(again), indices(got))
+	}
+
+	// 任意型号的任务只能使用剩余的 T4
+	other, err := allocator.Allocate(jobB, 1, 2, "")
+	if err != nil {
+		t.Fatalf("Allocate(jobB) error = %v", err)
+	}
+	if want := [][]int{{4, 5}}; !equalIndices(indices(other), want) {
+		t.Errorf("Allocate(jobB) = %v, want %v", indices(other), want)
+	}
+
+	for _, device := range allocator.List() {
+		if device.JobID == nil {
+			t.Errorf("GPU %d is free, want all allocated", device.Index)
+		}
+	}
+
+	allocator.Release(jobA)
+	for _, device := range allocator.List() {
+		if device.Index < 4 && device.JobID != nil {
+			t.Errorf("GPU %d still allocated to %s after Release", device.Index, device.JobID)
+		}
+		if device.Index >= 4 && (device.JobID == nil || *device.JobID != jobB) {
+			t.Errorf("GPU %d lost its allocation to jobB", device.Index)
+		}
+	}
+}
+
+func TestGPUAllocatorExhaustion(t *testing.T) {
+	allocator := newTestAllocator(t)
+	jobA, jobB := uuid.New(), uuid.New()
+
+	// 宿主机上符合型号的设备总数不足
+	if _, err := allocator.Allocate(jobA, 1, 5, "a100"); !errors.Is(err, ErrNoMatchingGPUs) {
+		t.Errorf("Allocate(5 a100) error = %v, want ErrNoMatchingGPUs", err)
+	}
+	if _, err := allocator.Allocate(jobA, 1, 1, "h100"); !errors.Is(err, ErrNoMatchingGPUs) {
+		t.Errorf("Allocate(h100) error = %v, want ErrNoMatchingGPUs", err)
+	}
+
+	// 设备被占用时需要等待
+	if _, err := allocator.Allocate(jobA, 1, 3, "a100"); err != nil {
+		t.Fatalf("Allocate(3 a100) error = %v", err)
+	}
+	if _, err := allocator.Allocate(jobB, 1, 2, "a100"); !errors.Is(err, ErrGPUsBusy) {
+		t.Errorf("Allocate(2 a100) error = %v, want ErrGPUsBusy", err)
+	}
+
+	// 释放后可以分配
+	allocator.Release(jobA)
+	if _, err := allocator.Allocate(jobB, 1, 2, "a100"); err != nil {
+		t.Errorf("Allocate(2 a100) after Release error = %v", err)
+	}
+}
+
+func TestGPUAllocatorResize(t *testing.T) {
+	allocator := newTestAllocator(t)
+	job := uuid.New()
+
+	if _, err := allocator.Allocate(job, 1, 2, "a100"); err != nil {
+		t.Fatalf("Allocate(2) error = %v", err)
+	}
+	// 规模不同时释放原有分配后重新分配
+	got, err := allocator.Allocate(job, 1, 4, "a100")
+	if err != nil {
+		t.Fatalf("Allocate(4) error = %v", err)
+	}
+	if want := [][]int{{0, 1, 2, 3}}; !equalIndices(indices(got), want) {
+		t.Errorf("Allocate(4) = %v, want %v", indices(got), want)
+	}
+}
+
+func TestGPUAllocatorRestore(t *testing.T) {
+	allocator := newTestAllocator(t)
+	job := uuid.New()
+
+	allocator.Restore(job, 0, parseGPUIndices("1,3"))
+	if _, err := allocator.Allocate(uuid.New(), 1, 3, "a100"); !errors.Is(err, ErrGPUsBusy) {
+		t.Errorf("Allocate(3 a100) error = %v, want ErrGPUsBusy", err)
+	}
+
+	got, err := allocator.Allocate(job, 1, 2, "a100")
+	if err != nil {
+		t.Fatalf("Allocate(restored job) error = %v", err)
+	}
+	if want := [][]int{{1, 3}}; !equalIndices(indices(got), want) {
+		t.Errorf("Allocate(restored job) = %v, want %v", indices(got), want)
+	}
+}
+
+func TestGPUDetectorUsesSource(t *testing.T) {
+	detector := &GPUDetector{source: fixedGPUSource(testGPUOutput), available: true}
+
+	stats := detector.GetGPUStats([]int{1, 5})
+	if len(stats) != 2 {
+		t.Fatalf("GetGPUStats() returned %d GPUs, want 2", len(stats))
+	}
+	if stats[0].Index != 1 || stats[0].PowerDraw != 60.0 {
+		t.Errorf("stats[0] = %+v", stats[0])
+	}
+	if stats[1].Index != 5 || stats[1].MemoryUsed != 0 || stats[1].Utilization != 0 {
+		t.Errorf("stats[1] = %+v, want unsupported values as 0", stats[1])
+	}
+}
+
+func equalIndices(a, b [][]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,1260 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// trainerContainerName Pod 中训练容器的名称
+const trainerContainerName = "trainer"
+
+// datasetInitContainerName 下载数据集的 init 容器名称
+const datasetInitContainerName = "fetch-dataset"
+
+// MinIO 凭据在 Job 专用 Secret 中的键
+const (
+	minioAccessKeyKey = "minio-access-key"
+	minioSecretKeyKey = "minio-secret-key"
+)
+
+// imagePullFailures 表示镜像或容器配置无法恢复的等待原因，出现时直接判定任务失败
+var imagePullFailures = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+}
+
+// KubernetesConfig Kubernetes 执行器配置
+type KubernetesConfig struct {
+	Namespace        string
+	ServiceAccount   string
+	NodeSelector     map[string]string
+	Tolerations      []corev1.Toleration
+	ImagePullSecrets []string
+	GPUResourceName  string        // GPU 扩展资源名，默认 nvidia.com/gpu
+	GPUTypeLabel     string        // 按 gpu_type 选择节点时使用的节点标签
+	DataPVC          string        // 数据集所在的 PVC，dataset_path 作为其子路径挂载
+	OutputPVC        string        // 输出目录所在的 PVC，output_path 作为其子路径挂载
+	PollInterval     time.Duration // Pod 状态轮询间隔
+
+	// 未配置 DataPVC 时通过 init 容器从 MinIO 下载数据集，dataset_path 为 bucket/prefix
+	MinIOImage     string
+	MinIOEndpoint  string
+	MinIOAccessKey string
+	MinIOSecretKey string
+	MinIOUseSSL    bool
+}
+
+// KubernetesExecutor Kubernetes 执行器实现，每次尝试创建一个 batch/v1 Job
+type KubernetesExecutor struct {
+	clientset    kubernetes.Interface
+	logRepo      repository.LogRepository
+	cfg          KubernetesConfig
+	jobs         map[uuid.UUID]*JobProcess
+	mu           sync.RWMutex
+	errorHandler *ErrorHandler
+	metricParser *MetricParser
+	exitHandler  ExitHandler
+
+	metricsHandler  MetricsHandler
+	metricsEndpoint *MetricsEndpoint
+	secrets         secrets.Manager
+}
+
+// NewKubernetesClientset 创建 Kubernetes 客户端，kubeconfig 为空时使用集群内配置
+func NewKubernetesClientset(kubeconfig string) (kubernetes.Interface, error) {
+	var (
+		restConfig *rest.Config
+		err        error
+	)
+	if kubeconfig == "" {
+		restConfig, err = rest.InClusterConfig()
+	} else {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubernetes config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	return clientset, nil
+}
+
+// NewKubernetesExecutor 创建 Kubernetes 执行器
+func NewKubernetesExecutor(clientset kubernetes.Interface, logRepo repository.LogRepository, cfg KubernetesConfig) *KubernetesExecutor {
+	if cfg.Namespace == "" {
+		cfg.Namespace = "default"
+	}
+	if cfg.GPUResourceName == "" {
+		cfg.GPUResourceName = "nvidia.com/gpu"
+	}
+	if cfg.MinIOImage == "" {
+		cfg.MinIOImage = "minio/mc:latest"
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+
+	return &KubernetesExecutor{
+		clientset:    clientset,
+		logRepo:      logRepo,
+		cfg:          cfg,
+		jobs:         make(map[uuid.UUID]*JobProcess),
+		errorHandler: NewErrorHandler(),
+		metricParser: NewMetricParser(),
+	}
+}
+
+// SetExitHandler 设置任务退出回调
+func (e *KubernetesExecutor) SetExitHandler(handler ExitHandler) {
+	e.exitHandler = handler
+}
+
+// SetMetricsHandler 设置指标回调
+func (e *KubernetesExecutor) SetMetricsHandler(handler MetricsHandler) {
+	e.metricsHandler = handler
+}
+
+// SetMetricsEndpoint 设置训练容器上报指标的接口
+func (e *KubernetesExecutor) SetMetricsEndpoint(endpoint *MetricsEndpoint) {
+	e.metricsEndpoint = endpoint
+}
+
+// SetSecretManager 设置密钥存储，任务引用的密钥在启动时解密并写入 Kubernetes Secret
+func (e *KubernetesExecutor) SetSecretManager(manager secrets.Manager) {
+	e.secrets = manager
+}
+
+// Start 创建 Kubernetes Job 并开始跟踪其 Pod
+func (e *KubernetesExecutor) Start(ctx context.Context, job *domain.TrainingJob) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.jobs[job.ID]; exists {
+		return fmt.Errorf("job %s is already running", job.ID)
+	}
+	if job.Distributed.Nodes() > 1 {
+		return fmt.Errorf("multi-node distributed training is not supported by the kubernetes executor")
+	}
+
+	resolved, err := injectSecrets(ctx, e.secrets, job)
+	if err != nil {
+		return err
+	}
+
+	// 密钥和 MinIO 凭据写入 Job 专用的 Secret，Pod 通过 secretKeyRef 和 Secret 卷引用，Job 定义中不含明文
+	spec := e.buildJob(job, resolved)
+	secret := e.buildSecret(spec, resolved)
+	if secret != nil {
+		if err := e.applySecret(ctx, secret); err != nil {
+			return err
+		}
+	}
+
+	created, err := e.clientset.BatchV1().Jobs(e.cfg.Namespace).Create(ctx, spec, metav1.CreateOptions{})
+	if err != nil {
+		if secret != nil {
+			e.deleteSecret(ctx, secret.Name)
+		}
+		return fmt.Errorf("failed to create kubernetes job: %w", err)
+	}
+	if secret != nil {
+		e.adoptSecret(ctx, secret.Name, created)
+	}
+
+	logger.Info("Kubernetes job created",
+		zap.String("job_id", job.ID.String()),
+		zap.String("namespace", e.cfg.Namespace),
+		zap.String("k8s_job", created.Name),
+	)
+
+	execCtx, cancel := context.WithCancel(context.Background())
+	process := &JobProcess{
+		JobID:       job.ID,
+		Attempt:     job.Attempt,
+		ContainerID: created.Name,
+		CancelFunc:  cancel,
+		StartedAt:   time.Now(),
+		RetryCount:  job.Attempt - 1,
+		logsDone:    make(chan struct{}),
+		redactor:    secrets.NewRedactor(resolved),
+	}
+	e.jobs[job.ID] = process
+
+	job.ContainerID = created.Name
+	job.ContainerName = created.Name
+
+	go e.monitorJob(execCtx, job, process, time.Time{})
+
+	return nil
+}
+
+// buildJob 构建任务当前尝试的 batch/v1 Job，resolved 为需要注入的密钥
+func (e *KubernetesExecutor) buildJob(job *domain.TrainingJob, resolved []secrets.Resolved) *batchv1.Job {
+	name := containerNameFor(job)
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "aitip-training",
+		"aitip.job.id":                 job.ID.String(),
+		"aitip.job.attempt":            strconv.Itoa(job.Attempt),
+		"aitip.project.id":             job.ProjectID.String(),
+		"aitip.framework":              string(job.Framework),
+	}
+	annotations := map[string]string{
+		"aitip.job.name": job.Name,
+	}
+
+	env := append(jobEnv(job), metricsEnv(job, e.metricsEndpoint)...)
+	if job.Distributed.IsDistributed() {
+		// 单个 Pod 内的多进程训练，rendezvous 地址为本机
+		env = append(env, distributedEnv(job, 0, "127.0.0.1")...)
+	}
+
+	trainer := corev1.Container{
+		Name:       trainerContainerName,
+		Image:      job.Image,
+		Command:    job.Command,
+		Env:        toEnvVars(env),
+		WorkingDir: "/workspace",
+		Resources:  e.buildResources(job),
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/data", ReadOnly: true},
+			{Name: "output", MountPath: "/output"},
+			{Name: "dshm", MountPath: "/dev/shm"},
+		},
+	}
+
+	shmSize := resource.MustParse("2Gi")
+	volumes := []corev1.Volume{
+		e.outputVolume(job),
+		{
+			Name: "dshm",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &shmSize},
+			},
+		},
+	}
+
+	// 对象存储中的数据集（dataset_id）总是由 init 容器下载，提供了 DATASET_URL 时由训练脚本自行下载
+	var initContainers []corev1.Container
+	_, _, stored := job.StoredDataset()
+	if e.cfg.DataPVC != "" && !stored {
+		volumes = append(volumes, pvcVolume("data", e.cfg.DataPVC, true))
+		trainer.VolumeMounts[0].SubPath = strings.TrimPrefix(job.DatasetPath, "/")
+	} else {
+		volumes = append(volumes, corev1.Volume{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		if job.DatasetPath != "" && job.DatasetURL == "" {
+			initContainers = append(initContainers, e.datasetInitContainer(job, secretNameFor(name)))
+		}
+	}
+	if e.cfg.OutputPVC != "" {
+		trainer.VolumeMounts[1].SubPath = strings.TrimPrefix(job.OutputPath, "/")
+	}
+
+	// 恢复训练的检查点只能来自输出目录，其他位置的检查点在集群内不可见
+	if job.ResumeFrom != "" {
+		if resumePath := ContainerOutputPath(job.OutputPath, job.ResumeFrom); resumePath != "" && e.cfg.OutputPVC != "" {
+			trainer.Env = append(trainer.Env, corev1.EnvVar{Name: "RESUME_FROM_CHECKPOINT", Value: resumePath})
+		} else {
+			logger.Warn("Checkpoint is not reachable from the cluster, starting without it",
+				zap.String("job_id", job.ID.String()),
+				zap.String("checkpoint", job.ResumeFrom),
+			)
+		}
+	}
+
+	if len(resolved) > 0 {
+		volumes = append(volumes, mountSecrets(&trainer, secretNameFor(name), resolved)...)
+	}
+
+	if job.Framework == domain.FrameworkTensorFlow {
+		trainer.Ports = []corev1.ContainerPort{{Name: "tensorboard", ContainerPort: 6006}}
+	}
+
+	nodeSelector := make(map[string]string, len(e.cfg.NodeSelector)+1)
+	for key, value := range e.cfg.NodeSelector {
+		nodeSelector[key] = value
+	}
+	if job.GPUType != "" && e.cfg.GPUTypeLabel != "" {
+		nodeSelector[e.cfg.GPUTypeLabel] = job.GPUType
+	}
+
+	var pullSecrets []corev1.LocalObjectReference
+	for _, secret := range e.cfg.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+
+	// 重试由调度器按任务重试策略处理，Job 本身不重试
+	backoffLimit := int32(0)
+	ttl := int32(time.Hour.Seconds())
+	var deadline *int64
+	if job.TimeoutHours > 0 {
+		// 之前的尝试已经用掉的运行时间不再计入本次尝试
+		seconds := max(int64(job.TimeoutHours)*3600-job.RunSeconds, 1)
+		deadline = &seconds
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   e.cfg.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   deadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: e.cfg.ServiceAccount,
+					NodeSelector:       nodeSelector,
+					Tolerations:        e.cfg.Tolerations,
+					ImagePullSecrets:   pullSecrets,
+					InitContainers:     initContainers,
+					Containers:         []corev1.Container{trainer},
+					Volumes:            volumes,
+				},
+			},
+		},
+	}
+}
+
+// buildResources 构建训练容器的资源请求与限制
+func (e *KubernetesExecutor) buildResources(job *domain.TrainingJob) corev1.ResourceRequirements {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{},
+		Limits:   corev1.ResourceList{},
+	}
+
+	if job.CPUCount > 0 {
+		cpu := *resource.NewQuantity(int64(job.CPUCount), resource.DecimalSI)
+		resources.Requests[corev1.ResourceCPU] = cpu
+		resources.Limits[corev1.ResourceCPU] = cpu
+	}
+	if job.MemoryGB > 0 {
+		memory := *resource.NewQuantity(int64(job.MemoryGB)*1024*1024*1024, resource.BinarySI)
+		resources.Requests[corev1.ResourceMemory] = memory
+		resources.Limits[corev1.ResourceMemory] = memory
+	}
+	if job.GPUCount > 0 {
+		// 扩展资源只能设置 limits，requests 会自动取相同的值
+		resources.Limits[corev1.ResourceName(e.cfg.GPUResourceName)] = *resource.NewQuantity(int64(job.GPUCount), resource.DecimalSI)
+	}
+
+	return resources
+}
+
+// outputVolume 输出目录卷，未配置 PVC 时为临时目录（Pod 删除后输出丢失）
+func (e *KubernetesExecutor) outputVolume(job *domain.TrainingJob) corev1.Volume {
+	if e.cfg.OutputPVC != "" {
+		return pvcVolume("output", e.cfg.OutputPVC, false)
+	}
+
+	logger.Warn("No output PVC configured, training output will not be persisted", zap.String("job_id", job.ID.String()))
+	return corev1.Volume{
+		Name:         "output",
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+}
+
+// datasetInitContainer 从 MinIO 下载数据集到 /data 的 init 容器，MinIO 凭据从 secretName 引用
+func (e *KubernetesExecutor) datasetInitContainer(job *domain.TrainingJob, secretName string) corev1.Container {
+	scheme := "http"
+	if e.cfg.MinIOUseSSL {
+		scheme = "https"
+	}
+
+	source := strings.TrimPrefix(job.DatasetPath, "s3://")
+	source = strings.TrimPrefix(source, "minio://")
+	source = path.Clean("src/" + strings.TrimPrefix(source, "/"))
+
+	script := `mc alias set src "$MINIO_URL" "$MINIO_ACCESS_KEY" "$MINIO_SECRET_KEY" >/dev/null && ` +
+		`mc cp --recursive "$DATASET_SOURCE" /data/`
+	if _, _, stored := job.StoredDataset(); stored {
+		// 数据服务中的数据集是单个文件，归档解压后删除
+		script += ` && cd /data && for f in *; do case "$f" in ` +
+			`*.tar.gz|*.tgz) tar -xzf "$f" && rm "$f" ;; ` +
+			`*.tar) tar -xf "$f" && rm "$f" ;; ` +
+			`*.zip) unzip -q "$f" && rm "$f" ;; ` +
+			`*.gz) gunzip "$f" ;; ` +
+			`esac || exit 1; done`
+	}
+
+	return corev1.Container{
+		Name:    datasetInitContainerName,
+		Image:   e.cfg.MinIOImage,
+		Command: []string{"sh", "-c", script},
+		Env: []corev1.EnvVar{
+			{Name: "MINIO_URL", Value: fmt.Sprintf("%s://%s", scheme, e.cfg.MinIOEndpoint)},
+			secretEnvVar("MINIO_ACCESS_KEY", secretName, minioAccessKeyKey),
+			secretEnvVar("MINIO_SECRET_KEY", secretName, minioSecretKeyKey),
+			{Name: "DATASET_SOURCE", Value: source},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/data"},
+		},
+	}
+}
+
+// pvcVolume 构建 PVC 卷
+func pvcVolume(name, claim string, readOnly bool) corev1.Volume {
+	return corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claim,
+				ReadOnly:  readOnly,
+			},
+		},
+	}
+}
+
+// toEnvVars 将 KEY=VALUE 形式的环境变量转换为 Kubernetes 格式，重复的键以后出现的为准
+func toEnvVars(env []string) []corev1.EnvVar {
+	index := make(map[string]int, len(env))
+	vars := make([]corev1.EnvVar, 0, len(env))
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		if i, ok := index[key]; ok {
+			vars[i].Value = value
+			continue
+		}
+		index[key] = len(vars)
+		vars = append(vars, corev1.EnvVar{Name: key, Value: value})
+	}
+	return vars
+}
+
+// secretNameFor 任务尝试专用的 Secret 名称
+func secretNameFor(jobName string) string {
+	return jobName + "-secrets"
+}
+
+// secretEnvVar 从 Secret 的 key 读取值的环境变量
+func secretEnvVar(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// secretKey 密钥在 Secret 中的键，按引用顺序编号，避免密钥名不符合 Secret 键的格式
+func secretKey(i int) string {
+	return "s" + strconv.Itoa(i)
+}
+
+// mountSecrets 将密钥以 secretKeyRef 环境变量或 Secret 卷文件的形式引用到容器中，返回需要添加的卷
+func mountSecrets(container *corev1.Container, secretName string, resolved []secrets.Resolved) []corev1.Volume {
+	env := make([]corev1.EnvVar, 0, len(container.Env)+len(resolved))
+	overridden := make(map[string]bool, len(resolved))
+	for _, secret := range resolved {
+		if name := secret.EnvName(); name != "" {
+			overridden[name] = true
+		}
+	}
+	for _, v := range container.Env {
+		if !overridden[v.Name] {
+			env = append(env, v)
+		}
+	}
+
+	hasFiles := false
+	for i, secret := range resolved {
+		if name := secret.EnvName(); name != "" {
+			env = append(env, secretEnvVar(name, secretName, secretKey(i)))
+		}
+		if secret.Path != "" {
+			hasFiles = true
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      "secrets",
+				MountPath: secret.Path,
+				SubPath:   secretKey(i),
+				ReadOnly:  true,
+			})
+		}
+	}
+	container.Env = env
+
+	if !hasFiles {
+		return nil
+	}
+	mode := int32(0444)
+	return []corev1.Volume{{
+		Name: "secrets",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: secretName, DefaultMode: &mode},
+		},
+	}}
+}
+
+// buildSecret 构建 Job 引用的 Secret，包含任务的密钥和下载数据集用的 MinIO 凭据，都不需要时返回 nil
+func (e *KubernetesExecutor) buildSecret(spec *batchv1.Job, resolved []secrets.Resolved) *corev1.Secret {
+	fetchesDataset := false
+	for _, c := range spec.Spec.Template.Spec.InitContainers {
+		if c.Name == datasetInitContainerName {
+			fetchesDataset = true
+		}
+	}
+	if len(resolved) == 0 && !fetchesDataset {
+		return nil
+	}
+	data := make(map[string][]byte, len(resolved)+2)
+	for i, secret := range resolved {
+		data[secretKey(i)] = []byte(secret.Value)
+	}
+	if fetchesDataset {
+		data[minioAccessKeyKey] = []byte(e.cfg.MinIOAccessKey)
+		data[minioSecretKeyKey] = []byte(e.cfg.MinIOSecretKey)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretNameFor(spec.Name),
+			Namespace: e.cfg.Namespace,
+			Labels:    spec.Labels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// applySecret 创建 Secret，同名 Secret 已存在时（上次创建 Job 失败残留）覆盖
+func (e *KubernetesExecutor) applySecret(ctx context.Context, secret *corev1.Secret) error {
+	client := e.clientset.CoreV1().Secrets(e.cfg.Namespace)
+	_, err := client.Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = client.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes secret: %w", err)
+	}
+	return nil
+}
+
+// adoptSecret 将 Secret 的所有者设为 Job，删除 Job 时由垃圾回收一并删除
+func (e *KubernetesExecutor) adoptSecret(ctx context.Context, name string, owner *batchv1.Job) {
+	client := e.clientset.CoreV1().Secrets(e.cfg.Namespace)
+	secret, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		secret.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(owner, batchv1.SchemeGroupVersion.WithKind("Job")),
+		}
+		_, err = client.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		logger.Warn("Failed to set secret owner", zap.String("secret", name), zap.Error(err))
+	}
+}
+
+// deleteSecret 删除 Secret，不存在时忽略
+func (e *KubernetesExecutor) deleteSecret(ctx context.Context, name string) {
+	err := e.clientset.CoreV1().Secrets(e.cfg.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Warn("Failed to delete kubernetes secret", zap.String("secret", name), zap.Error(err))
+	}
+}
+
+// monitorJob 轮询任务 Pod 的状态，Pod 启动后开始收集日志，结束后上报退出事件
+func (e *KubernetesExecutor) monitorJob(ctx context.Context, job *domain.TrainingJob, process *JobProcess, since time.Time) {
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+
+	logsStarted := false
+	startLogs := func(podName string) {
+		if logsStarted {
+			return
+		}
+		logsStarted = true
+		go e.collectLogs(ctx, process, podName, since)
+	}
+
+	for {
+		// 暂停导致的退出由 Pause 处理
+		if process.pausing.Load() {
+			return
+		}
+
+		pod, err := e.currentPod(ctx, process)
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("Failed to get training pod", zap.String("job_id", job.ID.String()), zap.Error(err))
+		}
+
+		if pod != nil {
+			if reason, message := podWaitingFailure(pod); reason != "" {
+				if ctx.Err() != nil {
+					return
+				}
+				e.handleJobFailure(job, process, fmt.Errorf("%s: %s", reason, message))
+				return
+			}
+
+			switch pod.Status.Phase {
+			case corev1.PodRunning:
+				startLogs(pod.Name)
+			case corev1.PodSucceeded, corev1.PodFailed:
+				startLogs(pod.Name)
+				if ctx.Err() != nil {
+					return
+				}
+				e.handlePodExit(job, process, pod)
+				return
+			}
+		} else if err == nil {
+			// Job 在创建 Pod 之前失败（例如超出 activeDeadlineSeconds）
+			if failed, message := e.jobFailure(ctx, process); failed {
+				if ctx.Err() != nil {
+					return
+				}
+				e.handleJobFailure(job, process, fmt.Errorf("%s", message))
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// currentPod 获取任务当前尝试的 Pod，尚未创建时返回 nil
+func (e *KubernetesExecutor) currentPod(ctx context.Context, process *JobProcess) (*corev1.Pod, error) {
+	pods, err := e.clientset.CoreV1().Pods(e.cfg.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("aitip.job.id=%s,aitip.job.attempt=%d", process.JobID, process.Attempt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		return nil, nil
+	}
+
+	// BackoffLimit 为 0 时只会有一个 Pod，取最新的以防万一
+	latest := &pods.Items[0]
+	for i := range pods.Items {
+		if pods.Items[i].CreationTimestamp.After(latest.CreationTimestamp.Time) {
+			latest = &pods.Items[i]
+		}
+	}
+	return latest, nil
+}
+
+// jobFailure 检查 Job 是否已失败
+func (e *KubernetesExecutor) jobFailure(ctx context.Context, process *JobProcess) (bool, string) {
+	k8sJob, err := e.clientset.BatchV1().Jobs(e.cfg.Namespace).Get(ctx, process.ContainerID, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, fmt.Sprintf("kubernetes job %s no longer exists", process.ContainerID)
+		}
+		return false, ""
+	}
+
+	for _, cond := range k8sJob.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			return true, fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+		}
+	}
+	return false, ""
+}
+
+// podWaitingFailure 返回训练容器无法恢复的等待原因
+func podWaitingFailure(pod *corev1.Pod) (string, string) {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if waiting := status.State.Waiting; waiting != nil && imagePullFailures[waiting.Reason] {
+			return waiting.Reason, waiting.Message
+		}
+	}
+	return "", ""
+}
+
+// trainerTermination 获取训练容器的终止状态
+func trainerTermination(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == trainerContainerName {
+			return status.State.Terminated
+		}
+	}
+	return nil
+}
+
+// collectLogs 跟随收集训练容器日志，since 非零时只收集该时间之后的日志
+func (e *KubernetesExecutor) collectLogs(ctx context.Context, process *JobProcess, podName string, since time.Time) {
+	defer close(process.logsDone)
+
+	options := &corev1.PodLogOptions{
+		Container: trainerContainerName,
+		Follow:    true,
+	}
+	if !since.IsZero() {
+		options.SinceTime = &metav1.Time{Time: since}
+	}
+
+	stream, err := e.clientset.CoreV1().Pods(e.cfg.Namespace).GetLogs(podName, options).Stream(ctx)
+	if err != nil {
+		logger.Error("Failed to get pod logs", zap.String("pod", podName), zap.Error(err))
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		line = process.redactor.Redact(line)
+
+		process.appendTail(line)
+
+		// Pod 日志不区分 stdout/stderr，只能按内容推断级别
+		entry := &domain.LogEntry{
+			Level:     logLevel("stdout", line),
+			Source:    "stdout",
+			Message:   line,
+			Attempt:   process.Attempt,
+			Timestamp: time.Now(),
+		}
+		metrics, err := e.metricParser.ParseLine(line)
+		if err != nil {
+			logger.Warn("Invalid metric report", zap.String("job_id", process.JobID.String()), zap.Error(err))
+		} else if metrics != nil {
+			entry.Message = fmt.Sprintf("[METRICS] %s", line)
+			if e.metricsHandler != nil {
+				go e.metricsHandler(process.JobID, metrics)
+			}
+		}
+
+		logCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		e.logRepo.AppendLog(logCtx, process.JobID, entry)
+		cancel()
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		logger.Error("Error reading pod logs", zap.String("pod", podName), zap.Error(err))
+	}
+}
+
+// handlePodExit 处理 Pod 结束
+func (e *KubernetesExecutor) handlePodExit(job *domain.TrainingJob, process *JobProcess, pod *corev1.Pod) {
+	exitCode := int64(0)
+	oomKilled := false
+	if terminated := trainerTermination(pod); terminated != nil {
+		exitCode = int64(terminated.ExitCode)
+		oomKilled = terminated.Reason == "OOMKilled"
+	} else if pod.Status.Phase == corev1.PodFailed {
+		exitCode = 1
+	}
+	logger.Info("Training pod finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("pod", pod.Name),
+		zap.String("phase", string(pod.Status.Phase)),
+		zap.Int64("exit_code", exitCode),
+	)
+
+	code := int(exitCode)
+	event := &ExitEvent{
+		JobID:    job.ID,
+		Attempt:  job.Attempt,
+		ExitCode: &code,
+	}
+
+	// 等待日志收集完成：删除 Job 前需读完最后的输出，重试判断也依赖它
+	select {
+	case <-process.logsDone:
+	case <-time.After(3 * time.Second):
+	}
+
+	if pod.Status.Phase == corev1.PodSucceeded {
+		event.Status = domain.JobStatusCompleted
+		event.Message = "Training completed successfully"
+	} else {
+		logTail := process.tail()
+
+		event.Status = domain.JobStatusFailed
+		switch {
+		case oomKilled || e.errorHandler.IsOOMExit(exitCode, logTail):
+			event.Message = "Training failed: Out of Memory (OOM)"
+		case pod.Status.Reason == "DeadlineExceeded":
+			event.Message = "Training failed: timeout exceeded"
+		default:
+			event.Message = fmt.Sprintf("Training failed with exit code %d", exitCode)
+		}
+
+		event.Retryable, event.RetryReason = e.errorHandler.ShouldRetryWithPolicy(job.RetryPolicy, exitCode, logTail)
+	}
+
+	level := "INFO"
+	if event.Status == domain.JobStatusFailed {
+		level = "ERROR"
+	}
+	e.appendSystemLog(job, level, event.Message)
+
+	e.cleanupJob(process)
+	e.notifyExit(event)
+}
+
+// handleJobFailure 处理任务失败
+func (e *KubernetesExecutor) handleJobFailure(job *domain.TrainingJob, process *JobProcess, err error) {
+	logger.Error("Job failed", zap.String("job_id", job.ID.String()), zap.Error(err))
+
+	message := fmt.Sprintf("Job failed: %v", err)
+	e.appendSystemLog(job, "ERROR", message)
+
+	e.cleanupJob(process)
+	e.notifyExit(&ExitEvent{
+		JobID:   job.ID,
+		Attempt: job.Attempt,
+		Status:  domain.JobStatusFailed,
+		Message: message,
+	})
+}
+
+// appendSystemLog 记录当前尝试的系统日志
+func (e *KubernetesExecutor) appendSystemLog(job *domain.TrainingJob, level, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e.logRepo.AppendLog(ctx, job.ID, &domain.LogEntry{
+		Level:     level,
+		Source:    "system",
+		Message:   message,
+		Attempt:   job.Attempt,
+		Timestamp: time.Now(),
+	})
+}
+
+// notifyExit 通知任务退出
+func (e *KubernetesExecutor) notifyExit(event *ExitEvent) {
+	if e.exitHandler != nil {
+		e.exitHandler(event)
+	}
+}
+
+// cleanupJob 停止跟踪任务并删除 Kubernetes Job 及其 Pod
+func (e *KubernetesExecutor) cleanupJob(process *JobProcess) {
+	e.mu.Lock()
+	if e.jobs[process.JobID] == process {
+		delete(e.jobs, process.JobID)
+	}
+	e.mu.Unlock()
+
+	if process.CancelFunc != nil {
+		process.CancelFunc()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := e.deleteJob(ctx, process.ContainerID, nil); err != nil {
+		logger.Warn("Failed to delete kubernetes job", zap.String("k8s_job", process.ContainerID), zap.Error(err))
+	}
+}
+
+// deleteJob 删除 Kubernetes Job 及其 Pod，Job 不存在时不报错
+func (e *KubernetesExecutor) deleteJob(ctx context.Context, name string, gracePeriod *int64) error {
+	propagation := metav1.DeletePropagationBackground
+	err := e.clientset.BatchV1().Jobs(e.cfg.Namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy:  &propagation,
+		GracePeriodSeconds: gracePeriod,
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	// 通常由垃圾回收随 Job 删除，设置所有者失败时在这里兜底
+	e.deleteSecret(ctx, secretNameFor(name))
+	return nil
+}
+
+// Stop 停止训练任务
+func (e *KubernetesExecutor) Stop(ctx context.Context, jobID uuid.UUID) error {
+	e.mu.Lock()
+	process, exists := e.jobs[jobID]
+	e.mu.Unlock()
+
+	if !exists {
+		return e.stopOrphanJob(ctx, jobID)
+	}
+
+	if process.CancelFunc != nil {
+		process.CancelFunc()
+	}
+
+	gracePeriod := int64(30)
+	if err := e.deleteJob(ctx, process.ContainerID, &gracePeriod); err != nil {
+		return fmt.Errorf("failed to delete kubernetes job: %w", err)
+	}
+
+	// 等待 Pod 退出，与 Docker 执行器的停止语义保持一致
+	deadline := time.After(35 * time.Second)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		pod, err := e.currentPod(ctx, process)
+		if err == nil && pod == nil {
+			e.cleanupJob(process)
+			return nil
+		}
+
+		select {
+		case <-deadline:
+			e.cleanupJob(process)
+			return fmt.Errorf("timeout waiting for job %s to stop", jobID)
+		case <-ctx.Done():
+			e.cleanupJob(process)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Pause 暂停任务：以 grace 为宽限期删除 Pod，kubelet 先发送 SIGTERM，超时后强制终止
+//
+// Kubernetes 不支持自定义终止信号，signal 不是 SIGTERM 时记录警告；训练镜像需在 SIGTERM 时保存检查点。
+func (e *KubernetesExecutor) Pause(ctx context.Context, jobID uuid.UUID, signal string, grace time.Duration) error {
+	e.mu.Lock()
+	process, exists := e.jobs[jobID]
+	e.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("job %s is not running", jobID)
+	}
+	process.pausing.Store(true)
+
+	if signal != "" && signal != "SIGTERM" {
+		logger.Warn("Kubernetes executor pauses with SIGTERM", zap.String("job_id", jobID.String()), zap.String("signal", signal))
+	}
+
+	gracePeriod := int64(grace.Seconds())
+	if err := e.deleteJob(ctx, process.ContainerID, &gracePeriod); err != nil {
+		return fmt.Errorf("failed to delete kubernetes job: %w", err)
+	}
+
+	// 等待 Pod 在宽限期内退出
+	deadline := time.After(grace + 30*time.Second)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		pod, err := e.currentPod(ctx, process)
+		if err == nil && pod == nil {
+			e.cleanupJob(process)
+			return nil
+		}
+
+		select {
+		case <-deadline:
+			e.cleanupJob(process)
+			return fmt.Errorf("timeout waiting for job %s to pause", jobID)
+		case <-ctx.Done():
+			e.cleanupJob(process)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopOrphanJob 删除未被跟踪的任务的 Kubernetes Job
+func (e *KubernetesExecutor) stopOrphanJob(ctx context.Context, jobID uuid.UUID) error {
+	jobs, err := e.clientset.BatchV1().Jobs(e.cfg.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("aitip.job.id=%s", jobID),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k8sJob := range jobs.Items {
+		if err := e.deleteJob(ctx, k8sJob.Name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStatus 获取任务状态
+func (e *KubernetesExecutor) GetStatus(ctx context.Context, jobID uuid.UUID) (domain.JobStatus, error) {
+	e.mu.RLock()
+	process, exists := e.jobs[jobID]
+	e.mu.RUnlock()
+
+	if !exists {
+		return domain.JobStatusPending, nil
+	}
+
+	pod, err := e.currentPod(ctx, process)
+	if err != nil {
+		return domain.JobStatusFailed, fmt.Errorf("failed to get pod: %w", err)
+	}
+	if pod == nil {
+		return domain.JobStatusPending, nil
+	}
+	return podPhaseToStatus(pod.Status.Phase), nil
+}
+
+// podPhaseToStatus 将 Pod 阶段映射为任务状态
+func podPhaseToStatus(phase corev1.PodPhase) domain.JobStatus {
+	switch phase {
+	case corev1.PodRunning:
+		return domain.JobStatusRunning
+	case corev1.PodSucceeded:
+		return domain.JobStatusCompleted
+	case corev1.PodFailed:
+		return domain.JobStatusFailed
+	default:
+		return domain.JobStatusPending
+	}
+}
+
+// IsRunning 检查任务是否在运行
+func (e *KubernetesExecutor) IsRunning(ctx context.Context, jobID uuid.UUID) bool {
+	status, err := e.GetStatus(ctx, jobID)
+	if err != nil {
+		return false
+	}
+	return status == domain.JobStatusRunning
+}
+
+// GetContainerStats 获取容器统计信息（资源用量需要 metrics-server，此处只返回状态）
+func (e *KubernetesExecutor) GetContainerStats(ctx context.Context, jobID uuid.UUID) (*ContainerStats, error) {
+	e.mu.RLock()
+	process, exists := e.jobs[jobID]
+	e.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("job %s not found", jobID)
+	}
+
+	pod, err := e.currentPod(ctx, process)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ContainerStats{
+		ContainerID: process.ContainerID,
+		Status:      string(corev1.PodPending),
+		StartedAt:   process.StartedAt,
+	}
+	if pod != nil {
+		stats.Status = string(pod.Status.Phase)
+		if pod.Status.StartTime != nil {
+			stats.StartedAt = pod.Status.StartTime.Time
+		}
+	}
+	return stats, nil
+}
+
+// GetGPUCount 获取集群中可分配的 GPU 总数
+func (e *KubernetesExecutor) GetGPUCount(ctx context.Context) (int, error) {
+	nodes, err := e.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	total := 0
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		if quantity, ok := node.Status.Allocatable[corev1.ResourceName(e.cfg.GPUResourceName)]; ok {
+			total += int(quantity.Value())
+		}
+	}
+	return total, nil
+}
+
+// IsTracked 任务是否由当前执行器跟踪
+func (e *KubernetesExecutor) IsTracked(jobID uuid.UUID) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, exists := e.jobs[jobID]
+	return exists
+}
+
+// ListManagedContainers 列出平台创建的 Kubernetes Job，以容器信息的形式返回
+//
+// ContainerID 为 Job 名，ContainerName 为 Pod 名。Pod 等待调度或拉取镜像时也视为 running，
+// 以便协调器重新接管而不是判定为未启动。
+func (e *KubernetesExecutor) ListManagedContainers(ctx context.Context) ([]*domain.ContainerInfo, error) {
+	jobs, err := e.clientset.BatchV1().Jobs(e.cfg.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "aitip.job.id",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kubernetes jobs: %w", err)
+	}
+
+	result := make([]*domain.ContainerInfo, 0, len(jobs.Items))
+	for i := range jobs.Items {
+		k8sJob := &jobs.Items[i]
+		info := &domain.ContainerInfo{
+			ContainerID:   k8sJob.Name,
+			ContainerName: k8sJob.Name,
+			State:         "running",
+			Labels:        k8sJob.Labels,
+		}
+		if containers := k8sJob.Spec.Template.Spec.Containers; len(containers) > 0 {
+			info.Image = containers[0].Image
+		}
+
+		attempt, _ := strconv.Atoi(k8sJob.Labels["aitip.job.attempt"])
+		jobID, _ := uuid.Parse(k8sJob.Labels["aitip.job.id"])
+		pod, err := e.currentPod(ctx, &JobProcess{JobID: jobID, Attempt: attempt})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod of kubernetes job %s: %w", k8sJob.Name, err)
+		}
+
+		switch {
+		case pod == nil:
+			if k8sJob.Status.Failed > 0 || k8sJob.Status.Succeeded > 0 {
+				info.State = "dead"
+			}
+		default:
+			info.ContainerName = pod.Name
+			info.Status = string(pod.Status.Phase)
+			switch pod.Status.Phase {
+			case corev1.PodSucceeded:
+				info.State = "exited"
+			case corev1.PodFailed:
+				info.State = "exited"
+				info.ExitCode = 1
+				if terminated := trainerTermination(pod); terminated != nil {
+					info.ExitCode = int(terminated.ExitCode)
+				}
+			}
+		}
+
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+// Reattach 重新接管服务重启前创建的 Kubernetes Job，恢复日志收集和状态监控
+func (e *KubernetesExecutor) Reattach(ctx context.Context, job *domain.TrainingJob, containers []*domain.ContainerInfo) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.jobs[job.ID]; exists {
+		return nil
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no kubernetes job to reattach for job %s", job.ID)
+	}
+
+	// 从最后一条已收集的日志之后继续收集，避免重复
+	since, err := e.logRepo.GetLastContainerLogTime(ctx, job.ID, nil)
+	if err != nil {
+		logger.Warn("Failed to get last log time, collecting from start", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	execCtx, cancel := context.WithCancel(context.Background())
+	process := &JobProcess{
+		JobID:         job.ID,
+		Attempt:       job.Attempt,
+		ContainerID:   containers[0].ContainerID,
+		ContainerName: containers[0].ContainerName,
+		CancelFunc:    cancel,
+		StartedAt:     time.Now(),
+		RetryCount:    job.Attempt - 1,
+		logsDone:      make(chan struct{}),
+		redactor:      restoreRedactor(ctx, e.secrets, job),
+	}
+	e.jobs[job.ID] = process
+
+	go e.monitorJob(execCtx, job, process, since)
+
+	logger.Info("Reattached to kubernetes job", zap.String("job_id", job.ID.String()), zap.String("k8s_job", process.ContainerID))
+	return nil
+}
+
+// RemoveContainer 删除 Kubernetes Job 及其 Pod
+func (e *KubernetesExecutor) RemoveContainer(ctx context.Context, containerID string) error {
+	return e.deleteJob(ctx, containerID, nil)
+}
+
+// RemoveNetwork Kubernetes 任务没有专用网络，无需删除
+func (e *KubernetesExecutor) RemoveNetwork(ctx context.Context, name string) error {
+	return nil
+}
+
+// ParseNodeSelector 解析 key=value,key2=value2 形式的节点选择器
+func ParseNodeSelector(value string) (map[string]string, error) {
+	selector := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(item, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid node selector %q, expected key=value", item)
+		}
+		selector[key] = val
+	}
+	return selector, nil
+}
+
+// ParseTolerations 解析 key=value:Effect 或 key:Effect 形式的容忍度，多个以逗号分隔
+func ParseTolerations(value string) ([]corev1.Toleration, error) {
+	var tolerations []corev1.Toleration
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		spec, effect, _ := strings.Cut(item, ":")
+		key, val, hasValue := strings.Cut(spec, "=")
+		if key == "" {
+			return nil, fmt.Errorf("invalid toleration %q, expected key[=value][:effect]", item)
+		}
+
+		toleration := corev1.Toleration{
+			Key:      key,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffect(effect),
+		}
+		if hasValue {
+			toleration.Operator = corev1.TolerationOpEqual
+			toleration.Value = val
+		}
+		tolerations = append(tolerations, toleration)
+	}
+	return tolerations, nil
+}
@@ -0,0 +1,378 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// fakeLogRepo 记录追加的日志，其余方法未实现
+type fakeLogRepo struct {
+	repository.LogRepository
+
+	mu      sync.Mutex
+	entries []*domain.LogEntry
+}
+
+func (r *fakeLogRepo) AppendLog(ctx context.Context, jobID uuid.UUID, entry *domain.LogEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func (r *fakeLogRepo) GetLastContainerLogTime(ctx context.Context, jobID uuid.UUID, rank *int) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func newTestKubernetesExecutor(t *testing.T) (*KubernetesExecutor, *fake.Clientset) {
+	t.Helper()
+	clientset := fake.NewSimpleClientset()
+	e := NewKubernetesExecutor(clientset, &fakeLogRepo{}, KubernetesConfig{
+		Namespace:    "training",
+		GPUTypeLabel: "gpu.aitip.io/type",
+		NodeSelector: map[string]string{"pool": "gpu"},
+		PollInterval: 10 * time.Millisecond,
+	})
+	return e, clientset
+}
+
+func newTestJob() *domain.TrainingJob {
+	return &domain.TrainingJob{
+		ID:           uuid.New(),
+		ProjectID:    uuid.New(),
+		Name:         "resnet",
+		Framework:    domain.FrameworkPyTorch,
+		Image:        "pytorch/pytorch:latest",
+		Command:      []string{"python", "train.py"},
+		CPUCount:     4,
+		MemoryGB:     16,
+		GPUCount:     2,
+		GPUType:      "a100",
+		TimeoutHours: 2,
+		Attempt:      1,
+	}
+}
+
+func TestKubernetesBuildJob(t *testing.T) {
+	e, _ := newTestKubernetesExecutor(t)
+	job := newTestJob()
+
+	spec := e.buildJob(job, nil)
+
+	if spec.Namespace != "training" || spec.Name != containerNameFor(job) {
+		t.Errorf("namespace/name = %s/%s", spec.Namespace, spec.Name)
+	}
+	if spec.Labels["aitip.job.id"] != job.ID.String() || spec.Labels["aitip.job.attempt"] != "1" {
+		t.Errorf("labels = %v", spec.Labels)
+	}
+	if *spec.Spec.BackoffLimit != 0 {
+		t.Errorf("backoffLimit = %d, want 0", *spec.Spec.BackoffLimit)
+	}
+	if spec.Spec.ActiveDeadlineSeconds == nil || *spec.Spec.ActiveDeadlineSeconds != 7200 {
+		t.Errorf("activeDeadlineSeconds = %v, want 7200", spec.Spec.ActiveDeadlineSeconds)
+	}
+
+	// 之前的尝试用掉的运行时间从本次尝试的期限中扣除
+	job.RunSeconds = 1800
+	if retried := e.buildJob(job, nil); *retried.Spec.ActiveDeadlineSeconds != 5400 {
+		t.Errorf("activeDeadlineSeconds after 1800s of earlier attempts = %d, want 5400", *retried.Spec.ActiveDeadlineSeconds)
+	}
+
+	pod := spec.Spec.Template.Spec
+	if pod.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("restartPolicy = %s, want Never", pod.RestartPolicy)
+	}
+	if pod.NodeSelector["pool"] != "gpu" || pod.NodeSelector["gpu.aitip.io/type"] != "a100" {
+		t.Errorf("nodeSelector = %v", pod.NodeSelector)
+	}
+	if len(pod.Containers) != 1 || pod.Containers[0].Name != trainerContainerName {
+		t.Fatalf("containers = %+v", pod.Containers)
+	}
+
+	limits := pod.Containers[0].Resources.Limits
+	if gpu := limits[corev1.ResourceName("nvidia.com/gpu")]; gpu.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("gpu limit = %s, want 2", gpu.String())
+	}
+	if cpu := limits[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("cpu limit = %s, want 4", cpu.String())
+	}
+	if memory := limits[corev1.ResourceMemory]; memory.Cmp(resource.MustParse("16Gi")) != 0 {
+		t.Errorf("memory limit = %s, want 16Gi", memory.String())
+	}
+}
+
+func TestKubernetesStartAndStop(t *testing.T) {
+	e, clientset := newTestKubernetesExecutor(t)
+	job := newTestJob()
+	ctx := context.Background()
+
+	if err := e.Start(ctx, job); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if job.ContainerID != containerNameFor(job) {
+		t.Errorf("ContainerID = %s, want %s", job.ContainerID, containerNameFor(job))
+	}
+	if !e.IsTracked(job.ID) {
+		t.Error("job is not tracked after Start")
+	}
+	if _, err := clientset.BatchV1().Jobs("training").Get(ctx, job.ContainerID, metav1.GetOptions{}); err != nil {
+		t.Fatalf("kubernetes job not created: %v", err)
+	}
+	if err := e.Start(ctx, job); err == nil {
+		t.Error("second Start() succeeded, want error")
+	}
+
+	if err := e.Stop(ctx, job.ID); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if e.IsTracked(job.ID) {
+		t.Error("job is still tracked after Stop")
+	}
+	jobs, err := clientset.BatchV1().Jobs("training").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(jobs.Items) != 0 {
+		t.Errorf("%d kubernetes jobs left after Stop", len(jobs.Items))
+	}
+}
+
+func TestKubernetesDatasetCredentials(t *testing.T) {
+	token := []secrets.Resolved{{Ref: secrets.Ref{Name: "HF_TOKEN"}, Value: "hf_secret"}}
+
+	tests := []struct {
+		name        string
+		dataPVC     string
+		datasetPath string
+		resolved    []secrets.Resolved
+		wantFetch   bool
+		wantKeys    []string
+	}{
+		{"no dataset", "", "", nil, false, nil},
+		{"dataset from minio", "", "s3://datasets/mnist", nil, true, []string{minioAccessKeyKey, minioSecretKeyKey}},
+		{"dataset from minio with job secrets", "", "s3://datasets/mnist", token, true, []string{minioAccessKeyKey, minioSecretKeyKey, secretKey(0)}},
+		{"dataset on pvc", "datasets", "/mnist", token, false, []string{secretKey(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _ := newTestKubernetesExecutor(t)
+			e.cfg.DataPVC = tt.dataPVC
+			e.cfg.MinIOAccessKey = "minio"
+			e.cfg.MinIOSecretKey = "minio-password"
+			job := newTestJob()
+			job.DatasetPath = tt.datasetPath
+
+			spec := e.buildJob(job, tt.resolved)
+			secret := e.buildSecret(spec, tt.resolved)
+
+			var keys []string
+			if secret != nil {
+				for key := range secret.Data {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("secret keys = %v, want %v", keys, tt.wantKeys)
+			}
+
+			initContainers := spec.Spec.Template.Spec.InitContainers
+			if fetch := len(initContainers) > 0; fetch != tt.wantFetch {
+				t.Fatalf("init containers = %d, want fetch %v", len(initContainers), tt.wantFetch)
+			}
+			if !tt.wantFetch {
+				return
+			}
+			if string(secret.Data[minioSecretKeyKey]) != "minio-password" {
+				t.Errorf("secret %s = %q", minioSecretKeyKey, secret.Data[minioSecretKeyKey])
+			}
+			for _, v := range initContainers[0].Env {
+				if v.Name != "MINIO_ACCESS_KEY" && v.Name != "MINIO_SECRET_KEY" {
+					continue
+				}
+				if v.Value != "" || v.ValueFrom == nil || v.ValueFrom.SecretKeyRef == nil || v.ValueFrom.SecretKeyRef.Name != secret.Name {
+					t.Errorf("%s = %+v, want secretKeyRef to %s", v.Name, v, secret.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestKubernetesStartRejectsMultiNode(t *testing.T) {
+	e, _ := newTestKubernetesExecutor(t)
+	job := newTestJob()
+	job.Distributed = &domain.DistributedConfig{NumNodes: 2}
+
+	if err := e.Start(context.Background(), job); err == nil {
+		t.Fatal("Start() succeeded for a multi-node job, want error")
+	}
+}
+
+func TestKubernetesPodExit(t *testing.T) {
+	tests := []struct {
+		name       string
+		phase      corev1.PodPhase
+		terminated *corev1.ContainerStateTerminated
+		wantStatus domain.JobStatus
+		wantCode   int
+	}{
+		{
+			name:       "succeeded",
+			phase:      corev1.PodSucceeded,
+			terminated: &corev1.ContainerStateTerminated{ExitCode: 0},
+			wantStatus: domain.JobStatusCompleted,
+			wantCode:   0,
+		},
+		{
+			name:       "oom killed",
+			phase:      corev1.PodFailed,
+			terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+			wantStatus: domain.JobStatusFailed,
+			wantCode:   137,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, clientset := newTestKubernetesExecutor(t)
+			exits := make(chan *ExitEvent, 1)
+			e.SetExitHandler(func(event *ExitEvent) { exits <- event })
+
+			job := newTestJob()
+			ctx := context.Background()
+			if err := e.Start(ctx, job); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      job.ContainerID + "-abcde",
+					Namespace: "training",
+					Labels: map[string]string{
+						"aitip.job.id":      job.ID.String(),
+						"aitip.job.attempt": "1",
+					},
+				},
+				Status: corev1.PodStatus{
+					Phase: tt.phase,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  trainerContainerName,
+						State: corev1.ContainerState{Terminated: tt.terminated},
+					}},
+				},
+			}
+			if _, err := clientset.CoreV1().Pods("training").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("create pod: %v", err)
+			}
+
+			select {
+			case event := <-exits:
+				if event.JobID != job.ID || event.Status != tt.wantStatus {
+					t.Errorf("event = %+v, want status %s", event, tt.wantStatus)
+				}
+				if event.ExitCode == nil || *event.ExitCode != tt.wantCode {
+					t.Errorf("exit code = %v, want %d", event.ExitCode, tt.wantCode)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("exit handler was not called")
+			}
+			if e.IsTracked(job.ID) {
+				t.Error("job is still tracked after exit")
+			}
+		})
+	}
+}
+
+func TestKubernetesListManagedContainers(t *testing.T) {
+	e, clientset := newTestKubernetesExecutor(t)
+	job := newTestJob()
+	ctx := context.Background()
+
+	spec := e.buildJob(job, nil)
+	if _, err := clientset.BatchV1().Jobs("training").Create(ctx, spec, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      spec.Name + "-abcde",
+			Namespace: "training",
+			Labels:    spec.Spec.Template.Labels,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  trainerContainerName,
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 3}},
+			}},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods("training").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create pod: %v", err)
+	}
+
+	containers, err := e.ListManagedContainers(ctx)
+	if err != nil {
+		t.Fatalf("ListManagedContainers() error = %v", err)
+	}
+	if len(containers) != 1 {
+		t.Fatalf("got %d containers, want 1", len(containers))
+	}
+	c := containers[0]
+	if c.ContainerID != spec.Name || c.ContainerName != pod.Name {
+		t.Errorf("container id/name = %s/%s", c.ContainerID, c.ContainerName)
+	}
+	if c.State != "exited" || c.ExitCode != 3 {
+		t.Errorf("state/exit code = %s/%d, want exited/3", c.State, c.ExitCode)
+	}
+	if c.Image != job.Image {
+		t.Errorf("image = %s, want %s", c.Image, job.Image)
+	}
+}
+
+func TestParseTolerations(t *testing.T) {
+	tolerations, err := ParseTolerations("nvidia.com/gpu:NoSchedule, dedicated=training:NoExecute")
+	if err != nil {
+		t.Fatalf("ParseTolerations() error = %v", err)
+	}
+	if len(tolerations) != 2 {
+		t.Fatalf("got %d tolerations, want 2", len(tolerations))
+	}
+	if got := tolerations[0]; got.Key != "nvidia.com/gpu" || got.Operator != corev1.TolerationOpExists || got.Effect != corev1.TaintEffectNoSchedule {
+		t.Errorf("tolerations[0] = %+v", got)
+	}
+	if got := tolerations[1]; got.Key != "dedicated" || got.Operator != corev1.TolerationOpEqual || got.Value != "training" || got.Effect != corev1.TaintEffectNoExecute {
+		t.Errorf("tolerations[1] = %+v", got)
+	}
+
+	if _, err := ParseTolerations("=value:NoSchedule"); err == nil {
+		t.Error("ParseTolerations() accepted an empty key")
+	}
+}
+
+func TestParseNodeSelector(t *testing.T) {
+	selector, err := ParseNodeSelector("pool=gpu, zone=a")
+	if err != nil {
+		t.Fatalf("ParseNodeSelector() error = %v", err)
+	}
+	if len(selector) != 2 || selector["pool"] != "gpu" || selector["zone"] != "a" {
+		t.Errorf("selector = %v", selector)
+	}
+	if _, err := ParseNodeSelector("pool"); err == nil {
+		t.Error("ParseNodeSelector() accepted an item without '='")
+	}
+}
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// metricLogPrefix 结构化指标日志行前缀，后跟 JSON（格式见 domain.MetricReport）
+const metricLogPrefix = "AITIP_METRIC "
+
+// MetricsHandler 从训练日志中解析到指标时的回调
+type MetricsHandler func(jobID uuid.UUID, metrics *TrainingMetrics)
+
+// MetricsEndpoint 训练容器上报指标的接口地址和令牌签名密钥
+type MetricsEndpoint struct {
+	BaseURL string // 训练服务地址（容器内可访问）
+	Secret  string
+}
+
+// MetricsToken 生成任务专用的指标上报令牌
+func MetricsToken(secret string, jobID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(jobID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyMetricsToken 校验指标上报令牌
+func VerifyMetricsToken(secret string, jobID uuid.UUID, token string) bool {
+	return hmac.Equal([]byte(MetricsToken(secret, jobID)), []byte(token))
+}
+
+// metricsEnv 指标上报接口的环境变量，未配置接口时为空
+func metricsEnv(job *domain.TrainingJob, endpoint *MetricsEndpoint) []string {
+	if endpoint == nil || endpoint.BaseURL == "" {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("AITIP_METRICS_URL=%s/api/v1/training/jobs/%s/metrics", strings.TrimRight(endpoint.BaseURL, "/"), job.ID),
+		fmt.Sprintf("AITIP_METRICS_TOKEN=%s", MetricsToken(endpoint.Secret, job.ID)),
+	}
+}
+
+// MetricsFromReport 将上报的指标转换为训练指标，常用指标映射到对应字段，其余放入 Custom
+func MetricsFromReport(report *domain.MetricReport) *TrainingMetrics {
+	metrics := &TrainingMetrics{Timestamp: time.Now()}
+	if report.Timestamp != nil {
+		metrics.Timestamp = *report.Timestamp
+	}
+	if report.Step != nil {
+		metrics.Step = *report.Step
+	}
+	if report.Epoch != nil {
+		metrics.Epoch = *report.Epoch
+	}
+
+	for key, value := range report.Metrics {
+		metrics.Set(key, value)
+	}
+	return metrics
+}
+
+// Set 设置指标值，常用指标写入对应字段，其余写入 Custom
+func (m *TrainingMetrics) Set(key string, value float64) {
+	switch key {
+	case "loss":
+		m.Loss = &value
+	case "accuracy":
+		m.Accuracy = &value
+	case "val_loss":
+		m.ValLoss = &value
+	case "val_accuracy":
+		m.ValAccuracy = &value
+	case "learning_rate":
+		m.LearningRate = &value
+	default:
+		if m.Custom == nil {
+			m.Custom = make(map[string]float64)
+		}
+		m.Custom[key] = value
+	}
+}
+
+// Values 以指标名到值的形式返回全部指标
+func (m *TrainingMetrics) Values() map[string]float64 {
+	values := make(map[string]float64, len(m.Custom)+5)
+	for key, ptr := range map[string]*float64{
+		"loss":          m.Loss,
+		"accuracy":      m.Accuracy,
+		"val_loss":      m.ValLoss,
+		"val_accuracy":  m.ValAccuracy,
+		"learning_rate": m.LearningRate,
+	} {
+		if ptr != nil {
+			values[key] = *ptr
+		}
+	}
+	for key, value := range m.Custom {
+		values[key] = value
+	}
+	return values
+}
+
+// ParseLine 解析一行日志中的指标：AITIP_METRIC 行按协议解析，其余行使用正则兜底
+//
+// 协议行格式错误时返回错误，不再尝试正则解析。
+func (p *MetricParser) ParseLine(line string) (*TrainingMetrics, error) {
+	if !strings.HasPrefix(line, metricLogPrefix) {
+		return p.Parse(line), nil
+	}
+
+	var report domain.MetricReport
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, metricLogPrefix)), &report); err != nil {
+		return nil, fmt.Errorf("invalid metric report: %w", err)
+	}
+	if err := report.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid metric report: %w", err)
+	}
+	return MetricsFromReport(&report), nil
+}
@@ -14,8 +14,14 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
 )
 
+// MetricsBroadcaster 实时指标推送接口
+type MetricsBroadcaster interface {
+	BroadcastMetric(jobID uuid.UUID, metricType string, payload interface{})
+}
+
 // MetricCollector 指标收集器
 type MetricCollector struct {
 	db         *gorm.DB
@@ -112,17 +118,23 @@ func (c *MetricCollector) runHub() {
 			c.clientsMu.Unlock()
 
 		case message := <-c.broadcast:
-			c.clientsMu.RLock()
-			clients := c.clients[message.JobID]
-			c.clientsMu.RUnlock()
+			data := messageToBytes(message)
 
+			c.clientsMu.Lock()
+			clients := c.clients[message.JobID]
 			for client := range clients {
 				select {
-				case client.Send <- messageToBytes(message):
+				case client.Send <- data:
 				default:
-					c.unregister <- client
+					// 客户端消费过慢，直接断开（不能向 unregister 发送，hub 自身会阻塞）
+					delete(clients, client)
+					close(client.Send)
 				}
 			}
+			if len(clients) == 0 {
+				delete(c.clients, message.JobID)
+			}
+			c.clientsMu.Unlock()
 
 		case <-c.stopCh:
 			return
@@ -252,28 +264,86 @@ func (c *MetricCollector) SaveMetric(ctx context.Context, jobID uuid.UUID, metri
 	return nil
 }
 
-func (c *MetricCollector) metricsToRecords(jobID uuid.UUID, metrics *TrainingMetrics) []*MetricRecord {
+// SaveMetrics 保存指标，实现 MetricsRepository
+func (c *MetricCollector) SaveMetrics(ctx context.Context, jobID uuid.UUID, metrics *TrainingMetrics) error {
+	return c.SaveMetric(ctx, jobID, metrics)
+}
+
+// GetMetrics 查询任务的指标，metricType 为空时返回全部指标，同一时刻的指标合并为一条
+func (c *MetricCollector) GetMetrics(ctx context.Context, jobID uuid.UUID, metricType string) ([]*TrainingMetrics, error) {
+	query := c.db.WithContext(ctx).Where("job_id = ?", jobID)
+	if metricType != "" {
+		query = query.Where("metric_type = ?", metricType)
+	}
+
 	var records []*MetricRecord
+	if err := query.Order("timestamp ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get metrics: %w", err)
+	}
 
-	if metrics.Loss != nil {
-		records = append(records, &MetricRecord{
-			JobID:      jobID,
-			Timestamp:  metrics.Timestamp,
-			MetricType: "loss",
-			Epoch:      intPtr(metrics.Epoch),
-			Step:       intPtr(metrics.Step),
-			Value:      *metrics.Loss,
+	var result []*TrainingMetrics
+	var current *TrainingMetrics
+	for _, record := range records {
+		if current == nil || !current.Timestamp.Equal(record.Timestamp) {
+			current = &TrainingMetrics{Timestamp: record.Timestamp}
+			if record.Epoch != nil {
+				current.Epoch = *record.Epoch
+			}
+			if record.Step != nil {
+				current.Step = *record.Step
+			}
+			result = append(result, current)
+		}
+		current.Set(record.MetricType, record.Value)
+	}
+
+	return result, nil
+}
+
+// GetSeries 按指标名分组查询任务的指标曲线，metricType 为空时返回全部指标
+func (c *MetricCollector) GetSeries(ctx context.Context, jobID uuid.UUID, metricType string) ([]*domain.MetricSeries, error) {
+	query := c.db.WithContext(ctx).Where("job_id = ?", jobID)
+	if metricType != "" {
+		query = query.Where("metric_type = ?", metricType)
+	}
+
+	var records []*MetricRecord
+	if err := query.Order("metric_type ASC, timestamp ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get metric series: %w", err)
+	}
+
+	var series []*domain.MetricSeries
+	var current *domain.MetricSeries
+	for _, record := range records {
+		if current == nil || current.Name != record.MetricType {
+			current = &domain.MetricSeries{Name: record.MetricType}
+			series = append(series, current)
+		}
+		current.Points = append(current.Points, domain.MetricPoint{
+			Timestamp: record.Timestamp,
+			Step:      record.Step,
+			Epoch:     record.Epoch,
+			Value:     record.Value,
 		})
 	}
+	for _, s := range series {
+		s.Total = len(s.Points)
+	}
+
+	return series, nil
+}
+
+func (c *MetricCollector) metricsToRecords(jobID uuid.UUID, metrics *TrainingMetrics) []*MetricRecord {
+	var records []*MetricRecord
 
-	if metrics.Accuracy != nil {
+	for metricType, value := range metrics.Values() {
 		records = append(records, &MetricRecord{
 			JobID:      jobID,
 			Timestamp:  metrics.Timestamp,
-			MetricType: "accuracy",
+			MetricType: metricType,
 			Epoch:      intPtr(metrics.Epoch),
 			Step:       intPtr(metrics.Step),
-			Value:      *metrics.Accuracy,
+			Value:      value,
 		})
 	}
 
@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// secretResourceType 审计记录中训练任务的资源类型
+const secretResourceType = "training_job"
+
+// resolveJobSecrets 解密任务引用的密钥，每次读取都会写入审计记录
+func resolveJobSecrets(ctx context.Context, manager secrets.Manager, job *domain.TrainingJob, action, detail string) ([]secrets.Resolved, error) {
+	if len(job.SecretRefs) == 0 {
+		return nil, nil
+	}
+	if manager == nil {
+		return nil, fmt.Errorf("job references secrets but the secrets store is not configured")
+	}
+
+	userID := job.UserID
+	return manager.Resolve(ctx, job.ProjectID, job.SecretRefs, secrets.Access{
+		Action:     action,
+		ActorID:    &userID,
+		Resource:   secretResourceType,
+		ResourceID: job.ID.String(),
+		Detail:     detail,
+	})
+}
+
+// injectSecrets 解密本次尝试需要注入容器的密钥
+func injectSecrets(ctx context.Context, manager secrets.Manager, job *domain.TrainingJob) ([]secrets.Resolved, error) {
+	resolved, err := resolveJobSecrets(ctx, manager, job, secrets.ActionInject, fmt.Sprintf("attempt %d", job.Attempt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	return resolved, nil
+}
+
+// restoreRedactor 服务重启后重新读取任务的密钥以继续对日志脱敏，失败时只记录警告
+func restoreRedactor(ctx context.Context, manager secrets.Manager, job *domain.TrainingJob) *secrets.Redactor {
+	resolved, err := resolveJobSecrets(ctx, manager, job, secrets.ActionRead, "log redaction after restart")
+	if err != nil {
+		logger.Warn("Failed to load job secrets, logs will not be redacted", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return nil
+	}
+	return secrets.NewRedactor(resolved)
+}
+
+// copySecretFiles 在容器启动前将以文件形式挂载的密钥写入容器，文件随容器删除
+func (e *DockerExecutor) copySecretFiles(ctx context.Context, containerID string, files map[string]string) error {
+	archive, err := secrets.Archive(files)
+	if err != nil {
+		return err
+	}
+	if err := e.client.CopyToContainer(ctx, containerID, "/", archive, types.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("failed to copy secret files to container: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/admission"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// AdmissionHandler 镜像准入策略处理器
+type AdmissionHandler struct {
+	controller  admission.Controller
+	projectRepo repository.ProjectRepository
+}
+
+// NewAdmissionHandler 创建镜像准入策略处理器
+func NewAdmissionHandler(controller admission.Controller, projectRepo repository.ProjectRepository) *AdmissionHandler {
+	return &AdmissionHandler{controller: controller, projectRepo: projectRepo}
+}
+
+// RegisterRoutes 注册路由
+func (h *AdmissionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	policies := router.Group("/training/image-policies")
+	{
+		policies.GET("/:org_id", h.GetPolicy)
+		policies.PUT("/:org_id", h.PutPolicy)
+		policies.DELETE("/:org_id", h.DeletePolicy)
+	}
+}
+
+// GetPolicy 获取组织的镜像策略，未设置时返回默认策略
+func (h *AdmissionHandler) GetPolicy(c *gin.Context) {
+	orgID, err := uuid.Parse(c.Param("org_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	policy, err := h.controller.GetPolicy(c.Request.Context(), orgID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, policy)
+}
+
+// PutPolicy 设置组织的镜像策略，整体替换已有策略
+func (h *AdmissionHandler) PutPolicy(c *gin.Context) {
+	orgID, err := uuid.Parse(c.Param("org_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	var req admission.PutPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok || !h.checkOrgManager(c, userID, orgID) {
+		return
+	}
+
+	policy, err := h.controller.PutPolicy(c.Request.Context(), userID, orgID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, policy)
+}
+
+// DeletePolicy 删除组织的镜像策略，之后使用默认策略
+func (h *AdmissionHandler) DeletePolicy(c *gin.Context) {
+	orgID, err := uuid.Parse(c.Param("org_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid organization ID")
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok || !h.checkOrgManager(c, userID, orgID) {
+		return
+	}
+
+	if err := h.controller.DeletePolicy(c.Request.Context(), orgID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Image policy deleted successfully"})
+}
+
+// checkOrgManager 检查调用方是否为组织的 owner 或 admin，否则写入 403 响应
+func (h *AdmissionHandler) checkOrgManager(c *gin.Context, userID, orgID uuid.UUID) bool {
+	ok, err := h.projectRepo.CanManageOrg(c.Request.Context(), userID, orgID)
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+	if !ok {
+		respondError(c, apperrors.Wrap(apperrors.ErrForbidden, http.StatusForbidden, fmt.Sprintf("only owners and admins of organization %s can change its image policy", orgID)))
+		return false
+	}
+	return true
+}
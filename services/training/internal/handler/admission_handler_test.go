@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/admission"
+)
+
+// fakeAdmissionController 记录策略的修改
+type fakeAdmissionController struct {
+	admission.Controller
+	changed []string
+}
+
+func (c *fakeAdmissionController) GetPolicy(ctx context.Context, orgID uuid.UUID) (*admission.ImagePolicy, error) {
+	return &admission.ImagePolicy{OrgID: orgID}, nil
+}
+
+func (c *fakeAdmissionController) PutPolicy(ctx context.Context, userID, orgID uuid.UUID, req *admission.PutPolicyRequest) (*admission.ImagePolicy, error) {
+	c.changed = append(c.changed, "put")
+	return &admission.ImagePolicy{OrgID: orgID, AllowedRegistries: req.AllowedRegistries}, nil
+}
+
+func (c *fakeAdmissionController) DeletePolicy(ctx context.Context, orgID uuid.UUID) error {
+	c.changed = append(c.changed, "delete")
+	return nil
+}
+
+func TestAdmissionHandlerOrgManagers(t *testing.T) {
+	orgID := uuid.New()
+	policyPath := "/api/v1/training/image-policies/" + orgID.String()
+	body := `{"allowed_registries":["registry.example.com"]}`
+
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		manager     bool
+		wantStatus  int
+		wantChanged bool
+	}{
+		{"get by member", http.MethodGet, "", false, http.StatusOK, false},
+		{"put by admin", http.MethodPut, body, true, http.StatusOK, true},
+		{"put by member", http.MethodPut, body, false, http.StatusForbidden, false},
+		{"delete by admin", http.MethodDelete, "", true, http.StatusOK, true},
+		{"delete by member", http.MethodDelete, "", false, http.StatusForbidden, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &fakeAdmissionController{}
+			projects := &fakeProjectRepo{managed: map[uuid.UUID]bool{orgID: tt.manager}}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewAdmissionHandler(controller, projects).RegisterRoutes(router.Group("/api/v1"))
+
+			req := httptest.NewRequest(tt.method, policyPath, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if changed := len(controller.changed) > 0; changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/service"
+)
+
+// ArtifactHandler 任务产物处理器
+type ArtifactHandler struct {
+	service service.ArtifactService
+}
+
+// NewArtifactHandler 创建任务产物处理器
+func NewArtifactHandler(service service.ArtifactService) *ArtifactHandler {
+	return &ArtifactHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *ArtifactHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/training/jobs/:id/artifacts", h.List)
+}
+
+// List 列出任务结束后上传的输出文件及其下载 URL
+func (h *ArtifactHandler) List(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	artifacts, err := h.service.List(c.Request.Context(), jobID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, artifacts)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/service"
+	"go.uber.org/zap"
+)
+
+// CheckpointHandler 检查点处理器
+type CheckpointHandler struct {
+	service service.CheckpointService
+}
+
+// NewCheckpointHandler 创建检查点处理器
+func NewCheckpointHandler(service service.CheckpointService) *CheckpointHandler {
+	return &CheckpointHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *CheckpointHandler) RegisterRoutes(router *gin.RouterGroup) {
+	checkpoints := router.Group("/training/jobs/:id/checkpoints")
+	{
+		checkpoints.GET("", h.List)
+		checkpoints.GET("/:checkpoint_id/download", h.Download)
+		checkpoints.DELETE("/:checkpoint_id", h.Delete)
+	}
+}
+
+// List 列出任务的检查点
+func (h *CheckpointHandler) List(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	checkpoints, err := h.service.List(c.Request.Context(), jobID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, checkpoints)
+}
+
+// Download 下载检查点：单文件返回预签名 URL（direct=true 时重定向），目录以 tar.gz 流式返回
+func (h *CheckpointHandler) Download(c *gin.Context) {
+	jobID, checkpointID, ok := parseCheckpointParams(c)
+	if !ok {
+		return
+	}
+
+	ckpt, err := h.service.Get(c.Request.Context(), jobID, checkpointID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	download, err := h.service.Download(c.Request.Context(), ckpt)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if download.Archive {
+		c.Header("Content-Type", download.ContentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", download.Filename))
+		c.Status(http.StatusOK)
+		if err := h.service.WriteArchive(c.Request.Context(), ckpt, c.Writer); err != nil {
+			// 响应头已发送，只能记录错误并中断连接
+			logger.Error("Failed to stream checkpoint archive",
+				zap.String("checkpoint_id", checkpointID.String()),
+				zap.Error(err),
+			)
+			c.Abort()
+		}
+		return
+	}
+
+	if c.Query("direct") == "true" {
+		c.Redirect(http.StatusTemporaryRedirect, download.URL)
+		return
+	}
+
+	response.Success(c, download)
+}
+
+// Delete 删除检查点
+func (h *CheckpointHandler) Delete(c *gin.Context) {
+	jobID, checkpointID, ok := parseCheckpointParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), jobID, checkpointID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// parseCheckpointParams 解析任务 ID 和检查点 ID
+func parseCheckpointParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	checkpointID, err := uuid.Parse(c.Param("checkpoint_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid checkpoint ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return jobID, checkpointID, true
+}
+
+// respondError 输出错误响应，AppError 使用其自带的状态码
+func respondError(c *gin.Context, err error) {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		response.AppError(c, appErr)
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+)
+
+// ClusterHandler 集群资源处理器
+type ClusterHandler struct {
+	gpus *executor.GPUAllocator
+}
+
+// NewClusterHandler 创建集群资源处理器，gpus 为 nil 时（如 Kubernetes 执行器）不报告 GPU 设备
+func NewClusterHandler(gpus *executor.GPUAllocator) *ClusterHandler {
+	return &ClusterHandler{gpus: gpus}
+}
+
+// RegisterRoutes 注册路由
+func (h *ClusterHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/cluster/gpus", h.ListGPUs)
+}
+
+// ListGPUs 列出宿主机的 GPU 及占用它们的任务
+func (h *ClusterHandler) ListGPUs(c *gin.Context) {
+	devices := []executor.GPUDevice{}
+	if h.gpus != nil {
+		devices = h.gpus.List()
+	}
+
+	allocated := 0
+	for _, device := range devices {
+		if device.JobID != nil {
+			allocated++
+		}
+	}
+
+	response.Success(c, gin.H{
+		"gpus":      devices,
+		"total":     len(devices),
+		"allocated": allocated,
+	})
+}
@@ -2,13 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
 	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/service"
@@ -35,6 +39,9 @@ func (h *JobHandler) RegisterRoutes(router *gin.RouterGroup) {
 		jobs.DELETE("/:id", h.DeleteJob)
 		jobs.POST("/:id/stop", h.StopJob)
 		jobs.GET("/:id/logs", h.GetLogs)
+		jobs.GET("/:id/attempts", h.ListAttempts)
+		jobs.POST("/:id/resume", h.ResumeJob)
+		jobs.POST("/:id/pause", h.PauseJob)
 	}
 }
 
@@ -60,6 +67,11 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 
 	job, err := h.service.CreateJob(c.Request.Context(), userID, &req)
 	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			response.AppError(c, appErr)
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -194,16 +206,116 @@ func (h *JobHandler) GetLogs(c *gin.Context) {
 	start := c.Query("start")
 	countStr := c.DefaultQuery("count", "100")
 	count, _ := strconv.ParseInt(countStr, 10, 64)
+	var filter domain.LogFilter
+	filter.Attempt, _ = strconv.Atoi(c.Query("attempt"))
+	if rankStr := c.Query("rank"); rankStr != "" {
+		rank, err := strconv.Atoi(rankStr)
+		if err != nil || rank < 0 {
+			response.Error(c, http.StatusBadRequest, "Invalid rank")
+			return
+		}
+		filter.Rank = &rank
+	}
+	// after 为上一页最后一条日志的 ID，从其之后继续读取
+	if after := c.Query("after"); after != "" {
+		start = "(" + after
+	}
+	filter.Levels = splitQueryList(c.Query("level"))
+	filter.Sources = splitQueryList(c.Query("source"))
+	if q := c.Query("q"); q != "" {
+		pattern, err := regexp.Compile(q)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid q: %v", err))
+			return
+		}
+		filter.Pattern = pattern
+	}
 
-	logs, err := h.service.GetLogs(c.Request.Context(), id, start, count)
+	logs, err := h.service.GetLogs(c.Request.Context(), id, start, count, filter)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
+		respondError(c, err)
 		return
 	}
 
 	response.Success(c, logs)
 }
 
+// ListAttempts 列出任务的所有执行尝试
+func (h *JobHandler) ListAttempts(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	attempts, err := h.service.ListAttempts(c.Request.Context(), id)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, attempts)
+}
+
+// ResumeJob 从检查点恢复训练，创建新任务
+func (h *JobHandler) ResumeJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var req domain.ResumeJobRequest
+	// 请求体可以为空，此时使用最佳或最新的检查点
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		// 临时使用默认用户 ID
+		userIDStr = "00000000-0000-0000-0000-000000000001"
+	}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid user_id")
+		return
+	}
+
+	job, err := h.service.ResumeJob(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, job.ToResponse())
+}
+
+// PauseJob 暂停运行中的任务，任务保存检查点并退出后可通过 resume 继续
+func (h *JobHandler) PauseJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var req domain.PauseJobRequest
+	// 请求体可以为空，此时使用服务配置的信号和宽限期
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	job, err := h.service.PauseJob(c.Request.Context(), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, job.ToResponse())
+}
+
 // streamLogsSSE SSE 流式输出日志
 func (h *JobHandler) streamLogsSSE(c *gin.Context, jobID uuid.UUID) {
 	ctx := c.Request.Context()
@@ -242,3 +354,14 @@ func (h *JobHandler) streamLogsSSE(c *gin.Context, jobID uuid.UUID) {
 		}
 	})
 }
+
+// splitQueryList 解析逗号分隔的查询参数，忽略空项
+func splitQueryList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
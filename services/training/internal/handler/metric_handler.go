@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/service"
+)
+
+// metricsTokenHeader 训练容器上报指标时携带令牌的请求头（也可使用 Authorization: Bearer）
+const metricsTokenHeader = "X-AITIP-Metrics-Token"
+
+// maxMetricsBodySize 单次上报的最大请求体
+const maxMetricsBodySize = 1 << 20
+
+// MetricHandler 训练指标处理器
+type MetricHandler struct {
+	service   service.MetricService
+	collector *executor.MetricCollector
+}
+
+// NewMetricHandler 创建训练指标处理器
+func NewMetricHandler(service service.MetricService, collector *executor.MetricCollector) *MetricHandler {
+	return &MetricHandler{service: service, collector: collector}
+}
+
+// RegisterRoutes 注册路由
+func (h *MetricHandler) RegisterRoutes(router *gin.RouterGroup) {
+	metrics := router.Group("/training/jobs/:id/metrics")
+	{
+		metrics.POST("", h.Report)
+		metrics.GET("", h.List)
+		metrics.GET("/ws", h.Live)
+	}
+}
+
+// Report 训练容器上报指标，请求体为单个指标对象或指标对象数组
+func (h *MetricHandler) Report(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxMetricsBodySize))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	var reports []*domain.MetricReport
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &reports)
+	} else {
+		var report domain.MetricReport
+		err = json.Unmarshal(trimmed, &report)
+		reports = []*domain.MetricReport{&report}
+	}
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+	if len(reports) == 0 {
+		response.Error(c, http.StatusBadRequest, "No metrics in request")
+		return
+	}
+
+	if err := h.service.Report(c.Request.Context(), jobID, metricsToken(c), reports); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"accepted": len(reports)})
+}
+
+// List 查询任务的指标曲线，长曲线按 max_points 降采样（downsample=lttb|bucket|none）
+func (h *MetricHandler) List(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	var query domain.MetricsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid query: %v", err))
+		return
+	}
+
+	series, err := h.service.List(c.Request.Context(), jobID, &query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, series)
+}
+
+// Live 通过 WebSocket 推送任务的实时指标和资源用量
+func (h *MetricHandler) Live(c *gin.Context) {
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	if err := h.service.CheckJob(c.Request.Context(), jobID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.collector.HandleWebSocket(c.Writer, c.Request, jobID)
+}
+
+// metricsToken 获取请求携带的指标上报令牌
+func metricsToken(c *gin.Context) string {
+	if token := c.GetHeader(metricsTokenHeader); token != "" {
+		return token
+	}
+	return strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
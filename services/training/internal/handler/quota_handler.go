@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/quota"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+)
+
+// QuotaHandler 配额处理器
+type QuotaHandler struct {
+	checker quota.Checker
+}
+
+// NewQuotaHandler 创建配额处理器
+func NewQuotaHandler(checker quota.Checker) *QuotaHandler {
+	return &QuotaHandler{checker: checker}
+}
+
+// RegisterRoutes 注册路由
+func (h *QuotaHandler) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/quotas", h.GetQuota)
+}
+
+// GetQuota 获取项目及所属组织的配额和使用情况
+func (h *QuotaHandler) GetQuota(c *gin.Context) {
+	projectID, err := uuid.Parse(c.Query("project_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+
+	report, err := h.checker.GetReport(c.Request.Context(), projectID)
+	if err != nil {
+		var appErr *apperrors.AppError
+		if errors.As(err, &appErr) {
+			response.AppError(c, appErr)
+			return
+		}
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, report)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// SecretHandler 项目密钥处理器，接口不返回密钥值，只允许能访问项目的用户操作
+type SecretHandler struct {
+	manager     secrets.Manager
+	projectRepo repository.ProjectRepository
+}
+
+// NewSecretHandler 创建密钥处理器
+func NewSecretHandler(manager secrets.Manager, projectRepo repository.ProjectRepository) *SecretHandler {
+	return &SecretHandler{manager: manager, projectRepo: projectRepo}
+}
+
+// RegisterRoutes 注册路由
+func (h *SecretHandler) RegisterRoutes(router *gin.RouterGroup) {
+	group := router.Group("/secrets")
+	{
+		group.POST("", h.CreateSecret)
+		group.GET("", h.ListSecrets)
+		group.GET("/:id", h.GetSecret)
+		group.PATCH("/:id", h.UpdateSecret)
+		group.DELETE("/:id", h.DeleteSecret)
+	}
+	router.GET("/secret-access-logs", h.ListAccessLogs)
+}
+
+// CreateSecret 创建密钥
+func (h *SecretHandler) CreateSecret(c *gin.Context) {
+	var req secrets.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	secret, err := h.manager.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, secret)
+}
+
+// ListSecrets 列出项目的密钥
+func (h *SecretHandler) ListSecrets(c *gin.Context) {
+	projectID, err := uuid.Parse(c.Query("project_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	list, err := h.manager.List(c.Request.Context(), projectID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, list)
+}
+
+// GetSecret 获取密钥
+func (h *SecretHandler) GetSecret(c *gin.Context) {
+	secret, _, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	response.Success(c, secret)
+}
+
+// UpdateSecret 更新密钥值或描述，已启动的容器仍使用旧值
+func (h *SecretHandler) UpdateSecret(c *gin.Context) {
+	var req secrets.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	current, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	secret, err := h.manager.Update(c.Request.Context(), userID, current.ID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, secret)
+}
+
+// DeleteSecret 删除密钥
+func (h *SecretHandler) DeleteSecret(c *gin.Context) {
+	current, userID, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	if err := h.manager.Delete(c.Request.Context(), userID, current.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Secret deleted successfully"})
+}
+
+// ListAccessLogs 分页列出项目密钥的访问审计记录
+func (h *SecretHandler) ListAccessLogs(c *gin.Context) {
+	var query secrets.AccessLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(query.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	logs, total, err := h.manager.ListAccessLogs(c.Request.Context(), &query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.SuccessWithMeta(c, logs, pageMeta(query.Page, query.PageSize, total))
+}
+
+// authorize 获取路径中的密钥并检查调用方能否访问其所属项目，失败时写入错误响应
+func (h *SecretHandler) authorize(c *gin.Context) (*secrets.Secret, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid secret ID")
+		return nil, uuid.Nil, false
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	secret, err := h.manager.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return nil, uuid.Nil, false
+	}
+	if !checkProject(c, h.projectRepo, userID, secret.ProjectID) {
+		return nil, uuid.Nil, false
+	}
+	return secret, userID, true
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+)
+
+// fakeSecretManager 只保存一个密钥，记录更新和删除
+type fakeSecretManager struct {
+	secrets.Manager
+
+	secret  *secrets.Secret
+	changed []string
+}
+
+func (m *fakeSecretManager) Create(ctx context.Context, userID uuid.UUID, req *secrets.CreateRequest) (*secrets.Secret, error) {
+	m.changed = append(m.changed, "create")
+	return m.secret, nil
+}
+
+func (m *fakeSecretManager) List(ctx context.Context, projectID uuid.UUID) ([]*secrets.Secret, error) {
+	return []*secrets.Secret{m.secret}, nil
+}
+
+func (m *fakeSecretManager) Get(ctx context.Context, id uuid.UUID) (*secrets.Secret, error) {
+	return m.secret, nil
+}
+
+func (m *fakeSecretManager) Update(ctx context.Context, userID, id uuid.UUID, req *secrets.UpdateRequest) (*secrets.Secret, error) {
+	m.changed = append(m.changed, "update")
+	return m.secret, nil
+}
+
+func (m *fakeSecretManager) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	m.changed = append(m.changed, "delete")
+	return nil
+}
+
+func (m *fakeSecretManager) ListAccessLogs(ctx context.Context, query *secrets.AccessLogQuery) ([]*secrets.AccessLog, int64, error) {
+	return nil, 0, nil
+}
+
+// fakeProjectRepo 只允许访问 allowed 中的项目，只允许管理 managed 中的组织
+type fakeProjectRepo struct {
+	allowed map[uuid.UUID]bool
+	managed map[uuid.UUID]bool
+}
+
+func (r *fakeProjectRepo) CanAccess(ctx context.Context, userID, projectID uuid.UUID) (bool, error) {
+	return r.allowed[projectID], nil
+}
+
+func (r *fakeProjectRepo) CanManageOrg(ctx context.Context, userID, orgID uuid.UUID) (bool, error) {
+	return r.managed[orgID], nil
+}
+
+func TestSecretHandlerProjectAccess(t *testing.T) {
+	secret := &secrets.Secret{ID: uuid.New(), ProjectID: uuid.New(), Name: "hf-token"}
+	secretPath := "/api/v1/secrets/" + secret.ID.String()
+	project := secret.ProjectID.String()
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		allowed     bool
+		wantStatus  int
+		wantChanged bool
+	}{
+		{"create allowed", http.MethodPost, "/api/v1/secrets", `{"project_id":"` + project + `","name":"hf-token","value":"hf_abcdefgh"}`, true, http.StatusCreated, true},
+		{"create forbidden", http.MethodPost, "/api/v1/secrets", `{"project_id":"` + project + `","name":"hf-token","value":"hf_abcdefgh"}`, false, http.StatusForbidden, false},
+		{"list allowed", http.MethodGet, "/api/v1/secrets?project_id=" + project, "", true, http.StatusOK, false},
+		{"list forbidden", http.MethodGet, "/api/v1/secrets?project_id=" + project, "", false, http.StatusForbidden, false},
+		{"get allowed", http.MethodGet, secretPath, "", true, http.StatusOK, false},
+		{"get forbidden", http.MethodGet, secretPath, "", false, http.StatusForbidden, false},
+		{"update forbidden", http.MethodPatch, secretPath, `{"description":"rotated"}`, false, http.StatusForbidden, false},
+		{"delete allowed", http.MethodDelete, secretPath, "", true, http.StatusOK, true},
+		{"delete forbidden", http.MethodDelete, secretPath, "", false, http.StatusForbidden, false},
+		{"access logs forbidden", http.MethodGet, "/api/v1/secret-access-logs?project_id=" + project, "", false, http.StatusForbidden, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &fakeSecretManager{secret: secret}
+			projects := &fakeProjectRepo{allowed: map[uuid.UUID]bool{secret.ProjectID: tt.allowed}}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewSecretHandler(manager, projects).RegisterRoutes(router.Group("/api/v1"))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if changed := len(manager.changed) > 0; changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/service"
+)
+
+// TemplateHandler 任务模板处理器
+type TemplateHandler struct {
+	service service.TemplateService
+}
+
+// NewTemplateHandler 创建任务模板处理器
+func NewTemplateHandler(service service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+// RegisterRoutes 注册路由
+func (h *TemplateHandler) RegisterRoutes(router *gin.RouterGroup) {
+	templates := router.Group("/training/templates")
+	{
+		templates.POST("", h.CreateTemplate)
+		templates.GET("", h.ListTemplates)
+		templates.GET("/builtin", h.ListBuiltinTemplates)
+		templates.GET("/:id", h.GetTemplate)
+		templates.DELETE("/:id", h.DeleteTemplate)
+		templates.GET("/:id/versions", h.ListVersions)
+		templates.POST("/:id/versions", h.CreateVersion)
+	}
+
+	router.POST("/training/jobs/from-template", h.CreateJob)
+}
+
+// CreateTemplate 创建任务模板
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req domain.CreateJobTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+
+	tmpl, err := h.service.CreateTemplate(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, tmpl)
+}
+
+// ListTemplates 列出项目的任务模板，每个模板返回最新版本
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	var req domain.ListJobTemplatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+
+	templates, total, err := h.service.ListTemplates(c.Request.Context(), &req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.SuccessWithMeta(c, templates, pageMeta(req.Page, req.PageSize, total))
+}
+
+// ListBuiltinTemplates 列出内置任务模板
+func (h *TemplateHandler) ListBuiltinTemplates(c *gin.Context) {
+	response.Success(c, h.service.ListBuiltinTemplates())
+}
+
+// GetTemplate 获取任务模板版本
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	tmpl, err := h.service.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, tmpl)
+}
+
+// DeleteTemplate 删除任务模板的所有版本
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	if err := h.service.DeleteTemplate(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ListVersions 列出任务模板的所有版本
+func (h *TemplateHandler) ListVersions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	versions, err := h.service.ListVersions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, versions)
+}
+
+// CreateVersion 以指定版本为基础创建任务模板的新版本
+func (h *TemplateHandler) CreateVersion(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var req domain.CreateJobTemplateVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+
+	tmpl, err := h.service.CreateVersion(c.Request.Context(), userID, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, tmpl)
+}
+
+// CreateJob 从任务模板创建训练任务
+func (h *TemplateHandler) CreateJob(c *gin.Context) {
+	var req domain.CreateJobFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.CreateJob(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, job.ToResponse())
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/response"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/webhook"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// WebhookHandler Webhook 处理器，只允许能访问项目的用户操作
+type WebhookHandler struct {
+	manager     webhook.Manager
+	projectRepo repository.ProjectRepository
+}
+
+// NewWebhookHandler 创建 Webhook 处理器
+func NewWebhookHandler(manager webhook.Manager, projectRepo repository.ProjectRepository) *WebhookHandler {
+	return &WebhookHandler{manager: manager, projectRepo: projectRepo}
+}
+
+// RegisterRoutes 注册路由
+func (h *WebhookHandler) RegisterRoutes(router *gin.RouterGroup) {
+	hooks := router.Group("/webhooks")
+	{
+		hooks.POST("", h.CreateWebhook)
+		hooks.GET("", h.ListWebhooks)
+		hooks.GET("/:id", h.GetWebhook)
+		hooks.PATCH("/:id", h.UpdateWebhook)
+		hooks.DELETE("/:id", h.DeleteWebhook)
+		hooks.GET("/:id/deliveries", h.ListDeliveries)
+		hooks.POST("/:id/deliveries/:delivery_id/redeliver", h.Redeliver)
+	}
+	router.GET("/webhook-deliveries", h.ListProjectDeliveries)
+}
+
+// CreateWebhook 注册 Webhook
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req webhook.CreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	created, err := h.manager.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Created(c, created)
+}
+
+// ListWebhooks 列出项目的 Webhook
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	projectID, err := uuid.Parse(c.Query("project_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	hooks, err := h.manager.List(c.Request.Context(), projectID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, hooks)
+}
+
+// GetWebhook 获取 Webhook
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	hook, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	response.Success(c, hook)
+}
+
+// UpdateWebhook 更新 Webhook
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var req webhook.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: %v", err))
+		return
+	}
+
+	hook, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.manager.Update(c.Request.Context(), hook.ID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// 只有轮换密钥时才返回新密钥
+	if updated.Secret == "" {
+		response.Success(c, updated.Webhook)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// DeleteWebhook 删除 Webhook
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	hook, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	if err := h.manager.Delete(c.Request.Context(), hook.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// ListDeliveries 列出 Webhook 的投递记录
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	var query webhook.DeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+
+	hook, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	query.WebhookID = &hook.ID
+
+	h.listDeliveries(c, &query)
+}
+
+// ListProjectDeliveries 列出项目全部 Webhook 的投递记录，status=dead_letter 时为死信列表
+func (h *WebhookHandler) ListProjectDeliveries(c *gin.Context) {
+	var query webhook.DeliveryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("Invalid query parameters: %v", err))
+		return
+	}
+	projectID, err := uuid.Parse(query.ProjectID)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid project_id")
+		return
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return
+	}
+	if !checkProject(c, h.projectRepo, userID, projectID) {
+		return
+	}
+
+	h.listDeliveries(c, &query)
+}
+
+// listDeliveries 分页返回投递记录
+func (h *WebhookHandler) listDeliveries(c *gin.Context, query *webhook.DeliveryQuery) {
+	deliveries, total, err := h.manager.ListDeliveries(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	totalPages := int(total) / query.PageSize
+	if int(total)%query.PageSize > 0 {
+		totalPages++
+	}
+
+	response.SuccessWithMeta(c, deliveries, &response.MetaInfo{
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	})
+}
+
+// Redeliver 重新投递一条投递记录
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	hook, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.manager.Redeliver(c.Request.Context(), hook.ID, deliveryID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, delivery)
+}
+
+// authorize 获取路径中的 Webhook 并检查调用方能否访问其所属项目，失败时写入错误响应
+func (h *WebhookHandler) authorize(c *gin.Context) (*webhook.Webhook, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid webhook ID")
+		return nil, false
+	}
+	userID, ok := contextUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	hook, err := h.manager.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	if !checkProject(c, h.projectRepo, userID, hook.ProjectID) {
+		return nil, false
+	}
+	return hook, true
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/webhook"
+)
+
+// fakeWebhookManager 只保存一个 Webhook，记录修改和读取投递记录的操作
+type fakeWebhookManager struct {
+	webhook.Manager
+
+	hook  *webhook.Webhook
+	calls []string
+}
+
+func (m *fakeWebhookManager) Create(ctx context.Context, userID uuid.UUID, req *webhook.CreateRequest) (*webhook.Created, error) {
+	m.calls = append(m.calls, "create")
+	return &webhook.Created{Webhook: m.hook, Secret: "whsec_0123456789abcdef"}, nil
+}
+
+func (m *fakeWebhookManager) List(ctx context.Context, projectID uuid.UUID) ([]*webhook.Webhook, error) {
+	m.calls = append(m.calls, "list")
+	return []*webhook.Webhook{m.hook}, nil
+}
+
+func (m *fakeWebhookManager) Get(ctx context.Context, id uuid.UUID) (*webhook.Webhook, error) {
+	return m.hook, nil
+}
+
+func (m *fakeWebhookManager) Update(ctx context.Context, id uuid.UUID, req *webhook.UpdateRequest) (*webhook.Created, error) {
+	m.calls = append(m.calls, "update")
+	return &webhook.Created{Webhook: m.hook}, nil
+}
+
+func (m *fakeWebhookManager) Delete(ctx context.Context, id uuid.UUID) error {
+	m.calls = append(m.calls, "delete")
+	return nil
+}
+
+func (m *fakeWebhookManager) ListDeliveries(ctx context.Context, query *webhook.DeliveryQuery) ([]*webhook.Delivery, int64, error) {
+	m.calls = append(m.calls, "deliveries")
+	return nil, 0, nil
+}
+
+func (m *fakeWebhookManager) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*webhook.Delivery, error) {
+	m.calls = append(m.calls, "redeliver")
+	return &webhook.Delivery{ID: deliveryID, WebhookID: webhookID}, nil
+}
+
+func TestWebhookHandlerProjectAccess(t *testing.T) {
+	hook := &webhook.Webhook{ID: uuid.New(), ProjectID: uuid.New(), URL: "https://example.com/hook"}
+	hookPath := "/api/v1/webhooks/" + hook.ID.String()
+	project := hook.ProjectID.String()
+	createBody := `{"project_id":"` + project + `","url":"https://example.com/hook"}`
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		allowed    bool
+		wantStatus int
+		wantCall   string
+	}{
+		{"create allowed", http.MethodPost, "/api/v1/webhooks", createBody, true, http.StatusCreated, "create"},
+		{"create forbidden", http.MethodPost, "/api/v1/webhooks", createBody, false, http.StatusForbidden, ""},
+		{"list allowed", http.MethodGet, "/api/v1/webhooks?project_id=" + project, "", true, http.StatusOK, "list"},
+		{"list forbidden", http.MethodGet, "/api/v1/webhooks?project_id=" + project, "", false, http.StatusForbidden, ""},
+		{"get allowed", http.MethodGet, hookPath, "", true, http.StatusOK, ""},
+		{"get forbidden", http.MethodGet, hookPath, "", false, http.StatusForbidden, ""},
+		{"update forbidden", http.MethodPatch, hookPath, `{"active":false}`, false, http.StatusForbidden, ""},
+		{"delete forbidden", http.MethodDelete, hookPath, "", false, http.StatusForbidden, ""},
+		{"deliveries allowed", http.MethodGet, hookPath + "/deliveries", "", true, http.StatusOK, "deliveries"},
+		{"deliveries forbidden", http.MethodGet, hookPath + "/deliveries", "", false, http.StatusForbidden, ""},
+		{"redeliver forbidden", http.MethodPost, hookPath + "/deliveries/" + uuid.NewString() + "/redeliver", "", false, http.StatusForbidden, ""},
+		{"project deliveries forbidden", http.MethodGet, "/api/v1/webhook-deliveries?project_id=" + project, "", false, http.StatusForbidden, ""},
+		{"project deliveries without project", http.MethodGet, "/api/v1/webhook-deliveries", "", true, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &fakeWebhookManager{hook: hook}
+			projects := &fakeProjectRepo{allowed: map[uuid.UUID]bool{hook.ProjectID: tt.allowed}}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			NewWebhookHandler(manager, projects).RegisterRoutes(router.Group("/api/v1"))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			var wantCalls []string
+			if tt.wantCall != "" {
+				wantCalls = []string{tt.wantCall}
+			}
+			if strings.Join(manager.calls, ",") != strings.Join(wantCalls, ",") {
+				t.Errorf("calls = %v, want %v", manager.calls, wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,383 @@
+package logarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	pkgminio "github.com/plucky-groove3/ai-train-infer-platform/pkg/minio"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// terminalGrace 任务结束后等待该时间再做最终归档，以收录退出时写入的系统日志
+const terminalGrace = 30 * time.Second
+
+// Config 日志归档配置
+type Config struct {
+	Bucket        string        // 归档段上传的 bucket
+	Interval      time.Duration // 检查日志流的间隔
+	SegmentLines  int           // 单个归档段的最大行数
+	MaxAge        time.Duration // 运行中任务的日志最长在未归档状态停留的时间，超过后不足一段也归档
+	CacheSegments int           // 内存中缓存的已解压归档段数量
+}
+
+// Archiver 日志归档器
+//
+// 日志先写入 Redis Stream（training:logs:<job_id>），流长度受上限约束。
+// 归档器定期把流中尚未归档的日志压缩为 gzip NDJSON 段上传到对象存储并写入
+// training_log_segments 表：运行中任务每满一段或超过 MaxAge 归档一次，
+// 任务结束后归档剩余日志并删除日志流。读取时按条目 ID 依次遍历归档段和日志流。
+type Archiver struct {
+	logRepo     repository.LogRepository
+	segmentRepo repository.LogSegmentRepository
+	jobRepo     repository.JobRepository
+	storage     *pkgminio.Client
+	cfg         Config
+
+	// 串行化归档过程，同一任务不会被同时归档
+	mu sync.Mutex
+
+	cacheMu    sync.Mutex
+	cache      map[uuid.UUID][]domain.LogEntry // 归档段 ID -> 段内日志
+	cacheOrder []uuid.UUID
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewArchiver 创建日志归档器
+func NewArchiver(logRepo repository.LogRepository, segmentRepo repository.LogSegmentRepository, jobRepo repository.JobRepository, storage *pkgminio.Client, cfg Config) *Archiver {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.SegmentLines <= 0 {
+		cfg.SegmentLines = 5000
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = 10 * time.Minute
+	}
+	if cfg.CacheSegments <= 0 {
+		cfg.CacheSegments = 16
+	}
+
+	return &Archiver{
+		logRepo:     logRepo,
+		segmentRepo: segmentRepo,
+		jobRepo:     jobRepo,
+		storage:     storage,
+		cfg:         cfg,
+		cache:       make(map[uuid.UUID][]domain.LogEntry),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start 确保 bucket 存在并启动归档循环
+func (a *Archiver) Start(ctx context.Context) error {
+	if err := a.storage.MakeBucket(ctx, a.cfg.Bucket); err != nil {
+		return fmt.Errorf("failed to ensure log archive bucket: %w", err)
+	}
+
+	a.wg.Add(1)
+	go a.loop()
+
+	logger.Info("Log archiver started",
+		zap.String("bucket", a.cfg.Bucket),
+		zap.Duration("interval", a.cfg.Interval),
+		zap.Int("segment_lines", a.cfg.SegmentLines),
+		zap.Duration("max_age", a.cfg.MaxAge),
+	)
+	return nil
+}
+
+// Stop 停止归档循环
+func (a *Archiver) Stop() {
+	close(a.stopCh)
+	a.wg.Wait()
+}
+
+// loop 归档循环
+func (a *Archiver) loop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), a.cfg.Interval*10)
+		a.archiveAll(ctx)
+		cancel()
+	}
+}
+
+// archiveAll 检查所有日志流并归档
+func (a *Archiver) archiveAll(ctx context.Context) {
+	jobIDs, err := a.logRepo.ListLogStreams(ctx)
+	if err != nil {
+		logger.Error("Failed to list log streams", zap.Error(err))
+		return
+	}
+
+	for _, jobID := range jobIDs {
+		final, ok := a.isFinal(ctx, jobID)
+		if !ok {
+			continue
+		}
+		if err := a.ArchiveJob(ctx, jobID, final); err != nil {
+			logger.Error("Failed to archive job logs", zap.String("job_id", jobID.String()), zap.Error(err))
+		}
+	}
+}
+
+// isFinal 判断任务的日志是否可以最终归档，ok 为 false 表示暂不处理
+func (a *Archiver) isFinal(ctx context.Context, jobID uuid.UUID) (final bool, ok bool) {
+	job, err := a.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			// 任务已删除，归档剩余日志后清理日志流
+			return true, true
+		}
+		logger.Warn("Failed to get job for log archival", zap.String("job_id", jobID.String()), zap.Error(err))
+		return false, false
+	}
+
+	if !job.IsTerminal() {
+		return false, true
+	}
+	if job.CompletedAt != nil && time.Since(*job.CompletedAt) < terminalGrace {
+		return false, false
+	}
+	return true, true
+}
+
+// ArchiveJob 归档任务日志流中尚未归档的日志
+//
+// final 为 false 时只归档满一段或最早一条超过 MaxAge 的日志；为 true 时归档全部剩余日志，
+// 并删除日志流中已归档的部分，日志流为空时删除日志流。
+func (a *Archiver) ArchiveJob(ctx context.Context, jobID uuid.UUID, final bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	last, err := a.segmentRepo.GetLast(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to get last log segment: %w", err)
+	}
+	after, seq := "", 1
+	if last != nil {
+		after, seq = last.LastID, last.Seq+1
+	}
+
+	for {
+		entries, err := a.logRepo.ReadLogs(ctx, jobID, rangeStart(after), int64(a.cfg.SegmentLines))
+		if err != nil {
+			return fmt.Errorf("failed to read log stream: %w", err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+		full := len(entries) >= a.cfg.SegmentLines
+		if !final && !full && time.Since(entries[0].Timestamp) < a.cfg.MaxAge {
+			break
+		}
+
+		segment, err := a.writeSegment(ctx, jobID, seq, entries)
+		if err != nil {
+			return err
+		}
+		after, seq = segment.LastID, seq+1
+
+		if !full {
+			break
+		}
+	}
+
+	if !final || after == "" {
+		return nil
+	}
+
+	id, err := parseStreamID(after)
+	if err != nil {
+		return err
+	}
+	remaining, err := a.logRepo.TrimLogStreamBefore(ctx, jobID, id.next().String())
+	if err != nil {
+		return fmt.Errorf("failed to trim log stream: %w", err)
+	}
+	if remaining == 0 {
+		if err := a.logRepo.DeleteLogStream(ctx, jobID); err != nil {
+			return fmt.Errorf("failed to delete log stream: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeSegment 压缩并上传一段日志，然后记录归档段
+func (a *Archiver) writeSegment(ctx context.Context, jobID uuid.UUID, seq int, entries []domain.LogEntry) (*domain.LogSegment, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(gz)
+	for i := range entries {
+		if err := encoder.Encode(&entries[i]); err != nil {
+			return nil, fmt.Errorf("failed to encode log entry: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress log segment: %w", err)
+	}
+
+	first, last := entries[0], entries[len(entries)-1]
+	// 对象名由序号和首条 ID 决定，记录失败后重试会覆盖同一对象
+	objectName := path.Join("logs", jobID.String(), fmt.Sprintf("%06d-%s.ndjson.gz", seq, first.ID))
+	size := int64(buf.Len())
+	if _, err := a.storage.Upload(ctx, a.cfg.Bucket, objectName, &buf, size, &pkgminio.UploadOptions{
+		ContentType: "application/gzip",
+	}); err != nil {
+		return nil, fmt.Errorf("failed to upload log segment: %w", err)
+	}
+
+	segment := &domain.LogSegment{
+		ID:          uuid.New(),
+		JobID:       jobID,
+		Seq:         seq,
+		FirstID:     first.ID,
+		LastID:      last.ID,
+		FirstTime:   first.Timestamp,
+		LastTime:    last.Timestamp,
+		Lines:       len(entries),
+		Size:        size,
+		StoragePath: objectName,
+		CreatedAt:   time.Now(),
+	}
+	if err := a.segmentRepo.Create(ctx, segment); err != nil {
+		return nil, fmt.Errorf("failed to record log segment: %w", err)
+	}
+
+	logger.Info("Archived log segment",
+		zap.String("job_id", jobID.String()),
+		zap.Int("seq", seq),
+		zap.Int("lines", len(entries)),
+		zap.Int64("size", size),
+	)
+	return segment, nil
+}
+
+// ReadLogs 按条目 ID 顺序读取满足过滤条件的日志，依次遍历归档段和日志流
+//
+// start 为空时从头读取；以 "(" 开头时从该条目之后读取；否则从该条目（含）开始读取。
+func (a *Archiver) ReadLogs(ctx context.Context, jobID uuid.UUID, filter domain.LogFilter, start string, count int64) ([]domain.LogEntry, error) {
+	if count <= 0 {
+		count = 100
+	}
+	after, err := afterCursor(start)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+	}
+
+	segments, err := a.segmentRepo.ListByJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list log segments: %w", err)
+	}
+
+	var logs []domain.LogEntry
+	liveAfter := after
+	for _, segment := range segments {
+		if liveAfter == "" || compareIDs(segment.LastID, liveAfter) > 0 {
+			liveAfter = segment.LastID
+		}
+		if after != "" && compareIDs(segment.LastID, after) <= 0 {
+			continue
+		}
+
+		entries, err := a.loadSegment(ctx, segment)
+		if err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			entry := &entries[i]
+			if after != "" && compareIDs(entry.ID, after) <= 0 {
+				continue
+			}
+			if !filter.Match(entry) {
+				continue
+			}
+			logs = append(logs, *entry)
+			if int64(len(logs)) >= count {
+				return logs, nil
+			}
+		}
+	}
+
+	// 日志流中可能仍保留已归档的日志，从最后一个归档段之后开始读取
+	live, err := a.logRepo.ReadFilteredLogs(ctx, jobID, filter, rangeStart(liveAfter), count-int64(len(logs)))
+	if err != nil {
+		return nil, err
+	}
+	return append(logs, live...), nil
+}
+
+// loadSegment 下载并解压归档段，结果缓存在内存中
+func (a *Archiver) loadSegment(ctx context.Context, segment *domain.LogSegment) ([]domain.LogEntry, error) {
+	a.cacheMu.Lock()
+	entries, ok := a.cache[segment.ID]
+	a.cacheMu.Unlock()
+	if ok {
+		return entries, nil
+	}
+
+	object, _, err := a.storage.Download(ctx, a.cfg.Bucket, segment.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download log segment %d: %w", segment.Seq, err)
+	}
+	defer object.Close()
+
+	gz, err := gzip.NewReader(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress log segment %d: %w", segment.Seq, err)
+	}
+	defer gz.Close()
+
+	entries = make([]domain.LogEntry, 0, segment.Lines)
+	decoder := json.NewDecoder(gz)
+	for {
+		var entry domain.LogEntry
+		if err := decoder.Decode(&entry); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode log segment %d: %w", segment.Seq, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	a.cacheMu.Lock()
+	if _, ok := a.cache[segment.ID]; !ok {
+		a.cache[segment.ID] = entries
+		a.cacheOrder = append(a.cacheOrder, segment.ID)
+		if len(a.cacheOrder) > a.cfg.CacheSegments {
+			delete(a.cache, a.cacheOrder[0])
+			a.cacheOrder = a.cacheOrder[1:]
+		}
+	}
+	a.cacheMu.Unlock()
+
+	return entries, nil
+}
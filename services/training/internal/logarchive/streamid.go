@@ -0,0 +1,112 @@
+package logarchive
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// streamID Redis Stream 条目 ID（<毫秒时间戳>-<序号>）
+type streamID struct {
+	ms  uint64
+	seq uint64
+}
+
+// parseStreamID 解析条目 ID，省略序号时视为 0
+func parseStreamID(id string) (streamID, error) {
+	msPart, seqPart, hasSeq := strings.Cut(id, "-")
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return streamID{}, fmt.Errorf("invalid log id %q", id)
+	}
+	var seq uint64
+	if hasSeq {
+		if seq, err = strconv.ParseUint(seqPart, 10, 64); err != nil {
+			return streamID{}, fmt.Errorf("invalid log id %q", id)
+		}
+	}
+	return streamID{ms: ms, seq: seq}, nil
+}
+
+func (id streamID) String() string {
+	return fmt.Sprintf("%d-%d", id.ms, id.seq)
+}
+
+// compare 比较两个条目 ID 的先后
+func (id streamID) compare(other streamID) int {
+	switch {
+	case id.ms < other.ms:
+		return -1
+	case id.ms > other.ms:
+		return 1
+	case id.seq < other.seq:
+		return -1
+	case id.seq > other.seq:
+		return 1
+	}
+	return 0
+}
+
+// next 紧随其后的条目 ID
+func (id streamID) next() streamID {
+	if id.seq == math.MaxUint64 {
+		return streamID{ms: id.ms + 1}
+	}
+	return streamID{ms: id.ms, seq: id.seq + 1}
+}
+
+// prev 紧挨在前的条目 ID，ok 为 false 表示不存在更小的 ID
+func (id streamID) prev() (streamID, bool) {
+	switch {
+	case id.seq > 0:
+		return streamID{ms: id.ms, seq: id.seq - 1}, true
+	case id.ms > 0:
+		return streamID{ms: id.ms - 1, seq: math.MaxUint64}, true
+	}
+	return streamID{}, false
+}
+
+// compareIDs 比较两个条目 ID 字符串，无法解析的 ID 按字符串比较
+func compareIDs(a, b string) int {
+	ida, errA := parseStreamID(a)
+	idb, errB := parseStreamID(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return ida.compare(idb)
+}
+
+// afterCursor 将分页起点转换为“读取该 ID 之后的日志”的游标，空字符串表示从头读取
+//
+// start 为空、"0" 或 "-" 时从头读取；以 "(" 开头时不包含该条目；否则包含该条目。
+func afterCursor(start string) (string, error) {
+	if start == "" || start == "0" || start == "-" {
+		return "", nil
+	}
+	if strings.HasPrefix(start, "(") {
+		id, err := parseStreamID(strings.TrimPrefix(start, "("))
+		if err != nil {
+			return "", err
+		}
+		return id.String(), nil
+	}
+
+	id, err := parseStreamID(start)
+	if err != nil {
+		return "", err
+	}
+	prev, ok := id.prev()
+	if !ok {
+		return "", nil
+	}
+	return prev.String(), nil
+}
+
+// rangeStart 读取 after 之后日志时 XRANGE 的起点
+func rangeStart(after string) string {
+	if after == "" {
+		return "-"
+	}
+	return "(" + after
+}
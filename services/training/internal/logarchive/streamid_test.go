@@ -0,0 +1,122 @@
+package logarchive
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseStreamID(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    streamID
+		wantErr bool
+	}{
+		{"1700000000000-3", streamID{ms: 1700000000000, seq: 3}, false},
+		{"1700000000000", streamID{ms: 1700000000000}, false},
+		{"0-0", streamID{}, false},
+		{"", streamID{}, true},
+		{"abc-1", streamID{}, true},
+		{"1-abc", streamID{}, true},
+		{"-1", streamID{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := parseStreamID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStreamID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseStreamID() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"5-1", "5-1", 0},
+		{"5-1", "5-2", -1},
+		{"5-10", "5-9", 1},
+		{"10-0", "9-5", 1},
+		{"5", "5-0", 0},
+		{"abc", "abd", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			if got := compareIDs(tt.a, tt.b); got != tt.want {
+				t.Errorf("compareIDs() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamIDNeighbours(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       streamID
+		wantNext streamID
+		wantPrev streamID
+		wantOK   bool
+	}{
+		{"same millisecond", streamID{ms: 5, seq: 2}, streamID{ms: 5, seq: 3}, streamID{ms: 5, seq: 1}, true},
+		{"previous millisecond", streamID{ms: 5}, streamID{ms: 5, seq: 1}, streamID{ms: 4, seq: math.MaxUint64}, true},
+		{"sequence overflow", streamID{ms: 5, seq: math.MaxUint64}, streamID{ms: 6}, streamID{ms: 5, seq: math.MaxUint64 - 1}, true},
+		{"smallest id", streamID{}, streamID{seq: 1}, streamID{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.next(); got != tt.wantNext {
+				t.Errorf("next() = %s, want %s", got, tt.wantNext)
+			}
+			got, ok := tt.id.prev()
+			if got != tt.wantPrev || ok != tt.wantOK {
+				t.Errorf("prev() = %s, %v, want %s, %v", got, ok, tt.wantPrev, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAfterCursor(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     string
+		want      string
+		wantRange string
+		wantErr   bool
+	}{
+		{"from the beginning", "", "", "-", false},
+		{"zero", "0", "", "-", false},
+		{"dash", "-", "", "-", false},
+		{"exclusive", "(5-2", "5-2", "(5-2", false},
+		{"exclusive without sequence", "(5", "5-0", "(5-0", false},
+		{"inclusive", "5-2", "5-1", "(5-1", false},
+		{"inclusive first of millisecond", "5-0", "4-18446744073709551615", "(4-18446744073709551615", false},
+		{"inclusive smallest id", "0-0", "", "-", false},
+		{"invalid", "abc", "", "", true},
+		{"invalid exclusive", "(abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := afterCursor(tt.start)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("afterCursor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("afterCursor() = %q, want %q", got, tt.want)
+			}
+			if start := rangeStart(got); start != tt.wantRange {
+				t.Errorf("rangeStart() = %q, want %q", start, tt.wantRange)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 推理服务状态
+const (
+	ServiceStatusPending = "pending"
+	ServiceStatusRunning = "running"
+	ServiceStatusStopped = "stopped"
+	ServiceStatusError   = "error"
+)
+
+// CreateServiceRequest 推理服务创建请求
+type CreateServiceRequest struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	ProjectID   string                 `json:"project_id"`
+	ModelID     string                 `json:"model_id"`
+	Type        string                 `json:"type"`
+	Config      map[string]interface{} `json:"config,omitempty"`
+	Environment map[string]string      `json:"environment,omitempty"`
+	GPUCount    int                    `json:"gpu_count"`
+	GPUType     string                 `json:"gpu_type,omitempty"`
+	CPUCount    int                    `json:"cpu_count"`
+	MemoryGB    int                    `json:"memory_gb"`
+}
+
+// InferenceService 推理服务返回的服务信息
+type InferenceService struct {
+	ID            uuid.UUID `json:"id"`
+	Status        string    `json:"status"`
+	StatusMessage string    `json:"status_message"`
+	EndpointURL   string    `json:"endpoint_url"`
+}
+
+// Dataset 数据服务返回的数据集信息
+type Dataset struct {
+	ID          uuid.UUID `json:"id"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	Name        string    `json:"name"`
+	StoragePath string    `json:"storage_path"` // <bucket>/<object>
+	Status      string    `json:"status"`
+	Checksum    string    `json:"checksum"`
+}
+
+// APIError 其他服务返回的错误
+type APIError struct {
+	Service    string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s service returned %d: %s", e.Service, e.StatusCode, e.Message)
+}
+
+// IsClientError 是否为请求本身的问题（重试不会成功）
+func (e *APIError) IsClientError() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 && e.StatusCode != http.StatusTooManyRequests
+}
+
+// InferenceClient 推理服务客户端接口
+type InferenceClient interface {
+	CreateService(ctx context.Context, userID uuid.UUID, req *CreateServiceRequest) (*InferenceService, error)
+	StartService(ctx context.Context, userID, serviceID uuid.UUID) error
+	GetService(ctx context.Context, userID, serviceID uuid.UUID) (*InferenceService, error)
+}
+
+// DatasetClient 数据服务客户端接口
+type DatasetClient interface {
+	GetDataset(ctx context.Context, userID, datasetID uuid.UUID) (*Dataset, error)
+}
+
+// NewInferenceClient 创建推理服务客户端
+func NewInferenceClient(baseURL string) InferenceClient {
+	return newAPIClient("inference", baseURL)
+}
+
+// NewDatasetClient 创建数据服务客户端
+func NewDatasetClient(baseURL string) DatasetClient {
+	return newAPIClient("data", baseURL)
+}
+
+// apiClient 通过 REST API 访问其他服务
+type apiClient struct {
+	service string
+	baseURL string
+	client  *http.Client
+}
+
+func newAPIClient(service, baseURL string) *apiClient {
+	return &apiClient{
+		service: service,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *apiClient) CreateService(ctx context.Context, userID uuid.UUID, req *CreateServiceRequest) (*InferenceService, error) {
+	var svc InferenceService
+	if err := c.do(ctx, http.MethodPost, "/api/v1/inference/services", userID, req, &svc); err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
+
+func (c *apiClient) StartService(ctx context.Context, userID, serviceID uuid.UUID) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/inference/services/"+serviceID.String()+"/start", userID, map[string]interface{}{}, nil)
+}
+
+func (c *apiClient) GetService(ctx context.Context, userID, serviceID uuid.UUID) (*InferenceService, error) {
+	var svc InferenceService
+	if err := c.do(ctx, http.MethodGet, "/api/v1/inference/services/"+serviceID.String(), userID, nil, &svc); err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
+
+func (c *apiClient) GetDataset(ctx context.Context, userID, datasetID uuid.UUID) (*Dataset, error) {
+	var dataset Dataset
+	if err := c.do(ctx, http.MethodGet, "/api/v1/datasets/"+datasetID.String(), userID, nil, &dataset); err != nil {
+		return nil, err
+	}
+	return &dataset, nil
+}
+
+// do 发送请求并解析标准响应结构中的 data 字段
+func (c *apiClient) do(ctx context.Context, method, path string, userID uuid.UUID, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", userID.String())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s service: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data  json.RawMessage `json:"data"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode %s service response: %w", c.service, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{Service: c.service, StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		if envelope.Error != nil {
+			apiErr.Message = envelope.Error.Message
+		}
+		return apiErr
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return fmt.Errorf("failed to decode %s service response: %w", c.service, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,656 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// JobLauncher 创建和查询训练任务，由 JobService 实现
+type JobLauncher interface {
+	CreateJob(ctx context.Context, userID uuid.UUID, req *domain.CreateJobRequest) (*domain.TrainingJob, error)
+	GetJob(ctx context.Context, jobID uuid.UUID) (*domain.TrainingJob, error)
+	StopJob(ctx context.Context, jobID uuid.UUID) error
+}
+
+// MetricReader 读取训练任务的指标曲线
+type MetricReader interface {
+	GetSeries(ctx context.Context, jobID uuid.UUID, metricType string) ([]*domain.MetricSeries, error)
+}
+
+// Config 流水线引擎配置
+type Config struct {
+	OutputBase string        // 未指定输出目录的训练步骤的输出根目录
+	Interval   time.Duration // 推进运行的间隔
+}
+
+// Engine 流水线执行引擎
+//
+// 周期性推进所有进行中的运行：按依赖顺序检查步骤，依赖全部完成后评估执行条件，
+// 训练步骤通过 JobLauncher 创建训练任务，部署步骤在推理服务中创建并启动服务；
+// 运行中的步骤同步训练任务或推理服务的状态。所有步骤结束后运行结束。
+// 状态保存在数据库中，服务重启后继续推进未完成的运行。
+type Engine struct {
+	repo      repository.PipelineRepository
+	models    repository.ModelRepository
+	jobs      JobLauncher
+	metrics   MetricReader
+	inference InferenceClient
+	datasets  DatasetClient
+	events    events.Publisher
+	config    Config
+
+	// mu 串行化推进循环与取消操作
+	mu sync.Mutex
+
+	notify chan struct{}
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewEngine 创建流水线引擎，inference 或 datasets 为 nil 时部署步骤或引用数据集的步骤失败
+func NewEngine(
+	repo repository.PipelineRepository,
+	models repository.ModelRepository,
+	jobs JobLauncher,
+	metrics MetricReader,
+	inference InferenceClient,
+	datasets DatasetClient,
+	config Config,
+) *Engine {
+	if config.Interval <= 0 {
+		config.Interval = 15 * time.Second
+	}
+
+	return &Engine{
+		repo:      repo,
+		models:    models,
+		jobs:      jobs,
+		metrics:   metrics,
+		inference: inference,
+		datasets:  datasets,
+		config:    config,
+		notify:    make(chan struct{}, 1),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// SetEventPublisher 设置运行结束事件的发布器
+func (e *Engine) SetEventPublisher(publisher events.Publisher) {
+	e.events = publisher
+}
+
+// Start 启动推进循环
+func (e *Engine) Start() {
+	e.wg.Add(1)
+	go e.loop()
+	logger.Info("Pipeline engine started", zap.Duration("interval", e.config.Interval))
+}
+
+// Stop 停止推进循环，已创建的训练任务和推理服务不受影响
+func (e *Engine) Stop() {
+	close(e.stopCh)
+	e.wg.Wait()
+}
+
+// Notify 立即触发一轮推进（例如新建运行或训练任务状态变化后）
+func (e *Engine) Notify() {
+	select {
+	case e.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run 以流水线当前的定义创建一次运行
+func (e *Engine) Run(ctx context.Context, pipeline *domain.Pipeline, userID uuid.UUID) (*domain.PipelineRun, error) {
+	run := &domain.PipelineRun{
+		ID:         uuid.New(),
+		PipelineID: pipeline.ID,
+		ProjectID:  pipeline.ProjectID,
+		UserID:     userID,
+		Name:       pipeline.Name,
+		Spec:       pipeline.Spec,
+		Status:     domain.PipelineRunRunning,
+	}
+	for _, step := range pipeline.Spec.Steps {
+		run.Steps = append(run.Steps, &domain.StepRun{
+			Name:   step.Name,
+			Type:   step.Type,
+			Status: domain.StepStatusPending,
+		})
+	}
+
+	if err := e.repo.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to create pipeline run: %w", err)
+	}
+
+	logger.Info("Pipeline run created",
+		zap.String("pipeline_id", pipeline.ID.String()),
+		zap.String("run_id", run.ID.String()),
+		zap.Int("number", run.Number),
+	)
+	e.Notify()
+	return run, nil
+}
+
+// Cancel 取消运行：停止运行中的训练任务，未开始的步骤不再执行，已部署的推理服务保留
+func (e *Engine) Cancel(ctx context.Context, runID uuid.UUID) (*domain.PipelineRun, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	run, err := e.repo.GetRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.IsTerminal() {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("pipeline run is already %s", run.Status))
+	}
+
+	for _, state := range run.Steps {
+		switch state.Status {
+		case domain.StepStatusRunning:
+			if state.JobID != nil {
+				if err := e.jobs.StopJob(ctx, *state.JobID); err != nil {
+					logger.Warn("Failed to stop pipeline step job",
+						zap.String("run_id", run.ID.String()),
+						zap.String("step", state.Name),
+						zap.Error(err),
+					)
+				}
+			}
+			state.Finish(domain.StepStatusCancelled, "Cancelled by user")
+		case domain.StepStatusPending:
+			state.Finish(domain.StepStatusCancelled, "Cancelled by user")
+		}
+	}
+	e.finish(run, domain.PipelineRunCancelled, "Cancelled by user")
+
+	if err := e.repo.UpdateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to update pipeline run: %w", err)
+	}
+	return run, nil
+}
+
+func (e *Engine) loop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	e.tick()
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+			e.tick()
+		case <-e.notify:
+			e.tick()
+		}
+	}
+}
+
+// tick 推进所有进行中的运行
+func (e *Engine) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.Interval*4)
+	defer cancel()
+
+	runs, err := e.repo.ListRunsByStatus(ctx, domain.PipelineRunRunning)
+	if err != nil {
+		logger.Error("Failed to list running pipeline runs", zap.Error(err))
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, run := range runs {
+		if err := e.advance(ctx, run.ID); err != nil {
+			logger.Error("Failed to advance pipeline run", zap.String("run_id", run.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// advance 推进单个运行
+func (e *Engine) advance(ctx context.Context, runID uuid.UUID) error {
+	// 取消操作可能在本轮列出运行之后发生
+	run, err := e.repo.GetRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if run.IsTerminal() {
+		return nil
+	}
+
+	order, err := run.Spec.Order()
+	if err != nil {
+		e.finish(run, domain.PipelineRunFailed, err.Error())
+		return e.repo.UpdateRun(ctx, run)
+	}
+
+	// 按依赖顺序处理，上游步骤在本轮结束后下游步骤可以立即开始
+	changed := false
+	for _, name := range order {
+		step, state := run.Spec.Step(name), run.Step(name)
+		if step == nil || state == nil {
+			continue
+		}
+		switch state.Status {
+		case domain.StepStatusPending:
+			if e.tryStart(ctx, run, step, state) {
+				changed = true
+			}
+		case domain.StepStatusRunning:
+			if e.refresh(ctx, run, step, state) {
+				changed = true
+			}
+		}
+	}
+
+	if e.finishIfDone(run) {
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return e.repo.UpdateRun(ctx, run)
+}
+
+// tryStart 依赖全部完成且条件满足时启动步骤，返回步骤状态是否变化
+func (e *Engine) tryStart(ctx context.Context, run *domain.PipelineRun, step *domain.PipelineStep, state *domain.StepRun) bool {
+	for _, dep := range step.DependsOn {
+		depState := run.Step(dep)
+		if !depState.Status.IsTerminal() {
+			return false
+		}
+		if depState.Status != domain.StepStatusCompleted {
+			state.Finish(domain.StepStatusSkipped, fmt.Sprintf("Dependency %s %s", dep, depState.Status))
+			return true
+		}
+	}
+
+	if step.When != nil {
+		ok, message, err := e.evaluate(ctx, run, step.When)
+		if err != nil {
+			logger.Warn("Failed to evaluate pipeline step condition",
+				zap.String("run_id", run.ID.String()),
+				zap.String("step", step.Name),
+				zap.Error(err),
+			)
+			return false
+		}
+		if !ok {
+			state.Finish(domain.StepStatusSkipped, message)
+			return true
+		}
+		state.Message = message
+	}
+
+	var err error
+	switch step.Type {
+	case domain.PipelineStepDeploy:
+		err = e.startDeploy(ctx, run, step, state)
+	default:
+		err = e.startTraining(ctx, run, step, state)
+	}
+	if err != nil {
+		if !permanent(err) {
+			// 依赖的服务暂不可用等情况，下一轮重试；已注册的模型需要保存，避免重复注册
+			logger.Warn("Failed to start pipeline step",
+				zap.String("run_id", run.ID.String()),
+				zap.String("step", step.Name),
+				zap.Error(err),
+			)
+			return state.ModelID != nil
+		}
+		state.Finish(domain.StepStatusFailed, err.Error())
+		return true
+	}
+
+	now := time.Now()
+	state.Status = domain.StepStatusRunning
+	state.StartedAt = &now
+	logger.Info("Pipeline step started",
+		zap.String("run_id", run.ID.String()),
+		zap.String("step", step.Name),
+		zap.String("type", string(step.Type)),
+	)
+
+	// 部署步骤创建服务后立即启动
+	if step.Type == domain.PipelineStepDeploy {
+		e.refresh(ctx, run, step, state)
+	}
+	return true
+}
+
+// startTraining 以步骤的任务模板创建训练任务
+func (e *Engine) startTraining(ctx context.Context, run *domain.PipelineRun, step *domain.PipelineStep, state *domain.StepRun) error {
+	req := *step.Job
+	req.Name = fmt.Sprintf("%s-%d-%s", run.Name, run.Number, step.Name)
+	if req.Description == "" {
+		req.Description = fmt.Sprintf("Pipeline %s run #%d, step %s", run.Name, run.Number, step.Name)
+	}
+	req.ProjectID = run.ProjectID.String()
+
+	switch {
+	case step.InputFrom != "":
+		req.DatasetPath = run.Step(step.InputFrom).OutputPath
+	case step.DatasetID != "":
+		datasetPath, err := e.resolveDataset(ctx, run, step.DatasetID)
+		if err != nil {
+			return err
+		}
+		req.DatasetPath = datasetPath
+	}
+	if req.OutputPath == "" {
+		req.OutputPath = path.Join(e.config.OutputBase, run.ID.String(), step.Name)
+	}
+
+	req.Environment = make(map[string]string, len(step.Job.Environment)+3)
+	for key, value := range step.Job.Environment {
+		req.Environment[key] = value
+	}
+	req.Environment["AITIP_PIPELINE_ID"] = run.PipelineID.String()
+	req.Environment["AITIP_PIPELINE_RUN_ID"] = run.ID.String()
+	req.Environment["AITIP_PIPELINE_STEP"] = step.Name
+
+	job, err := e.jobs.CreateJob(ctx, run.UserID, &req)
+	if err != nil {
+		return err
+	}
+
+	state.JobID = &job.ID
+	state.DatasetPath = req.DatasetPath
+	state.OutputPath = req.OutputPath
+	return nil
+}
+
+// resolveDataset 将数据集 ID 解析为训练任务的数据集路径
+func (e *Engine) resolveDataset(ctx context.Context, run *domain.PipelineRun, id string) (string, error) {
+	if e.datasets == nil {
+		return "", apperrors.New(http.StatusBadRequest, "data service is not configured")
+	}
+
+	datasetID, err := uuid.Parse(id)
+	if err != nil {
+		return "", apperrors.New(http.StatusBadRequest, fmt.Sprintf("invalid dataset_id: %s", id))
+	}
+	dataset, err := e.datasets.GetDataset(ctx, run.UserID, datasetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get dataset %s: %w", id, err)
+	}
+	if dataset.ProjectID != run.ProjectID {
+		return "", apperrors.New(http.StatusBadRequest, fmt.Sprintf("dataset %s belongs to another project", id))
+	}
+	if dataset.Status != "ready" {
+		return "", apperrors.New(http.StatusBadRequest, fmt.Sprintf("dataset %s is %s", id, dataset.Status))
+	}
+	return "s3://" + dataset.StoragePath, nil
+}
+
+// startDeploy 创建推理服务，未指定模型时先将上游步骤的输出注册为模型
+func (e *Engine) startDeploy(ctx context.Context, run *domain.PipelineRun, step *domain.PipelineStep, state *domain.StepRun) error {
+	if e.inference == nil {
+		return apperrors.New(http.StatusBadRequest, "inference service is not configured")
+	}
+	deploy := step.Deploy
+
+	if state.ModelID == nil {
+		if deploy.ModelID != "" {
+			modelID, err := uuid.Parse(deploy.ModelID)
+			if err != nil {
+				return apperrors.New(http.StatusBadRequest, fmt.Sprintf("invalid model_id: %s", deploy.ModelID))
+			}
+			state.ModelID = &modelID
+		} else {
+			source := run.Step(step.InputFrom)
+			name := deploy.ModelName
+			if name == "" {
+				name = run.Spec.Step(step.InputFrom).Job.ModelName
+			}
+			model := &repository.RegisteredModel{
+				ProjectID:     run.ProjectID,
+				Name:          name,
+				Version:       fmt.Sprintf("%s-%d", run.Name, run.Number),
+				StoragePath:   source.OutputPath,
+				TrainingJobID: source.JobID,
+			}
+			if err := e.models.Register(ctx, model); err != nil {
+				return fmt.Errorf("failed to register model: %w", err)
+			}
+			state.ModelID = &model.ID
+			state.DatasetPath = source.OutputPath
+		}
+	}
+
+	name := deploy.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", run.Name, step.Name)
+	}
+	svc, err := e.inference.CreateService(ctx, run.UserID, &CreateServiceRequest{
+		Name:        fmt.Sprintf("%s-%d", name, run.Number),
+		Description: fmt.Sprintf("Pipeline %s run #%d, step %s", run.Name, run.Number, step.Name),
+		ProjectID:   run.ProjectID.String(),
+		ModelID:     state.ModelID.String(),
+		Type:        deploy.Type,
+		Config:      deploy.Config,
+		Environment: deploy.Environment,
+		GPUCount:    deploy.GPUCount,
+		GPUType:     deploy.GPUType,
+		CPUCount:    deploy.CPUCount,
+		MemoryGB:    deploy.MemoryGB,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create inference service: %w", err)
+	}
+
+	state.ServiceID = &svc.ID
+	return nil
+}
+
+// refresh 同步运行中步骤的状态，返回步骤状态是否变化
+func (e *Engine) refresh(ctx context.Context, run *domain.PipelineRun, step *domain.PipelineStep, state *domain.StepRun) bool {
+	if step.Type == domain.PipelineStepDeploy {
+		return e.refreshDeploy(ctx, run, state)
+	}
+
+	job, err := e.jobs.GetJob(ctx, *state.JobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			state.Finish(domain.StepStatusFailed, "Training job was deleted")
+			return true
+		}
+		logger.Warn("Failed to get pipeline step job", zap.String("run_id", run.ID.String()), zap.String("step", state.Name), zap.Error(err))
+		return false
+	}
+
+	switch job.Status {
+	case domain.JobStatusCompleted:
+		state.Finish(domain.StepStatusCompleted, job.StatusMessage)
+	case domain.JobStatusFailed:
+		state.Finish(domain.StepStatusFailed, job.StatusMessage)
+	case domain.JobStatusCancelled:
+		state.Finish(domain.StepStatusCancelled, job.StatusMessage)
+	default:
+		return false
+	}
+	return true
+}
+
+// refreshDeploy 启动已创建的推理服务，服务运行后步骤完成
+func (e *Engine) refreshDeploy(ctx context.Context, run *domain.PipelineRun, state *domain.StepRun) bool {
+	if e.inference == nil {
+		state.Finish(domain.StepStatusFailed, "inference service is not configured")
+		return true
+	}
+
+	svc, err := e.inference.GetService(ctx, run.UserID, *state.ServiceID)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			state.Finish(domain.StepStatusFailed, "Inference service was deleted")
+			return true
+		}
+		logger.Warn("Failed to get pipeline step service", zap.String("run_id", run.ID.String()), zap.String("step", state.Name), zap.Error(err))
+		return false
+	}
+
+	switch svc.Status {
+	case ServiceStatusRunning:
+		state.Finish(domain.StepStatusCompleted, fmt.Sprintf("Service running at %s", svc.EndpointURL))
+		return true
+	case ServiceStatusError:
+		state.Finish(domain.StepStatusFailed, svc.StatusMessage)
+		return true
+	case ServiceStatusStopped:
+		state.Finish(domain.StepStatusFailed, "Inference service was stopped")
+		return true
+	case ServiceStatusPending:
+	default:
+		return false
+	}
+
+	// 服务已创建但尚未启动；409 表示服务已在启动中
+	if err := e.inference.StartService(ctx, run.UserID, svc.ID); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			return false
+		}
+		if permanent(err) {
+			state.Finish(domain.StepStatusFailed, fmt.Sprintf("failed to start inference service: %v", err))
+			return true
+		}
+		logger.Warn("Failed to start pipeline step service", zap.String("run_id", run.ID.String()), zap.String("step", state.Name), zap.Error(err))
+	}
+	return false
+}
+
+// evaluate 评估步骤执行条件，返回是否满足及说明
+func (e *Engine) evaluate(ctx context.Context, run *domain.PipelineRun, cond *domain.PipelineCondition) (bool, string, error) {
+	source := run.Step(cond.Step)
+	if source == nil || source.Status != domain.StepStatusCompleted || source.JobID == nil {
+		return false, fmt.Sprintf("Condition %s not met: step %s did not complete", cond, cond.Step), nil
+	}
+
+	series, err := e.metrics.GetSeries(ctx, *source.JobID, cond.Metric)
+	if err != nil {
+		return false, "", err
+	}
+	if len(series) == 0 || len(series[0].Points) == 0 {
+		return false, fmt.Sprintf("Condition %s not met: metric %s was not reported", cond, cond.Metric), nil
+	}
+
+	points := series[0].Points
+	value := points[len(points)-1].Value
+	for _, point := range points {
+		switch {
+		case cond.Aggregate == "min" && point.Value < value:
+			value = point.Value
+		case cond.Aggregate == "max" && point.Value > value:
+			value = point.Value
+		}
+	}
+
+	if !cond.Evaluate(value) {
+		return false, fmt.Sprintf("Condition %s not met (%s = %g)", cond, cond.Metric, value), nil
+	}
+	return true, fmt.Sprintf("Condition %s met (%s = %g)", cond, cond.Metric, value), nil
+}
+
+// finishIfDone 所有步骤结束后结束运行，返回运行是否结束
+func (e *Engine) finishIfDone(run *domain.PipelineRun) bool {
+	var failed, cancelled *domain.StepRun
+	for _, state := range run.Steps {
+		if !state.Status.IsTerminal() {
+			return false
+		}
+		if state.Status == domain.StepStatusFailed && failed == nil {
+			failed = state
+		}
+		if state.Status == domain.StepStatusCancelled && cancelled == nil {
+			cancelled = state
+		}
+	}
+
+	switch {
+	case failed != nil:
+		e.finish(run, domain.PipelineRunFailed, fmt.Sprintf("Step %s failed: %s", failed.Name, failed.Message))
+	case cancelled != nil:
+		e.finish(run, domain.PipelineRunCancelled, fmt.Sprintf("Step %s was cancelled", cancelled.Name))
+	default:
+		e.finish(run, domain.PipelineRunCompleted, fmt.Sprintf("%d steps finished", len(run.Steps)))
+	}
+	return true
+}
+
+// finish 结束运行并发布事件
+func (e *Engine) finish(run *domain.PipelineRun, status domain.PipelineRunStatus, message string) {
+	now := time.Now()
+	run.Status = status
+	run.Message = message
+	run.CompletedAt = &now
+
+	logger.Info("Pipeline run finished",
+		zap.String("run_id", run.ID.String()),
+		zap.String("status", string(status)),
+		zap.String("message", message),
+	)
+	e.publish(run)
+}
+
+// publish 发布运行结束事件，失败时只记录日志
+func (e *Engine) publish(run *domain.PipelineRun) {
+	if e.events == nil {
+		return
+	}
+
+	steps := make(map[string]string, len(run.Steps))
+	for _, state := range run.Steps {
+		steps[state.Name] = string(state.Status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := e.events.Publish(ctx, &events.Event{
+		Type:       "training.pipeline." + string(run.Status),
+		Source:     events.SourceTraining,
+		ProjectID:  run.ProjectID,
+		ResourceID: run.ID,
+		Status:     string(run.Status),
+		Message:    run.Message,
+		Data: map[string]interface{}{
+			"pipeline_id": run.PipelineID,
+			"number":      run.Number,
+			"steps":       steps,
+		},
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		logger.Warn("Failed to publish pipeline event", zap.String("run_id", run.ID.String()), zap.Error(err))
+	}
+}
+
+// permanent 错误是否为请求本身的问题（重试不会成功）
+func permanent(err error) bool {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code >= 400 && appErr.Code < 500
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsClientError()
+	}
+	return false
+}
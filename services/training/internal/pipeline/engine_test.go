@@ -0,0 +1,274 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// fakePipelineRepo 内存中只保存一个运行
+type fakePipelineRepo struct {
+	repository.PipelineRepository
+	run *domain.PipelineRun
+}
+
+func (r *fakePipelineRepo) GetRun(ctx context.Context, id uuid.UUID) (*domain.PipelineRun, error) {
+	return r.run, nil
+}
+
+func (r *fakePipelineRepo) UpdateRun(ctx context.Context, run *domain.PipelineRun) error {
+	r.run = run
+	return nil
+}
+
+// fakeJobLauncher 内存中的训练任务，createErr 不为空时创建失败
+type fakeJobLauncher struct {
+	jobs      map[uuid.UUID]*domain.TrainingJob
+	created   []*domain.CreateJobRequest
+	createErr error
+}
+
+func (l *fakeJobLauncher) CreateJob(ctx context.Context, userID uuid.UUID, req *domain.CreateJobRequest) (*domain.TrainingJob, error) {
+	if l.createErr != nil {
+		return nil, l.createErr
+	}
+	job := &domain.TrainingJob{ID: uuid.New(), Name: req.Name, Status: domain.JobStatusQueued}
+	l.jobs[job.ID] = job
+	l.created = append(l.created, req)
+	return job, nil
+}
+
+func (l *fakeJobLauncher) GetJob(ctx context.Context, jobID uuid.UUID) (*domain.TrainingJob, error) {
+	job, ok := l.jobs[jobID]
+	if !ok {
+		return nil, repository.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (l *fakeJobLauncher) StopJob(ctx context.Context, jobID uuid.UUID) error {
+	return nil
+}
+
+// fakeMetricReader 所有任务返回同一条指标曲线
+type fakeMetricReader struct {
+	values []float64
+}
+
+func (r *fakeMetricReader) GetSeries(ctx context.Context, jobID uuid.UUID, metricType string) ([]*domain.MetricSeries, error) {
+	series := &domain.MetricSeries{Name: metricType}
+	for _, value := range r.values {
+		series.Points = append(series.Points, domain.MetricPoint{Value: value})
+	}
+	return []*domain.MetricSeries{series}, nil
+}
+
+// testSpec prep -> train -> eval，eval 只在 train 的 val_accuracy 不低于 0.9 时执行
+func testSpec(t *testing.T) domain.PipelineSpec {
+	t.Helper()
+	spec := domain.PipelineSpec{Steps: []*domain.PipelineStep{
+		{Name: "prep", Job: &domain.CreateJobRequest{DatasetPath: "/data/raw"}},
+		{Name: "train", DependsOn: []string{"prep"}, Job: &domain.CreateJobRequest{}},
+		{
+			Name:      "eval",
+			DependsOn: []string{"train"},
+			Job:       &domain.CreateJobRequest{},
+			When:      &domain.PipelineCondition{Step: "train", Metric: "val_accuracy", Operator: ">=", Value: 0.9},
+		},
+	}}
+	if err := spec.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// stepState 测试开始时的步骤状态，job 不为空时步骤已创建该状态的训练任务
+type stepState struct {
+	status domain.StepStatus
+	job    domain.JobStatus
+}
+
+func TestEngineAdvance(t *testing.T) {
+	pending := stepState{status: domain.StepStatusPending}
+	completed := stepState{status: domain.StepStatusCompleted, job: domain.JobStatusCompleted}
+	running := func(job domain.JobStatus) stepState {
+		return stepState{status: domain.StepStatusRunning, job: job}
+	}
+
+	tests := []struct {
+		name        string
+		states      [3]stepState // prep、train、eval
+		createErr   error
+		deleted     bool // 运行中步骤的训练任务已被删除
+		metric      []float64
+		wantSteps   [3]domain.StepStatus
+		wantCreated []string
+		wantRun     domain.PipelineRunStatus
+		wantMessage string
+	}{
+		{
+			name:        "only roots start",
+			states:      [3]stepState{pending, pending, pending},
+			wantSteps:   [3]domain.StepStatus{domain.StepStatusRunning, domain.StepStatusPending, domain.StepStatusPending},
+			wantCreated: []string{"prep"},
+			wantRun:     domain.PipelineRunRunning,
+		},
+		{
+			name:      "running upstream blocks downstream",
+			states:    [3]stepState{running(domain.JobStatusRunning), pending, pending},
+			wantSteps: [3]domain.StepStatus{domain.StepStatusRunning, domain.StepStatusPending, domain.StepStatusPending},
+			wantRun:   domain.PipelineRunRunning,
+		},
+		{
+			name:        "downstream starts in the same pass",
+			states:      [3]stepState{running(domain.JobStatusCompleted), pending, pending},
+			wantSteps:   [3]domain.StepStatus{domain.StepStatusCompleted, domain.StepStatusRunning, domain.StepStatusPending},
+			wantCreated: []string{"train"},
+			wantRun:     domain.PipelineRunRunning,
+		},
+		{
+			name:        "failure skips all downstream steps",
+			states:      [3]stepState{running(domain.JobStatusFailed), pending, pending},
+			wantSteps:   [3]domain.StepStatus{domain.StepStatusFailed, domain.StepStatusSkipped, domain.StepStatusSkipped},
+			wantRun:     domain.PipelineRunFailed,
+			wantMessage: "Step prep failed",
+		},
+		{
+			name:        "cancelled job cancels the run",
+			states:      [3]stepState{completed, running(domain.JobStatusCancelled), pending},
+			wantSteps:   [3]domain.StepStatus{domain.StepStatusCompleted, domain.StepStatusCancelled, domain.StepStatusSkipped},
+			wantRun:     domain.PipelineRunCancelled,
+			wantMessage: "Step train was cancelled",
+		},
+		{
+			name:        "deleted job fails the step",
+			states:      [3]stepState{running(domain.JobStatusRunning), pending, pending},
+			deleted:     true,
+			wantSteps:   [3]domain.StepStatus{domain.StepStatusFailed, domain.StepStatusSkipped, domain.StepStatusSkipped},
+			wantRun:     domain.PipelineRunFailed,
+			wantMessage: "Training job was deleted",
+		},
+		{
+			name:        "condition met",
+			states:      [3]stepState{completed, running(domain.JobStatusCompleted), pending},
+			metric:      []float64{0.7, 0.93},
+			wantSteps:   [3]domain.StepStatus{domain.StepStatusCompleted, domain.StepStatusCompleted, domain.StepStatusRunning},
+			wantCreated: []string{"eval"},
+			wantRun:     domain.PipelineRunRunning,
+		},
+		{
+			name:      "condition not met skips the step",
+			states:    [3]stepState{completed, running(domain.JobStatusCompleted), pending},
+			metric:    []float64{0.95, 0.8},
+			wantSteps: [3]domain.StepStatus{domain.StepStatusCompleted, domain.StepStatusCompleted, domain.StepStatusSkipped},
+			wantRun:   domain.PipelineRunCompleted,
+		},
+		{
+			name:      "unreported metric skips the step",
+			states:    [3]stepState{completed, running(domain.JobStatusCompleted), pending},
+			wantSteps: [3]domain.StepStatus{domain.StepStatusCompleted, domain.StepStatusCompleted, domain.StepStatusSkipped},
+			wantRun:   domain.PipelineRunCompleted,
+		},
+		{
+			name:        "rejected job fails the step",
+			states:      [3]stepState{pending, pending, pending},
+			createErr:   apperrors.New(http.StatusBadRequest, "invalid image"),
+			wantSteps:   [3]domain.StepStatus{domain.StepStatusFailed, domain.StepStatusSkipped, domain.StepStatusSkipped},
+			wantRun:     domain.PipelineRunFailed,
+			wantMessage: "invalid image",
+		},
+		{
+			name:      "transient create error retries next pass",
+			states:    [3]stepState{pending, pending, pending},
+			createErr: errors.New("connection refused"),
+			wantSteps: [3]domain.StepStatus{domain.StepStatusPending, domain.StepStatusPending, domain.StepStatusPending},
+			wantRun:   domain.PipelineRunRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec(t)
+			launcher := &fakeJobLauncher{jobs: make(map[uuid.UUID]*domain.TrainingJob), createErr: tt.createErr}
+			run := &domain.PipelineRun{ID: uuid.New(), ProjectID: uuid.New(), Name: "mnist", Number: 1, Spec: spec, Status: domain.PipelineRunRunning}
+			for i, step := range spec.Steps {
+				state := &domain.StepRun{Name: step.Name, Type: step.Type, Status: tt.states[i].status, OutputPath: "/output/" + step.Name}
+				if tt.states[i].job != "" {
+					jobID := uuid.New()
+					state.JobID = &jobID
+					if !tt.deleted {
+						launcher.jobs[jobID] = &domain.TrainingJob{ID: jobID, Status: tt.states[i].job}
+					}
+				}
+				run.Steps = append(run.Steps, state)
+			}
+
+			repo := &fakePipelineRepo{run: run}
+			e := NewEngine(repo, nil, launcher, &fakeMetricReader{values: tt.metric}, nil, nil, Config{OutputBase: "/output"})
+			if err := e.advance(context.Background(), run.ID); err != nil {
+				t.Fatalf("advance() error = %v", err)
+			}
+
+			var got [3]domain.StepStatus
+			for i, state := range repo.run.Steps {
+				got[i] = state.Status
+			}
+			if got != tt.wantSteps {
+				t.Errorf("steps = %v, want %v", got, tt.wantSteps)
+			}
+
+			var created []string
+			for _, req := range launcher.created {
+				created = append(created, req.Name[strings.LastIndex(req.Name, "-")+1:])
+			}
+			if !reflect.DeepEqual(created, tt.wantCreated) {
+				t.Errorf("created jobs for %v, want %v", created, tt.wantCreated)
+			}
+
+			if repo.run.Status != tt.wantRun {
+				t.Errorf("run status = %s, want %s (%s)", repo.run.Status, tt.wantRun, repo.run.Message)
+			}
+			if !strings.Contains(repo.run.Message, tt.wantMessage) {
+				t.Errorf("run message = %q, want %q", repo.run.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestEngineStartTrainingUsesUpstreamOutput(t *testing.T) {
+	spec := testSpec(t)
+	launcher := &fakeJobLauncher{jobs: make(map[uuid.UUID]*domain.TrainingJob)}
+	run := &domain.PipelineRun{ID: uuid.New(), PipelineID: uuid.New(), ProjectID: uuid.New(), Name: "mnist", Number: 3, Spec: spec}
+	run.Steps = []*domain.StepRun{
+		{Name: "prep", Status: domain.StepStatusCompleted, OutputPath: "/output/prep"},
+		{Name: "train", Status: domain.StepStatusPending},
+	}
+	e := NewEngine(&fakePipelineRepo{run: run}, nil, launcher, nil, nil, nil, Config{OutputBase: "/pipelines"})
+
+	if err := e.startTraining(context.Background(), run, spec.Step("train"), run.Step("train")); err != nil {
+		t.Fatalf("startTraining() error = %v", err)
+	}
+
+	req := launcher.created[0]
+	if req.Name != "mnist-3-train" || req.ProjectID != run.ProjectID.String() {
+		t.Errorf("name/project = %s/%s", req.Name, req.ProjectID)
+	}
+	if req.DatasetPath != "/output/prep" {
+		t.Errorf("dataset_path = %q, want upstream output", req.DatasetPath)
+	}
+	if want := "/pipelines/" + run.ID.String() + "/train"; req.OutputPath != want {
+		t.Errorf("output_path = %q, want %q", req.OutputPath, want)
+	}
+	if req.Environment["AITIP_PIPELINE_STEP"] != "train" || req.Environment["AITIP_PIPELINE_RUN_ID"] != run.ID.String() {
+		t.Errorf("environment = %v", req.Environment)
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AttemptRepository 任务尝试记录仓库接口
+type AttemptRepository interface {
+	Create(ctx context.Context, attempt *domain.JobAttempt) error
+	UpdateContainer(ctx context.Context, jobID uuid.UUID, attempt int, containerID, containerName string) error
+	Finish(ctx context.Context, jobID uuid.UUID, attempt int, status domain.JobStatus, exitCode *int, message string) error
+	ListByJob(ctx context.Context, jobID uuid.UUID) ([]*domain.JobAttempt, error)
+	AutoMigrate() error
+}
+
+// attemptRepository 任务尝试记录仓库实现
+type attemptRepository struct {
+	db *gorm.DB
+}
+
+// NewAttemptRepository 创建仓库实例
+func NewAttemptRepository(db *gorm.DB) AttemptRepository {
+	return &attemptRepository{db: db}
+}
+
+// Create 创建尝试记录
+func (r *attemptRepository) Create(ctx context.Context, attempt *domain.JobAttempt) error {
+	if attempt.StartedAt.IsZero() {
+		attempt.StartedAt = time.Now()
+	}
+	if attempt.Status == "" {
+		attempt.Status = domain.JobStatusRunning
+	}
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+// UpdateContainer 记录尝试使用的容器
+func (r *attemptRepository) UpdateContainer(ctx context.Context, jobID uuid.UUID, attempt int, containerID, containerName string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.JobAttempt{}).
+		Where("job_id = ? AND attempt = ?", jobID, attempt).
+		Updates(map[string]interface{}{
+			"container_id":   containerID,
+			"container_name": containerName,
+		}).Error
+}
+
+// Finish 结束尝试（仅更新仍在运行中的记录）
+func (r *attemptRepository) Finish(ctx context.Context, jobID uuid.UUID, attempt int, status domain.JobStatus, exitCode *int, message string) error {
+	updates := map[string]interface{}{
+		"status":         status,
+		"status_message": message,
+		"completed_at":   time.Now(),
+	}
+	if exitCode != nil {
+		updates["exit_code"] = *exitCode
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&domain.JobAttempt{}).
+		Where("job_id = ? AND attempt = ? AND status = ?", jobID, attempt, domain.JobStatusRunning).
+		Updates(updates).Error
+}
+
+// ListByJob 列出任务的所有尝试
+func (r *attemptRepository) ListByJob(ctx context.Context, jobID uuid.UUID) ([]*domain.JobAttempt, error) {
+	var attempts []*domain.JobAttempt
+	if err := r.db.WithContext(ctx).
+		Where("job_id = ?", jobID).
+		Order("attempt ASC").
+		Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *attemptRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.JobAttempt{})
+}
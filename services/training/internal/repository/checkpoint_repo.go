@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCheckpointNotFound 检查点不存在
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// CheckpointRepository 检查点仓库接口
+type CheckpointRepository interface {
+	Create(ctx context.Context, checkpoint *models.Checkpoint) error
+	Update(ctx context.Context, checkpoint *models.Checkpoint) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Checkpoint, error)
+	GetBySourcePath(ctx context.Context, jobID uuid.UUID, sourcePath string) (*models.Checkpoint, error)
+	ListByJob(ctx context.Context, jobID uuid.UUID) ([]*models.Checkpoint, error)
+	GetBest(ctx context.Context, jobID uuid.UUID) (*models.Checkpoint, error)
+	GetLatest(ctx context.Context, jobID uuid.UUID) (*models.Checkpoint, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	MarkBest(ctx context.Context, jobID uuid.UUID, metric string, higherIsBetter bool) error
+	AutoMigrate() error
+}
+
+// checkpointRepository 检查点仓库实现
+type checkpointRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckpointRepository 创建仓库实例
+func NewCheckpointRepository(db *gorm.DB) CheckpointRepository {
+	return &checkpointRepository{db: db}
+}
+
+// Create 创建检查点
+func (r *checkpointRepository) Create(ctx context.Context, checkpoint *models.Checkpoint) error {
+	return r.db.WithContext(ctx).Create(checkpoint).Error
+}
+
+// Update 更新检查点
+func (r *checkpointRepository) Update(ctx context.Context, checkpoint *models.Checkpoint) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(checkpoint).Error
+}
+
+// GetByID 根据 ID 获取检查点
+func (r *checkpointRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Checkpoint, error) {
+	var checkpoint models.Checkpoint
+	if err := r.db.WithContext(ctx).First(&checkpoint, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, id)
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// GetBySourcePath 根据宿主机路径获取任务的检查点
+func (r *checkpointRepository) GetBySourcePath(ctx context.Context, jobID uuid.UUID, sourcePath string) (*models.Checkpoint, error) {
+	var checkpoint models.Checkpoint
+	if err := r.db.WithContext(ctx).
+		Where("training_job_id = ? AND source_path = ?", jobID, sourcePath).
+		First(&checkpoint).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, sourcePath)
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// ListByJob 列出任务的检查点（按步数升序）
+func (r *checkpointRepository) ListByJob(ctx context.Context, jobID uuid.UUID) ([]*models.Checkpoint, error) {
+	var checkpoints []*models.Checkpoint
+	if err := r.db.WithContext(ctx).
+		Where("training_job_id = ?", jobID).
+		Order("step ASC").
+		Order("created_at ASC").
+		Find(&checkpoints).Error; err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+// GetBest 获取任务的最佳检查点
+func (r *checkpointRepository) GetBest(ctx context.Context, jobID uuid.UUID) (*models.Checkpoint, error) {
+	var checkpoint models.Checkpoint
+	if err := r.db.WithContext(ctx).
+		Where("training_job_id = ? AND is_best = ?", jobID, true).
+		First(&checkpoint).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no best checkpoint for job %s", ErrCheckpointNotFound, jobID)
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// GetLatest 获取任务最新的检查点
+func (r *checkpointRepository) GetLatest(ctx context.Context, jobID uuid.UUID) (*models.Checkpoint, error) {
+	var checkpoint models.Checkpoint
+	if err := r.db.WithContext(ctx).
+		Where("training_job_id = ?", jobID).
+		Order("step DESC").
+		Order("created_at DESC").
+		First(&checkpoint).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no checkpoint for job %s", ErrCheckpointNotFound, jobID)
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// Delete 删除检查点
+func (r *checkpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Checkpoint{}, "id = ?", id).Error
+}
+
+// MarkBest 按指标重新标记任务的最佳检查点，没有该指标的检查点不参与比较
+func (r *checkpointRepository) MarkBest(ctx context.Context, jobID uuid.UUID, metric string, higherIsBetter bool) error {
+	direction := "ASC"
+	if higherIsBetter {
+		direction = "DESC"
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Checkpoint{}).
+			Where("training_job_id = ? AND is_best = ?", jobID, true).
+			Update("is_best", false).Error; err != nil {
+			return err
+		}
+
+		var best models.Checkpoint
+		err := tx.Where("training_job_id = ?", jobID).
+			Where("metrics->>? IS NOT NULL", metric).
+			Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:  "(metrics->>?)::float " + direction + ", step DESC",
+				Vars: []interface{}{metric},
+			}}).
+			First(&best).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return tx.Model(&models.Checkpoint{}).
+			Where("id = ?", best.ID).
+			Update("is_best", true).Error
+	})
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *checkpointRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&models.Checkpoint{})
+}
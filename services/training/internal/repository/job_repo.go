@@ -2,14 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrJobNotFound 训练任务不存在
+var ErrJobNotFound = errors.New("training job not found")
+
 // JobRepository 训练任务仓库接口
 type JobRepository interface {
 	Create(ctx context.Context, job *domain.TrainingJob) error
@@ -24,6 +29,31 @@ type JobRepository interface {
 	
 	// 运行统计
 	GetExperimentMetrics(ctx context.Context, experimentID uuid.UUID) (map[string]interface{}, error)
+
+	// 调度队列
+	ListQueued(ctx context.Context) ([]*domain.TrainingJob, error)
+	ListByStatus(ctx context.Context, statuses ...domain.JobStatus) ([]*domain.TrainingJob, error)
+	GetQueuePosition(ctx context.Context, job *domain.TrainingJob) (int, error)
+	TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.JobStatus, message string) (bool, error)
+
+	// 重试
+	Requeue(ctx context.Context, id uuid.UUID, attempt int, notBefore time.Time, message string) (bool, error)
+	UpdateContainer(ctx context.Context, id uuid.UUID, containerID, containerName string) error
+
+	// 强制结束
+	Terminate(ctx context.Context, id uuid.UUID, from domain.JobStatus, reason, message string) (bool, error)
+
+	// 暂停
+	MarkPaused(ctx context.Context, id uuid.UUID, checkpoint, message string) (bool, error)
+	Unpause(ctx context.Context, id uuid.UUID, attempt, priority int, message string) (bool, error)
+
+	// 产物
+	UpdateArtifactPath(ctx context.Context, id uuid.UUID, artifactPath string) error
+
+	// 事务
+	WithTx(tx *gorm.DB) JobRepository
+
+	AutoMigrate() error
 }
 
 // jobRepository 训练任务仓库实现
@@ -36,6 +66,11 @@ func NewJobRepository(db *gorm.DB) JobRepository {
 	return &jobRepository{db: db}
 }
 
+// WithTx 返回在事务 tx 中读写的仓库
+func (r *jobRepository) WithTx(tx *gorm.DB) JobRepository {
+	return &jobRepository{db: tx}
+}
+
 // Create 创建训练任务
 func (r *jobRepository) Create(ctx context.Context, job *domain.TrainingJob) error {
 	job.CreatedAt = time.Now()
@@ -44,6 +79,9 @@ func (r *jobRepository) Create(ctx context.Context, job *domain.TrainingJob) err
 	if job.Status == "" {
 		job.Status = domain.JobStatusPending
 	}
+	if job.Attempt == 0 {
+		job.Attempt = 1
+	}
 	
 	now := time.Now()
 	job.QueuedAt = &now
@@ -57,7 +95,7 @@ func (r *jobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Trai
 	err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("training job not found: %s", id)
+			return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
 		}
 		return nil, err
 	}
@@ -197,3 +235,192 @@ func (r *jobRepository) GetExperimentMetrics(ctx context.Context, experimentID u
 
 	return metrics, nil
 }
+
+// ListQueued 按调度顺序列出排队中的任务（优先级降序，提交时间升序）
+func (r *jobRepository) ListQueued(ctx context.Context) ([]*domain.TrainingJob, error) {
+	var jobs []*domain.TrainingJob
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", domain.JobStatusQueued).
+		Order("priority DESC").
+		Order("queued_at ASC").
+		Order("created_at ASC").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListByStatus 列出指定状态的任务
+func (r *jobRepository) ListByStatus(ctx context.Context, statuses ...domain.JobStatus) ([]*domain.TrainingJob, error) {
+	var jobs []*domain.TrainingJob
+	if err := r.db.WithContext(ctx).
+		Where("status IN ?", statuses).
+		Order("created_at ASC").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// GetQueuePosition 获取任务在队列中的位置（从 1 开始）
+func (r *jobRepository) GetQueuePosition(ctx context.Context, job *domain.TrainingJob) (int, error) {
+	if job.Status != domain.JobStatusQueued || job.QueuedAt == nil {
+		return 0, nil
+	}
+
+	var ahead int64
+	if err := r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("status = ?", domain.JobStatusQueued).
+		Where("id <> ?", job.ID).
+		Where("priority > ? OR (priority = ? AND queued_at < ?)", job.Priority, job.Priority, *job.QueuedAt).
+		Count(&ahead).Error; err != nil {
+		return 0, err
+	}
+
+	return int(ahead) + 1, nil
+}
+
+// TransitionStatus 仅当任务处于 from 状态时才更新为 to 状态，返回是否更新成功
+func (r *jobRepository) TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.JobStatus, message string) (bool, error) {
+	updates := map[string]interface{}{
+		"status":         to,
+		"status_message": message,
+		"updated_at":     time.Now(),
+	}
+
+	switch to {
+	case domain.JobStatusRunning:
+		updates["started_at"] = time.Now()
+	case domain.JobStatusCompleted, domain.JobStatusFailed, domain.JobStatusCancelled:
+		updates["completed_at"] = time.Now()
+	}
+
+	result := r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Requeue 将第 attempt 次尝试失败的运行中任务放回队列，notBefore 之前不会被调度
+func (r *jobRepository) Requeue(ctx context.Context, id uuid.UUID, attempt int, notBefore time.Time, message string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("id = ? AND status = ? AND attempt = ?", id, domain.JobStatusRunning, attempt).
+		Updates(map[string]interface{}{
+			"status":         domain.JobStatusQueued,
+			"status_message": message,
+			"attempt":        attempt + 1,
+			"next_retry_at":  notBefore,
+			"run_seconds":    runSecondsExpr(),
+			"container_id":   "",
+			"container_name": "",
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// runSecondsExpr 把当前尝试的运行时间累加到 run_seconds，started_at 在下次运行时会被重置
+func runSecondsExpr() clause.Expr {
+	return gorm.Expr("run_seconds + COALESCE(EXTRACT(EPOCH FROM (? - started_at)), 0)::bigint", time.Now())
+}
+
+// Terminate 仅当任务处于 from 状态时将其标记为失败，并记录终止原因
+func (r *jobRepository) Terminate(ctx context.Context, id uuid.UUID, from domain.JobStatus, reason, message string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{
+			"status":             domain.JobStatusFailed,
+			"status_message":     message,
+			"termination_reason": reason,
+			"completed_at":       time.Now(),
+			"updated_at":         time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// MarkPaused 将暂停中的任务标记为已暂停，checkpoint 为继续时使用的检查点
+func (r *jobRepository) MarkPaused(ctx context.Context, id uuid.UUID, checkpoint, message string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("id = ? AND status = ?", id, domain.JobStatusPausing).
+		Updates(map[string]interface{}{
+			"status":            domain.JobStatusPaused,
+			"status_message":    message,
+			"pauses":            gorm.Expr("pauses + 1"),
+			"paused_at":         time.Now(),
+			"paused_checkpoint": checkpoint,
+			"run_seconds":       runSecondsExpr(),
+			"container_id":      "",
+			"container_name":    "",
+			"updated_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Unpause 将第 attempt 次尝试时暂停的任务以 priority 放回队列
+func (r *jobRepository) Unpause(ctx context.Context, id uuid.UUID, attempt, priority int, message string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("id = ? AND status = ? AND attempt = ?", id, domain.JobStatusPaused, attempt).
+		Updates(map[string]interface{}{
+			"status":         domain.JobStatusQueued,
+			"status_message": message,
+			"attempt":        attempt + 1,
+			"priority":       priority,
+			"next_retry_at":  nil,
+			"queued_at":      time.Now(),
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateContainer 更新任务当前使用的容器
+func (r *jobRepository) UpdateContainer(ctx context.Context, id uuid.UUID, containerID, containerName string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"container_id":   containerID,
+			"container_name": containerName,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+// UpdateArtifactPath 记录任务输出上传到对象存储的位置
+func (r *jobRepository) UpdateArtifactPath(ctx context.Context, id uuid.UUID, artifactPath string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.TrainingJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"artifact_path": artifactPath,
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *jobRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.TrainingJob{})
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,9 +17,16 @@ type LogRepository interface {
 	// Redis Stream 操作
 	AppendLog(ctx context.Context, jobID uuid.UUID, entry *domain.LogEntry) error
 	ReadLogs(ctx context.Context, jobID uuid.UUID, start string, count int64) ([]domain.LogEntry, error)
+	ReadFilteredLogs(ctx context.Context, jobID uuid.UUID, filter domain.LogFilter, start string, count int64) ([]domain.LogEntry, error)
 	ReadLogsRealtime(ctx context.Context, jobID uuid.UUID, block time.Duration) ([]domain.LogEntry, error)
 	GetLogStreamLength(ctx context.Context, jobID uuid.UUID) (int64, error)
 	TrimLogStream(ctx context.Context, jobID uuid.UUID, maxLen int64) error
+	GetLastContainerLogTime(ctx context.Context, jobID uuid.UUID, rank *int) (time.Time, error)
+
+	// 归档
+	ListLogStreams(ctx context.Context) ([]uuid.UUID, error)
+	TrimLogStreamBefore(ctx context.Context, jobID uuid.UUID, minID string) (int64, error)
+	DeleteLogStream(ctx context.Context, jobID uuid.UUID) error
 	
 	// 文件存储（用于持久化）
 	SaveLogToFile(ctx context.Context, jobID uuid.UUID, content string) error
@@ -55,6 +64,12 @@ func (r *logRepository) AppendLog(ctx context.Context, jobID uuid.UUID, entry *d
 		"message":   entry.Message,
 		"timestamp": entry.Timestamp.Format(time.RFC3339Nano),
 	}
+	if entry.Attempt > 0 {
+		values["attempt"] = entry.Attempt
+	}
+	if entry.Rank != nil {
+		values["rank"] = *entry.Rank
+	}
 
 	_, err := r.redis.XAdd(ctx, &redis.XAddArgs{
 		Stream: streamKey,
@@ -74,6 +89,30 @@ func (r *logRepository) ReadLogs(ctx context.Context, jobID uuid.UUID, start str
 		count = 100
 	}
 
+	streamKey := r.getStreamKey(jobID)
+	messages, err := r.redis.XRangeN(ctx, streamKey, start, "+", count).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []domain.LogEntry
+	for _, msg := range messages {
+		log := parseLogEntry(msg)
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
+
+// ReadFilteredLogs 读取满足过滤条件的日志（从指定位置）
+func (r *logRepository) ReadFilteredLogs(ctx context.Context, jobID uuid.UUID, filter domain.LogFilter, start string, count int64) ([]domain.LogEntry, error) {
+	if start == "" {
+		start = "0"
+	}
+	if count == 0 {
+		count = 100
+	}
+
 	streamKey := r.getStreamKey(jobID)
 	messages, err := r.redis.XRange(ctx, streamKey, start, "+").Result()
 	if err != nil {
@@ -81,12 +120,15 @@ func (r *logRepository) ReadLogs(ctx context.Context, jobID uuid.UUID, start str
 	}
 
 	var logs []domain.LogEntry
-	for i, msg := range messages {
-		if int64(i) >= count {
+	for _, msg := range messages {
+		if int64(len(logs)) >= count {
 			break
 		}
-		
+
 		log := parseLogEntry(msg)
+		if !filter.Match(&log) {
+			continue
+		}
 		logs = append(logs, log)
 	}
 
@@ -134,6 +176,55 @@ func (r *logRepository) TrimLogStream(ctx context.Context, jobID uuid.UUID, maxL
 	return r.redis.XTrimMaxLen(ctx, streamKey, maxLen).Err()
 }
 
+// GetLastContainerLogTime 获取最后一条容器输出（非 system 来源）的时间，rank 非空时只看该节点的输出，不存在时返回零值
+func (r *logRepository) GetLastContainerLogTime(ctx context.Context, jobID uuid.UUID, rank *int) (time.Time, error) {
+	streamKey := r.getStreamKey(jobID)
+	messages, err := r.redis.XRevRangeN(ctx, streamKey, "+", "-", 100).Result()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, msg := range messages {
+		entry := parseLogEntry(msg)
+		if entry.Source != "system" && (domain.LogFilter{Rank: rank}).Match(&entry) {
+			return entry.Timestamp, nil
+		}
+	}
+
+	return time.Time{}, nil
+}
+
+// ListLogStreams 列出存在日志流的任务
+func (r *logRepository) ListLogStreams(ctx context.Context) ([]uuid.UUID, error) {
+	var jobIDs []uuid.UUID
+	iter := r.redis.Scan(ctx, 0, r.streamPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		jobID, err := uuid.Parse(strings.TrimPrefix(iter.Val(), r.streamPrefix))
+		if err != nil {
+			continue
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return jobIDs, nil
+}
+
+// TrimLogStreamBefore 删除条目 ID 小于 minID 的日志，返回剩余条目数
+func (r *logRepository) TrimLogStreamBefore(ctx context.Context, jobID uuid.UUID, minID string) (int64, error) {
+	streamKey := r.getStreamKey(jobID)
+	if err := r.redis.XTrimMinID(ctx, streamKey, minID).Err(); err != nil {
+		return 0, err
+	}
+	return r.redis.XLen(ctx, streamKey).Result()
+}
+
+// DeleteLogStream 删除任务的日志流
+func (r *logRepository) DeleteLogStream(ctx context.Context, jobID uuid.UUID) error {
+	return r.redis.Del(ctx, r.getStreamKey(jobID)).Err()
+}
+
 // SaveLogToFile 保存日志到文件（持久化）
 func (r *logRepository) SaveLogToFile(ctx context.Context, jobID uuid.UUID, content string) error {
 	// 暂时使用 Redis 存储完整日志，后续可以改为文件存储
@@ -150,11 +241,19 @@ func (r *logRepository) GetLogFromFile(ctx context.Context, jobID uuid.UUID) (st
 // parseLogEntry 解析日志条目
 func parseLogEntry(msg redis.XMessage) domain.LogEntry {
 	entry := domain.LogEntry{
+		ID:     msg.ID,
 		Level:  getString(msg.Values, "level"),
 		Source: getString(msg.Values, "source"),
 		Message: getString(msg.Values, "message"),
 	}
 
+	if attempt, err := strconv.Atoi(getString(msg.Values, "attempt")); err == nil {
+		entry.Attempt = attempt
+	}
+	if rank, err := strconv.Atoi(getString(msg.Values, "rank")); err == nil {
+		entry.Rank = &rank
+	}
+
 	// 解析时间戳
 	if tsStr := getString(msg.Values, "timestamp"); tsStr != "" {
 		if ts, err := time.Parse(time.RFC3339Nano, tsStr); err == nil {
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"gorm.io/gorm"
+)
+
+// LogSegmentRepository 日志归档段仓库接口
+type LogSegmentRepository interface {
+	Create(ctx context.Context, segment *domain.LogSegment) error
+	GetLast(ctx context.Context, jobID uuid.UUID) (*domain.LogSegment, error)
+	ListByJob(ctx context.Context, jobID uuid.UUID) ([]*domain.LogSegment, error)
+	AutoMigrate() error
+}
+
+// logSegmentRepository 日志归档段仓库实现
+type logSegmentRepository struct {
+	db *gorm.DB
+}
+
+// NewLogSegmentRepository 创建仓库实例
+func NewLogSegmentRepository(db *gorm.DB) LogSegmentRepository {
+	return &logSegmentRepository{db: db}
+}
+
+// Create 记录归档段
+func (r *logSegmentRepository) Create(ctx context.Context, segment *domain.LogSegment) error {
+	return r.db.WithContext(ctx).Create(segment).Error
+}
+
+// GetLast 获取任务最后一个归档段，没有归档段时返回 nil
+func (r *logSegmentRepository) GetLast(ctx context.Context, jobID uuid.UUID) (*domain.LogSegment, error) {
+	var segment domain.LogSegment
+	err := r.db.WithContext(ctx).
+		Where("job_id = ?", jobID).
+		Order("seq DESC").
+		First(&segment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &segment, nil
+}
+
+// ListByJob 按顺序列出任务的归档段
+func (r *logSegmentRepository) ListByJob(ctx context.Context, jobID uuid.UUID) ([]*domain.LogSegment, error) {
+	var segments []*domain.LogSegment
+	err := r.db.WithContext(ctx).
+		Where("job_id = ?", jobID).
+		Order("seq ASC").
+		Find(&segments).Error
+	return segments, err
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *logSegmentRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.LogSegment{})
+}
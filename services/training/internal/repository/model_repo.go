@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModelRepository 模型注册表仓库接口，推理服务从同一张表读取模型的存储路径
+type ModelRepository interface {
+	Register(ctx context.Context, model *RegisteredModel) error
+}
+
+// RegisteredModel 由训练任务输出注册的模型
+type RegisteredModel struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProjectID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Name          string     `gorm:"not null;size:255"`
+	Version       string     `gorm:"size:50;default:'v1'"`
+	StoragePath   string     `gorm:"size:500"`
+	TrainingJobID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt     time.Time
+}
+
+// TableName 表名
+func (RegisteredModel) TableName() string {
+	return "models"
+}
+
+// modelRepository 模型注册表仓库实现
+type modelRepository struct {
+	db *gorm.DB
+}
+
+// NewModelRepository 创建仓库实例
+func NewModelRepository(db *gorm.DB) ModelRepository {
+	return &modelRepository{db: db}
+}
+
+// Register 注册模型
+func (r *modelRepository) Register(ctx context.Context, model *RegisteredModel) error {
+	if model.ID == uuid.Nil {
+		model.ID = uuid.New()
+	}
+	return r.db.WithContext(ctx).Create(model).Error
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrPipelineNotFound 流水线不存在
+	ErrPipelineNotFound = errors.New("pipeline not found")
+	// ErrPipelineRunNotFound 流水线运行不存在
+	ErrPipelineRunNotFound = errors.New("pipeline run not found")
+)
+
+// PipelineRepository 流水线仓库接口
+type PipelineRepository interface {
+	Create(ctx context.Context, pipeline *domain.Pipeline) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Pipeline, error)
+	List(ctx context.Context, req *domain.ListPipelinesRequest) ([]*domain.Pipeline, int64, error)
+	Update(ctx context.Context, pipeline *domain.Pipeline) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// 运行记录
+	CreateRun(ctx context.Context, run *domain.PipelineRun) error
+	GetRun(ctx context.Context, id uuid.UUID) (*domain.PipelineRun, error)
+	ListRuns(ctx context.Context, pipelineID uuid.UUID, req *domain.ListPipelineRunsRequest) ([]*domain.PipelineRun, int64, error)
+	ListRunsByStatus(ctx context.Context, status domain.PipelineRunStatus) ([]*domain.PipelineRun, error)
+	CountActiveRuns(ctx context.Context, pipelineID uuid.UUID) (int64, error)
+	UpdateRun(ctx context.Context, run *domain.PipelineRun) error
+
+	AutoMigrate() error
+}
+
+// pipelineRepository 流水线仓库实现
+type pipelineRepository struct {
+	db *gorm.DB
+}
+
+// NewPipelineRepository 创建仓库实例
+func NewPipelineRepository(db *gorm.DB) PipelineRepository {
+	return &pipelineRepository{db: db}
+}
+
+// Create 创建流水线
+func (r *pipelineRepository) Create(ctx context.Context, pipeline *domain.Pipeline) error {
+	return r.db.WithContext(ctx).Create(pipeline).Error
+}
+
+// GetByID 根据 ID 获取流水线
+func (r *pipelineRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Pipeline, error) {
+	var pipeline domain.Pipeline
+	if err := r.db.WithContext(ctx).First(&pipeline, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrPipelineNotFound, id)
+		}
+		return nil, err
+	}
+	return &pipeline, nil
+}
+
+// List 列出项目的流水线
+func (r *pipelineRepository) List(ctx context.Context, req *domain.ListPipelinesRequest) ([]*domain.Pipeline, int64, error) {
+	var pipelines []*domain.Pipeline
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Pipeline{}).Where("project_id = ?", req.ProjectID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&pipelines).Error; err != nil {
+		return nil, 0, err
+	}
+	return pipelines, total, nil
+}
+
+// Update 更新流水线
+func (r *pipelineRepository) Update(ctx context.Context, pipeline *domain.Pipeline) error {
+	pipeline.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(pipeline).Error
+}
+
+// Delete 删除流水线及其运行记录
+func (r *pipelineRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("pipeline_id = ?", id).Delete(&domain.PipelineRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Pipeline{}, "id = ?", id).Error
+	})
+}
+
+// CreateRun 创建运行记录并分配运行编号
+func (r *pipelineRepository) CreateRun(ctx context.Context, run *domain.PipelineRun) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pipeline domain.Pipeline
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&pipeline, "id = ?", run.PipelineID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: %s", ErrPipelineNotFound, run.PipelineID)
+			}
+			return err
+		}
+
+		run.Number = pipeline.Runs + 1
+		if err := tx.Model(&domain.Pipeline{}).
+			Where("id = ?", pipeline.ID).
+			UpdateColumn("runs", run.Number).Error; err != nil {
+			return err
+		}
+		return tx.Create(run).Error
+	})
+}
+
+// GetRun 根据 ID 获取运行记录
+func (r *pipelineRepository) GetRun(ctx context.Context, id uuid.UUID) (*domain.PipelineRun, error) {
+	var run domain.PipelineRun
+	if err := r.db.WithContext(ctx).First(&run, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrPipelineRunNotFound, id)
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// ListRuns 列出流水线的运行记录，按编号倒序
+func (r *pipelineRepository) ListRuns(ctx context.Context, pipelineID uuid.UUID, req *domain.ListPipelineRunsRequest) ([]*domain.PipelineRun, int64, error) {
+	var runs []*domain.PipelineRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.PipelineRun{}).Where("pipeline_id = ?", pipelineID)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("number DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// ListRunsByStatus 列出指定状态的运行记录
+func (r *pipelineRepository) ListRunsByStatus(ctx context.Context, status domain.PipelineRunStatus) ([]*domain.PipelineRun, error) {
+	var runs []*domain.PipelineRun
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at ASC").
+		Find(&runs).Error
+	return runs, err
+}
+
+// CountActiveRuns 统计流水线进行中的运行
+func (r *pipelineRepository) CountActiveRuns(ctx context.Context, pipelineID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.PipelineRun{}).
+		Where("pipeline_id = ? AND status = ?", pipelineID, domain.PipelineRunRunning).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateRun 更新运行记录
+func (r *pipelineRepository) UpdateRun(ctx context.Context, run *domain.PipelineRun) error {
+	run.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *pipelineRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.Pipeline{}, &domain.PipelineRun{})
+}
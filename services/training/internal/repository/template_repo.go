@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTemplateNotFound 模板不存在
+	ErrTemplateNotFound = errors.New("job template not found")
+	// ErrTemplateExists 同名模板已存在或版本号冲突
+	ErrTemplateExists = errors.New("job template already exists")
+)
+
+// TemplateRepository 任务模板仓库接口
+type TemplateRepository interface {
+	Create(ctx context.Context, tmpl *domain.JobTemplate) error
+	CreateVersion(ctx context.Context, tmpl *domain.JobTemplate) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.JobTemplate, error)
+	GetByName(ctx context.Context, projectID uuid.UUID, name string, version int) (*domain.JobTemplate, error)
+	List(ctx context.Context, req *domain.ListJobTemplatesRequest) ([]*domain.JobTemplate, int64, error)
+	ListVersions(ctx context.Context, projectID uuid.UUID, name string) ([]*domain.JobTemplate, error)
+	DeleteByName(ctx context.Context, projectID uuid.UUID, name string) error
+	AutoMigrate() error
+}
+
+// templateRepository 任务模板仓库实现
+type templateRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepository 创建仓库实例
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+// Create 创建模板的第一个版本
+func (r *templateRepository) Create(ctx context.Context, tmpl *domain.JobTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.JobTemplate{}).
+			Where("project_id = ? AND name = ?", tmpl.ProjectID, tmpl.Name).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %s", ErrTemplateExists, tmpl.Name)
+		}
+
+		tmpl.Version = 1
+		return translateTemplateError(tx.Create(tmpl).Error, tmpl)
+	})
+}
+
+// CreateVersion 以当前最大版本号加一创建新版本，并发创建时由唯一索引保证版本号不重复
+func (r *templateRepository) CreateVersion(ctx context.Context, tmpl *domain.JobTemplate) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&domain.JobTemplate{}).
+			Where("project_id = ? AND name = ?", tmpl.ProjectID, tmpl.Name).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		if latest == 0 {
+			return fmt.Errorf("%w: %s", ErrTemplateNotFound, tmpl.Name)
+		}
+
+		tmpl.Version = latest + 1
+		return translateTemplateError(tx.Create(tmpl).Error, tmpl)
+	})
+}
+
+// GetByID 根据 ID 获取模板版本
+func (r *templateRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.JobTemplate, error) {
+	var tmpl domain.JobTemplate
+	if err := r.db.WithContext(ctx).First(&tmpl, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+		}
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// GetByName 按名称获取模板版本，version 为 0 时返回最新版本
+func (r *templateRepository) GetByName(ctx context.Context, projectID uuid.UUID, name string, version int) (*domain.JobTemplate, error) {
+	query := r.db.WithContext(ctx).Where("project_id = ? AND name = ?", projectID, name)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	var tmpl domain.JobTemplate
+	if err := query.Order("version DESC").First(&tmpl).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+		}
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// List 列出项目的模板，每个模板只返回最新版本
+func (r *templateRepository) List(ctx context.Context, req *domain.ListJobTemplatesRequest) ([]*domain.JobTemplate, int64, error) {
+	var templates []*domain.JobTemplate
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.JobTemplate{}).
+		Where("project_id = ?", req.ProjectID).
+		Where("version = (SELECT MAX(t.version) FROM job_templates t WHERE t.project_id = job_templates.project_id AND t.name = job_templates.name)")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("name ASC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&templates).Error; err != nil {
+		return nil, 0, err
+	}
+	return templates, total, nil
+}
+
+// ListVersions 列出模板的所有版本，按版本号倒序
+func (r *templateRepository) ListVersions(ctx context.Context, projectID uuid.UUID, name string) ([]*domain.JobTemplate, error) {
+	var templates []*domain.JobTemplate
+	err := r.db.WithContext(ctx).
+		Where("project_id = ? AND name = ?", projectID, name).
+		Order("version DESC").
+		Find(&templates).Error
+	return templates, err
+}
+
+// DeleteByName 删除模板的所有版本
+func (r *templateRepository) DeleteByName(ctx context.Context, projectID uuid.UUID, name string) error {
+	return r.db.WithContext(ctx).
+		Where("project_id = ? AND name = ?", projectID, name).
+		Delete(&domain.JobTemplate{}).Error
+}
+
+// AutoMigrate 自动迁移数据库表
+func (r *templateRepository) AutoMigrate() error {
+	return r.db.AutoMigrate(&domain.JobTemplate{})
+}
+
+// translateTemplateError 将唯一索引冲突转换为 ErrTemplateExists
+func translateTemplateError(err error, tmpl *domain.JobTemplate) error {
+	if err != nil && strings.Contains(err.Error(), "duplicate key") {
+		return fmt.Errorf("%w: %s version %d", ErrTemplateExists, tmpl.Name, tmpl.Version)
+	}
+	return err
+}
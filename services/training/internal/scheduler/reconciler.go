@@ -0,0 +1,312 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// Reconciler 训练任务状态协调器
+//
+// 执行器只在内存中跟踪容器，服务重启后会丢失日志收集和状态监控。
+// 协调器在启动时以及之后定期比对数据库中 running/stopping 状态的任务
+// 与实际容器状态：重新接管仍在运行的容器，并结束容器已消失或已退出的任务。
+type Reconciler struct {
+	jobRepo   repository.JobRepository
+	logRepo   repository.LogRepository
+	runtime   executor.Reconcilable
+	scheduler *Scheduler
+	interval  time.Duration
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewReconciler 创建协调器
+func NewReconciler(jobRepo repository.JobRepository, logRepo repository.LogRepository, runtime executor.Reconcilable, sched *Scheduler, interval time.Duration) *Reconciler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &Reconciler{
+		jobRepo:   jobRepo,
+		logRepo:   logRepo,
+		runtime:   runtime,
+		scheduler: sched,
+		interval:  interval,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start 执行一次启动协调并开始定期协调
+func (r *Reconciler) Start(ctx context.Context) error {
+	if err := r.Reconcile(ctx); err != nil {
+		return fmt.Errorf("failed to reconcile jobs on startup: %w", err)
+	}
+
+	r.wg.Add(1)
+	go r.loop()
+
+	logger.Info("Reconciler started", zap.Duration("interval", r.interval))
+	return nil
+}
+
+// Stop 停止定期协调
+func (r *Reconciler) Stop() {
+	close(r.stopCh)
+	r.wg.Wait()
+}
+
+// loop 定期协调循环
+func (r *Reconciler) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		if err := r.Reconcile(ctx); err != nil {
+			logger.Error("Reconcile cycle failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// Reconcile 比对任务与容器状态并修正
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	containers, err := r.runtime.ListManagedContainers(ctx)
+	if err != nil {
+		return err
+	}
+
+	byJob := make(map[uuid.UUID][]*domain.ContainerInfo, len(containers))
+	for _, c := range containers {
+		jobID, err := uuid.Parse(c.Labels["aitip.job.id"])
+		if err != nil {
+			continue
+		}
+		byJob[jobID] = append(byJob[jobID], c)
+	}
+
+	active, err := r.jobRepo.ListByStatus(ctx, domain.JobStatusRunning, domain.JobStatusStopping, domain.JobStatusPausing)
+	if err != nil {
+		return fmt.Errorf("failed to list active jobs: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(active))
+	for _, job := range active {
+		seen[job.ID] = true
+		r.reconcileJob(ctx, job, currentAttempt(job, byJob[job.ID]))
+	}
+
+	// 清理不属于任何活跃任务的运行中容器
+	for jobID, list := range byJob {
+		if seen[jobID] || r.runtime.IsTracked(jobID) {
+			continue
+		}
+		for _, c := range list {
+			if c.State == "running" {
+				r.reconcileOrphan(ctx, jobID, c)
+			}
+		}
+	}
+
+	return nil
+}
+
+// currentAttempt 筛选任务当前尝试的容器（分布式任务每个节点一个容器）
+func currentAttempt(job *domain.TrainingJob, containers []*domain.ContainerInfo) []*domain.ContainerInfo {
+	var current []*domain.ContainerInfo
+	for _, c := range containers {
+		attempt, ok := c.Labels["aitip.job.attempt"]
+		if !ok || attempt == strconv.Itoa(job.Attempt) {
+			current = append(current, c)
+		}
+	}
+	return current
+}
+
+// reconcileJob 协调单个活跃任务，containers 为任务当前尝试的全部节点容器
+func (r *Reconciler) reconcileJob(ctx context.Context, job *domain.TrainingJob, containers []*domain.ContainerInfo) {
+	// 正在拉取镜像或创建容器的任务由调度器负责
+	if r.scheduler.IsLaunching(job.ID) {
+		return
+	}
+
+	// 执行器仍在跟踪时，退出事件会由执行器回调处理
+	if r.runtime.IsTracked(job.ID) {
+		return
+	}
+
+	// 暂停过程中服务中断，停止剩余容器后完成暂停
+	if job.Status == domain.JobStatusPausing {
+		if err := r.removeContainers(ctx, job, containers); err != nil {
+			logger.Warn("Failed to remove container of pausing job", zap.String("job_id", job.ID.String()), zap.Error(err))
+			return
+		}
+		r.scheduler.FinishPause(ctx, job, "Paused (completed after service restart)")
+		return
+	}
+
+	if len(containers) == 0 {
+		if job.Status == domain.JobStatusStopping {
+			r.finish(ctx, job, domain.JobStatusCancelled, "Stopped (container no longer exists)")
+			return
+		}
+		r.finish(ctx, job, domain.JobStatusFailed, "Container lost: it no longer exists after the training service restarted or the host was cleaned up")
+		return
+	}
+
+	var running, created int
+	var failed *domain.ContainerInfo
+	for _, c := range containers {
+		switch c.State {
+		case "running", "restarting", "paused":
+			running++
+		case "created":
+			created++
+		default:
+			// exited / dead
+			if failed == nil && (c.ExitCode != 0 || c.State == "dead") {
+				failed = c
+			}
+		}
+	}
+	nodes := job.Distributed.Nodes()
+
+	switch {
+	case job.Status == domain.JobStatusStopping:
+		// 停止过程中服务中断，继续完成停止
+		if err := r.removeContainers(ctx, job, containers); err != nil {
+			logger.Warn("Failed to remove container of stopping job", zap.String("job_id", job.ID.String()), zap.Error(err))
+			return
+		}
+		r.finish(ctx, job, domain.JobStatusCancelled, "Stopped by user")
+
+	case running == nodes && len(containers) == nodes:
+		if err := r.runtime.Reattach(ctx, job, containers); err != nil {
+			logger.Error("Failed to reattach container", zap.String("job_id", job.ID.String()), zap.Error(err))
+			return
+		}
+		if nodes > 1 {
+			r.appendLog(ctx, job.ID, "INFO", fmt.Sprintf("Reattached to %d running node containers after service restart", nodes))
+		} else {
+			r.appendLog(ctx, job.ID, "INFO", fmt.Sprintf("Reattached to running container %s after service restart", containers[0].ContainerName))
+		}
+
+	case created > 0:
+		r.removeContainers(ctx, job, containers)
+		r.finish(ctx, job, domain.JobStatusFailed, "Container was created but never started before the training service restarted")
+
+	case running > 0 || len(containers) < nodes:
+		// 分布式任务的部分节点已退出或丢失，剩余节点无法继续训练
+		r.removeContainers(ctx, job, containers)
+		r.finish(ctx, job, domain.JobStatusFailed, fmt.Sprintf("Distributed training lost %d of %d nodes (detected after service restart)", nodes-running, nodes))
+
+	default:
+		status := domain.JobStatusCompleted
+		message := "Training completed successfully (detected after service restart)"
+		if failed != nil {
+			status = domain.JobStatusFailed
+			message = fmt.Sprintf("Training failed with exit code %d (detected after service restart)", failed.ExitCode)
+		}
+		r.removeContainers(ctx, job, containers)
+		r.finish(ctx, job, status, message)
+	}
+}
+
+// removeContainers 删除任务的节点容器及其专用网络
+func (r *Reconciler) removeContainers(ctx context.Context, job *domain.TrainingJob, containers []*domain.ContainerInfo) error {
+	var firstErr error
+	networks := make(map[string]bool)
+	for _, c := range containers {
+		if err := r.runtime.RemoveContainer(ctx, c.ContainerID); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if name := c.Labels["aitip.job.network"]; name != "" {
+			networks[name] = true
+		}
+	}
+
+	for name := range networks {
+		if err := r.runtime.RemoveNetwork(ctx, name); err != nil {
+			logger.Warn("Failed to remove job network", zap.String("job_id", job.ID.String()), zap.String("network", name), zap.Error(err))
+		}
+	}
+	return firstErr
+}
+
+// reconcileOrphan 清理任务已结束或已删除但仍在运行的容器
+func (r *Reconciler) reconcileOrphan(ctx context.Context, jobID uuid.UUID, c *domain.ContainerInfo) {
+	job, err := r.jobRepo.GetByID(ctx, jobID)
+	if err != nil && !errors.Is(err, repository.ErrJobNotFound) {
+		return
+	}
+	if job != nil && !job.IsTerminal() {
+		return
+	}
+
+	logger.Warn("Removing orphan training container",
+		zap.String("job_id", jobID.String()),
+		zap.String("container_id", c.ContainerID),
+	)
+	if err := r.runtime.RemoveContainer(ctx, c.ContainerID); err != nil {
+		logger.Error("Failed to remove orphan container", zap.String("container_id", c.ContainerID), zap.Error(err))
+	}
+}
+
+// finish 将任务更新为终止状态
+func (r *Reconciler) finish(ctx context.Context, job *domain.TrainingJob, status domain.JobStatus, message string) {
+	ok, err := r.jobRepo.TransitionStatus(ctx, job.ID, job.Status, status, message)
+	if err != nil {
+		logger.Error("Failed to reconcile job status", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	r.scheduler.FinishAttempt(ctx, job.ID, job.Attempt, status, nil, message)
+
+	level := "INFO"
+	if status == domain.JobStatusFailed {
+		level = "ERROR"
+	}
+	r.appendLog(ctx, job.ID, level, message)
+	r.scheduler.Emit(statusEventType(status), job, status, message)
+
+	logger.Info("Reconciled job",
+		zap.String("job_id", job.ID.String()),
+		zap.String("status", string(status)),
+		zap.String("message", message),
+	)
+
+	r.scheduler.Notify()
+}
+
+// appendLog 记录系统日志
+func (r *Reconciler) appendLog(ctx context.Context, jobID uuid.UUID, level, message string) {
+	r.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
+		Level:     level,
+		Source:    "system",
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
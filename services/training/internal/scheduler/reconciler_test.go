@@ -0,0 +1,251 @@
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+// fakeRuntime 执行器中的容器，记录重新接管和删除的容器
+type fakeRuntime struct {
+	containers []*domain.ContainerInfo
+	tracked    map[uuid.UUID]bool
+
+	reattached []string
+	removed    []string
+}
+
+func (r *fakeRuntime) IsTracked(jobID uuid.UUID) bool {
+	return r.tracked[jobID]
+}
+
+func (r *fakeRuntime) ListManagedContainers(ctx context.Context) ([]*domain.ContainerInfo, error) {
+	return r.containers, nil
+}
+
+func (r *fakeRuntime) Reattach(ctx context.Context, job *domain.TrainingJob, containers []*domain.ContainerInfo) error {
+	for _, c := range containers {
+		r.reattached = append(r.reattached, c.ContainerID)
+	}
+	return nil
+}
+
+func (r *fakeRuntime) RemoveContainer(ctx context.Context, containerID string) error {
+	r.removed = append(r.removed, containerID)
+	return nil
+}
+
+func (r *fakeRuntime) RemoveNetwork(ctx context.Context, name string) error {
+	return nil
+}
+
+// jobContainer 任务第 attempt 次尝试的容器
+func jobContainer(jobID uuid.UUID, attempt int, id, state string, exitCode int) *domain.ContainerInfo {
+	return &domain.ContainerInfo{
+		ContainerID:   id,
+		ContainerName: id,
+		State:         state,
+		ExitCode:      exitCode,
+		Labels: map[string]string{
+			"aitip.job.id":      jobID.String(),
+			"aitip.job.attempt": strconv.Itoa(attempt),
+		},
+	}
+}
+
+func TestReconcilerReconcileJob(t *testing.T) {
+	type container struct {
+		id       string
+		attempt  int
+		state    string
+		exitCode int
+	}
+	twoNodes := &domain.DistributedConfig{NumNodes: 2}
+
+	tests := []struct {
+		name           string
+		status         domain.JobStatus
+		distributed    *domain.DistributedConfig
+		containers     []container
+		tracked        bool
+		wantStatus     domain.JobStatus
+		wantReattached []string
+		wantRemoved    []string
+	}{
+		{
+			name:           "running container is reattached",
+			status:         domain.JobStatusRunning,
+			containers:     []container{{"c1", 2, "running", 0}},
+			wantStatus:     domain.JobStatusRunning,
+			wantReattached: []string{"c1"},
+		},
+		{
+			name:       "tracked job is left to the executor",
+			status:     domain.JobStatusRunning,
+			containers: []container{{"c1", 2, "exited", 1}},
+			tracked:    true,
+			wantStatus: domain.JobStatusRunning,
+		},
+		{
+			name:       "lost container fails the job",
+			status:     domain.JobStatusRunning,
+			wantStatus: domain.JobStatusFailed,
+		},
+		{
+			name:       "container of an earlier attempt does not count",
+			status:     domain.JobStatusRunning,
+			containers: []container{{"old", 1, "running", 0}},
+			wantStatus: domain.JobStatusFailed,
+		},
+		{
+			name:        "exited successfully",
+			status:      domain.JobStatusRunning,
+			containers:  []container{{"c1", 2, "exited", 0}},
+			wantStatus:  domain.JobStatusCompleted,
+			wantRemoved: []string{"c1"},
+		},
+		{
+			name:        "exited with an error",
+			status:      domain.JobStatusRunning,
+			containers:  []container{{"c1", 2, "exited", 2}},
+			wantStatus:  domain.JobStatusFailed,
+			wantRemoved: []string{"c1"},
+		},
+		{
+			name:        "created but never started",
+			status:      domain.JobStatusRunning,
+			containers:  []container{{"c1", 2, "created", 0}},
+			wantStatus:  domain.JobStatusFailed,
+			wantRemoved: []string{"c1"},
+		},
+		{
+			name:        "stopping job finishes stopping",
+			status:      domain.JobStatusStopping,
+			containers:  []container{{"c1", 2, "running", 0}},
+			wantStatus:  domain.JobStatusCancelled,
+			wantRemoved: []string{"c1"},
+		},
+		{
+			name:       "stopping job without container",
+			status:     domain.JobStatusStopping,
+			wantStatus: domain.JobStatusCancelled,
+		},
+		{
+			name:        "pausing job finishes pausing",
+			status:      domain.JobStatusPausing,
+			containers:  []container{{"c1", 2, "running", 0}},
+			wantStatus:  domain.JobStatusPaused,
+			wantRemoved: []string{"c1"},
+		},
+		{
+			name:           "all distributed nodes running",
+			status:         domain.JobStatusRunning,
+			distributed:    twoNodes,
+			containers:     []container{{"n0", 2, "running", 0}, {"n1", 2, "running", 0}},
+			wantStatus:     domain.JobStatusRunning,
+			wantReattached: []string{"n0", "n1"},
+		},
+		{
+			name:        "distributed node exited",
+			status:      domain.JobStatusRunning,
+			distributed: twoNodes,
+			containers:  []container{{"n0", 2, "running", 0}, {"n1", 2, "exited", 1}},
+			wantStatus:  domain.JobStatusFailed,
+			wantRemoved: []string{"n0", "n1"},
+		},
+		{
+			name:        "distributed node lost",
+			status:      domain.JobStatusRunning,
+			distributed: twoNodes,
+			containers:  []container{{"n0", 2, "running", 0}},
+			wantStatus:  domain.JobStatusFailed,
+			wantRemoved: []string{"n0"},
+		},
+		{
+			name:        "all distributed nodes completed",
+			status:      domain.JobStatusRunning,
+			distributed: twoNodes,
+			containers:  []container{{"n0", 2, "exited", 0}, {"n1", 2, "exited", 0}},
+			wantStatus:  domain.JobStatusCompleted,
+			wantRemoved: []string{"n0", "n1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &domain.TrainingJob{ID: uuid.New(), Status: tt.status, Attempt: 2, Distributed: tt.distributed, OutputPath: t.TempDir()}
+			jobs := newFakeJobRepo(job)
+			runtime := &fakeRuntime{tracked: map[uuid.UUID]bool{job.ID: tt.tracked}}
+			for _, c := range tt.containers {
+				runtime.containers = append(runtime.containers, jobContainer(job.ID, c.attempt, c.id, c.state, c.exitCode))
+			}
+			sched := NewScheduler(jobs, &fakeLogRepo{}, &fakeAttemptRepo{}, &fakeExecutor{}, Capacity{}, time.Minute)
+			r := NewReconciler(jobs, &fakeLogRepo{}, runtime, sched, time.Minute)
+
+			if err := r.Reconcile(context.Background()); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if got := jobs.status(job.ID); got != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got, tt.wantStatus)
+			}
+			sort.Strings(runtime.reattached)
+			if !equalStrings(runtime.reattached, tt.wantReattached) {
+				t.Errorf("reattached = %v, want %v", runtime.reattached, tt.wantReattached)
+			}
+			sort.Strings(runtime.removed)
+			if !equalStrings(runtime.removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", runtime.removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestReconcilerRemovesOrphans(t *testing.T) {
+	finished := &domain.TrainingJob{ID: uuid.New(), Status: domain.JobStatusCompleted, Attempt: 1}
+	queued := &domain.TrainingJob{ID: uuid.New(), Status: domain.JobStatusQueued, Attempt: 1}
+	deleted := uuid.New()
+	tracked := uuid.New()
+
+	jobs := newFakeJobRepo(finished, queued)
+	runtime := &fakeRuntime{
+		tracked: map[uuid.UUID]bool{tracked: true},
+		containers: []*domain.ContainerInfo{
+			jobContainer(finished.ID, 1, "finished", "running", 0),
+			jobContainer(queued.ID, 1, "queued", "running", 0),
+			jobContainer(deleted, 1, "deleted", "running", 0),
+			jobContainer(deleted, 1, "deleted-exited", "exited", 0),
+			jobContainer(tracked, 1, "tracked", "running", 0),
+		},
+	}
+	sched := NewScheduler(jobs, &fakeLogRepo{}, &fakeAttemptRepo{}, &fakeExecutor{}, Capacity{}, time.Minute)
+	r := NewReconciler(jobs, &fakeLogRepo{}, runtime, sched, time.Minute)
+
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	sort.Strings(runtime.removed)
+	if want := []string{"deleted", "finished"}; !equalStrings(runtime.removed, want) {
+		t.Errorf("removed = %v, want %v", runtime.removed, want)
+	}
+}
+
+// equalStrings 比较两个字符串切片，nil 与空切片视为相同
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,713 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// Capacity 可调度的资源容量
+type Capacity struct {
+	CPU      int
+	MemoryGB int
+	GPU      int
+	MaxJobs  int
+}
+
+// Resources 任务占用的资源
+type Resources struct {
+	CPU      int
+	MemoryGB int
+	GPU      int
+}
+
+// resourcesOf 计算任务申请的资源，分布式任务为单节点资源乘以节点数
+func resourcesOf(job *domain.TrainingJob) Resources {
+	nodes := job.Distributed.Nodes()
+	return Resources{
+		CPU:      job.CPUCount * nodes,
+		MemoryGB: job.MemoryGB * nodes,
+		GPU:      job.GPUCount * nodes,
+	}
+}
+
+// add 累加资源
+func (r Resources) add(o Resources) Resources {
+	return Resources{
+		CPU:      r.CPU + o.CPU,
+		MemoryGB: r.MemoryGB + o.MemoryGB,
+		GPU:      r.GPU + o.GPU,
+	}
+}
+
+// fits 检查资源是否在容量范围内
+func (c Capacity) fits(r Resources) bool {
+	return r.CPU <= c.CPU && r.MemoryGB <= c.MemoryGB && r.GPU <= c.GPU
+}
+
+// Checkpoints 调度器使用的检查点注册表
+type Checkpoints interface {
+	// Collect 任务结束后登记输出目录中剩余的检查点
+	Collect(ctx context.Context, jobID uuid.UUID)
+	// Materialize 返回检查点在本机可挂载的路径
+	Materialize(ctx context.Context, checkpointID uuid.UUID) (string, error)
+}
+
+// Datasets 为使用对象存储数据集的任务准备每次尝试的数据集
+type Datasets interface {
+	// Prepare 下载数据集或生成下载地址，结果记录在 job.DatasetMount、job.DatasetURL 上
+	Prepare(ctx context.Context, job *domain.TrainingJob) error
+}
+
+// Artifacts 任务结束后上传输出目录
+type Artifacts interface {
+	// Upload 上传任务输出目录中的产物
+	Upload(ctx context.Context, jobID uuid.UUID)
+}
+
+// GPUAllocator 为任务分配宿主机上的具体 GPU
+type GPUAllocator interface {
+	// Allocate 为任务的每个节点分配 perNode 个符合型号的 GPU
+	Allocate(jobID uuid.UUID, nodes, perNode int, gpuType string) ([][]executor.GPUInfo, error)
+	// Release 释放任务占用的 GPU
+	Release(jobID uuid.UUID)
+}
+
+// EventHandler 任务事件回调
+type EventHandler func(event *domain.JobEvent)
+
+// Scheduler 训练任务调度器
+//
+// 任务状态持久化在数据库中：排队任务按优先级降序、提交时间升序出队，
+// 已占用的资源由 running/stopping 状态的任务计算得出，因此服务重启后
+// 无需额外恢复即可继续调度。
+type Scheduler struct {
+	jobRepo     repository.JobRepository
+	logRepo     repository.LogRepository
+	attemptRepo repository.AttemptRepository
+	executor    executor.Executor
+	checkpoints Checkpoints
+	datasets    Datasets
+	artifacts   Artifacts
+	gpus        GPUAllocator
+	timeouts    *executor.TimeoutConfig
+	onEvent     EventHandler
+	capacity    Capacity
+	interval    time.Duration
+
+	mu        sync.Mutex
+	launching map[uuid.UUID]struct{} // 已派发但容器尚未启动完成的任务
+
+	wakeCh chan struct{}
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewScheduler 创建调度器
+func NewScheduler(jobRepo repository.JobRepository, logRepo repository.LogRepository, attemptRepo repository.AttemptRepository, exec executor.Executor, capacity Capacity, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	s := &Scheduler{
+		jobRepo:     jobRepo,
+		logRepo:     logRepo,
+		attemptRepo: attemptRepo,
+		executor:    exec,
+		timeouts:    executor.DefaultTimeoutConfig(),
+		capacity:    capacity,
+		interval:    interval,
+		launching:   make(map[uuid.UUID]struct{}),
+		wakeCh:      make(chan struct{}, 1),
+		stopCh:      make(chan struct{}),
+	}
+	exec.SetExitHandler(s.onJobExit)
+
+	return s
+}
+
+// SetCheckpoints 设置检查点注册表，未设置时只能从输出目录中的检查点恢复
+func (s *Scheduler) SetCheckpoints(checkpoints Checkpoints) {
+	s.checkpoints = checkpoints
+}
+
+// SetDatasets 设置数据集准备器，未设置时对象存储中的数据集由执行器自行处理
+func (s *Scheduler) SetDatasets(datasets Datasets) {
+	s.datasets = datasets
+}
+
+// SetArtifacts 设置产物上传器，未设置时任务输出只保留在输出目录中
+func (s *Scheduler) SetArtifacts(artifacts Artifacts) {
+	s.artifacts = artifacts
+}
+
+// SetGPUAllocator 设置 GPU 分配器，设置后派发任务时预留具体设备，未设置时只按 GPU 数量调度
+func (s *Scheduler) SetGPUAllocator(gpus GPUAllocator) {
+	s.gpus = gpus
+}
+
+// SetTimeouts 设置超时配置，StartupTimeout 限制启动任务的时间
+func (s *Scheduler) SetTimeouts(timeouts *executor.TimeoutConfig) {
+	s.timeouts = timeouts
+}
+
+// SetEventHandler 设置任务事件回调
+func (s *Scheduler) SetEventHandler(handler EventHandler) {
+	s.onEvent = handler
+}
+
+// Start 恢复排队任务并启动调度循环
+func (s *Scheduler) Start(ctx context.Context) error {
+	if err := s.recoverPending(ctx); err != nil {
+		return fmt.Errorf("failed to recover pending jobs: %w", err)
+	}
+
+	s.wg.Add(1)
+	go s.loop()
+
+	s.Notify()
+
+	logger.Info("Scheduler started",
+		zap.Int("cpu_capacity", s.capacity.CPU),
+		zap.Int("memory_gb_capacity", s.capacity.MemoryGB),
+		zap.Int("gpu_capacity", s.capacity.GPU),
+		zap.Int("max_jobs", s.capacity.MaxJobs),
+	)
+	return nil
+}
+
+// Stop 停止调度循环（不会停止已在运行的任务）
+func (s *Scheduler) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// Notify 唤醒调度循环（非阻塞）
+func (s *Scheduler) Notify() {
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// QueuePosition 获取任务的队列位置
+func (s *Scheduler) QueuePosition(ctx context.Context, job *domain.TrainingJob) int {
+	pos, err := s.jobRepo.GetQueuePosition(ctx, job)
+	if err != nil {
+		logger.Warn("Failed to get queue position", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return 0
+	}
+	return pos
+}
+
+// recoverPending 将重启前遗留的 pending 任务放回队列
+func (s *Scheduler) recoverPending(ctx context.Context) error {
+	jobs, err := s.jobRepo.ListByStatus(ctx, domain.JobStatusPending)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		ok, err := s.jobRepo.TransitionStatus(ctx, job.ID, domain.JobStatusPending, domain.JobStatusQueued, "Waiting for resources")
+		if err != nil {
+			logger.Warn("Failed to requeue pending job", zap.String("job_id", job.ID.String()), zap.Error(err))
+			continue
+		}
+		if ok {
+			logger.Info("Requeued pending job", zap.String("job_id", job.ID.String()))
+		}
+	}
+
+	return nil
+}
+
+// loop 调度循环
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		case <-s.wakeCh:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := s.schedule(ctx); err != nil {
+			logger.Error("Scheduling cycle failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// schedule 执行一次调度：按顺序派发能够放入剩余容量的排队任务
+func (s *Scheduler) schedule(ctx context.Context) error {
+	active, err := s.jobRepo.ListByStatus(ctx, domain.JobStatusRunning, domain.JobStatusStopping, domain.JobStatusPausing)
+	if err != nil {
+		return fmt.Errorf("failed to list active jobs: %w", err)
+	}
+
+	var used Resources
+	for _, job := range active {
+		used = used.add(resourcesOf(job))
+	}
+	running := len(active)
+
+	queued, err := s.jobRepo.ListQueued(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list queued jobs: %w", err)
+	}
+
+	now := time.Now()
+	for _, job := range queued {
+		// 处于重试退避期的任务暂不调度，也不阻塞后面的任务
+		if job.NextRetryAt != nil && job.NextRetryAt.After(now) {
+			continue
+		}
+
+		req := resourcesOf(job)
+
+		// 超出总容量的任务永远无法调度，直接失败
+		if !s.capacity.fits(req) {
+			message := fmt.Sprintf("Requested resources exceed cluster capacity (cpu=%d memory_gb=%d gpu=%d)", req.CPU, req.MemoryGB, req.GPU)
+			if ok, _ := s.jobRepo.TransitionStatus(ctx, job.ID, domain.JobStatusQueued, domain.JobStatusFailed, message); ok {
+				s.appendLog(ctx, job.ID, "ERROR", message)
+			}
+			continue
+		}
+
+		// 严格按队列顺序调度，队首任务放不下时等待资源释放
+		if s.capacity.MaxJobs > 0 && running >= s.capacity.MaxJobs {
+			break
+		}
+		if !s.capacity.fits(used.add(req)) {
+			break
+		}
+
+		// 预留具体的 GPU：宿主机没有足够的对应型号时任务失败，设备被占用时等待释放
+		if err := s.reserveGPUs(job); err != nil {
+			if errors.Is(err, executor.ErrNoMatchingGPUs) {
+				message := fmt.Sprintf("Requested GPUs are not available: %v", err)
+				if ok, _ := s.jobRepo.TransitionStatus(ctx, job.ID, domain.JobStatusQueued, domain.JobStatusFailed, message); ok {
+					s.appendLog(ctx, job.ID, "ERROR", message)
+				}
+				continue
+			}
+			break
+		}
+
+		// 在任务变为 running 之前标记为启动中，避免协调器在容器创建前把它当作丢失容器的任务
+		s.mu.Lock()
+		s.launching[job.ID] = struct{}{}
+		s.mu.Unlock()
+
+		ok, err := s.jobRepo.TransitionStatus(ctx, job.ID, domain.JobStatusQueued, domain.JobStatusRunning, "Starting training...")
+		if err != nil || !ok {
+			s.mu.Lock()
+			delete(s.launching, job.ID)
+			s.mu.Unlock()
+			s.releaseGPUs(job.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to dispatch job %s: %w", job.ID, err)
+		}
+		if !ok {
+			// 任务在调度前已被取消或删除
+			continue
+		}
+
+		used = used.add(req)
+		running++
+
+		logger.Info("Dispatching job",
+			zap.String("job_id", job.ID.String()),
+			zap.Int("priority", job.Priority),
+			zap.Int("cpu", req.CPU),
+			zap.Int("memory_gb", req.MemoryGB),
+			zap.Int("gpu", req.GPU),
+		)
+
+		go s.launch(job)
+	}
+
+	return nil
+}
+
+// reserveGPUs 为使用 GPU 的任务预留设备，执行器启动时复用预留的设备
+func (s *Scheduler) reserveGPUs(job *domain.TrainingJob) error {
+	if s.gpus == nil || job.GPUCount <= 0 {
+		return nil
+	}
+	_, err := s.gpus.Allocate(job.ID, job.Distributed.Nodes(), job.GPUCount, job.GPUType)
+	return err
+}
+
+// releaseGPUs 释放未能启动的任务预留的 GPU
+func (s *Scheduler) releaseGPUs(jobID uuid.UUID) {
+	if s.gpus != nil {
+		s.gpus.Release(jobID)
+	}
+}
+
+// launch 启动已派发的任务
+func (s *Scheduler) launch(job *domain.TrainingJob) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.launching, job.ID)
+		s.mu.Unlock()
+	}()
+
+	// 重试时从输出目录中最新的检查点恢复，没有时回退到创建任务时指定的检查点
+	if job.Attempt > 1 && job.RetryPolicy.ShouldResume() {
+		job.ResumeFrom = executor.FindLatestCheckpoint(job.OutputPath)
+	}
+	// 从暂停继续的任务使用暂停时保存的检查点
+	if job.ResumeFrom == "" && job.PausedCheckpoint != "" {
+		job.ResumeFrom = job.PausedCheckpoint
+	}
+	if job.ResumeFrom == "" && job.ResumeCheckpointID != nil {
+		path, err := s.materialize(job)
+		if err != nil {
+			s.failLaunch(job, fmt.Sprintf("Failed to prepare checkpoint %s: %v", job.ResumeCheckpointID, err))
+			return
+		}
+		job.ResumeFrom = path
+	}
+	if err := s.prepareDataset(job); err != nil {
+		s.failLaunch(job, fmt.Sprintf("Failed to prepare dataset %s: %v", job.DatasetPath, err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attempt := &domain.JobAttempt{
+		JobID:       job.ID,
+		Attempt:     job.Attempt,
+		ResumedFrom: job.ResumeFrom,
+	}
+	if err := s.attemptRepo.Create(ctx, attempt); err != nil {
+		logger.Warn("Failed to record job attempt", zap.String("job_id", job.ID.String()), zap.Int("attempt", job.Attempt), zap.Error(err))
+	}
+
+	if job.Attempt > 1 {
+		message := fmt.Sprintf("Starting attempt %d", job.Attempt)
+		if job.ResumeFrom != "" {
+			message = fmt.Sprintf("%s, resuming from checkpoint %s", message, job.ResumeFrom)
+		}
+		s.appendLog(ctx, job.ID, "INFO", message)
+	}
+	s.appendLog(ctx, job.ID, "INFO", fmt.Sprintf("Starting training with image: %s", job.Image))
+
+	// 启动执行器，拉取镜像和创建容器的时间受 StartupTimeout 限制；运行时间由 Watchdog 限制
+	execCtx, execCancel := context.WithTimeout(context.Background(), s.timeouts.StartupTimeout)
+	defer execCancel()
+
+	if err := s.executor.Start(execCtx, job); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// 容器没有启动，不会触发退出回调，需要在这里释放预留的 GPU
+		s.releaseGPUs(job.ID)
+		if errors.Is(err, context.DeadlineExceeded) {
+			s.appendLog(ctx, job.ID, "ERROR", fmt.Sprintf("Failed to start executor: %v", err))
+			s.terminated(ctx, job, domain.JobStatusRunning, domain.TerminationTimeout, fmt.Sprintf("Startup timed out after %s", s.timeouts.StartupTimeout))
+			return
+		}
+
+		message := fmt.Sprintf("Failed to start: %v", err)
+		s.jobRepo.UpdateStatus(ctx, job.ID, domain.JobStatusFailed, message)
+		s.FinishAttempt(ctx, job.ID, job.Attempt, domain.JobStatusFailed, nil, message)
+		s.appendLog(ctx, job.ID, "ERROR", fmt.Sprintf("Failed to start executor: %v", err))
+		s.Emit(domain.JobEventFailed, job, domain.JobStatusFailed, message)
+		s.Notify()
+		return
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.jobRepo.UpdateContainer(ctx, job.ID, job.ContainerID, job.ContainerName); err != nil {
+		logger.Warn("Failed to record job container", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+	if err := s.attemptRepo.UpdateContainer(ctx, job.ID, job.Attempt, job.ContainerID, job.ContainerName); err != nil {
+		logger.Warn("Failed to record attempt container", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	s.Emit(domain.JobEventStarted, job, domain.JobStatusRunning, fmt.Sprintf("Attempt %d started", job.Attempt))
+}
+
+// materialize 准备任务指定的恢复检查点，对象存储中的检查点可能需要较长时间下载
+func (s *Scheduler) materialize(job *domain.TrainingJob) (string, error) {
+	if s.checkpoints == nil {
+		return "", fmt.Errorf("checkpoint registry not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	return s.checkpoints.Materialize(ctx, *job.ResumeCheckpointID)
+}
+
+// prepareDataset 准备对象存储中的数据集，下载可能需要较长时间，不计入 StartupTimeout
+func (s *Scheduler) prepareDataset(job *domain.TrainingJob) error {
+	if _, _, ok := job.StoredDataset(); !ok || s.datasets == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	s.appendLog(ctx, job.ID, "INFO", fmt.Sprintf("Preparing dataset %s", job.DatasetPath))
+	return s.datasets.Prepare(ctx, job)
+}
+
+// failLaunch 启动前的准备失败，直接将任务标记为失败并释放资源
+func (s *Scheduler) failLaunch(job *domain.TrainingJob, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s.releaseGPUs(job.ID)
+	s.jobRepo.UpdateStatus(ctx, job.ID, domain.JobStatusFailed, message)
+	s.appendLog(ctx, job.ID, "ERROR", message)
+	s.Emit(domain.JobEventFailed, job, domain.JobStatusFailed, message)
+	s.Notify()
+}
+
+// IsLaunching 检查任务是否正在启动中
+func (s *Scheduler) IsLaunching(jobID uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.launching[jobID]
+	return ok
+}
+
+// onJobExit 容器退出回调：按重试策略重新排队或更新任务最终状态，释放资源并触发下一轮调度
+func (s *Scheduler) onJobExit(event *executor.ExitEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s.FinishAttempt(ctx, event.JobID, event.Attempt, event.Status, event.ExitCode, event.Message)
+
+	// 登记退出前最后写入的检查点
+	s.CollectCheckpoints(event.JobID)
+
+	if event.Status == domain.JobStatusFailed && event.Retryable && s.retry(ctx, event) {
+		s.Notify()
+		return
+	}
+	s.UploadArtifacts(event.JobID)
+
+	// 任务已被停止等操作更新为其他状态时不覆盖；暂停请求发出前任务已自行退出时以退出结果为准
+	ok, err := s.jobRepo.TransitionStatus(ctx, event.JobID, domain.JobStatusRunning, event.Status, event.Message)
+	if err == nil && !ok {
+		ok, err = s.jobRepo.TransitionStatus(ctx, event.JobID, domain.JobStatusPausing, event.Status, event.Message)
+	}
+	if err != nil {
+		logger.Error("Failed to update job status on exit", zap.String("job_id", event.JobID.String()), zap.Error(err))
+	}
+	if ok {
+		if job, err := s.jobRepo.GetByID(ctx, event.JobID); err == nil {
+			s.Emit(statusEventType(event.Status), job, event.Status, event.Message)
+		}
+	}
+
+	s.Notify()
+}
+
+// FinishPause 完成暂停：记录输出目录中最新的检查点供继续时使用，结束当前尝试并释放资源，返回是否更新成功
+func (s *Scheduler) FinishPause(ctx context.Context, job *domain.TrainingJob, message string) bool {
+	checkpoint := executor.FindLatestCheckpoint(job.OutputPath)
+	if checkpoint != "" {
+		message = fmt.Sprintf("%s, will resume from checkpoint %s", message, checkpoint)
+	} else {
+		message = fmt.Sprintf("%s, no checkpoint found in output directory", message)
+	}
+
+	ok, err := s.jobRepo.MarkPaused(ctx, job.ID, checkpoint, message)
+	if err != nil {
+		logger.Error("Failed to mark job paused", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return false
+	}
+	if !ok {
+		// 任务在暂停前已结束或已被停止
+		return false
+	}
+
+	s.FinishAttempt(ctx, job.ID, job.Attempt, domain.JobStatusPaused, nil, message)
+	s.CollectCheckpoints(job.ID)
+	s.appendLog(ctx, job.ID, "INFO", message)
+	s.Emit(domain.JobEventPaused, job, domain.JobStatusPaused, message)
+	s.Notify()
+	return true
+}
+
+// Terminate 强制结束运行中的任务并记录终止原因（domain.TerminationTimeout 等），不会重试
+func (s *Scheduler) Terminate(ctx context.Context, job *domain.TrainingJob, reason, message string) bool {
+	ok, err := s.jobRepo.TransitionStatus(ctx, job.ID, domain.JobStatusRunning, domain.JobStatusStopping, message)
+	if err != nil {
+		logger.Error("Failed to terminate job", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return false
+	}
+	if !ok {
+		// 任务已结束或正在被停止、暂停
+		return false
+	}
+	s.appendLog(ctx, job.ID, "ERROR", message)
+
+	if err := s.executor.Stop(ctx, job.ID); err != nil {
+		logger.Warn("Failed to stop terminated job", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	s.CollectCheckpoints(job.ID)
+	s.UploadArtifacts(job.ID)
+	return s.terminated(ctx, job, domain.JobStatusStopping, reason, message)
+}
+
+// terminated 将容器已停止的任务从 from 状态标记为失败，记录终止原因并发出事件
+func (s *Scheduler) terminated(ctx context.Context, job *domain.TrainingJob, from domain.JobStatus, reason, message string) bool {
+	ok, err := s.jobRepo.Terminate(ctx, job.ID, from, reason, message)
+	if err != nil {
+		logger.Error("Failed to record job termination", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return false
+	}
+	if !ok {
+		return false
+	}
+	s.FinishAttempt(ctx, job.ID, job.Attempt, domain.JobStatusFailed, nil, message)
+
+	logger.Warn("Job terminated",
+		zap.String("job_id", job.ID.String()),
+		zap.String("reason", reason),
+		zap.String("message", message),
+	)
+	s.Emit(reason, job, domain.JobStatusFailed, message)
+
+	s.Notify()
+	return true
+}
+
+// Emit 发出任务事件
+func (s *Scheduler) Emit(eventType string, job *domain.TrainingJob, status domain.JobStatus, message string) {
+	if s.onEvent == nil {
+		return
+	}
+	s.onEvent(&domain.JobEvent{
+		Type:      eventType,
+		JobID:     job.ID,
+		ProjectID: job.ProjectID,
+		Attempt:   job.Attempt,
+		Status:    status,
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
+
+// statusEventType 任务结束状态对应的事件类型
+func statusEventType(status domain.JobStatus) string {
+	switch status {
+	case domain.JobStatusCompleted:
+		return domain.JobEventCompleted
+	case domain.JobStatusCancelled:
+		return domain.JobEventStopped
+	default:
+		return domain.JobEventFailed
+	}
+}
+
+// CollectCheckpoints 在后台登记任务输出目录中剩余的检查点
+func (s *Scheduler) CollectCheckpoints(jobID uuid.UUID) {
+	if s.checkpoints == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+		s.checkpoints.Collect(ctx, jobID)
+	}()
+}
+
+// UploadArtifacts 在后台上传已结束任务的输出目录
+func (s *Scheduler) UploadArtifacts(jobID uuid.UUID) {
+	if s.artifacts == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		s.artifacts.Upload(ctx, jobID)
+	}()
+}
+
+// retry 按退避策略将失败的任务重新排队，返回是否已安排重试
+func (s *Scheduler) retry(ctx context.Context, event *executor.ExitEvent) bool {
+	job, err := s.jobRepo.GetByID(ctx, event.JobID)
+	if err != nil {
+		logger.Error("Failed to load job for retry", zap.String("job_id", event.JobID.String()), zap.Error(err))
+		return false
+	}
+
+	policy := executor.RetryPolicyFor(job.RetryPolicy)
+	retries := event.Attempt - job.Pauses // 本次失败后即将进行的重试序号，暂停产生的尝试不计入
+	if retries > policy.MaxRetries {
+		s.appendLog(ctx, job.ID, "WARN", fmt.Sprintf("Retry limit reached after %d attempts", retries))
+		return false
+	}
+
+	delay := policy.Delay(retries)
+	message := fmt.Sprintf("Attempt %d/%d failed (%s), retrying in %s", retries, policy.MaxRetries+1, event.RetryReason, delay)
+
+	ok, err := s.jobRepo.Requeue(ctx, job.ID, event.Attempt, time.Now().Add(delay), message)
+	if err != nil {
+		logger.Error("Failed to requeue job", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return false
+	}
+	if !ok {
+		// 任务已被停止或状态已变化
+		return false
+	}
+
+	s.appendLog(ctx, job.ID, "WARN", message)
+	s.Emit(domain.JobEventRetrying, job, domain.JobStatusQueued, message)
+	logger.Info("Job scheduled for retry",
+		zap.String("job_id", job.ID.String()),
+		zap.Int("attempt", event.Attempt+1),
+		zap.Duration("delay", delay),
+		zap.String("reason", event.RetryReason),
+	)
+
+	// 退避结束后唤醒调度循环
+	time.AfterFunc(delay, s.Notify)
+	return true
+}
+
+// FinishAttempt 结束任务的一次尝试
+func (s *Scheduler) FinishAttempt(ctx context.Context, jobID uuid.UUID, attempt int, status domain.JobStatus, exitCode *int, message string) {
+	if err := s.attemptRepo.Finish(ctx, jobID, attempt, status, exitCode, message); err != nil {
+		logger.Warn("Failed to finish job attempt", zap.String("job_id", jobID.String()), zap.Int("attempt", attempt), zap.Error(err))
+	}
+}
+
+// appendLog 记录系统日志
+func (s *Scheduler) appendLog(ctx context.Context, jobID uuid.UUID, level, message string) {
+	s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
+		Level:     level,
+		Source:    "system",
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
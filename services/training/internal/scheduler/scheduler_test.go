@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+)
+
+func TestSchedulerRetry(t *testing.T) {
+	policy := &domain.RetryPolicy{MaxAttempts: 3, InitialDelaySeconds: 10, BackoffMultiplier: 2}
+
+	tests := []struct {
+		name      string
+		policy    *domain.RetryPolicy
+		status    domain.JobStatus
+		attempt   int
+		pauses    int
+		wantRetry bool
+		wantDelay time.Duration
+	}{
+		{"first failure", policy, domain.JobStatusRunning, 1, 0, true, 10 * time.Second},
+		{"second failure backs off", policy, domain.JobStatusRunning, 2, 0, true, 20 * time.Second},
+		{"attempts used up", policy, domain.JobStatusRunning, 3, 0, false, 0},
+		{"paused attempts are not retries", policy, domain.JobStatusRunning, 3, 1, true, 20 * time.Second},
+		{"no retry policy", nil, domain.JobStatusRunning, 1, 0, false, 0},
+		{"stopped while exiting", policy, domain.JobStatusStopping, 1, 0, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &domain.TrainingJob{ID: uuid.New(), Status: tt.status, Attempt: tt.attempt, Pauses: tt.pauses, RetryPolicy: tt.policy}
+			jobs := newFakeJobRepo(job)
+			s := NewScheduler(jobs, &fakeLogRepo{}, &fakeAttemptRepo{}, &fakeExecutor{}, Capacity{}, time.Minute)
+
+			before := time.Now()
+			event := &executor.ExitEvent{JobID: job.ID, Attempt: tt.attempt, Status: domain.JobStatusFailed, Retryable: true, RetryReason: "exit code 1"}
+			if got := s.retry(context.Background(), event); got != tt.wantRetry {
+				t.Fatalf("retry() = %v, want %v", got, tt.wantRetry)
+			}
+
+			notBefore, requeued := jobs.requeued[job.ID]
+			if requeued != tt.wantRetry {
+				t.Fatalf("requeued = %v, want %v", requeued, tt.wantRetry)
+			}
+			if !requeued {
+				return
+			}
+			if delay := notBefore.Sub(before); delay < tt.wantDelay || delay > tt.wantDelay+time.Second {
+				t.Errorf("requeued after %s, want %s", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestSchedulerFinishPause(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         domain.JobStatus
+		checkpoint     string // 输出目录中相对路径，为空表示没有检查点
+		wantOK         bool
+		wantStatus     domain.JobStatus
+		wantCheckpoint bool
+		wantMessage    string
+	}{
+		{"saved a checkpoint", domain.JobStatusPausing, "checkpoints/model.pt", true, domain.JobStatusPaused, true, "will resume from checkpoint"},
+		{"no checkpoint", domain.JobStatusPausing, "", true, domain.JobStatusPaused, false, "no checkpoint found"},
+		{"exited before pausing", domain.JobStatusFailed, "checkpoints/model.pt", false, domain.JobStatusFailed, false, ""},
+		{"stopped while pausing", domain.JobStatusStopping, "", false, domain.JobStatusStopping, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := t.TempDir()
+			if tt.checkpoint != "" {
+				path := filepath.Join(output, tt.checkpoint)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("weights"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			job := &domain.TrainingJob{ID: uuid.New(), Status: tt.status, Attempt: 1, OutputPath: output}
+			jobs := newFakeJobRepo(job)
+			s := NewScheduler(jobs, &fakeLogRepo{}, &fakeAttemptRepo{}, &fakeExecutor{}, Capacity{}, time.Minute)
+			var events []string
+			s.SetEventHandler(func(event *domain.JobEvent) {
+				events = append(events, event.Type)
+			})
+
+			if got := s.FinishPause(context.Background(), job, "Paused by user"); got != tt.wantOK {
+				t.Fatalf("FinishPause() = %v, want %v", got, tt.wantOK)
+			}
+
+			stored, _ := jobs.GetByID(context.Background(), job.ID)
+			if stored.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+			if got := stored.PausedCheckpoint != ""; got != tt.wantCheckpoint {
+				t.Errorf("paused checkpoint = %q, want set = %v", stored.PausedCheckpoint, tt.wantCheckpoint)
+			}
+			if !strings.Contains(stored.StatusMessage, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", stored.StatusMessage, tt.wantMessage)
+			}
+			wantEvents := 0
+			if tt.wantOK {
+				wantEvents = 1
+			}
+			if len(events) != wantEvents || (wantEvents == 1 && events[0] != domain.JobEventPaused) {
+				t.Errorf("events = %v, want %d %s event", events, wantEvents, domain.JobEventPaused)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// Watchdog 训练任务看门狗
+//
+// 定期检查运行中的任务：各次尝试累计运行时间超过 TimeoutHours（未设置时为 OverallTimeout）的任务以
+// timeout 终止，重试和暂停后继续不会重新计时；在 StallTimeout 内既没有容器日志输出也没有上报指标的任务以 stalled 终止。
+// 最近活动时间取容器日志时间与 Touch 记录的时间中较晚者，服务重启后从看门狗启动时重新计时。
+type Watchdog struct {
+	jobRepo   repository.JobRepository
+	logRepo   repository.LogRepository
+	scheduler *Scheduler
+	timeouts  *executor.TimeoutConfig
+	interval  time.Duration
+	startedAt time.Time
+
+	mu       sync.Mutex
+	activity map[uuid.UUID]time.Time // 任务 ID -> 最近一次上报指标的时间
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewWatchdog 创建看门狗
+func NewWatchdog(jobRepo repository.JobRepository, logRepo repository.LogRepository, sched *Scheduler, timeouts *executor.TimeoutConfig, interval time.Duration) *Watchdog {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if timeouts == nil {
+		timeouts = executor.DefaultTimeoutConfig()
+	}
+
+	return &Watchdog{
+		jobRepo:   jobRepo,
+		logRepo:   logRepo,
+		scheduler: sched,
+		timeouts:  timeouts,
+		interval:  interval,
+		activity:  make(map[uuid.UUID]time.Time),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start 开始定期检查
+func (w *Watchdog) Start() {
+	w.startedAt = time.Now()
+
+	w.wg.Add(1)
+	go w.loop()
+
+	logger.Info("Watchdog started",
+		zap.Duration("interval", w.interval),
+		zap.Duration("overall_timeout", w.timeouts.OverallTimeout),
+		zap.Duration("stall_timeout", w.timeouts.StallTimeout),
+	)
+}
+
+// Stop 停止定期检查
+func (w *Watchdog) Stop() {
+	close(w.stopCh)
+	w.wg.Wait()
+}
+
+// Touch 记录任务的活动（如上报指标），用于停滞检测
+func (w *Watchdog) Touch(jobID uuid.UUID) {
+	w.mu.Lock()
+	w.activity[jobID] = time.Now()
+	w.mu.Unlock()
+}
+
+// loop 定期检查循环
+func (w *Watchdog) loop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		if err := w.Check(ctx); err != nil {
+			logger.Error("Watchdog check failed", zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// Check 检查全部运行中的任务，终止超时或停滞的任务
+func (w *Watchdog) Check(ctx context.Context) error {
+	running, err := w.jobRepo.ListByStatus(ctx, domain.JobStatusRunning)
+	if err != nil {
+		return fmt.Errorf("failed to list running jobs: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(running))
+	for _, job := range running {
+		seen[job.ID] = true
+		w.checkJob(ctx, job)
+	}
+
+	// 清理已结束任务的活动记录
+	w.mu.Lock()
+	for jobID := range w.activity {
+		if !seen[jobID] {
+			delete(w.activity, jobID)
+		}
+	}
+	w.mu.Unlock()
+
+	return nil
+}
+
+// checkJob 检查单个运行中的任务
+func (w *Watchdog) checkJob(ctx context.Context, job *domain.TrainingJob) {
+	// 启动阶段受 StartupTimeout 限制
+	if w.scheduler.IsLaunching(job.ID) || job.StartedAt == nil {
+		return
+	}
+
+	now := time.Now()
+	if budget := w.budget(job); budget > 0 && job.Runtime(now) > budget {
+		w.scheduler.Terminate(ctx, job, domain.TerminationTimeout,
+			fmt.Sprintf("Job exceeded its time limit of %s", budget))
+		return
+	}
+
+	if w.timeouts.StallTimeout <= 0 {
+		return
+	}
+	last := w.lastActivity(ctx, job)
+	if idle := now.Sub(last); idle > w.timeouts.StallTimeout {
+		w.scheduler.Terminate(ctx, job, domain.TerminationStalled,
+			fmt.Sprintf("Job stalled: no log output or metrics for %s", idle.Truncate(time.Second)))
+	}
+}
+
+// budget 任务所有尝试累计允许的运行时间
+func (w *Watchdog) budget(job *domain.TrainingJob) time.Duration {
+	if job.TimeoutHours > 0 {
+		return time.Duration(job.TimeoutHours) * time.Hour
+	}
+	return w.timeouts.OverallTimeout
+}
+
+// lastActivity 任务最近一次活动的时间
+func (w *Watchdog) lastActivity(ctx context.Context, job *domain.TrainingJob) time.Time {
+	last := *job.StartedAt
+	if w.startedAt.After(last) {
+		last = w.startedAt
+	}
+
+	w.mu.Lock()
+	touched := w.activity[job.ID]
+	w.mu.Unlock()
+	if touched.After(last) {
+		last = touched
+	}
+
+	logged, err := w.logRepo.GetLastContainerLogTime(ctx, job.ID, nil)
+	if err != nil {
+		logger.Warn("Failed to get last log time", zap.String("job_id", job.ID.String()), zap.Error(err))
+		// 无法确认时不判定为停滞
+		return time.Now()
+	}
+	if logged.After(last) {
+		last = logged
+	}
+	return last
+}
@@ -0,0 +1,310 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// fakeJobRepo 内存中的任务表，记录终止原因和重新排队的时间，条件更新只在状态匹配时生效
+type fakeJobRepo struct {
+	repository.JobRepository
+
+	mu         sync.Mutex
+	jobs       map[uuid.UUID]*domain.TrainingJob
+	terminated map[uuid.UUID]string
+	requeued   map[uuid.UUID]time.Time
+}
+
+func newFakeJobRepo(jobs ...*domain.TrainingJob) *fakeJobRepo {
+	r := &fakeJobRepo{
+		jobs:       make(map[uuid.UUID]*domain.TrainingJob),
+		terminated: make(map[uuid.UUID]string),
+		requeued:   make(map[uuid.UUID]time.Time),
+	}
+	for _, job := range jobs {
+		r.jobs[job.ID] = job
+	}
+	return r
+}
+
+func (r *fakeJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.TrainingJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, repository.ErrJobNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (r *fakeJobRepo) ListByStatus(ctx context.Context, statuses ...domain.JobStatus) ([]*domain.TrainingJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []*domain.TrainingJob
+	for _, job := range r.jobs {
+		for _, status := range statuses {
+			if job.Status == status {
+				copied := *job
+				jobs = append(jobs, &copied)
+			}
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeJobRepo) TransitionStatus(ctx context.Context, id uuid.UUID, from, to domain.JobStatus, message string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != from {
+		return false, nil
+	}
+	job.Status = to
+	job.StatusMessage = message
+	return true, nil
+}
+
+func (r *fakeJobRepo) Terminate(ctx context.Context, id uuid.UUID, from domain.JobStatus, reason, message string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != from {
+		return false, nil
+	}
+	job.Status = domain.JobStatusFailed
+	job.StatusMessage = message
+	r.terminated[id] = reason
+	return true, nil
+}
+
+func (r *fakeJobRepo) Requeue(ctx context.Context, id uuid.UUID, attempt int, notBefore time.Time, message string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != domain.JobStatusRunning || job.Attempt != attempt {
+		return false, nil
+	}
+	job.Status = domain.JobStatusQueued
+	r.requeued[id] = notBefore
+	return true, nil
+}
+
+func (r *fakeJobRepo) MarkPaused(ctx context.Context, id uuid.UUID, checkpoint, message string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != domain.JobStatusPausing {
+		return false, nil
+	}
+	job.Status = domain.JobStatusPaused
+	job.StatusMessage = message
+	job.PausedCheckpoint = checkpoint
+	return true, nil
+}
+
+// status 任务当前的状态
+func (r *fakeJobRepo) status(id uuid.UUID) domain.JobStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[id].Status
+}
+
+// fakeLogRepo 丢弃系统日志，返回固定的最近容器日志时间
+type fakeLogRepo struct {
+	repository.LogRepository
+
+	lastLog time.Time
+	err     error
+}
+
+func (r *fakeLogRepo) AppendLog(ctx context.Context, jobID uuid.UUID, entry *domain.LogEntry) error {
+	return nil
+}
+
+func (r *fakeLogRepo) GetLastContainerLogTime(ctx context.Context, jobID uuid.UUID, rank *int) (time.Time, error) {
+	return r.lastLog, r.err
+}
+
+// fakeAttemptRepo 忽略尝试记录
+type fakeAttemptRepo struct {
+	repository.AttemptRepository
+}
+
+func (r *fakeAttemptRepo) Finish(ctx context.Context, jobID uuid.UUID, attempt int, status domain.JobStatus, exitCode *int, message string) error {
+	return nil
+}
+
+// fakeExecutor 记录被停止的任务
+type fakeExecutor struct {
+	executor.Executor
+
+	mu      sync.Mutex
+	stopped []uuid.UUID
+}
+
+func (e *fakeExecutor) SetExitHandler(handler executor.ExitHandler) {}
+
+func (e *fakeExecutor) Stop(ctx context.Context, jobID uuid.UUID) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = append(e.stopped, jobID)
+	return nil
+}
+
+func TestWatchdogCheck(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	timeouts := &executor.TimeoutConfig{OverallTimeout: 4 * time.Hour, StallTimeout: 10 * time.Minute}
+
+	tests := []struct {
+		name        string
+		job         domain.TrainingJob
+		timeouts    *executor.TimeoutConfig
+		lastLog     time.Duration // 距最近一次容器日志的时间，0 表示没有日志
+		logErr      error
+		touched     time.Duration // 距最近一次上报指标的时间，0 表示没有上报
+		watchdogAge time.Duration // 看门狗已运行的时间，0 表示与任务同时启动
+		launching   bool
+		wantReason  string
+	}{
+		{
+			name:     "within time limit",
+			job:      domain.TrainingJob{TimeoutHours: 2, StartedAt: ago(time.Hour)},
+			timeouts: timeouts,
+			lastLog:  time.Minute,
+		},
+		{
+			name:       "exceeds timeout_hours",
+			job:        domain.TrainingJob{TimeoutHours: 2, StartedAt: ago(3 * time.Hour)},
+			timeouts:   timeouts,
+			lastLog:    time.Minute,
+			wantReason: domain.TerminationTimeout,
+		},
+		{
+			name:       "earlier attempts count toward the limit",
+			job:        domain.TrainingJob{TimeoutHours: 2, StartedAt: ago(30 * time.Minute), RunSeconds: 6000, Attempt: 2},
+			timeouts:   timeouts,
+			lastLog:    time.Minute,
+			wantReason: domain.TerminationTimeout,
+		},
+		{
+			name:       "overall timeout without timeout_hours",
+			job:        domain.TrainingJob{StartedAt: ago(5 * time.Hour)},
+			timeouts:   timeouts,
+			lastLog:    time.Minute,
+			wantReason: domain.TerminationTimeout,
+		},
+		{
+			name:     "no limit",
+			job:      domain.TrainingJob{StartedAt: ago(100 * time.Hour)},
+			timeouts: &executor.TimeoutConfig{StallTimeout: 10 * time.Minute},
+			lastLog:  time.Minute,
+		},
+		{
+			name:       "stalled without logs or metrics",
+			job:        domain.TrainingJob{StartedAt: ago(time.Hour)},
+			timeouts:   timeouts,
+			lastLog:    20 * time.Minute,
+			wantReason: domain.TerminationStalled,
+		},
+		{
+			name:       "stalled since start",
+			job:        domain.TrainingJob{StartedAt: ago(time.Hour)},
+			timeouts:   timeouts,
+			wantReason: domain.TerminationStalled,
+		},
+		{
+			name:     "recent metrics keep a quiet job alive",
+			job:      domain.TrainingJob{StartedAt: ago(time.Hour)},
+			timeouts: timeouts,
+			lastLog:  20 * time.Minute,
+			touched:  time.Minute,
+		},
+		{
+			name:        "stall timer restarts with the watchdog",
+			job:         domain.TrainingJob{StartedAt: ago(time.Hour)},
+			timeouts:    timeouts,
+			lastLog:     30 * time.Minute,
+			watchdogAge: 5 * time.Minute,
+		},
+		{
+			name:     "log lookup failure is not a stall",
+			job:      domain.TrainingJob{StartedAt: ago(time.Hour)},
+			timeouts: timeouts,
+			logErr:   errors.New("redis unavailable"),
+		},
+		{
+			name:     "stall detection disabled",
+			job:      domain.TrainingJob{StartedAt: ago(time.Hour)},
+			timeouts: &executor.TimeoutConfig{OverallTimeout: 4 * time.Hour},
+		},
+		{
+			name:      "launching job is left to the startup timeout",
+			job:       domain.TrainingJob{TimeoutHours: 1, StartedAt: ago(3 * time.Hour)},
+			timeouts:  timeouts,
+			launching: true,
+		},
+		{
+			name:     "not started",
+			job:      domain.TrainingJob{TimeoutHours: 1},
+			timeouts: timeouts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := tt.job
+			job.ID = uuid.New()
+			job.Status = domain.JobStatusRunning
+			if job.Attempt == 0 {
+				job.Attempt = 1
+			}
+
+			jobs := newFakeJobRepo(&job)
+			logs := &fakeLogRepo{err: tt.logErr}
+			if tt.lastLog > 0 {
+				logs.lastLog = now.Add(-tt.lastLog)
+			}
+			exec := &fakeExecutor{}
+			sched := NewScheduler(jobs, logs, &fakeAttemptRepo{}, exec, Capacity{}, time.Minute)
+			if tt.launching {
+				sched.launching[job.ID] = struct{}{}
+			}
+
+			w := NewWatchdog(jobs, logs, sched, tt.timeouts, time.Minute)
+			if job.StartedAt != nil {
+				w.startedAt = *job.StartedAt
+			}
+			if tt.watchdogAge > 0 {
+				w.startedAt = now.Add(-tt.watchdogAge)
+			}
+			if tt.touched > 0 {
+				w.activity[job.ID] = now.Add(-tt.touched)
+			}
+
+			if err := w.Check(context.Background()); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if got := jobs.terminated[job.ID]; got != tt.wantReason {
+				t.Errorf("termination reason = %q, want %q", got, tt.wantReason)
+			}
+			if stopped := len(exec.stopped) > 0; stopped != (tt.wantReason != "") {
+				t.Errorf("container stopped = %v, want %v", stopped, tt.wantReason != "")
+			}
+		})
+	}
+}
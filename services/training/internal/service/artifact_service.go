@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/artifact"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// ArtifactService 任务产物服务接口
+type ArtifactService interface {
+	List(ctx context.Context, jobID uuid.UUID) ([]*domain.Artifact, error)
+}
+
+// artifactService 任务产物服务实现
+type artifactService struct {
+	jobRepo  repository.JobRepository
+	uploader *artifact.Uploader
+}
+
+// NewArtifactService 创建任务产物服务实例
+func NewArtifactService(jobRepo repository.JobRepository, uploader *artifact.Uploader) ArtifactService {
+	return &artifactService{
+		jobRepo:  jobRepo,
+		uploader: uploader,
+	}
+}
+
+// List 列出任务已上传的产物
+func (s *artifactService) List(ctx context.Context, jobID uuid.UUID) ([]*domain.Artifact, error) {
+	if _, err := s.jobRepo.GetByID(ctx, jobID); err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("job not found: %s", jobID))
+		}
+		return nil, err
+	}
+
+	return s.uploader.List(ctx, jobID)
+}
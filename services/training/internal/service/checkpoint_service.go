@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/checkpoint"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// CheckpointService 检查点服务接口
+type CheckpointService interface {
+	List(ctx context.Context, jobID uuid.UUID) ([]*models.Checkpoint, error)
+	Get(ctx context.Context, jobID, checkpointID uuid.UUID) (*models.Checkpoint, error)
+	Download(ctx context.Context, ckpt *models.Checkpoint) (*domain.CheckpointDownload, error)
+	WriteArchive(ctx context.Context, ckpt *models.Checkpoint, w io.Writer) error
+	Delete(ctx context.Context, jobID, checkpointID uuid.UUID) error
+}
+
+// checkpointService 检查点服务实现
+type checkpointService struct {
+	jobRepo        repository.JobRepository
+	checkpointRepo repository.CheckpointRepository
+	registry       *checkpoint.Registry
+}
+
+// NewCheckpointService 创建检查点服务实例
+func NewCheckpointService(jobRepo repository.JobRepository, checkpointRepo repository.CheckpointRepository, registry *checkpoint.Registry) CheckpointService {
+	return &checkpointService{
+		jobRepo:        jobRepo,
+		checkpointRepo: checkpointRepo,
+		registry:       registry,
+	}
+}
+
+// List 列出任务的检查点
+func (s *checkpointService) List(ctx context.Context, jobID uuid.UUID) ([]*models.Checkpoint, error) {
+	if _, err := s.jobRepo.GetByID(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	return s.checkpointRepo.ListByJob(ctx, jobID)
+}
+
+// Get 获取任务的检查点
+func (s *checkpointService) Get(ctx context.Context, jobID, checkpointID uuid.UUID) (*models.Checkpoint, error) {
+	return getJobCheckpoint(ctx, s.checkpointRepo, jobID, checkpointID)
+}
+
+// Download 获取检查点下载信息
+func (s *checkpointService) Download(ctx context.Context, ckpt *models.Checkpoint) (*domain.CheckpointDownload, error) {
+	return s.registry.DownloadInfo(ctx, ckpt)
+}
+
+// WriteArchive 打包目录检查点
+func (s *checkpointService) WriteArchive(ctx context.Context, ckpt *models.Checkpoint, w io.Writer) error {
+	return s.registry.WriteArchive(ctx, ckpt, w)
+}
+
+// Delete 删除检查点
+func (s *checkpointService) Delete(ctx context.Context, jobID, checkpointID uuid.UUID) error {
+	ckpt, err := getJobCheckpoint(ctx, s.checkpointRepo, jobID, checkpointID)
+	if err != nil {
+		return err
+	}
+
+	return s.registry.Delete(ctx, ckpt)
+}
+
+// getJobCheckpoint 获取检查点并校验其属于指定任务
+func getJobCheckpoint(ctx context.Context, repo repository.CheckpointRepository, jobID, checkpointID uuid.UUID) (*models.Checkpoint, error) {
+	ckpt, err := repo.GetByID(ctx, checkpointID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckpointNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("checkpoint not found: %s", checkpointID))
+		}
+		return nil, err
+	}
+	if ckpt.TrainingJobID != jobID {
+		return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("checkpoint %s does not belong to job %s", checkpointID, jobID))
+	}
+	return ckpt, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/events"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/scheduler"
+)
+
+// NewJobEventPublisher 返回将任务事件以 training.job.<type> 类型发布到事件总线的回调
+//
+// 发布在后台进行，不阻塞调度和状态更新；失败时只记录日志。
+func NewJobEventPublisher(publisher events.Publisher) scheduler.EventHandler {
+	return func(event *domain.JobEvent) {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := publisher.Publish(ctx, &events.Event{
+				Type:       "training.job." + event.Type,
+				Source:     events.SourceTraining,
+				ProjectID:  event.ProjectID,
+				ResourceID: event.JobID,
+				Status:     string(event.Status),
+				Message:    event.Message,
+				Data: map[string]interface{}{
+					"attempt": event.Attempt,
+				},
+				Timestamp: event.Timestamp,
+			})
+			if err != nil {
+				logger.Warn("Failed to publish job event",
+					zap.String("job_id", event.JobID.String()),
+					zap.String("type", event.Type),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+)
+
+// RunMetricsForwarder 将训练指标转发到实验服务的运行记录
+type RunMetricsForwarder interface {
+	ForwardMetrics(ctx context.Context, runID uuid.UUID, metrics *executor.TrainingMetrics) error
+}
+
+// runMetric 实验服务的指标记录请求
+type runMetric struct {
+	RunID     uuid.UUID `json:"run_id"`
+	Key       string    `json:"key"`
+	Value     float64   `json:"value"`
+	Step      *int64    `json:"step,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// experimentMetricsClient 通过实验服务 REST API 批量记录指标
+type experimentMetricsClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewExperimentMetricsClient 创建实验服务指标客户端
+func NewExperimentMetricsClient(baseURL string) RunMetricsForwarder {
+	return &experimentMetricsClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ForwardMetrics 批量记录一组指标，step 未知时使用 epoch
+func (c *experimentMetricsClient) ForwardMetrics(ctx context.Context, runID uuid.UUID, metrics *executor.TrainingMetrics) error {
+	values := metrics.Values()
+	if len(values) == 0 {
+		return nil
+	}
+
+	var step *int64
+	if metrics.Step > 0 {
+		s := int64(metrics.Step)
+		step = &s
+	} else if metrics.Epoch > 0 {
+		s := int64(metrics.Epoch)
+		step = &s
+	}
+
+	batch := make([]runMetric, 0, len(values))
+	for key, value := range values {
+		batch = append(batch, runMetric{
+			RunID:     runID,
+			Key:       key,
+			Value:     value,
+			Step:      step,
+			Timestamp: metrics.Timestamp,
+		})
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"metrics": batch})
+	if err != nil {
+		return fmt.Errorf("failed to encode run metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/metrics/batch", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call experiment service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("experiment service returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -3,14 +3,26 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/quota"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/secrets"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/admission"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/config"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/logarchive"
 	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/scheduler"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // JobService 训练任务服务接口
@@ -27,25 +39,48 @@ type JobService interface {
 	GetExperimentMetrics(ctx context.Context, experimentID uuid.UUID) (map[string]interface{}, error)
 	
 	// 日志
-	GetLogs(ctx context.Context, jobID uuid.UUID, start string, count int64) ([]domain.LogEntry, error)
+	GetLogs(ctx context.Context, jobID uuid.UUID, start string, count int64, filter domain.LogFilter) ([]domain.LogEntry, error)
 	StreamLogs(ctx context.Context, jobID uuid.UUID, logChan chan<- domain.LogEntry) error
+
+	// 重试
+	ListAttempts(ctx context.Context, jobID uuid.UUID) ([]*domain.JobAttempt, error)
+
+	// 从检查点恢复
+	ResumeJob(ctx context.Context, userID, jobID uuid.UUID, req *domain.ResumeJobRequest) (*domain.TrainingJob, error)
+
+	// 暂停
+	PauseJob(ctx context.Context, jobID uuid.UUID, req *domain.PauseJobRequest) (*domain.TrainingJob, error)
 }
 
 // jobService 训练任务服务实现
 type jobService struct {
-	cfg       *config.Config
-	jobRepo   repository.JobRepository
-	logRepo   repository.LogRepository
-	executor  *executor.DockerExecutor
+	cfg            *config.Config
+	jobRepo        repository.JobRepository
+	logRepo        repository.LogRepository
+	attemptRepo    repository.AttemptRepository
+	checkpointRepo repository.CheckpointRepository
+	executor       executor.Executor
+	scheduler      *scheduler.Scheduler
+	quota          quota.Checker
+	logArchive     *logarchive.Archiver
+	secrets        secrets.Manager
+	admission      admission.Controller
 }
 
-// NewJobService 创建任务服务实例
-func NewJobService(cfg *config.Config, jobRepo repository.JobRepository, logRepo repository.LogRepository, exec *executor.DockerExecutor) JobService {
+// NewJobService 创建任务服务实例，secretStore 为 nil 时不支持 secret_refs，admissionController 为 nil 时不做准入检查
+func NewJobService(cfg *config.Config, jobRepo repository.JobRepository, logRepo repository.LogRepository, attemptRepo repository.AttemptRepository, checkpointRepo repository.CheckpointRepository, exec executor.Executor, sched *scheduler.Scheduler, quotaChecker quota.Checker, logArchive *logarchive.Archiver, secretStore secrets.Manager, admissionController admission.Controller) JobService {
 	return &jobService{
-		cfg:      cfg,
-		jobRepo:  jobRepo,
-		logRepo:  logRepo,
-		executor: exec,
+		cfg:            cfg,
+		jobRepo:        jobRepo,
+		logRepo:        logRepo,
+		attemptRepo:    attemptRepo,
+		checkpointRepo: checkpointRepo,
+		executor:       exec,
+		scheduler:      sched,
+		quota:          quotaChecker,
+		logArchive:     logArchive,
+		secrets:        secretStore,
+		admission:      admissionController,
 	}
 }
 
@@ -67,6 +102,7 @@ func (s *jobService) CreateJob(ctx context.Context, userID uuid.UUID, req *domai
 		ModelName:       req.ModelName,
 		ModelVersion:    req.ModelVersion,
 		DatasetPath:     req.DatasetPath,
+		DatasetVersion:  req.DatasetVersion,
 		OutputPath:      req.OutputPath,
 		Framework:       req.Framework,
 		Image:           req.Image,
@@ -78,7 +114,10 @@ func (s *jobService) CreateJob(ctx context.Context, userID uuid.UUID, req *domai
 		CPUCount:        req.CPUCount,
 		MemoryGB:        req.MemoryGB,
 		TimeoutHours:    req.TimeoutHours,
-		Status:          domain.JobStatusPending,
+		Priority:        req.Priority,
+		RetryPolicy:     req.RetryPolicy,
+		Attempt:         1,
+		Status:          domain.JobStatusQueued,
 		Progress:        0,
 	}
 
@@ -91,6 +130,30 @@ func (s *jobService) CreateJob(ctx context.Context, userID uuid.UUID, req *domai
 		job.ExperimentID = &expID
 	}
 
+	// 解析运行记录 ID（如果提供）
+	if req.RunID != "" {
+		runID, err := uuid.Parse(req.RunID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid run_id: %w", err)
+		}
+		job.RunID = &runID
+	}
+
+	// 校验恢复检查点（如果提供）
+	if req.ResumeCheckpointID != "" {
+		checkpointID, err := uuid.Parse(req.ResumeCheckpointID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resume_checkpoint_id: %w", err)
+		}
+		if _, err := s.checkpointRepo.GetByID(ctx, checkpointID); err != nil {
+			if errors.Is(err, repository.ErrCheckpointNotFound) {
+				return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("checkpoint not found: %s", checkpointID))
+			}
+			return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+		}
+		job.ResumeCheckpointID = &checkpointID
+	}
+
 	// 设置默认值
 	if job.CPUCount == 0 {
 		job.CPUCount = 4
@@ -105,6 +168,33 @@ func (s *jobService) CreateJob(ctx context.Context, userID uuid.UUID, req *domai
 		job.TimeoutHours = 24
 	}
 
+	// 分布式配置：资源按单节点申请
+	if req.Distributed != nil {
+		distributed := *req.Distributed
+		distributed.ApplyDefaults(job.GPUCount)
+		if distributed.IsDistributed() {
+			if job.Framework != domain.FrameworkPyTorch {
+				return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "distributed training is only supported for the pytorch framework")
+			}
+			if job.GPUCount > 0 && distributed.ProcsPerNode > job.GPUCount {
+				return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("procs_per_node (%d) exceeds gpu_count per node (%d)", distributed.ProcsPerNode, job.GPUCount))
+			}
+			job.Distributed = &distributed
+		}
+	}
+	nodes := job.Distributed.Nodes()
+
+	// 校验引用的密钥，密钥值只在启动容器时读取
+	if len(req.SecretRefs) > 0 {
+		if s.secrets == nil {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "secret_refs are not supported: the secrets store is not configured")
+		}
+		if err := s.secrets.Check(ctx, projectID, req.SecretRefs); err != nil {
+			return nil, err
+		}
+		job.SecretRefs = req.SecretRefs
+	}
+
 	// 设置默认镜像
 	if job.Image == "" {
 		switch job.Framework {
@@ -117,6 +207,15 @@ func (s *jobService) CreateJob(ctx context.Context, userID uuid.UUID, req *domai
 		}
 	}
 
+	// 准入检查：镜像策略、挂载路径和 GPU，一次返回全部违规项
+	if s.admission != nil {
+		if err := s.admission.Admit(ctx, userID, job, req.DatasetID); err != nil {
+			return nil, err
+		}
+	} else if req.DatasetID != "" {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "dataset_id is not supported: admission is not configured")
+	}
+
 	// 设置环境变量
 	if job.Environment == nil {
 		job.Environment = make(map[string]string)
@@ -133,9 +232,19 @@ func (s *jobService) CreateJob(ctx context.Context, userID uuid.UUID, req *domai
 		job.Environment["HYPERPARAMETERS"] = string(hpJSON)
 	}
 
-	// 保存到数据库
-	if err := s.jobRepo.Create(ctx, job); err != nil {
-		return nil, fmt.Errorf("failed to create job: %w", err)
+	// 检查项目/组织配额并保存到数据库，配额检查和写入在同一事务中，并发提交不会一起超出配额
+	if err := s.quota.Reserve(ctx, job.ProjectID, quota.Request{
+		GPUs:     job.GPUCount * nodes,
+		GPUHours: float64(job.GPUCount * nodes * job.TimeoutHours),
+		Jobs:     1,
+		MemoryGB: job.MemoryGB * nodes,
+	}, func(tx *gorm.DB) error {
+		if err := s.jobRepo.WithTx(tx).Create(ctx, job); err != nil {
+			return fmt.Errorf("failed to create job: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
 	// 记录创建日志
@@ -146,20 +255,48 @@ func (s *jobService) CreateJob(ctx context.Context, userID uuid.UUID, req *domai
 		Timestamp: time.Now(),
 	})
 
-	// 异步启动任务
-	go s.startJobAsync(job.ID)
+	// 加入调度队列
+	job.QueuePosition = s.scheduler.QueuePosition(ctx, job)
+	s.logRepo.AppendLog(ctx, job.ID, &domain.LogEntry{
+		Level:     "INFO",
+		Source:    "system",
+		Message:   fmt.Sprintf("Job queued with priority %d at position %d", job.Priority, job.QueuePosition),
+		Timestamp: time.Now(),
+	})
+	s.scheduler.Emit(domain.JobEventCreated, job, job.Status, fmt.Sprintf("Training job created: %s", job.Name))
+	s.scheduler.Notify()
 
 	return job, nil
 }
 
 // GetJob 获取任务详情
 func (s *jobService) GetJob(ctx context.Context, jobID uuid.UUID) (*domain.TrainingJob, error) {
-	return s.jobRepo.GetByID(ctx, jobID)
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status == domain.JobStatusQueued {
+		job.QueuePosition = s.scheduler.QueuePosition(ctx, job)
+	}
+
+	return job, nil
 }
 
 // ListJobs 列出训练任务
 func (s *jobService) ListJobs(ctx context.Context, req *domain.ListJobsRequest) ([]*domain.TrainingJob, int64, error) {
-	return s.jobRepo.List(ctx, req)
+	jobs, total, err := s.jobRepo.List(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, job := range jobs {
+		if job.Status == domain.JobStatusQueued {
+			job.QueuePosition = s.scheduler.QueuePosition(ctx, job)
+		}
+	}
+
+	return jobs, total, nil
 }
 
 // UpdateJob 更新任务
@@ -216,6 +353,46 @@ func (s *jobService) StopJob(ctx context.Context, jobID uuid.UUID) error {
 		return fmt.Errorf("job cannot be stopped in status: %s", job.Status)
 	}
 
+	// 已暂停的任务没有运行中的容器，直接取消
+	if job.Status == domain.JobStatusPaused {
+		ok, err := s.jobRepo.TransitionStatus(ctx, jobID, domain.JobStatusPaused, domain.JobStatusCancelled, "Stopped by user while paused")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("job cannot be stopped in status: %s", job.Status)
+		}
+		s.scheduler.Emit(domain.JobEventStopped, job, domain.JobStatusCancelled, "Stopped by user while paused")
+		return nil
+	}
+
+	// 尚未派发的任务直接从队列中移除
+	if job.Status == domain.JobStatusQueued || job.Status == domain.JobStatusPending {
+		ok, err := s.jobRepo.TransitionStatus(ctx, jobID, job.Status, domain.JobStatusCancelled, "Cancelled before start")
+		if err != nil {
+			return err
+		}
+		if ok {
+			s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
+				Level:     "INFO",
+				Source:    "system",
+				Message:   "Job removed from queue by user",
+				Timestamp: time.Now(),
+			})
+			s.scheduler.Emit(domain.JobEventStopped, job, domain.JobStatusCancelled, "Cancelled before start")
+			return nil
+		}
+
+		// 任务恰好被调度器派发，按运行中任务处理
+		job, err = s.jobRepo.GetByID(ctx, jobID)
+		if err != nil {
+			return err
+		}
+		if job.Status != domain.JobStatusRunning {
+			return fmt.Errorf("job cannot be stopped in status: %s", job.Status)
+		}
+	}
+
 	// 更新状态为 stopping
 	if err := s.jobRepo.UpdateStatus(ctx, jobID, domain.JobStatusStopping, "User requested stop"); err != nil {
 		return err
@@ -233,134 +410,281 @@ func (s *jobService) StopJob(ctx context.Context, jobID uuid.UUID) error {
 	}
 
 	// 更新状态为 cancelled
-	return s.jobRepo.UpdateStatus(ctx, jobID, domain.JobStatusCancelled, "Stopped by user")
+	if err := s.jobRepo.UpdateStatus(ctx, jobID, domain.JobStatusCancelled, "Stopped by user"); err != nil {
+		return err
+	}
+	s.scheduler.FinishAttempt(ctx, jobID, job.Attempt, domain.JobStatusCancelled, nil, "Stopped by user")
+	s.scheduler.Emit(domain.JobEventStopped, job, domain.JobStatusCancelled, "Stopped by user")
+
+	// 资源已释放，触发下一轮调度
+	s.scheduler.Notify()
+	return nil
 }
 
-// startJobAsync 异步启动任务
-func (s *jobService) startJobAsync(jobID uuid.UUID) {
-	// 给数据库事务一些时间完成
-	time.Sleep(100 * time.Millisecond)
+// ListJobsByExperiment 列出实验下的任务
+func (s *jobService) ListJobsByExperiment(ctx context.Context, experimentID uuid.UUID, page, pageSize int) ([]*domain.TrainingJob, int64, error) {
+	return s.jobRepo.ListByExperiment(ctx, experimentID, page, pageSize)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// GetExperimentMetrics 获取实验指标
+func (s *jobService) GetExperimentMetrics(ctx context.Context, experimentID uuid.UUID) (map[string]interface{}, error) {
+	return s.jobRepo.GetExperimentMetrics(ctx, experimentID)
+}
 
-	// 获取任务详情
-	job, err := s.jobRepo.GetByID(ctx, jobID)
+// GetLogs 获取日志，可按尝试次数和分布式节点过滤
+func (s *jobService) GetLogs(ctx context.Context, jobID uuid.UUID, start string, count int64, filter domain.LogFilter) ([]domain.LogEntry, error) {
+	// 先验证任务存在
+	if _, err := s.jobRepo.GetByID(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	// 跨归档段和日志流分页读取
+	if s.logArchive != nil {
+		return s.logArchive.ReadLogs(ctx, jobID, filter, start, count)
+	}
+
+	if !filter.IsEmpty() {
+		return s.logRepo.ReadFilteredLogs(ctx, jobID, filter, start, count)
+	}
+	return s.logRepo.ReadLogs(ctx, jobID, start, count)
+}
+
+// ListAttempts 列出任务的所有尝试
+func (s *jobService) ListAttempts(ctx context.Context, jobID uuid.UUID) ([]*domain.JobAttempt, error) {
+	if _, err := s.jobRepo.GetByID(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	return s.attemptRepo.ListByJob(ctx, jobID)
+}
+
+// ResumeJob 以原任务的配置创建新任务，并从其检查点恢复训练
+func (s *jobService) ResumeJob(ctx context.Context, userID, jobID uuid.UUID, req *domain.ResumeJobRequest) (*domain.TrainingJob, error) {
+	source, err := s.getJob(ctx, jobID)
 	if err != nil {
-		s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
-			Level:     "ERROR",
-			Source:    "system",
-			Message:   fmt.Sprintf("Failed to get job for starting: %v", err),
-			Timestamp: time.Now(),
-		})
-		return
+		return nil, err
 	}
 
-	// 更新状态为 running
-	if err := s.jobRepo.UpdateStatus(ctx, jobID, domain.JobStatusRunning, "Starting training..."); err != nil {
-		s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
-			Level:     "ERROR",
-			Source:    "system",
-			Message:   fmt.Sprintf("Failed to update job status: %v", err),
-			Timestamp: time.Now(),
-		})
-		return
+	// 暂停的任务原地继续
+	if source.Status == domain.JobStatusPaused {
+		return s.unpause(ctx, source, req)
 	}
 
-	// 记录启动日志
-	s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
+	// 未指定检查点时优先使用最佳检查点，其次是最新的
+	var ckpt *models.Checkpoint
+	if req.CheckpointID != "" {
+		checkpointID, err := uuid.Parse(req.CheckpointID)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("invalid checkpoint_id: %s", req.CheckpointID))
+		}
+		ckpt, err = getJobCheckpoint(ctx, s.checkpointRepo, jobID, checkpointID)
+		if err != nil {
+			// checkpoint_id 来自请求体，不存在或属于其他任务时是参数错误
+			if errors.Is(err, apperrors.ErrNotFound) {
+				return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("checkpoint %s is not a checkpoint of job %s", checkpointID, jobID))
+			}
+			return nil, err
+		}
+	} else {
+		ckpt, err = s.checkpointRepo.GetBest(ctx, jobID)
+		if errors.Is(err, repository.ErrCheckpointNotFound) {
+			ckpt, err = s.checkpointRepo.GetLatest(ctx, jobID)
+		}
+		if err != nil {
+			if errors.Is(err, repository.ErrCheckpointNotFound) {
+				return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("job %s has no checkpoints", jobID))
+			}
+			return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+		}
+	}
+
+	createReq := &domain.CreateJobRequest{
+		Name:               req.Name,
+		Description:        source.Description,
+		ProjectID:          source.ProjectID.String(),
+		ModelName:          source.ModelName,
+		ModelVersion:       source.ModelVersion,
+		DatasetPath:        source.DatasetPath,
+		OutputPath:         req.OutputPath,
+		Framework:          source.Framework,
+		Image:              source.Image,
+		Command:            source.Command,
+		Hyperparameters:    source.Hyperparameters,
+		Environment:        make(map[string]string, len(source.Environment)),
+		SecretRefs:         source.SecretRefs,
+		GPUCount:           source.GPUCount,
+		GPUType:            source.GPUType,
+		CPUCount:           source.CPUCount,
+		MemoryGB:           source.MemoryGB,
+		TimeoutHours:       source.TimeoutHours,
+		Priority:           source.Priority,
+		RetryPolicy:        source.RetryPolicy,
+		Distributed:        source.Distributed,
+		ResumeCheckpointID: ckpt.ID.String(),
+	}
+	for key, value := range source.Environment {
+		createReq.Environment[key] = value
+	}
+	if source.DatasetID != nil {
+		// 重新解析数据集，确认其仍然可用且内容与原任务一致
+		createReq.DatasetID = source.DatasetID.String()
+		createReq.DatasetVersion = source.DatasetVersion
+		createReq.DatasetPath = ""
+	}
+	if source.ExperimentID != nil {
+		createReq.ExperimentID = source.ExperimentID.String()
+	}
+	if source.RunID != nil {
+		createReq.RunID = source.RunID.String()
+	}
+	if createReq.Name == "" {
+		createReq.Name = fmt.Sprintf("%s (resumed from step %d)", source.Name, ckpt.Step)
+	}
+	if createReq.OutputPath == "" {
+		createReq.OutputPath = fmt.Sprintf("%s-resume-%d", source.OutputPath, time.Now().Unix())
+	}
+	if req.Priority != nil {
+		createReq.Priority = *req.Priority
+	}
+
+	job, err := s.CreateJob(ctx, userID, createReq)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logRepo.AppendLog(ctx, job.ID, &domain.LogEntry{
 		Level:     "INFO",
 		Source:    "system",
-		Message:   fmt.Sprintf("Starting training with image: %s", job.Image),
+		Message:   fmt.Sprintf("Resuming job %s from checkpoint %s (step %d)", source.ID, ckpt.Name, ckpt.Step),
 		Timestamp: time.Now(),
 	})
 
-	// 启动执行器
-	execCtx, execCancel := context.WithTimeout(context.Background(), time.Duration(job.TimeoutHours)*time.Hour)
-	defer execCancel()
+	return job, nil
+}
 
-	if err := s.executor.Start(execCtx, job); err != nil {
-		s.jobRepo.UpdateStatus(ctx, jobID, domain.JobStatusFailed, fmt.Sprintf("Failed to start: %v", err))
-		s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
-			Level:     "ERROR",
-			Source:    "system",
-			Message:   fmt.Sprintf("Failed to start executor: %v", err),
-			Timestamp: time.Now(),
-		})
-		return
+// PauseJob 暂停运行中的任务：通知训练脚本保存检查点，容器退出后释放资源，之后可通过 ResumeJob 继续
+func (s *jobService) PauseJob(ctx context.Context, jobID uuid.UUID, req *domain.PauseJobRequest) (*domain.TrainingJob, error) {
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !job.CanPause() {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("job cannot be paused in status: %s", job.Status))
+	}
+	pausable, ok := s.executor.(executor.Pausable)
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusNotImplemented, "executor does not support pausing jobs")
+	}
+
+	signal, grace := s.cfg.PauseSignal, s.cfg.PauseGracePeriod
+	if req.Signal != "" {
+		signal = req.Signal
+	}
+	if req.GracePeriodSeconds > 0 {
+		grace = time.Duration(req.GracePeriodSeconds) * time.Second
+	}
+
+	ok, err = s.jobRepo.TransitionStatus(ctx, jobID, domain.JobStatusRunning, domain.JobStatusPausing, "Pausing: waiting for the training script to save a checkpoint")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, "job is no longer running")
 	}
 
-	// 监控任务完成
-	go s.monitorJobCompletion(jobID)
+	s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
+		Level:     "INFO",
+		Source:    "system",
+		Message:   fmt.Sprintf("Pause requested: sent %s, waiting up to %s for the job to exit", signal, grace),
+		Attempt:   job.Attempt,
+		Timestamp: time.Now(),
+	})
+
+	// 等待容器退出可能需要整个宽限期，在后台完成
+	go s.pause(job, pausable, signal, grace)
+
+	job.Status = domain.JobStatusPausing
+	job.StatusMessage = "Pausing: waiting for the training script to save a checkpoint"
+	return job, nil
 }
 
-// monitorJobCompletion 监控任务完成状态
-func (s *jobService) monitorJobCompletion(jobID uuid.UUID) {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		
-		// 检查执行器状态
-		isRunning := s.executor.IsRunning(ctx, jobID)
-		
-		if !isRunning {
-			// 任务已完成，更新状态
-			status, _ := s.executor.GetStatus(ctx, jobID)
-			
-			var finalStatus domain.JobStatus
-			var message string
-			
-			switch status {
-			case domain.JobStatusCompleted:
-				finalStatus = domain.JobStatusCompleted
-				message = "Training completed successfully"
-			case domain.JobStatusFailed:
-				finalStatus = domain.JobStatusFailed
-				message = "Training failed"
-			case domain.JobStatusCancelled:
-				finalStatus = domain.JobStatusCancelled
-				message = "Training cancelled"
-			default:
-				finalStatus = domain.JobStatusFailed
-				message = "Training ended with unknown status"
-			}
-			
-			s.jobRepo.UpdateStatus(ctx, jobID, finalStatus, message)
-			s.logRepo.AppendLog(ctx, jobID, &domain.LogEntry{
-				Level:     "INFO",
-				Source:    "system",
-				Message:   message,
-				Timestamp: time.Now(),
-			})
-			
-			cancel()
-			break
+// getJob 获取任务并转换仓库错误
+func (s *jobService) getJob(ctx context.Context, jobID uuid.UUID) (*domain.TrainingJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("job not found: %s", jobID))
 		}
-		
-		cancel()
+		return nil, err
 	}
+	return job, nil
 }
 
-// ListJobsByExperiment 列出实验下的任务
-func (s *jobService) ListJobsByExperiment(ctx context.Context, experimentID uuid.UUID, page, pageSize int) ([]*domain.TrainingJob, int64, error) {
-	return s.jobRepo.ListByExperiment(ctx, experimentID, page, pageSize)
-}
+// pause 停止任务的容器并将任务标记为已暂停
+func (s *jobService) pause(job *domain.TrainingJob, pausable executor.Pausable, signal string, grace time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), grace+2*time.Minute)
+	defer cancel()
 
-// GetExperimentMetrics 获取实验指标
-func (s *jobService) GetExperimentMetrics(ctx context.Context, experimentID uuid.UUID) (map[string]interface{}, error) {
-	return s.jobRepo.GetExperimentMetrics(ctx, experimentID)
+	if err := pausable.Pause(ctx, job.ID, signal, grace); err != nil {
+		logger.Warn("Failed to pause job cleanly", zap.String("job_id", job.ID.String()), zap.Error(err))
+	}
+
+	s.scheduler.FinishPause(ctx, job, fmt.Sprintf("Paused by user after attempt %d", job.Attempt))
 }
 
-// GetLogs 获取日志
-func (s *jobService) GetLogs(ctx context.Context, jobID uuid.UUID, start string, count int64) ([]domain.LogEntry, error) {
-	// 先验证任务存在
-	if _, err := s.jobRepo.GetByID(ctx, jobID); err != nil {
+// unpause 将暂停的任务放回队列，调度后从暂停时的检查点继续
+func (s *jobService) unpause(ctx context.Context, job *domain.TrainingJob, req *domain.ResumeJobRequest) (*domain.TrainingJob, error) {
+	if req.CheckpointID != "" || req.Name != "" || req.OutputPath != "" {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "a paused job resumes from its pause checkpoint; only priority can be changed")
+	}
+
+	priority := job.Priority
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+
+	// 继续后重新占用资源，按新提交检查配额，GPU 时长按剩余的超时时长计算
+	nodes := job.Distributed.Nodes()
+	remaining := max(time.Duration(job.TimeoutHours)*time.Hour-job.Runtime(time.Now()), 0)
+	message := "Resumed by user, waiting to be scheduled"
+	var ok bool
+	if err := s.quota.Reserve(ctx, job.ProjectID, quota.Request{
+		GPUs:     job.GPUCount * nodes,
+		GPUHours: float64(job.GPUCount*nodes) * remaining.Hours(),
+		Jobs:     1,
+		MemoryGB: job.MemoryGB * nodes,
+	}, func(tx *gorm.DB) error {
+		var err error
+		ok, err = s.jobRepo.WithTx(tx).Unpause(ctx, job.ID, job.Attempt, priority, message)
+		return err
+	}); err != nil {
 		return nil, err
 	}
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, "job is no longer paused")
+	}
 
-	return s.logRepo.ReadLogs(ctx, jobID, start, count)
+	job, err := s.jobRepo.GetByID(ctx, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	job.QueuePosition = s.scheduler.QueuePosition(ctx, job)
+
+	resumeMessage := "Resuming from scratch: no checkpoint was saved when pausing"
+	if job.PausedCheckpoint != "" {
+		resumeMessage = fmt.Sprintf("Resuming from pause checkpoint %s", job.PausedCheckpoint)
+	}
+	s.logRepo.AppendLog(ctx, job.ID, &domain.LogEntry{
+		Level:     "INFO",
+		Source:    "system",
+		Message:   fmt.Sprintf("%s, queued with priority %d at position %d", resumeMessage, job.Priority, job.QueuePosition),
+		Timestamp: time.Now(),
+	})
+	s.scheduler.Emit(domain.JobEventResumed, job, job.Status, resumeMessage)
+	s.scheduler.Notify()
+
+	return job, nil
 }
 
 // StreamLogs 流式获取日志
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/models"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+)
+
+// fakeJobRepo 内存中的任务表，只实现查询
+type fakeJobRepo struct {
+	repository.JobRepository
+	jobs map[uuid.UUID]*domain.TrainingJob
+}
+
+func (r *fakeJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.TrainingJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+// fakeCheckpointRepo 内存中的检查点表，只实现按 ID 查询
+type fakeCheckpointRepo struct {
+	repository.CheckpointRepository
+	checkpoints map[uuid.UUID]*models.Checkpoint
+}
+
+func (r *fakeCheckpointRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Checkpoint, error) {
+	ckpt, ok := r.checkpoints[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", repository.ErrCheckpointNotFound, id)
+	}
+	return ckpt, nil
+}
+
+// errorStatus 返回错误对应的 HTTP 状态码，非 AppError 返回 500
+func errorStatus(err error) int {
+	var appErr *apperrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+func TestJobServiceResumeAndPauseErrors(t *testing.T) {
+	job := &domain.TrainingJob{ID: uuid.New(), Status: domain.JobStatusFailed}
+	running := &domain.TrainingJob{ID: uuid.New(), Status: domain.JobStatusRunning}
+	paused := &domain.TrainingJob{ID: uuid.New(), Status: domain.JobStatusPaused}
+	other := uuid.New()
+	foreign := &models.Checkpoint{ID: uuid.New(), TrainingJobID: other}
+	s := &jobService{
+		jobRepo: &fakeJobRepo{jobs: map[uuid.UUID]*domain.TrainingJob{
+			job.ID:     job,
+			running.ID: running,
+			paused.ID:  paused,
+		}},
+		checkpointRepo: &fakeCheckpointRepo{checkpoints: map[uuid.UUID]*models.Checkpoint{foreign.ID: foreign}},
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		call       func() error
+		wantStatus int
+	}{
+		{
+			name: "resume unknown job",
+			call: func() error {
+				_, err := s.ResumeJob(ctx, uuid.New(), uuid.New(), &domain.ResumeJobRequest{})
+				return err
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "resume with malformed checkpoint_id",
+			call: func() error {
+				_, err := s.ResumeJob(ctx, uuid.New(), job.ID, &domain.ResumeJobRequest{CheckpointID: "latest"})
+				return err
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "resume with unknown checkpoint_id",
+			call: func() error {
+				_, err := s.ResumeJob(ctx, uuid.New(), job.ID, &domain.ResumeJobRequest{CheckpointID: uuid.NewString()})
+				return err
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "resume with checkpoint of another job",
+			call: func() error {
+				_, err := s.ResumeJob(ctx, uuid.New(), job.ID, &domain.ResumeJobRequest{CheckpointID: foreign.ID.String()})
+				return err
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "pause unknown job",
+			call: func() error {
+				_, err := s.PauseJob(ctx, uuid.New(), &domain.PauseJobRequest{})
+				return err
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "pause finished job",
+			call: func() error {
+				_, err := s.PauseJob(ctx, job.ID, &domain.PauseJobRequest{})
+				return err
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "pause without a pausable executor",
+			call: func() error {
+				_, err := s.PauseJob(ctx, running.ID, &domain.PauseJobRequest{})
+				return err
+			},
+			wantStatus: http.StatusNotImplemented,
+		},
+		{
+			name: "resume paused job from another checkpoint",
+			call: func() error {
+				_, err := s.ResumeJob(ctx, uuid.New(), paused.ID, &domain.ResumeJobRequest{CheckpointID: uuid.NewString()})
+				return err
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "resume paused job into a new output path",
+			call: func() error {
+				_, err := s.ResumeJob(ctx, uuid.New(), paused.ID, &domain.ResumeJobRequest{OutputPath: "/tmp/other"})
+				return err
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil {
+				t.Fatal("error = nil, want error")
+			}
+			if got := errorStatus(err); got != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %v", got, tt.wantStatus, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/executor"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+	"go.uber.org/zap"
+)
+
+// MetricService 训练指标服务接口
+type MetricService interface {
+	// Record 保存指标并转发到任务关联的实验运行记录
+	Record(ctx context.Context, jobID uuid.UUID, metrics *executor.TrainingMetrics) error
+	// OnMetrics 执行器从日志中解析到指标时的回调
+	OnMetrics(jobID uuid.UUID, metrics *executor.TrainingMetrics)
+	// Report 处理训练容器通过接口上报的指标
+	Report(ctx context.Context, jobID uuid.UUID, token string, reports []*domain.MetricReport) error
+	// List 按指标名返回降采样后的指标曲线
+	List(ctx context.Context, jobID uuid.UUID, query *domain.MetricsQuery) ([]*domain.MetricSeries, error)
+	// CheckJob 校验任务存在
+	CheckJob(ctx context.Context, jobID uuid.UUID) error
+	// SetActivityHandler 设置任务产生指标时的回调，用于停滞检测
+	SetActivityHandler(handler func(jobID uuid.UUID))
+}
+
+// defaultMaxMetricPoints 未指定 max_points 时每条指标曲线返回的最大点数
+const defaultMaxMetricPoints = 1000
+
+// metricService 训练指标服务实现
+type metricService struct {
+	jobRepo     repository.JobRepository
+	metricsRepo executor.MetricsRepository
+	forwarder   RunMetricsForwarder
+	tokenSecret string
+	onActivity  func(jobID uuid.UUID)
+
+	// runIDs 任务 ID 到运行记录 ID 的缓存，未关联运行记录时为 nil
+	runIDs sync.Map
+}
+
+// NewMetricService 创建指标服务实例，forwarder 为 nil 时不转发到实验服务
+func NewMetricService(jobRepo repository.JobRepository, metricsRepo executor.MetricsRepository, forwarder RunMetricsForwarder, tokenSecret string) MetricService {
+	return &metricService{
+		jobRepo:     jobRepo,
+		metricsRepo: metricsRepo,
+		forwarder:   forwarder,
+		tokenSecret: tokenSecret,
+	}
+}
+
+// SetActivityHandler 设置任务产生指标时的回调
+func (s *metricService) SetActivityHandler(handler func(jobID uuid.UUID)) {
+	s.onActivity = handler
+}
+
+// Record 保存指标并转发到任务关联的实验运行记录
+func (s *metricService) Record(ctx context.Context, jobID uuid.UUID, metrics *executor.TrainingMetrics) error {
+	if err := s.metricsRepo.SaveMetrics(ctx, jobID, metrics); err != nil {
+		return err
+	}
+	if s.onActivity != nil {
+		s.onActivity(jobID)
+	}
+
+	if s.forwarder == nil {
+		return nil
+	}
+	runID, err := s.runID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if runID == nil {
+		return nil
+	}
+	if err := s.forwarder.ForwardMetrics(ctx, *runID, metrics); err != nil {
+		// 转发失败不影响训练服务中的指标记录
+		logger.Warn("Failed to forward metrics to run",
+			zap.String("job_id", jobID.String()),
+			zap.String("run_id", runID.String()),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// OnMetrics 执行器从日志中解析到指标时的回调
+func (s *metricService) OnMetrics(jobID uuid.UUID, metrics *executor.TrainingMetrics) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := s.Record(ctx, jobID, metrics); err != nil {
+		logger.Error("Failed to record metrics", zap.String("job_id", jobID.String()), zap.Error(err))
+	}
+}
+
+// Report 校验任务令牌后记录上报的指标，仅接受未结束任务的上报
+func (s *metricService) Report(ctx context.Context, jobID uuid.UUID, token string, reports []*domain.MetricReport) error {
+	if token == "" || !executor.VerifyMetricsToken(s.tokenSecret, jobID, token) {
+		return apperrors.Wrap(apperrors.ErrUnauthorized, http.StatusUnauthorized, "invalid metrics token")
+	}
+
+	job, err := s.getJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.IsTerminal() {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("job is already %s", job.Status))
+	}
+
+	for i, report := range reports {
+		if report == nil {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("report %d is empty", i))
+		}
+		if err := report.Validate(); err != nil {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("report %d: %v", i, err))
+		}
+	}
+
+	for _, report := range reports {
+		if err := s.Record(ctx, jobID, executor.MetricsFromReport(report)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List 按指标名返回降采样后的指标曲线
+func (s *metricService) List(ctx context.Context, jobID uuid.UUID, query *domain.MetricsQuery) ([]*domain.MetricSeries, error) {
+	if err := s.CheckJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	series, err := s.metricsRepo.GetSeries(ctx, jobID, query.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	maxPoints := query.MaxPoints
+	if maxPoints == 0 {
+		maxPoints = defaultMaxMetricPoints
+	}
+	for _, sr := range series {
+		executor.DownsampleSeries(sr, query.Downsample, maxPoints)
+	}
+	return series, nil
+}
+
+// CheckJob 校验任务存在
+func (s *metricService) CheckJob(ctx context.Context, jobID uuid.UUID) error {
+	_, err := s.getJob(ctx, jobID)
+	return err
+}
+
+// runID 获取任务关联的运行记录 ID
+func (s *metricService) runID(ctx context.Context, jobID uuid.UUID) (*uuid.UUID, error) {
+	if cached, ok := s.runIDs.Load(jobID); ok {
+		return cached.(*uuid.UUID), nil
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	s.runIDs.Store(jobID, job.RunID)
+	return job.RunID, nil
+}
+
+// getJob 获取任务并转换仓库错误
+func (s *metricService) getJob(ctx context.Context, jobID uuid.UUID) (*domain.TrainingJob, error) {
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFound) {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("job not found: %s", jobID))
+		}
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/pipeline"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+	"go.uber.org/zap"
+)
+
+// PipelineService 流水线服务接口
+type PipelineService interface {
+	CreatePipeline(ctx context.Context, userID uuid.UUID, req *domain.CreatePipelineRequest) (*domain.Pipeline, error)
+	GetPipeline(ctx context.Context, id uuid.UUID) (*domain.Pipeline, error)
+	ListPipelines(ctx context.Context, req *domain.ListPipelinesRequest) ([]*domain.Pipeline, int64, error)
+	UpdatePipeline(ctx context.Context, id uuid.UUID, req *domain.UpdatePipelineRequest) (*domain.Pipeline, error)
+	DeletePipeline(ctx context.Context, id uuid.UUID) error
+
+	RunPipeline(ctx context.Context, userID, id uuid.UUID) (*domain.PipelineRun, error)
+	ListRuns(ctx context.Context, id uuid.UUID, req *domain.ListPipelineRunsRequest) ([]*domain.PipelineRun, int64, error)
+	GetRun(ctx context.Context, runID uuid.UUID) (*domain.PipelineRun, error)
+	CancelRun(ctx context.Context, runID uuid.UUID) (*domain.PipelineRun, error)
+}
+
+// pipelineService 流水线服务实现
+type pipelineService struct {
+	repo   repository.PipelineRepository
+	engine *pipeline.Engine
+}
+
+// NewPipelineService 创建流水线服务实例
+func NewPipelineService(repo repository.PipelineRepository, engine *pipeline.Engine) PipelineService {
+	return &pipelineService{
+		repo:   repo,
+		engine: engine,
+	}
+}
+
+// CreatePipeline 创建流水线
+func (s *pipelineService) CreatePipeline(ctx context.Context, userID uuid.UUID, req *domain.CreatePipelineRequest) (*domain.Pipeline, error) {
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+	}
+	if req.Name == "" || len(req.Name) > 255 {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "name is required and must be at most 255 characters")
+	}
+	if err := validateSpec(&req.Spec, projectID); err != nil {
+		return nil, err
+	}
+
+	p := &domain.Pipeline{
+		ID:          uuid.New(),
+		ProjectID:   projectID,
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Spec:        req.Spec,
+	}
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to create pipeline: %w", err)
+	}
+
+	logger.Info("Pipeline created",
+		zap.String("pipeline_id", p.ID.String()),
+		zap.String("name", p.Name),
+		zap.Int("steps", len(p.Spec.Steps)),
+	)
+	return p, nil
+}
+
+// GetPipeline 获取流水线
+func (s *pipelineService) GetPipeline(ctx context.Context, id uuid.UUID) (*domain.Pipeline, error) {
+	p, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapPipelineError(err)
+	}
+	return p, nil
+}
+
+// ListPipelines 列出项目的流水线
+func (s *pipelineService) ListPipelines(ctx context.Context, req *domain.ListPipelinesRequest) ([]*domain.Pipeline, int64, error) {
+	return s.repo.List(ctx, req)
+}
+
+// UpdatePipeline 更新流水线，已创建的运行使用创建时的定义，不受影响
+func (s *pipelineService) UpdatePipeline(ctx context.Context, id uuid.UUID, req *domain.UpdatePipelineRequest) (*domain.Pipeline, error) {
+	p, err := s.GetPipeline(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" || len(*req.Name) > 255 {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "name is required and must be at most 255 characters")
+		}
+		p.Name = *req.Name
+	}
+	if req.Description != nil {
+		p.Description = *req.Description
+	}
+	if req.Spec != nil {
+		if err := validateSpec(req.Spec, p.ProjectID); err != nil {
+			return nil, err
+		}
+		p.Spec = *req.Spec
+	}
+
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to update pipeline: %w", err)
+	}
+	return p, nil
+}
+
+// DeletePipeline 删除流水线及其运行记录，有进行中的运行时拒绝删除
+func (s *pipelineService) DeletePipeline(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetPipeline(ctx, id); err != nil {
+		return err
+	}
+
+	active, err := s.repo.CountActiveRuns(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to count active runs: %w", err)
+	}
+	if active > 0 {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, fmt.Sprintf("pipeline has %d active run(s); cancel them first", active))
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// RunPipeline 运行流水线
+func (s *pipelineService) RunPipeline(ctx context.Context, userID, id uuid.UUID) (*domain.PipelineRun, error) {
+	p, err := s.GetPipeline(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.engine.Run(ctx, p, userID)
+	if err != nil {
+		return nil, mapPipelineError(err)
+	}
+	return run, nil
+}
+
+// ListRuns 列出流水线的运行记录
+func (s *pipelineService) ListRuns(ctx context.Context, id uuid.UUID, req *domain.ListPipelineRunsRequest) ([]*domain.PipelineRun, int64, error) {
+	if _, err := s.GetPipeline(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListRuns(ctx, id, req)
+}
+
+// GetRun 获取运行记录
+func (s *pipelineService) GetRun(ctx context.Context, runID uuid.UUID) (*domain.PipelineRun, error) {
+	run, err := s.repo.GetRun(ctx, runID)
+	if err != nil {
+		return nil, mapPipelineError(err)
+	}
+	return run, nil
+}
+
+// CancelRun 取消运行
+func (s *pipelineService) CancelRun(ctx context.Context, runID uuid.UUID) (*domain.PipelineRun, error) {
+	run, err := s.engine.Cancel(ctx, runID)
+	if err != nil {
+		return nil, mapPipelineError(err)
+	}
+	return run, nil
+}
+
+// validateSpec 校验流水线定义，训练步骤的任务模板按创建任务的规则校验
+func validateSpec(spec *domain.PipelineSpec, projectID uuid.UUID) error {
+	if err := spec.Validate(); err != nil {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("invalid pipeline spec: %v", err))
+	}
+
+	for _, step := range spec.Steps {
+		if step.Type != domain.PipelineStepTraining {
+			continue
+		}
+
+		// 运行时由引擎填充的字段使用占位值
+		job := *step.Job
+		job.Name = step.Name
+		job.ProjectID = projectID.String()
+		if job.DatasetPath == "" {
+			job.DatasetPath = path.Join("/pipeline", step.Name, "input")
+		}
+		if job.OutputPath == "" {
+			job.OutputPath = path.Join("/pipeline", step.Name, "output")
+		}
+		if err := binding.Validator.ValidateStruct(&job); err != nil {
+			return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("invalid job template for step %q: %v", step.Name, err))
+		}
+	}
+	return nil
+}
+
+// mapPipelineError 将仓库的不存在错误转换为 404
+func mapPipelineError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrPipelineNotFound):
+		return apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrPipelineRunNotFound):
+		return apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, err.Error())
+	}
+	return err
+}
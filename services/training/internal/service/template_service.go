@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/plucky-groove3/ai-train-infer-platform/pkg/errors"
+	"github.com/plucky-groove3/ai-train-infer-platform/pkg/logger"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/template"
+	"go.uber.org/zap"
+)
+
+// TemplateService 任务模板服务接口
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, userID uuid.UUID, req *domain.CreateJobTemplateRequest) (*domain.JobTemplate, error)
+	GetTemplate(ctx context.Context, id uuid.UUID) (*domain.JobTemplate, error)
+	ListTemplates(ctx context.Context, req *domain.ListJobTemplatesRequest) ([]*domain.JobTemplate, int64, error)
+	ListBuiltinTemplates() []*domain.JobTemplate
+	ListVersions(ctx context.Context, id uuid.UUID) ([]*domain.JobTemplate, error)
+	CreateVersion(ctx context.Context, userID, id uuid.UUID, req *domain.CreateJobTemplateVersionRequest) (*domain.JobTemplate, error)
+	DeleteTemplate(ctx context.Context, id uuid.UUID) error
+	CreateJob(ctx context.Context, userID uuid.UUID, req *domain.CreateJobFromTemplateRequest) (*domain.TrainingJob, error)
+}
+
+// templateService 任务模板服务实现
+type templateService struct {
+	repo       repository.TemplateRepository
+	manager    *template.TemplateManager
+	jobService JobService
+
+	builtins       []*domain.JobTemplate
+	builtinsByID   map[uuid.UUID]*domain.JobTemplate
+	builtinsByName map[string]*domain.JobTemplate
+}
+
+// NewTemplateService 创建任务模板服务实例
+func NewTemplateService(repo repository.TemplateRepository, manager *template.TemplateManager, jobService JobService) TemplateService {
+	s := &templateService{
+		repo:           repo,
+		manager:        manager,
+		jobService:     jobService,
+		builtins:       manager.BuiltinTemplates(),
+		builtinsByID:   make(map[uuid.UUID]*domain.JobTemplate),
+		builtinsByName: make(map[string]*domain.JobTemplate),
+	}
+	for _, tmpl := range s.builtins {
+		s.builtinsByID[tmpl.ID] = tmpl
+		s.builtinsByName[tmpl.Name] = tmpl
+	}
+	return s
+}
+
+// CreateTemplate 创建模板的第一个版本
+func (s *templateService) CreateTemplate(ctx context.Context, userID uuid.UUID, req *domain.CreateJobTemplateRequest) (*domain.JobTemplate, error) {
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+	}
+
+	tmpl := &domain.JobTemplate{
+		ID:              uuid.New(),
+		ProjectID:       &projectID,
+		Name:            req.Name,
+		Description:     req.Description,
+		Framework:       req.Framework,
+		Generator:       req.Generator,
+		Image:           req.Image,
+		Command:         req.Command,
+		ModelName:       req.ModelName,
+		GPUCount:        req.GPUCount,
+		GPUType:         req.GPUType,
+		CPUCount:        req.CPUCount,
+		MemoryGB:        req.MemoryGB,
+		TimeoutHours:    req.TimeoutHours,
+		Hyperparameters: req.Hyperparameters,
+		Environment:     req.Environment,
+		Parameters:      req.Parameters,
+		CreatedBy:       userID,
+	}
+	if err := tmpl.Validate(); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("invalid template: %v", err))
+	}
+
+	if err := s.repo.Create(ctx, tmpl); err != nil {
+		return nil, mapTemplateError(err)
+	}
+
+	logger.Info("Job template created",
+		zap.String("template_id", tmpl.ID.String()),
+		zap.String("name", tmpl.Name),
+	)
+	return tmpl, nil
+}
+
+// GetTemplate 获取模板版本，包括内置模板
+func (s *templateService) GetTemplate(ctx context.Context, id uuid.UUID) (*domain.JobTemplate, error) {
+	if tmpl, ok := s.builtinsByID[id]; ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapTemplateError(err)
+	}
+	return tmpl, nil
+}
+
+// ListTemplates 列出项目的模板（最新版本）
+func (s *templateService) ListTemplates(ctx context.Context, req *domain.ListJobTemplatesRequest) ([]*domain.JobTemplate, int64, error) {
+	return s.repo.List(ctx, req)
+}
+
+// ListBuiltinTemplates 列出内置模板
+func (s *templateService) ListBuiltinTemplates() []*domain.JobTemplate {
+	return s.builtins
+}
+
+// ListVersions 列出模板的所有版本
+func (s *templateService) ListVersions(ctx context.Context, id uuid.UUID) ([]*domain.JobTemplate, error) {
+	tmpl, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl.Builtin {
+		return []*domain.JobTemplate{tmpl}, nil
+	}
+
+	return s.repo.ListVersions(ctx, *tmpl.ProjectID, tmpl.Name)
+}
+
+// CreateVersion 以指定版本为基础创建新版本，新版本号为当前最大版本号加一
+func (s *templateService) CreateVersion(ctx context.Context, userID, id uuid.UUID, req *domain.CreateJobTemplateVersionRequest) (*domain.JobTemplate, error) {
+	base, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if base.Builtin {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "built-in templates are read-only; create a project template instead")
+	}
+
+	tmpl := *base
+	tmpl.ID = uuid.New()
+	tmpl.CreatedBy = userID
+	tmpl.CreatedAt = time.Time{}
+	if req.Description != nil {
+		tmpl.Description = *req.Description
+	}
+	if req.Framework != nil {
+		tmpl.Framework = *req.Framework
+	}
+	if req.Generator != nil {
+		tmpl.Generator = *req.Generator
+	}
+	if req.Image != nil {
+		tmpl.Image = *req.Image
+	}
+	if req.Command != nil {
+		tmpl.Command = req.Command
+	}
+	if req.ModelName != nil {
+		tmpl.ModelName = *req.ModelName
+	}
+	if req.GPUCount != nil {
+		tmpl.GPUCount = *req.GPUCount
+	}
+	if req.GPUType != nil {
+		tmpl.GPUType = *req.GPUType
+	}
+	if req.CPUCount != nil {
+		tmpl.CPUCount = *req.CPUCount
+	}
+	if req.MemoryGB != nil {
+		tmpl.MemoryGB = *req.MemoryGB
+	}
+	if req.TimeoutHours != nil {
+		tmpl.TimeoutHours = *req.TimeoutHours
+	}
+	if req.Hyperparameters != nil {
+		tmpl.Hyperparameters = req.Hyperparameters
+	}
+	if req.Environment != nil {
+		tmpl.Environment = req.Environment
+	}
+	if req.Parameters != nil {
+		tmpl.Parameters = req.Parameters
+	}
+	if err := tmpl.Validate(); err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, fmt.Sprintf("invalid template: %v", err))
+	}
+
+	if err := s.repo.CreateVersion(ctx, &tmpl); err != nil {
+		return nil, mapTemplateError(err)
+	}
+
+	logger.Info("Job template version created",
+		zap.String("template_id", tmpl.ID.String()),
+		zap.String("name", tmpl.Name),
+		zap.Int("version", tmpl.Version),
+	)
+	return &tmpl, nil
+}
+
+// DeleteTemplate 删除模板的所有版本，已创建的任务不受影响
+func (s *templateService) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	tmpl, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tmpl.Builtin {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "built-in templates cannot be deleted")
+	}
+
+	return s.repo.DeleteByName(ctx, *tmpl.ProjectID, tmpl.Name)
+}
+
+// CreateJob 实例化模板并创建训练任务
+func (s *templateService) CreateJob(ctx context.Context, userID uuid.UUID, req *domain.CreateJobFromTemplateRequest) (*domain.TrainingJob, error) {
+	projectID, err := uuid.Parse(req.ProjectID)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid project_id")
+	}
+
+	tmpl, err := s.resolveTemplate(ctx, projectID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	hyperparameters, err := tmpl.ResolveParameters(req.Parameters)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+	}
+
+	jobReq := &domain.CreateJobRequest{
+		Name:               req.Name,
+		Description:        req.Description,
+		ProjectID:          req.ProjectID,
+		ExperimentID:       req.ExperimentID,
+		RunID:              req.RunID,
+		ModelName:          firstNonEmpty(req.ModelName, tmpl.ModelName),
+		ModelVersion:       req.ModelVersion,
+		DatasetPath:        req.DatasetPath,
+		DatasetID:          req.DatasetID,
+		DatasetVersion:     req.DatasetVersion,
+		OutputPath:         req.OutputPath,
+		Framework:          tmpl.Framework,
+		Image:              firstNonEmpty(req.Image, tmpl.Image),
+		Command:            tmpl.Command,
+		Hyperparameters:    hyperparameters,
+		Environment:        make(map[string]string, len(tmpl.Environment)+len(req.Environment)+1),
+		SecretRefs:         req.SecretRefs,
+		GPUCount:           intOrDefault(req.GPUCount, tmpl.GPUCount),
+		GPUType:            firstNonEmpty(req.GPUType, tmpl.GPUType),
+		CPUCount:           intOrDefault(req.CPUCount, tmpl.CPUCount),
+		MemoryGB:           intOrDefault(req.MemoryGB, tmpl.MemoryGB),
+		TimeoutHours:       intOrDefault(req.TimeoutHours, tmpl.TimeoutHours),
+		Priority:           req.Priority,
+		RetryPolicy:        req.RetryPolicy,
+		ResumeCheckpointID: req.ResumeCheckpointID,
+		Distributed:        req.Distributed,
+	}
+	if len(req.Command) > 0 {
+		jobReq.Command = req.Command
+	}
+	for key, value := range tmpl.Environment {
+		jobReq.Environment[key] = value
+	}
+	for key, value := range req.Environment {
+		jobReq.Environment[key] = value
+	}
+	if jobReq.ModelName == "" {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "model_name is required: the template does not define one")
+	}
+
+	// 没有命令时执行由模板生成的训练脚本
+	if len(jobReq.Command) == 0 && tmpl.Generator != "" {
+		if err := s.applyGeneratedScript(jobReq, tmpl); err != nil {
+			return nil, err
+		}
+	}
+
+	job, err := s.jobService.CreateJob(ctx, userID, jobReq)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Job created from template",
+		zap.String("job_id", job.ID.String()),
+		zap.String("template", tmpl.Name),
+		zap.Int("version", tmpl.Version),
+	)
+	return job, nil
+}
+
+// resolveTemplate 按 template_id 或模板名查找模板，模板名先在项目中查找，再查找内置模板
+func (s *templateService) resolveTemplate(ctx context.Context, projectID uuid.UUID, req *domain.CreateJobFromTemplateRequest) (*domain.JobTemplate, error) {
+	if (req.TemplateID == "") == (req.Template == "") {
+		return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "exactly one of template_id or template is required")
+	}
+
+	if req.TemplateID != "" {
+		if req.Version != 0 {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "version can only be used with template")
+		}
+		id, err := uuid.Parse(req.TemplateID)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, "invalid template_id")
+		}
+		tmpl, err := s.GetTemplate(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !tmpl.Builtin && *tmpl.ProjectID != projectID {
+			return nil, apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, fmt.Sprintf("job template not found in project: %s", id))
+		}
+		return tmpl, nil
+	}
+
+	tmpl, err := s.repo.GetByName(ctx, projectID, req.Template, req.Version)
+	if err == nil {
+		return tmpl, nil
+	}
+	if !errors.Is(err, repository.ErrTemplateNotFound) {
+		return nil, err
+	}
+	if builtin, ok := s.builtinsByName[req.Template]; ok && req.Version <= builtin.Version {
+		return builtin, nil
+	}
+	return nil, mapTemplateError(err)
+}
+
+// applyGeneratedScript 生成训练脚本，通过环境变量传入容器并设置执行命令
+func (s *templateService) applyGeneratedScript(req *domain.CreateJobRequest, tmpl *domain.JobTemplate) error {
+	generator, err := s.manager.GetTemplate(template.TemplateType(tmpl.Generator))
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusBadRequest, err.Error())
+	}
+	if req.Image == "" {
+		req.Image = generator.GetDefaultImage("")
+	}
+
+	script, err := generator.Generate(&template.TrainingConfig{
+		Framework:       req.Framework,
+		ModelName:       req.ModelName,
+		DatasetPath:     "/data",
+		OutputPath:      "/output",
+		Hyperparameters: req.Hyperparameters,
+		Environment:     req.Environment,
+		Distributed:     req.Distributed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate training script: %w", err)
+	}
+
+	distributed := false
+	if req.Distributed != nil {
+		config := *req.Distributed
+		config.ApplyDefaults(req.GPUCount)
+		distributed = config.IsDistributed()
+	}
+	req.Environment[template.ScriptEnv] = script
+	req.Command = template.ScriptCommand(distributed)
+	return nil
+}
+
+// mapTemplateError 将仓库错误转换为对应的 HTTP 状态码
+func mapTemplateError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrTemplateNotFound):
+		return apperrors.Wrap(apperrors.ErrNotFound, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrTemplateExists):
+		return apperrors.Wrap(apperrors.ErrInvalidParams, http.StatusConflict, err.Error())
+	}
+	return err
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// intOrDefault 返回覆盖值，未设置时返回默认值
+func intOrDefault(value *int, defaultValue int) int {
+	if value != nil {
+		return *value
+	}
+	return defaultValue
+}
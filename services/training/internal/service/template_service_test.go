@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/repository"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/template"
+)
+
+// fakeTemplateRepo 内存中的模板表，按 ID 或项目内的名称查询
+type fakeTemplateRepo struct {
+	repository.TemplateRepository
+	templates []*domain.JobTemplate
+}
+
+func (r *fakeTemplateRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.JobTemplate, error) {
+	for _, tmpl := range r.templates {
+		if tmpl.ID == id {
+			return tmpl, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", repository.ErrTemplateNotFound, id)
+}
+
+func (r *fakeTemplateRepo) GetByName(ctx context.Context, projectID uuid.UUID, name string, version int) (*domain.JobTemplate, error) {
+	var latest *domain.JobTemplate
+	for _, tmpl := range r.templates {
+		if *tmpl.ProjectID != projectID || tmpl.Name != name {
+			continue
+		}
+		if tmpl.Version == version {
+			return tmpl, nil
+		}
+		if version == 0 && (latest == nil || tmpl.Version > latest.Version) {
+			latest = tmpl
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w: %s", repository.ErrTemplateNotFound, name)
+	}
+	return latest, nil
+}
+
+// fakeJobCreator 记录实例化模板后提交的任务请求
+type fakeJobCreator struct {
+	JobService
+	req *domain.CreateJobRequest
+}
+
+func (s *fakeJobCreator) CreateJob(ctx context.Context, userID uuid.UUID, req *domain.CreateJobRequest) (*domain.TrainingJob, error) {
+	s.req = req
+	return &domain.TrainingJob{ID: uuid.New(), Name: req.Name}, nil
+}
+
+func TestTemplateServiceCreateJob(t *testing.T) {
+	projectID := uuid.New()
+	otherProject := uuid.New()
+	v1 := &domain.JobTemplate{
+		ID: uuid.New(), ProjectID: &projectID, Name: "resnet", Version: 1,
+		Framework: domain.FrameworkPyTorch, Image: "resnet:1", Command: []string{"python", "train.py"},
+		ModelName: "resnet50", GPUCount: 1, Environment: map[string]string{"A": "template", "B": "template"},
+	}
+	v2 := &domain.JobTemplate{
+		ID: uuid.New(), ProjectID: &projectID, Name: "resnet", Version: 2,
+		Framework: domain.FrameworkPyTorch, Image: "resnet:2", Command: []string{"python", "train.py"},
+		ModelName: "resnet50", GPUCount: 2,
+		Parameters: map[string]*domain.TemplateParameter{"epochs": {Type: domain.ParameterTypeInt, Default: 10}},
+	}
+	noModel := &domain.JobTemplate{
+		ID: uuid.New(), ProjectID: &projectID, Name: "bare", Version: 1,
+		Framework: domain.FrameworkOther, Image: "bare:1",
+	}
+	foreign := &domain.JobTemplate{
+		ID: uuid.New(), ProjectID: &otherProject, Name: "resnet", Version: 1,
+		Framework: domain.FrameworkPyTorch, Image: "other:1", ModelName: "resnet50",
+	}
+	gpus := 4
+
+	tests := []struct {
+		name       string
+		req        domain.CreateJobFromTemplateRequest
+		wantStatus int // 0 表示成功
+		check      func(t *testing.T, req *domain.CreateJobRequest)
+	}{
+		{
+			name: "latest version by name",
+			req:  domain.CreateJobFromTemplateRequest{Template: "resnet"},
+			check: func(t *testing.T, req *domain.CreateJobRequest) {
+				if req.Image != "resnet:2" || req.GPUCount != 2 || req.Hyperparameters["epochs"] != 10 {
+					t.Errorf("image = %s, gpus = %d, hyperparameters = %v", req.Image, req.GPUCount, req.Hyperparameters)
+				}
+			},
+		},
+		{
+			name: "pinned version with overrides",
+			req: domain.CreateJobFromTemplateRequest{
+				Template: "resnet", Version: 1, GPUCount: &gpus, Image: "resnet:dev",
+				Environment: map[string]string{"B": "request"},
+			},
+			check: func(t *testing.T, req *domain.CreateJobRequest) {
+				if req.Image != "resnet:dev" || req.GPUCount != 4 {
+					t.Errorf("image = %s, gpus = %d", req.Image, req.GPUCount)
+				}
+				if req.Environment["A"] != "template" || req.Environment["B"] != "request" {
+					t.Errorf("environment = %v", req.Environment)
+				}
+			},
+		},
+		{
+			name: "by id",
+			req:  domain.CreateJobFromTemplateRequest{TemplateID: v1.ID.String()},
+			check: func(t *testing.T, req *domain.CreateJobRequest) {
+				if req.Image != "resnet:1" {
+					t.Errorf("image = %s, want resnet:1", req.Image)
+				}
+			},
+		},
+		{
+			name: "builtin generates a training script",
+			req:  domain.CreateJobFromTemplateRequest{Template: "huggingface-finetune", Parameters: map[string]interface{}{"task": "causal-lm"}},
+			check: func(t *testing.T, req *domain.CreateJobRequest) {
+				if req.ModelName != "distilbert-base-uncased" || req.Environment[template.ScriptEnv] == "" {
+					t.Errorf("model = %s, script set = %v", req.ModelName, req.Environment[template.ScriptEnv] != "")
+				}
+				if len(req.Command) != 3 || !strings.Contains(req.Command[2], "exec python "+template.ScriptPath) {
+					t.Errorf("command = %v", req.Command)
+				}
+			},
+		},
+		{
+			name: "explicit command skips script generation",
+			req:  domain.CreateJobFromTemplateRequest{Template: "pytorch", ModelName: "mlp", Command: []string{"python", "my.py"}},
+			check: func(t *testing.T, req *domain.CreateJobRequest) {
+				_, generated := req.Environment[template.ScriptEnv]
+				if generated || req.Command[1] != "my.py" {
+					t.Errorf("command = %v, script set = %v", req.Command, generated)
+				}
+			},
+		},
+		{name: "neither id nor name", req: domain.CreateJobFromTemplateRequest{}, wantStatus: http.StatusBadRequest},
+		{name: "both id and name", req: domain.CreateJobFromTemplateRequest{TemplateID: v1.ID.String(), Template: "resnet"}, wantStatus: http.StatusBadRequest},
+		{name: "version with id", req: domain.CreateJobFromTemplateRequest{TemplateID: v1.ID.String(), Version: 1}, wantStatus: http.StatusBadRequest},
+		{name: "id of another project", req: domain.CreateJobFromTemplateRequest{TemplateID: foreign.ID.String()}, wantStatus: http.StatusNotFound},
+		{name: "unknown name", req: domain.CreateJobFromTemplateRequest{Template: "missing"}, wantStatus: http.StatusNotFound},
+		{name: "unknown builtin version", req: domain.CreateJobFromTemplateRequest{Template: "pytorch", Version: 2}, wantStatus: http.StatusNotFound},
+		{name: "unknown parameter", req: domain.CreateJobFromTemplateRequest{Template: "resnet", Parameters: map[string]interface{}{"lr": 0.1}}, wantStatus: http.StatusBadRequest},
+		{name: "no model name", req: domain.CreateJobFromTemplateRequest{Template: "bare"}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := &fakeJobCreator{}
+			s := NewTemplateService(&fakeTemplateRepo{templates: []*domain.JobTemplate{v1, v2, noModel, foreign}}, template.NewTemplateManager(), jobs)
+
+			req := tt.req
+			req.Name = "job"
+			req.ProjectID = projectID.String()
+			req.DatasetPath = "/data/train"
+			req.OutputPath = "/output/job"
+			_, err := s.CreateJob(context.Background(), uuid.New(), &req)
+
+			if tt.wantStatus != 0 {
+				if err == nil {
+					t.Fatal("error = nil, want error")
+				}
+				if got := errorStatus(err); got != tt.wantStatus {
+					t.Errorf("status = %d, want %d: %v", got, tt.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateJob() error = %v", err)
+			}
+			tt.check(t, jobs.req)
+		})
+	}
+}
+
+func TestTemplateServiceBuiltinsAreReadOnly(t *testing.T) {
+	s := NewTemplateService(&fakeTemplateRepo{}, template.NewTemplateManager(), &fakeJobCreator{})
+	builtin := s.ListBuiltinTemplates()[0]
+	image := "custom:1"
+
+	if _, err := s.CreateVersion(context.Background(), uuid.New(), builtin.ID, &domain.CreateJobTemplateVersionRequest{Image: &image}); errorStatus(err) != http.StatusBadRequest {
+		t.Errorf("CreateVersion() error = %v, want 400", err)
+	}
+	if err := s.DeleteTemplate(context.Background(), builtin.ID); errorStatus(err) != http.StatusBadRequest {
+		t.Errorf("DeleteTemplate() error = %v, want 400", err)
+	}
+}
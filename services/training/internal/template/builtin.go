@@ -0,0 +1,116 @@
+package template
+
+import (
+	"github.com/google/uuid"
+	"github.com/plucky-groove3/ai-train-infer-platform/services/training/internal/domain"
+)
+
+const (
+	// ScriptEnv 传递生成的训练脚本内容的环境变量
+	ScriptEnv = "AITIP_TRAINING_SCRIPT"
+	// ScriptPath 训练脚本在容器内的路径
+	ScriptPath = "/tmp/aitip_train.py"
+)
+
+// builtinNamespace 内置模板 ID 的命名空间，ID 由模板名派生，重启后保持不变
+var builtinNamespace = uuid.MustParse("6f1c3e0a-8d7b-4a52-9e61-2b4f0c9d7a13")
+
+// ScriptCommand 将 ScriptEnv 中的脚本写入容器后执行的命令，分布式任务通过 torchrun 启动
+func ScriptCommand(distributed bool) []string {
+	write := `printf '%s' "$` + ScriptEnv + `" > ` + ScriptPath + ` && `
+	if distributed {
+		return []string{"sh", "-c", write + `exec torchrun --nnodes="$NNODES" --nproc_per_node="$NPROC_PER_NODE" --node_rank="$NODE_RANK" ` +
+			`--master_addr="$MASTER_ADDR" --master_port="$MASTER_PORT" ` + ScriptPath}
+	}
+	return []string{"sh", "-c", write + "exec python " + ScriptPath}
+}
+
+// BuiltinTemplates 由各框架的脚本模板生成的内置任务模板
+func (m *TemplateManager) BuiltinTemplates() []*domain.JobTemplate {
+	builtins := []*domain.JobTemplate{
+		{
+			Name:        "pytorch",
+			Description: "PyTorch starter script with a placeholder training loop, DDP support and checkpointing helpers",
+			Framework:   domain.FrameworkPyTorch,
+			Generator:   string(TemplatePyTorch),
+			Image:       m.templates[TemplatePyTorch].GetDefaultImage(""),
+			GPUCount:    1,
+			Parameters: map[string]*domain.TemplateParameter{
+				"epochs":        intParameter("Number of epochs", 10, 1),
+				"batch_size":    intParameter("Batch size per process", 32, 1),
+				"learning_rate": floatParameter("Learning rate", 0.001),
+			},
+		},
+		{
+			Name:        "tensorflow",
+			Description: "TensorFlow/Keras starter script with a placeholder model",
+			Framework:   domain.FrameworkTensorFlow,
+			Generator:   string(TemplateTensorFlow),
+			Image:       m.templates[TemplateTensorFlow].GetDefaultImage(""),
+			GPUCount:    1,
+			Parameters: map[string]*domain.TemplateParameter{
+				"epochs":        intParameter("Number of epochs", 10, 1),
+				"batch_size":    intParameter("Batch size", 32, 1),
+				"learning_rate": floatParameter("Learning rate", 0.001),
+			},
+		},
+		{
+			Name:        "huggingface-finetune",
+			Description: "Fine-tune a HuggingFace Transformers model for sequence classification or causal language modeling",
+			Framework:   domain.FrameworkPyTorch,
+			Generator:   string(TemplateHuggingFace),
+			Image:       m.templates[TemplateHuggingFace].GetDefaultImage(""),
+			ModelName:   "distilbert-base-uncased",
+			GPUCount:    1,
+			MemoryGB:    32,
+			Parameters: map[string]*domain.TemplateParameter{
+				"task": {
+					Type:        domain.ParameterTypeString,
+					Description: "Fine-tuning task",
+					Default:     "sequence-classification",
+					Enum:        []interface{}{"sequence-classification", "causal-lm"},
+				},
+				"epochs":        intParameter("Number of epochs", 3, 1),
+				"batch_size":    intParameter("Batch size per device", 8, 1),
+				"learning_rate": floatParameter("Learning rate", 5e-5),
+				"max_length":    intParameter("Maximum tokens per example", 512, 8),
+				"warmup_ratio":  floatParameter("Fraction of steps used for learning rate warmup", 0),
+				"weight_decay":  floatParameter("Weight decay", 0),
+				"text_column":   {Type: domain.ParameterTypeString, Description: "Input text column", Default: "text"},
+				"label_column":  {Type: domain.ParameterTypeString, Description: "Label column (sequence-classification)", Default: "label"},
+				"num_labels":    {Type: domain.ParameterTypeInt, Description: "Number of classes, inferred from the labels when unset", Min: float64Ptr(1)},
+			},
+		},
+	}
+
+	for _, tmpl := range builtins {
+		tmpl.ID = uuid.NewSHA1(builtinNamespace, []byte(tmpl.Name))
+		tmpl.Version = 1
+		tmpl.Builtin = true
+	}
+	return builtins
+}
+
+// intParameter 带默认值和下限的整数参数
+func intParameter(description string, value int, min float64) *domain.TemplateParameter {
+	return &domain.TemplateParameter{
+		Type:        domain.ParameterTypeInt,
+		Description: description,
+		Default:     value,
+		Min:         float64Ptr(min),
+	}
+}
+
+// floatParameter 带默认值的非负浮点参数
+func floatParameter(description string, value float64) *domain.TemplateParameter {
+	return &domain.TemplateParameter{
+		Type:        domain.ParameterTypeFloat,
+		Description: description,
+		Default:     value,
+		Min:         float64Ptr(0),
+	}
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,71 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	first := NewTemplateManager().BuiltinTemplates()
+	second := NewTemplateManager().BuiltinTemplates()
+
+	names := make(map[string]bool)
+	for i, tmpl := range first {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			if names[tmpl.Name] {
+				t.Fatalf("duplicate builtin template %q", tmpl.Name)
+			}
+			names[tmpl.Name] = true
+
+			if err := tmpl.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			if !tmpl.Builtin || tmpl.Version != 1 || tmpl.Image == "" {
+				t.Errorf("builtin = %v, version = %d, image = %q", tmpl.Builtin, tmpl.Version, tmpl.Image)
+			}
+			if tmpl.ID != second[i].ID {
+				t.Errorf("ID changed between managers: %s, %s", tmpl.ID, second[i].ID)
+			}
+
+			// 参数默认值能直接生成训练脚本
+			hyperparameters, err := tmpl.ResolveParameters(nil)
+			if err != nil {
+				t.Fatalf("ResolveParameters() error = %v", err)
+			}
+			generator, err := NewTemplateManager().GetTemplate(TemplateType(tmpl.Generator))
+			if err != nil {
+				t.Fatalf("GetTemplate() error = %v", err)
+			}
+			script, err := generator.Generate(&TrainingConfig{
+				Framework:       tmpl.Framework,
+				ModelName:       "model",
+				DatasetPath:     "/data",
+				OutputPath:      "/output",
+				Hyperparameters: hyperparameters,
+			})
+			if err != nil || script == "" {
+				t.Errorf("Generate() = %d bytes, error = %v", len(script), err)
+			}
+		})
+	}
+}
+
+func TestScriptCommand(t *testing.T) {
+	tests := []struct {
+		distributed bool
+		want        string
+	}{
+		{false, "exec python " + ScriptPath},
+		{true, "exec torchrun "},
+	}
+
+	for _, tt := range tests {
+		command := ScriptCommand(tt.distributed)
+		if len(command) != 3 || command[0] != "sh" {
+			t.Fatalf("ScriptCommand(%v) = %v", tt.distributed, command)
+		}
+		if !strings.Contains(command[2], tt.want) || !strings.Contains(command[2], "$"+ScriptEnv) {
+			t.Errorf("ScriptCommand(%v) = %q, want it to contain %q", tt.distributed, command[2], tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package template
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -28,14 +29,15 @@ type TrainingTemplate interface {
 
 // TrainingConfig 训练配置
 type TrainingConfig struct {
-	Framework       domain.FrameworkType   `json:"framework"`
-	ModelName       string                 `json:"model_name"`
-	DatasetPath     string                 `json:"dataset_path"`
-	OutputPath      string                 `json:"output_path"`
-	Hyperparameters map[string]interface{} `json:"hyperparameters"`
-	Environment     map[string]string      `json:"environment"`
-	Script          string                 `json:"script,omitempty"` // 自定义脚本
-	CustomArgs      []string               `json:"custom_args,omitempty"`
+	Framework       domain.FrameworkType      `json:"framework"`
+	ModelName       string                    `json:"model_name"`
+	DatasetPath     string                    `json:"dataset_path"`
+	OutputPath      string                    `json:"output_path"`
+	Hyperparameters map[string]interface{}    `json:"hyperparameters"`
+	Environment     map[string]string         `json:"environment"`
+	Script          string                    `json:"script,omitempty"` // 自定义脚本
+	CustomArgs      []string                  `json:"custom_args,omitempty"`
+	Distributed     *domain.DistributedConfig `json:"distributed,omitempty"` // 多节点分布式训练配置
 }
 
 // TemplateManager 模板管理器
@@ -120,6 +122,12 @@ func (t *PyTorchTemplate) Generate(config *TrainingConfig) (string, error) {
 Auto-generated PyTorch Training Script
 Framework: PyTorch
 Model: {{.ModelName}}
+{{- if .Distributed.IsDistributed}}
+Distributed: {{.Distributed.Nodes}} node(s) x {{.Distributed.ProcsPerNode}} process(es), launch with torchrun
+{{- end}}
+
+The script runs standalone or under torchrun: when WORLD_SIZE > 1 it joins the
+process group using MASTER_ADDR/MASTER_PORT/RANK/LOCAL_RANK from the environment.
 """
 
 import os
@@ -132,17 +140,26 @@ from datetime import datetime
 import torch
 import torch.nn as nn
 import torch.optim as optim
+import torch.distributed as dist
+from torch.nn.parallel import DistributedDataParallel as DDP
 from torch.utils.data import DataLoader, Dataset
+from torch.utils.data.distributed import DistributedSampler
 from torch.utils.tensorboard import SummaryWriter
 
-# 配置日志
+# 分布式环境（由平台或 torchrun 设置，单机运行时为默认值）
+RANK = int(os.environ.get('RANK', 0))
+LOCAL_RANK = int(os.environ.get('LOCAL_RANK', 0))
+WORLD_SIZE = int(os.environ.get('WORLD_SIZE', 1))
+IS_MAIN = RANK == 0
+
+# 配置日志，只有 rank 0 写日志文件
+handlers = [logging.StreamHandler(sys.stdout)]
+if IS_MAIN:
+    handlers.append(logging.FileHandler('/output/training.log'))
 logging.basicConfig(
     level=logging.INFO,
-    format='%(asctime)s - %(levelname)s - %(message)s',
-    handlers=[
-        logging.StreamHandler(sys.stdout),
-        logging.FileHandler('/output/training.log')
-    ]
+    format=f'%(asctime)s - [rank {RANK}] %(levelname)s - %(message)s',
+    handlers=handlers
 )
 logger = logging.getLogger(__name__)
 
@@ -157,14 +174,44 @@ class Config:
     {{$key | upper}} = {{$value | formatValue}}
     {{end}}
     
-    # 默认值
-    EPOCHS = int(os.environ.get('EPOCHS', 10))
-    BATCH_SIZE = int(os.environ.get('BATCH_SIZE', 32))
-    LEARNING_RATE = float(os.environ.get('LEARNING_RATE', 0.001))
-    DEVICE = torch.device('cuda' if torch.cuda.is_available() else 'cpu')
+    # 默认值，任务超参数（HP_<NAME> 环境变量）优先
+    EPOCHS = int(os.environ.get('HP_EPOCHS', os.environ.get('EPOCHS', 10)))
+    BATCH_SIZE = int(os.environ.get('HP_BATCH_SIZE', os.environ.get('BATCH_SIZE', 32)))
+    LEARNING_RATE = float(os.environ.get('HP_LEARNING_RATE', os.environ.get('LEARNING_RATE', 0.001)))
+    DEVICE = torch.device(f'cuda:{LOCAL_RANK}' if torch.cuda.is_available() else 'cpu')
+
+def setup_distributed():
+    """WORLD_SIZE > 1 时初始化进程组"""
+    if WORLD_SIZE <= 1:
+        return
+    backend = os.environ.get('DIST_BACKEND', 'nccl' if torch.cuda.is_available() else 'gloo')
+    if torch.cuda.is_available():
+        torch.cuda.set_device(LOCAL_RANK)
+    dist.init_process_group(backend=backend)
+    logger.info(f"Joined process group: rank {RANK}/{WORLD_SIZE}, local rank {LOCAL_RANK}, backend {backend}")
+
+def cleanup_distributed():
+    """销毁进程组"""
+    if dist.is_available() and dist.is_initialized():
+        dist.destroy_process_group()
+
+def wrap_model(model):
+    """分布式训练时用 DDP 包装模型"""
+    model = model.to(Config.DEVICE)
+    if WORLD_SIZE > 1:
+        device_ids = [LOCAL_RANK] if torch.cuda.is_available() else None
+        model = DDP(model, device_ids=device_ids)
+    return model
+
+def make_loader(dataset, shuffle=True):
+    """分布式训练时每个进程只读取自己的数据分片"""
+    sampler = DistributedSampler(dataset, shuffle=shuffle) if WORLD_SIZE > 1 else None
+    return DataLoader(dataset, batch_size=Config.BATCH_SIZE, shuffle=shuffle and sampler is None, sampler=sampler)
 
 def log_metrics(epoch, step, loss, accuracy=None, val_loss=None, val_accuracy=None):
-    """记录训练指标"""
+    """记录训练指标（只在 rank 0 输出）"""
+    if not IS_MAIN:
+        return
     metrics = {
         'epoch': epoch,
         'step': step,
@@ -181,7 +228,11 @@ def log_metrics(epoch, step, loss, accuracy=None, val_loss=None, val_accuracy=No
     logger.info(f"METRICS: {json.dumps(metrics)}")
 
 def save_checkpoint(model, optimizer, epoch, path):
-    """保存检查点"""
+    """保存检查点（只在 rank 0 保存）"""
+    if not IS_MAIN:
+        return
+    if isinstance(model, DDP):
+        model = model.module
     checkpoint = {
         'epoch': epoch,
         'model_state_dict': model.state_dict(),
@@ -192,19 +243,20 @@ def save_checkpoint(model, optimizer, epoch, path):
 
 def train():
     """主训练函数"""
+    setup_distributed()
     logger.info(f"Starting training with PyTorch {torch.__version__}")
     logger.info(f"Device: {Config.DEVICE}")
     logger.info(f"CUDA available: {torch.cuda.is_available()}")
     
     if torch.cuda.is_available():
-        logger.info(f"CUDA device: {torch.cuda.get_device_name(0)}")
-        logger.info(f"CUDA memory: {torch.cuda.get_device_properties(0).total_memory / 1e9:.2f} GB")
+        logger.info(f"CUDA device: {torch.cuda.get_device_name(LOCAL_RANK)}")
+        logger.info(f"CUDA memory: {torch.cuda.get_device_properties(LOCAL_RANK).total_memory / 1e9:.2f} GB")
     
     # 创建输出目录
     os.makedirs(Config.OUTPUT_PATH, exist_ok=True)
     
-    # TensorBoard 写入器
-    writer = SummaryWriter(os.path.join(Config.OUTPUT_PATH, 'runs'))
+    # TensorBoard 写入器（只在 rank 0 写入）
+    writer = SummaryWriter(os.path.join(Config.OUTPUT_PATH, 'runs')) if IS_MAIN else None
     
     try:
         # 训练循环占位符
@@ -214,6 +266,8 @@ def train():
         logger.info(f"Dataset: {Config.DATASET_PATH}")
         
         # 模拟训练循环（实际使用时替换）
+        # 分布式训练时用 wrap_model 包装模型、用 make_loader 创建数据加载器，
+        # 并在每个 epoch 开始时调用 loader.sampler.set_epoch(epoch)
         for epoch in range(Config.EPOCHS):
             logger.info(f"Epoch {epoch + 1}/{Config.EPOCHS}")
             
@@ -224,7 +278,8 @@ def train():
                 
                 if step % 10 == 0:
                     log_metrics(epoch + 1, step, loss)
-                    writer.add_scalar('Loss/train', loss, epoch * 100 + step)
+                    if writer:
+                        writer.add_scalar('Loss/train', loss, epoch * 100 + step)
             
             # 保存检查点
             checkpoint_path = os.path.join(Config.OUTPUT_PATH, f'checkpoint_epoch_{epoch + 1}.pth')
@@ -236,7 +291,9 @@ def train():
         logger.error(f"Training failed: {str(e)}", exc_info=True)
         sys.exit(1)
     finally:
-        writer.close()
+        if writer:
+            writer.close()
+        cleanup_distributed()
 
 if __name__ == '__main__':
     train()
@@ -316,10 +373,10 @@ class Config:
     {{$key | upper}} = {{$value | formatValue}}
     {{end}}
     
-    # 默认值
-    EPOCHS = int(os.environ.get('EPOCHS', 10))
-    BATCH_SIZE = int(os.environ.get('BATCH_SIZE', 32))
-    LEARNING_RATE = float(os.environ.get('LEARNING_RATE', 0.001))
+    # 默认值，任务超参数（HP_<NAME> 环境变量）优先
+    EPOCHS = int(os.environ.get('HP_EPOCHS', os.environ.get('EPOCHS', 10)))
+    BATCH_SIZE = int(os.environ.get('HP_BATCH_SIZE', os.environ.get('BATCH_SIZE', 32)))
+    LEARNING_RATE = float(os.environ.get('HP_LEARNING_RATE', os.environ.get('LEARNING_RATE', 0.001)))
     
     # GPU 配置
     GPUS = tf.config.experimental.list_physical_devices('GPU')
@@ -458,103 +515,261 @@ func NewHuggingFaceTemplate() *HuggingFaceTemplate {
 	return &HuggingFaceTemplate{}
 }
 
-// Generate 生成训练脚本
+// Generate 生成微调脚本
+//
+// 脚本从 DATASET_PATH 加载数据集，按 task 超参数做序列分类或因果语言模型微调，
+// 通过 AITIP_METRIC 和 AITIP_CHECKPOINT 日志行上报指标和检查点，最终模型保存到 OUTPUT_PATH。
 func (t *HuggingFaceTemplate) Generate(config *TrainingConfig) (string, error) {
 	tmpl := `#!/usr/bin/env python3
 """
-Auto-generated HuggingFace Training Script
+Auto-generated HuggingFace Fine-tuning Script
 Framework: Transformers
 Model: {{.ModelName}}
+
+Fine-tunes the model on the dataset under DATASET_PATH and saves it to OUTPUT_PATH.
+The dataset is a directory written by datasets' save_to_disk, or csv/json/jsonl/parquet/txt
+files: files named validation*/valid*/eval*/dev*/test* form the evaluation split, all others
+the training split. Without an evaluation split, 10% of the training data is held out.
+
+Hyperparameters (HP_<NAME> environment variables):
+  task          sequence-classification (default) or causal-lm
+  epochs, batch_size, learning_rate, max_length, warmup_ratio, weight_decay
+  text_column   input text column (default: text)
+  label_column  label column for sequence-classification (default: label)
+  num_labels    number of classes, inferred from the labels when unset
 """
 
 import os
 import sys
+import glob
 import json
 import logging
-from datetime import datetime
 
+import numpy as np
+import torch
+from datasets import DatasetDict, load_dataset, load_from_disk
 from transformers import (
-    AutoModel, AutoTokenizer, AutoConfig,
-    TrainingArguments, Trainer,
-    DataCollatorWithPadding
+    AutoModelForCausalLM, AutoModelForSequenceClassification, AutoTokenizer,
+    DataCollatorForLanguageModeling, DataCollatorWithPadding,
+    Trainer, TrainerCallback, TrainingArguments,
 )
-from datasets import load_dataset
-import torch
 
 # 配置日志
 logging.basicConfig(
     level=logging.INFO,
     format='%(asctime)s - %(levelname)s - %(message)s',
-    handlers=[
-        logging.StreamHandler(sys.stdout),
-        logging.FileHandler('/output/training.log')
-    ]
+    handlers=[logging.StreamHandler(sys.stdout)]
 )
 logger = logging.getLogger(__name__)
 
+def hp(name, default, cast=str):
+    """读取任务超参数"""
+    value = os.environ.get('HP_' + name.upper())
+    if value is None or value == '':
+        return default
+    return cast(value)
+
 class Config:
-    MODEL_NAME = "{{.ModelName}}"
+    MODEL_NAME = os.environ.get('MODEL_NAME') or "{{.ModelName}}"
     DATASET_PATH = os.environ.get('DATASET_PATH', '{{.DatasetPath}}')
     OUTPUT_PATH = os.environ.get('OUTPUT_PATH', '{{.OutputPath}}')
-    
-    # 超参数
-    {{range $key, $value := .Hyperparameters}}
-    {{$key | upper}} = {{$value | formatValue}}
-    {{end}}
-    
-    # 默认值
-    EPOCHS = int(os.environ.get('EPOCHS', 3))
-    BATCH_SIZE = int(os.environ.get('BATCH_SIZE', 8))
-    LEARNING_RATE = float(os.environ.get('LEARNING_RATE', 5e-5))
-    MAX_LENGTH = int(os.environ.get('MAX_LENGTH', 512))
-
-def compute_metrics(eval_pred):
-    """计算评估指标"""
-    predictions, labels = eval_pred
-    # 添加具体的指标计算
-    return {"accuracy": 0.0}
-
-class MetricsCallback:
-    """自定义指标回调"""
-    
-    def on_epoch_end(self, args, state, control, **kwargs):
-        metrics = state.log_history[-1] if state.log_history else {}
-        metrics['epoch'] = state.epoch
-        metrics['timestamp'] = datetime.now().isoformat()
-        logger.info(f"METRICS: {json.dumps(metrics, default=str)}")
+
+    TASK = hp('task', 'sequence-classification')
+    EPOCHS = hp('epochs', 3, float)
+    BATCH_SIZE = hp('batch_size', 8, int)
+    LEARNING_RATE = hp('learning_rate', 5e-5, float)
+    MAX_LENGTH = hp('max_length', 512, int)
+    WARMUP_RATIO = hp('warmup_ratio', 0.0, float)
+    WEIGHT_DECAY = hp('weight_decay', 0.0, float)
+    TEXT_COLUMN = hp('text_column', 'text')
+    LABEL_COLUMN = hp('label_column', 'label')
+    NUM_LABELS = hp('num_labels', 0, int)
+
+FILE_FORMATS = {'.csv': 'csv', '.json': 'json', '.jsonl': 'json', '.parquet': 'parquet', '.txt': 'text'}
+EVAL_PREFIXES = ('validation', 'valid', 'eval', 'dev', 'test')
+
+def load_data():
+    """加载数据集，返回包含 train 和 validation 的 DatasetDict"""
+    path = Config.DATASET_PATH
+    if os.path.exists(os.path.join(path, 'dataset_dict.json')) or os.path.exists(os.path.join(path, 'dataset_info.json')):
+        data = load_from_disk(path)
+        if not isinstance(data, DatasetDict):
+            data = DatasetDict({'train': data})
+    else:
+        files = [path] if os.path.isfile(path) else sorted(glob.glob(os.path.join(path, '**', '*'), recursive=True))
+        builder, splits = None, {}
+        for file in files:
+            fmt = FILE_FORMATS.get(os.path.splitext(file)[1].lower())
+            if fmt is None:
+                continue
+            if builder is not None and fmt != builder:
+                logger.warning(f"Skipping {file}: mixed dataset formats ({builder} and {fmt})")
+                continue
+            builder = fmt
+            split = 'validation' if os.path.basename(file).lower().startswith(EVAL_PREFIXES) else 'train'
+            splits.setdefault(split, []).append(file)
+        if 'train' not in splits:
+            raise ValueError(f"No training data found under {path}")
+        data = load_dataset(builder, data_files=splits)
+
+    if 'validation' not in data:
+        for name in ('eval', 'test', 'dev'):
+            if name in data:
+                data['validation'] = data.pop(name)
+                break
+    if 'validation' not in data:
+        held_out = data['train'].train_test_split(test_size=0.1, seed=42)
+        data = DatasetDict({'train': held_out['train'], 'validation': held_out['test']})
+    return data
+
+def compute_accuracy(eval_pred):
+    """分类准确率"""
+    logits, labels = eval_pred
+    predictions = np.argmax(logits, axis=-1)
+    return {'accuracy': float((predictions == labels).mean())}
+
+def build(tokenizer, data):
+    """按任务类型创建模型并处理数据集"""
+    def tokenize(batch):
+        return tokenizer(batch[Config.TEXT_COLUMN], truncation=True, max_length=Config.MAX_LENGTH)
+
+    if tokenizer.pad_token is None:
+        tokenizer.pad_token = tokenizer.eos_token
+
+    if Config.TASK == 'causal-lm':
+        model = AutoModelForCausalLM.from_pretrained(Config.MODEL_NAME)
+        data = data.map(tokenize, batched=True, remove_columns=data['train'].column_names)
+        return model, data, DataCollatorForLanguageModeling(tokenizer, mlm=False), None
+    if Config.TASK != 'sequence-classification':
+        raise ValueError(f"Unknown task: {Config.TASK}")
+
+    column = Config.LABEL_COLUMN
+    labels = sorted(set().union(*(data[split][column] for split in data)))
+    label2id = None
+    if labels and isinstance(labels[0], str):
+        label2id = {label: i for i, label in enumerate(labels)}
+        data = data.map(lambda batch: {'labels': [label2id[label] for label in batch[column]]}, batched=True)
+        num_labels = len(labels)
+    else:
+        data = data.map(lambda batch: {'labels': batch[column]}, batched=True)
+        num_labels = int(max(labels)) + 1 if labels else 2
+    if Config.NUM_LABELS > 0:
+        num_labels = Config.NUM_LABELS
+
+    kwargs = {'num_labels': num_labels}
+    if label2id:
+        kwargs['label2id'] = label2id
+        kwargs['id2label'] = {i: label for label, i in label2id.items()}
+    model = AutoModelForSequenceClassification.from_pretrained(Config.MODEL_NAME, **kwargs)
+    model.config.pad_token_id = tokenizer.pad_token_id
+
+    columns = [name for name in data['train'].column_names if name != 'labels']
+    data = data.map(tokenize, batched=True, remove_columns=columns)
+    return model, data, DataCollatorWithPadding(tokenizer), compute_accuracy
+
+def platform_metrics(logs):
+    """转换为平台指标名：Trainer 的 eval_ 前缀改为 val_"""
+    metrics = {}
+    for key, value in logs.items():
+        if key in ('epoch', 'step') or isinstance(value, bool) or not isinstance(value, (int, float)):
+            continue
+        if key.startswith('eval_'):
+            key = 'val_' + key[len('eval_'):]
+        metrics[key] = value
+    return metrics
+
+class PlatformCallback(TrainerCallback):
+    """通过 AITIP_METRIC 和 AITIP_CHECKPOINT 日志行上报指标和检查点（只在主进程输出）"""
+
+    def __init__(self):
+        self.last_eval = {}
+
+    def on_log(self, args, state, control, logs=None, **kwargs):
+        if not state.is_world_process_zero or not logs:
+            return
+        metrics = platform_metrics(logs)
+        if not metrics:
+            return
+        if any(key.startswith('val_') for key in metrics):
+            self.last_eval = metrics
+        report = {'step': state.global_step, 'epoch': int(state.epoch or 0), 'metrics': metrics}
+        print('AITIP_METRIC ' + json.dumps(report), flush=True)
+
+    def on_save(self, args, state, control, **kwargs):
+        if not state.is_world_process_zero:
+            return
+        announcement = {
+            'path': os.path.join(args.output_dir, f'checkpoint-{state.global_step}'),
+            'step': state.global_step,
+            'epoch': int(state.epoch or 0),
+            'metrics': self.last_eval,
+        }
+        print('AITIP_CHECKPOINT ' + json.dumps(announcement), flush=True)
 
 def train():
     """主训练函数"""
     logger.info(f"PyTorch version: {torch.__version__}")
     logger.info(f"CUDA available: {torch.cuda.is_available()}")
-    
+
     os.makedirs(Config.OUTPUT_PATH, exist_ok=True)
-    
+
     try:
-        logger.info(f"Loading model: {Config.MODEL_NAME}")
-        
-        # 加载模型和 tokenizer
+        logger.info(f"Loading dataset from {Config.DATASET_PATH}")
+        data = load_data()
+        logger.info(f"Loading model: {Config.MODEL_NAME} ({Config.TASK})")
         tokenizer = AutoTokenizer.from_pretrained(Config.MODEL_NAME)
-        model = AutoModel.from_pretrained(Config.MODEL_NAME)
-        
-        # 训练参数
-        training_args = TrainingArguments(
+        model, data, collator, compute_metrics = build(tokenizer, data)
+        logger.info(f"Train examples: {len(data['train'])}, validation examples: {len(data['validation'])}")
+
+        # 训练参数，每个 epoch 评估并保存检查点
+        args = dict(
             output_dir=Config.OUTPUT_PATH,
             num_train_epochs=Config.EPOCHS,
             per_device_train_batch_size=Config.BATCH_SIZE,
             per_device_eval_batch_size=Config.BATCH_SIZE,
             learning_rate=Config.LEARNING_RATE,
+            warmup_ratio=Config.WARMUP_RATIO,
+            weight_decay=Config.WEIGHT_DECAY,
             logging_dir=os.path.join(Config.OUTPUT_PATH, 'logs'),
             logging_steps=10,
-            save_steps=500,
-            evaluation_strategy="epoch",
-            save_strategy="epoch",
+            save_strategy='epoch',
+            save_total_limit=3,
             load_best_model_at_end=True,
+            metric_for_best_model='loss',
+            greater_is_better=False,
+            fp16=torch.cuda.is_available(),
+            report_to=[],
         )
-        
-        logger.info("Training configuration loaded successfully")
+        try:
+            training_args = TrainingArguments(eval_strategy='epoch', **args)
+        except TypeError:
+            # transformers < 4.41
+            training_args = TrainingArguments(evaluation_strategy='epoch', **args)
+
+        trainer = Trainer(
+            model=model,
+            args=training_args,
+            train_dataset=data['train'],
+            eval_dataset=data['validation'],
+            data_collator=collator,
+            compute_metrics=compute_metrics,
+            callbacks=[PlatformCallback()],
+        )
+
+        # 从平台传入的检查点继续训练
+        resume = os.environ.get('RESUME_FROM_CHECKPOINT')
+        if resume and not os.path.isdir(resume):
+            logger.warning(f"Ignoring RESUME_FROM_CHECKPOINT {resume}: not a Trainer checkpoint directory")
+            resume = None
+        trainer.train(resume_from_checkpoint=resume)
+
+        trainer.save_model(Config.OUTPUT_PATH)
+        if trainer.is_world_process_zero():
+            tokenizer.save_pretrained(Config.OUTPUT_PATH)
+        metrics = trainer.evaluate()
+        logger.info(f"Final evaluation: {json.dumps(metrics, default=str)}")
         logger.info("Training completed!")
-        
+
     except Exception as e:
         logger.error(f"Training failed: {str(e)}", exc_info=True)
         sys.exit(1)
@@ -563,12 +778,7 @@ if __name__ == '__main__':
     train()
 `
 
-	funcMap := template.FuncMap{
-		"upper":       strings.ToUpper,
-		"formatValue": formatPythonValue,
-	}
-
-	return t.execute(tmpl, config, funcMap)
+	return t.execute(tmpl, config, nil)
 }
 
 // GetDefaultImage 获取默认镜像
@@ -636,8 +846,10 @@ func formatPythonValue(v interface{}) string {
 		return fmt.Sprintf("%t", val)
 	case int, int32, int64:
 		return fmt.Sprintf("%d", val)
-	case float32, float64:
-		return fmt.Sprintf("%f", val)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
 	case []interface{}:
 		var parts []string
 		for _, item := range val {
@@ -681,3 +893,15 @@ func BuildTrainingCommand(framework domain.FrameworkType, scriptPath string, hyp
 
 	return InjectHyperparameters(command, hyperparams)
 }
+
+// BuildDistributedCommand 构建 torchrun 启动命令
+//
+// 节点数、每节点进程数、节点序号和 rendezvous 地址在容器启动时由执行器通过环境变量注入，
+// 因此通过 sh 展开；脚本参数作为位置参数传递，避免被 shell 再次解析。
+func BuildDistributedCommand(scriptPath string, hyperparams map[string]interface{}) []string {
+	launcher := `exec torchrun --nnodes="$NNODES" --nproc_per_node="$NPROC_PER_NODE" --node_rank="$NODE_RANK" ` +
+		`--master_addr="$MASTER_ADDR" --master_port="$MASTER_PORT" "$@"`
+
+	command := []string{"sh", "-c", launcher, "torchrun", scriptPath}
+	return InjectHyperparameters(command, hyperparams)
+}
@@ -95,6 +95,12 @@ func main() {
 			auth.POST("/refresh", authHandler.RefreshToken)
 		}
 
+		// 内部接口，网关不对外转发 /internal 路径
+		internal := v1.Group("/internal")
+		{
+			internal.POST("/api-keys/validate", apiKeyHandler.Validate)
+		}
+
 		authenticated := v1.Group("")
 		authenticated.Use(middleware.JWTAuth(jwtManager, userService))
 		{
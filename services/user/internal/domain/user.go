@@ -124,6 +124,18 @@ type CreateAPIKeyRequest struct {
 	Name string `json:"name" binding:"required,max=255"`
 }
 
+// ValidateAPIKeyRequest 校验 API Key 请求
+type ValidateAPIKeyRequest struct {
+	Key string `json:"key" binding:"required"`
+}
+
+// APIKeyIdentity API Key 校验结果
+type APIKeyIdentity struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+}
+
 // AuthResponse 认证响应
 type AuthResponse struct {
 	User         *UserProfile `json:"user"`
@@ -254,3 +254,25 @@ func (h *APIKeyHandler) Delete(c *gin.Context) {
 
 	response.Success(c, gin.H{"message": "api key deleted successfully"})
 }
+
+// Validate 校验 API Key 并返回其所属用户，供网关内部调用
+func (h *APIKeyHandler) Validate(c *gin.Context) {
+	var req domain.ValidateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	apiKey, err := h.userService.ValidateAPIKey(c.Request.Context(), req.Key)
+	if err != nil {
+		status := service.MapServiceError(err)
+		response.Error(c, status, err.Error())
+		return
+	}
+
+	response.Success(c, domain.APIKeyIdentity{
+		ID:     apiKey.ID,
+		UserID: apiKey.UserID,
+		Name:   apiKey.Name,
+	})
+}
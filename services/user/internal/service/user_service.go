@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ai-train-infer-platform/services/user/internal/domain"
@@ -25,11 +27,14 @@ var (
 	ErrTokenBlacklisted   = errors.New("token has been revoked")
 	ErrAPIKeyNotFound     = errors.New("api key not found")
 	ErrMaxAPIKeysReached  = errors.New("maximum number of API keys reached")
+	ErrInvalidAPIKey      = errors.New("invalid api key")
 )
 
 const (
 	MaxAPIKeysPerUser = 10
 	APIKeyLength      = 32
+	// APIKeyPrefix API Key 前缀，完整格式为 aitip_<key id hex>_<secret>，只对 secret 部分做哈希
+	APIKeyPrefix = "aitip"
 )
 
 type UserService interface {
@@ -222,17 +227,20 @@ func (s *userService) CreateAPIKey(ctx context.Context, userID uuid.UUID, req *d
 		return nil, ErrMaxAPIKeysReached
 	}
 
-	plainKey, err := generateAPIKey()
+	secret, err := generateAPIKey()
 	if err != nil {
 		logger.Log.Error("Failed to generate API key", zap.Error(err))
 		return nil, err
 	}
 
+	// key 中带有记录 ID，校验时按 ID 查找，无需遍历比较哈希
 	apiKey := &domain.APIKey{
+		ID:     uuid.New(),
 		UserID: userID,
 		Name:   req.Name,
 	}
-	if err := apiKey.SetKey(plainKey); err != nil {
+	plainKey := formatAPIKey(apiKey.ID, secret)
+	if err := apiKey.SetKey(secret); err != nil {
 		logger.Log.Error("Failed to hash API key", zap.Error(err))
 		return nil, err
 	}
@@ -286,7 +294,31 @@ func (s *userService) DeleteAPIKey(ctx context.Context, userID uuid.UUID, keyID
 }
 
 func (s *userService) ValidateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
-	return nil, errors.New("not implemented")
+	keyID, secret, ok := parseAPIKey(key)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if !apiKey.CheckKey(secret) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// 用户已被删除时其 API Key 同样失效
+	if _, err := s.userRepo.GetByID(ctx, apiKey.UserID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	return apiKey, nil
 }
 
 func (s *userService) generateAuthResponse(user *domain.User) (*domain.AuthResponse, error) {
@@ -320,6 +352,28 @@ func generateAPIKey() (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
+// formatAPIKey 拼接返回给用户的完整 API Key
+func formatAPIKey(id uuid.UUID, secret string) string {
+	return APIKeyPrefix + "_" + hex.EncodeToString(id[:]) + "_" + secret
+}
+
+// parseAPIKey 从完整 API Key 中解析记录 ID 和 secret，secret 本身可能包含下划线
+func parseAPIKey(key string) (uuid.UUID, string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != APIKeyPrefix || parts[2] == "" {
+		return uuid.Nil, "", false
+	}
+	raw, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return uuid.Nil, "", false
+	}
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		return uuid.Nil, "", false
+	}
+	return id, parts[2], true
+}
+
 func MapServiceError(err error) int {
 	switch {
 	case errors.Is(err, ErrInvalidCredentials):
@@ -328,7 +382,7 @@ func MapServiceError(err error) int {
 		return http.StatusConflict
 	case errors.Is(err, ErrUserNotFound):
 		return http.StatusNotFound
-	case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrTokenExpired), errors.Is(err, ErrTokenBlacklisted):
+	case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrTokenExpired), errors.Is(err, ErrTokenBlacklisted), errors.Is(err, ErrInvalidAPIKey):
 		return http.StatusUnauthorized
 	case errors.Is(err, ErrAPIKeyNotFound):
 		return http.StatusNotFound